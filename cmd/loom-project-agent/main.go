@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -21,6 +22,15 @@ func main() {
 		port             = flag.String("port", getEnvOrDefault("PORT", "8090"), "HTTP port for agent API")
 		workDir          = flag.String("work-dir", getEnvOrDefault("WORK_DIR", "/workspace"), "Project workspace directory")
 		heartbeatInterval = flag.Duration("heartbeat", 30*time.Second, "Heartbeat interval")
+		maxConcurrent    = flag.Int("max-concurrent", 1, "Tasks to run at once")
+		queueSize        = flag.Int("queue-size", projectagent.DefaultQueueSize, "Accepted tasks that may wait to run")
+		isolation        = flag.String("isolation", getEnvOrDefault("ISOLATION_MODE", projectagent.IsolationNone), "How bash tasks are isolated: none, chroot or container")
+		isolationImage   = flag.String("isolation-image", getEnvOrDefault("ISOLATION_IMAGE", projectagent.DefaultIsolationImage), "Image bash tasks run in with -isolation container")
+		maxMemoryMB      = flag.Int("max-memory-mb", 0, "Memory a bash task may use in MB; 0 means unlimited")
+		maxCPUSeconds    = flag.Int("max-cpu-seconds", 0, "CPU time a bash task may use in seconds; 0 means unlimited")
+		readCacheMB      = flag.Int("read-cache-mb", projectagent.DefaultReadCacheMB, "File content read tasks may keep cached, in MB")
+		allowCommands    = flag.String("allow-commands", os.Getenv("ALLOW_COMMANDS"), "Comma-separated programs bash tasks may run; empty allows any")
+		denyCommands     = flag.String("deny-commands", os.Getenv("DENY_COMMANDS"), "Comma-separated programs bash tasks may never run")
 	)
 
 	flag.Parse()
@@ -38,6 +48,9 @@ func main() {
 	log.Printf("  Control Plane: %s", *controlPlaneURL)
 	log.Printf("  Work Directory: %s", *workDir)
 	log.Printf("  Listen Port: %s", *port)
+	log.Printf("  Max Concurrent Tasks: %d", *maxConcurrent)
+	log.Printf("  Isolation: %s", *isolation)
+	log.Printf("  Resource Limits: memory %dMB, CPU %ds (0 is unlimited)", *maxMemoryMB, *maxCPUSeconds)
 
 	// Create project agent
 	agent, err := projectagent.New(projectagent.Config{
@@ -45,6 +58,15 @@ func main() {
 		ControlPlaneURL:   *controlPlaneURL,
 		WorkDir:           *workDir,
 		HeartbeatInterval: *heartbeatInterval,
+		MaxConcurrent:     *maxConcurrent,
+		QueueSize:         *queueSize,
+		IsolationMode:     *isolation,
+		IsolationImage:    *isolationImage,
+		MaxMemoryMB:       *maxMemoryMB,
+		MaxCPUSeconds:     *maxCPUSeconds,
+		ReadCacheMB:       *readCacheMB,
+		AllowedCommands:   splitList(*allowCommands),
+		DeniedCommands:    splitList(*denyCommands),
 	})
 	if err != nil {
 		log.Fatalf("Failed to create project agent: %v", err)
@@ -95,6 +117,17 @@ func main() {
 	log.Println("Project agent stopped")
 }
 
+// splitList splits a comma-separated flag value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 func getEnvOrDefault(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -49,14 +49,7 @@ func main() {
 	}
 
 	// Override with environment variables if set
-	if temporalHost := os.Getenv("TEMPORAL_HOST"); temporalHost != "" {
-		cfg.Temporal.Host = temporalHost
-		log.Printf("Using Temporal host from environment: %s", temporalHost)
-	}
-	if temporalNamespace := os.Getenv("TEMPORAL_NAMESPACE"); temporalNamespace != "" {
-		cfg.Temporal.Namespace = temporalNamespace
-		log.Printf("Using Temporal namespace from environment: %s", temporalNamespace)
-	}
+	config.ApplyEnvOverrides(cfg)
 
 	arb, err := loom.New(cfg)
 	if err != nil {
@@ -138,9 +131,26 @@ func main() {
 	// Initialize auth manager (JWT + API key support)
 	log.Printf("[DEBUG] Initializing auth manager...")
 	authManager := auth.NewManager(cfg.Security.JWTSecret)
+	if len(cfg.Security.Tokens) > 0 {
+		tokens := make([]auth.StaticToken, 0, len(cfg.Security.Tokens))
+		for _, t := range cfg.Security.Tokens {
+			tokens = append(tokens, auth.StaticToken{Subject: t.Subject, Token: t.Token, Scopes: t.Scopes})
+		}
+		authManager.SetStaticTokens(tokens)
+		log.Printf("[Auth] Loaded %d static bearer token(s)", len(tokens))
+	}
 
 	log.Printf("[DEBUG] Creating API server...")
 	apiServer := api.NewServer(arb, km, authManager, cfg)
+
+	// Reload logging, readiness and rate limit settings when the file changes
+	config.OnReload(arb.ApplyConfig)
+	config.OnReload(apiServer.ApplyConfig)
+	if cfgWatcher, err := config.WatchFile(*configPath, cfg); err != nil {
+		log.Printf("Config hot-reload disabled: %v", err)
+	} else {
+		defer cfgWatcher.Close()
+	}
 	log.Printf("[DEBUG] Setting up routes...")
 	handler := apiServer.SetupRoutes()
 	log.Printf("[DEBUG] Routes configured")
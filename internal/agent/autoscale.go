@@ -0,0 +1,252 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/jordanhubbard/loom/internal/worker"
+	"github.com/jordanhubbard/loom/pkg/models"
+)
+
+// autoScaleCooldownIntervals is how many scale intervals must pass after a
+// scaling action before the next one, so the pool does not thrash.
+const autoScaleCooldownIntervals = 3
+
+// ReadyBeadCounter reports how many beads are waiting to be dispatched.
+type ReadyBeadCounter interface {
+	ReadyBeadCount() int
+}
+
+// autoScaler holds the worker pool auto-scaling settings and state. Fields
+// are guarded by WorkerManager.mu.
+type autoScaler struct {
+	min, max  int
+	interval  time.Duration
+	counter   ReadyBeadCounter
+	stop      chan struct{}
+	lastScale time.Time
+	seq       int
+	scaled    map[string]bool // Agents added by the scaler
+	draining  map[string]bool // Scaled agents waiting to finish their task before removal
+}
+
+// SetReadyBeadCounter sets the source of queue depth for auto-scaling.
+func (m *WorkerManager) SetReadyBeadCounter(c ReadyBeadCounter) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.scaler.counter = c
+}
+
+// SetAutoScale grows the worker pool up to max workers while ready beads are
+// waiting and every worker is busy, and shrinks it back toward min when
+// workers sit idle with nothing queued. The pool is checked every
+// scaleInterval, and after each change it waits three intervals before
+// scaling again. Only workers added by the scaler are removed, each after it
+// finishes its current task. max <= 0 or scaleInterval <= 0 turns
+// auto-scaling off.
+func (m *WorkerManager) SetAutoScale(min, max int, scaleInterval time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.scaler.stop != nil {
+		close(m.scaler.stop)
+		m.scaler.stop = nil
+	}
+	if max <= 0 || scaleInterval <= 0 {
+		return
+	}
+	if min < 0 {
+		min = 0
+	}
+	if min > max {
+		min = max
+	}
+	m.scaler.min = min
+	m.scaler.max = max
+	m.scaler.interval = scaleInterval
+	m.workerPool.SetMaxWorkers(max)
+
+	stop := make(chan struct{})
+	m.scaler.stop = stop
+	go m.runAutoScale(scaleInterval, stop)
+	log.Printf("[WorkerManager] Auto-scaling workers between %d and %d every %v", min, max, scaleInterval)
+}
+
+func (m *WorkerManager) runAutoScale(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			m.autoScaleOnce(time.Now())
+		}
+	}
+}
+
+// autoScaleOnce removes drained workers, then adds or drains one worker if
+// the queue calls for it and the cooldown has passed.
+func (m *WorkerManager) autoScaleOnce(now time.Time) {
+	m.finishDraining()
+
+	m.mu.Lock()
+	s := &m.scaler
+	if s.counter == nil || now.Sub(s.lastScale) < time.Duration(autoScaleCooldownIntervals)*s.interval {
+		m.mu.Unlock()
+		return
+	}
+	ready := s.counter.ReadyBeadCount()
+	stats := m.workerPool.GetPoolStats()
+	active := stats.TotalWorkers - len(s.draining)
+
+	switch {
+	case ready > 0 && stats.IdleWorkers == 0 && active < s.max:
+		if id := m.undrainLocked(); id != "" {
+			s.lastScale = now
+			m.mu.Unlock()
+			log.Printf("[WorkerManager] Auto-scale up: kept draining agent %s (%d ready beads)", id, ready)
+			return
+		}
+		template := m.scaleTemplateLocked()
+		if template == nil {
+			m.mu.Unlock()
+			return
+		}
+		s.seq++
+		name := fmt.Sprintf("%s-autoscale-%d", template.Name, s.seq)
+		s.lastScale = now
+		m.mu.Unlock()
+
+		agent, err := m.SpawnAgentWorker(context.Background(), name, template.PersonaName, template.ProjectID, template.ProviderID, template.Persona)
+		if err != nil {
+			log.Printf("[WorkerManager] Auto-scale up failed: %v", err)
+			return
+		}
+		m.mu.Lock()
+		if s.scaled == nil {
+			s.scaled = make(map[string]bool)
+		}
+		s.scaled[agent.ID] = true
+		m.mu.Unlock()
+		log.Printf("[WorkerManager] Auto-scale up: added agent %s (%d ready beads, %d workers)", agent.ID, ready, active+1)
+
+	case ready == 0 && stats.IdleWorkers > 0 && active > s.min:
+		id := m.drainCandidateLocked()
+		if id == "" {
+			m.mu.Unlock()
+			return
+		}
+		if s.draining == nil {
+			s.draining = make(map[string]bool)
+		}
+		s.draining[id] = true
+		s.lastScale = now
+		m.mu.Unlock()
+		log.Printf("[WorkerManager] Auto-scale down: draining agent %s", id)
+
+	default:
+		m.mu.Unlock()
+	}
+}
+
+// scaleTemplateLocked picks a busy agent to clone when scaling up. The caller
+// must hold m.mu.
+func (m *WorkerManager) scaleTemplateLocked() *models.Agent {
+	ids := make([]string, 0, len(m.agents))
+	for id, a := range m.agents {
+		if a.Status == "working" && a.ProviderID != "" && !m.scaler.draining[id] {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	sort.Strings(ids)
+	template := *m.agents[ids[0]]
+	return &template
+}
+
+// drainCandidateLocked picks a scaled agent to remove, preferring idle ones.
+// The caller must hold m.mu.
+func (m *WorkerManager) drainCandidateLocked() string {
+	var idle, busy []string
+	for id := range m.scaler.scaled {
+		a, ok := m.agents[id]
+		if !ok || m.scaler.draining[id] {
+			continue
+		}
+		if a.Status == "idle" {
+			idle = append(idle, id)
+		} else {
+			busy = append(busy, id)
+		}
+	}
+	sort.Strings(idle)
+	sort.Strings(busy)
+	if len(idle) > 0 {
+		return idle[0]
+	}
+	if len(busy) > 0 {
+		return busy[0]
+	}
+	return ""
+}
+
+// undrainLocked returns a draining agent to service instead of spawning a
+// new one. The caller must hold m.mu.
+func (m *WorkerManager) undrainLocked() string {
+	for id := range m.scaler.draining {
+		delete(m.scaler.draining, id)
+		return id
+	}
+	return ""
+}
+
+// finishDraining removes draining agents whose worker has finished its task.
+func (m *WorkerManager) finishDraining() {
+	m.mu.RLock()
+	var done []string
+	for id := range m.scaler.draining {
+		a, ok := m.agents[id]
+		if !ok {
+			done = append(done, id)
+			continue
+		}
+		if a.Status == "working" {
+			continue
+		}
+		if w, err := m.workerPool.GetWorker(id); err == nil && w.GetStatus() == worker.WorkerStatusWorking {
+			continue
+		}
+		done = append(done, id)
+	}
+	m.mu.RUnlock()
+
+	for _, id := range done {
+		if err := m.StopAgent(id); err != nil {
+			log.Printf("[WorkerManager] Failed to remove drained agent %s: %v", id, err)
+		} else {
+			log.Printf("[WorkerManager] Auto-scale down: removed drained agent %s", id)
+		}
+		m.mu.Lock()
+		delete(m.scaler.draining, id)
+		delete(m.scaler.scaled, id)
+		persister := m.agentPersister
+		m.mu.Unlock()
+
+		// Scaled agents are temporary; don't restore them on restart.
+		if d, ok := persister.(interface{ DeleteAgent(string) error }); ok {
+			_ = d.DeleteAgent(id)
+		}
+	}
+}
+
+// isDrainingLocked reports whether an agent is being drained and should not
+// be given new work. The caller must hold m.mu.
+func (m *WorkerManager) isDrainingLocked(id string) bool {
+	return m.scaler.draining[id]
+}
@@ -0,0 +1,117 @@
+package agent
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jordanhubbard/loom/internal/provider"
+	"github.com/jordanhubbard/loom/internal/worker"
+	"github.com/jordanhubbard/loom/pkg/models"
+)
+
+type fakeReadyCounter struct{ n int }
+
+func (c *fakeReadyCounter) ReadyBeadCount() int { return c.n }
+
+func TestWorkerManager_AutoScale(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"ok"}}]}`))
+	}))
+	defer server.Close()
+	defer close(release)
+
+	m := setupWorkerManager(t)
+	_ = m.providerRegistry.Register(&provider.ProviderConfig{ID: "p1", Type: "openai", Endpoint: server.URL, Model: "m", Status: "active"})
+	persona := &models.Persona{Name: "default/engineer"}
+	base, err := m.SpawnAgentWorker(context.Background(), "Engineer", "default/engineer", "proj-1", "p1", persona)
+	if err != nil {
+		t.Fatalf("SpawnAgentWorker: %v", err)
+	}
+
+	counter := &fakeReadyCounter{n: 5}
+	m.SetReadyBeadCounter(counter)
+	m.SetAutoScale(1, 2, time.Hour) // Long interval: ticks are driven by hand
+	defer m.SetAutoScale(0, 0, 0)
+
+	// Keep the only worker busy.
+	w, _ := m.workerPool.GetWorker(base.ID)
+	go func() { _, _ = w.ExecuteTask(context.Background(), &worker.Task{ID: "t1", Description: "busy"}) }()
+	waitFor(t, func() bool { return w.GetStatus() == worker.WorkerStatusWorking })
+	_ = m.UpdateAgentStatus(base.ID, "working")
+
+	start := time.Now().Add(time.Hour)
+	m.autoScaleOnce(start)
+	agents := m.ListAgents()
+	if len(agents) != 2 {
+		t.Fatalf("agents = %d, want 2 after scaling up", len(agents))
+	}
+	var clone *models.Agent
+	for _, a := range agents {
+		if a.ID != base.ID {
+			clone = a
+		}
+	}
+	if clone.PersonaName != base.PersonaName || clone.ProjectID != "proj-1" || clone.ProviderID != "p1" {
+		t.Errorf("clone = %+v, want copy of the busy agent", clone)
+	}
+
+	// At max: no further growth even with a backlog.
+	m.autoScaleOnce(start.Add(4 * time.Hour))
+	if got := len(m.ListAgents()); got != 2 {
+		t.Errorf("agents = %d, want ceiling of 2", got)
+	}
+
+	// Queue empty: the scaled agent is drained and then removed.
+	counter.n = 0
+	m.autoScaleOnce(start.Add(time.Minute))
+	if got := len(m.ListAgents()); got != 2 {
+		t.Errorf("scaled down during cooldown: agents = %d", got)
+	}
+	m.autoScaleOnce(start.Add(8 * time.Hour))
+	for _, a := range m.GetIdleAgents() {
+		if a.ID == clone.ID {
+			t.Error("draining agent still offered as idle")
+		}
+	}
+	m.autoScaleOnce(start.Add(9 * time.Hour))
+	if _, err := m.GetAgent(clone.ID); err == nil {
+		t.Error("drained agent was not removed")
+	}
+	if _, err := m.GetAgent(base.ID); err != nil {
+		t.Errorf("original agent removed: %v", err)
+	}
+}
+
+func TestWorkerManager_AutoScaleDrainWaitsForTask(t *testing.T) {
+	m := setupWorkerManager(t)
+	m.agents["a1"] = &models.Agent{ID: "a1", Status: "working"}
+	m.scaler.scaled = map[string]bool{"a1": true}
+	m.scaler.draining = map[string]bool{"a1": true}
+
+	m.finishDraining()
+	if _, err := m.GetAgent("a1"); err != nil {
+		t.Fatal("busy draining agent removed before finishing its task")
+	}
+
+	_ = m.UpdateAgentStatus("a1", "idle")
+	m.finishDraining()
+	if _, err := m.GetAgent("a1"); err == nil {
+		t.Error("idle draining agent not removed")
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,81 @@
+package agent
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jordanhubbard/loom/pkg/models"
+)
+
+// SetAgentMaxConcurrent sets how many tasks an agent may run at once and
+// applies it to the agent's worker. Values of 0 or 1 mean one at a time.
+func (m *WorkerManager) SetAgentMaxConcurrent(agentID string, n int) error {
+	if n < 0 {
+		return fmt.Errorf("max_concurrent must not be negative")
+	}
+	m.mu.Lock()
+	agent, ok := m.agents[agentID]
+	if !ok {
+		m.mu.Unlock()
+		return fmt.Errorf("agent not found: %s", agentID)
+	}
+	agent.MaxConcurrent = n
+	m.mu.Unlock()
+
+	if w, err := m.workerPool.GetWorker(agentID); err == nil {
+		w.SetMaxConcurrent(n)
+	}
+	return nil
+}
+
+// hasSpareCapacityLocked reports whether a working agent may start another
+// task. The caller must hold m.mu.
+func (m *WorkerManager) hasSpareCapacityLocked(a *models.Agent) bool {
+	return a.Status == "working" && a.MaxConcurrent > 1 && m.agentTasks[a.ID] < a.MaxConcurrent
+}
+
+// startAgentTask marks an agent as working on a task.
+func (m *WorkerManager) startAgentTask(agentID, beadID string) {
+	m.mu.Lock()
+	if m.agentTasks == nil {
+		m.agentTasks = make(map[string]int)
+	}
+	m.agentTasks[agentID]++
+	m.mu.Unlock()
+
+	_ = m.UpdateAgentStatus(agentID, "working")
+	if beadID == "" {
+		return
+	}
+	m.mu.Lock()
+	if a, ok := m.agents[agentID]; ok {
+		a.CurrentBead = beadID
+		a.LastActive = time.Now()
+		m.persistAgent(a)
+	}
+	m.mu.Unlock()
+}
+
+// finishAgentTask undoes startAgentTask. The agent goes idle once its last
+// running task finishes, unless it has been marked unreachable.
+func (m *WorkerManager) finishAgentTask(agentID, beadID string) {
+	m.mu.Lock()
+	if m.agentTasks[agentID] > 0 {
+		m.agentTasks[agentID]--
+	}
+	remaining := m.agentTasks[agentID]
+	if remaining == 0 {
+		delete(m.agentTasks, agentID)
+	}
+	a, ok := m.agents[agentID]
+	if ok && beadID != "" && a.CurrentBead == beadID {
+		a.CurrentBead = ""
+		m.persistAgent(a)
+	}
+	unreachable := ok && a.Status == StatusUnreachable
+	m.mu.Unlock()
+
+	if remaining == 0 && !unreachable {
+		_ = m.UpdateAgentStatus(agentID, "idle")
+	}
+}
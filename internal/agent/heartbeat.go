@@ -0,0 +1,80 @@
+package agent
+
+import (
+	"log"
+	"time"
+)
+
+// StatusUnreachable marks an agent whose project agent stopped sending
+// heartbeats. Unreachable agents are not given work until a heartbeat
+// arrives again.
+const StatusUnreachable = "unreachable"
+
+// StaleAgent is an agent marked unreachable by ReapStaleAgents.
+type StaleAgent struct {
+	AgentID   string
+	ProjectID string
+	BeadID    string // Bead the agent was working on, if any
+}
+
+// RecordProjectHeartbeat records a heartbeat from a project's agent
+// container for every agent of the project. Unreachable agents go back to
+// idle. It returns the number of agents updated.
+func (m *WorkerManager) RecordProjectHeartbeat(projectID string, at time.Time) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	count := 0
+	for _, agent := range m.agents {
+		if agent.ProjectID != projectID {
+			continue
+		}
+		heartbeat := at
+		agent.LastHeartbeat = &heartbeat
+		if agent.Status == StatusUnreachable {
+			log.Printf("[WorkerManager] Agent %s is reachable again", agent.ID)
+			agent.Status = "idle"
+			m.persistAgent(agent)
+		}
+		count++
+	}
+	return count
+}
+
+// ReapStaleAgents marks agents whose last heartbeat is older than timeout
+// as unreachable and returns them with the beads they were working on.
+// Agents that never sent a heartbeat are left alone.
+func (m *WorkerManager) ReapStaleAgents(now time.Time, timeout time.Duration) []StaleAgent {
+	if timeout <= 0 {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var stale []StaleAgent
+	for _, agent := range m.agents {
+		if agent.LastHeartbeat == nil || agent.Status == StatusUnreachable {
+			continue
+		}
+		silent := now.Sub(*agent.LastHeartbeat)
+		if silent <= timeout {
+			continue
+		}
+
+		log.Printf("[WorkerManager] Agent %s unreachable (no heartbeat for %v)", agent.ID, silent.Round(time.Second))
+		stale = append(stale, StaleAgent{AgentID: agent.ID, ProjectID: agent.ProjectID, BeadID: agent.CurrentBead})
+		agent.Status = StatusUnreachable
+		agent.CurrentBead = ""
+		m.persistAgent(agent)
+
+		if m.eventBus != nil {
+			_ = m.eventBus.PublishAgentEvent("agent.unreachable", agent.ID, agent.ProjectID, map[string]interface{}{
+				"agent_id":       agent.ID,
+				"project_id":     agent.ProjectID,
+				"last_heartbeat": agent.LastHeartbeat.UTC().Format(time.RFC3339),
+			})
+		}
+	}
+	return stale
+}
@@ -0,0 +1,54 @@
+package agent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jordanhubbard/loom/pkg/models"
+)
+
+func TestWorkerManager_ReapStaleAgents(t *testing.T) {
+	m := setupWorkerManager(t)
+	ctx := context.Background()
+	persona := &models.Persona{Name: "test-persona"}
+
+	silent, _ := m.CreateAgent(ctx, "silent", "persona-1", "proj-1", "Role1", persona)
+	alive, _ := m.CreateAgent(ctx, "alive", "persona-2", "proj-2", "Role2", persona)
+	never, _ := m.CreateAgent(ctx, "never", "persona-3", "proj-3", "Role3", persona)
+	for _, a := range []*models.Agent{silent, alive, never} {
+		m.UpdateAgentStatus(a.ID, "idle")
+	}
+	_ = m.AssignBead(silent.ID, "bead-1")
+
+	now := time.Now()
+	if n := m.RecordProjectHeartbeat("proj-1", now.Add(-5*time.Minute)); n != 1 {
+		t.Fatalf("RecordProjectHeartbeat(proj-1) = %d, want 1", n)
+	}
+	m.RecordProjectHeartbeat("proj-2", now)
+
+	stale := m.ReapStaleAgents(now, time.Minute)
+	if len(stale) != 1 || stale[0].AgentID != silent.ID || stale[0].BeadID != "bead-1" {
+		t.Fatalf("ReapStaleAgents() = %+v, want %s with bead-1", stale, silent.ID)
+	}
+	if got, _ := m.GetAgent(silent.ID); got.Status != StatusUnreachable || got.CurrentBead != "" {
+		t.Errorf("reaped agent status = %q, bead = %q; want unreachable with no bead", got.Status, got.CurrentBead)
+	}
+	for _, a := range []*models.Agent{alive, never} {
+		if got, _ := m.GetAgent(a.ID); got.Status != "idle" {
+			t.Errorf("agent %s status = %q, want idle", a.Name, got.Status)
+		}
+	}
+	if idle := m.GetIdleAgentsByProject("proj-1"); len(idle) != 0 {
+		t.Errorf("GetIdleAgentsByProject(proj-1) = %d agents, want none", len(idle))
+	}
+	if again := m.ReapStaleAgents(now, time.Minute); len(again) != 0 {
+		t.Errorf("second ReapStaleAgents() = %+v, want nothing new", again)
+	}
+
+	// A fresh heartbeat brings the agent back.
+	m.RecordProjectHeartbeat("proj-1", now)
+	if got, _ := m.GetAgent(silent.ID); got.Status != "idle" || got.LastHeartbeat == nil || !got.LastHeartbeat.Equal(now) {
+		t.Errorf("agent after heartbeat = %q at %v, want idle at %v", got.Status, got.LastHeartbeat, now)
+	}
+}
@@ -0,0 +1,156 @@
+package agent
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/jordanhubbard/loom/internal/worker"
+)
+
+// DefaultAgentHistorySize is how many task results are kept per agent.
+const DefaultAgentHistorySize = 10
+
+// TaskHistoryEntry summarizes one task an agent executed.
+type TaskHistoryEntry struct {
+	TaskID      string    `json:"task_id"`
+	BeadID      string    `json:"bead_id,omitempty"`
+	ProjectID   string    `json:"project_id,omitempty"`
+	ProviderID  string    `json:"provider_id,omitempty"`
+	StartedAt   time.Time `json:"started_at"`
+	CompletedAt time.Time `json:"completed_at"`
+	DurationMs  int64     `json:"duration_ms"`
+	Success     bool      `json:"success"`
+	TokensUsed  int       `json:"tokens_used"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// taskHistory is a fixed-size ring buffer of an agent's recent tasks.
+type taskHistory struct {
+	entries []TaskHistoryEntry
+	next    int
+	full    bool
+}
+
+func (h *taskHistory) add(e TaskHistoryEntry) {
+	h.entries[h.next] = e
+	h.next = (h.next + 1) % len(h.entries)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// list returns the entries newest first.
+func (h *taskHistory) list() []TaskHistoryEntry {
+	n := h.next
+	if h.full {
+		n = len(h.entries)
+	}
+	out := make([]TaskHistoryEntry, 0, n)
+	for i := 1; i <= n; i++ {
+		out = append(out, h.entries[(h.next-i+len(h.entries))%len(h.entries)])
+	}
+	return out
+}
+
+// agentHistories holds the task history of every agent.
+type agentHistories struct {
+	mu   sync.Mutex
+	size int
+	byID map[string]*taskHistory
+}
+
+// SetAgentHistorySize sets how many task results are kept per agent.
+// Existing history is discarded. size <= 0 restores the default.
+func (m *WorkerManager) SetAgentHistorySize(size int) {
+	if size <= 0 {
+		size = DefaultAgentHistorySize
+	}
+	m.history.mu.Lock()
+	defer m.history.mu.Unlock()
+	m.history.size = size
+	m.history.byID = nil
+}
+
+// GetAgentHistory returns the agent's most recent task results, newest first.
+func (m *WorkerManager) GetAgentHistory(agentID string) []TaskHistoryEntry {
+	m.history.mu.Lock()
+	defer m.history.mu.Unlock()
+	h, ok := m.history.byID[agentID]
+	if !ok {
+		return []TaskHistoryEntry{}
+	}
+	return h.list()
+}
+
+// recordTask adds the outcome of an ExecuteTask call to the agent's history.
+// Calls rejected before reaching the agent are not recorded.
+func (m *WorkerManager) recordTask(agentID string, task *worker.Task, started time.Time, result *worker.TaskResult, err error) {
+	if errors.Is(err, ErrShuttingDown) {
+		return
+	}
+	m.mu.RLock()
+	agent, ok := m.agents[agentID]
+	var projectID, providerID string
+	if ok {
+		projectID, providerID = agent.ProjectID, agent.ProviderID
+	}
+	m.mu.RUnlock()
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	e := TaskHistoryEntry{
+		ProjectID:   projectID,
+		ProviderID:  providerID,
+		StartedAt:   started,
+		CompletedAt: now,
+		DurationMs:  now.Sub(started).Milliseconds(),
+	}
+	if task != nil {
+		e.TaskID = task.ID
+		e.BeadID = task.BeadID
+		if task.ProjectID != "" {
+			e.ProjectID = task.ProjectID
+		}
+	}
+	if result != nil {
+		e.Success = result.Success
+		e.TokensUsed = result.TokensUsed
+		e.Error = result.Error
+		if result.ProviderID != "" {
+			e.ProviderID = result.ProviderID
+		}
+		if !result.CompletedAt.IsZero() {
+			e.CompletedAt = result.CompletedAt
+			e.DurationMs = result.CompletedAt.Sub(started).Milliseconds()
+		}
+	}
+	if err != nil {
+		e.Success = false
+		e.Error = err.Error()
+	}
+
+	m.history.mu.Lock()
+	defer m.history.mu.Unlock()
+	if m.history.size <= 0 {
+		m.history.size = DefaultAgentHistorySize
+	}
+	if m.history.byID == nil {
+		m.history.byID = make(map[string]*taskHistory)
+	}
+	h, ok := m.history.byID[agentID]
+	if !ok {
+		h = &taskHistory{entries: make([]TaskHistoryEntry, m.history.size)}
+		m.history.byID[agentID] = h
+	}
+	h.add(e)
+}
+
+// forgetHistory drops the history of a removed agent.
+func (m *WorkerManager) forgetHistory(agentID string) {
+	m.history.mu.Lock()
+	defer m.history.mu.Unlock()
+	delete(m.history.byID, agentID)
+}
@@ -0,0 +1,99 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jordanhubbard/loom/internal/provider"
+	"github.com/jordanhubbard/loom/internal/worker"
+	"github.com/jordanhubbard/loom/pkg/models"
+)
+
+func TestTaskHistory_RingBuffer(t *testing.T) {
+	h := &taskHistory{entries: make([]TaskHistoryEntry, 3)}
+	if got := h.list(); len(got) != 0 {
+		t.Fatalf("empty history = %v", got)
+	}
+	for i := 1; i <= 5; i++ {
+		h.add(TaskHistoryEntry{TaskID: fmt.Sprintf("t%d", i)})
+	}
+	got := h.list()
+	want := []string{"t5", "t4", "t3"}
+	if len(got) != len(want) {
+		t.Fatalf("len = %d, want %d", len(got), len(want))
+	}
+	for i, e := range got {
+		if e.TaskID != want[i] {
+			t.Errorf("entry %d = %s, want %s", i, e.TaskID, want[i])
+		}
+	}
+}
+
+func TestWorkerManager_AgentHistory(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"done"}}],"usage":{"total_tokens":7}}`))
+	}))
+	defer server.Close()
+
+	m := setupWorkerManager(t)
+	m.SetAgentHistorySize(2)
+	_ = m.providerRegistry.Register(&provider.ProviderConfig{ID: "p1", Type: "openai", Endpoint: server.URL, Model: "m", Status: "active"})
+	a, err := m.SpawnAgentWorker(context.Background(), "Engineer", "default/engineer", "proj-1", "p1", &models.Persona{Name: "default/engineer"})
+	if err != nil {
+		t.Fatalf("SpawnAgentWorker: %v", err)
+	}
+
+	for i := 1; i <= 3; i++ {
+		task := &worker.Task{ID: fmt.Sprintf("t%d", i), BeadID: fmt.Sprintf("bead-%d", i), Description: "work"}
+		if _, err := m.ExecuteTask(context.Background(), a.ID, task); err != nil {
+			t.Fatalf("ExecuteTask: %v", err)
+		}
+	}
+
+	history := m.GetAgentHistory(a.ID)
+	if len(history) != 2 {
+		t.Fatalf("history len = %d, want 2", len(history))
+	}
+	latest := history[0]
+	if latest.TaskID != "t3" || latest.BeadID != "bead-3" || history[1].TaskID != "t2" {
+		t.Errorf("history = %+v, want t3 then t2", history)
+	}
+	if !latest.Success || latest.TokensUsed != 7 || latest.ProviderID != "p1" || latest.ProjectID != "proj-1" {
+		t.Errorf("latest = %+v", latest)
+	}
+	if latest.StartedAt.IsZero() || latest.CompletedAt.Before(latest.StartedAt) {
+		t.Errorf("bad timestamps: %+v", latest)
+	}
+
+	if err := m.StopAgent(a.ID); err != nil {
+		t.Fatalf("StopAgent: %v", err)
+	}
+	if got := m.GetAgentHistory(a.ID); len(got) != 0 {
+		t.Errorf("history kept after StopAgent: %v", got)
+	}
+}
+
+func TestWorkerManager_AgentHistoryConcurrent(t *testing.T) {
+	m := setupWorkerManager(t)
+	m.agents["a1"] = &models.Agent{ID: "a1", ProjectID: "proj-1"}
+	started := time.Now()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			m.recordTask("a1", &worker.Task{ID: fmt.Sprintf("t%d", n)}, started, &worker.TaskResult{Success: true}, nil)
+			_ = m.GetAgentHistory("a1")
+		}(i)
+	}
+	wg.Wait()
+	if got := len(m.GetAgentHistory("a1")); got != DefaultAgentHistorySize {
+		t.Errorf("history len = %d, want %d", got, DefaultAgentHistorySize)
+	}
+}
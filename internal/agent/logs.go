@@ -0,0 +1,220 @@
+package agent
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jordanhubbard/loom/internal/worker"
+)
+
+// DefaultAgentLogSize is how many log lines are kept per agent.
+const DefaultAgentLogSize = 500
+
+// agentLogSubscriberBuffer is how many lines a slow follower may lag behind
+// before new lines are dropped for it.
+const agentLogSubscriberBuffer = 100
+
+// Sources of agent log lines.
+const (
+	LogSourceWorker       = "worker"
+	LogSourceProjectAgent = "project-agent"
+)
+
+// AgentLogLine is one line of an agent's execution log.
+type AgentLogLine struct {
+	Time    time.Time `json:"time"`
+	TaskID  string    `json:"task_id,omitempty"`
+	BeadID  string    `json:"bead_id,omitempty"`
+	Source  string    `json:"source"`
+	Level   string    `json:"level"` // info or error
+	Message string    `json:"message"`
+}
+
+// agentLog is an agent's recent log lines and the channels following it.
+type agentLog struct {
+	lines []AgentLogLine
+	subs  map[chan AgentLogLine]struct{}
+}
+
+// agentLogs holds the execution log of every agent.
+type agentLogs struct {
+	mu   sync.Mutex
+	size int
+	byID map[string]*agentLog
+}
+
+// AppendAgentLog adds a line to the agent's log and sends it to followers.
+// A multi-line message is split into one line per line of text. Lines for
+// unknown agents are dropped.
+func (m *WorkerManager) AppendAgentLog(agentID string, line AgentLogLine) {
+	m.mu.RLock()
+	_, ok := m.agents[agentID]
+	m.mu.RUnlock()
+	if !ok {
+		return
+	}
+	if line.Time.IsZero() {
+		line.Time = time.Now()
+	}
+	if line.Level == "" {
+		line.Level = "info"
+	}
+
+	m.logs.mu.Lock()
+	defer m.logs.mu.Unlock()
+	l := m.logs.get(agentID)
+	for _, text := range strings.Split(strings.TrimRight(line.Message, "\n"), "\n") {
+		next := line
+		next.Message = text
+		l.lines = append(l.lines, next)
+		for ch := range l.subs {
+			select {
+			case ch <- next:
+			default:
+			}
+		}
+	}
+	if over := len(l.lines) - m.logs.size; over > 0 {
+		l.lines = append([]AgentLogLine(nil), l.lines[over:]...)
+	}
+}
+
+// GetAgentLogs returns the agent's last limit log lines, oldest first. A
+// limit of 0 or less returns every line kept.
+func (m *WorkerManager) GetAgentLogs(agentID string, limit int) []AgentLogLine {
+	m.logs.mu.Lock()
+	defer m.logs.mu.Unlock()
+	l, ok := m.logs.byID[agentID]
+	if !ok {
+		return []AgentLogLine{}
+	}
+	return l.tail(limit)
+}
+
+// SubscribeAgentLogs returns the agent's last limit log lines, as
+// GetAgentLogs does, a channel that receives every line logged after them
+// and a function that stops the subscription. Taking both under one lock
+// means no line is missed or sent twice. The channel is closed when the
+// subscription stops or the agent is removed.
+func (m *WorkerManager) SubscribeAgentLogs(agentID string, limit int) ([]AgentLogLine, <-chan AgentLogLine, func()) {
+	ch := make(chan AgentLogLine, agentLogSubscriberBuffer)
+	m.logs.mu.Lock()
+	l := m.logs.get(agentID)
+	backlog := l.tail(limit)
+	l.subs[ch] = struct{}{}
+	m.logs.mu.Unlock()
+
+	var once sync.Once
+	return backlog, ch, func() {
+		once.Do(func() {
+			m.logs.mu.Lock()
+			defer m.logs.mu.Unlock()
+			if l, ok := m.logs.byID[agentID]; ok {
+				if _, ok := l.subs[ch]; ok {
+					delete(l.subs, ch)
+					close(ch)
+				}
+			}
+		})
+	}
+}
+
+// tail copies the last limit lines, or all of them when limit is 0 or less.
+// The caller holds mu.
+func (l *agentLog) tail(limit int) []AgentLogLine {
+	lines := l.lines
+	if limit > 0 && len(lines) > limit {
+		lines = lines[len(lines)-limit:]
+	}
+	return append([]AgentLogLine{}, lines...)
+}
+
+// get returns the agent's log, creating it. The caller holds mu.
+func (s *agentLogs) get(agentID string) *agentLog {
+	if s.size <= 0 {
+		s.size = DefaultAgentLogSize
+	}
+	if s.byID == nil {
+		s.byID = make(map[string]*agentLog)
+	}
+	l, ok := s.byID[agentID]
+	if !ok {
+		l = &agentLog{subs: make(map[chan AgentLogLine]struct{})}
+		s.byID[agentID] = l
+	}
+	return l
+}
+
+// forgetLogs drops the log of a removed agent and ends its followers.
+func (m *WorkerManager) forgetLogs(agentID string) {
+	m.logs.mu.Lock()
+	defer m.logs.mu.Unlock()
+	if l, ok := m.logs.byID[agentID]; ok {
+		for ch := range l.subs {
+			close(ch)
+		}
+		delete(m.logs.byID, agentID)
+	}
+}
+
+// logTaskStart logs the start of a task and returns the task to run and a
+// function that logs the task's outcome. When the caller streams the task,
+// its output is logged line by line as it arrives; otherwise the response is
+// logged when the task ends.
+func (m *WorkerManager) logTaskStart(agentID string, task *worker.Task) (*worker.Task, func(*worker.TaskResult, error)) {
+	if task == nil {
+		return nil, func(*worker.TaskResult, error) {}
+	}
+	base := AgentLogLine{TaskID: task.ID, BeadID: task.BeadID, Source: LogSourceWorker}
+	logLine := func(level, message string) {
+		line := base
+		line.Level, line.Message = level, message
+		m.AppendAgentLog(agentID, line)
+	}
+	logLine("info", "Task started: "+task.Description)
+
+	var mu sync.Mutex
+	var partial strings.Builder
+	streamed := false
+	run := *task
+	if task.OnChunk != nil {
+		run.OnChunk = func(chunk string) {
+			mu.Lock()
+			streamed = true
+			partial.WriteString(chunk)
+			var complete string
+			if text := partial.String(); strings.Contains(text, "\n") {
+				cut := strings.LastIndex(text, "\n")
+				complete = text[:cut]
+				partial.Reset()
+				partial.WriteString(text[cut+1:])
+			}
+			mu.Unlock()
+			if complete != "" {
+				logLine("info", complete)
+			}
+			task.OnChunk(chunk)
+		}
+	}
+
+	return &run, func(result *worker.TaskResult, err error) {
+		mu.Lock()
+		rest := partial.String()
+		mu.Unlock()
+		switch {
+		case streamed && rest != "":
+			logLine("info", rest)
+		case !streamed && result != nil && result.Response != "":
+			logLine("info", result.Response)
+		}
+		switch {
+		case err != nil:
+			logLine("error", "Task failed: "+err.Error())
+		case result != nil && !result.Success && result.Error != "":
+			logLine("error", "Task failed: "+result.Error)
+		default:
+			logLine("info", "Task completed")
+		}
+	}
+}
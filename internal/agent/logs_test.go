@@ -0,0 +1,108 @@
+package agent
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jordanhubbard/loom/internal/provider"
+	"github.com/jordanhubbard/loom/internal/worker"
+	"github.com/jordanhubbard/loom/pkg/models"
+)
+
+func TestWorkerManager_AgentLogs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"line one\nline two"}}],"usage":{"total_tokens":7}}`))
+	}))
+	defer server.Close()
+
+	m := setupWorkerManager(t)
+	_ = m.providerRegistry.Register(&provider.ProviderConfig{ID: "p1", Type: "openai", Endpoint: server.URL, Model: "m", Status: "active"})
+	a, err := m.SpawnAgentWorker(context.Background(), "Engineer", "default/engineer", "proj-1", "p1", &models.Persona{Name: "default/engineer"})
+	if err != nil {
+		t.Fatalf("SpawnAgentWorker: %v", err)
+	}
+
+	if _, err := m.ExecuteTask(context.Background(), a.ID, &worker.Task{ID: "t1", BeadID: "bead-1", Description: "fix the build"}); err != nil {
+		t.Fatalf("ExecuteTask: %v", err)
+	}
+
+	var messages []string
+	for _, line := range m.GetAgentLogs(a.ID, 0) {
+		if line.TaskID != "t1" || line.BeadID != "bead-1" || line.Source != LogSourceWorker {
+			t.Errorf("line %+v is missing its task, bead or source", line)
+		}
+		messages = append(messages, line.Message)
+	}
+	want := []string{"Task started: fix the build", "line one", "line two", "Task completed"}
+	if strings.Join(messages, "|") != strings.Join(want, "|") {
+		t.Errorf("log = %q, want %q", messages, want)
+	}
+
+	if got := m.GetAgentLogs(a.ID, 2); len(got) != 2 || got[1].Message != "Task completed" {
+		t.Errorf("GetAgentLogs(limit 2) = %+v, want the last two lines", got)
+	}
+}
+
+func TestWorkerManager_AgentLogsBounded(t *testing.T) {
+	m := setupWorkerManager(t)
+	a, err := m.CreateAgent(context.Background(), "Engineer", "default/engineer", "proj-1", "", &models.Persona{Name: "default/engineer"})
+	if err != nil {
+		t.Fatalf("CreateAgent: %v", err)
+	}
+
+	for i := 0; i < DefaultAgentLogSize+10; i++ {
+		m.AppendAgentLog(a.ID, AgentLogLine{Message: "line"})
+	}
+	if got := len(m.GetAgentLogs(a.ID, 0)); got != DefaultAgentLogSize {
+		t.Errorf("kept %d lines, want %d", got, DefaultAgentLogSize)
+	}
+
+	m.AppendAgentLog("unknown-agent", AgentLogLine{Message: "dropped"})
+	if got := m.GetAgentLogs("unknown-agent", 0); len(got) != 0 {
+		t.Errorf("lines for an unknown agent should be dropped, got %d", len(got))
+	}
+}
+
+func TestWorkerManager_SubscribeAgentLogs(t *testing.T) {
+	m := setupWorkerManager(t)
+	a, err := m.CreateAgent(context.Background(), "Engineer", "default/engineer", "proj-1", "", &models.Persona{Name: "default/engineer"})
+	if err != nil {
+		t.Fatalf("CreateAgent: %v", err)
+	}
+
+	m.AppendAgentLog(a.ID, AgentLogLine{Message: "earlier"})
+	backlog, lines, unsubscribe := m.SubscribeAgentLogs(a.ID, 0)
+	if len(backlog) != 1 || backlog[0].Message != "earlier" {
+		t.Errorf("backlog = %+v, want only the earlier line", backlog)
+	}
+	m.AppendAgentLog(a.ID, AgentLogLine{Source: LogSourceProjectAgent, Message: "building\ntesting"})
+	for _, want := range []string{"building", "testing"} {
+		select {
+		case line := <-lines:
+			if line.Message != want || line.Level != "info" {
+				t.Errorf("line = %+v, want info %q", line, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("no line received, want %q", want)
+		}
+	}
+
+	unsubscribe()
+	unsubscribe()
+	if _, ok := <-lines; ok {
+		t.Error("channel should be closed after unsubscribing")
+	}
+
+	_, lines, unsubscribe = m.SubscribeAgentLogs(a.ID, 0)
+	defer unsubscribe()
+	if err := m.StopAgent(a.ID); err != nil {
+		t.Fatalf("StopAgent: %v", err)
+	}
+	if _, ok := <-lines; ok {
+		t.Error("channel should be closed when the agent is removed")
+	}
+}
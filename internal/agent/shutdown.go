@@ -0,0 +1,112 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+)
+
+// ErrShuttingDown is returned by ExecuteTask once Shutdown has begun.
+var ErrShuttingDown = errors.New("worker manager is shutting down")
+
+// ShutdownError is returned by Shutdown when the context expired before
+// running tasks finished and those tasks were cancelled.
+type ShutdownError struct {
+	Cancelled int
+	Err       error
+}
+
+func (e *ShutdownError) Error() string {
+	return fmt.Sprintf("shutdown cancelled %d running task(s): %v", e.Cancelled, e.Err)
+}
+
+func (e *ShutdownError) Unwrap() error {
+	return e.Err
+}
+
+// runningTask is a task being executed and the func that cancels it.
+type runningTask struct {
+	beadID string
+	cancel context.CancelFunc
+}
+
+// beginTask registers a running task for a bead and returns a context that
+// Shutdown and CancelTask can cancel, plus a func to call when the task ends.
+func (m *WorkerManager) beginTask(ctx context.Context, beadID string) (context.Context, func(), error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.shuttingDown {
+		return nil, nil, ErrShuttingDown
+	}
+	taskCtx, cancel := context.WithCancel(ctx)
+	if m.running == nil {
+		m.running = make(map[uint64]runningTask)
+	}
+	m.nextTaskID++
+	id := m.nextTaskID
+	m.running[id] = runningTask{beadID: beadID, cancel: cancel}
+	m.inflight.Add(1)
+
+	return taskCtx, func() {
+		m.mu.Lock()
+		delete(m.running, id)
+		m.mu.Unlock()
+		cancel()
+		m.inflight.Done()
+	}, nil
+}
+
+// Shutdown stops accepting tasks, waits for running tasks to finish and then
+// stops all workers. If ctx expires first, the remaining tasks are cancelled
+// and a *ShutdownError reports how many there were.
+func (m *WorkerManager) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	m.shuttingDown = true
+	m.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		m.inflight.Wait()
+		close(done)
+	}()
+
+	var err error
+	select {
+	case <-done:
+	case <-ctx.Done():
+		m.mu.Lock()
+		cancelled := len(m.running)
+		for _, task := range m.running {
+			task.cancel()
+		}
+		m.mu.Unlock()
+		log.Printf("[WorkerManager] Shutdown deadline reached, cancelled %d running task(s)", cancelled)
+		err = &ShutdownError{Cancelled: cancelled, Err: ctx.Err()}
+	}
+
+	m.StopAll()
+	return err
+}
+
+// CancelTask cancels the running tasks for a bead and reports whether there
+// were any. The worker sees its context cancelled and stops.
+func (m *WorkerManager) CancelTask(beadID string) bool {
+	if beadID == "" {
+		return false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cancelled := false
+	for _, task := range m.running {
+		if task.beadID == beadID {
+			task.cancel()
+			cancelled = true
+		}
+	}
+	if cancelled {
+		log.Printf("[WorkerManager] Cancelled running task for bead %s", beadID)
+	}
+	return cancelled
+}
@@ -0,0 +1,183 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jordanhubbard/loom/internal/provider"
+	"github.com/jordanhubbard/loom/internal/worker"
+	"github.com/jordanhubbard/loom/pkg/models"
+)
+
+// spawnBlockingAgents registers a provider whose responses wait for release
+// and spawns n agents using it. served counts responses sent.
+func spawnBlockingAgents(t *testing.T, m *WorkerManager, n int, release <-chan struct{}, served *atomic.Int32) []*models.Agent {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+			return
+		}
+		served.Add(1)
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"done"}}]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	_ = m.providerRegistry.Register(&provider.ProviderConfig{ID: "p1", Type: "openai", Endpoint: server.URL, Model: "m", Status: "active"})
+	agents := make([]*models.Agent, 0, n)
+	for i := 0; i < n; i++ {
+		a, err := m.SpawnAgentWorker(context.Background(), fmt.Sprintf("Engineer%d", i), "default/engineer", "proj-1", "p1", &models.Persona{Name: "default/engineer"})
+		if err != nil {
+			t.Fatalf("SpawnAgentWorker: %v", err)
+		}
+		agents = append(agents, a)
+	}
+	return agents
+}
+
+func TestWorkerManager_ShutdownWaitsForRunningTasks(t *testing.T) {
+	m := setupWorkerManager(t)
+	release := make(chan struct{})
+	var served atomic.Int32
+	agents := spawnBlockingAgents(t, m, 2, release, &served)
+
+	taskErrs := make(chan error, len(agents))
+	for i, a := range agents {
+		go func(id string, n int) {
+			_, err := m.ExecuteTask(context.Background(), id, &worker.Task{ID: fmt.Sprintf("t%d", n), Description: "work"})
+			taskErrs <- err
+		}(a.ID, i)
+	}
+	waitFor(t, func() bool {
+		m.mu.RLock()
+		defer m.mu.RUnlock()
+		return len(m.running) == 2
+	})
+
+	shutdownDone := make(chan error, 1)
+	go func() { shutdownDone <- m.Shutdown(context.Background()) }()
+
+	waitFor(t, func() bool {
+		m.mu.RLock()
+		defer m.mu.RUnlock()
+		return m.shuttingDown
+	})
+	if _, err := m.ExecuteTask(context.Background(), agents[0].ID, &worker.Task{ID: "late"}); !errors.Is(err, ErrShuttingDown) {
+		t.Errorf("ExecuteTask during shutdown err = %v, want ErrShuttingDown", err)
+	}
+	select {
+	case err := <-shutdownDone:
+		t.Fatalf("Shutdown returned before tasks finished: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	select {
+	case err := <-shutdownDone:
+		if err != nil {
+			t.Fatalf("Shutdown: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Shutdown did not return after tasks finished")
+	}
+	if got := served.Load(); got != 2 {
+		t.Errorf("responses served = %d, want 2 before Shutdown returned", got)
+	}
+	for range agents {
+		if err := <-taskErrs; err != nil {
+			t.Errorf("task failed: %v", err)
+		}
+	}
+	if len(m.ListAgents()) != 0 {
+		t.Error("agents remain after Shutdown")
+	}
+}
+
+func TestWorkerManager_ShutdownCancelsOnDeadline(t *testing.T) {
+	m := setupWorkerManager(t)
+	release := make(chan struct{})
+	defer close(release)
+	var served atomic.Int32
+	agents := spawnBlockingAgents(t, m, 1, release, &served)
+
+	taskErr := make(chan error, 1)
+	go func() {
+		_, err := m.ExecuteTask(context.Background(), agents[0].ID, &worker.Task{ID: "t1", Description: "work"})
+		taskErr <- err
+	}()
+	waitFor(t, func() bool {
+		m.mu.RLock()
+		defer m.mu.RUnlock()
+		return len(m.running) == 1
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := m.Shutdown(ctx)
+	var shutdownErr *ShutdownError
+	if !errors.As(err, &shutdownErr) || shutdownErr.Cancelled != 1 {
+		t.Fatalf("Shutdown err = %v, want ShutdownError with 1 cancelled", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown err does not wrap the context error: %v", err)
+	}
+
+	select {
+	case err := <-taskErr:
+		if err == nil {
+			t.Error("cancelled task reported success")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("cancelled task did not return")
+	}
+}
+
+func TestWorkerManager_CancelTask(t *testing.T) {
+	m := setupWorkerManager(t)
+	release := make(chan struct{})
+	defer close(release)
+	var served atomic.Int32
+	agents := spawnBlockingAgents(t, m, 2, release, &served)
+
+	taskErrs := make(map[string]chan error)
+	for i, beadID := range []string{"b1", "b2"} {
+		errCh := make(chan error, 1)
+		taskErrs[beadID] = errCh
+		go func(agentID, beadID string) {
+			_, err := m.ExecuteTask(context.Background(), agentID, &worker.Task{ID: "t-" + beadID, BeadID: beadID, Description: "work"})
+			errCh <- err
+		}(agents[i].ID, beadID)
+	}
+	waitFor(t, func() bool {
+		m.mu.RLock()
+		defer m.mu.RUnlock()
+		return len(m.running) == 2
+	})
+
+	if m.CancelTask("unknown") {
+		t.Error("CancelTask reported a task for a bead that is not running")
+	}
+	if !m.CancelTask("b1") {
+		t.Fatal("CancelTask did not find the running task for b1")
+	}
+	select {
+	case err := <-taskErrs["b1"]:
+		if err == nil {
+			t.Error("cancelled task reported success")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("cancelled task did not return")
+	}
+	select {
+	case err := <-taskErrs["b2"]:
+		t.Fatalf("task for b2 returned after cancelling b1: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
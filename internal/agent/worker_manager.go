@@ -36,6 +36,16 @@ type WorkerManager struct {
 	db                *database.Database
 	mu                sync.RWMutex
 	maxAgents         int
+	scaler            autoScaler
+	history           agentHistories
+	logs              agentLogs
+	agentTasks        map[string]int // Tasks running per agent; see concurrency.go
+
+	// Graceful shutdown and cancellation: running tasks and their cancel funcs
+	shuttingDown bool
+	running      map[uint64]runningTask
+	nextTaskID   uint64
+	inflight     sync.WaitGroup
 }
 
 // NewWorkerManager creates a new agent manager with worker pool
@@ -411,7 +421,9 @@ func (m *WorkerManager) RestoreAgentWorker(ctx context.Context, agent *models.Ag
 	return agent, nil
 }
 
-// GetIdleAgentsByProject returns idle agents for a given project.
+// GetIdleAgentsByProject returns the agents of a project that can take a
+// task: idle and paused agents, and working agents with a free concurrent
+// task slot.
 func (m *WorkerManager) GetIdleAgentsByProject(projectID string) []*models.Agent {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -420,7 +432,10 @@ func (m *WorkerManager) GetIdleAgentsByProject(projectID string) []*models.Agent
 	for _, a := range m.agents {
 		// Include both "idle" and "paused" agents — paused agents are idle
 		// but waiting for a provider, which the dispatcher can auto-assign.
-		if a.Status != "idle" && a.Status != "paused" {
+		if a.Status != "idle" && a.Status != "paused" && !m.hasSpareCapacityLocked(a) {
+			continue
+		}
+		if m.isDrainingLocked(a.ID) {
 			continue
 		}
 		if projectID != "" && a.ProjectID != projectID {
@@ -432,12 +447,29 @@ func (m *WorkerManager) GetIdleAgentsByProject(projectID string) []*models.Agent
 	return agents
 }
 
-// ExecuteTask assigns a task to an agent's worker
+// ExecuteTask assigns a task to an agent's worker and records the outcome in
+// the agent's history and log.
 func (m *WorkerManager) ExecuteTask(ctx context.Context, agentID string, task *worker.Task) (*worker.TaskResult, error) {
+	started := time.Now()
+	run, logOutcome := m.logTaskStart(agentID, task)
+	result, err := m.executeTask(ctx, agentID, run)
+	logOutcome(result, err)
+	m.recordTask(agentID, task, started, result, err)
+	return result, err
+}
+
+func (m *WorkerManager) executeTask(ctx context.Context, agentID string, task *worker.Task) (*worker.TaskResult, error) {
 	// Create tracing span for agent execution
 	ctx, span := telemetry.Tracer.Start(ctx, "agent.ExecuteTask")
 	defer span.End()
 
+	ctx, done, err := m.beginTask(ctx, task.BeadID)
+	if err != nil {
+		span.SetStatus(codes.Error, "shutting down")
+		return nil, err
+	}
+	defer done()
+
 	m.mu.RLock()
 	agent, exists := m.agents[agentID]
 	m.mu.RUnlock()
@@ -447,6 +479,7 @@ func (m *WorkerManager) ExecuteTask(ctx context.Context, agentID string, task *w
 		return nil, fmt.Errorf("agent not found: %s", agentID)
 	}
 
+	tlog := observability.NewLogger("agent").WithTrace(ctx)
 	startTime := time.Now()
 	projectID := agent.ProjectID
 	taskID := ""
@@ -457,7 +490,7 @@ func (m *WorkerManager) ExecuteTask(ctx context.Context, agentID string, task *w
 		}
 		taskID = task.ID
 		beadID = task.BeadID
-		observability.Info("agent.task_start", map[string]interface{}{
+		tlog.Info("agent.task_start", map[string]interface{}{
 			"agent_id":    agent.ID,
 			"project_id":  projectID,
 			"provider_id": agent.ProviderID,
@@ -477,27 +510,8 @@ func (m *WorkerManager) ExecuteTask(ctx context.Context, agentID string, task *w
 	}
 
 	// Update agent status
-	_ = m.UpdateAgentStatus(agentID, "working")
-	if task != nil && task.BeadID != "" {
-		m.mu.Lock()
-		if a, ok := m.agents[agentID]; ok {
-			a.CurrentBead = task.BeadID
-			a.LastActive = time.Now()
-			m.persistAgent(a)
-		}
-		m.mu.Unlock()
-	}
-	defer func() {
-		if task != nil && task.BeadID != "" {
-			m.mu.Lock()
-			if a, ok := m.agents[agentID]; ok {
-				a.CurrentBead = ""
-				m.persistAgent(a)
-			}
-			m.mu.Unlock()
-		}
-		_ = m.UpdateAgentStatus(agentID, "idle")
-	}()
+	m.startAgentTask(agentID, beadID)
+	defer m.finishAgentTask(agentID, beadID)
 
 	// Ensure a worker exists for this agent; auto-spawn if the agent has a
 	// provider but no worker yet (e.g. agents created without a provider that
@@ -516,9 +530,10 @@ func (m *WorkerManager) ExecuteTask(ctx context.Context, agentID string, task *w
 			return nil, fmt.Errorf("failed to get worker for loop: %w", workerErr)
 		}
 
-		// Check if worker is stuck in "working" state - if so, respawn it
+		// Check if worker is stuck in "working" state - if so, respawn it.
+		// Workers running several tasks at once are only stuck when full.
 		workerStatus := workerInstance.GetStatus()
-		if workerStatus != worker.WorkerStatusIdle {
+		if workerStatus != worker.WorkerStatusIdle && !workerInstance.HasCapacity() {
 			log.Printf("[WorkerManager] Worker %s is stuck in status %s, respawning", agentID, workerStatus)
 			// Stop and remove the stuck worker
 			if stopErr := m.workerPool.StopWorker(agentID); stopErr != nil {
@@ -569,7 +584,7 @@ func (m *WorkerManager) ExecuteTask(ctx context.Context, agentID string, task *w
 		loopResult, loopErr := workerInstance.ExecuteTaskWithLoop(ctx, task, loopConfig)
 		if loopErr != nil {
 			elapsed := time.Since(startTime)
-			observability.Error("agent.task_complete", map[string]interface{}{
+			tlog.Error("agent.task_complete", map[string]interface{}{
 				"agent_id":    agent.ID,
 				"project_id":  projectID,
 				"provider_id": agent.ProviderID,
@@ -594,15 +609,18 @@ func (m *WorkerManager) ExecuteTask(ctx context.Context, agentID string, task *w
 		// Store loop metadata
 		result.LoopIterations = loopResult.Iterations
 		result.LoopTerminalReason = loopResult.TerminalReason
+		if result.ProviderID == "" {
+			result.ProviderID = agent.ProviderID
+		}
 
 		_ = m.UpdateHeartbeat(agentID)
 
 		elapsed := time.Since(startTime)
 		if task != nil {
-			observability.Info("agent.task_complete", map[string]interface{}{
+			tlog.Info("agent.task_complete", map[string]interface{}{
 				"agent_id":        agent.ID,
 				"project_id":      projectID,
-				"provider_id":     agent.ProviderID,
+				"provider_id":     result.ProviderID,
 				"task_id":         taskID,
 				"bead_id":         beadID,
 				"duration_ms":     elapsed.Milliseconds(),
@@ -623,9 +641,10 @@ func (m *WorkerManager) ExecuteTask(ctx context.Context, agentID string, task *w
 			}
 			_ = al.LogRequest(ctx, &analytics.RequestLog{
 				UserID:       "agent:" + agent.Name,
+				ProjectID:    projectID,
 				Method:       "POST",
 				Path:         "/internal/worker/execute-loop",
-				ProviderID:   agent.ProviderID,
+				ProviderID:   result.ProviderID,
 				TotalTokens:  int64(result.TokensUsed),
 				LatencyMs:    elapsed.Milliseconds(),
 				StatusCode:   statusCode,
@@ -664,7 +683,7 @@ func (m *WorkerManager) ExecuteTask(ctx context.Context, agentID string, task *w
 	result, err := m.workerPool.ExecuteTask(ctx, task, agentID)
 	if err != nil {
 		elapsed := time.Since(startTime)
-		observability.Error("agent.task_complete", map[string]interface{}{
+		tlog.Error("agent.task_complete", map[string]interface{}{
 			"agent_id":    agent.ID,
 			"project_id":  projectID,
 			"provider_id": agent.ProviderID,
@@ -676,6 +695,7 @@ func (m *WorkerManager) ExecuteTask(ctx context.Context, agentID string, task *w
 		if al := m.analyticsLogger; al != nil {
 			_ = al.LogRequest(ctx, &analytics.RequestLog{
 				UserID:       "agent:" + agent.Name,
+				ProjectID:    projectID,
 				Method:       "POST",
 				Path:         "/internal/worker/execute",
 				ProviderID:   agent.ProviderID,
@@ -692,8 +712,9 @@ func (m *WorkerManager) ExecuteTask(ctx context.Context, agentID string, task *w
 		return nil, fmt.Errorf("task execution failed: %w", err)
 	}
 
-	// Enforce strict JSON action output and route actions
-	if result != nil && task != nil {
+	// Enforce strict JSON action output and route actions. A task the
+	// worker already failed (e.g. over its token budget) has nothing to route.
+	if result != nil && task != nil && result.Success {
 		router := m.actionRouter
 		if router != nil {
 			actx := actions.ActionContext{
@@ -726,15 +747,19 @@ func (m *WorkerManager) ExecuteTask(ctx context.Context, agentID string, task *w
 		}
 	}
 
+	if result != nil && result.ProviderID == "" {
+		result.ProviderID = agent.ProviderID
+	}
+
 	// Update last active time
 	_ = m.UpdateHeartbeat(agentID)
 
 	elapsed := time.Since(startTime)
 	if task != nil {
-		observability.Info("agent.task_complete", map[string]interface{}{
+		tlog.Info("agent.task_complete", map[string]interface{}{
 			"agent_id":    agent.ID,
 			"project_id":  projectID,
-			"provider_id": agent.ProviderID,
+			"provider_id": result.ProviderID,
 			"task_id":     taskID,
 			"bead_id":     beadID,
 			"duration_ms": elapsed.Milliseconds(),
@@ -758,9 +783,10 @@ func (m *WorkerManager) ExecuteTask(ctx context.Context, agentID string, task *w
 		}
 		_ = al.LogRequest(ctx, &analytics.RequestLog{
 			UserID:       "agent:" + agent.Name,
+			ProjectID:    projectID,
 			Method:       "POST",
 			Path:         "/internal/worker/execute",
-			ProviderID:   agent.ProviderID,
+			ProviderID:   result.ProviderID,
 			ModelName:    modelName,
 			TotalTokens:  int64(result.TokensUsed),
 			LatencyMs:    elapsed.Milliseconds(),
@@ -794,6 +820,8 @@ func (m *WorkerManager) StopAgent(id string) error {
 
 	// Remove agent
 	delete(m.agents, id)
+	m.forgetHistory(id)
+	m.forgetLogs(id)
 
 	log.Printf("Stopped agent %s", agent.Name)
 	if m.eventBus != nil {
@@ -941,7 +969,7 @@ func (m *WorkerManager) GetIdleAgents() []*models.Agent {
 
 	agents := make([]*models.Agent, 0)
 	for _, agent := range m.agents {
-		if agent.Status == "idle" {
+		if agent.Status == "idle" && !m.isDrainingLocked(agent.ID) {
 			agents = append(agents, agent)
 		}
 	}
@@ -1021,6 +1049,11 @@ func (m *WorkerManager) StopAll() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	if m.scaler.stop != nil {
+		close(m.scaler.stop)
+		m.scaler.stop = nil
+	}
+
 	// Stop all workers
 	m.workerPool.StopAll()
 
@@ -794,3 +794,41 @@ func TestWorkerManager_ExecuteTask_ErrorCases(t *testing.T) {
 		t.Error("ExecuteTask with nonexistent agent should fail")
 	}
 }
+
+func TestWorkerManager_AgentMaxConcurrent(t *testing.T) {
+	m := setupWorkerManager(t)
+	persona := &models.Persona{Name: "test-persona"}
+	agent, err := m.CreateAgent(context.Background(), "agent-1", "persona-1", "proj-1", "Role1", persona)
+	if err != nil {
+		t.Fatalf("CreateAgent() error = %v", err)
+	}
+
+	if err := m.SetAgentMaxConcurrent(agent.ID, -1); err == nil {
+		t.Error("SetAgentMaxConcurrent(-1) should fail")
+	}
+	if err := m.SetAgentMaxConcurrent("missing", 2); err == nil {
+		t.Error("SetAgentMaxConcurrent() on unknown agent should fail")
+	}
+	if err := m.SetAgentMaxConcurrent(agent.ID, 2); err != nil {
+		t.Fatalf("SetAgentMaxConcurrent() error = %v", err)
+	}
+
+	// A working agent with a free slot is still offered work.
+	m.startAgentTask(agent.ID, "bead-1")
+	if got := len(m.GetIdleAgentsByProject("proj-1")); got != 1 {
+		t.Errorf("GetIdleAgentsByProject() with one of two slots busy = %d, want 1", got)
+	}
+	m.startAgentTask(agent.ID, "bead-2")
+	if got := len(m.GetIdleAgentsByProject("proj-1")); got != 0 {
+		t.Errorf("GetIdleAgentsByProject() with all slots busy = %d, want 0", got)
+	}
+
+	m.finishAgentTask(agent.ID, "bead-1")
+	if a, _ := m.GetAgent(agent.ID); a.Status != "working" || a.CurrentBead != "bead-2" {
+		t.Errorf("after first finish: status = %q, bead = %q, want working on bead-2", a.Status, a.CurrentBead)
+	}
+	m.finishAgentTask(agent.ID, "bead-2")
+	if a, _ := m.GetAgent(agent.ID); a.Status != "idle" || a.CurrentBead != "" {
+		t.Errorf("after last finish: status = %q, bead = %q, want idle", a.Status, a.CurrentBead)
+	}
+}
@@ -0,0 +1,113 @@
+package analytics
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+)
+
+// AlertStateStore is implemented by storage that records when each alert
+// last notified, so cooldowns survive restarts. Scope names the user or
+// project an alert is about and key identifies the alert within it.
+type AlertStateStore interface {
+	GetAlertStates(ctx context.Context, scope string) (map[string]time.Time, error)
+	SetAlertState(ctx context.Context, scope, key string, notifiedAt time.Time) error
+	DeleteAlertState(ctx context.Context, scope, key string) error
+}
+
+// memoryAlertState keeps alert state for storage that cannot persist it.
+type memoryAlertState struct {
+	mu     sync.Mutex
+	states map[string]map[string]time.Time
+}
+
+func newMemoryAlertState() *memoryAlertState {
+	return &memoryAlertState{states: make(map[string]map[string]time.Time)}
+}
+
+func (m *memoryAlertState) GetAlertStates(ctx context.Context, scope string) (map[string]time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	states := make(map[string]time.Time, len(m.states[scope]))
+	for key, at := range m.states[scope] {
+		states[key] = at
+	}
+	return states, nil
+}
+
+func (m *memoryAlertState) SetAlertState(ctx context.Context, scope, key string, notifiedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.states[scope] == nil {
+		m.states[scope] = make(map[string]time.Time)
+	}
+	m.states[scope][key] = notifiedAt
+	return nil
+}
+
+func (m *memoryAlertState) DeleteAlertState(ctx context.Context, scope, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.states[scope], key)
+	return nil
+}
+
+// GetAlertStates returns when each alert in scope last notified
+func (s *DatabaseStorage) GetAlertStates(ctx context.Context, scope string) (map[string]time.Time, error) {
+	return queryAlertStates(ctx, s.db.QueryContext, "SELECT alert_key, notified_at FROM alert_states WHERE scope = ?", scope)
+}
+
+// SetAlertState records when an alert last notified
+func (s *DatabaseStorage) SetAlertState(ctx context.Context, scope, key string, notifiedAt time.Time) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO alert_states (scope, alert_key, notified_at) VALUES (?, ?, ?)
+		ON CONFLICT (scope, alert_key) DO UPDATE SET notified_at = excluded.notified_at`,
+		scope, key, notifiedAt)
+	return err
+}
+
+// DeleteAlertState forgets an alert once it has resolved
+func (s *DatabaseStorage) DeleteAlertState(ctx context.Context, scope, key string) error {
+	_, err := s.db.ExecContext(ctx, "DELETE FROM alert_states WHERE scope = ? AND alert_key = ?", scope, key)
+	return err
+}
+
+// GetAlertStates returns when each alert in scope last notified
+func (s *PostgresStorage) GetAlertStates(ctx context.Context, scope string) (map[string]time.Time, error) {
+	return queryAlertStates(ctx, s.db.QueryContext, "SELECT alert_key, notified_at FROM alert_states WHERE scope = $1", scope)
+}
+
+// SetAlertState records when an alert last notified
+func (s *PostgresStorage) SetAlertState(ctx context.Context, scope, key string, notifiedAt time.Time) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO alert_states (scope, alert_key, notified_at) VALUES ($1, $2, $3)
+		ON CONFLICT (scope, alert_key) DO UPDATE SET notified_at = excluded.notified_at`,
+		scope, key, notifiedAt)
+	return err
+}
+
+// DeleteAlertState forgets an alert once it has resolved
+func (s *PostgresStorage) DeleteAlertState(ctx context.Context, scope, key string) error {
+	_, err := s.db.ExecContext(ctx, "DELETE FROM alert_states WHERE scope = $1 AND alert_key = $2", scope, key)
+	return err
+}
+
+func queryAlertStates(ctx context.Context, query func(context.Context, string, ...interface{}) (*sql.Rows, error), stmt, scope string) (map[string]time.Time, error) {
+	rows, err := query(ctx, stmt, scope)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	states := make(map[string]time.Time)
+	for rows.Next() {
+		var key string
+		var notifiedAt time.Time
+		if err := rows.Scan(&key, &notifiedAt); err != nil {
+			return nil, err
+		}
+		states[key] = notifiedAt
+	}
+	return states, rows.Err()
+}
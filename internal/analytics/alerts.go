@@ -7,10 +7,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"math"
 	"net/http"
 	"net/smtp"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -24,15 +26,24 @@ type SMTPConfig struct {
 	UseTLS   bool   // Whether to use TLS (default: true)
 }
 
-// AlertConfig defines alerting thresholds and settings
+// AlertConfig defines alerting thresholds and settings.
+// When ProjectID is set, budgets apply to the project's total spend across
+// all users rather than to UserID's spend.
 type AlertConfig struct {
 	UserID              string  `json:"user_id"`
-	DailyBudgetUSD      float64 `json:"daily_budget_usd"`   // Alert if daily spend exceeds
-	MonthlyBudgetUSD    float64 `json:"monthly_budget_usd"` // Alert if monthly spend exceeds
-	AnomalyThreshold    float64 `json:"anomaly_threshold"`  // Alert if spend is X times normal (e.g., 2.0 = 2x)
+	ProjectID           string  `json:"project_id,omitempty"`
+	DailyBudgetUSD      float64 `json:"daily_budget_usd"`      // Alert if daily spend exceeds
+	MonthlyBudgetUSD    float64 `json:"monthly_budget_usd"`    // Alert if monthly spend exceeds
+	AnomalyThreshold    float64 `json:"anomaly_threshold"`     // Alert if spend is X times normal (e.g., 2.0 = 2x)
+	LatencyThreshold    float64 `json:"latency_threshold"`     // Alert if today's p95 latency is X times the baseline p95
+	ErrorRateThreshold  float64 `json:"error_rate_threshold"`  // Alert if today's error rate is X times the baseline rate
+	TokenDriftThreshold float64 `json:"token_drift_threshold"` // Alert if tokens per request move X times above or below baseline
+	CooldownMinutes     int     `json:"cooldown_minutes"`      // Minutes before a firing alert notifies again (0 = 60, negative = every check)
 	EnableEmailAlerts   bool    `json:"enable_email_alerts"`
 	EnableWebhookAlerts bool    `json:"enable_webhook_alerts"`
+	EnableSlackAlerts   bool    `json:"enable_slack_alerts"`
 	WebhookURL          string  `json:"webhook_url"`
+	SlackWebhookURL     string  `json:"slack_webhook_url"`
 	EmailAddress        string  `json:"email_address"`
 }
 
@@ -40,20 +51,30 @@ type AlertConfig struct {
 type Alert struct {
 	ID           string    `json:"id"`
 	UserID       string    `json:"user_id"`
-	Type         string    `json:"type"`     // "budget_exceeded", "anomaly_detected"
+	ProjectID    string    `json:"project_id,omitempty"`
+	Type         string    `json:"type"`     // "budget_exceeded", "project_exceeded", "anomaly_detected", "latency_spike", "error_rate_surge", "token_drift"
 	Severity     string    `json:"severity"` // "info", "warning", "critical"
 	Message      string    `json:"message"`
 	CurrentCost  float64   `json:"current_cost"`
 	Threshold    float64   `json:"threshold"`
+	Metric       string    `json:"metric,omitempty"` // What an anomaly alert measured
+	Observed     float64   `json:"observed,omitempty"`
+	Expected     float64   `json:"expected,omitempty"`
+	Resolved     bool      `json:"resolved,omitempty"` // Set on the notice that a firing alert has cleared
 	TriggeredAt  time.Time `json:"triggered_at"`
 	Acknowledged bool      `json:"acknowledged"`
 }
 
+// defaultAlertCooldown is how long a firing alert stays quiet after it
+// notifies when CooldownMinutes is 0.
+const defaultAlertCooldown = time.Hour
+
 // AlertChecker monitors spending and triggers alerts
 type AlertChecker struct {
 	storage    Storage
 	config     *AlertConfig
 	smtpConfig *SMTPConfig
+	state      AlertStateStore // Last notification per alert; the storage when it implements AlertStateStore
 }
 
 // NewAlertChecker creates a new alert checker
@@ -95,8 +116,111 @@ func loadSMTPConfigFromEnv() *SMTPConfig {
 	}
 }
 
-// CheckAlerts checks for spending anomalies and budget overruns
+// CheckAlerts checks for spending anomalies and budget overruns. Alerts
+// that are still firing notify again only after the cooldown, and alerts
+// that have cleared send a resolved notice.
 func (ac *AlertChecker) CheckAlerts(ctx context.Context) ([]*Alert, error) {
+	alerts := ac.EvaluateAlerts(ctx)
+
+	// Notify for each alert
+	firing := make(map[string]bool, len(alerts))
+	for _, alert := range alerts {
+		firing[alertKey(alert)] = true
+		ac.notify(ctx, alert)
+	}
+	ac.resolveAlerts(ctx, firing)
+
+	return alerts, nil
+}
+
+// alertKey identifies an alert for cooldowns: its type, and its metric when
+// one type covers several.
+func alertKey(alert *Alert) string {
+	if alert.Metric == "" {
+		return alert.Type
+	}
+	return alert.Type + "/" + alert.Metric
+}
+
+// alertScope names whose alerts the checker tracks
+func (ac *AlertChecker) alertScope() string {
+	if ac.config.ProjectID != "" {
+		return "project:" + ac.config.ProjectID
+	}
+	return "user:" + ac.config.UserID
+}
+
+func (ac *AlertChecker) alertState() AlertStateStore {
+	if ac.state == nil {
+		if store, ok := ac.storage.(AlertStateStore); ok {
+			ac.state = store
+		} else {
+			ac.state = newMemoryAlertState()
+		}
+	}
+	return ac.state
+}
+
+func (ac *AlertChecker) cooldown() time.Duration {
+	if ac.config.CooldownMinutes == 0 {
+		return defaultAlertCooldown
+	}
+	return time.Duration(ac.config.CooldownMinutes) * time.Minute
+}
+
+// claimNotification reports whether alert may notify now, and if so records
+// the notification. Alerts notified within the cooldown may not.
+func (ac *AlertChecker) claimNotification(ctx context.Context, alert *Alert) bool {
+	scope, key := ac.alertScope(), alertKey(alert)
+	states, err := ac.alertState().GetAlertStates(ctx, scope)
+	if err != nil {
+		log.Printf("[ALERT] Failed to load alert state for %s: %v", scope, err)
+	}
+	now := time.Now()
+	if last, ok := states[key]; ok && now.Sub(last) < ac.cooldown() {
+		return false
+	}
+	if err := ac.alertState().SetAlertState(ctx, scope, key, now); err != nil {
+		log.Printf("[ALERT] Failed to save alert state for %s: %v", scope, err)
+	}
+	return true
+}
+
+// resolveAlerts sends a resolved notice for each alert that notified before
+// and is no longer firing, and forgets it.
+func (ac *AlertChecker) resolveAlerts(ctx context.Context, firing map[string]bool) {
+	scope := ac.alertScope()
+	states, err := ac.alertState().GetAlertStates(ctx, scope)
+	if err != nil {
+		log.Printf("[ALERT] Failed to load alert state for %s: %v", scope, err)
+		return
+	}
+	now := time.Now()
+	for key := range states {
+		if firing[key] {
+			continue
+		}
+		alertType, metric, _ := strings.Cut(key, "/")
+		ac.notify(ctx, &Alert{
+			ID:          fmt.Sprintf("alert-resolved-%d", now.Unix()),
+			UserID:      ac.config.UserID,
+			ProjectID:   ac.config.ProjectID,
+			Type:        alertType,
+			Severity:    "info",
+			Message:     ac.scopeLabel() + fmt.Sprintf("Resolved: %s is back within its threshold", key),
+			Metric:      metric,
+			Resolved:    true,
+			TriggeredAt: now,
+		})
+		if err := ac.alertState().DeleteAlertState(ctx, scope, key); err != nil {
+			log.Printf("[ALERT] Failed to clear alert state for %s: %v", scope, err)
+		}
+	}
+}
+
+// EvaluateAlerts returns the alerts that currently apply without sending
+// any notifications.
+func (ac *AlertChecker) EvaluateAlerts(ctx context.Context) []*Alert {
 	alerts := make([]*Alert, 0)
 
 	// Check daily budget
@@ -114,18 +238,40 @@ func (ac *AlertChecker) CheckAlerts(ctx context.Context) ([]*Alert, error) {
 	}
 
 	// Check for anomalies
-	if ac.config.AnomalyThreshold > 1.0 {
-		if alert := ac.checkAnomalies(ctx); alert != nil {
-			alerts = append(alerts, alert)
-		}
+	if ac.config.AnomalyThreshold > 1.0 || ac.config.LatencyThreshold > 1.0 ||
+		ac.config.ErrorRateThreshold > 1.0 || ac.config.TokenDriftThreshold > 1.0 {
+		alerts = append(alerts, ac.checkAnomalies(ctx)...)
 	}
 
-	// Notify for each alert
-	for _, alert := range alerts {
-		ac.notify(alert)
+	return alerts
+}
+
+// scopedFilter builds a log filter for the configured alert scope: the
+// project's spend when ProjectID is set, otherwise the user's spend
+func (ac *AlertChecker) scopedFilter(start, end time.Time) *LogFilter {
+	filter := &LogFilter{StartTime: start, EndTime: end}
+	if ac.config.ProjectID != "" {
+		filter.ProjectID = ac.config.ProjectID
+	} else {
+		filter.UserID = ac.config.UserID
 	}
+	return filter
+}
 
-	return alerts, nil
+// budgetAlertType returns the alert type for a budget overrun in the configured scope
+func (ac *AlertChecker) budgetAlertType() string {
+	if ac.config.ProjectID != "" {
+		return "project_exceeded"
+	}
+	return "budget_exceeded"
+}
+
+// scopeLabel prefixes alert messages with the project when alerts are project-scoped
+func (ac *AlertChecker) scopeLabel() string {
+	if ac.config.ProjectID != "" {
+		return fmt.Sprintf("Project %s: ", ac.config.ProjectID)
+	}
+	return ""
 }
 
 // checkDailyBudget checks if daily spending exceeds budget
@@ -133,11 +279,7 @@ func (ac *AlertChecker) checkDailyBudget(ctx context.Context) *Alert {
 	now := time.Now()
 	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 
-	stats, err := ac.storage.GetLogStats(ctx, &LogFilter{
-		UserID:    ac.config.UserID,
-		StartTime: startOfDay,
-		EndTime:   now,
-	})
+	stats, err := ac.storage.GetLogStats(ctx, ac.scopedFilter(startOfDay, now))
 	if err != nil {
 		return nil
 	}
@@ -146,11 +288,13 @@ func (ac *AlertChecker) checkDailyBudget(ctx context.Context) *Alert {
 		return &Alert{
 			ID:          fmt.Sprintf("alert-daily-%d", time.Now().Unix()),
 			UserID:      ac.config.UserID,
-			Type:        "budget_exceeded",
+			ProjectID:   ac.config.ProjectID,
+			Type:        ac.budgetAlertType(),
 			Severity:    "warning",
-			Message:     fmt.Sprintf("Daily budget exceeded: $%.2f / $%.2f (%.0f%%)", stats.TotalCostUSD, ac.config.DailyBudgetUSD, (stats.TotalCostUSD/ac.config.DailyBudgetUSD)*100),
+			Message:     ac.scopeLabel() + fmt.Sprintf("Daily budget exceeded: $%.2f / $%.2f (%.0f%%)", stats.TotalCostUSD, ac.config.DailyBudgetUSD, (stats.TotalCostUSD/ac.config.DailyBudgetUSD)*100),
 			CurrentCost: stats.TotalCostUSD,
 			Threshold:   ac.config.DailyBudgetUSD,
+			Metric:      "daily_spend_usd",
 			TriggeredAt: now,
 		}
 	}
@@ -163,11 +307,7 @@ func (ac *AlertChecker) checkMonthlyBudget(ctx context.Context) *Alert {
 	now := time.Now()
 	startOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
 
-	stats, err := ac.storage.GetLogStats(ctx, &LogFilter{
-		UserID:    ac.config.UserID,
-		StartTime: startOfMonth,
-		EndTime:   now,
-	})
+	stats, err := ac.storage.GetLogStats(ctx, ac.scopedFilter(startOfMonth, now))
 	if err != nil {
 		return nil
 	}
@@ -176,11 +316,13 @@ func (ac *AlertChecker) checkMonthlyBudget(ctx context.Context) *Alert {
 		return &Alert{
 			ID:          fmt.Sprintf("alert-monthly-%d", time.Now().Unix()),
 			UserID:      ac.config.UserID,
-			Type:        "budget_exceeded",
+			ProjectID:   ac.config.ProjectID,
+			Type:        ac.budgetAlertType(),
 			Severity:    "critical",
-			Message:     fmt.Sprintf("Monthly budget exceeded: $%.2f / $%.2f (%.0f%%)", stats.TotalCostUSD, ac.config.MonthlyBudgetUSD, (stats.TotalCostUSD/ac.config.MonthlyBudgetUSD)*100),
+			Message:     ac.scopeLabel() + fmt.Sprintf("Monthly budget exceeded: $%.2f / $%.2f (%.0f%%)", stats.TotalCostUSD, ac.config.MonthlyBudgetUSD, (stats.TotalCostUSD/ac.config.MonthlyBudgetUSD)*100),
 			CurrentCost: stats.TotalCostUSD,
 			Threshold:   ac.config.MonthlyBudgetUSD,
+			Metric:      "monthly_spend_usd",
 			TriggeredAt: now,
 		}
 	}
@@ -188,54 +330,102 @@ func (ac *AlertChecker) checkMonthlyBudget(ctx context.Context) *Alert {
 	return nil
 }
 
-// checkAnomalies detects unusual spending patterns
-func (ac *AlertChecker) checkAnomalies(ctx context.Context) *Alert {
+// minAnomalyRequests is the fewest requests today and in the baseline
+// before latency, error rate and token use are compared.
+const minAnomalyRequests = 10
+
+// errorRateFloor stands in for lower baseline error rates, so a surge from
+// no errors at all is still caught.
+const errorRateFloor = 0.01
+
+// checkAnomalies compares today against the last seven days: spending,
+// p95 latency, error rate and tokens per request, each under its own
+// threshold. Checks without enough history are skipped.
+func (ac *AlertChecker) checkAnomalies(ctx context.Context) []*Alert {
 	now := time.Now()
 
-	// Get today's spending
+	// Get today's stats
 	startOfToday := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-	todayStats, err := ac.storage.GetLogStats(ctx, &LogFilter{
-		UserID:    ac.config.UserID,
-		StartTime: startOfToday,
-		EndTime:   now,
-	})
+	todayStats, err := ac.storage.GetLogStats(ctx, ac.scopedFilter(startOfToday, now))
 	if err != nil {
 		return nil
 	}
 
-	// Get average spending from last 7 days (excluding today)
+	// Get the baseline from the last 7 days (excluding today)
 	sevenDaysAgo := startOfToday.Add(-7 * 24 * time.Hour)
-	historicalStats, err := ac.storage.GetLogStats(ctx, &LogFilter{
-		UserID:    ac.config.UserID,
-		StartTime: sevenDaysAgo,
-		EndTime:   startOfToday,
-	})
+	historicalStats, err := ac.storage.GetLogStats(ctx, ac.scopedFilter(sevenDaysAgo, startOfToday))
 	if err != nil {
 		return nil
 	}
 
+	var alerts []*Alert
+
 	// Calculate average daily spending
 	avgDailySpend := historicalStats.TotalCostUSD / 7.0
 
 	// Check if today's spending is anomalous
-	if avgDailySpend > 0 && todayStats.TotalCostUSD > (avgDailySpend*ac.config.AnomalyThreshold) {
-		return &Alert{
-			ID:          fmt.Sprintf("alert-anomaly-%d", time.Now().Unix()),
-			UserID:      ac.config.UserID,
-			Type:        "anomaly_detected",
-			Severity:    "warning",
-			Message:     fmt.Sprintf("Unusual spending detected: $%.2f today vs $%.2f average (%.0fx increase)", todayStats.TotalCostUSD, avgDailySpend, todayStats.TotalCostUSD/avgDailySpend),
-			CurrentCost: todayStats.TotalCostUSD,
-			Threshold:   avgDailySpend * ac.config.AnomalyThreshold,
-			TriggeredAt: now,
+	if ac.config.AnomalyThreshold > 1.0 && avgDailySpend > 0 && todayStats.TotalCostUSD > (avgDailySpend*ac.config.AnomalyThreshold) {
+		alert := ac.anomalyAlert("anomaly_detected", "daily_cost_usd", todayStats.TotalCostUSD, avgDailySpend, now,
+			fmt.Sprintf("Unusual spending detected: $%.2f today vs $%.2f average (%.0fx increase)", todayStats.TotalCostUSD, avgDailySpend, todayStats.TotalCostUSD/avgDailySpend))
+		alert.ID = fmt.Sprintf("alert-anomaly-%d", now.Unix())
+		alert.CurrentCost = todayStats.TotalCostUSD
+		alert.Threshold = avgDailySpend * ac.config.AnomalyThreshold
+		alerts = append(alerts, alert)
+	}
+
+	if todayStats.TotalRequests < minAnomalyRequests || historicalStats.TotalRequests < minAnomalyRequests {
+		return alerts
+	}
+
+	if ac.config.LatencyThreshold > 1.0 && historicalStats.P95LatencyMs > 0 &&
+		todayStats.P95LatencyMs > historicalStats.P95LatencyMs*ac.config.LatencyThreshold {
+		alerts = append(alerts, ac.anomalyAlert("latency_spike", "p95_latency_ms", todayStats.P95LatencyMs, historicalStats.P95LatencyMs, now,
+			fmt.Sprintf("Latency spike detected: p95 %.0fms today vs %.0fms baseline (%.1fx)", todayStats.P95LatencyMs, historicalStats.P95LatencyMs, todayStats.P95LatencyMs/historicalStats.P95LatencyMs)))
+	}
+
+	if ac.config.ErrorRateThreshold > 1.0 {
+		expected := math.Max(historicalStats.ErrorRate, errorRateFloor)
+		if todayStats.ErrorRate > expected*ac.config.ErrorRateThreshold {
+			alerts = append(alerts, ac.anomalyAlert("error_rate_surge", "error_rate", todayStats.ErrorRate, historicalStats.ErrorRate, now,
+				fmt.Sprintf("Error rate surge detected: %.1f%% of requests failed today vs %.1f%% baseline", todayStats.ErrorRate*100, historicalStats.ErrorRate*100)))
 		}
 	}
 
-	return nil
+	if ac.config.TokenDriftThreshold > 1.0 && historicalStats.TotalTokens > 0 {
+		expected := float64(historicalStats.TotalTokens) / float64(historicalStats.TotalRequests)
+		observed := float64(todayStats.TotalTokens) / float64(todayStats.TotalRequests)
+		if observed > expected*ac.config.TokenDriftThreshold || observed < expected/ac.config.TokenDriftThreshold {
+			alerts = append(alerts, ac.anomalyAlert("token_drift", "tokens_per_request", observed, expected, now,
+				fmt.Sprintf("Token usage drift detected: %.0f tokens per request today vs %.0f baseline (%.1fx)", observed, expected, observed/expected)))
+		}
+	}
+
+	return alerts
 }
 
-// notify sends notifications for an alert
-func (ac *AlertChecker) notify(alert *Alert) {
+// anomalyAlert builds a warning for a metric that moved away from its baseline
+func (ac *AlertChecker) anomalyAlert(alertType, metric string, observed, expected float64, now time.Time, message string) *Alert {
+	return &Alert{
+		ID:          fmt.Sprintf("alert-%s-%d", strings.ReplaceAll(alertType, "_", "-"), now.Unix()),
+		UserID:      ac.config.UserID,
+		ProjectID:   ac.config.ProjectID,
+		Type:        alertType,
+		Severity:    "warning",
+		Message:     ac.scopeLabel() + message,
+		Metric:      metric,
+		Observed:    observed,
+		Expected:    expected,
+		TriggeredAt: now,
+	}
+}
+
+// notify sends notifications for an alert unless it is in its cooldown
+func (ac *AlertChecker) notify(ctx context.Context, alert *Alert) {
+	if !alert.Resolved && !ac.claimNotification(ctx, alert) {
+		log.Printf("[ALERT] Suppressed %s for %s: notified within the last %s", alertKey(alert), ac.alertScope(), ac.cooldown())
+		return
+	}
+
 	// Log the alert
 	log.Printf("[ALERT] %s: %s", alert.Severity, alert.Message)
 
@@ -260,6 +450,112 @@ func (ac *AlertChecker) notify(alert *Alert) {
 			log.Printf("[ALERT] Webhook notification sent to %s: %s", ac.config.WebhookURL, alert.Message)
 		}
 	}
+
+	// Send Slack notifications if enabled
+	if ac.config.EnableSlackAlerts {
+		if ac.config.SlackWebhookURL == "" {
+			log.Printf("[ALERT] Slack notifications enabled but slack_webhook_url not configured")
+		} else if err := ac.sendSlack(alert); err != nil {
+			log.Printf("[ALERT] Failed to send Slack notification: %v", err)
+		} else {
+			log.Printf("[ALERT] Slack notification sent: %s", alert.Message)
+		}
+	}
+}
+
+// sendSlack sends an alert to a Slack incoming webhook as a Block Kit
+// message wrapped in an attachment colored by severity
+func (ac *AlertChecker) sendSlack(alert *Alert) error {
+	blocks := []map[string]interface{}{
+		{
+			"type": "header",
+			"text": map[string]interface{}{
+				"type": "plain_text",
+				"text": fmt.Sprintf("Loom Alert: %s", alert.Type),
+			},
+		},
+		{
+			"type": "section",
+			"text": map[string]interface{}{
+				"type": "mrkdwn",
+				"text": alert.Message,
+			},
+		},
+		{
+			"type":   "section",
+			"fields": slackAlertFields(alert),
+		},
+		{
+			"type": "context",
+			"elements": []map[string]interface{}{
+				{"type": "mrkdwn", "text": fmt.Sprintf("Alert %s triggered at %s", alert.ID, alert.TriggeredAt.Format(time.RFC3339))},
+			},
+		},
+	}
+
+	payload := map[string]interface{}{
+		// Fallback text for notifications and clients without Block Kit
+		"text": fmt.Sprintf("[Loom Alert] %s: %s", alert.Severity, alert.Message),
+		"attachments": []map[string]interface{}{
+			{
+				"color":  severityColor(alert.Severity),
+				"blocks": blocks,
+			},
+		},
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal slack payload: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", ac.config.SlackWebhookURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create slack request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Loom-Alerts/1.0")
+
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send slack notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("slack webhook returned non-success status: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// slackAlertFields lists an alert's details: the cost fields for cost
+// alerts and the observed and expected values for anomalies
+func slackAlertFields(alert *Alert) []map[string]interface{} {
+	fields := []map[string]interface{}{
+		{"type": "mrkdwn", "text": fmt.Sprintf("*Severity:*\n%s", alert.Severity)},
+		{"type": "mrkdwn", "text": fmt.Sprintf("*User:*\n%s", alert.UserID)},
+	}
+	if alert.Resolved {
+		return fields
+	}
+	if alert.Metric == "" || alert.CurrentCost > 0 || alert.Threshold > 0 {
+		fields = append(fields,
+			map[string]interface{}{"type": "mrkdwn", "text": fmt.Sprintf("*Current Cost:*\n$%.2f USD", alert.CurrentCost)},
+			map[string]interface{}{"type": "mrkdwn", "text": fmt.Sprintf("*Threshold:*\n$%.2f USD", alert.Threshold)},
+		)
+	}
+	if alert.Metric != "" && alert.Observed+alert.Expected != 0 {
+		fields = append(fields,
+			map[string]interface{}{"type": "mrkdwn", "text": fmt.Sprintf("*Metric:*\n%s", alert.Metric)},
+			map[string]interface{}{"type": "mrkdwn", "text": fmt.Sprintf("*Observed / Expected:*\n%.4g / %.4g", alert.Observed, alert.Expected)},
+		)
+	}
+	return fields
 }
 
 // sendWebhook sends an alert via HTTP webhook
@@ -268,12 +564,19 @@ func (ac *AlertChecker) sendWebhook(alert *Alert) error {
 	payload := map[string]interface{}{
 		"id":           alert.ID,
 		"user_id":      alert.UserID,
+		"project_id":   alert.ProjectID,
 		"type":         alert.Type,
 		"severity":     alert.Severity,
 		"message":      alert.Message,
 		"current_cost": alert.CurrentCost,
 		"threshold":    alert.Threshold,
 		"triggered_at": alert.TriggeredAt.Format(time.RFC3339),
+		"resolved":     alert.Resolved,
+	}
+	if alert.Metric != "" {
+		payload["metric"] = alert.Metric
+		payload["observed"] = alert.Observed
+		payload["expected"] = alert.Expected
 	}
 
 	jsonData, err := json.Marshal(payload)
@@ -412,15 +715,20 @@ func sendEmailTLS(addr string, auth smtp.Auth, from string, to []string, msg []b
 	return nil
 }
 
-// buildEmailBody creates an HTML email body for the alert
-func buildEmailBody(alert *Alert) string {
-	severityColor := "#FFA500" // Orange for warning
-	if alert.Severity == "critical" {
-		severityColor = "#DC3545" // Red
-	} else if alert.Severity == "info" {
-		severityColor = "#17A2B8" // Blue
+// severityColor maps an alert severity to the hex color used in notifications
+func severityColor(severity string) string {
+	switch severity {
+	case "critical":
+		return "#DC3545" // Red
+	case "info":
+		return "#17A2B8" // Blue
+	default:
+		return "#FFA500" // Orange for warning
 	}
+}
 
+// buildEmailBody creates an HTML email body for the alert
+func buildEmailBody(alert *Alert) string {
 	return fmt.Sprintf(`
 <!DOCTYPE html>
 <html>
@@ -477,7 +785,7 @@ func buildEmailBody(alert *Alert) string {
 </body>
 </html>
 `,
-		severityColor,
+		severityColor(alert.Severity),
 		alert.Severity,
 		alert.Type,
 		alert.Message,
@@ -495,7 +803,11 @@ func DefaultAlertConfig(userID string) *AlertConfig {
 		DailyBudgetUSD:      100.0,  // $100/day default
 		MonthlyBudgetUSD:    2000.0, // $2000/month default
 		AnomalyThreshold:    2.0,    // Alert if 2x normal spending
+		LatencyThreshold:    2.0,    // Alert if p95 latency doubles
+		ErrorRateThreshold:  3.0,    // Alert if 3x the usual error rate
+		TokenDriftThreshold: 2.0,    // Alert if tokens per request double or halve
 		EnableEmailAlerts:   false,
 		EnableWebhookAlerts: false,
+		EnableSlackAlerts:   false,
 	}
 }
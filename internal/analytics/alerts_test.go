@@ -2,7 +2,12 @@ package analytics
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 )
@@ -180,6 +185,104 @@ func TestAnomalyDetection(t *testing.T) {
 	}
 }
 
+// seedAnomalyLogs stores n baseline logs from the last few days and n logs
+// from today, built by the given functions.
+func seedAnomalyLogs(t *testing.T, storage *InMemoryStorage, n int, baseline, today func(i int) RequestLog) {
+	t.Helper()
+	now := time.Now()
+	startOfToday := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	for i := 0; i < n; i++ {
+		hist := baseline(i)
+		hist.ID = fmt.Sprintf("log-hist-%d", i)
+		hist.UserID = "user-test"
+		hist.Timestamp = startOfToday.Add(-time.Duration(i+1) * time.Hour)
+		cur := today(i)
+		cur.ID = fmt.Sprintf("log-today-%d", i)
+		cur.UserID = "user-test"
+		cur.Timestamp = now.Add(-time.Duration(i+1) * time.Millisecond)
+		if cur.Timestamp.Before(startOfToday) {
+			cur.Timestamp = startOfToday
+		}
+		for _, log := range []*RequestLog{&hist, &cur} {
+			if err := storage.SaveLog(context.Background(), log); err != nil {
+				t.Fatalf("Failed to save log: %v", err)
+			}
+		}
+	}
+}
+
+func TestAnomalyDetection_Metrics(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    AlertConfig
+		baseline  func(i int) RequestLog
+		today     func(i int) RequestLog
+		wantType  string
+		wantValue float64
+	}{
+		{
+			name:      "latency spike",
+			config:    AlertConfig{LatencyThreshold: 2.0},
+			baseline:  func(i int) RequestLog { return RequestLog{LatencyMs: 100, StatusCode: 200} },
+			today:     func(i int) RequestLog { return RequestLog{LatencyMs: 500, StatusCode: 200} },
+			wantType:  "latency_spike",
+			wantValue: 500,
+		},
+		{
+			name:     "error rate surge from no errors",
+			config:   AlertConfig{ErrorRateThreshold: 3.0},
+			baseline: func(i int) RequestLog { return RequestLog{StatusCode: 200} },
+			today: func(i int) RequestLog {
+				if i%2 == 0 {
+					return RequestLog{StatusCode: 500}
+				}
+				return RequestLog{StatusCode: 200}
+			},
+			wantType:  "error_rate_surge",
+			wantValue: 0.5,
+		},
+		{
+			name:      "tokens per request drop",
+			config:    AlertConfig{TokenDriftThreshold: 2.0},
+			baseline:  func(i int) RequestLog { return RequestLog{TotalTokens: 1000, StatusCode: 200} },
+			today:     func(i int) RequestLog { return RequestLog{TotalTokens: 200, StatusCode: 200} },
+			wantType:  "token_drift",
+			wantValue: 200,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := NewInMemoryStorage()
+			seedAnomalyLogs(t, storage, minAnomalyRequests, tt.baseline, tt.today)
+			config := tt.config
+			config.UserID = "user-test"
+
+			alerts := NewAlertChecker(storage, &config).EvaluateAlerts(context.Background())
+			if len(alerts) != 1 {
+				t.Fatalf("Expected 1 alert, got %d", len(alerts))
+			}
+			if alerts[0].Type != tt.wantType || alerts[0].Observed != tt.wantValue {
+				t.Errorf("alert = %s observed %v, want %s observed %v", alerts[0].Type, alerts[0].Observed, tt.wantType, tt.wantValue)
+			}
+			if alerts[0].Metric == "" || alerts[0].Expected == 0 && tt.wantType != "error_rate_surge" {
+				t.Errorf("alert should name its metric and expected value: %+v", alerts[0])
+			}
+		})
+	}
+}
+
+func TestAnomalyDetection_MetricsNeedHistory(t *testing.T) {
+	storage := NewInMemoryStorage()
+	seedAnomalyLogs(t, storage, minAnomalyRequests-1,
+		func(i int) RequestLog { return RequestLog{LatencyMs: 100, TotalTokens: 1000, StatusCode: 200} },
+		func(i int) RequestLog { return RequestLog{LatencyMs: 900, TotalTokens: 100, StatusCode: 500} })
+	config := &AlertConfig{UserID: "user-test", LatencyThreshold: 2.0, ErrorRateThreshold: 2.0, TokenDriftThreshold: 2.0}
+
+	if alerts := NewAlertChecker(storage, config).EvaluateAlerts(context.Background()); len(alerts) != 0 {
+		t.Errorf("Expected no alerts without enough history, got %d", len(alerts))
+	}
+}
+
 func TestNoAlertsWhenWithinBudget(t *testing.T) {
 	storage := NewInMemoryStorage()
 	ctx := context.Background()
@@ -214,6 +317,88 @@ func TestNoAlertsWhenWithinBudget(t *testing.T) {
 	}
 }
 
+func TestProjectBudgetAlert(t *testing.T) {
+	storage := NewInMemoryStorage()
+	ctx := context.Background()
+
+	now := time.Now()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	// Two users each stay under $100, but the project total does not
+	for i, user := range []string{"user-a", "user-b"} {
+		if err := storage.SaveLog(ctx, &RequestLog{
+			ID:        fmt.Sprintf("log-%d", i),
+			Timestamp: startOfDay,
+			UserID:    user,
+			ProjectID: "proj-acme",
+			CostUSD:   60.0,
+		}); err != nil {
+			t.Fatalf("Failed to save log: %v", err)
+		}
+	}
+	// Spend on another project must not count
+	if err := storage.SaveLog(ctx, &RequestLog{
+		ID:        "log-other",
+		Timestamp: startOfDay,
+		UserID:    "user-a",
+		ProjectID: "proj-other",
+		CostUSD:   500.0,
+	}); err != nil {
+		t.Fatalf("Failed to save log: %v", err)
+	}
+
+	checker := NewAlertChecker(storage, &AlertConfig{
+		UserID:         "user-a",
+		ProjectID:      "proj-acme",
+		DailyBudgetUSD: 100.0,
+	})
+	alerts, err := checker.CheckAlerts(ctx)
+	if err != nil {
+		t.Fatalf("CheckAlerts failed: %v", err)
+	}
+	if len(alerts) != 1 {
+		t.Fatalf("Expected 1 alert, got %d", len(alerts))
+	}
+
+	alert := alerts[0]
+	if alert.Type != "project_exceeded" {
+		t.Errorf("Expected type 'project_exceeded', got '%s'", alert.Type)
+	}
+	if alert.ProjectID != "proj-acme" {
+		t.Errorf("Expected project 'proj-acme', got '%s'", alert.ProjectID)
+	}
+	if alert.CurrentCost != 120.0 {
+		t.Errorf("Expected current cost 120.0, got %.2f", alert.CurrentCost)
+	}
+	if !strings.Contains(alert.Message, "proj-acme") {
+		t.Errorf("Expected message to name the project, got %q", alert.Message)
+	}
+}
+
+func TestLogger_ProjectAlerts(t *testing.T) {
+	storage := NewInMemoryStorage()
+	ctx := context.Background()
+
+	now := time.Now()
+	for _, l := range []*RequestLog{
+		{ID: "log-acme", Timestamp: now, ProjectID: "proj-acme", CostUSD: 150.0},
+		{ID: "log-other", Timestamp: now, ProjectID: "proj-other", CostUSD: 5.0},
+	} {
+		if err := storage.SaveLog(ctx, l); err != nil {
+			t.Fatalf("Failed to save log: %v", err)
+		}
+	}
+
+	logger := NewLogger(storage, nil)
+	alerts := logger.ProjectAlerts(ctx, "proj-acme")
+	if len(alerts) != 1 || alerts[0].Type != "project_exceeded" || alerts[0].ProjectID != "proj-acme" {
+		t.Fatalf("Expected one project_exceeded alert for proj-acme, got %+v", alerts)
+	}
+	if alerts := logger.ProjectAlerts(ctx, "proj-other"); len(alerts) != 0 {
+		t.Errorf("Expected no alerts for proj-other, got %+v", alerts)
+	}
+}
+
 func TestSMTPConfigLoading(t *testing.T) {
 	// Test with no SMTP configuration
 	config := loadSMTPConfigFromEnv()
@@ -332,6 +517,113 @@ func TestEmailNotificationDisabled(t *testing.T) {
 	}
 }
 
+func TestSlackNotification(t *testing.T) {
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("Expected POST, got %s", r.Method)
+		}
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Expected JSON content type, got %s", r.Header.Get("Content-Type"))
+		}
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	checker := &AlertChecker{
+		storage: NewInMemoryStorage(),
+		config: &AlertConfig{
+			UserID:            "user-test",
+			EnableSlackAlerts: true,
+			SlackWebhookURL:   server.URL,
+		},
+	}
+
+	alert := &Alert{
+		ID:          "alert-slack-1",
+		UserID:      "user-test",
+		Type:        "budget_exceeded",
+		Severity:    "critical",
+		Message:     "Monthly budget exceeded: $2500.00 / $2000.00 (125%)",
+		CurrentCost: 2500.0,
+		Threshold:   2000.0,
+		TriggeredAt: time.Now(),
+	}
+	checker.notify(context.Background(), alert)
+
+	if len(body) == 0 {
+		t.Fatal("Slack webhook was not called")
+	}
+
+	var payload struct {
+		Text        string `json:"text"`
+		Attachments []struct {
+			Color  string            `json:"color"`
+			Blocks []json.RawMessage `json:"blocks"`
+		} `json:"attachments"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("Slack payload is not valid JSON: %v", err)
+	}
+
+	raw := string(body)
+	if !strings.Contains(raw, alert.Message) {
+		t.Error("Slack payload missing alert message")
+	}
+	if !strings.Contains(raw, "$2000.00") {
+		t.Error("Slack payload missing threshold")
+	}
+	if len(payload.Attachments) != 1 {
+		t.Fatalf("Expected 1 attachment, got %d", len(payload.Attachments))
+	}
+	if payload.Attachments[0].Color != "#DC3545" {
+		t.Errorf("Expected critical color #DC3545, got %s", payload.Attachments[0].Color)
+	}
+	if len(payload.Attachments[0].Blocks) == 0 {
+		t.Error("Expected Block Kit blocks in attachment")
+	}
+}
+
+func TestSlackNotification_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	checker := &AlertChecker{
+		config: &AlertConfig{EnableSlackAlerts: true, SlackWebhookURL: server.URL},
+	}
+
+	err := checker.sendSlack(&Alert{ID: "a", Severity: "warning", Message: "test"})
+	if err == nil {
+		t.Fatal("Expected error for non-2xx Slack response")
+	}
+}
+
+func TestSlackNotification_EnabledNoURL(t *testing.T) {
+	checker := &AlertChecker{
+		config: &AlertConfig{EnableSlackAlerts: true},
+	}
+
+	// Should log a warning, not panic
+	checker.notify(context.Background(), &Alert{ID: "a", Severity: "info", Message: "test"})
+}
+
+func TestSeverityColor(t *testing.T) {
+	tests := map[string]string{
+		"critical": "#DC3545",
+		"warning":  "#FFA500",
+		"info":     "#17A2B8",
+		"":         "#FFA500",
+	}
+	for severity, want := range tests {
+		if got := severityColor(severity); got != want {
+			t.Errorf("severityColor(%q) = %s, want %s", severity, got, want)
+		}
+	}
+}
+
 // Helper function to check if string contains substring
 func containsString(str, substr string) bool {
 	return len(str) > 0 && len(substr) > 0 &&
@@ -349,3 +641,93 @@ func findInString(str, substr string) bool {
 	}
 	return false
 }
+
+// countingWebhook counts webhook notifications and records whether each
+// was a resolved notice.
+func countingWebhook(t *testing.T) (*httptest.Server, *[]bool) {
+	t.Helper()
+	var resolved []bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Resolved bool `json:"resolved"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		resolved = append(resolved, payload.Resolved)
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server, &resolved
+}
+
+func TestCheckAlerts_Cooldown(t *testing.T) {
+	storage := NewInMemoryStorage()
+	ctx := context.Background()
+	_ = storage.SaveLog(ctx, &RequestLog{ID: "log-1", Timestamp: time.Now().Add(-time.Second), UserID: "user-test", CostUSD: 150.0})
+
+	server, notified := countingWebhook(t)
+	config := &AlertConfig{UserID: "user-test", DailyBudgetUSD: 100.0, EnableWebhookAlerts: true, WebhookURL: server.URL}
+	checker := NewAlertChecker(storage, config)
+
+	for i := 0; i < 2; i++ {
+		alerts, err := checker.CheckAlerts(ctx)
+		if err != nil || len(alerts) != 1 {
+			t.Fatalf("CheckAlerts() = %d alerts, %v; want the budget alert each time", len(alerts), err)
+		}
+	}
+	if len(*notified) != 1 {
+		t.Fatalf("Expected 1 notification across two immediate checks, got %d", len(*notified))
+	}
+
+	// With no cooldown every check notifies
+	config.CooldownMinutes = -1
+	if _, err := checker.CheckAlerts(ctx); err != nil {
+		t.Fatalf("CheckAlerts failed: %v", err)
+	}
+	if len(*notified) != 2 {
+		t.Errorf("Expected a notification without a cooldown, got %d total", len(*notified))
+	}
+}
+
+func TestCheckAlerts_Resolved(t *testing.T) {
+	storage := NewInMemoryStorage()
+	ctx := context.Background()
+	_ = storage.SaveLog(ctx, &RequestLog{ID: "log-1", Timestamp: time.Now().Add(-time.Second), UserID: "user-test", CostUSD: 150.0})
+
+	server, notified := countingWebhook(t)
+	config := &AlertConfig{UserID: "user-test", DailyBudgetUSD: 100.0, EnableWebhookAlerts: true, WebhookURL: server.URL}
+	checker := NewAlertChecker(storage, config)
+	if _, err := checker.CheckAlerts(ctx); err != nil {
+		t.Fatalf("CheckAlerts failed: %v", err)
+	}
+
+	// Raising the budget clears the alert
+	config.DailyBudgetUSD = 200.0
+	for i := 0; i < 2; i++ {
+		if alerts, err := checker.CheckAlerts(ctx); err != nil || len(alerts) != 0 {
+			t.Fatalf("CheckAlerts() = %d alerts, %v; want none", len(alerts), err)
+		}
+	}
+	if len(*notified) != 2 || (*notified)[0] || !(*notified)[1] {
+		t.Errorf("notifications = %v, want the alert then one resolved notice", *notified)
+	}
+}
+
+func TestCheckAlerts_CooldownSurvivesRestart(t *testing.T) {
+	storage, err := NewDatabaseStorage(newTestDB(t))
+	if err != nil {
+		t.Fatalf("NewDatabaseStorage failed: %v", err)
+	}
+	ctx := context.Background()
+	_ = storage.SaveLog(ctx, &RequestLog{ID: "log-1", Timestamp: time.Now().Add(-time.Second), UserID: "user-test", Method: "POST", Path: "/x", CostUSD: 150.0})
+
+	server, notified := countingWebhook(t)
+	config := &AlertConfig{UserID: "user-test", DailyBudgetUSD: 100.0, EnableWebhookAlerts: true, WebhookURL: server.URL}
+	for i := 0; i < 2; i++ {
+		if _, err := NewAlertChecker(storage, config).CheckAlerts(ctx); err != nil {
+			t.Fatalf("CheckAlerts failed: %v", err)
+		}
+	}
+	if len(*notified) != 1 {
+		t.Errorf("Expected 1 notification across two checkers, got %d", len(*notified))
+	}
+}
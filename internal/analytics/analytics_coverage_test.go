@@ -221,7 +221,7 @@ func TestNotify_EmailDisabled(t *testing.T) {
 	}
 
 	// Should not panic
-	checker.notify(alert)
+	checker.notify(context.Background(), alert)
 }
 
 func TestNotify_EmailEnabledNoSMTP(t *testing.T) {
@@ -244,7 +244,7 @@ func TestNotify_EmailEnabledNoSMTP(t *testing.T) {
 	}
 
 	// Should not panic - just log warning
-	checker.notify(alert)
+	checker.notify(context.Background(), alert)
 }
 
 func TestNotify_EmailEnabledNoAddress(t *testing.T) {
@@ -266,7 +266,7 @@ func TestNotify_EmailEnabledNoAddress(t *testing.T) {
 	}
 
 	// Should not panic
-	checker.notify(alert)
+	checker.notify(context.Background(), alert)
 }
 
 func TestNotify_WebhookEnabledNoURL(t *testing.T) {
@@ -288,7 +288,7 @@ func TestNotify_WebhookEnabledNoURL(t *testing.T) {
 	}
 
 	// Should not panic
-	checker.notify(alert)
+	checker.notify(context.Background(), alert)
 }
 
 // ---- Tests for CheckAlerts edge cases ----
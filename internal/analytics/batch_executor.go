@@ -0,0 +1,190 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jordanhubbard/loom/internal/provider"
+)
+
+// batchingProviderTypes lists the provider types trusted to answer a
+// multi-part prompt in the marked format. Others run groups sequentially.
+var batchingProviderTypes = map[string]bool{
+	"openai": true, "azure-openai": true, "anthropic": true, "vllm": true,
+}
+
+// SupportsBatching reports whether providers of the given type can take a
+// group's prompts as one multi-part request.
+func SupportsBatching(providerType string) bool {
+	return batchingProviderTypes[providerType]
+}
+
+// BatchRequest is one request of an auto-batch group: the prompt to send and
+// what the request cost when it was made on its own.
+type BatchRequest struct {
+	ID              string  `json:"id"`
+	Prompt          string  `json:"prompt"`
+	BaselineTokens  int64   `json:"baseline_tokens"`
+	BaselineCostUSD float64 `json:"baseline_cost_usd"`
+}
+
+// BatchResponse is the answer to one request of a group.
+type BatchResponse struct {
+	RequestID string `json:"request_id"`
+	Content   string `json:"content,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// BatchExecution is the outcome of running an auto-batch group. Savings are
+// only realized when the group was batched; a sequential run saves nothing.
+type BatchExecution struct {
+	GroupID         string          `json:"group_id"`
+	Batched         bool            `json:"batched"`
+	Note            string          `json:"note,omitempty"`
+	Responses       []BatchResponse `json:"responses"`
+	BaselineTokens  int64           `json:"baseline_tokens"`
+	TokensUsed      int64           `json:"tokens_used"`
+	TokensSaved     int64           `json:"tokens_saved"`
+	BaselineCostUSD float64         `json:"baseline_cost_usd"`
+	CostUSD         float64         `json:"cost_usd"`
+	CostSavingsUSD  float64         `json:"cost_savings_usd"`
+}
+
+// BatchPlanResult totals the executions of an auto-batch plan.
+type BatchPlanResult struct {
+	Groups           []*BatchExecution `json:"groups"`
+	BatchedGroups    int               `json:"batched_groups"`
+	SequentialGroups int               `json:"sequential_groups"`
+	TokensUsed       int64             `json:"tokens_used"`
+	TokensSaved      int64             `json:"tokens_saved"`
+	CostUSD          float64           `json:"cost_usd"`
+	CostSavingsUSD   float64           `json:"cost_savings_usd"`
+}
+
+// Add counts an execution toward the plan's totals.
+func (r *BatchPlanResult) Add(exec *BatchExecution) {
+	r.Groups = append(r.Groups, exec)
+	if exec.Batched {
+		r.BatchedGroups++
+	} else {
+		r.SequentialGroups++
+	}
+	r.TokensUsed += exec.TokensUsed
+	r.TokensSaved += exec.TokensSaved
+	r.CostUSD += exec.CostUSD
+	r.CostSavingsUSD += exec.CostSavingsUSD
+}
+
+var batchResponseMarker = regexp.MustCompile(`(?m)^=== RESPONSE (\d+) ===[ \t]*$`)
+
+// ChatSender sends a chat completion to a provider. The provider registry
+// implements it, applying its rate limits, circuit breaker and metrics.
+type ChatSender interface {
+	SendChatCompletion(ctx context.Context, providerID string, req *provider.ChatCompletionRequest) (*provider.ChatCompletionResponse, error)
+}
+
+// ExecuteBatchGroup runs a group's requests on p through sender. Providers
+// that support batching get one combined request whose answer is split back
+// out per request; the rest, and any combined answer that cannot be split,
+// run the requests one at a time.
+func ExecuteBatchGroup(ctx context.Context, sender ChatSender, p *provider.RegisteredProvider, groupID string, requests []BatchRequest) *BatchExecution {
+	exec := &BatchExecution{GroupID: groupID}
+	for _, req := range requests {
+		exec.BaselineTokens += req.BaselineTokens
+		exec.BaselineCostUSD += req.BaselineCostUSD
+	}
+	costPerToken := p.Config.CostPerMToken / 1_000_000
+	if costPerToken == 0 && exec.BaselineTokens > 0 {
+		costPerToken = exec.BaselineCostUSD / float64(exec.BaselineTokens)
+	}
+
+	if !SupportsBatching(p.Config.Type) {
+		exec.Note = fmt.Sprintf("provider type %s cannot batch; ran sequentially, no savings possible", p.Config.Type)
+	} else if len(requests) < 2 {
+		exec.Note = "group has fewer than two requests; ran sequentially, no savings possible"
+	} else {
+		resp, err := sender.SendChatCompletion(ctx, p.Config.ID, &provider.ChatCompletionRequest{
+			Model:    p.Config.Model,
+			Messages: []provider.ChatMessage{{Role: "user", Content: combinePrompts(requests)}},
+		})
+		if err == nil {
+			exec.TokensUsed += int64(resp.Usage.TotalTokens)
+			if parts, ok := splitBatchResponse(resp, len(requests)); ok {
+				exec.Batched = true
+				for i, req := range requests {
+					exec.Responses = append(exec.Responses, BatchResponse{RequestID: req.ID, Content: parts[i]})
+				}
+				exec.TokensSaved = exec.BaselineTokens - exec.TokensUsed
+				exec.CostUSD = float64(exec.TokensUsed) * costPerToken
+				exec.CostSavingsUSD = exec.BaselineCostUSD - exec.CostUSD
+				return exec
+			}
+			exec.Note = "combined response could not be split; ran sequentially, no savings realized"
+		} else {
+			exec.Note = fmt.Sprintf("combined request failed (%v); ran sequentially, no savings realized", err)
+		}
+	}
+
+	for _, req := range requests {
+		resp, err := sender.SendChatCompletion(ctx, p.Config.ID, &provider.ChatCompletionRequest{
+			Model:    p.Config.Model,
+			Messages: []provider.ChatMessage{{Role: "user", Content: req.Prompt}},
+		})
+		if err != nil {
+			exec.Responses = append(exec.Responses, BatchResponse{RequestID: req.ID, Error: err.Error()})
+			continue
+		}
+		exec.TokensUsed += int64(resp.Usage.TotalTokens)
+		content := ""
+		if len(resp.Choices) > 0 {
+			content = resp.Choices[0].Message.Content
+		}
+		exec.Responses = append(exec.Responses, BatchResponse{RequestID: req.ID, Content: content})
+	}
+	exec.CostUSD = float64(exec.TokensUsed) * costPerToken
+	return exec
+}
+
+// combinePrompts builds one prompt that asks for each request's answer
+// under its own numbered marker.
+func combinePrompts(requests []BatchRequest) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Answer each of the following %d requests independently. "+
+		"Start the answer to request N with a line containing only \"=== RESPONSE N ===\" "+
+		"and write nothing outside the answers.\n", len(requests))
+	for i, req := range requests {
+		fmt.Fprintf(&b, "\n=== REQUEST %d ===\n%s\n", i+1, req.Prompt)
+	}
+	return b.String()
+}
+
+// splitBatchResponse splits a combined answer at its markers. It fails
+// unless each of the n responses appears exactly once.
+func splitBatchResponse(resp *provider.ChatCompletionResponse, n int) ([]string, bool) {
+	if len(resp.Choices) == 0 {
+		return nil, false
+	}
+	content := resp.Choices[0].Message.Content
+	markers := batchResponseMarker.FindAllStringSubmatchIndex(content, -1)
+	if len(markers) != n {
+		return nil, false
+	}
+	parts := make([]string, n)
+	seen := make([]bool, n)
+	for i, m := range markers {
+		idx, err := strconv.Atoi(content[m[2]:m[3]])
+		if err != nil || idx < 1 || idx > n || seen[idx-1] {
+			return nil, false
+		}
+		seen[idx-1] = true
+		end := len(content)
+		if i+1 < len(markers) {
+			end = markers[i+1][0]
+		}
+		parts[idx-1] = strings.TrimSpace(content[m[1]:end])
+	}
+	return parts, true
+}
@@ -0,0 +1,114 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jordanhubbard/loom/internal/provider"
+)
+
+// batchTestSender answers combined prompts with one marked response per
+// request, or echoes a single prompt.
+type batchTestSender struct {
+	calls    int
+	tokens   int
+	noMarker bool
+	fail     bool
+}
+
+func (p *batchTestSender) SendChatCompletion(ctx context.Context, providerID string, req *provider.ChatCompletionRequest) (*provider.ChatCompletionResponse, error) {
+	p.calls++
+	if providerID != "p1" {
+		return nil, fmt.Errorf("sent to provider %q, want p1", providerID)
+	}
+	prompt := req.Messages[len(req.Messages)-1].Content
+	if p.fail && strings.Contains(prompt, "=== REQUEST") {
+		return nil, errors.New("boom")
+	}
+	content := "answer: " + prompt
+	if n := strings.Count(prompt, "=== REQUEST "); n > 0 && !p.noMarker {
+		var b strings.Builder
+		for i := n; i >= 1; i-- {
+			fmt.Fprintf(&b, "=== RESPONSE %d ===\nanswer %d\n", i, i)
+		}
+		content = b.String()
+	}
+	resp := &provider.ChatCompletionResponse{}
+	resp.Choices = append(resp.Choices, struct {
+		Index   int                  `json:"index"`
+		Message provider.ChatMessage `json:"message"`
+		Finish  string               `json:"finish_reason"`
+	}{Message: provider.ChatMessage{Role: "assistant", Content: content}, Finish: "stop"})
+	resp.Usage.TotalTokens = p.tokens
+	return resp, nil
+}
+
+func batchTestRequests() []BatchRequest {
+	return []BatchRequest{
+		{ID: "r1", Prompt: "first", BaselineTokens: 100, BaselineCostUSD: 0.10},
+		{ID: "r2", Prompt: "second", BaselineTokens: 100, BaselineCostUSD: 0.10},
+		{ID: "r3", Prompt: "third", BaselineTokens: 100, BaselineCostUSD: 0.10},
+	}
+}
+
+func TestExecuteBatchGroup_Batched(t *testing.T) {
+	proto := &batchTestSender{tokens: 240}
+	p := &provider.RegisteredProvider{Config: &provider.ProviderConfig{ID: "p1", Type: "openai", Model: "m"}}
+
+	exec := ExecuteBatchGroup(context.Background(), proto, p, "g1", batchTestRequests())
+	if !exec.Batched || proto.calls != 1 {
+		t.Fatalf("Batched = %v after %d calls, want one combined call", exec.Batched, proto.calls)
+	}
+	for i, resp := range exec.Responses {
+		if want := fmt.Sprintf("answer %d", i+1); resp.Content != want {
+			t.Errorf("response %s = %q, want %q", resp.RequestID, resp.Content, want)
+		}
+	}
+	if exec.TokensUsed != 240 || exec.TokensSaved != 60 {
+		t.Errorf("TokensUsed = %d, TokensSaved = %d, want 240 and 60", exec.TokensUsed, exec.TokensSaved)
+	}
+	if got := exec.CostSavingsUSD; got < 0.0599 || got > 0.0601 {
+		t.Errorf("CostSavingsUSD = %v, want 0.06 at the logged cost per token", got)
+	}
+}
+
+func TestExecuteBatchGroup_Sequential(t *testing.T) {
+	tests := []struct {
+		name      string
+		typ       string
+		proto     *batchTestSender
+		wantCalls int
+	}{
+		{"cannot batch", "ollama", &batchTestSender{tokens: 90}, 3},
+		{"unsplittable", "openai", &batchTestSender{tokens: 90, noMarker: true}, 4},
+		{"combined fails", "openai", &batchTestSender{tokens: 90, fail: true}, 4},
+	}
+	for _, tt := range tests {
+		p := &provider.RegisteredProvider{Config: &provider.ProviderConfig{ID: "p1", Type: tt.typ, Model: "m"}}
+		exec := ExecuteBatchGroup(context.Background(), tt.proto, p, "g1", batchTestRequests())
+		if exec.Batched || tt.proto.calls != tt.wantCalls {
+			t.Errorf("%s: Batched = %v after %d calls, want sequential after %d", tt.name, exec.Batched, tt.proto.calls, tt.wantCalls)
+		}
+		if exec.TokensSaved != 0 || exec.CostSavingsUSD != 0 || exec.Note == "" {
+			t.Errorf("%s: saved %d tokens, $%v, note %q; want no savings and a note", tt.name, exec.TokensSaved, exec.CostSavingsUSD, exec.Note)
+		}
+		if len(exec.Responses) != 3 || exec.Responses[1].Content != "answer: second" {
+			t.Errorf("%s: responses = %+v, want one per request", tt.name, exec.Responses)
+		}
+	}
+}
+
+func TestBatchPlanResult_Add(t *testing.T) {
+	result := &BatchPlanResult{}
+	result.Add(&BatchExecution{Batched: true, TokensUsed: 200, TokensSaved: 100, CostSavingsUSD: 0.1})
+	result.Add(&BatchExecution{TokensUsed: 300})
+	if result.BatchedGroups != 1 || result.SequentialGroups != 1 {
+		t.Errorf("groups = %d batched, %d sequential, want 1 and 1", result.BatchedGroups, result.SequentialGroups)
+	}
+	if result.TokensUsed != 500 || result.TokensSaved != 100 || result.CostSavingsUSD != 0.1 {
+		t.Errorf("totals = %+v", result)
+	}
+}
@@ -2,6 +2,8 @@ package analytics
 
 import (
 	"context"
+	"fmt"
+	"sort"
 	"testing"
 	"time"
 )
@@ -248,6 +250,59 @@ func TestCostTrackingTimeRangeFiltering(t *testing.T) {
 	}
 }
 
+func TestLatencyPercentiles(t *testing.T) {
+	storage := NewInMemoryStorage()
+	ctx := context.Background()
+
+	// Latencies 1..100ms inserted in reverse order to exercise sorting
+	for i := 100; i >= 1; i-- {
+		_ = storage.SaveLog(ctx, &RequestLog{
+			ID:        fmt.Sprintf("log-%d", i),
+			Timestamp: time.Now(),
+			UserID:    "user-1",
+			LatencyMs: int64(i),
+		})
+	}
+
+	stats, err := storage.GetLogStats(ctx, &LogFilter{})
+	if err != nil {
+		t.Fatalf("GetLogStats failed: %v", err)
+	}
+
+	if stats.P50LatencyMs != 50 {
+		t.Errorf("Expected p50 50ms, got %.1f", stats.P50LatencyMs)
+	}
+	if stats.P95LatencyMs != 95 {
+		t.Errorf("Expected p95 95ms, got %.1f", stats.P95LatencyMs)
+	}
+	if stats.P99LatencyMs != 99 {
+		t.Errorf("Expected p99 99ms, got %.1f", stats.P99LatencyMs)
+	}
+}
+
+func TestLatencyPercentiles_Empty(t *testing.T) {
+	storage := NewInMemoryStorage()
+
+	stats, err := storage.GetLogStats(context.Background(), &LogFilter{})
+	if err != nil {
+		t.Fatalf("GetLogStats failed: %v", err)
+	}
+
+	if stats.P50LatencyMs != 0 || stats.P95LatencyMs != 0 || stats.P99LatencyMs != 0 {
+		t.Errorf("Expected zero percentiles for empty logs, got %.1f/%.1f/%.1f",
+			stats.P50LatencyMs, stats.P95LatencyMs, stats.P99LatencyMs)
+	}
+}
+
+func TestPercentile_SingleValue(t *testing.T) {
+	sorted := []int64{42}
+	for _, p := range []float64{50, 95, 99} {
+		if got := percentile(sorted, p); got != 42 {
+			t.Errorf("percentile(%v) = %.1f, want 42", p, got)
+		}
+	}
+}
+
 // InMemoryStorage is a simple in-memory implementation for testing
 type InMemoryStorage struct {
 	logs []*RequestLog
@@ -270,6 +325,12 @@ func (s *InMemoryStorage) GetLogs(ctx context.Context, filter *LogFilter) ([]*Re
 		if filter.UserID != "" && log.UserID != filter.UserID {
 			continue
 		}
+		if filter.ProjectID != "" && log.ProjectID != filter.ProjectID {
+			continue
+		}
+		if filter.TraceID != "" && log.TraceID != filter.TraceID {
+			continue
+		}
 		if filter.ProviderID != "" && log.ProviderID != filter.ProviderID {
 			continue
 		}
@@ -281,11 +342,36 @@ func (s *InMemoryStorage) GetLogs(ctx context.Context, filter *LogFilter) ([]*Re
 		}
 		filtered = append(filtered, log)
 	}
+
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return filtered[i].Timestamp.After(filtered[j].Timestamp)
+	})
+	if filter.Offset > 0 {
+		if filter.Offset >= len(filtered) {
+			return []*RequestLog{}, nil
+		}
+		filtered = filtered[filter.Offset:]
+	}
+	if filter.Limit > 0 && filter.Limit < len(filtered) {
+		filtered = filtered[:filter.Limit]
+	}
 	return filtered, nil
 }
 
+func (s *InMemoryStorage) CountLogs(ctx context.Context, filter *LogFilter) (int64, error) {
+	unpaged := *filter
+	unpaged.Limit, unpaged.Offset = 0, 0
+	logs, err := s.GetLogs(ctx, &unpaged)
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(logs)), nil
+}
+
 func (s *InMemoryStorage) GetLogStats(ctx context.Context, filter *LogFilter) (*LogStats, error) {
-	logs, err := s.GetLogs(ctx, filter)
+	unpaged := *filter
+	unpaged.Limit, unpaged.Offset = 0, 0
+	logs, err := s.GetLogs(ctx, &unpaged)
 	if err != nil {
 		return nil, err
 	}
@@ -295,16 +381,21 @@ func (s *InMemoryStorage) GetLogStats(ctx context.Context, filter *LogFilter) (*
 		RequestsByProvider: make(map[string]int64),
 		CostByProvider:     make(map[string]float64),
 		CostByUser:         make(map[string]float64),
+		CostByProject:      make(map[string]float64),
+		CostByModel:        make(map[string]float64),
+		TokensByModel:      make(map[string]int64),
 	}
 
 	var totalLatency int64
 	var errorCount int64
+	latencies := make([]int64, 0, len(logs))
 
 	for _, log := range logs {
 		stats.TotalRequests++
 		stats.TotalTokens += log.TotalTokens
 		stats.TotalCostUSD += log.CostUSD
 		totalLatency += log.LatencyMs
+		latencies = append(latencies, log.LatencyMs)
 
 		if log.StatusCode >= 400 {
 			errorCount++
@@ -315,6 +406,15 @@ func (s *InMemoryStorage) GetLogStats(ctx context.Context, filter *LogFilter) (*
 			stats.CostByUser[log.UserID] += log.CostUSD
 		}
 
+		if log.ProjectID != "" {
+			stats.CostByProject[log.ProjectID] += log.CostUSD
+		}
+
+		if log.ModelName != "" {
+			stats.CostByModel[log.ModelName] += log.CostUSD
+			stats.TokensByModel[log.ModelName] += log.TotalTokens
+		}
+
 		if log.ProviderID != "" {
 			stats.RequestsByProvider[log.ProviderID]++
 			stats.CostByProvider[log.ProviderID] += log.CostUSD
@@ -326,6 +426,9 @@ func (s *InMemoryStorage) GetLogStats(ctx context.Context, filter *LogFilter) (*
 		stats.ErrorRate = float64(errorCount) / float64(stats.TotalRequests)
 	}
 
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	stats.setLatencyPercentiles(latencies)
+
 	return stats, nil
 }
 
@@ -0,0 +1,167 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// minTrendDays is the fewest days of history needed to fit a linear trend.
+// With less data the forecast falls back to a flat run-rate projection.
+const minTrendDays = 3
+
+// maxForecastDays caps the days of history a forecast reads.
+const maxForecastDays = 90
+
+// CostForecast is a projection of spend through the end of the current month
+type CostForecast struct {
+	ProjectedUSD   float64   `json:"projected_usd"`   // Projected total spend for the month
+	LowerBoundUSD  float64   `json:"lower_bound_usd"` // ~95% confidence band
+	UpperBoundUSD  float64   `json:"upper_bound_usd"`
+	MonthToDateUSD float64   `json:"month_to_date_usd"`
+	DailyRateUSD   float64   `json:"daily_rate_usd"` // Projected spend per day for the rest of the month
+	DaysOfHistory  int       `json:"days_of_history"`
+	Method         string    `json:"method"` // "linear" or "flat"
+	PeriodEnd      time.Time `json:"period_end"`
+	GeneratedAt    time.Time `json:"generated_at"`
+}
+
+// ForecastCost projects end-of-month spend from the daily cost of the last
+// horizon days, at most maxForecastDays. The filter's user, project and
+// provider scope the data; its time range and paging are ignored.
+func (l *Logger) ForecastCost(ctx context.Context, filter *LogFilter, horizon int) (*CostForecast, error) {
+	if horizon <= 0 {
+		return nil, fmt.Errorf("horizon must be positive, got %d", horizon)
+	}
+	if horizon > maxForecastDays {
+		horizon = maxForecastDays
+	}
+
+	now := time.Now()
+	startOfToday := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	startOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	historyStart := startOfToday.AddDate(0, 0, -horizon)
+
+	scope := LogFilter{}
+	if filter != nil {
+		scope.UserID = filter.UserID
+		scope.ProjectID = filter.ProjectID
+		scope.ProviderID = filter.ProviderID
+	}
+
+	// Completed days only; today's partial spend would drag the trend down.
+	// Each day is summed by the storage rather than loading its logs.
+	daily := make([]float64, horizon)
+	for day := range daily {
+		dayFilter := scope
+		dayFilter.StartTime = historyStart.AddDate(0, 0, day)
+		dayFilter.EndTime = historyStart.AddDate(0, 0, day+1).Add(-time.Nanosecond)
+		stats, err := l.storage.GetLogStats(ctx, &dayFilter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load cost history: %w", err)
+		}
+		daily[day] = stats.TotalCostUSD
+	}
+
+	monthFilter := scope
+	monthFilter.StartTime = startOfMonth
+	monthFilter.EndTime = now
+	monthStats, err := l.storage.GetLogStats(ctx, &monthFilter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load month-to-date cost: %w", err)
+	}
+
+	// History starts at the first day with any spend
+	first := 0
+	for first < len(daily) && daily[first] == 0 {
+		first++
+	}
+
+	return buildForecast(daily[first:], monthStats.TotalCostUSD, now), nil
+}
+
+// buildForecast projects the rest of the month from daily costs (oldest
+// first, ending yesterday) and the spend so far this month
+func buildForecast(daily []float64, monthToDate float64, now time.Time) *CostForecast {
+	startOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	periodEnd := startOfMonth.AddDate(0, 1, 0)
+	remainingDays := periodEnd.Sub(now).Hours() / 24
+
+	forecast := &CostForecast{
+		MonthToDateUSD: monthToDate,
+		DaysOfHistory:  len(daily),
+		PeriodEnd:      periodEnd,
+		GeneratedAt:    now,
+	}
+
+	n := len(daily)
+	var rate, stdErr float64
+	if n >= minTrendDays {
+		forecast.Method = "linear"
+		intercept, slope := linearFit(daily)
+		// Average predicted rate over the remaining days (x = n is today)
+		rate = intercept + slope*(float64(n)+remainingDays/2)
+		stdErr = residualStdErr(daily, intercept, slope)
+	} else {
+		forecast.Method = "flat"
+		if n > 0 {
+			rate = sumCosts(daily) / float64(n)
+		} else if elapsed := now.Sub(startOfMonth).Hours() / 24; elapsed > 0 {
+			// No completed days yet; use this month's run-rate so far
+			rate = monthToDate / elapsed
+		}
+	}
+	rate = math.Max(rate, 0)
+
+	forecast.DailyRateUSD = rate
+	forecast.ProjectedUSD = monthToDate + rate*remainingDays
+
+	// Daily errors add in quadrature over the remaining period
+	band := 1.96 * stdErr * math.Sqrt(remainingDays)
+	forecast.LowerBoundUSD = math.Max(forecast.ProjectedUSD-band, monthToDate)
+	forecast.UpperBoundUSD = forecast.ProjectedUSD + band
+
+	return forecast
+}
+
+// linearFit returns the least-squares intercept and slope of ys against x = 0..n-1
+func linearFit(ys []float64) (intercept, slope float64) {
+	n := float64(len(ys))
+	var sumX, sumY, sumXY, sumXX float64
+	for i, y := range ys {
+		x := float64(i)
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+	denom := n*sumXX - sumX*sumX
+	if denom == 0 {
+		return sumY / n, 0
+	}
+	slope = (n*sumXY - sumX*sumY) / denom
+	intercept = (sumY - slope*sumX) / n
+	return intercept, slope
+}
+
+// residualStdErr is the standard error of a linear fit's residuals
+func residualStdErr(ys []float64, intercept, slope float64) float64 {
+	if len(ys) <= 2 {
+		return 0
+	}
+	var ss float64
+	for i, y := range ys {
+		r := y - (intercept + slope*float64(i))
+		ss += r * r
+	}
+	return math.Sqrt(ss / float64(len(ys)-2))
+}
+
+func sumCosts(values []float64) float64 {
+	var total float64
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
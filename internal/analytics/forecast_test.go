@@ -0,0 +1,165 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBuildForecast_LinearTrend(t *testing.T) {
+	// Mid-month, with spend growing by $1/day over the last 10 days
+	now := time.Date(2026, time.January, 16, 0, 0, 0, 0, time.UTC)
+	daily := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+	f := buildForecast(daily, 100, now)
+
+	if f.Method != "linear" {
+		t.Fatalf("Method = %q, want linear", f.Method)
+	}
+	if f.DaysOfHistory != 10 {
+		t.Errorf("DaysOfHistory = %d, want 10", f.DaysOfHistory)
+	}
+	// 16 days remain; the trend continues at x = 10..25, averaging x = 18 -> $19/day
+	if math.Abs(f.DailyRateUSD-19) > 1e-9 {
+		t.Errorf("DailyRateUSD = %f, want 19", f.DailyRateUSD)
+	}
+	if math.Abs(f.ProjectedUSD-(100+19*16)) > 1e-9 {
+		t.Errorf("ProjectedUSD = %f, want %f", f.ProjectedUSD, float64(100+19*16))
+	}
+	// A perfect fit has no confidence band
+	if f.LowerBoundUSD != f.ProjectedUSD || f.UpperBoundUSD != f.ProjectedUSD {
+		t.Errorf("band = [%f, %f], want collapsed on %f", f.LowerBoundUSD, f.UpperBoundUSD, f.ProjectedUSD)
+	}
+	if !f.PeriodEnd.Equal(time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("PeriodEnd = %v, want start of February", f.PeriodEnd)
+	}
+}
+
+func TestBuildForecast_ConfidenceBand(t *testing.T) {
+	now := time.Date(2026, time.January, 16, 0, 0, 0, 0, time.UTC)
+	f := buildForecast([]float64{2, 8, 4, 10, 6, 12}, 50, now)
+
+	if f.UpperBoundUSD <= f.ProjectedUSD {
+		t.Errorf("UpperBoundUSD = %f, want above projection %f", f.UpperBoundUSD, f.ProjectedUSD)
+	}
+	if f.LowerBoundUSD >= f.ProjectedUSD {
+		t.Errorf("LowerBoundUSD = %f, want below projection %f", f.LowerBoundUSD, f.ProjectedUSD)
+	}
+	if f.LowerBoundUSD < f.MonthToDateUSD {
+		t.Errorf("LowerBoundUSD = %f, should never drop below month-to-date %f", f.LowerBoundUSD, f.MonthToDateUSD)
+	}
+}
+
+func TestBuildForecast_SparseDataIsFlat(t *testing.T) {
+	now := time.Date(2026, time.January, 21, 0, 0, 0, 0, time.UTC)
+	f := buildForecast([]float64{4, 6}, 10, now)
+
+	if f.Method != "flat" {
+		t.Fatalf("Method = %q, want flat", f.Method)
+	}
+	if f.DailyRateUSD != 5 {
+		t.Errorf("DailyRateUSD = %f, want 5", f.DailyRateUSD)
+	}
+	// 11 days remain in January
+	if f.ProjectedUSD != 10+5*11 {
+		t.Errorf("ProjectedUSD = %f, want %d", f.ProjectedUSD, 10+5*11)
+	}
+}
+
+func TestBuildForecast_NoHistoryUsesMonthRunRate(t *testing.T) {
+	now := time.Date(2026, time.January, 11, 0, 0, 0, 0, time.UTC)
+	f := buildForecast(nil, 20, now)
+
+	if f.Method != "flat" || f.DaysOfHistory != 0 {
+		t.Fatalf("Method/DaysOfHistory = %q/%d, want flat/0", f.Method, f.DaysOfHistory)
+	}
+	// $20 over 10 elapsed days, 21 days remaining
+	if math.Abs(f.ProjectedUSD-62) > 1e-9 {
+		t.Errorf("ProjectedUSD = %f, want 62", f.ProjectedUSD)
+	}
+}
+
+func TestBuildForecast_NegativeTrendClampsToZero(t *testing.T) {
+	now := time.Date(2026, time.January, 16, 0, 0, 0, 0, time.UTC)
+	f := buildForecast([]float64{30, 20, 10, 0.5}, 60, now)
+
+	if f.DailyRateUSD != 0 {
+		t.Errorf("DailyRateUSD = %f, want 0 for a declining trend", f.DailyRateUSD)
+	}
+	if f.ProjectedUSD != 60 {
+		t.Errorf("ProjectedUSD = %f, want month-to-date 60", f.ProjectedUSD)
+	}
+}
+
+func TestLoggerForecastCost(t *testing.T) {
+	storage := NewInMemoryStorage()
+	logger := NewLogger(storage, DefaultPrivacyConfig())
+	ctx := context.Background()
+
+	now := time.Now()
+	startOfToday := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	for i := 1; i <= 5; i++ {
+		_ = storage.SaveLog(ctx, &RequestLog{
+			ID:        fmt.Sprintf("day-%d", i),
+			Timestamp: startOfToday.AddDate(0, 0, -i).Add(time.Hour),
+			UserID:    "user-a",
+			CostUSD:   2.0,
+		})
+	}
+	// Other users are out of scope
+	_ = storage.SaveLog(ctx, &RequestLog{
+		ID:        "other",
+		Timestamp: startOfToday.AddDate(0, 0, -1).Add(time.Hour),
+		UserID:    "user-b",
+		CostUSD:   100.0,
+	})
+
+	f, err := logger.ForecastCost(ctx, &LogFilter{UserID: "user-a"}, 7)
+	if err != nil {
+		t.Fatalf("ForecastCost failed: %v", err)
+	}
+	if f.DaysOfHistory != 5 {
+		t.Errorf("DaysOfHistory = %d, want 5", f.DaysOfHistory)
+	}
+	if f.Method != "linear" {
+		t.Errorf("Method = %q, want linear", f.Method)
+	}
+	if math.Abs(f.DailyRateUSD-2.0) > 1e-9 {
+		t.Errorf("DailyRateUSD = %f, want 2.0", f.DailyRateUSD)
+	}
+}
+
+func TestLoggerForecastCost_CapsHorizon(t *testing.T) {
+	storage := NewInMemoryStorage()
+	logger := NewLogger(storage, DefaultPrivacyConfig())
+	ctx := context.Background()
+
+	now := time.Now()
+	startOfToday := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	for _, daysAgo := range []int{maxForecastDays + 10, 1} {
+		_ = storage.SaveLog(ctx, &RequestLog{
+			ID:        fmt.Sprintf("day-%d", daysAgo),
+			Timestamp: startOfToday.AddDate(0, 0, -daysAgo).Add(time.Hour),
+			CostUSD:   1.0,
+		})
+	}
+
+	f, err := logger.ForecastCost(ctx, &LogFilter{}, 1000)
+	if err != nil {
+		t.Fatalf("ForecastCost failed: %v", err)
+	}
+	if f.DaysOfHistory != 1 {
+		t.Errorf("DaysOfHistory = %d, want 1 with history older than %d days ignored", f.DaysOfHistory, maxForecastDays)
+	}
+}
+
+func TestLoggerForecastCost_InvalidHorizon(t *testing.T) {
+	logger := NewLogger(NewInMemoryStorage(), nil)
+	for _, horizon := range []int{0, -3} {
+		if _, err := logger.ForecastCost(context.Background(), &LogFilter{}, horizon); err == nil {
+			t.Errorf("ForecastCost(horizon=%d) expected error", horizon)
+		}
+	}
+}
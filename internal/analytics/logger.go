@@ -4,8 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"regexp"
 	"time"
+
+	"github.com/jordanhubbard/loom/internal/metrics"
+	"github.com/jordanhubbard/loom/internal/observability"
 )
 
 // RequestLog represents a logged API request
@@ -13,6 +17,8 @@ type RequestLog struct {
 	ID               string            `json:"id"`
 	Timestamp        time.Time         `json:"timestamp"`
 	UserID           string            `json:"user_id"`
+	ProjectID        string            `json:"project_id,omitempty"`
+	TraceID          string            `json:"trace_id,omitempty"` // Correlates the request with its dispatch and API call
 	Method           string            `json:"method"`
 	Path             string            `json:"path"`
 	ProviderID       string            `json:"provider_id"`
@@ -34,6 +40,7 @@ type PrivacyConfig struct {
 	LogRequestBodies  bool     // Log full request bodies
 	LogResponseBodies bool     // Log full response bodies
 	RedactPatterns    []string // Regex patterns to redact (emails, tokens, etc.)
+	RedactJSONPaths   []string // JSON paths to redact in JSON bodies (e.g. $.messages[*].content)
 	MaxBodyLength     int      // Max length of logged bodies (0 = unlimited)
 }
 
@@ -60,6 +67,7 @@ func DefaultPrivacyConfig() *PrivacyConfig {
 type Logger struct {
 	storage Storage
 	privacy *PrivacyConfig
+	metrics *metrics.Metrics // Exports logged cost to Prometheus; nil disables
 }
 
 // Storage interface for persisting logs
@@ -70,10 +78,19 @@ type Storage interface {
 	DeleteOldLogs(ctx context.Context, before time.Time) (int64, error)
 }
 
-// LogFilter for querying logs
+// LogCounter is implemented by storage that can count matching logs without
+// loading them
+type LogCounter interface {
+	CountLogs(ctx context.Context, filter *LogFilter) (int64, error)
+}
+
+// LogFilter for querying logs. Logs are returned newest first; Limit and
+// Offset select a page of that ordering (Limit 0 means no limit).
 type LogFilter struct {
 	UserID     string
+	ProjectID  string
 	ProviderID string
+	TraceID    string
 	StartTime  time.Time
 	EndTime    time.Time
 	Limit      int
@@ -86,13 +103,19 @@ type LogStats struct {
 	TotalTokens        int64              `json:"total_tokens"`
 	TotalCostUSD       float64            `json:"total_cost_usd"`
 	AvgLatencyMs       float64            `json:"avg_latency_ms"`
+	P50LatencyMs       float64            `json:"p50_latency_ms"`
+	P95LatencyMs       float64            `json:"p95_latency_ms"`
+	P99LatencyMs       float64            `json:"p99_latency_ms"`
 	ErrorRate          float64            `json:"error_rate"`
 	RequestsByUser     map[string]int64   `json:"requests_by_user"`
 	RequestsByProvider map[string]int64   `json:"requests_by_provider"`
 	CostByProvider     map[string]float64 `json:"cost_by_provider"`
 	CostByUser         map[string]float64 `json:"cost_by_user"`
+	CostByProject      map[string]float64 `json:"cost_by_project"`
+	CostByModel        map[string]float64 `json:"cost_by_model"`
 	TokensByProvider   map[string]int64   `json:"tokens_by_provider"`
 	TokensByUser       map[string]int64   `json:"tokens_by_user"`
+	TokensByModel      map[string]int64   `json:"tokens_by_model"`
 	LatencyByProvider  map[string]float64 `json:"latency_by_provider"`
 }
 
@@ -109,38 +132,73 @@ func NewLogger(storage Storage, privacy *PrivacyConfig) *Logger {
 
 // LogRequest logs an API request with privacy controls
 func (l *Logger) LogRequest(ctx context.Context, log *RequestLog) error {
-	// Apply privacy filters
+	l.applyPrivacy(log)
+
+	// Generate ID if not provided
+	if log.ID == "" {
+		log.ID = generateLogID()
+	}
+
+	// Set timestamp if not provided
+	if log.Timestamp.IsZero() {
+		log.Timestamp = time.Now()
+	}
+
+	if log.TraceID == "" {
+		log.TraceID = observability.TraceID(ctx)
+	}
+
+	if err := l.storage.SaveLog(ctx, log); err != nil {
+		return err
+	}
+	if l.metrics != nil {
+		l.metrics.RecordCost(log.ProviderID, log.ProjectID, log.CostUSD)
+	}
+	return nil
+}
+
+// SetMetrics exports the cost of each logged request to Prometheus
+func (l *Logger) SetMetrics(m *metrics.Metrics) {
+	l.metrics = m
+}
+
+// RedactForExport returns copies of the logs with the privacy config applied,
+// so exports never carry data the logger would not have stored
+func (l *Logger) RedactForExport(logs []*RequestLog) []*RequestLog {
+	redacted := make([]*RequestLog, 0, len(logs))
+	for _, log := range logs {
+		copied := *log
+		l.applyPrivacy(&copied)
+		redacted = append(redacted, &copied)
+	}
+	return redacted
+}
+
+// applyPrivacy drops, redacts and truncates request/response bodies.
+// Redaction runs before truncation so JSON bodies are still parseable.
+func (l *Logger) applyPrivacy(log *RequestLog) {
 	if !l.privacy.LogRequestBodies {
 		log.RequestBody = "" // Don't log request bodies
-	} else if l.privacy.MaxBodyLength > 0 && len(log.RequestBody) > l.privacy.MaxBodyLength {
-		log.RequestBody = log.RequestBody[:l.privacy.MaxBodyLength] + "... [truncated]"
 	}
-
 	if !l.privacy.LogResponseBodies {
 		log.ResponseBody = "" // Don't log response bodies
-	} else if l.privacy.MaxBodyLength > 0 && len(log.ResponseBody) > l.privacy.MaxBodyLength {
-		log.ResponseBody = log.ResponseBody[:l.privacy.MaxBodyLength] + "... [truncated]"
 	}
 
-	// Redact sensitive patterns
+	// Redact sensitive fields and patterns
 	if log.RequestBody != "" {
-		log.RequestBody = l.redactSensitiveData(log.RequestBody)
+		log.RequestBody = l.truncateBody(l.redactSensitiveData(log.RequestBody))
 	}
 	if log.ResponseBody != "" {
-		log.ResponseBody = l.redactSensitiveData(log.ResponseBody)
-	}
-
-	// Generate ID if not provided
-	if log.ID == "" {
-		log.ID = generateLogID()
+		log.ResponseBody = l.truncateBody(l.redactSensitiveData(log.ResponseBody))
 	}
+}
 
-	// Set timestamp if not provided
-	if log.Timestamp.IsZero() {
-		log.Timestamp = time.Now()
+// truncateBody cuts a body to MaxBodyLength
+func (l *Logger) truncateBody(body string) string {
+	if l.privacy.MaxBodyLength > 0 && len(body) > l.privacy.MaxBodyLength {
+		return body[:l.privacy.MaxBodyLength] + "... [truncated]"
 	}
-
-	return l.storage.SaveLog(ctx, log)
+	return body
 }
 
 // GetLogs retrieves logs with filtering
@@ -148,18 +206,53 @@ func (l *Logger) GetLogs(ctx context.Context, filter *LogFilter) ([]*RequestLog,
 	return l.storage.GetLogs(ctx, filter)
 }
 
+// GetLogsPage retrieves one page of logs and the total number of logs that
+// match the filter across all pages
+func (l *Logger) GetLogsPage(ctx context.Context, filter *LogFilter) ([]*RequestLog, int64, error) {
+	logs, err := l.storage.GetLogs(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if counter, ok := l.storage.(LogCounter); ok {
+		total, err := counter.CountLogs(ctx, filter)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to count logs: %w", err)
+		}
+		return logs, total, nil
+	}
+
+	stats, err := l.storage.GetLogStats(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count logs: %w", err)
+	}
+	return logs, stats.TotalRequests, nil
+}
+
 // GetStats retrieves aggregate statistics
 func (l *Logger) GetStats(ctx context.Context, filter *LogFilter) (*LogStats, error) {
 	return l.storage.GetLogStats(ctx, filter)
 }
 
+// ProjectAlerts evaluates the default alert thresholds against a project's
+// spend. No notifications are sent.
+func (l *Logger) ProjectAlerts(ctx context.Context, projectID string) []*Alert {
+	config := DefaultAlertConfig("")
+	config.ProjectID = projectID
+	return NewAlertChecker(l.storage, config).EvaluateAlerts(ctx)
+}
+
 // PurgeLogs deletes logs older than the specified time
 func (l *Logger) PurgeLogs(ctx context.Context, before time.Time) (int64, error) {
 	return l.storage.DeleteOldLogs(ctx, before)
 }
 
-// redactSensitiveData applies privacy redaction patterns
+// redactSensitiveData applies the JSON path redactions when data is JSON,
+// then the regex patterns
 func (l *Logger) redactSensitiveData(data string) string {
+	if len(l.privacy.RedactJSONPaths) > 0 {
+		data, _ = redactJSONPaths(data, l.privacy.RedactJSONPaths)
+	}
 	for _, pattern := range l.privacy.RedactPatterns {
 		re, err := regexp.Compile(pattern)
 		if err != nil {
@@ -175,6 +268,29 @@ func generateLogID() string {
 	return fmt.Sprintf("log-%d", time.Now().UnixNano())
 }
 
+// setLatencyPercentiles fills the p50/p95/p99 fields from latencies that are
+// already sorted ascending. An empty slice leaves the fields at zero.
+func (s *LogStats) setLatencyPercentiles(sorted []int64) {
+	s.P50LatencyMs = percentile(sorted, 50)
+	s.P95LatencyMs = percentile(sorted, 95)
+	s.P99LatencyMs = percentile(sorted, 99)
+}
+
+// percentile returns the nearest-rank percentile p (0-100] of an ascending slice
+func percentile(sorted []int64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+	return float64(sorted[rank-1])
+}
+
 // CalculateCost computes cost based on token usage and provider pricing
 func CalculateCost(providerCostPerMToken float64, totalTokens int64) float64 {
 	if providerCostPerMToken <= 0 || totalTokens <= 0 {
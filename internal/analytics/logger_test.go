@@ -135,6 +135,71 @@ func TestLogRequest_Redaction(t *testing.T) {
 	}
 }
 
+func TestLogRequest_JSONPathRedaction(t *testing.T) {
+	storage := &MockStorage{}
+	privacy := &PrivacyConfig{
+		LogRequestBodies:  true,
+		LogResponseBodies: true,
+		RedactPatterns:    []string{`[invalid(`},
+		RedactJSONPaths: []string{
+			"$.messages[*].content",
+			"$.auth.credentials.token",
+			"$['metadata']['tags'][1]",
+			"$..bad",
+		},
+	}
+	logger := NewLogger(storage, privacy)
+
+	log := &RequestLog{
+		RequestBody:  `{"model":"m","messages":[{"role":"user","content":"secret one"},{"role":"assistant","content":"secret two"}],"auth":{"credentials":{"token":"abc","user":"bob"}},"metadata":{"tags":["a","b","c"]},"n":1.50}`,
+		ResponseBody: `plain text with content: secret`,
+	}
+	if err := logger.LogRequest(context.Background(), log); err != nil {
+		t.Fatalf("LogRequest failed: %v", err)
+	}
+
+	want := `{"auth":{"credentials":{"token":"[REDACTED]","user":"bob"}},"messages":[{"content":"[REDACTED]","role":"user"},{"content":"[REDACTED]","role":"assistant"}],"metadata":{"tags":["a","[REDACTED]","c"]},"model":"m","n":1.50}`
+	if got := storage.logs[0].RequestBody; got != want {
+		t.Errorf("RequestBody = %s\nwant %s", got, want)
+	}
+	// Non-JSON bodies are left to the regex patterns.
+	if got := storage.logs[0].ResponseBody; got != "plain text with content: secret" {
+		t.Errorf("ResponseBody = %s", got)
+	}
+}
+
+func TestLogRequest_JSONPathRedactionBeforeTruncation(t *testing.T) {
+	storage := &MockStorage{}
+	logger := NewLogger(storage, &PrivacyConfig{
+		LogRequestBodies: true,
+		MaxBodyLength:    30,
+		RedactJSONPaths:  []string{"$.prompt"},
+	})
+
+	log := &RequestLog{RequestBody: `{"prompt":"a very long and very private prompt"}`}
+	if err := logger.LogRequest(context.Background(), log); err != nil {
+		t.Fatalf("LogRequest failed: %v", err)
+	}
+	if got := storage.logs[0].RequestBody; got != `{"prompt":"[REDACTED]"}` {
+		t.Errorf("RequestBody = %s", got)
+	}
+}
+
+func TestParseJSONPath(t *testing.T) {
+	valid := []string{"$.a", "$.a.b[0]", "$.a[*].b", "$.*", `$["a b"]`}
+	for _, p := range valid {
+		if _, ok := parseJSONPath(p); !ok {
+			t.Errorf("parseJSONPath(%q) rejected", p)
+		}
+	}
+	invalid := []string{"", "$", "a.b", "$..a", "$.a[", "$.a[-1]", "$.a[x]"}
+	for _, p := range invalid {
+		if _, ok := parseJSONPath(p); ok {
+			t.Errorf("parseJSONPath(%q) accepted", p)
+		}
+	}
+}
+
 func TestLogRequest_BodyTruncation(t *testing.T) {
 	storage := &MockStorage{}
 	privacy := &PrivacyConfig{
@@ -254,3 +319,60 @@ func TestDefaultPrivacyConfig(t *testing.T) {
 		t.Error("Default should have max body length")
 	}
 }
+
+func TestGetLogsPage(t *testing.T) {
+	storage := NewInMemoryStorage()
+	logger := NewLogger(storage, DefaultPrivacyConfig())
+	ctx := context.Background()
+
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		storage.SaveLog(ctx, &RequestLog{
+			ID:        string(rune('a' + i)),
+			Timestamp: now.Add(time.Duration(i) * time.Minute),
+		})
+	}
+
+	logs, total, err := logger.GetLogsPage(ctx, &LogFilter{Limit: 2, Offset: 2})
+	if err != nil {
+		t.Fatalf("GetLogsPage failed: %v", err)
+	}
+	if total != 5 {
+		t.Errorf("expected total 5, got %d", total)
+	}
+	if len(logs) != 2 || logs[0].ID != "c" || logs[1].ID != "b" {
+		t.Errorf("expected newest-first page [c b], got %v", logIDs(logs))
+	}
+}
+
+func TestGetLogsPage_WithoutCounter(t *testing.T) {
+	storage := &MockStorage{stats: &LogStats{TotalRequests: 42}}
+	logger := NewLogger(storage, DefaultPrivacyConfig())
+
+	_, total, err := logger.GetLogsPage(context.Background(), &LogFilter{Limit: 10})
+	if err != nil {
+		t.Fatalf("GetLogsPage failed: %v", err)
+	}
+	if total != 42 {
+		t.Errorf("expected total from stats, got %d", total)
+	}
+}
+
+func TestRedactForExport(t *testing.T) {
+	logger := NewLogger(&MockStorage{}, DefaultPrivacyConfig())
+	stored := &RequestLog{
+		ID:          "log-1",
+		RequestBody: `{"email":"alice@example.com"}`,
+	}
+
+	exported := logger.RedactForExport([]*RequestLog{stored})
+	if len(exported) != 1 {
+		t.Fatalf("expected 1 log, got %d", len(exported))
+	}
+	if exported[0].RequestBody != "" {
+		t.Errorf("expected request body to be dropped, got %q", exported[0].RequestBody)
+	}
+	if stored.RequestBody == "" {
+		t.Error("RedactForExport should not modify the stored log")
+	}
+}
@@ -0,0 +1,428 @@
+package analytics
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "github.com/lib/pq"
+)
+
+// postgresMigrationLock is the advisory lock key held while migrating, so
+// instances starting together don't race on the schema.
+const postgresMigrationLock = 7347001
+
+// postgresMigrations are applied in order; the index is the schema version
+// minus one. Append new migrations, never edit applied ones. Logs live in
+// analytics_request_logs because the main database schema already has a
+// request_logs table of its own when both share a server.
+var postgresMigrations = []string{
+	`CREATE TABLE IF NOT EXISTS analytics_request_logs (
+		id TEXT PRIMARY KEY,
+		timestamp TIMESTAMPTZ NOT NULL,
+		user_id TEXT NOT NULL,
+		project_id TEXT,
+		method TEXT NOT NULL,
+		path TEXT NOT NULL,
+		provider_id TEXT,
+		model_name TEXT,
+		prompt_tokens BIGINT,
+		completion_tokens BIGINT,
+		total_tokens BIGINT,
+		latency_ms BIGINT,
+		status_code INTEGER,
+		cost_usd DOUBLE PRECISION,
+		error_message TEXT,
+		request_body TEXT,
+		response_body TEXT,
+		metadata_json TEXT,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	);
+	CREATE INDEX IF NOT EXISTS idx_analytics_request_logs_timestamp ON analytics_request_logs(timestamp);
+	CREATE INDEX IF NOT EXISTS idx_analytics_request_logs_project_id ON analytics_request_logs(project_id);`,
+
+	`CREATE INDEX IF NOT EXISTS idx_analytics_request_logs_ts_user_provider ON analytics_request_logs(timestamp, user_id, provider_id);`,
+
+	`ALTER TABLE analytics_request_logs ADD COLUMN IF NOT EXISTS trace_id TEXT;
+	CREATE INDEX IF NOT EXISTS idx_analytics_request_logs_trace_id ON analytics_request_logs(trace_id);`,
+
+	`CREATE TABLE IF NOT EXISTS alert_states (
+		scope TEXT NOT NULL,
+		alert_key TEXT NOT NULL,
+		notified_at TIMESTAMPTZ NOT NULL,
+		PRIMARY KEY (scope, alert_key)
+	);`,
+
+	// Versions 1-3 once created the log table as request_logs; move logs
+	// written then to the table the storage reads now.
+	`DO $$
+	BEGIN
+		IF to_regclass('analytics_request_logs') IS NULL AND EXISTS (
+			SELECT 1 FROM information_schema.columns
+			WHERE table_schema = current_schema() AND table_name = 'request_logs' AND column_name = 'metadata_json'
+		) THEN
+			ALTER TABLE request_logs RENAME TO analytics_request_logs;
+		END IF;
+	END $$;`,
+}
+
+// PostgresStorage implements Storage using PostgreSQL, so analytics survive
+// restarts and can be shared by several instances.
+type PostgresStorage struct {
+	db *sql.DB
+}
+
+// OpenPostgresStorage connects to dsn with a pooled connection and returns
+// the migrated storage.
+func OpenPostgresStorage(dsn string) (*PostgresStorage, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open postgres: %w", err)
+	}
+	db.SetMaxOpenConns(25)
+	db.SetMaxIdleConns(5)
+	db.SetConnMaxLifetime(5 * time.Minute)
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping postgres: %w", err)
+	}
+
+	storage, err := NewPostgresStorage(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return storage, nil
+}
+
+// NewPostgresStorage creates a storage on an existing PostgreSQL connection
+// pool and applies pending schema migrations.
+func NewPostgresStorage(db *sql.DB) (*PostgresStorage, error) {
+	storage := &PostgresStorage{db: db}
+	if err := storage.migrate(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to migrate analytics schema: %w", err)
+	}
+	return storage, nil
+}
+
+// migrate applies the migrations newer than the recorded schema version.
+func (s *PostgresStorage) migrate(ctx context.Context) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", postgresMigrationLock); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS analytics_schema_migrations (
+			version INTEGER PRIMARY KEY,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		)`); err != nil {
+		return err
+	}
+
+	var current int
+	if err := tx.QueryRowContext(ctx, "SELECT COALESCE(MAX(version), 0) FROM analytics_schema_migrations").Scan(&current); err != nil {
+		return err
+	}
+	for i := current; i < len(postgresMigrations); i++ {
+		if _, err := tx.ExecContext(ctx, postgresMigrations[i]); err != nil {
+			return fmt.Errorf("migration %d: %w", i+1, err)
+		}
+		if _, err := tx.ExecContext(ctx, "INSERT INTO analytics_schema_migrations (version) VALUES ($1)", i+1); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// SaveLog persists a request log
+func (s *PostgresStorage) SaveLog(ctx context.Context, log *RequestLog) error {
+	metadataJSON, err := json.Marshal(log.Metadata)
+	if err != nil {
+		metadataJSON = []byte("{}")
+	}
+
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO analytics_request_logs (
+			id, timestamp, user_id, project_id, trace_id, method, path, provider_id, model_name,
+			prompt_tokens, completion_tokens, total_tokens, latency_ms,
+			status_code, cost_usd, error_message, request_body, response_body,
+			metadata_json
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+	`,
+		log.ID,
+		log.Timestamp,
+		log.UserID,
+		log.ProjectID,
+		log.TraceID,
+		log.Method,
+		log.Path,
+		log.ProviderID,
+		log.ModelName,
+		log.PromptTokens,
+		log.CompletionTokens,
+		log.TotalTokens,
+		log.LatencyMs,
+		log.StatusCode,
+		log.CostUSD,
+		log.ErrorMessage,
+		log.RequestBody,
+		log.ResponseBody,
+		string(metadataJSON),
+	)
+	return err
+}
+
+// GetLogs retrieves logs with filtering
+func (s *PostgresStorage) GetLogs(ctx context.Context, filter *LogFilter) ([]*RequestLog, error) {
+	query := `
+		SELECT
+			id, timestamp, user_id, COALESCE(project_id, ''), COALESCE(trace_id, ''), method, path,
+			COALESCE(provider_id, ''), COALESCE(model_name, ''),
+			COALESCE(prompt_tokens, 0), COALESCE(completion_tokens, 0), COALESCE(total_tokens, 0),
+			COALESCE(latency_ms, 0), COALESCE(status_code, 0), COALESCE(cost_usd, 0),
+			COALESCE(error_message, ''), COALESCE(request_body, ''), COALESCE(response_body, ''),
+			COALESCE(metadata_json, '')
+		FROM analytics_request_logs
+		WHERE 1=1` + buildWhereClause(filter) + `
+		ORDER BY timestamp DESC`
+	args := buildWhereArgs(filter)
+
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query += " OFFSET ?"
+		args = append(args, filter.Offset)
+	}
+
+	rows, err := s.db.QueryContext(ctx, rebindPostgres(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var logs []*RequestLog
+	for rows.Next() {
+		log := &RequestLog{}
+		var metadataJSON string
+		if err := rows.Scan(
+			&log.ID,
+			&log.Timestamp,
+			&log.UserID,
+			&log.ProjectID,
+			&log.TraceID,
+			&log.Method,
+			&log.Path,
+			&log.ProviderID,
+			&log.ModelName,
+			&log.PromptTokens,
+			&log.CompletionTokens,
+			&log.TotalTokens,
+			&log.LatencyMs,
+			&log.StatusCode,
+			&log.CostUSD,
+			&log.ErrorMessage,
+			&log.RequestBody,
+			&log.ResponseBody,
+			&metadataJSON,
+		); err != nil {
+			return nil, err
+		}
+		if metadataJSON != "" {
+			if err := json.Unmarshal([]byte(metadataJSON), &log.Metadata); err != nil {
+				log.Metadata = nil
+			}
+		}
+		logs = append(logs, log)
+	}
+
+	return logs, rows.Err()
+}
+
+// GetLogStats computes aggregate statistics. Totals and latency percentiles
+// are computed by the server in one pass; breakdowns use GROUP BY.
+func (s *PostgresStorage) GetLogStats(ctx context.Context, filter *LogFilter) (*LogStats, error) {
+	where := buildWhereClause(filter)
+	args := buildWhereArgs(filter)
+
+	stats := &LogStats{
+		RequestsByUser:     make(map[string]int64),
+		RequestsByProvider: make(map[string]int64),
+		CostByProvider:     make(map[string]float64),
+		CostByUser:         make(map[string]float64),
+		CostByProject:      make(map[string]float64),
+		CostByModel:        make(map[string]float64),
+		TokensByProvider:   make(map[string]int64),
+		TokensByUser:       make(map[string]int64),
+		TokensByModel:      make(map[string]int64),
+		LatencyByProvider:  make(map[string]float64),
+	}
+
+	// percentile_disc matches the nearest-rank percentile used elsewhere.
+	var errorCount int64
+	err := s.db.QueryRowContext(ctx, rebindPostgres(`
+		SELECT
+			COUNT(*),
+			COALESCE(SUM(total_tokens), 0),
+			COALESCE(SUM(cost_usd), 0),
+			COALESCE(AVG(latency_ms), 0),
+			COUNT(*) FILTER (WHERE status_code >= 400),
+			COALESCE(percentile_disc(0.50) WITHIN GROUP (ORDER BY COALESCE(latency_ms, 0)), 0),
+			COALESCE(percentile_disc(0.95) WITHIN GROUP (ORDER BY COALESCE(latency_ms, 0)), 0),
+			COALESCE(percentile_disc(0.99) WITHIN GROUP (ORDER BY COALESCE(latency_ms, 0)), 0)
+		FROM analytics_request_logs
+		WHERE 1=1`+where), args...).Scan(
+		&stats.TotalRequests,
+		&stats.TotalTokens,
+		&stats.TotalCostUSD,
+		&stats.AvgLatencyMs,
+		&errorCount,
+		&stats.P50LatencyMs,
+		&stats.P95LatencyMs,
+		&stats.P99LatencyMs,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if stats.TotalRequests > 0 {
+		stats.ErrorRate = float64(errorCount) / float64(stats.TotalRequests)
+	}
+
+	// Per-user stats (requests, costs, tokens)
+	rows, err := s.db.QueryContext(ctx, rebindPostgres(`
+		SELECT user_id, COUNT(*), COALESCE(SUM(cost_usd), 0), COALESCE(SUM(total_tokens), 0)
+		FROM analytics_request_logs
+		WHERE 1=1`+where+` AND user_id != ''
+		GROUP BY user_id`), args...)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var userID string
+		var count, tokens int64
+		var cost float64
+		if err := rows.Scan(&userID, &count, &cost, &tokens); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		stats.RequestsByUser[userID] = count
+		stats.CostByUser[userID] = cost
+		stats.TokensByUser[userID] = tokens
+	}
+	rows.Close()
+
+	// Per-project costs
+	rows, err = s.db.QueryContext(ctx, rebindPostgres(`
+		SELECT project_id, COALESCE(SUM(cost_usd), 0)
+		FROM analytics_request_logs
+		WHERE 1=1`+where+` AND project_id IS NOT NULL AND project_id != ''
+		GROUP BY project_id`), args...)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var projectID string
+		var cost float64
+		if err := rows.Scan(&projectID, &cost); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		stats.CostByProject[projectID] = cost
+	}
+	rows.Close()
+
+	// Per-model costs and tokens
+	rows, err = s.db.QueryContext(ctx, rebindPostgres(`
+		SELECT model_name, COALESCE(SUM(cost_usd), 0), COALESCE(SUM(total_tokens), 0)
+		FROM analytics_request_logs
+		WHERE 1=1`+where+` AND model_name IS NOT NULL AND model_name != ''
+		GROUP BY model_name`), args...)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var modelName string
+		var cost float64
+		var tokens int64
+		if err := rows.Scan(&modelName, &cost, &tokens); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		stats.CostByModel[modelName] = cost
+		stats.TokensByModel[modelName] = tokens
+	}
+	rows.Close()
+
+	// Per-provider stats (requests, costs, tokens, latency)
+	rows, err = s.db.QueryContext(ctx, rebindPostgres(`
+		SELECT provider_id, COUNT(*), COALESCE(SUM(cost_usd), 0),
+		       COALESCE(SUM(total_tokens), 0), COALESCE(AVG(latency_ms), 0)
+		FROM analytics_request_logs
+		WHERE 1=1`+where+` AND provider_id IS NOT NULL AND provider_id != ''
+		GROUP BY provider_id`), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var providerID string
+		var count, tokens int64
+		var cost, avgLatency float64
+		if err := rows.Scan(&providerID, &count, &cost, &tokens, &avgLatency); err != nil {
+			return nil, err
+		}
+		stats.RequestsByProvider[providerID] = count
+		stats.CostByProvider[providerID] = cost
+		stats.TokensByProvider[providerID] = tokens
+		stats.LatencyByProvider[providerID] = avgLatency
+	}
+
+	return stats, rows.Err()
+}
+
+// DeleteOldLogs removes logs older than the specified time
+func (s *PostgresStorage) DeleteOldLogs(ctx context.Context, before time.Time) (int64, error) {
+	result, err := s.db.ExecContext(ctx, "DELETE FROM analytics_request_logs WHERE timestamp < $1", before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// CountLogs returns how many logs match the filter, ignoring its limit and offset
+func (s *PostgresStorage) CountLogs(ctx context.Context, filter *LogFilter) (int64, error) {
+	query := rebindPostgres("SELECT COUNT(*) FROM analytics_request_logs WHERE 1=1" + buildWhereClause(filter))
+	var count int64
+	if err := s.db.QueryRowContext(ctx, query, buildWhereArgs(filter)...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// rebindPostgres converts the ? placeholders produced by buildWhereClause to
+// PostgreSQL's $1, $2, ... form.
+func rebindPostgres(query string) string {
+	var b strings.Builder
+	n := 0
+	for i := 0; i < len(query); i++ {
+		if query[i] == '?' {
+			n++
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(n))
+			continue
+		}
+		b.WriteByte(query[i])
+	}
+	return b.String()
+}
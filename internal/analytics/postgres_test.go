@@ -0,0 +1,111 @@
+package analytics
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jordanhubbard/loom/internal/database"
+)
+
+var (
+	_ Storage         = (*PostgresStorage)(nil)
+	_ LogCounter      = (*PostgresStorage)(nil)
+	_ AlertStateStore = (*PostgresStorage)(nil)
+)
+
+func TestRebindPostgres(t *testing.T) {
+	filter := &LogFilter{UserID: "u", ProviderID: "p", StartTime: time.Now()}
+	got := rebindPostgres("SELECT 1 FROM request_logs WHERE 1=1" + buildWhereClause(filter) + " LIMIT ?")
+	want := "SELECT 1 FROM request_logs WHERE 1=1 AND user_id = $1 AND provider_id = $2 AND timestamp >= $3 LIMIT $4"
+	if got != want {
+		t.Errorf("rebindPostgres = %q, want %q", got, want)
+	}
+}
+
+// TestPostgresStorage runs against a real server when LOOM_TEST_POSTGRES_DSN
+// is set.
+func TestPostgresStorage(t *testing.T) {
+	dsn := os.Getenv("LOOM_TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("LOOM_TEST_POSTGRES_DSN not set")
+	}
+	s, err := OpenPostgresStorage(dsn)
+	if err != nil {
+		t.Fatalf("OpenPostgresStorage: %v", err)
+	}
+	defer s.db.Close()
+	ctx := context.Background()
+	_, _ = s.db.ExecContext(ctx, "DELETE FROM analytics_request_logs")
+
+	// Migrating again is a no-op.
+	if err := s.migrate(ctx); err != nil {
+		t.Fatalf("second migrate: %v", err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	logs := []*RequestLog{
+		{ID: "l1", Timestamp: now.Add(-48 * time.Hour), UserID: "u1", ProviderID: "p1", Method: "POST", Path: "/x", TotalTokens: 10, LatencyMs: 100, StatusCode: 200, CostUSD: 0.5},
+		{ID: "l2", Timestamp: now.Add(-time.Hour), UserID: "u1", ProjectID: "proj", ProviderID: "p1", Method: "POST", Path: "/x", TotalTokens: 20, LatencyMs: 200, StatusCode: 500, CostUSD: 1},
+		{ID: "l3", Timestamp: now, UserID: "u2", ProviderID: "p2", Method: "POST", Path: "/x", TotalTokens: 30, LatencyMs: 300, StatusCode: 200, CostUSD: 2, Metadata: map[string]string{"k": "v"}},
+	}
+	for _, l := range logs {
+		if err := s.SaveLog(ctx, l); err != nil {
+			t.Fatalf("SaveLog: %v", err)
+		}
+	}
+
+	got, err := s.GetLogs(ctx, &LogFilter{UserID: "u1", Limit: 1})
+	if err != nil || len(got) != 1 || got[0].ID != "l2" {
+		t.Fatalf("GetLogs = %v, %v; want newest u1 log", got, err)
+	}
+
+	stats, err := s.GetLogStats(ctx, &LogFilter{})
+	if err != nil {
+		t.Fatalf("GetLogStats: %v", err)
+	}
+	if stats.TotalRequests != 3 || stats.TotalTokens != 60 || stats.P50LatencyMs != 200 || stats.P99LatencyMs != 300 {
+		t.Errorf("stats = %+v", stats)
+	}
+	if stats.RequestsByUser["u1"] != 2 || stats.CostByProject["proj"] != 1 || stats.TokensByProvider["p2"] != 30 {
+		t.Errorf("breakdowns = %+v", stats)
+	}
+
+	deleted, err := s.DeleteOldLogs(ctx, now.Add(-24*time.Hour))
+	if err != nil || deleted != 1 {
+		t.Errorf("DeleteOldLogs = %d, %v; want 1", deleted, err)
+	}
+	if n, err := s.CountLogs(ctx, &LogFilter{}); err != nil || n != 2 {
+		t.Errorf("CountLogs = %d, %v; want 2", n, err)
+	}
+}
+
+// TestPostgresStorage_SharedDatabase opens the storage on a database the main
+// schema was created in, as loom does, when LOOM_TEST_POSTGRES_DSN is set.
+func TestPostgresStorage_SharedDatabase(t *testing.T) {
+	dsn := os.Getenv("LOOM_TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("LOOM_TEST_POSTGRES_DSN not set")
+	}
+	db, err := database.NewPostgres(dsn)
+	if err != nil {
+		t.Fatalf("database.NewPostgres: %v", err)
+	}
+	defer db.Close()
+
+	s, err := NewPostgresStorage(db.DB())
+	if err != nil {
+		t.Fatalf("NewPostgresStorage on the main database: %v", err)
+	}
+	ctx := context.Background()
+	_, _ = s.db.ExecContext(ctx, "DELETE FROM analytics_request_logs WHERE id = 'shared-1'")
+
+	log := &RequestLog{ID: "shared-1", Timestamp: time.Now().UTC(), UserID: "u1", ProjectID: "proj", ProviderID: "p1", Method: "POST", Path: "/x", StatusCode: 200}
+	if err := s.SaveLog(ctx, log); err != nil {
+		t.Fatalf("SaveLog: %v", err)
+	}
+	if n, err := s.CountLogs(ctx, &LogFilter{ProjectID: "proj", UserID: "u1"}); err != nil || n < 1 {
+		t.Errorf("CountLogs = %d, %v; want the saved log", n, err)
+	}
+}
@@ -0,0 +1,142 @@
+package analytics
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+const redactedValue = "[REDACTED]"
+
+// jsonPathSegment is one step of a parsed JSON path: an object key, an array
+// index, or a wildcard matching every key or element.
+type jsonPathSegment struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// parseJSONPath parses the subset of JSONPath used for redaction: a leading
+// $, dotted or bracket-quoted keys, [N] indexes and * / [*] wildcards, e.g.
+// $.messages[*].content or $['headers']['x-api-key']. ok is false for paths
+// outside that subset.
+func parseJSONPath(path string) (segs []jsonPathSegment, ok bool) {
+	path = strings.TrimSpace(path)
+	if !strings.HasPrefix(path, "$") {
+		return nil, false
+	}
+	rest := path[1:]
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			name := rest[:end]
+			if name == "" {
+				return nil, false // ".." (recursive descent) is not supported
+			}
+			if name == "*" {
+				segs = append(segs, jsonPathSegment{wildcard: true})
+			} else {
+				segs = append(segs, jsonPathSegment{key: name})
+			}
+			rest = rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, false
+			}
+			inner := strings.TrimSpace(rest[1:end])
+			rest = rest[end+1:]
+			switch {
+			case inner == "*":
+				segs = append(segs, jsonPathSegment{wildcard: true})
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				segs = append(segs, jsonPathSegment{key: inner[1 : len(inner)-1]})
+			default:
+				n, err := strconv.Atoi(inner)
+				if err != nil || n < 0 {
+					return nil, false
+				}
+				segs = append(segs, jsonPathSegment{index: n, isIndex: true})
+			}
+		default:
+			return nil, false
+		}
+	}
+	return segs, len(segs) > 0
+}
+
+// redactJSONPaths replaces the values at the given paths with [REDACTED].
+// ok is false when data is not valid JSON, so the caller can fall back to
+// regex redaction. Invalid paths are skipped.
+func redactJSONPaths(data string, paths []string) (string, bool) {
+	dec := json.NewDecoder(strings.NewReader(data))
+	dec.UseNumber()
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil || dec.More() {
+		return data, false
+	}
+
+	changed := false
+	for _, p := range paths {
+		segs, ok := parseJSONPath(p)
+		if !ok {
+			continue // Skip invalid paths
+		}
+		var hit bool
+		doc, hit = redactAt(doc, segs)
+		changed = changed || hit
+	}
+	if !changed {
+		return data, true
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(doc); err != nil {
+		return data, false
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), true
+}
+
+// redactAt walks segs from node and replaces every matched value. It returns
+// the (possibly replaced) node and whether anything matched.
+func redactAt(node interface{}, segs []jsonPathSegment) (interface{}, bool) {
+	if len(segs) == 0 {
+		return redactedValue, true
+	}
+	seg, rest := segs[0], segs[1:]
+	hit := false
+
+	switch v := node.(type) {
+	case map[string]interface{}:
+		if seg.isIndex {
+			return node, false
+		}
+		for k, child := range v {
+			if seg.wildcard || k == seg.key {
+				var h bool
+				v[k], h = redactAt(child, rest)
+				hit = hit || h
+			}
+		}
+	case []interface{}:
+		if seg.wildcard {
+			for i, child := range v {
+				var h bool
+				v[i], h = redactAt(child, rest)
+				hit = hit || h
+			}
+		} else if seg.isIndex && seg.index < len(v) {
+			v[seg.index], hit = redactAt(v[seg.index], rest)
+		}
+	}
+	return node, hit
+}
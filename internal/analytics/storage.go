@@ -29,6 +29,7 @@ func (s *DatabaseStorage) initSchema() error {
 		id TEXT PRIMARY KEY,
 		timestamp DATETIME NOT NULL,
 		user_id TEXT NOT NULL,
+		project_id TEXT,
 		method TEXT NOT NULL,
 		path TEXT NOT NULL,
 		provider_id TEXT,
@@ -52,7 +53,31 @@ func (s *DatabaseStorage) initSchema() error {
 	CREATE INDEX IF NOT EXISTS idx_request_logs_created_at ON request_logs(created_at);
 	`
 
-	_, err := s.db.Exec(schema)
+	if _, err := s.db.Exec(schema); err != nil {
+		return err
+	}
+
+	// Tables created before per-project tracking lack project_id.
+	// SQLite doesn't support IF NOT EXISTS on ADD COLUMN, so ignore the
+	// duplicate column error on already-migrated databases.
+	_, _ = s.db.Exec("ALTER TABLE request_logs ADD COLUMN project_id TEXT")
+	_, _ = s.db.Exec("ALTER TABLE request_logs ADD COLUMN trace_id TEXT")
+
+	if _, err := s.db.Exec("CREATE INDEX IF NOT EXISTS idx_request_logs_project_id ON request_logs(project_id)"); err != nil {
+		return err
+	}
+	if _, err := s.db.Exec("CREATE INDEX IF NOT EXISTS idx_request_logs_trace_id ON request_logs(trace_id)"); err != nil {
+		return err
+	}
+
+	// When each alert last notified, for cooldowns
+	_, err := s.db.Exec(`
+	CREATE TABLE IF NOT EXISTS alert_states (
+		scope TEXT NOT NULL,
+		alert_key TEXT NOT NULL,
+		notified_at DATETIME NOT NULL,
+		PRIMARY KEY (scope, alert_key)
+	)`)
 	return err
 }
 
@@ -65,17 +90,19 @@ func (s *DatabaseStorage) SaveLog(ctx context.Context, log *RequestLog) error {
 
 	query := `
 		INSERT INTO request_logs (
-			id, timestamp, user_id, method, path, provider_id, model_name,
+			id, timestamp, user_id, project_id, trace_id, method, path, provider_id, model_name,
 			prompt_tokens, completion_tokens, total_tokens, latency_ms,
 			status_code, cost_usd, error_message, request_body, response_body,
 			metadata_json
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	_, err = s.db.ExecContext(ctx, query,
 		log.ID,
 		log.Timestamp,
 		log.UserID,
+		log.ProjectID,
+		log.TraceID,
 		log.Method,
 		log.Path,
 		log.ProviderID,
@@ -99,7 +126,7 @@ func (s *DatabaseStorage) SaveLog(ctx context.Context, log *RequestLog) error {
 func (s *DatabaseStorage) GetLogs(ctx context.Context, filter *LogFilter) ([]*RequestLog, error) {
 	query := `
 		SELECT 
-			id, timestamp, user_id, method, path, provider_id, model_name,
+			id, timestamp, user_id, COALESCE(project_id, ''), COALESCE(trace_id, ''), method, path, provider_id, model_name,
 			prompt_tokens, completion_tokens, total_tokens, latency_ms,
 			status_code, cost_usd, error_message, request_body, response_body,
 			metadata_json
@@ -113,6 +140,16 @@ func (s *DatabaseStorage) GetLogs(ctx context.Context, filter *LogFilter) ([]*Re
 		args = append(args, filter.UserID)
 	}
 
+	if filter.ProjectID != "" {
+		query += " AND project_id = ?"
+		args = append(args, filter.ProjectID)
+	}
+
+	if filter.TraceID != "" {
+		query += " AND trace_id = ?"
+		args = append(args, filter.TraceID)
+	}
+
 	if filter.ProviderID != "" {
 		query += " AND provider_id = ?"
 		args = append(args, filter.ProviderID)
@@ -133,10 +170,12 @@ func (s *DatabaseStorage) GetLogs(ctx context.Context, filter *LogFilter) ([]*Re
 	if filter.Limit > 0 {
 		query += " LIMIT ?"
 		args = append(args, filter.Limit)
-		if filter.Offset > 0 {
-			query += " OFFSET ?"
-			args = append(args, filter.Offset)
-		}
+	} else if filter.Offset > 0 {
+		query += " LIMIT -1" // SQLite requires LIMIT before OFFSET
+	}
+	if filter.Offset > 0 {
+		query += " OFFSET ?"
+		args = append(args, filter.Offset)
 	}
 
 	rows, err := s.db.QueryContext(ctx, query, args...)
@@ -154,6 +193,8 @@ func (s *DatabaseStorage) GetLogs(ctx context.Context, filter *LogFilter) ([]*Re
 			&log.ID,
 			&log.Timestamp,
 			&log.UserID,
+			&log.ProjectID,
+			&log.TraceID,
 			&log.Method,
 			&log.Path,
 			&log.ProviderID,
@@ -205,6 +246,16 @@ func (s *DatabaseStorage) GetLogStats(ctx context.Context, filter *LogFilter) (*
 		args = append(args, filter.UserID)
 	}
 
+	if filter.ProjectID != "" {
+		baseQuery += " AND project_id = ?"
+		args = append(args, filter.ProjectID)
+	}
+
+	if filter.TraceID != "" {
+		baseQuery += " AND trace_id = ?"
+		args = append(args, filter.TraceID)
+	}
+
 	if filter.ProviderID != "" {
 		baseQuery += " AND provider_id = ?"
 		args = append(args, filter.ProviderID)
@@ -225,8 +276,11 @@ func (s *DatabaseStorage) GetLogStats(ctx context.Context, filter *LogFilter) (*
 		RequestsByProvider: make(map[string]int64),
 		CostByProvider:     make(map[string]float64),
 		CostByUser:         make(map[string]float64),
+		CostByProject:      make(map[string]float64),
+		CostByModel:        make(map[string]float64),
 		TokensByProvider:   make(map[string]int64),
 		TokensByUser:       make(map[string]int64),
+		TokensByModel:      make(map[string]int64),
 		LatencyByProvider:  make(map[string]float64),
 	}
 
@@ -247,6 +301,30 @@ func (s *DatabaseStorage) GetLogStats(ctx context.Context, filter *LogFilter) (*
 		stats.ErrorRate = float64(errorCount) / float64(stats.TotalRequests)
 	}
 
+	// Latency percentiles (SQLite has no percentile aggregate, so sort in SQL
+	// and index into the ordered result)
+	if stats.TotalRequests > 0 {
+		latencyQuery := fmt.Sprintf(`
+			SELECT COALESCE(latency_ms, 0)
+			FROM request_logs
+			WHERE 1=1 %s
+			ORDER BY latency_ms ASC
+		`, buildWhereClause(filter))
+
+		rows, err := s.db.QueryContext(ctx, latencyQuery, buildWhereArgs(filter)...)
+		if err == nil {
+			latencies := make([]int64, 0, stats.TotalRequests)
+			for rows.Next() {
+				var latency int64
+				if err := rows.Scan(&latency); err == nil {
+					latencies = append(latencies, latency)
+				}
+			}
+			rows.Close()
+			stats.setLatencyPercentiles(latencies)
+		}
+	}
+
 	// Get per-user stats (requests, costs, tokens)
 	userQuery := fmt.Sprintf(`
 		SELECT user_id, COUNT(*) as count, COALESCE(SUM(cost_usd), 0) as cost,
@@ -272,6 +350,48 @@ func (s *DatabaseStorage) GetLogStats(ctx context.Context, filter *LogFilter) (*
 		}
 	}
 
+	// Get per-project costs
+	projectQuery := fmt.Sprintf(`
+		SELECT project_id, COALESCE(SUM(cost_usd), 0) as cost
+		FROM request_logs
+		WHERE 1=1 %s AND project_id IS NOT NULL AND project_id != ''
+		GROUP BY project_id
+	`, buildWhereClause(filter))
+
+	rows, err = s.db.QueryContext(ctx, projectQuery, buildWhereArgs(filter)...)
+	if err == nil {
+		defer rows.Close()
+		for rows.Next() {
+			var projectID string
+			var cost float64
+			if err := rows.Scan(&projectID, &cost); err == nil {
+				stats.CostByProject[projectID] = cost
+			}
+		}
+	}
+
+	// Get per-model costs and tokens
+	modelQuery := fmt.Sprintf(`
+		SELECT model_name, COALESCE(SUM(cost_usd), 0) as cost, COALESCE(SUM(total_tokens), 0) as tokens
+		FROM request_logs
+		WHERE 1=1 %s AND model_name IS NOT NULL AND model_name != ''
+		GROUP BY model_name
+	`, buildWhereClause(filter))
+
+	rows, err = s.db.QueryContext(ctx, modelQuery, buildWhereArgs(filter)...)
+	if err == nil {
+		defer rows.Close()
+		for rows.Next() {
+			var modelName string
+			var cost float64
+			var tokens int64
+			if err := rows.Scan(&modelName, &cost, &tokens); err == nil {
+				stats.CostByModel[modelName] = cost
+				stats.TokensByModel[modelName] = tokens
+			}
+		}
+	}
+
 	// Get per-provider stats (requests, costs, tokens, latency)
 	providerQuery := fmt.Sprintf(`
 		SELECT provider_id, COUNT(*) as count, COALESCE(SUM(cost_usd), 0) as cost,
@@ -311,12 +431,28 @@ func (s *DatabaseStorage) DeleteOldLogs(ctx context.Context, before time.Time) (
 	return result.RowsAffected()
 }
 
+// CountLogs returns how many logs match the filter, ignoring its limit and offset
+func (s *DatabaseStorage) CountLogs(ctx context.Context, filter *LogFilter) (int64, error) {
+	query := "SELECT COUNT(*) FROM request_logs WHERE 1=1" + buildWhereClause(filter)
+	var count int64
+	if err := s.db.QueryRowContext(ctx, query, buildWhereArgs(filter)...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
 // Helper functions for building queries
 func buildWhereClause(filter *LogFilter) string {
 	where := ""
 	if filter.UserID != "" {
 		where += " AND user_id = ?"
 	}
+	if filter.ProjectID != "" {
+		where += " AND project_id = ?"
+	}
+	if filter.TraceID != "" {
+		where += " AND trace_id = ?"
+	}
 	if filter.ProviderID != "" {
 		where += " AND provider_id = ?"
 	}
@@ -334,6 +470,12 @@ func buildWhereArgs(filter *LogFilter) []interface{} {
 	if filter.UserID != "" {
 		args = append(args, filter.UserID)
 	}
+	if filter.ProjectID != "" {
+		args = append(args, filter.ProjectID)
+	}
+	if filter.TraceID != "" {
+		args = append(args, filter.TraceID)
+	}
 	if filter.ProviderID != "" {
 		args = append(args, filter.ProviderID)
 	}
@@ -3,9 +3,11 @@ package analytics
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"testing"
 	"time"
 
+	"github.com/jordanhubbard/loom/internal/observability"
 	_ "github.com/mattn/go-sqlite3"
 )
 
@@ -206,6 +208,14 @@ func TestDatabaseStorage_GetLogStats(t *testing.T) {
 	if stats.CostByProvider["openai"] != 0.03 {
 		t.Errorf("openai cost = %f, want 0.03", stats.CostByProvider["openai"])
 	}
+
+	// Latency percentiles (nearest rank over 100/200/300ms)
+	if stats.P50LatencyMs != 200 {
+		t.Errorf("P50LatencyMs = %f, want 200", stats.P50LatencyMs)
+	}
+	if stats.P95LatencyMs != 300 || stats.P99LatencyMs != 300 {
+		t.Errorf("P95/P99 = %f/%f, want 300/300", stats.P95LatencyMs, stats.P99LatencyMs)
+	}
 }
 
 func TestDatabaseStorage_GetLogStats_Filtered(t *testing.T) {
@@ -240,6 +250,81 @@ func TestDatabaseStorage_GetLogStats_Filtered(t *testing.T) {
 	}
 }
 
+func TestDatabaseStorage_GetLogStats_ByProject(t *testing.T) {
+	db := newTestDB(t)
+	storage, err := NewDatabaseStorage(db)
+	if err != nil {
+		t.Fatalf("NewDatabaseStorage failed: %v", err)
+	}
+
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+
+	_ = storage.SaveLog(ctx, &RequestLog{ID: "pj1", Timestamp: now, UserID: "alice", ProjectID: "acme", CostUSD: 0.10, StatusCode: 200, Method: "POST", Path: "/api"})
+	_ = storage.SaveLog(ctx, &RequestLog{ID: "pj2", Timestamp: now, UserID: "bob", ProjectID: "acme", CostUSD: 0.20, StatusCode: 200, Method: "POST", Path: "/api"})
+	_ = storage.SaveLog(ctx, &RequestLog{ID: "pj3", Timestamp: now, UserID: "bob", ProjectID: "globex", CostUSD: 0.05, StatusCode: 200, Method: "POST", Path: "/api"})
+	_ = storage.SaveLog(ctx, &RequestLog{ID: "pj4", Timestamp: now, UserID: "bob", CostUSD: 0.01, StatusCode: 200, Method: "POST", Path: "/api"})
+
+	stats, err := storage.GetLogStats(ctx, &LogFilter{})
+	if err != nil {
+		t.Fatalf("GetLogStats failed: %v", err)
+	}
+	if len(stats.CostByProject) != 2 {
+		t.Errorf("CostByProject = %v, want 2 projects", stats.CostByProject)
+	}
+	if got := stats.CostByProject["acme"]; got < 0.2999 || got > 0.3001 {
+		t.Errorf("acme cost = %f, want 0.30", got)
+	}
+
+	stats, err = storage.GetLogStats(ctx, &LogFilter{ProjectID: "acme"})
+	if err != nil {
+		t.Fatalf("GetLogStats by project failed: %v", err)
+	}
+	if stats.TotalRequests != 2 {
+		t.Errorf("TotalRequests = %d, want 2", stats.TotalRequests)
+	}
+
+	logs, err := storage.GetLogs(ctx, &LogFilter{ProjectID: "globex"})
+	if err != nil {
+		t.Fatalf("GetLogs by project failed: %v", err)
+	}
+	if len(logs) != 1 || logs[0].ProjectID != "globex" {
+		t.Errorf("GetLogs(globex) = %+v, want single globex log", logs)
+	}
+}
+
+func TestNewDatabaseStorage_AddsProjectColumn(t *testing.T) {
+	db := newTestDB(t)
+	// Table as created before per-project tracking existed
+	if _, err := db.Exec(`CREATE TABLE request_logs (
+		id TEXT PRIMARY KEY, timestamp DATETIME NOT NULL, user_id TEXT NOT NULL,
+		method TEXT NOT NULL, path TEXT NOT NULL, provider_id TEXT, model_name TEXT,
+		prompt_tokens INTEGER, completion_tokens INTEGER, total_tokens INTEGER,
+		latency_ms INTEGER, status_code INTEGER, cost_usd REAL, error_message TEXT,
+		request_body TEXT, response_body TEXT, metadata_json TEXT,
+		created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP)`); err != nil {
+		t.Fatalf("create legacy table: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO request_logs (id, timestamp, user_id, method, path, provider_id, model_name,
+		prompt_tokens, completion_tokens, total_tokens, latency_ms, status_code, cost_usd, error_message,
+		request_body, response_body, metadata_json) VALUES ('old', ?, 'alice', 'POST', '/api', '', '', 0, 0, 0, 0, 200, 0.5, '', '', '', '{}')`, time.Now()); err != nil {
+		t.Fatalf("insert legacy row: %v", err)
+	}
+
+	storage, err := NewDatabaseStorage(db)
+	if err != nil {
+		t.Fatalf("NewDatabaseStorage on legacy table failed: %v", err)
+	}
+
+	logs, err := storage.GetLogs(context.Background(), &LogFilter{})
+	if err != nil {
+		t.Fatalf("GetLogs failed: %v", err)
+	}
+	if len(logs) != 1 || logs[0].ProjectID != "" {
+		t.Errorf("expected legacy row with empty project, got %+v", logs)
+	}
+}
+
 func TestDatabaseStorage_GetLogStats_Empty(t *testing.T) {
 	db := newTestDB(t)
 	storage, err := NewDatabaseStorage(db)
@@ -255,6 +340,9 @@ func TestDatabaseStorage_GetLogStats_Empty(t *testing.T) {
 	if stats.TotalRequests != 0 {
 		t.Errorf("TotalRequests = %d, want 0", stats.TotalRequests)
 	}
+	if stats.P50LatencyMs != 0 || stats.P99LatencyMs != 0 {
+		t.Errorf("expected zero percentiles on empty DB, got p50=%f p99=%f", stats.P50LatencyMs, stats.P99LatencyMs)
+	}
 }
 
 func TestDatabaseStorage_DeleteOldLogs(t *testing.T) {
@@ -385,3 +473,160 @@ func TestDatabaseStorage_GetLogStats_TokensByUser(t *testing.T) {
 		t.Errorf("p1 tokens = %d, want 3000", stats.TokensByProvider["p1"])
 	}
 }
+
+func TestDatabaseStorage_GetLogStats_ByModel(t *testing.T) {
+	db := newTestDB(t)
+	storage, err := NewDatabaseStorage(db)
+	if err != nil {
+		t.Fatalf("NewDatabaseStorage failed: %v", err)
+	}
+
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+
+	_ = storage.SaveLog(ctx, &RequestLog{ID: "m1", Timestamp: now, UserID: "alice", ModelName: "gpt-4", TotalTokens: 1000, CostUSD: 0.80, StatusCode: 200, Method: "POST", Path: "/api"})
+	_ = storage.SaveLog(ctx, &RequestLog{ID: "m2", Timestamp: now, UserID: "alice", ModelName: "small", TotalTokens: 3000, CostUSD: 0.10, StatusCode: 200, Method: "POST", Path: "/api"})
+	_ = storage.SaveLog(ctx, &RequestLog{ID: "m3", Timestamp: now, UserID: "bob", ModelName: "small", TotalTokens: 2000, CostUSD: 0.10, StatusCode: 200, Method: "POST", Path: "/api"})
+	_ = storage.SaveLog(ctx, &RequestLog{ID: "m4", Timestamp: now, UserID: "bob", TotalTokens: 10, StatusCode: 200, Method: "POST", Path: "/api"})
+
+	stats, err := storage.GetLogStats(ctx, &LogFilter{})
+	if err != nil {
+		t.Fatalf("GetLogStats failed: %v", err)
+	}
+
+	if len(stats.CostByModel) != 2 {
+		t.Errorf("CostByModel = %v, want 2 models", stats.CostByModel)
+	}
+	if got := stats.CostByModel["gpt-4"]; got < 0.7999 || got > 0.8001 {
+		t.Errorf("gpt-4 cost = %f, want 0.80", got)
+	}
+	if stats.TokensByModel["small"] != 5000 {
+		t.Errorf("small tokens = %d, want 5000", stats.TokensByModel["small"])
+	}
+
+	stats, err = storage.GetLogStats(ctx, &LogFilter{UserID: "bob"})
+	if err != nil {
+		t.Fatalf("GetLogStats failed: %v", err)
+	}
+	if _, ok := stats.CostByModel["gpt-4"]; ok || stats.TokensByModel["small"] != 2000 {
+		t.Errorf("filtered model stats = %v / %v", stats.CostByModel, stats.TokensByModel)
+	}
+}
+
+func TestDatabaseStorage_Pagination(t *testing.T) {
+	db := newTestDB(t)
+	storage, err := NewDatabaseStorage(db)
+	if err != nil {
+		t.Fatalf("NewDatabaseStorage failed: %v", err)
+	}
+
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+	for i := 0; i < 5; i++ {
+		log := &RequestLog{
+			ID:         fmt.Sprintf("log-%d", i),
+			Timestamp:  now.Add(-time.Duration(i) * time.Minute),
+			UserID:     "user-alice",
+			ProviderID: "openai",
+		}
+		if err := storage.SaveLog(ctx, log); err != nil {
+			t.Fatalf("SaveLog failed: %v", err)
+		}
+	}
+
+	logs, err := storage.GetLogs(ctx, &LogFilter{Limit: 2, Offset: 1})
+	if err != nil {
+		t.Fatalf("GetLogs failed: %v", err)
+	}
+	if len(logs) != 2 || logs[0].ID != "log-1" || logs[1].ID != "log-2" {
+		t.Fatalf("expected [log-1 log-2], got %v", logIDs(logs))
+	}
+
+	// Offset without a limit returns the rest of the results
+	logs, err = storage.GetLogs(ctx, &LogFilter{Offset: 3})
+	if err != nil {
+		t.Fatalf("GetLogs with offset only failed: %v", err)
+	}
+	if len(logs) != 2 || logs[0].ID != "log-3" {
+		t.Fatalf("expected [log-3 log-4], got %v", logIDs(logs))
+	}
+
+	count, err := storage.CountLogs(ctx, &LogFilter{UserID: "user-alice", Limit: 1, Offset: 1})
+	if err != nil {
+		t.Fatalf("CountLogs failed: %v", err)
+	}
+	if count != 5 {
+		t.Errorf("expected count 5, got %d", count)
+	}
+}
+
+func logIDs(logs []*RequestLog) []string {
+	ids := make([]string, len(logs))
+	for i, log := range logs {
+		ids[i] = log.ID
+	}
+	return ids
+}
+
+func TestDatabaseStorage_FilterByTraceID(t *testing.T) {
+	db := newTestDB(t)
+	storage, err := NewDatabaseStorage(db)
+	if err != nil {
+		t.Fatalf("NewDatabaseStorage failed: %v", err)
+	}
+	logger := NewLogger(storage, nil)
+
+	traced := observability.WithTraceID(context.Background(), "trace-1")
+	_ = logger.LogRequest(traced, &RequestLog{UserID: "agent:a", ProjectID: "acme", CostUSD: 0.10, StatusCode: 200, Method: "POST", Path: "/internal/worker/execute"})
+	_ = logger.LogRequest(traced, &RequestLog{UserID: "agent:a", ProjectID: "acme", CostUSD: 0.20, StatusCode: 500, Method: "POST", Path: "/internal/worker/execute"})
+	_ = logger.LogRequest(context.Background(), &RequestLog{UserID: "agent:b", ProjectID: "acme", StatusCode: 200, Method: "POST", Path: "/internal/worker/execute"})
+
+	logs, err := storage.GetLogs(context.Background(), &LogFilter{TraceID: "trace-1"})
+	if err != nil {
+		t.Fatalf("GetLogs by trace failed: %v", err)
+	}
+	if len(logs) != 2 || logs[0].TraceID != "trace-1" || logs[1].TraceID != "trace-1" {
+		t.Errorf("GetLogs(trace-1) = %+v, want the two traced logs", logs)
+	}
+
+	stats, err := storage.GetLogStats(context.Background(), &LogFilter{TraceID: "trace-1"})
+	if err != nil {
+		t.Fatalf("GetLogStats by trace failed: %v", err)
+	}
+	if stats.TotalRequests != 2 || stats.ErrorRate != 0.5 {
+		t.Errorf("stats = %d requests, %.2f error rate, want 2 and 0.50", stats.TotalRequests, stats.ErrorRate)
+	}
+}
+
+func TestDatabaseStorage_AlertStates(t *testing.T) {
+	storage, err := NewDatabaseStorage(newTestDB(t))
+	if err != nil {
+		t.Fatalf("NewDatabaseStorage failed: %v", err)
+	}
+	ctx := context.Background()
+	first := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	second := first.Add(30 * time.Minute)
+
+	if err := storage.SetAlertState(ctx, "user:u1", "budget_exceeded", first); err != nil {
+		t.Fatalf("SetAlertState failed: %v", err)
+	}
+	if err := storage.SetAlertState(ctx, "user:u1", "budget_exceeded", second); err != nil {
+		t.Fatalf("SetAlertState (update) failed: %v", err)
+	}
+	_ = storage.SetAlertState(ctx, "user:u2", "latency_spike/p95_latency_ms", first)
+
+	states, err := storage.GetAlertStates(ctx, "user:u1")
+	if err != nil {
+		t.Fatalf("GetAlertStates failed: %v", err)
+	}
+	if len(states) != 1 || !states["budget_exceeded"].Equal(second) {
+		t.Errorf("states = %v, want budget_exceeded at %v", states, second)
+	}
+
+	if err := storage.DeleteAlertState(ctx, "user:u1", "budget_exceeded"); err != nil {
+		t.Fatalf("DeleteAlertState failed: %v", err)
+	}
+	if states, _ := storage.GetAlertStates(ctx, "user:u1"); len(states) != 0 {
+		t.Errorf("states after delete = %v, want none", states)
+	}
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// DefaultMaxRequestBodyBytes caps request bodies when
+// server.max_request_body_bytes is unset.
+const DefaultMaxRequestBodyBytes = 4 << 20 // 4MB
+
+// routeBodyLimits overrides the body cap for routes that accept large uploads.
+var routeBodyLimits = map[string]int64{
+	"/api/v1/import": maxImportSize,
+}
+
+// maxRequestBodyBytes returns the configured body cap. Zero uses the default
+// and a negative value disables the cap.
+func (s *Server) maxRequestBodyBytes() int64 {
+	if s.config == nil || s.config.Server.MaxRequestBodyBytes == 0 {
+		return DefaultMaxRequestBodyBytes
+	}
+	return s.config.Server.MaxRequestBodyBytes
+}
+
+// limitRequestBody caps the request body of a route. A declared
+// Content-Length over the cap is rejected up front; otherwise the body is
+// wrapped in http.MaxBytesReader, so every handler reading it stops at the
+// cap, and a client error the handler returns after hitting it becomes 413.
+func (s *Server) limitRequestBody(pattern string, next http.HandlerFunc) http.HandlerFunc {
+	limit, ok := routeBodyLimits[pattern]
+	if !ok {
+		limit = s.maxRequestBodyBytes()
+	}
+	if limit < 0 {
+		return next
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength > limit {
+			s.respondError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("Request body exceeds %d bytes", limit))
+			return
+		}
+		if r.Body == nil || r.Body == http.NoBody {
+			next(w, r)
+			return
+		}
+		body := &limitedBody{ReadCloser: http.MaxBytesReader(w, r.Body, limit)}
+		r.Body = body
+		next(&bodyLimitWriter{ResponseWriter: w, body: body}, r)
+	}
+}
+
+// limitedBody records whether a read hit the body cap.
+type limitedBody struct {
+	io.ReadCloser
+	exceeded bool
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		b.exceeded = true
+	}
+	return n, err
+}
+
+// bodyLimitWriter turns a handler's 4xx into 413 when the body was cut off,
+// since the handler only saw a failed read or a truncated document.
+type bodyLimitWriter struct {
+	http.ResponseWriter
+	body *limitedBody
+}
+
+func (w *bodyLimitWriter) WriteHeader(code int) {
+	if w.body.exceeded && code >= 400 && code < 500 {
+		code = http.StatusRequestEntityTooLarge
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Flush implements http.Flusher to support streaming
+func (w *bodyLimitWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
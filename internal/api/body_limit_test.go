@@ -0,0 +1,109 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jordanhubbard/loom/pkg/config"
+)
+
+func newBodyLimitServer(limit int64) *Server {
+	cfg := &config.Config{}
+	cfg.Server.MaxRequestBodyBytes = limit
+	return &Server{config: cfg}
+}
+
+// decodeHandler responds like the API handlers do on a bad body.
+func decodeHandler(s *Server) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var v map[string]interface{}
+		if err := s.parseJSON(r, &v); err != nil {
+			s.respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		s.respondJSON(w, http.StatusOK, v)
+	}
+}
+
+func TestLimitRequestBody_ContentLengthRejected(t *testing.T) {
+	s := newBodyLimitServer(16)
+	called := false
+	handler := s.limitRequestBody("/api/v1/beads", func(w http.ResponseWriter, r *http.Request) { called = true })
+
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodPost, "/api/v1/beads", strings.NewReader(`{"title":"much too long"}`)))
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected 413, got %d", w.Code)
+	}
+	if called {
+		t.Error("handler should not run for an oversized Content-Length")
+	}
+}
+
+func TestLimitRequestBody_StreamedBodyBecomes413(t *testing.T) {
+	s := newBodyLimitServer(16)
+	handler := s.limitRequestBody("/api/v1/beads", decodeHandler(s))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/beads", io.NopCloser(strings.NewReader(`{"title":"`+strings.Repeat("x", 64)+`"}`)))
+	req.ContentLength = -1 // Chunked; size unknown up front
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected 413, got %d", w.Code)
+	}
+}
+
+func TestLimitRequestBody_AllowsSmallBodies(t *testing.T) {
+	s := newBodyLimitServer(0) // Default limit
+	handler := s.limitRequestBody("/api/v1/beads", decodeHandler(s))
+
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodPost, "/api/v1/beads", strings.NewReader(`{"title":"ok"}`)))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got %d", w.Code)
+	}
+
+	// A malformed small body is still a 400.
+	w = httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodPost, "/api/v1/beads", strings.NewReader(`{`)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestLimitRequestBody_Overrides(t *testing.T) {
+	body := strings.Repeat("x", 64)
+
+	// Negative disables the cap.
+	s := newBodyLimitServer(-1)
+	w := httptest.NewRecorder()
+	s.limitRequestBody("/api/v1/beads", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})(w, httptest.NewRequest(http.MethodPost, "/api/v1/beads", strings.NewReader(body)))
+	if w.Code != http.StatusNoContent {
+		t.Errorf("expected no limit, got %d", w.Code)
+	}
+
+	// Imports keep their own larger limit.
+	s = newBodyLimitServer(16)
+	w = httptest.NewRecorder()
+	s.limitRequestBody("/api/v1/import", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})(w, httptest.NewRequest(http.MethodPost, "/api/v1/import", strings.NewReader(body)))
+	if w.Code != http.StatusNoContent {
+		t.Errorf("expected import route to allow body, got %d", w.Code)
+	}
+}
+
+func TestSetupRoutes_AppliesBodyLimit(t *testing.T) {
+	s := NewServer(nil, nil, nil, &config.Config{Server: config.ServerConfig{MaxRequestBodyBytes: 8}})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", strings.NewReader(`{"username":"admin","password":"admin"}`))
+	s.SetupRoutes().ServeHTTP(w, req)
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected 413, got %d", w.Code)
+	}
+}
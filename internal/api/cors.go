@@ -0,0 +1,104 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jordanhubbard/loom/pkg/config"
+)
+
+var (
+	defaultCORSMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	defaultCORSHeaders = []string{"Content-Type", "X-API-Key", "Authorization"}
+)
+
+// corsConfig returns the effective CORS settings. server.cors takes
+// precedence; security.allowed_origins is still honored when it is unset.
+// No allowed origins means same-origin only.
+func (s *Server) corsConfig() config.CORSConfig {
+	if s.config == nil {
+		return config.CORSConfig{}
+	}
+	cors := s.config.Server.CORS
+	if len(cors.AllowedOrigins) == 0 {
+		cors.AllowedOrigins = s.config.Security.AllowedOrigins
+	}
+	if len(cors.AllowedMethods) == 0 {
+		cors.AllowedMethods = defaultCORSMethods
+	}
+	if len(cors.AllowedHeaders) == 0 {
+		cors.AllowedHeaders = defaultCORSHeaders
+	}
+	return cors
+}
+
+// allowOrigin returns the Access-Control-Allow-Origin value for origin, or ""
+// when the origin is not allowed. A wildcard is echoed back as the origin
+// when credentials are allowed, since browsers reject "*" with credentials.
+func allowOrigin(cors config.CORSConfig, origin string) string {
+	for _, allowed := range cors.AllowedOrigins {
+		switch {
+		case allowed == "*" && cors.AllowCredentials:
+			return origin
+		case allowed == "*":
+			return "*"
+		case strings.EqualFold(allowed, origin):
+			return origin
+		}
+	}
+	return ""
+}
+
+// corsMiddleware applies the CORS policy. It runs outside authMiddleware so
+// preflight requests are answered without credentials and error responses
+// still carry CORS headers the browser can read.
+func (s *Server) corsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cors := s.corsConfig()
+		w.Header().Add("Vary", "Origin")
+		allowed := allowOrigin(cors, origin)
+		if allowed != "" {
+			w.Header().Set("Access-Control-Allow-Origin", allowed)
+			if cors.AllowCredentials {
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+		}
+
+		// Handle preflight
+		if r.Method != http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Add("Vary", "Access-Control-Request-Method")
+		w.Header().Add("Vary", "Access-Control-Request-Headers")
+		if allowed != "" && corsMethodAllowed(cors, r.Header.Get("Access-Control-Request-Method")) {
+			w.Header().Set("Access-Control-Allow-Methods", strings.Join(cors.AllowedMethods, ", "))
+			w.Header().Set("Access-Control-Allow-Headers", strings.Join(cors.AllowedHeaders, ", "))
+			if cors.MaxAge > 0 {
+				w.Header().Set("Access-Control-Max-Age", strconv.Itoa(cors.MaxAge))
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// corsMethodAllowed reports whether a preflight's requested method is allowed.
+// A preflight without Access-Control-Request-Method is treated as allowed.
+func corsMethodAllowed(cors config.CORSConfig, method string) bool {
+	if method == "" {
+		return true
+	}
+	for _, m := range cors.AllowedMethods {
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+	return false
+}
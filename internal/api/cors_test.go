@@ -0,0 +1,96 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jordanhubbard/loom/internal/auth"
+	"github.com/jordanhubbard/loom/pkg/config"
+)
+
+func preflight(handler http.Handler, origin, method string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodOptions, "/api/v1/beads", nil)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", method)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w
+}
+
+func TestCORSMiddleware_ServerConfig(t *testing.T) {
+	cfg := &config.Config{
+		Server: config.ServerConfig{CORS: config.CORSConfig{
+			AllowedOrigins:   []string{"https://dash.example.com"},
+			AllowedMethods:   []string{"GET", "POST"},
+			AllowedHeaders:   []string{"Authorization"},
+			AllowCredentials: true,
+			MaxAge:           600,
+		}},
+		Security: config.SecurityConfig{AllowedOrigins: []string{"*"}},
+	}
+	s := &Server{config: cfg}
+	handler := s.corsMiddleware(http.NotFoundHandler())
+
+	w := preflight(handler, "https://dash.example.com", "POST")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	h := w.Header()
+	if h.Get("Access-Control-Allow-Origin") != "https://dash.example.com" ||
+		h.Get("Access-Control-Allow-Credentials") != "true" ||
+		h.Get("Access-Control-Allow-Methods") != "GET, POST" ||
+		h.Get("Access-Control-Allow-Headers") != "Authorization" ||
+		h.Get("Access-Control-Max-Age") != "600" {
+		t.Errorf("unexpected preflight headers %v", h)
+	}
+
+	// server.cors overrides the legacy wildcard.
+	if w := preflight(handler, "https://other.example.com", "GET"); w.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Error("origin outside server.cors should not be allowed")
+	}
+	if w := preflight(handler, "https://dash.example.com", "DELETE"); w.Header().Get("Access-Control-Allow-Methods") != "" {
+		t.Error("disallowed method should not get preflight approval")
+	}
+}
+
+func TestCORSMiddleware_WildcardWithCredentialsEchoesOrigin(t *testing.T) {
+	cfg := &config.Config{Server: config.ServerConfig{CORS: config.CORSConfig{
+		AllowedOrigins:   []string{"*"},
+		AllowCredentials: true,
+	}}}
+	s := &Server{config: cfg}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/beads", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	w := httptest.NewRecorder()
+	s.corsMiddleware(http.NotFoundHandler()).ServeHTTP(w, req)
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+		t.Errorf("expected origin to be echoed, got %q", got)
+	}
+}
+
+func TestSetupRoutes_PreflightSkipsAuth(t *testing.T) {
+	cfg := &config.Config{
+		Server:   config.ServerConfig{CORS: config.CORSConfig{AllowedOrigins: []string{"https://dash.example.com"}}},
+		Security: config.SecurityConfig{EnableAuth: true},
+	}
+	handler := NewServer(nil, nil, auth.NewManager("test-secret"), cfg).SetupRoutes()
+
+	w := preflight(handler, "https://dash.example.com", "POST")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected preflight to succeed without credentials, got %d", w.Code)
+	}
+
+	// A rejected request still carries CORS headers so the browser can read it.
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/beads", nil)
+	req.Header.Set("Origin", "https://dash.example.com")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", w.Code)
+	}
+	if w.Header().Get("Access-Control-Allow-Origin") != "https://dash.example.com" {
+		t.Error("expected CORS headers on the 401 response")
+	}
+}
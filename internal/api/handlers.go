@@ -2,6 +2,8 @@ package api
 
 import (
 	"context"
+	"errors"
+	"github.com/jordanhubbard/loom/internal/provider"
 	"github.com/jordanhubbard/loom/pkg/models"
 	"net/http"
 	"strings"
@@ -82,6 +84,14 @@ func (s *Server) handlePersona(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// createAgentRequest is the body of POST /api/v1/agents.
+type createAgentRequest struct {
+	Name        string `json:"name"`
+	PersonaName string `json:"persona_name"`
+	ProjectID   string `json:"project_id"`
+	ProviderID  string `json:"provider_id"`
+}
+
 // handleAgents handles GET/POST /api/v1/agents
 func (s *Server) handleAgents(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
@@ -90,12 +100,7 @@ func (s *Server) handleAgents(w http.ResponseWriter, r *http.Request) {
 		s.respondJSON(w, http.StatusOK, agents)
 
 	case http.MethodPost:
-		var req struct {
-			Name        string `json:"name"`
-			PersonaName string `json:"persona_name"`
-			ProjectID   string `json:"project_id"`
-			ProviderID  string `json:"provider_id"`
-		}
+		var req createAgentRequest
 		if err := s.parseJSON(r, &req); err != nil {
 			s.respondError(w, http.StatusBadRequest, "Invalid request body")
 			return
@@ -113,6 +118,11 @@ func (s *Server) handleAgents(w http.ResponseWriter, r *http.Request) {
 		}
 
 		agent, err := s.app.SpawnAgent(context.Background(), req.Name, personaName, req.ProjectID, req.ProviderID)
+		var notAvailable *provider.ModelNotAvailableError
+		if errors.As(err, &notAvailable) {
+			s.respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		if err != nil {
 			s.respondError(w, http.StatusInternalServerError, err.Error())
 			return
@@ -148,8 +158,9 @@ func (s *Server) handleAgent(w http.ResponseWriter, r *http.Request) {
 
 	case http.MethodPut:
 		var req struct {
-			Name    string          `json:"name"`
-			Persona *models.Persona `json:"persona"`
+			Name          string          `json:"name"`
+			Persona       *models.Persona `json:"persona"`
+			MaxConcurrent *int            `json:"max_concurrent"`
 		}
 		if err := s.parseJSON(r, &req); err != nil {
 			s.respondError(w, http.StatusBadRequest, "Invalid request body")
@@ -185,6 +196,13 @@ func (s *Server) handleAgent(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 
+		if req.MaxConcurrent != nil {
+			if err := s.app.GetAgentManager().SetAgentMaxConcurrent(id, *req.MaxConcurrent); err != nil {
+				s.respondError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+
 		// Write-through cache: Persist to database
 		// Note: agent is already updated in-memory since GetAgent returns a pointer to the cached object
 		if s.app.GetDatabase() != nil {
@@ -212,11 +230,31 @@ func (s *Server) handleAgentAction(w http.ResponseWriter, r *http.Request, id, a
 	switch action {
 	case "clone":
 		s.handleCloneAgent(w, r, id)
+	case "history":
+		s.handleAgentHistory(w, r, id)
+	case "logs":
+		s.handleAgentLogs(w, r, id)
 	default:
 		s.respondError(w, http.StatusNotFound, "Unknown action")
 	}
 }
 
+// handleAgentHistory handles GET /api/v1/agents/{id}/history
+func (s *Server) handleAgentHistory(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != http.MethodGet {
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	manager := s.app.GetAgentManager()
+	if _, err := manager.GetAgent(id); err != nil {
+		s.respondError(w, http.StatusNotFound, "Agent not found")
+		return
+	}
+
+	s.respondJSON(w, http.StatusOK, manager.GetAgentHistory(id))
+}
+
 func (s *Server) handleCloneAgent(w http.ResponseWriter, r *http.Request, id string) {
 	if r.Method != http.MethodPost {
 		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
@@ -252,6 +290,16 @@ func (s *Server) handleCloneAgent(w http.ResponseWriter, r *http.Request, id str
 	s.respondJSON(w, http.StatusCreated, agent)
 }
 
+// createProjectRequest is the body of POST /api/v1/projects.
+type createProjectRequest struct {
+	Name      string            `json:"name"`
+	GitRepo   string            `json:"git_repo"`
+	Branch    string            `json:"branch"`
+	BeadsPath string            `json:"beads_path"`
+	Context   map[string]string `json:"context"`
+	IsSticky  *bool             `json:"is_sticky"`
+}
+
 // handleProjects handles GET/POST /api/v1/projects
 func (s *Server) handleProjects(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
@@ -260,14 +308,7 @@ func (s *Server) handleProjects(w http.ResponseWriter, r *http.Request) {
 		s.respondJSON(w, http.StatusOK, projects)
 
 	case http.MethodPost:
-		var req struct {
-			Name      string            `json:"name"`
-			GitRepo   string            `json:"git_repo"`
-			Branch    string            `json:"branch"`
-			BeadsPath string            `json:"beads_path"`
-			Context   map[string]string `json:"context"`
-			IsSticky  *bool             `json:"is_sticky"`
-		}
+		var req createProjectRequest
 		if err := s.parseJSON(r, &req); err != nil {
 			s.respondError(w, http.StatusBadRequest, "Invalid request body")
 			return
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jordanhubbard/loom/internal/agent"
+)
+
+// defaultAgentLogLimit is how many recent lines an agent log request returns
+// when it does not set limit.
+const defaultAgentLogLimit = 100
+
+// agentLogsKeepalive is how often an idle agent log stream sends a comment so
+// proxies keep the connection open.
+var agentLogsKeepalive = 30 * time.Second
+
+// handleAgentLogs handles GET /api/v1/agents/{id}/logs. It returns the
+// agent's recent log lines, oldest first; with ?follow=true it sends them as
+// server-sent events and keeps streaming new lines until the client
+// disconnects or the agent is removed.
+func (s *Server) handleAgentLogs(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != http.MethodGet {
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	limit := defaultAgentLogLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			s.respondError(w, http.StatusBadRequest, "limit must be a non-negative integer")
+			return
+		}
+		limit = n
+	}
+
+	manager := s.app.GetAgentManager()
+	if _, err := manager.GetAgent(id); err != nil {
+		s.respondError(w, http.StatusNotFound, "Agent not found")
+		return
+	}
+
+	if r.URL.Query().Get("follow") != "true" {
+		s.respondJSON(w, http.StatusOK, manager.GetAgentLogs(id, limit))
+		return
+	}
+	s.streamAgentLogs(w, r, manager, id, limit)
+}
+
+func (s *Server) streamAgentLogs(w http.ResponseWriter, r *http.Request, manager *agent.WorkerManager, id string, limit int) {
+	// Disable write timeout for SSE - the server's WriteTimeout would kill
+	// long-running streams.
+	rc := http.NewResponseController(w)
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	backlog, lines, unsubscribe := manager.SubscribeAgentLogs(id, limit)
+	defer unsubscribe()
+
+	flush := func() {
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+	}
+	send := func(line agent.AgentLogLine) {
+		data, err := json.Marshal(line)
+		if err != nil {
+			return
+		}
+		fmt.Fprintf(w, "event: log\ndata: %s\n\n", data)
+	}
+
+	for _, line := range backlog {
+		send(line)
+	}
+	flush()
+
+	keepalive := time.NewTicker(agentLogsKeepalive)
+	defer keepalive.Stop()
+
+	ctx := r.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			// Client disconnected
+			return
+		case line, ok := <-lines:
+			if !ok {
+				// Agent removed
+				return
+			}
+			send(line)
+			flush()
+		case <-keepalive.C:
+			fmt.Fprintf(w, ": keepalive\n\n")
+			flush()
+		}
+	}
+}
@@ -12,7 +12,15 @@ import (
 	"github.com/jordanhubbard/loom/internal/auth"
 )
 
-// handleGetLogs handles GET /api/v1/analytics/logs
+// Page size bounds for GET /api/v1/analytics/logs
+const (
+	defaultLogsPageSize = 100
+	maxLogsPageSize     = 1000
+)
+
+// handleGetLogs handles GET /api/v1/analytics/logs. Results are paged with
+// the limit and offset query params; X-Total-Count carries the number of
+// matching logs across all pages.
 func (s *Server) handleGetLogs(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -29,13 +37,42 @@ func (s *Server) handleGetLogs(w http.ResponseWriter, r *http.Request) {
 	// Parse query parameters
 	filter := &analytics.LogFilter{
 		UserID: userID, // Users can only see their own logs (or all if auth disabled)
-		Limit:  100,    // Default limit
+		Limit:  defaultLogsPageSize,
+	}
+
+	if limitParam := r.URL.Query().Get("limit"); limitParam != "" {
+		parsed, err := strconv.Atoi(limitParam)
+		if err != nil || parsed <= 0 {
+			http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		if parsed > maxLogsPageSize {
+			parsed = maxLogsPageSize
+		}
+		filter.Limit = parsed
+	}
+
+	if offsetParam := r.URL.Query().Get("offset"); offsetParam != "" {
+		parsed, err := strconv.Atoi(offsetParam)
+		if err != nil || parsed < 0 {
+			http.Error(w, "offset must be a non-negative integer", http.StatusBadRequest)
+			return
+		}
+		filter.Offset = parsed
 	}
 
 	if providerID := r.URL.Query().Get("provider_id"); providerID != "" {
 		filter.ProviderID = providerID
 	}
 
+	if projectID := r.URL.Query().Get("project_id"); projectID != "" {
+		filter.ProjectID = projectID
+	}
+
+	if traceID := r.URL.Query().Get("trace_id"); traceID != "" {
+		filter.TraceID = traceID
+	}
+
 	if startTime := r.URL.Query().Get("start_time"); startTime != "" {
 		if t, err := time.Parse(time.RFC3339, startTime); err == nil {
 			filter.StartTime = t
@@ -48,13 +85,17 @@ func (s *Server) handleGetLogs(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	logs, err := s.analyticsLogger.GetLogs(r.Context(), filter)
+	logs, total, err := s.analyticsLogger.GetLogsPage(r.Context(), filter)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if logs == nil {
+		logs = []*analytics.RequestLog{}
+	}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Total-Count", strconv.FormatInt(total, 10))
 	if err := json.NewEncoder(w).Encode(logs); err != nil {
 		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 	}
@@ -79,6 +120,14 @@ func (s *Server) handleGetLogStats(w http.ResponseWriter, r *http.Request) {
 		UserID: userID, // Users can only see their own stats (or all if auth disabled)
 	}
 
+	if projectID := r.URL.Query().Get("project_id"); projectID != "" {
+		filter.ProjectID = projectID
+	}
+
+	if traceID := r.URL.Query().Get("trace_id"); traceID != "" {
+		filter.TraceID = traceID
+	}
+
 	if startTime := r.URL.Query().Get("start_time"); startTime != "" {
 		if t, err := time.Parse(time.RFC3339, startTime); err == nil {
 			filter.StartTime = t
@@ -106,10 +155,7 @@ func (s *Server) handleGetLogStats(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(stats); err != nil {
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
-	}
+	s.respondJSONWithETag(w, r, stats)
 }
 
 // handleExportLogs handles GET /api/v1/analytics/export
@@ -136,6 +182,14 @@ func (s *Server) handleExportLogs(w http.ResponseWriter, r *http.Request) {
 		filter.ProviderID = providerID
 	}
 
+	if projectID := r.URL.Query().Get("project_id"); projectID != "" {
+		filter.ProjectID = projectID
+	}
+
+	if traceID := r.URL.Query().Get("trace_id"); traceID != "" {
+		filter.TraceID = traceID
+	}
+
 	if startTime := r.URL.Query().Get("start_time"); startTime != "" {
 		if t, err := time.Parse(time.RFC3339, startTime); err == nil {
 			filter.StartTime = t
@@ -154,11 +208,15 @@ func (s *Server) handleExportLogs(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	logs = s.analyticsLogger.RedactForExport(logs)
+
 	// Default to JSON export
 	format := r.URL.Query().Get("format")
 	switch format {
 	case "csv":
 		exportLogsAsCSV(w, logs)
+	case "jsonl":
+		exportLogsAsJSONL(w, logs)
 	default:
 		w.Header().Set("Content-Type", "application/json")
 		w.Header().Set("Content-Disposition", "attachment; filename=\"logs.json\"")
@@ -187,6 +245,10 @@ func (s *Server) handleGetCostReport(w http.ResponseWriter, r *http.Request) {
 		UserID: userID, // Users can only see their own costs by default (or all if auth disabled)
 	}
 
+	if projectID := r.URL.Query().Get("project_id"); projectID != "" {
+		filter.ProjectID = projectID
+	}
+
 	if startTime := r.URL.Query().Get("start_time"); startTime != "" {
 		if t, err := time.Parse(time.RFC3339, startTime); err == nil {
 			filter.StartTime = t
@@ -233,6 +295,9 @@ func (s *Server) handleGetCostReport(w http.ResponseWriter, r *http.Request) {
 		}(),
 		"cost_by_provider": stats.CostByProvider,
 		"cost_by_user":     stats.CostByUser,
+		"cost_by_project":  stats.CostByProject,
+		"cost_by_model":    stats.CostByModel,
+		"tokens_by_model":  stats.TokensByModel,
 		"time_range": map[string]interface{}{
 			"start": filter.StartTime,
 			"end":   filter.EndTime,
@@ -245,6 +310,67 @@ func (s *Server) handleGetCostReport(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// handleCostForecast handles GET /api/v1/analytics/forecast
+func (s *Server) handleCostForecast(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if s.analyticsLogger == nil {
+		http.Error(w, "Analytics unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	userID := auth.GetUserIDFromRequest(r)
+	if userID == "" && s.config.Security.EnableAuth {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// horizon is the number of days of history used to fit the trend
+	horizon := 30
+	if horizonParam := r.URL.Query().Get("horizon"); horizonParam != "" {
+		parsed, err := strconv.Atoi(horizonParam)
+		if err != nil || parsed <= 0 {
+			http.Error(w, "horizon must be a positive number of days", http.StatusBadRequest)
+			return
+		}
+		horizon = parsed
+	}
+
+	filter := &analytics.LogFilter{
+		UserID: userID,
+	}
+
+	if projectID := r.URL.Query().Get("project_id"); projectID != "" {
+		filter.ProjectID = projectID
+	}
+
+	if providerID := r.URL.Query().Get("provider_id"); providerID != "" {
+		filter.ProviderID = providerID
+	}
+
+	role := auth.GetRoleFromRequest(r)
+	if role == "admin" {
+		filter.UserID = ""
+		if queryUserID := r.URL.Query().Get("user_id"); queryUserID != "" {
+			filter.UserID = queryUserID
+		}
+	}
+
+	forecast, err := s.analyticsLogger.ForecastCost(r.Context(), filter, horizon)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(forecast); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
 // handleGetBatchingRecommendations handles GET /api/v1/analytics/batching
 func (s *Server) handleGetBatchingRecommendations(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -272,6 +398,10 @@ func (s *Server) handleGetBatchingRecommendations(w http.ResponseWriter, r *http
 		filter.ProviderID = providerID
 	}
 
+	if projectID := r.URL.Query().Get("project_id"); projectID != "" {
+		filter.ProjectID = projectID
+	}
+
 	if startTime := r.URL.Query().Get("start_time"); startTime != "" {
 		if t, err := time.Parse(time.RFC3339, startTime); err == nil {
 			filter.StartTime = t
@@ -329,6 +459,103 @@ func (s *Server) handleGetBatchingRecommendations(w http.ResponseWriter, r *http
 	}
 }
 
+// executeBatchPlanRequest is the body of POST /api/v1/analytics/batching/execute.
+// Each request needs a prompt: the log holds at most a raw request body,
+// which is not a prompt that can be replayed.
+type executeBatchPlanRequest struct {
+	ProviderID string                     `json:"provider_id"`
+	Groups     []analytics.AutoBatchGroup `json:"groups"`
+	Prompts    map[string]string          `json:"prompts,omitempty"`
+}
+
+// handleExecuteBatchPlan handles POST /api/v1/analytics/batching/execute. It
+// runs each group of an auto-batch plan on a provider and reports the token
+// and cost savings realized against the logged requests.
+func (s *Server) handleExecuteBatchPlan(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if s.analyticsLogger == nil {
+		http.Error(w, "Analytics unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	userID := auth.GetUserIDFromRequest(r)
+	if userID == "" && s.config.Security.EnableAuth {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req executeBatchPlanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.ProviderID == "" || len(req.Groups) == 0 {
+		http.Error(w, "provider_id and groups are required", http.StatusBadRequest)
+		return
+	}
+
+	filter := &analytics.LogFilter{UserID: userID, Limit: 10000}
+	if auth.GetRoleFromRequest(r) == "admin" {
+		filter.UserID = ""
+	}
+	logs, err := s.analyticsLogger.GetLogs(r.Context(), filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	logsByID := make(map[string]*analytics.RequestLog, len(logs))
+	for _, log := range logs {
+		logsByID[log.ID] = log
+	}
+
+	groups := make([][]analytics.BatchRequest, len(req.Groups))
+	for i, group := range req.Groups {
+		for _, id := range group.RequestIDs {
+			log, ok := logsByID[id]
+			if !ok {
+				http.Error(w, fmt.Sprintf("request %s not found in logs", id), http.StatusBadRequest)
+				return
+			}
+			prompt := req.Prompts[id]
+			if prompt == "" {
+				http.Error(w, fmt.Sprintf("no prompt for request %s", id), http.StatusBadRequest)
+				return
+			}
+			groups[i] = append(groups[i], analytics.BatchRequest{
+				ID:              id,
+				Prompt:          prompt,
+				BaselineTokens:  log.TotalTokens,
+				BaselineCostUSD: log.CostUSD,
+			})
+		}
+	}
+
+	if s.app == nil {
+		http.Error(w, "Providers unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	registry := s.app.GetProviderRegistry()
+	p, err := registry.Get(req.ProviderID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	result := &analytics.BatchPlanResult{}
+	for i, group := range req.Groups {
+		result.Add(analytics.ExecuteBatchGroup(r.Context(), registry, p, group.ID, groups[i]))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
 // handleExportStats handles GET /api/v1/analytics/export-stats
 func (s *Server) handleExportStats(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -348,6 +575,10 @@ func (s *Server) handleExportStats(w http.ResponseWriter, r *http.Request) {
 		UserID: userID,
 	}
 
+	if projectID := r.URL.Query().Get("project_id"); projectID != "" {
+		filter.ProjectID = projectID
+	}
+
 	if startTime := r.URL.Query().Get("start_time"); startTime != "" {
 		if t, err := time.Parse(time.RFC3339, startTime); err == nil {
 			filter.StartTime = t
@@ -395,10 +626,15 @@ func (s *Server) handleExportStats(w http.ResponseWriter, r *http.Request) {
 				"total_tokens":   stats.TotalTokens,
 				"total_cost_usd": stats.TotalCostUSD,
 				"avg_latency_ms": stats.AvgLatencyMs,
+				"p50_latency_ms": stats.P50LatencyMs,
+				"p95_latency_ms": stats.P95LatencyMs,
+				"p99_latency_ms": stats.P99LatencyMs,
 				"error_rate":     stats.ErrorRate,
 			},
 			"cost_by_provider":     stats.CostByProvider,
 			"cost_by_user":         stats.CostByUser,
+			"cost_by_project":      stats.CostByProject,
+			"cost_by_model":        stats.CostByModel,
 			"requests_by_provider": stats.RequestsByProvider,
 			"requests_by_user":     stats.RequestsByUser,
 		}); err != nil {
@@ -422,6 +658,9 @@ func exportStatsAsCSV(w http.ResponseWriter, stats *analytics.LogStats, filter *
 	_ = writer.Write([]string{"Total Tokens", fmt.Sprintf("%d", stats.TotalTokens), "", ""})
 	_ = writer.Write([]string{"Total Cost (USD)", fmt.Sprintf("%.4f", stats.TotalCostUSD), "", ""})
 	_ = writer.Write([]string{"Avg Latency (ms)", fmt.Sprintf("%.2f", stats.AvgLatencyMs), "", ""})
+	_ = writer.Write([]string{"P50 Latency (ms)", fmt.Sprintf("%.2f", stats.P50LatencyMs), "", ""})
+	_ = writer.Write([]string{"P95 Latency (ms)", fmt.Sprintf("%.2f", stats.P95LatencyMs), "", ""})
+	_ = writer.Write([]string{"P99 Latency (ms)", fmt.Sprintf("%.2f", stats.P99LatencyMs), "", ""})
 	_ = writer.Write([]string{"Error Rate", fmt.Sprintf("%.2f%%", stats.ErrorRate*100), "", ""})
 	_ = writer.Write([]string{""})
 
@@ -441,6 +680,18 @@ func exportStatsAsCSV(w http.ResponseWriter, stats *analytics.LogStats, filter *
 		requests := stats.RequestsByUser[user]
 		_ = writer.Write([]string{user, fmt.Sprintf("%d", requests), fmt.Sprintf("%.4f", cost), ""})
 	}
+	_ = writer.Write([]string{""})
+
+	// Cost by Model
+	_ = writer.Write([]string{"Cost by Model", "", "", ""})
+	_ = writer.Write([]string{"Model", "Tokens", "Cost (USD)", "Share of Cost"})
+	for model, cost := range stats.CostByModel {
+		share := 0.0
+		if stats.TotalCostUSD > 0 {
+			share = cost / stats.TotalCostUSD * 100
+		}
+		_ = writer.Write([]string{model, fmt.Sprintf("%d", stats.TokensByModel[model]), fmt.Sprintf("%.4f", cost), fmt.Sprintf("%.2f%%", share)})
+	}
 }
 
 // exportLogsAsCSV exports logs in CSV format
@@ -488,6 +739,20 @@ func exportLogsAsCSV(w http.ResponseWriter, logs []*analytics.RequestLog) {
 	}
 }
 
+// exportLogsAsJSONL exports logs as newline-delimited JSON, one log per line
+func exportLogsAsJSONL(w http.ResponseWriter, logs []*analytics.RequestLog) {
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Content-Disposition", "attachment; filename=\"loom-logs-"+time.Now().Format("2006-01-02")+".jsonl\"")
+
+	// Encode appends a newline after each value
+	encoder := json.NewEncoder(w)
+	for _, log := range logs {
+		if err := encoder.Encode(log); err != nil {
+			return
+		}
+	}
+}
+
 // handleGetChangeVelocity handles GET /api/v1/analytics/change-velocity
 func (s *Server) handleGetChangeVelocity(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
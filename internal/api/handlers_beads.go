@@ -6,9 +6,24 @@ import (
 	"strings"
 	"time"
 
+	"github.com/jordanhubbard/loom/internal/auth"
 	"github.com/jordanhubbard/loom/pkg/models"
 )
 
+// createBeadRequest is the body of POST /api/v1/beads.
+type createBeadRequest struct {
+	Type               string            `json:"type"`
+	Title              string            `json:"title"`
+	Description        string            `json:"description"`
+	Priority           *int              `json:"priority"` // Nil means 2 (P2)
+	ProjectID          string            `json:"project_id"`
+	Parent             string            `json:"parent"`
+	Tags               []string          `json:"tags"`
+	Context            map[string]string `json:"context"`
+	IdempotencyKey     string            `json:"idempotency_key"`     // Retries with the same key return the original bead
+	RequiredCapability string            `json:"required_capability"` // Only agents whose persona lists it may take the bead
+}
+
 // handleBeads handles GET/POST /api/v1/beads
 func (s *Server) handleBeads(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
@@ -47,7 +62,13 @@ func (s *Server) handleBeads(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 
-		beads, err := s.app.GetBeadsManager().ListBeads(filters)
+		var beads []*models.Bead
+		var err error
+		if query := strings.TrimSpace(r.URL.Query().Get("q")); query != "" {
+			beads, err = s.app.GetBeadsManager().SearchBeads(query, filters)
+		} else {
+			beads, err = s.app.GetBeadsManager().ListBeads(filters)
+		}
 		if err != nil {
 			s.respondError(w, http.StatusInternalServerError, err.Error())
 			return
@@ -56,16 +77,7 @@ func (s *Server) handleBeads(w http.ResponseWriter, r *http.Request) {
 		s.respondJSON(w, http.StatusOK, beads)
 
 	case http.MethodPost:
-		var req struct {
-			Type        string            `json:"type"`
-			Title       string            `json:"title"`
-			Description string            `json:"description"`
-			Priority    int               `json:"priority"`
-			ProjectID   string            `json:"project_id"`
-			Parent      string            `json:"parent"`
-			Tags        []string          `json:"tags"`
-			Context     map[string]string `json:"context"`
-		}
+		var req createBeadRequest
 		if err := s.parseJSON(r, &req); err != nil {
 			s.respondError(w, http.StatusBadRequest, "Invalid request body")
 			return
@@ -79,15 +91,23 @@ func (s *Server) handleBeads(w http.ResponseWriter, r *http.Request) {
 		if req.Type == "" {
 			req.Type = "task"
 		}
-		if req.Priority == 0 {
-			req.Priority = 2
+		priority := 2
+		if req.Priority != nil {
+			priority = *req.Priority
 		}
 
-		bead, err := s.app.CreateBead(req.Title, req.Description, models.BeadPriority(req.Priority), req.Type, req.ProjectID)
+		bead, err := s.app.CreateBeadIdempotent(req.IdempotencyKey, req.Title, req.Description, models.BeadPriority(priority), req.Type, req.ProjectID)
 		if err != nil {
 			s.respondError(w, http.StatusInternalServerError, err.Error())
 			return
 		}
+		if capability := strings.TrimSpace(req.RequiredCapability); capability != "" && bead.RequiredCapability != capability {
+			bead, err = s.app.UpdateBead(bead.ID, map[string]interface{}{"required_capability": capability})
+			if err != nil {
+				s.respondError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+		}
 
 		s.respondJSON(w, http.StatusCreated, bead)
 
@@ -96,6 +116,38 @@ func (s *Server) handleBeads(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// claimBeadRequest is the body of POST /api/v1/beads/{id}/claim.
+type claimBeadRequest struct {
+	AgentID string `json:"agent_id"`
+}
+
+// claimBeadsRequest is the body of POST /api/v1/beads/claim.
+type claimBeadsRequest struct {
+	AgentID string   `json:"agent_id"`
+	BeadIDs []string `json:"bead_ids"`
+}
+
+// updateBeadRequest is the body of PATCH /api/v1/beads/{id}. Nil fields are
+// left unchanged.
+type updateBeadRequest struct {
+	Title       *string           `json:"title"`
+	Type        *string           `json:"type"`
+	Status      *string           `json:"status"`
+	Priority    *int              `json:"priority"`
+	ProjectID   *string           `json:"project_id"`
+	AssignedTo  *string           `json:"assigned_to"`
+	Description *string           `json:"description"`
+	Parent      *string           `json:"parent"`
+	Tags        *[]string         `json:"tags"`
+	BlockedBy   *[]string         `json:"blocked_by"`
+	Blocks      *[]string         `json:"blocks"`
+	RelatedTo   *[]string         `json:"related_to"`
+	Children    *[]string         `json:"children"`
+	Context     map[string]string `json:"context"`
+
+	RequiredCapability *string `json:"required_capability"`
+}
+
 // handleBead handles GET/PATCH /api/v1/beads/{id} and POST /api/v1/beads/{id}/claim
 func (s *Server) handleBead(w http.ResponseWriter, r *http.Request) {
 	path := strings.TrimPrefix(r.URL.Path, "/api/v1/beads/")
@@ -121,9 +173,7 @@ func (s *Server) handleBead(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		var req struct {
-			AgentID string `json:"agent_id"`
-		}
+		var req claimBeadRequest
 		if err := s.parseJSON(r, &req); err != nil {
 			s.respondError(w, http.StatusBadRequest, "Invalid request body")
 			return
@@ -177,6 +227,33 @@ func (s *Server) handleBead(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Handle /cancel endpoint: stop the running task and reopen the bead
+	if len(parts) > 1 && parts[1] == "cancel" {
+		if r.Method != http.MethodPost {
+			s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+		if _, err := s.app.GetBeadsManager().GetBead(id); err != nil {
+			s.respondError(w, http.StatusNotFound, "Bead not found")
+			return
+		}
+
+		cancelledBy := auth.GetUsernameFromRequest(r)
+		if cancelledBy == "" {
+			cancelledBy = auth.GetUserIDFromRequest(r)
+		}
+		bead, taskCancelled, err := s.app.CancelBead(id, cancelledBy)
+		if err != nil {
+			s.respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		s.respondJSON(w, http.StatusOK, map[string]interface{}{
+			"bead":           bead,
+			"task_cancelled": taskCancelled,
+		})
+		return
+	}
+
 	// Handle /escalate endpoint (human-in-the-loop)
 	if len(parts) > 1 && parts[1] == "escalate" {
 		if r.Method != http.MethodPost {
@@ -210,22 +287,7 @@ func (s *Server) handleBead(w http.ResponseWriter, r *http.Request) {
 		s.respondJSON(w, http.StatusOK, bead)
 
 	case http.MethodPatch:
-		var req struct {
-			Title       *string           `json:"title"`
-			Type        *string           `json:"type"`
-			Status      *string           `json:"status"`
-			Priority    *int              `json:"priority"`
-			ProjectID   *string           `json:"project_id"`
-			AssignedTo  *string           `json:"assigned_to"`
-			Description *string           `json:"description"`
-			Parent      *string           `json:"parent"`
-			Tags        *[]string         `json:"tags"`
-			BlockedBy   *[]string         `json:"blocked_by"`
-			Blocks      *[]string         `json:"blocks"`
-			RelatedTo   *[]string         `json:"related_to"`
-			Children    *[]string         `json:"children"`
-			Context     map[string]string `json:"context"`
-		}
+		var req updateBeadRequest
 		if err := s.parseJSON(r, &req); err != nil {
 			s.respondError(w, http.StatusBadRequest, "Invalid request body")
 			return
@@ -274,6 +336,9 @@ func (s *Server) handleBead(w http.ResponseWriter, r *http.Request) {
 		if req.Context != nil {
 			updates["context"] = req.Context
 		}
+		if req.RequiredCapability != nil {
+			updates["required_capability"] = *req.RequiredCapability
+		}
 
 		bead, err := s.app.UpdateBead(id, updates)
 		if err != nil {
@@ -287,6 +352,41 @@ func (s *Server) handleBead(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// handleClaimBeads handles POST /api/v1/beads/claim. Partial success is
+// still a 200; the result lists which beads were claimed and which were not.
+func (s *Server) handleClaimBeads(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req claimBeadsRequest
+	if err := s.parseJSON(r, &req); err != nil {
+		s.respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.AgentID == "" {
+		s.respondError(w, http.StatusBadRequest, "agent_id is required")
+		return
+	}
+	if len(req.BeadIDs) == 0 {
+		s.respondError(w, http.StatusBadRequest, "bead_ids is required")
+		return
+	}
+
+	result, err := s.app.ClaimBeads(req.BeadIDs, req.AgentID)
+	if err != nil {
+		if strings.Contains(err.Error(), "agent not found") {
+			s.respondError(w, http.StatusNotFound, err.Error())
+		} else {
+			s.respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	s.respondJSON(w, http.StatusOK, result)
+}
+
 // handleDecisions handles GET /api/v1/decisions
 func (s *Server) handleDecisions(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -459,11 +559,26 @@ func (s *Server) handleWorkGraph(w http.ResponseWriter, r *http.Request) {
 
 	projectID := r.URL.Query().Get("project_id")
 
+	if sinceParam := r.URL.Query().Get("since"); sinceParam != "" {
+		since, err := time.Parse(time.RFC3339, sinceParam)
+		if err != nil {
+			s.respondError(w, http.StatusBadRequest, "since must be an RFC 3339 timestamp")
+			return
+		}
+		delta, err := s.app.GetWorkGraphSince(projectID, since)
+		if err != nil {
+			s.respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		s.respondJSONWithETag(w, r, delta)
+		return
+	}
+
 	graph, err := s.app.GetWorkGraph(projectID)
 	if err != nil {
 		s.respondError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	s.respondJSON(w, http.StatusOK, graph)
+	s.respondJSONWithETag(w, r, graph)
 }
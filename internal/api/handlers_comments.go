@@ -2,13 +2,16 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
+
+	"github.com/jordanhubbard/loom/internal/comments"
 )
 
 // handleBeadComments handles comment operations for a specific bead
-// GET /api/v1/beads/{id}/comments - Get all comments
+// GET /api/v1/beads/{id}/comments - Get comments as threads (flat=true for a flat list)
 // POST /api/v1/beads/{id}/comments - Create comment
 func (s *Server) handleBeadComments(w http.ResponseWriter, r *http.Request) {
 	commentsMgr := s.app.GetCommentsManager()
@@ -37,20 +40,15 @@ func (s *Server) handleBeadComments(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// handleGetComments retrieves all comments for a bead
-func (s *Server) handleGetComments(w http.ResponseWriter, r *http.Request, beadID string, commentsMgr interface{}) {
-	// Type assert to access GetComments method
-	type CommentsGetter interface {
-		GetComments(beadID string) (interface{}, error)
-	}
-
-	getter, ok := commentsMgr.(CommentsGetter)
-	if !ok {
-		s.respondError(w, http.StatusInternalServerError, "Invalid comments manager")
-		return
+// handleGetComments retrieves all comments for a bead, threaded unless the
+// flat query parameter is true
+func (s *Server) handleGetComments(w http.ResponseWriter, r *http.Request, beadID string, commentsMgr *comments.Manager) {
+	getComments := commentsMgr.GetComments
+	if r.URL.Query().Get("flat") == "true" {
+		getComments = commentsMgr.GetCommentsFlat
 	}
 
-	comments, err := getter.GetComments(beadID)
+	comments, err := getComments(beadID)
 	if err != nil {
 		s.respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get comments: %v", err))
 		return
@@ -63,7 +61,7 @@ func (s *Server) handleGetComments(w http.ResponseWriter, r *http.Request, beadI
 }
 
 // handleCreateComment creates a new comment
-func (s *Server) handleCreateComment(w http.ResponseWriter, r *http.Request, beadID string, commentsMgr interface{}) {
+func (s *Server) handleCreateComment(w http.ResponseWriter, r *http.Request, beadID string, commentsMgr *comments.Manager) {
 	// Get user from context
 	user := s.getUserFromContext(r)
 	if user == nil {
@@ -87,18 +85,11 @@ func (s *Server) handleCreateComment(w http.ResponseWriter, r *http.Request, bea
 		return
 	}
 
-	// Type assert to access CreateComment method
-	type CommentCreator interface {
-		CreateComment(beadID, authorID, authorUsername, content, parentID string) (interface{}, error)
-	}
-
-	creator, ok := commentsMgr.(CommentCreator)
-	if !ok {
-		s.respondError(w, http.StatusInternalServerError, "Invalid comments manager")
+	comment, err := commentsMgr.CreateComment(beadID, user.ID, user.Username, req.Content, req.ParentID)
+	if errors.Is(err, comments.ErrInvalidParent) {
+		s.respondError(w, http.StatusBadRequest, err.Error())
 		return
 	}
-
-	comment, err := creator.CreateComment(beadID, user.ID, user.Username, req.Content, req.ParentID)
 	if err != nil {
 		s.respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to create comment: %v", err))
 		return
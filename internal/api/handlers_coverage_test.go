@@ -605,7 +605,7 @@ func TestCORSMiddleware_SpecificOrigin(t *testing.T) {
 	}
 }
 
-func TestCORSMiddleware_AlwaysSetsAllowedMethods(t *testing.T) {
+func TestCORSMiddleware_SameOriginByDefault(t *testing.T) {
 	cfg := &config.Config{}
 	s := &Server{config: cfg, apiFailureLast: make(map[string]time.Time)}
 
@@ -615,14 +615,15 @@ func TestCORSMiddleware_AlwaysSetsAllowedMethods(t *testing.T) {
 	handler := s.corsMiddleware(next)
 
 	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Origin", "http://example.com")
 	w := httptest.NewRecorder()
 	handler.ServeHTTP(w, req)
 
-	if w.Header().Get("Access-Control-Allow-Methods") == "" {
-		t.Error("expected Access-Control-Allow-Methods header")
+	if w.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Error("expected no Access-Control-Allow-Origin without configured origins")
 	}
-	if w.Header().Get("Access-Control-Allow-Headers") == "" {
-		t.Error("expected Access-Control-Allow-Headers header")
+	if w.Header().Get("Access-Control-Allow-Methods") != "" {
+		t.Error("expected no Access-Control-Allow-Methods on a simple request")
 	}
 }
 
@@ -650,6 +651,7 @@ func TestAuthMiddleware_SkipsHealthPaths(t *testing.T) {
 		"/api/v1/auth/refresh",
 		"/",
 		"/api/openapi.yaml",
+		"/api/v1/openapi.json",
 		"/api/v1/events/stream",
 		"/api/v1/chat/completions/stream",
 		"/api/v1/chat/completions",
@@ -1402,6 +1404,26 @@ func TestHandleGitStatus_MissingProjectID(t *testing.T) {
 	}
 }
 
+func TestHandleGitDiff_BadRequests(t *testing.T) {
+	s := newTestServer()
+	tests := []struct {
+		method string
+		url    string
+		want   int
+	}{
+		{http.MethodPost, "/api/v1/projects/git/diff?project_id=p1", http.StatusMethodNotAllowed},
+		{http.MethodGet, "/api/v1/projects/git/diff", http.StatusBadRequest},
+		{http.MethodGet, "/api/v1/projects/git/diff?project_id=p1&format=html", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		s.handleGitDiff(w, httptest.NewRequest(tt.method, tt.url, nil))
+		if w.Code != tt.want {
+			t.Errorf("%s %s: expected %d, got %d", tt.method, tt.url, tt.want, w.Code)
+		}
+	}
+}
+
 // ============================================================
 // Logs handler method checks
 // ============================================================
@@ -462,6 +462,16 @@ func TestHandleAgent_SubEndpoints_Clone_MethodNotAllowed(t *testing.T) {
 	}
 }
 
+func TestHandleAgent_SubEndpoints_History_MethodNotAllowed(t *testing.T) {
+	s := newTestServer()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/agents/a1/history", nil)
+	w := httptest.NewRecorder()
+	s.handleAgent(w, req)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", w.Code)
+	}
+}
+
 func TestHandleAgent_SubEndpoints_Unknown(t *testing.T) {
 	s := newTestServer()
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/agents/a1/unknown", nil)
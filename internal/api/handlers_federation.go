@@ -2,7 +2,14 @@ package api
 
 import (
 	"encoding/json"
+	"net"
 	"net/http"
+	"net/url"
+
+	"github.com/jordanhubbard/loom/internal/auth"
+	"github.com/jordanhubbard/loom/internal/beads"
+	"github.com/jordanhubbard/loom/pkg/config"
+	"github.com/jordanhubbard/loom/pkg/models"
 )
 
 // handleFederationStatus handles GET /api/v1/federation/status
@@ -27,6 +34,15 @@ func (s *Server) handleFederationStatus(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if s.config.Beads.Federation.SyncMode == beads.FederationSyncModeHTTP {
+		bm := s.app.GetBeadsManager()
+		result["sync_mode"] = beads.FederationSyncModeHTTP
+		result["peers"] = bm.FederationSyncResults()
+		result["conflicts"] = bm.FederationConflicts()
+		s.respondJSON(w, http.StatusOK, result)
+		return
+	}
+
 	output, err := s.app.GetBeadsManager().FederationStatus(r.Context())
 	if err != nil {
 		s.respondError(w, http.StatusInternalServerError, err.Error())
@@ -70,3 +86,75 @@ func (s *Server) handleFederationSync(w http.ResponseWriter, r *http.Request) {
 		"synced": true,
 	})
 }
+
+// handleFederationBeads handles GET/POST /api/v1/federation/beads, the
+// endpoint HTTP federation peers pull the bead index from and push beads to.
+func (s *Server) handleFederationBeads(w http.ResponseWriter, r *http.Request) {
+	if !s.config.Beads.Federation.Enabled {
+		s.respondError(w, http.StatusNotFound, "Federation is not enabled")
+		return
+	}
+	bm := s.app.GetBeadsManager()
+
+	switch r.Method {
+	case http.MethodGet:
+		s.respondJSON(w, http.StatusOK, bm.FederationIndex())
+	case http.MethodPost:
+		var remote []*models.Bead
+		if err := s.parseJSON(r, &remote); err != nil {
+			s.respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		s.respondJSON(w, http.StatusOK, bm.MergeFederatedBeads(federationPeerName(r, s.config.Beads.Federation.Peers), remote))
+	default:
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// federationPeerName identifies the peer pushing beads by the name it has
+// in this instance's peer list, so its pushes and this instance's pulls from
+// it share the versions both sides agreed on. A configured peer matches when
+// its name is the authenticated subject or its remote_url host resolves to
+// the remote address. Unmatched pushers are named by subject, else address.
+func federationPeerName(r *http.Request, peers []config.FederationPeer) string {
+	subject := ""
+	if p, ok := auth.PrincipalFromRequest(r); ok {
+		subject = p.Subject
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	for _, peer := range peers {
+		if peer.Enabled && subject != "" && peer.Name == subject {
+			return peer.Name
+		}
+	}
+	for _, peer := range peers {
+		if !peer.Enabled {
+			continue
+		}
+		u, err := url.Parse(peer.RemoteURL)
+		if err != nil || u.Hostname() == "" {
+			continue
+		}
+		if u.Hostname() == host {
+			return peer.Name
+		}
+		addrs, err := net.DefaultResolver.LookupHost(r.Context(), u.Hostname())
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			if addr == host {
+				return peer.Name
+			}
+		}
+	}
+
+	if subject != "" {
+		return subject
+	}
+	return host
+}
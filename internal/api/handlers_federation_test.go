@@ -0,0 +1,44 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jordanhubbard/loom/internal/auth"
+	"github.com/jordanhubbard/loom/pkg/config"
+)
+
+func TestFederationPeerName(t *testing.T) {
+	peers := []config.FederationPeer{
+		{Name: "west", RemoteURL: "https://10.0.0.5:8080", Enabled: true},
+		{Name: "east", RemoteURL: "https://10.0.0.6", Enabled: true},
+		{Name: "old", RemoteURL: "https://10.0.0.7", Enabled: false},
+	}
+	request := func(remote, subject string) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/api/v1/federation/beads", nil)
+		r.RemoteAddr = remote
+		if subject != "" {
+			r = r.WithContext(auth.WithPrincipal(r.Context(), &auth.Principal{Subject: subject}))
+		}
+		return r
+	}
+
+	tests := []struct {
+		name    string
+		remote  string
+		subject string
+		want    string
+	}{
+		{"subject names a peer", "192.168.1.1:4000", "east", "east"},
+		{"address matches remote_url", "10.0.0.5:4000", "api-key-7", "west"},
+		{"disabled peer is ignored", "10.0.0.7:4000", "", "10.0.0.7"},
+		{"unknown subject", "192.168.1.1:4000", "api-key-7", "api-key-7"},
+		{"unknown address", "192.168.1.1:4000", "", "192.168.1.1"},
+	}
+	for _, tt := range tests {
+		if got := federationPeerName(request(tt.remote, tt.subject), peers); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+
+	"github.com/jordanhubbard/loom/internal/gitops"
 )
 
 // handleGitSync handles git pull for a project
@@ -220,3 +222,54 @@ func (s *Server) handleGitStatus(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 	}
 }
+
+// gitDiffResponse is the body of GET /api/v1/projects/git/diff?format=json.
+type gitDiffResponse struct {
+	ProjectID string `json:"project_id"`
+	*gitops.ParsedDiff
+}
+
+// handleGitDiff handles getting the uncommitted changes of a project as
+// git diff text, or with ?format=json parsed into files, hunks and totals
+func (s *Server) handleGitDiff(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	projectID := r.URL.Query().Get("project_id")
+	if projectID == "" {
+		http.Error(w, "project_id required", http.StatusBadRequest)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "text" && format != "json" {
+		http.Error(w, "format must be text or json", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := s.app.GetProjectManager().GetProject(projectID); err != nil {
+		http.Error(w, fmt.Sprintf("Project not found: %v", err), http.StatusNotFound)
+		return
+	}
+
+	diff, err := s.app.GetGitopsManager().Diff(r.Context(), projectID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to diff: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if format != "json" {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		if diff != "" {
+			fmt.Fprintln(w, diff)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(gitDiffResponse{ProjectID: projectID, ParsedDiff: gitops.ParseDiff(diff)}); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
@@ -1,16 +1,26 @@
 package api
 
 import (
+	"bufio"
+	"context"
+	"database/sql"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/jordanhubbard/loom/internal/agent"
 	"github.com/jordanhubbard/loom/internal/analytics"
 	"github.com/jordanhubbard/loom/internal/cache"
+	"github.com/jordanhubbard/loom/internal/dispatch"
+	"github.com/jordanhubbard/loom/internal/provider"
+	"github.com/jordanhubbard/loom/internal/temporal/eventbus"
 	"github.com/jordanhubbard/loom/pkg/config"
+	"github.com/jordanhubbard/loom/pkg/models"
+	_ "github.com/mattn/go-sqlite3"
 )
 
 // ============================================================
@@ -38,6 +48,93 @@ func TestHandleGetLogs_Unauthorized(t *testing.T) {
 	}
 }
 
+func TestHandleGetLogs_InvalidPaging(t *testing.T) {
+	for _, query := range []string{"limit=0", "limit=abc", "offset=-1", "offset=x"} {
+		s := newTestServer()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/analytics/logs?"+query, nil)
+		w := httptest.NewRecorder()
+		s.handleGetLogs(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", query, w.Code)
+		}
+	}
+}
+
+func TestHandleGetLogs_Paging(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+	storage, err := analytics.NewDatabaseStorage(db)
+	if err != nil {
+		t.Fatalf("NewDatabaseStorage failed: %v", err)
+	}
+	now := time.Now().Truncate(time.Second)
+	for i := 0; i < 3; i++ {
+		storage.SaveLog(context.Background(), &analytics.RequestLog{
+			ID:        fmt.Sprintf("log-%d", i),
+			Timestamp: now.Add(-time.Duration(i) * time.Minute),
+		})
+	}
+
+	s := newTestServer()
+	s.analyticsLogger = analytics.NewLogger(storage, nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/analytics/logs?limit=1&offset=1", nil)
+	w := httptest.NewRecorder()
+	s.handleGetLogs(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("X-Total-Count"); got != "3" {
+		t.Errorf("expected X-Total-Count 3, got %q", got)
+	}
+	var logs []analytics.RequestLog
+	if err := json.Unmarshal(w.Body.Bytes(), &logs); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(logs) != 1 || logs[0].ID != "log-1" {
+		t.Errorf("expected [log-1], got %+v", logs)
+	}
+}
+
+func TestHandleGetLogStats_NotModified(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+	storage, err := analytics.NewDatabaseStorage(db)
+	if err != nil {
+		t.Fatalf("NewDatabaseStorage failed: %v", err)
+	}
+	storage.SaveLog(context.Background(), &analytics.RequestLog{ID: "log-1", Timestamp: time.Now()})
+
+	s := newTestServer()
+	s.analyticsLogger = analytics.NewLogger(storage, nil)
+	w := httptest.NewRecorder()
+	s.handleGetLogStats(w, httptest.NewRequest(http.MethodGet, "/api/v1/analytics/stats", nil))
+	etag := w.Header().Get("ETag")
+	if w.Code != http.StatusOK || etag == "" {
+		t.Fatalf("expected 200 with ETag, got %d %q", w.Code, etag)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/analytics/stats", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	s.handleGetLogStats(w, req)
+	if w.Code != http.StatusNotModified {
+		t.Fatalf("expected 304 for unchanged stats, got %d", w.Code)
+	}
+
+	storage.SaveLog(context.Background(), &analytics.RequestLog{ID: "log-2", Timestamp: time.Now()})
+	w = httptest.NewRecorder()
+	s.handleGetLogStats(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200 after stats changed, got %d", w.Code)
+	}
+}
+
 func TestHandleGetLogStats_MethodNotAllowed(t *testing.T) {
 	s := newTestServer()
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/analytics/stats", nil)
@@ -101,6 +198,38 @@ func TestHandleGetCostReport_Unauthorized(t *testing.T) {
 	}
 }
 
+func TestHandleCostForecast_MethodNotAllowed(t *testing.T) {
+	s := newTestServer()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/analytics/forecast", nil)
+	w := httptest.NewRecorder()
+	s.handleCostForecast(w, req)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", w.Code)
+	}
+}
+
+func TestHandleCostForecast_NilAnalytics(t *testing.T) {
+	s := newTestServer()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/analytics/forecast", nil)
+	w := httptest.NewRecorder()
+	s.handleCostForecast(w, req)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", w.Code)
+	}
+}
+
+func TestHandleCostForecast_InvalidHorizon(t *testing.T) {
+	s := &Server{config: &config.Config{}, apiFailureLast: make(map[string]time.Time), analyticsLogger: &analytics.Logger{}}
+	for _, horizon := range []string{"0", "-7", "abc"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/analytics/forecast?horizon="+horizon, nil)
+		w := httptest.NewRecorder()
+		s.handleCostForecast(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("horizon=%s: expected 400, got %d", horizon, w.Code)
+		}
+	}
+}
+
 func TestHandleGetBatchingRecommendations_MethodNotAllowed(t *testing.T) {
 	s := newTestServer()
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/analytics/batching", nil)
@@ -132,6 +261,59 @@ func TestHandleGetBatchingRecommendations_Unauthorized(t *testing.T) {
 	}
 }
 
+func TestHandleExecuteBatchPlan_MethodNotAllowed(t *testing.T) {
+	s := newTestServer()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/analytics/batching/execute", nil)
+	w := httptest.NewRecorder()
+	s.handleExecuteBatchPlan(w, req)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", w.Code)
+	}
+}
+
+func TestHandleExecuteBatchPlan_NilAnalytics(t *testing.T) {
+	s := newTestServer()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/analytics/batching/execute", strings.NewReader(`{}`))
+	w := httptest.NewRecorder()
+	s.handleExecuteBatchPlan(w, req)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", w.Code)
+	}
+}
+
+func TestHandleExecuteBatchPlan_Validation(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+	storage, err := analytics.NewDatabaseStorage(db)
+	if err != nil {
+		t.Fatalf("NewDatabaseStorage failed: %v", err)
+	}
+	storage.SaveLog(context.Background(), &analytics.RequestLog{ID: "log-1", Timestamp: time.Now(), TotalTokens: 100, RequestBody: `{"messages":[]}`})
+
+	s := newTestServer()
+	s.analyticsLogger = analytics.NewLogger(storage, nil)
+	tests := []struct {
+		body string
+		want int
+	}{
+		{"bad", http.StatusBadRequest},
+		{`{"groups":[{"id":"g1","request_ids":["log-1"]}]}`, http.StatusBadRequest},
+		{`{"provider_id":"p1","groups":[{"id":"g1","request_ids":["missing"]}]}`, http.StatusBadRequest},
+		{`{"provider_id":"p1","groups":[{"id":"g1","request_ids":["log-1"]}]}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/analytics/batching/execute", strings.NewReader(tt.body))
+		w := httptest.NewRecorder()
+		s.handleExecuteBatchPlan(w, req)
+		if w.Code != tt.want {
+			t.Errorf("%q: expected %d, got %d", tt.body, tt.want, w.Code)
+		}
+	}
+}
+
 func TestHandleExportStats_MethodNotAllowed(t *testing.T) {
 	s := newTestServer()
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/analytics/export-stats", nil)
@@ -164,11 +346,16 @@ func TestExportStatsAsCSV(t *testing.T) {
 		TotalTokens:        5000,
 		TotalCostUSD:       1.50,
 		AvgLatencyMs:       200.5,
+		P50LatencyMs:       180,
+		P95LatencyMs:       450,
+		P99LatencyMs:       900,
 		ErrorRate:          0.05,
 		CostByProvider:     map[string]float64{"openai": 1.0, "anthropic": 0.5},
 		CostByUser:         map[string]float64{"user1": 1.5},
 		RequestsByProvider: map[string]int64{"openai": 60, "anthropic": 40},
 		RequestsByUser:     map[string]int64{"user1": 100},
+		CostByModel:        map[string]float64{"gpt-4": 1.2},
+		TokensByModel:      map[string]int64{"gpt-4": 1000},
 	}
 	filter := &analytics.LogFilter{}
 	exportStatsAsCSV(w, stats, filter)
@@ -183,6 +370,15 @@ func TestExportStatsAsCSV(t *testing.T) {
 	if !strings.Contains(body, "100") {
 		t.Error("expected request count in CSV")
 	}
+	if !strings.Contains(body, "P95 Latency (ms),450.00") {
+		t.Error("expected p95 latency in CSV")
+	}
+	if !strings.Contains(body, "P99 Latency (ms),900.00") {
+		t.Error("expected p99 latency in CSV")
+	}
+	if !strings.Contains(body, "gpt-4,1000,1.2000,80.00%") {
+		t.Errorf("expected model cost share in CSV, got:\n%s", body)
+	}
 }
 
 func TestExportLogsAsCSV(t *testing.T) {
@@ -226,6 +422,70 @@ func TestExportLogsAsCSV_EmptyLogs(t *testing.T) {
 	}
 }
 
+func TestExportLogsAsJSONL(t *testing.T) {
+	w := httptest.NewRecorder()
+	logs := []*analytics.RequestLog{
+		{ID: "log-1", UserID: "user1", ModelName: "gpt-4"},
+		{ID: "log-2", UserID: "user2", ModelName: "claude-3"},
+	}
+	exportLogsAsJSONL(w, logs)
+
+	if ct := w.Header().Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Errorf("expected application/x-ndjson, got %s", ct)
+	}
+	lines := strings.Split(strings.TrimSuffix(w.Body.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d: %q", len(lines), w.Body.String())
+	}
+	var got analytics.RequestLog
+	if err := json.Unmarshal([]byte(lines[1]), &got); err != nil {
+		t.Fatalf("line is not valid JSON: %v", err)
+	}
+	if got.ID != "log-2" {
+		t.Errorf("expected log-2 on second line, got %s", got.ID)
+	}
+}
+
+func TestHandleExportLogs_JSONLRedacted(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+	storage, err := analytics.NewDatabaseStorage(db)
+	if err != nil {
+		t.Fatalf("NewDatabaseStorage failed: %v", err)
+	}
+	// Stored before redaction, e.g. by an older version with other settings
+	storage.SaveLog(context.Background(), &analytics.RequestLog{
+		ID:          "log-1",
+		Timestamp:   time.Now(),
+		RequestBody: `{"email":"alice@example.com"}`,
+	})
+
+	privacy := analytics.DefaultPrivacyConfig()
+	privacy.LogRequestBodies = true
+	s := newTestServer()
+	s.analyticsLogger = analytics.NewLogger(storage, privacy)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/analytics/export?format=jsonl", nil)
+	w := httptest.NewRecorder()
+	s.handleExportLogs(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Errorf("expected application/x-ndjson, got %s", ct)
+	}
+	body := w.Body.String()
+	if strings.Contains(body, "alice@example.com") {
+		t.Errorf("expected email to be redacted, got %s", body)
+	}
+	if !strings.Contains(body, "[REDACTED]") {
+		t.Errorf("expected redaction marker, got %s", body)
+	}
+}
+
 // ============================================================
 // Pattern handler method tests
 // ============================================================
@@ -350,7 +610,7 @@ func TestHandleCacheRecommendations_MethodNotAllowed(t *testing.T) {
 
 func TestHandleWorkflows_MethodNotAllowed(t *testing.T) {
 	s := newTestServer()
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/workflows", nil)
+	req := httptest.NewRequest(http.MethodPatch, "/api/v1/workflows", nil)
 	w := httptest.NewRecorder()
 	s.handleWorkflows(w, req)
 	if w.Code != http.StatusMethodNotAllowed {
@@ -358,6 +618,56 @@ func TestHandleWorkflows_MethodNotAllowed(t *testing.T) {
 	}
 }
 
+func TestHandleWorkflow_MethodNotAllowed(t *testing.T) {
+	s := newTestServer()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/workflows/wf-1", nil)
+	w := httptest.NewRecorder()
+	s.handleWorkflow(w, req)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", w.Code)
+	}
+}
+
+func TestHandleWorkflows_InvalidDefinition(t *testing.T) {
+	s := newTestServer()
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		body    string
+		wantErr string
+	}{
+		{"bad json", http.MethodPost, "/api/v1/workflows", "{", "invalid request body"},
+		{"no start edge", http.MethodPost, "/api/v1/workflows",
+			`{"name":"x","workflow_type":"custom","nodes":[{"node_key":"a","node_type":"task"}],"edges":[{"from_node_key":"a","to_node_key":"","condition":"success"}]}`,
+			"no start edge"},
+		{"dangling edge", http.MethodPut, "/api/v1/workflows/wf-1",
+			`{"name":"x","workflow_type":"custom","nodes":[{"node_key":"a","node_type":"task"}],"edges":[{"from_node_key":"","to_node_key":"b","condition":"success"}]}`,
+			`unknown node "b"`},
+		{"id mismatch", http.MethodPut, "/api/v1/workflows/wf-1",
+			`{"id":"wf-2","name":"x","workflow_type":"custom","nodes":[{"node_key":"a","node_type":"task"}],"edges":[{"from_node_key":"","to_node_key":"a","condition":"success"},{"from_node_key":"a","to_node_key":"","condition":"success"}]}`,
+			"does not match"},
+		{"missing id", http.MethodDelete, "/api/v1/workflows/", "", "Workflow ID required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			if tt.path == "/api/v1/workflows" {
+				s.handleWorkflows(w, req)
+			} else {
+				s.handleWorkflow(w, req)
+			}
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("expected 400, got %d: %s", w.Code, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), tt.wantErr) {
+				t.Errorf("body = %q, want it to contain %q", w.Body.String(), tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestHandleWorkflowExecutions_MethodNotAllowed(t *testing.T) {
 	s := newTestServer()
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/workflows/executions", nil)
@@ -368,6 +678,23 @@ func TestHandleWorkflowExecutions_MethodNotAllowed(t *testing.T) {
 	}
 }
 
+func TestHandleWorkflowExecution_BadRequests(t *testing.T) {
+	s := newTestServer()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/workflows/executions/exec-1", nil)
+	w := httptest.NewRecorder()
+	s.handleWorkflowExecution(w, req)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/workflows/executions/", nil)
+	w = httptest.NewRecorder()
+	s.handleWorkflowExecution(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+}
+
 func TestHandleWorkflowAnalytics_MethodNotAllowed(t *testing.T) {
 	s := newTestServer()
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/workflows/analytics", nil)
@@ -572,6 +899,222 @@ func TestHandleSystemStatus_MethodNotAllowed(t *testing.T) {
 	}
 }
 
+func TestHandleDispatchMetrics(t *testing.T) {
+	s := newTestServer()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/dispatch/metrics", nil)
+	w := httptest.NewRecorder()
+	s.handleDispatchMetrics(w, req)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/dispatch/metrics", nil)
+	w = httptest.NewRecorder()
+	s.handleDispatchMetrics(w, req)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 without an app, got %d", w.Code)
+	}
+}
+
+func TestHandleDispatchStatusStream(t *testing.T) {
+	s := newTestServer()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/dispatch/status/stream", nil)
+	w := httptest.NewRecorder()
+	s.handleDispatchStatusStream(w, req)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/dispatch/status/stream", nil)
+	w = httptest.NewRecorder()
+	s.handleDispatchStatusStream(w, req)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 without an app, got %d", w.Code)
+	}
+}
+
+func TestHandleAgentLogs_BadRequests(t *testing.T) {
+	s := newTestServer()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/agents/a1/logs", nil)
+	w := httptest.NewRecorder()
+	s.handleAgent(w, req)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/agents/a1/logs?limit=-1", nil)
+	w = httptest.NewRecorder()
+	s.handleAgent(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for a negative limit, got %d", w.Code)
+	}
+}
+
+func TestStreamAgentLogs(t *testing.T) {
+	manager := agent.NewWorkerManager(5, provider.NewRegistry(), nil)
+	a, err := manager.CreateAgent(context.Background(), "Engineer", "default/engineer", "proj", "", &models.Persona{Name: "default/engineer"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager.AppendAgentLog(a.ID, agent.AgentLogLine{Message: "earlier"})
+
+	s := newTestServer()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.streamAgentLogs(w, r, manager, a.ID, 10)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+
+	lines := make(chan string, 100)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+	nextLine := func() agent.AgentLogLine {
+		t.Helper()
+		timeout := time.After(2 * time.Second)
+		for {
+			select {
+			case line := <-lines:
+				if data, ok := strings.CutPrefix(line, "data: "); ok {
+					var logLine agent.AgentLogLine
+					if err := json.Unmarshal([]byte(data), &logLine); err != nil {
+						t.Fatalf("bad log payload %q: %v", data, err)
+					}
+					return logLine
+				}
+			case <-timeout:
+				t.Fatal("no log event received")
+			}
+		}
+	}
+
+	if got := nextLine(); got.Message != "earlier" {
+		t.Errorf("first line = %q, want the recent line", got.Message)
+	}
+	manager.AppendAgentLog(a.ID, agent.AgentLogLine{Message: "live"})
+	if got := nextLine(); got.Message != "live" {
+		t.Errorf("streamed line = %q, want live", got.Message)
+	}
+
+	// Removing the agent ends the stream
+	if err := manager.StopAgent(a.ID); err != nil {
+		t.Fatal(err)
+	}
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case _, ok := <-lines:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatal("stream did not end after the agent was removed")
+		}
+	}
+}
+
+func TestStreamDispatchStatus(t *testing.T) {
+	defer func(d time.Duration) { dispatchStatusKeepalive = d }(dispatchStatusKeepalive)
+	dispatchStatusKeepalive = 50 * time.Millisecond
+
+	eb := eventbus.NewEventBus(nil, &config.TemporalConfig{})
+	defer eb.Close()
+	d := dispatch.NewDispatcher(nil, nil, nil, nil, eb)
+	s := newTestServer()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.streamDispatchStatus(w, r, d, eb)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+
+	lines := make(chan string, 100)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+	nextStatus := func() dispatch.SystemStatus {
+		t.Helper()
+		timeout := time.After(2 * time.Second)
+		for {
+			select {
+			case line := <-lines:
+				if data, ok := strings.CutPrefix(line, "data: "); ok {
+					var status dispatch.SystemStatus
+					if err := json.Unmarshal([]byte(data), &status); err != nil {
+						t.Fatalf("bad status payload %q: %v", data, err)
+					}
+					return status
+				}
+			case <-timeout:
+				t.Fatal("no status event received")
+			}
+		}
+	}
+
+	if got := nextStatus(); got.State != d.GetSystemStatus().State {
+		t.Errorf("initial status = %+v, want %+v", got, d.GetSystemStatus())
+	}
+
+	_ = eb.Publish(&eventbus.Event{
+		Type: eventbus.EventTypeDispatchStatusChange,
+		Data: map[string]interface{}{"state": "active", "reason": "dispatching bd-1", "updated_at": time.Now()},
+	})
+	if got := nextStatus(); got.State != dispatch.StatusActive || got.Reason != "dispatching bd-1" {
+		t.Errorf("status = %+v, want active", got)
+	}
+
+	keepalive := time.After(2 * time.Second)
+	for found := false; !found; {
+		select {
+		case line := <-lines:
+			found = line == ": keepalive"
+		case <-keepalive:
+			t.Fatal("no keepalive received")
+		}
+	}
+
+	// Disconnecting removes the subscription.
+	cancel()
+	deadline := time.Now().Add(2 * time.Second)
+	for eb.SubscriberCount() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("subscriber not removed after disconnect, %d remain", eb.SubscriberCount())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
 func TestHandleRecommendedModels_MethodNotAllowed(t *testing.T) {
 	s := newTestServer()
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/models/recommended", nil)
@@ -890,6 +1433,49 @@ func TestHandleWorkGraph_MethodNotAllowed(t *testing.T) {
 	}
 }
 
+func TestHandleWorkGraph_BadSince(t *testing.T) {
+	s := newTestServer()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/work-graph?since=yesterday", nil)
+	w := httptest.NewRecorder()
+	s.handleWorkGraph(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestHandleProjectAgent_MethodNotAllowed(t *testing.T) {
+	s := newTestServer()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/project-agents/p1/heartbeat", nil)
+	w := httptest.NewRecorder()
+	s.handleProjectAgent(w, req)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", w.Code)
+	}
+}
+
+func TestHandleProjectAgent_BadRequests(t *testing.T) {
+	s := newTestServer()
+	tests := []struct {
+		path string
+		body string
+		want int
+	}{
+		{"/api/v1/project-agents/p1/unknown", `{}`, http.StatusNotFound},
+		{"/api/v1/project-agents/p1/results", `{}`, http.StatusBadRequest},
+		{"/api/v1/project-agents/p1/results", `bad`, http.StatusBadRequest},
+		{"/api/v1/project-agents/p1/heartbeat", `{"project_id":"p2"}`, http.StatusBadRequest},
+		{"/api/v1/project-agents/register", `{}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+		w := httptest.NewRecorder()
+		s.handleProjectAgent(w, req)
+		if w.Code != tt.want {
+			t.Errorf("POST %s %s: expected %d, got %d", tt.path, tt.body, tt.want, w.Code)
+		}
+	}
+}
+
 // ============================================================
 // Provider handler method tests
 // ============================================================
@@ -1113,6 +1699,74 @@ func TestHandleProjectState_MethodNotAllowed(t *testing.T) {
 	}
 }
 
+func TestHandleProjectDashboard_MethodNotAllowed(t *testing.T) {
+	s := newTestServer()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/projects/p1/dashboard", nil)
+	w := httptest.NewRecorder()
+	s.handleProjectDashboard(w, req, "p1")
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", w.Code)
+	}
+}
+
+func TestHandleProjectMarkdownExport_MethodNotAllowed(t *testing.T) {
+	s := newTestServer()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/projects/p1/export.md", nil)
+	w := httptest.NewRecorder()
+	s.handleProjectStateEndpoints(w, req, "p1", "export.md")
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", w.Code)
+	}
+}
+
+func TestHandleProjectBeadImport_MethodNotAllowed(t *testing.T) {
+	s := newTestServer()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/projects/p1/import", nil)
+	w := httptest.NewRecorder()
+	s.handleProjectStateEndpoints(w, req, "p1", "import")
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", w.Code)
+	}
+}
+
+func TestBuildProjectDashboard(t *testing.T) {
+	projectBeads := []*models.Bead{
+		{ID: "b1", Status: models.BeadStatusOpen},
+		{ID: "b2", Status: models.BeadStatusOpen},
+		{ID: "b3", Status: models.BeadStatusInProgress},
+		{ID: "b4", Status: models.BeadStatusClosed},
+	}
+	stats := &analytics.LogStats{
+		TotalCostUSD: 12.5,
+		TotalTokens:  9000,
+		CostByModel: map[string]float64{
+			"m1": 1, "m2": 6, "m3": 0.5, "m4": 2, "m5": 3, "m6": 0.25,
+		},
+		TokensByModel: map[string]int64{"m2": 4000},
+	}
+	alerts := []*analytics.Alert{{ID: "a1", ProjectID: "p1", Type: "project_exceeded"}}
+
+	d := buildProjectDashboard(projectBeads, stats, alerts)
+
+	counts := d["beads"].(map[string]int)
+	if counts["total"] != 4 || counts["open"] != 2 || counts["in_progress"] != 1 || counts["closed"] != 1 || counts["blocked"] != 0 {
+		t.Errorf("unexpected bead counts: %v", counts)
+	}
+	if d["total_cost_usd"] != 12.5 || d["total_tokens"] != int64(9000) {
+		t.Errorf("unexpected totals: %v %v", d["total_cost_usd"], d["total_tokens"])
+	}
+	top := d["top_models"].([]modelSpend)
+	if len(top) != dashboardTopModels {
+		t.Fatalf("expected %d top models, got %d", dashboardTopModels, len(top))
+	}
+	if top[0] != (modelSpend{Model: "m2", CostUSD: 6, Tokens: 4000}) || top[4].Model != "m3" {
+		t.Errorf("top models not ordered by spend: %+v", top)
+	}
+	if got := d["alerts"].([]*analytics.Alert); len(got) != 1 || got[0].ID != "a1" {
+		t.Errorf("unexpected alerts: %+v", got)
+	}
+}
+
 func TestHandleProjectGitKey_MethodNotAllowed(t *testing.T) {
 	s := newTestServer()
 	req := httptest.NewRequest(http.MethodDelete, "/api/v1/projects/p1/git-key", nil)
@@ -1341,6 +1995,28 @@ func TestHandleBead_ClaimMethodNotAllowed(t *testing.T) {
 	}
 }
 
+func TestHandleClaimBeads_Validation(t *testing.T) {
+	s := newTestServer()
+	tests := []struct {
+		method string
+		body   string
+		want   int
+	}{
+		{http.MethodGet, "", http.StatusMethodNotAllowed},
+		{http.MethodPost, "bad", http.StatusBadRequest},
+		{http.MethodPost, `{"bead_ids":["b1"]}`, http.StatusBadRequest},
+		{http.MethodPost, `{"agent_id":"a1","bead_ids":[]}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, "/api/v1/beads/claim", strings.NewReader(tt.body))
+		w := httptest.NewRecorder()
+		s.handleClaimBeads(w, req)
+		if w.Code != tt.want {
+			t.Errorf("%s %q: expected %d, got %d", tt.method, tt.body, tt.want, w.Code)
+		}
+	}
+}
+
 func TestHandleBead_RedispatchMethodNotAllowed(t *testing.T) {
 	s := newTestServer()
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/beads/b1/redispatch", nil)
@@ -1351,6 +2027,28 @@ func TestHandleBead_RedispatchMethodNotAllowed(t *testing.T) {
 	}
 }
 
+func TestHandleBead_CancelMethodNotAllowed(t *testing.T) {
+	s := newTestServer()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/beads/b1/cancel", nil)
+	w := httptest.NewRecorder()
+	s.handleBead(w, req)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", w.Code)
+	}
+}
+
+func TestHandleBead_CancelNotFound(t *testing.T) {
+	app, cleanup := createTestLoom(t)
+	defer cleanup()
+	s := NewServer(app, nil, nil, &config.Config{})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/beads/nonexistent/cancel", nil)
+	w := httptest.NewRecorder()
+	s.handleBead(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", w.Code)
+	}
+}
+
 func TestHandleBead_EscalateMethodNotAllowed(t *testing.T) {
 	s := newTestServer()
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/beads/b1/escalate", nil)
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+)
+
+// projectAgentHeartbeat is the body project agent containers send when they
+// register and on every heartbeat.
+type projectAgentHeartbeat struct {
+	ProjectID string `json:"project_id"`
+	Busy      bool   `json:"busy,omitempty"`
+}
+
+// projectAgentResult is the body project agent containers send when a task
+// they ran over HTTP finishes.
+type projectAgentResult struct {
+	TaskID  string `json:"task_id"`
+	BeadID  string `json:"bead_id"`
+	Success bool   `json:"success"`
+	Output  string `json:"output"`
+	Error   string `json:"error,omitempty"`
+}
+
+// handleProjectAgent handles calls from project agent containers:
+// POST /api/v1/project-agents/register,
+// POST /api/v1/project-agents/{project_id}/heartbeat and
+// POST /api/v1/project-agents/{project_id}/results. Registration and
+// heartbeats count as a heartbeat for the project's agents; results add the
+// task's output to the log of the agent its bead is assigned to.
+func (s *Server) handleProjectAgent(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1/project-agents/")
+	parts := strings.Split(path, "/")
+
+	if len(parts) == 2 && parts[1] == "results" {
+		var result projectAgentResult
+		if err := s.parseJSON(r, &result); err != nil {
+			s.respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if result.BeadID == "" {
+			s.respondError(w, http.StatusBadRequest, "bead_id is required")
+			return
+		}
+		s.app.RecordProjectAgentResult(result.TaskID, result.BeadID, result.Output, result.Error)
+		s.respondJSON(w, http.StatusOK, map[string]interface{}{
+			"project_id": parts[0],
+			"task_id":    result.TaskID,
+		})
+		return
+	}
+
+	var req projectAgentHeartbeat
+	if err := s.parseJSON(r, &req); err != nil {
+		s.respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	switch {
+	case len(parts) == 1 && parts[0] == "register":
+	case len(parts) == 2 && parts[1] == "heartbeat":
+		if req.ProjectID == "" {
+			req.ProjectID = parts[0]
+		}
+		if req.ProjectID != parts[0] {
+			s.respondError(w, http.StatusBadRequest, "project_id does not match the path")
+			return
+		}
+	default:
+		s.respondError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	if req.ProjectID == "" {
+		s.respondError(w, http.StatusBadRequest, "project_id is required")
+		return
+	}
+
+	agents := s.app.RecordProjectHeartbeat(req.ProjectID)
+	s.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"project_id": req.ProjectID,
+		"agents":     agents,
+	})
+}
@@ -1,10 +1,16 @@
 package api
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
+	"sort"
+	"time"
 
+	"github.com/jordanhubbard/loom/internal/analytics"
+	"github.com/jordanhubbard/loom/internal/beads"
 	"github.com/jordanhubbard/loom/internal/project"
 	"github.com/jordanhubbard/loom/pkg/models"
 )
@@ -20,6 +26,12 @@ func (s *Server) handleProjectStateEndpoints(w http.ResponseWriter, r *http.Requ
 		s.handleProjectComments(w, r, id)
 	case "state":
 		s.handleProjectState(w, r, id)
+	case "dashboard":
+		s.handleProjectDashboard(w, r, id)
+	case "export.md":
+		s.handleProjectMarkdownExport(w, r, id)
+	case "import":
+		s.handleProjectBeadImport(w, r, id)
 	case "agents":
 		s.handleProjectAgents(w, r, id)
 	case "git-key":
@@ -112,8 +124,9 @@ func (s *Server) handleCloseProject(w http.ResponseWriter, r *http.Request, id s
 	}
 
 	var req struct {
-		AuthorID string `json:"author_id"`
-		Comment  string `json:"comment"`
+		AuthorID        string `json:"author_id"`
+		Comment         string `json:"comment"`
+		DecisionTimeout string `json:"decision_timeout"` // Keep the project open if nobody decides in time
 	}
 	if err := s.parseJSON(r, &req); err != nil {
 		s.respondError(w, http.StatusBadRequest, "Invalid request body")
@@ -124,6 +137,15 @@ func (s *Server) handleCloseProject(w http.ResponseWriter, r *http.Request, id s
 		s.respondError(w, http.StatusBadRequest, "author_id is required")
 		return
 	}
+	var decisionTimeout time.Duration
+	if req.DecisionTimeout != "" {
+		var err error
+		decisionTimeout, err = time.ParseDuration(req.DecisionTimeout)
+		if err != nil || decisionTimeout <= 0 {
+			s.respondError(w, http.StatusBadRequest, "decision_timeout must be a positive duration")
+			return
+		}
+	}
 
 	// Check if project has open work
 	openBeads, err := s.app.GetReadyBeads(id)
@@ -135,7 +157,7 @@ func (s *Server) handleCloseProject(w http.ResponseWriter, r *http.Request, id s
 	hasOpenWork := len(openBeads) > 0
 	if hasOpenWork {
 		// Create a decision bead for closure
-		decision, err := s.app.CreateDecisionBead(
+		decision, err := s.app.CreateDecisionBeadWithTimeout(
 			fmt.Sprintf("Should project '%s' be closed despite having %d open beads?", id, len(openBeads)),
 			"",
 			req.AuthorID,
@@ -143,6 +165,8 @@ func (s *Server) handleCloseProject(w http.ResponseWriter, r *http.Request, id s
 			"no",
 			models.BeadPriorityP1,
 			id,
+			decisionTimeout,
+			"no",
 		)
 		if err != nil {
 			s.respondError(w, http.StatusInternalServerError, err.Error())
@@ -279,6 +303,162 @@ func (s *Server) handleProjectState(w http.ResponseWriter, r *http.Request, id s
 	s.respondJSON(w, http.StatusOK, state)
 }
 
+// dashboardTopModels is how many models the project dashboard lists by spend
+const dashboardTopModels = 5
+
+// modelSpend is one row of the dashboard's top models by spend
+type modelSpend struct {
+	Model   string  `json:"model"`
+	CostUSD float64 `json:"cost_usd"`
+	Tokens  int64   `json:"tokens"`
+}
+
+// handleProjectBeadImport handles POST /api/v1/projects/{id}/import. The body
+// is a YAML task file, or Markdown with YAML front matter when format=markdown.
+func (s *Server) handleProjectBeadImport(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != http.MethodPost {
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, 5<<20))
+	if err != nil {
+		s.respondError(w, http.StatusBadRequest, "Failed to read body")
+		return
+	}
+
+	if _, err := s.app.GetProjectManager().GetProject(id); err != nil {
+		s.respondError(w, http.StatusNotFound, "Project not found")
+		return
+	}
+
+	created, err := s.app.GetBeadsManager().ImportBeads(id, body, r.URL.Query().Get("format"))
+	if errors.Is(err, beads.ErrInvalidImport) {
+		s.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.respondJSON(w, http.StatusCreated, map[string]interface{}{
+		"project_id": id,
+		"beads":      created,
+	})
+}
+
+// handleProjectMarkdownExport handles GET /api/v1/projects/{id}/export.md
+func (s *Server) handleProjectMarkdownExport(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != http.MethodGet {
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if _, err := s.app.GetProjectManager().GetProject(id); err != nil {
+		s.respondError(w, http.StatusNotFound, "Project not found")
+		return
+	}
+
+	report, err := s.app.GetBeadsManager().ExportProjectMarkdown(id)
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(report))
+}
+
+// handleProjectDashboard handles GET /api/v1/projects/{id}/dashboard: bead
+// progress and spend for one project in a single call. Spend honors the
+// start_time and end_time query params (RFC3339).
+func (s *Server) handleProjectDashboard(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != http.MethodGet {
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	project, err := s.app.GetProjectManager().GetProject(id)
+	if err != nil {
+		s.respondError(w, http.StatusNotFound, "Project not found")
+		return
+	}
+
+	projectBeads, err := s.app.GetBeadsManager().ListBeads(map[string]interface{}{"project_id": id})
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	stats := &analytics.LogStats{}
+	alerts := []*analytics.Alert{}
+	if s.analyticsLogger != nil {
+		filter := &analytics.LogFilter{ProjectID: id}
+		if startTime := r.URL.Query().Get("start_time"); startTime != "" {
+			if t, err := time.Parse(time.RFC3339, startTime); err == nil {
+				filter.StartTime = t
+			}
+		}
+		if endTime := r.URL.Query().Get("end_time"); endTime != "" {
+			if t, err := time.Parse(time.RFC3339, endTime); err == nil {
+				filter.EndTime = t
+			}
+		}
+		stats, err = s.analyticsLogger.GetStats(r.Context(), filter)
+		if err != nil {
+			s.respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		alerts = s.analyticsLogger.ProjectAlerts(r.Context(), id)
+	}
+
+	dashboard := buildProjectDashboard(projectBeads, stats, alerts)
+	dashboard["id"] = project.ID
+	dashboard["name"] = project.Name
+	dashboard["status"] = project.Status
+	s.respondJSON(w, http.StatusOK, dashboard)
+}
+
+// buildProjectDashboard combines a project's beads with its spend
+func buildProjectDashboard(projectBeads []*models.Bead, stats *analytics.LogStats, alerts []*analytics.Alert) map[string]interface{} {
+	byStatus := make(map[models.BeadStatus]int)
+	for _, b := range projectBeads {
+		byStatus[b.Status]++
+	}
+
+	topModels := make([]modelSpend, 0, len(stats.CostByModel))
+	for model, cost := range stats.CostByModel {
+		topModels = append(topModels, modelSpend{Model: model, CostUSD: cost, Tokens: stats.TokensByModel[model]})
+	}
+	sort.Slice(topModels, func(i, j int) bool {
+		if topModels[i].CostUSD != topModels[j].CostUSD {
+			return topModels[i].CostUSD > topModels[j].CostUSD
+		}
+		return topModels[i].Model < topModels[j].Model
+	})
+	if len(topModels) > dashboardTopModels {
+		topModels = topModels[:dashboardTopModels]
+	}
+
+	return map[string]interface{}{
+		"beads": map[string]int{
+			"total":       len(projectBeads),
+			"open":        byStatus[models.BeadStatusOpen],
+			"in_progress": byStatus[models.BeadStatusInProgress],
+			"blocked":     byStatus[models.BeadStatusBlocked],
+			"dead_letter": byStatus[models.BeadStatusDeadLetter],
+			"closed":      byStatus[models.BeadStatusClosed],
+		},
+		"total_cost_usd": stats.TotalCostUSD,
+		"total_tokens":   stats.TotalTokens,
+		"total_requests": stats.TotalRequests,
+		"top_models":     topModels,
+		"alerts":         alerts,
+	}
+}
+
 // handleBootstrapProject handles POST /api/v1/projects/bootstrap
 func (s *Server) handleBootstrapProject(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
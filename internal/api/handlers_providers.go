@@ -7,17 +7,26 @@ import (
 	"strings"
 
 	internalmodels "github.com/jordanhubbard/loom/internal/models"
+	"github.com/jordanhubbard/loom/internal/provider"
 )
 
 // ProviderRequest is a request wrapper for provider registration with API key
 type ProviderRequest struct {
-	ID          string `json:"id"`
-	Name        string `json:"name"`
-	Type        string `json:"type"`
-	Endpoint    string `json:"endpoint"`
-	APIKey      string `json:"api_key"`
-	Model       string `json:"model"`
-	Description string `json:"description"`
+	ID                string `json:"id"`
+	Name              string `json:"name"`
+	Type              string `json:"type"`
+	Endpoint          string `json:"endpoint"`
+	APIKey            string `json:"api_key"`
+	Model             string `json:"model"`
+	Description       string `json:"description"`
+	DeploymentName    string `json:"deployment_name,omitempty"`     // azure-openai
+	APIVersion        string `json:"api_version,omitempty"`         // azure-openai
+	Region            string `json:"region,omitempty"`              // bedrock
+	TimeoutSeconds    int    `json:"timeout_seconds,omitempty"`     // 0 uses the default
+	MaxRetries        int    `json:"max_retries,omitempty"`         // 0 uses the default
+	RequestsPerMinute int    `json:"requests_per_minute,omitempty"` // 0 is unlimited
+	MockResponse      string `json:"mock_response,omitempty"`       // mock: canned reply
+	MockLatencyMs     int    `json:"mock_latency_ms,omitempty"`     // mock: simulated delay
 }
 
 // handleProviders handles GET/POST /api/v1/providers
@@ -42,13 +51,25 @@ func (s *Server) handleProviders(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		// Signed providers read credentials from the environment when no key is given.
+		requiresKey := provider.RequiresSignedCredentials(req.Type)
+
 		provider := &internalmodels.Provider{
-			ID:          req.ID,
-			Name:        req.Name,
-			Type:        req.Type,
-			Endpoint:    req.Endpoint,
-			Model:       req.Model,
-			Description: req.Description,
+			ID:                req.ID,
+			Name:              req.Name,
+			Type:              req.Type,
+			Endpoint:          req.Endpoint,
+			Model:             req.Model,
+			Description:       req.Description,
+			RequiresKey:       requiresKey,
+			DeploymentName:    req.DeploymentName,
+			APIVersion:        req.APIVersion,
+			Region:            req.Region,
+			TimeoutSeconds:    req.TimeoutSeconds,
+			MaxRetries:        req.MaxRetries,
+			RequestsPerMinute: req.RequestsPerMinute,
+			MockResponse:      req.MockResponse,
+			MockLatencyMs:     req.MockLatencyMs,
 		}
 
 		// Store API key if provided
@@ -81,7 +102,8 @@ func (s *Server) handleProviders(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// handleProvider handles GET/DELETE /api/v1/providers/{id} and GET /api/v1/providers/{id}/models
+// handleProvider handles GET/DELETE /api/v1/providers/{id}, GET /api/v1/providers/{id}/models,
+// POST /api/v1/providers/{id}/models/refresh and GET /api/v1/providers/{id}/rate-limit
 func (s *Server) handleProvider(w http.ResponseWriter, r *http.Request) {
 	path := strings.TrimPrefix(r.URL.Path, "/api/v1/providers/")
 	parts := strings.Split(path, "/")
@@ -92,6 +114,23 @@ func (s *Server) handleProvider(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if len(parts) > 2 && parts[1] == "models" && parts[2] == "refresh" {
+		if r.Method != http.MethodPost {
+			s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+		if s.app == nil {
+			s.respondError(w, http.StatusServiceUnavailable, "Application not initialized")
+			return
+		}
+		models, err := s.app.RefreshProviderModels(r.Context(), providerID)
+		if err != nil {
+			s.respondError(w, http.StatusBadGateway, err.Error())
+			return
+		}
+		s.respondJSON(w, http.StatusOK, map[string]interface{}{"models": models})
+		return
+	}
 	if len(parts) > 1 && parts[1] == "models" {
 		if r.Method != http.MethodGet {
 			s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
@@ -101,7 +140,7 @@ func (s *Server) handleProvider(w http.ResponseWriter, r *http.Request) {
 			s.respondError(w, http.StatusServiceUnavailable, "Application not initialized")
 			return
 		}
-		models, err := s.app.GetProviderModels(context.Background(), providerID)
+		models, err := s.app.ListProviderModels(r.Context(), providerID)
 		if err != nil {
 			s.respondError(w, http.StatusBadGateway, err.Error())
 			return
@@ -109,6 +148,23 @@ func (s *Server) handleProvider(w http.ResponseWriter, r *http.Request) {
 		s.respondJSON(w, http.StatusOK, map[string]interface{}{"models": models})
 		return
 	}
+	if len(parts) > 1 && parts[1] == "rate-limit" {
+		if r.Method != http.MethodGet {
+			s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+		if s.app == nil {
+			s.respondError(w, http.StatusServiceUnavailable, "Application not initialized")
+			return
+		}
+		stats, err := s.app.ProviderRateLimitStats(providerID)
+		if err != nil {
+			s.respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		s.respondJSON(w, http.StatusOK, stats)
+		return
+	}
 	if len(parts) > 1 && parts[1] == "negotiate" {
 		if r.Method != http.MethodPost {
 			s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
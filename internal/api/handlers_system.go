@@ -1,6 +1,18 @@
 package api
 
-import "net/http"
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/jordanhubbard/loom/internal/dispatch"
+	"github.com/jordanhubbard/loom/internal/temporal/eventbus"
+)
+
+// dispatchStatusKeepalive is how often an idle dispatch status stream sends
+// a comment to keep the connection open.
+var dispatchStatusKeepalive = 30 * time.Second
 
 // handleSystemStatus handles GET /api/v1/system/status
 func (s *Server) handleSystemStatus(w http.ResponseWriter, r *http.Request) {
@@ -12,3 +24,93 @@ func (s *Server) handleSystemStatus(w http.ResponseWriter, r *http.Request) {
 	status := s.app.GetDispatcher().GetSystemStatus()
 	s.respondJSON(w, http.StatusOK, status)
 }
+
+// handleDispatchMetrics handles GET /api/v1/dispatch/metrics
+func (s *Server) handleDispatchMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if s.app == nil || s.app.GetDispatcher() == nil {
+		s.respondError(w, http.StatusServiceUnavailable, "Dispatcher not available")
+		return
+	}
+
+	s.respondJSON(w, http.StatusOK, s.app.GetDispatcher().GetDispatchMetrics())
+}
+
+// handleDispatchStatusStream handles GET /api/v1/dispatch/status/stream, a
+// server-sent event stream that sends the current SystemStatus and then
+// every status change.
+func (s *Server) handleDispatchStatusStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if s.app == nil || s.app.GetDispatcher() == nil {
+		s.respondError(w, http.StatusServiceUnavailable, "Dispatcher not available")
+		return
+	}
+	eventBus := s.app.GetEventBus()
+	if eventBus == nil {
+		s.respondError(w, http.StatusServiceUnavailable, "Event bus not available")
+		return
+	}
+	s.streamDispatchStatus(w, r, s.app.GetDispatcher(), eventBus)
+}
+
+func (s *Server) streamDispatchStatus(w http.ResponseWriter, r *http.Request, d *dispatch.Dispatcher, eventBus *eventbus.EventBus) {
+	// Disable write timeout for SSE - the server's WriteTimeout would kill
+	// long-running streams.
+	rc := http.NewResponseController(w)
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	// Subscribe before reading the current status so no change is missed
+	subscriberID := fmt.Sprintf("dispatch-status-%d", time.Now().UnixNano())
+	subscriber := eventBus.Subscribe(subscriberID, func(event *eventbus.Event) bool {
+		return event.Type == eventbus.EventTypeDispatchStatusChange
+	})
+	defer eventBus.Unsubscribe(subscriberID)
+
+	flush := func() {
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+	}
+	send := func(status dispatch.SystemStatus) {
+		data, err := json.Marshal(status)
+		if err != nil {
+			return
+		}
+		fmt.Fprintf(w, "event: status\ndata: %s\n\n", data)
+		flush()
+	}
+
+	send(d.GetSystemStatus())
+
+	keepalive := time.NewTicker(dispatchStatusKeepalive)
+	defer keepalive.Stop()
+
+	ctx := r.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			// Client disconnected
+			return
+		case event, ok := <-subscriber.Channel:
+			if !ok {
+				return
+			}
+			if status, ok := dispatch.StatusFromEvent(event); ok {
+				send(status)
+			}
+		case <-keepalive.C:
+			fmt.Fprintf(w, ": keepalive\n\n")
+			flush()
+		}
+	}
+}
@@ -142,6 +142,10 @@ func (s *Server) handleGitHubWebhook(w http.ResponseWriter, r *http.Request) {
 	// Verify webhook signature if secret is configured
 	if s.config != nil && s.config.Security.WebhookSecret != "" {
 		signature := r.Header.Get("X-Hub-Signature-256")
+		if signature == "" {
+			s.respondError(w, http.StatusBadRequest, "Missing X-Hub-Signature-256 header")
+			return
+		}
 		if !verifyGitHubSignature(body, signature, s.config.Security.WebhookSecret) {
 			s.respondError(w, http.StatusUnauthorized, "Invalid webhook signature")
 			return
@@ -155,6 +159,12 @@ func (s *Server) handleGitHubWebhook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// GitHub sends a ping when the webhook is created; acknowledge it only
+	if eventType == "ping" {
+		s.respondJSON(w, http.StatusOK, map[string]string{"status": "pong"})
+		return
+	}
+
 	// Parse the payload
 	var payload GitHubWebhookPayload
 	if err := json.Unmarshal(body, &payload); err != nil {
@@ -170,6 +180,17 @@ func (s *Server) handleGitHubWebhook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	s.deliverWebhookEvent(webhookEvent, "github-webhook")
+
+	s.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "received",
+		"event":  webhookEvent,
+	})
+}
+
+// deliverWebhookEvent creates any requested review bead, publishes the event
+// to the event bus and stores it for the motivation system
+func (s *Server) deliverWebhookEvent(webhookEvent *WebhookEvent, source string) {
 	// Create code review bead if needed
 	if triggerReview, ok := webhookEvent.Data["trigger_code_review"].(bool); ok && triggerReview {
 		if err := s.createCodeReviewBead(webhookEvent); err != nil {
@@ -204,13 +225,15 @@ func (s *Server) handleGitHubWebhook(w http.ResponseWriter, r *http.Request) {
 				ebEventType = eventbus.EventType("external.github_comment")
 			case "release_published":
 				ebEventType = eventbus.EventType("external.release")
+			case "gitlab_push":
+				ebEventType = eventbus.EventType("external.git_push")
 			default:
 				ebEventType = eventbus.EventType("external.webhook")
 			}
 
 			_ = eb.Publish(&eventbus.Event{
 				Type:   ebEventType,
-				Source: "github-webhook",
+				Source: source,
 				Data:   eventData,
 			})
 		}
@@ -220,11 +243,6 @@ func (s *Server) handleGitHubWebhook(w http.ResponseWriter, r *http.Request) {
 	if s.app != nil {
 		s.storeExternalEvent(webhookEvent)
 	}
-
-	s.respondJSON(w, http.StatusOK, map[string]interface{}{
-		"status": "received",
-		"event":  webhookEvent,
-	})
 }
 
 // processGitHubEvent converts a GitHub webhook into a motivation-relevant event
@@ -397,8 +415,10 @@ func (s *Server) handleWebhookStatus(w http.ResponseWriter, r *http.Request) {
 	}
 
 	status := map[string]interface{}{
-		"github_webhook_enabled":    true,
-		"webhook_secret_configured": s.config != nil && s.config.Security.WebhookSecret != "",
+		"github_webhook_enabled":           true,
+		"webhook_secret_configured":        s.config != nil && s.config.Security.WebhookSecret != "",
+		"gitlab_webhook_enabled":           true,
+		"gitlab_webhook_secret_configured": s.config != nil && s.config.Security.GitLabWebhookSecret != "",
 	}
 
 	// Check if motivation engine is available
@@ -409,21 +429,27 @@ func (s *Server) handleWebhookStatus(w http.ResponseWriter, r *http.Request) {
 	s.respondJSON(w, http.StatusOK, status)
 }
 
-// verifyGitHubSignature verifies the GitHub webhook signature
+// verifyGitHubSignature verifies a "sha256=<hex>" GitHub webhook signature
+// in constant time
 func verifyGitHubSignature(payload []byte, signature, secret string) bool {
 	if signature == "" || secret == "" {
 		return false
 	}
 
-	// Remove "sha256=" prefix
-	signature = strings.TrimPrefix(signature, "sha256=")
+	hexSig, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return false
+	}
+	provided, err := hex.DecodeString(hexSig)
+	if err != nil {
+		return false
+	}
 
 	// Compute expected signature
 	mac := hmac.New(sha256.New, []byte(secret))
 	mac.Write(payload)
-	expected := hex.EncodeToString(mac.Sum(nil))
 
-	return hmac.Equal([]byte(signature), []byte(expected))
+	return hmac.Equal(provided, mac.Sum(nil))
 }
 
 // generateEventID generates a unique event ID
@@ -0,0 +1,197 @@
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+)
+
+// GitLabWebhookPayload represents the fields of GitLab Push Hook and Issue
+// Hook payloads that Loom uses
+type GitLabWebhookPayload struct {
+	ObjectKind       string                 `json:"object_kind"`
+	Ref              string                 `json:"ref,omitempty"`
+	Before           string                 `json:"before,omitempty"`
+	After            string                 `json:"after,omitempty"`
+	UserUsername     string                 `json:"user_username,omitempty"`
+	TotalCommits     int                    `json:"total_commits_count,omitempty"`
+	Commits          []GitLabCommit         `json:"commits,omitempty"`
+	User             *GitLabUser            `json:"user,omitempty"`
+	Project          *GitLabProject         `json:"project,omitempty"`
+	ObjectAttributes *GitLabIssueAttributes `json:"object_attributes,omitempty"`
+	Labels           []GitLabLabel          `json:"labels,omitempty"`
+}
+
+// GitLabProject represents a GitLab project
+type GitLabProject struct {
+	ID                int64  `json:"id"`
+	Name              string `json:"name"`
+	PathWithNamespace string `json:"path_with_namespace"`
+	WebURL            string `json:"web_url"`
+}
+
+// GitLabUser represents a GitLab user
+type GitLabUser struct {
+	ID       int64  `json:"id"`
+	Name     string `json:"name"`
+	Username string `json:"username"`
+}
+
+// GitLabCommit represents a commit in a push event
+type GitLabCommit struct {
+	ID      string `json:"id"`
+	Message string `json:"message"`
+	URL     string `json:"url"`
+}
+
+// GitLabIssueAttributes represents the issue in an issue event
+type GitLabIssueAttributes struct {
+	ID          int64  `json:"id"`
+	IID         int    `json:"iid"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	State       string `json:"state"`
+	Action      string `json:"action"`
+	URL         string `json:"url"`
+}
+
+// GitLabLabel represents a GitLab label
+type GitLabLabel struct {
+	ID    int64  `json:"id"`
+	Title string `json:"title"`
+}
+
+// handleGitLabWebhook handles incoming GitLab webhook events
+// POST /api/v1/webhooks/gitlab
+func (s *Server) handleGitLabWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	// Read the body
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		s.respondError(w, http.StatusBadRequest, "Failed to read request body")
+		return
+	}
+	defer r.Body.Close()
+
+	// Verify webhook token if secret is configured
+	if s.config != nil && s.config.Security.GitLabWebhookSecret != "" {
+		if !verifyGitLabToken(r.Header.Get("X-Gitlab-Token"), s.config.Security.GitLabWebhookSecret) {
+			s.respondError(w, http.StatusUnauthorized, "Invalid webhook token")
+			return
+		}
+	}
+
+	// Get the event type
+	eventType := r.Header.Get("X-Gitlab-Event")
+	if eventType == "" {
+		s.respondError(w, http.StatusBadRequest, "Missing X-Gitlab-Event header")
+		return
+	}
+
+	// Parse the payload
+	var payload GitLabWebhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		s.respondError(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	// Process the event
+	webhookEvent := s.processGitLabEvent(eventType, &payload)
+	if webhookEvent == nil {
+		// Event type not relevant to motivation system
+		s.respondJSON(w, http.StatusOK, map[string]string{"status": "ignored"})
+		return
+	}
+
+	s.deliverWebhookEvent(webhookEvent, "gitlab-webhook")
+
+	s.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "received",
+		"event":  webhookEvent,
+	})
+}
+
+// processGitLabEvent converts a GitLab webhook into a motivation-relevant
+// event. Issue events use the same event types as GitHub issues so existing
+// motivations fire for both.
+func (s *Server) processGitLabEvent(eventType string, payload *GitLabWebhookPayload) *WebhookEvent {
+	event := &WebhookEvent{
+		ID:         generateEventID(),
+		Source:     "gitlab",
+		ReceivedAt: time.Now(),
+		Data:       make(map[string]interface{}),
+	}
+
+	if payload.Project != nil {
+		event.Repository = payload.Project.PathWithNamespace
+	}
+
+	switch eventType {
+	case "Push Hook":
+		event.Type = "gitlab_push"
+		event.Action = "push"
+		event.Data["ref"] = payload.Ref
+		event.Data["before"] = payload.Before
+		event.Data["after"] = payload.After
+		event.Data["author"] = payload.UserUsername
+		event.Data["commit_count"] = payload.TotalCommits
+		commits := make([]string, 0, len(payload.Commits))
+		for _, c := range payload.Commits {
+			commits = append(commits, c.ID)
+		}
+		event.Data["commits"] = commits
+
+	case "Issue Hook":
+		issue := payload.ObjectAttributes
+		if issue == nil {
+			return nil
+		}
+		event.Action = issue.Action
+		switch issue.Action {
+		case "open":
+			event.Type = "github_issue_opened"
+			event.Data["issue_number"] = issue.IID
+			event.Data["issue_title"] = issue.Title
+			event.Data["issue_url"] = issue.URL
+			if payload.User != nil {
+				event.Data["author"] = payload.User.Username
+			}
+			labels := make([]string, 0)
+			for _, l := range payload.Labels {
+				labels = append(labels, l.Title)
+			}
+			event.Data["labels"] = labels
+		case "close":
+			event.Type = "github_issue_closed"
+			event.Data["issue_number"] = issue.IID
+		case "reopen":
+			event.Type = "github_issue_reopened"
+			event.Data["issue_number"] = issue.IID
+		case "update":
+			event.Type = "github_issue_edited"
+			event.Data["issue_number"] = issue.IID
+		default:
+			return nil // Not relevant
+		}
+
+	default:
+		return nil // Event type not relevant
+	}
+
+	return event
+}
+
+// verifyGitLabToken compares the X-Gitlab-Token header with the secret in
+// constant time
+func verifyGitLabToken(token, secret string) bool {
+	if token == "" || secret == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(secret)) == 1
+}
@@ -0,0 +1,153 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jordanhubbard/loom/pkg/config"
+)
+
+func newGitLabWebhookRequest(t *testing.T, eventType, token string, payload interface{}) *http.Request {
+	t.Helper()
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("Failed to marshal payload: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks/gitlab", bytes.NewReader(payloadBytes))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Gitlab-Event", eventType)
+	if token != "" {
+		req.Header.Set("X-Gitlab-Token", token)
+	}
+	return req
+}
+
+func TestGitLabWebhook_IssueOpened(t *testing.T) {
+	cfg := &config.Config{
+		Security: config.SecurityConfig{
+			GitLabWebhookSecret: "test-secret",
+		},
+	}
+	server := NewServer(nil, nil, nil, cfg)
+
+	payload := map[string]interface{}{
+		"object_kind": "issue",
+		"user":        map[string]interface{}{"username": "testuser"},
+		"project":     map[string]interface{}{"path_with_namespace": "group/repo"},
+		"object_attributes": map[string]interface{}{
+			"iid":    7,
+			"title":  "Test issue",
+			"action": "open",
+			"url":    "https://gitlab.com/group/repo/-/issues/7",
+		},
+		"labels": []map[string]interface{}{{"title": "bug"}},
+	}
+
+	w := httptest.NewRecorder()
+	server.handleGitLabWebhook(w, newGitLabWebhookRequest(t, "Issue Hook", "test-secret", payload))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response struct {
+		Status string       `json:"status"`
+		Event  WebhookEvent `json:"event"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if response.Status != "received" {
+		t.Errorf("Expected status 'received', got %q", response.Status)
+	}
+	if response.Event.Type != "github_issue_opened" {
+		t.Errorf("Expected GitHub-compatible type github_issue_opened, got %q", response.Event.Type)
+	}
+	if response.Event.Source != "gitlab" {
+		t.Errorf("Expected source gitlab, got %q", response.Event.Source)
+	}
+	if response.Event.Repository != "group/repo" {
+		t.Errorf("Expected repository group/repo, got %q", response.Event.Repository)
+	}
+	if response.Event.Data["issue_number"] != float64(7) {
+		t.Errorf("Expected issue_number 7, got %v", response.Event.Data["issue_number"])
+	}
+}
+
+func TestGitLabWebhook_Push(t *testing.T) {
+	server := NewServer(nil, nil, nil, &config.Config{})
+
+	payload := map[string]interface{}{
+		"object_kind":         "push",
+		"ref":                 "refs/heads/main",
+		"before":              "abc123",
+		"after":               "def456",
+		"user_username":       "testuser",
+		"total_commits_count": 1,
+		"commits":             []map[string]interface{}{{"id": "def456", "message": "Fix bug"}},
+		"project":             map[string]interface{}{"path_with_namespace": "group/repo"},
+	}
+
+	w := httptest.NewRecorder()
+	server.handleGitLabWebhook(w, newGitLabWebhookRequest(t, "Push Hook", "", payload))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response struct {
+		Event WebhookEvent `json:"event"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if response.Event.Type != "gitlab_push" {
+		t.Errorf("Expected type gitlab_push, got %q", response.Event.Type)
+	}
+	if response.Event.Data["ref"] != "refs/heads/main" {
+		t.Errorf("Expected ref refs/heads/main, got %v", response.Event.Data["ref"])
+	}
+}
+
+func TestGitLabWebhook_InvalidToken(t *testing.T) {
+	cfg := &config.Config{
+		Security: config.SecurityConfig{
+			GitLabWebhookSecret: "test-secret",
+		},
+	}
+	server := NewServer(nil, nil, nil, cfg)
+
+	for _, token := range []string{"", "wrong-secret"} {
+		w := httptest.NewRecorder()
+		server.handleGitLabWebhook(w, newGitLabWebhookRequest(t, "Push Hook", token, map[string]interface{}{}))
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("token %q: expected status 401, got %d", token, w.Code)
+		}
+	}
+}
+
+func TestGitLabWebhook_MissingEventHeader(t *testing.T) {
+	server := NewServer(nil, nil, nil, &config.Config{})
+
+	w := httptest.NewRecorder()
+	server.handleGitLabWebhook(w, newGitLabWebhookRequest(t, "", "", map[string]interface{}{}))
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
+	}
+}
+
+func TestProcessGitLabEvent_Ignored(t *testing.T) {
+	server := NewServer(nil, nil, nil, nil)
+
+	if event := server.processGitLabEvent("Merge Request Hook", &GitLabWebhookPayload{}); event != nil {
+		t.Errorf("Expected nil for unsupported event, got %v", event)
+	}
+	payload := &GitLabWebhookPayload{ObjectAttributes: &GitLabIssueAttributes{Action: "unknown"}}
+	if event := server.processGitLabEvent("Issue Hook", payload); event != nil {
+		t.Errorf("Expected nil for unsupported issue action, got %v", event)
+	}
+}
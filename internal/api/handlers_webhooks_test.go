@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/jordanhubbard/loom/pkg/config"
@@ -142,6 +143,65 @@ func TestGitHubWebhook_InvalidSignature(t *testing.T) {
 	}
 }
 
+func TestGitHubWebhook_MissingSignature(t *testing.T) {
+	cfg := &config.Config{
+		Security: config.SecurityConfig{
+			WebhookSecret: "test-secret",
+		},
+	}
+	server := NewServer(nil, nil, nil, cfg)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks/github", bytes.NewReader([]byte(`{"action":"opened"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Event", "issues")
+
+	w := httptest.NewRecorder()
+	server.handleGitHubWebhook(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
+	}
+}
+
+func TestGitHubWebhook_Ping(t *testing.T) {
+	cfg := &config.Config{
+		Security: config.SecurityConfig{
+			WebhookSecret: "test-secret",
+		},
+	}
+	server := NewServer(nil, nil, nil, cfg)
+
+	payloadBytes := []byte(`{"zen":"Keep it logically awesome.","hook_id":1}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks/github", bytes.NewReader(payloadBytes))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Event", "ping")
+	req.Header.Set("X-Hub-Signature-256", generateSignature(payloadBytes, "test-secret"))
+
+	w := httptest.NewRecorder()
+	server.handleGitHubWebhook(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	var response map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if response["status"] != "pong" {
+		t.Errorf("Expected status 'pong', got %v", response["status"])
+	}
+
+	// An unsigned ping is still rejected
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/webhooks/github", bytes.NewReader(payloadBytes))
+	req.Header.Set("X-GitHub-Event", "ping")
+	req.Header.Set("X-Hub-Signature-256", generateSignature(payloadBytes, "wrong-secret"))
+	w = httptest.NewRecorder()
+	server.handleGitHubWebhook(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 for badly signed ping, got %d", w.Code)
+	}
+}
+
 func TestGitHubWebhook_MissingEventHeader(t *testing.T) {
 	cfg := &config.Config{
 		Security: config.SecurityConfig{
@@ -352,6 +412,16 @@ func TestVerifyGitHubSignature(t *testing.T) {
 	if verifyGitHubSignature(payload, validSig, "") {
 		t.Error("Empty secret should fail verification")
 	}
+
+	// Test missing sha256= prefix
+	if verifyGitHubSignature(payload, strings.TrimPrefix(validSig, "sha256="), secret) {
+		t.Error("Signature without sha256= prefix should fail verification")
+	}
+
+	// Test signature over a different payload
+	if verifyGitHubSignature([]byte(`{"test":"tampered"}`), validSig, secret) {
+		t.Error("Signature for a different payload should fail verification")
+	}
 }
 
 func TestTruncateString(t *testing.T) {
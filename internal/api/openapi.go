@@ -0,0 +1,258 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+var openAPIPathParam = regexp.MustCompile(`\{([^}]+)\}`)
+
+// handleOpenAPISpec handles GET /api/v1/openapi.json. The document is built
+// from the route table, so every registered route is described.
+func (s *Server) handleOpenAPISpec(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	s.respondJSON(w, http.StatusOK, buildOpenAPISpec(s.routes()))
+}
+
+// buildOpenAPISpec returns an OpenAPI 3 document for the routes. Request and
+// response schemas are derived from the Go types recorded on each operation.
+func buildOpenAPISpec(routes []apiRoute) map[string]interface{} {
+	gen := &schemaGenerator{
+		schemas: make(map[string]interface{}),
+		names:   make(map[reflect.Type]string),
+	}
+	paths := make(map[string]map[string]interface{})
+	tagSet := make(map[string]bool)
+	opIDs := make(map[string]int)
+
+	for _, rt := range routes {
+		tagSet[rt.tag] = true
+		for _, op := range rt.ops {
+			path := op.path
+			if path == "" {
+				path = rt.pattern
+			}
+			if paths[path] == nil {
+				paths[path] = make(map[string]interface{})
+			}
+			paths[path][strings.ToLower(op.method)] = gen.operation(rt.tag, path, op, opIDs)
+		}
+	}
+
+	tags := make([]map[string]string, 0, len(tagSet))
+	for tag := range tagSet {
+		tags = append(tags, map[string]string{"name": tag})
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i]["name"] < tags[j]["name"] })
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]string{
+			"title":       "Loom API",
+			"description": "Generated from the server's route table.",
+			"version":     "1.0.0",
+		},
+		"tags":  tags,
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": gen.schemas,
+			"securitySchemes": map[string]interface{}{
+				"ApiKeyAuth": map[string]string{"type": "apiKey", "in": "header", "name": "X-API-Key"},
+				"BearerAuth": map[string]string{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+			},
+		},
+		"security": []map[string][]string{{"ApiKeyAuth": {}}, {"BearerAuth": {}}},
+	}
+}
+
+// operation describes one method of a path.
+func (g *schemaGenerator) operation(tag, path string, op apiOp, opIDs map[string]int) map[string]interface{} {
+	id := operationID(op.method, path)
+	opIDs[id]++
+	if n := opIDs[id]; n > 1 {
+		id = fmt.Sprintf("%s%d", id, n)
+	}
+
+	out := map[string]interface{}{
+		"summary":     op.summary,
+		"operationId": id,
+		"tags":        []string{tag},
+	}
+
+	var params []map[string]interface{}
+	for _, m := range openAPIPathParam.FindAllStringSubmatch(path, -1) {
+		params = append(params, map[string]interface{}{
+			"name":     m[1],
+			"in":       "path",
+			"required": true,
+			"schema":   map[string]string{"type": "string"},
+		})
+	}
+	if len(params) > 0 {
+		out["parameters"] = params
+	}
+
+	if op.request != nil {
+		out["requestBody"] = map[string]interface{}{
+			"required": true,
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{"schema": g.schema(reflect.TypeOf(op.request))},
+			},
+		}
+	}
+
+	success := map[string]interface{}{"description": "Success"}
+	if op.response != nil {
+		success["content"] = map[string]interface{}{
+			"application/json": map[string]interface{}{"schema": g.schema(reflect.TypeOf(op.response))},
+		}
+	}
+	out["responses"] = map[string]interface{}{
+		"2XX":     success,
+		"default": map[string]interface{}{"description": "Error"},
+	}
+
+	if op.public {
+		out["security"] = []map[string][]string{}
+	}
+	if scope := op.requiredScope(tag); scope != "" {
+		out["x-required-scope"] = scope
+	}
+	return out
+}
+
+// operationID turns GET /api/v1/beads/{id}/claim into getBeadsIdClaim.
+func operationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	path = strings.TrimPrefix(path, "/api/v1")
+	for _, part := range strings.FieldsFunc(path, func(r rune) bool {
+		return r == '/' || r == '-' || r == '.' || r == '{' || r == '}' || r == '_'
+	}) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+// schemaGenerator derives JSON schemas from Go types following encoding/json
+// rules. Named structs are emitted once under components/schemas and
+// referenced, which also handles recursive types.
+type schemaGenerator struct {
+	schemas map[string]interface{}
+	names   map[reflect.Type]string
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	durationType      = reflect.TypeOf(time.Duration(0))
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+func (g *schemaGenerator) schema(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case t == durationType:
+		return map[string]interface{}{"type": "integer", "format": "int64", "description": "Duration in nanoseconds"}
+	case t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType):
+		return map[string]interface{}{} // Custom encoding; any value
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]interface{}{"type": "integer", "format": "int32"}
+	case reflect.Float32:
+		return map[string]interface{}{"type": "number", "format": "float"}
+	case reflect.Float64:
+		return map[string]interface{}{"type": "number", "format": "double"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+		return map[string]interface{}{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + g.define(t)}
+	default:
+		return map[string]interface{}{} // interface{} and anything else: any value
+	}
+}
+
+// define registers a named struct under components/schemas and returns its
+// component name. Types sharing a name get their package path prepended.
+func (g *schemaGenerator) define(t reflect.Type) string {
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+	name := t.Name()
+	if _, taken := g.schemas[name]; taken {
+		pkg := strings.TrimPrefix(t.PkgPath(), "github.com/jordanhubbard/loom/")
+		name = strings.ReplaceAll(pkg, "/", ".") + "." + t.Name()
+	}
+	g.names[t] = name
+	g.schemas[name] = nil // Reserve before recursing
+	g.schemas[name] = g.object(t)
+	return name
+}
+
+// object builds an object schema from a struct's JSON fields, flattening
+// embedded structs the way encoding/json does.
+func (g *schemaGenerator) object(t reflect.Type) map[string]interface{} {
+	props := make(map[string]interface{})
+	g.addFields(t, props)
+	return map[string]interface{}{"type": "object", "properties": props}
+}
+
+func (g *schemaGenerator) addFields(t reflect.Type, props map[string]interface{}) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			g.addFields(ft, props)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if strings.Contains(opts, "string") {
+			props[name] = map[string]interface{}{"type": "string"}
+			continue
+		}
+		props[name] = g.schema(f.Type)
+	}
+}
@@ -0,0 +1,145 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jordanhubbard/loom/pkg/config"
+)
+
+func getOpenAPISpec(t *testing.T) map[string]interface{} {
+	t.Helper()
+	s := NewServer(nil, nil, nil, &config.Config{})
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/openapi.json", nil)
+	w := httptest.NewRecorder()
+	s.SetupRoutes().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var spec map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &spec); err != nil {
+		t.Fatalf("spec is not valid JSON: %v", err)
+	}
+	return spec
+}
+
+func TestOpenAPISpec_CoversRouteTable(t *testing.T) {
+	spec := getOpenAPISpec(t)
+	if spec["openapi"] != "3.0.3" {
+		t.Errorf("expected openapi 3.0.3, got %v", spec["openapi"])
+	}
+	paths := spec["paths"].(map[string]interface{})
+
+	s := NewServer(nil, nil, nil, &config.Config{})
+	for _, rt := range s.routes() {
+		if len(rt.ops) == 0 {
+			t.Errorf("route %s has no documented operations", rt.pattern)
+		}
+		for _, op := range rt.ops {
+			path := op.path
+			if path == "" {
+				path = rt.pattern
+			}
+			item, ok := paths[path].(map[string]interface{})
+			if !ok {
+				t.Errorf("path %s missing from spec", path)
+				continue
+			}
+			if _, ok := item[strings.ToLower(op.method)]; !ok {
+				t.Errorf("%s %s missing from spec", op.method, path)
+			}
+		}
+	}
+}
+
+func TestOpenAPISpec_Schemas(t *testing.T) {
+	spec := getOpenAPISpec(t)
+	paths := spec["paths"].(map[string]interface{})
+	schemas := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+
+	create := paths["/api/v1/beads"].(map[string]interface{})["post"].(map[string]interface{})
+	body := create["requestBody"].(map[string]interface{})["content"].(map[string]interface{})["application/json"].(map[string]interface{})
+	ref := body["schema"].(map[string]interface{})["$ref"]
+	if ref != "#/components/schemas/createBeadRequest" {
+		t.Errorf("expected createBeadRequest ref, got %v", ref)
+	}
+	props := schemas["createBeadRequest"].(map[string]interface{})["properties"].(map[string]interface{})
+	for _, field := range []string{"title", "project_id", "idempotency_key", "tags", "required_capability"} {
+		if _, ok := props[field]; !ok {
+			t.Errorf("createBeadRequest schema missing %s", field)
+		}
+	}
+
+	bead := schemas["Bead"].(map[string]interface{})["properties"].(map[string]interface{})
+	if got := bead["created_at"].(map[string]interface{})["format"]; got != "date-time" {
+		t.Errorf("expected date-time for created_at, got %v", got)
+	}
+	if _, ok := bead["schema_version"]; !ok {
+		t.Error("expected embedded EntityMetadata fields to be flattened into Bead")
+	}
+
+	get := paths["/api/v1/beads/{id}"].(map[string]interface{})["get"].(map[string]interface{})
+	params := get["parameters"].([]interface{})
+	if len(params) != 1 || params[0].(map[string]interface{})["name"] != "id" {
+		t.Errorf("expected id path parameter, got %v", params)
+	}
+
+	// Every $ref must resolve.
+	raw, _ := json.Marshal(spec)
+	for _, part := range strings.Split(string(raw), `"$ref":"#/components/schemas/`)[1:] {
+		name := part[:strings.IndexByte(part, '"')]
+		if schemas[name] == nil {
+			t.Errorf("dangling schema ref %s", name)
+		}
+	}
+}
+
+func TestOpenAPISpec_PublicOperationsSkipAuth(t *testing.T) {
+	cfg := &config.Config{Security: config.SecurityConfig{EnableAuth: true}}
+	s := &Server{config: cfg}
+
+	called := false
+	handler := s.authMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	for _, rt := range s.routes() {
+		for _, op := range rt.ops {
+			if !op.public {
+				continue
+			}
+			called = false
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(op.method, rt.pattern, nil))
+			if !called {
+				t.Errorf("%s is documented as unauthenticated but requires auth", rt.pattern)
+			}
+		}
+	}
+}
+
+func TestHandleOpenAPISpec_MethodNotAllowed(t *testing.T) {
+	s := NewServer(nil, nil, nil, &config.Config{})
+	w := httptest.NewRecorder()
+	s.handleOpenAPISpec(w, httptest.NewRequest(http.MethodPost, "/api/v1/openapi.json", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", w.Code)
+	}
+}
+
+func TestOperationID(t *testing.T) {
+	tests := map[string]string{
+		"GET /api/v1/beads/{id}/claim":                       "getBeadsIdClaim",
+		"POST /api/v1/auth/api-keys":                         "postAuthApiKeys",
+		"GET /api/v1/config/export.yaml":                     "getConfigExportYaml",
+		"GET /health/live":                                   "getHealthLive",
+		"DELETE /api/v1/file-locks/{project_id}/{file_path}": "deleteFileLocksProjectIdFilePath",
+	}
+	for in, want := range tests {
+		method, path, _ := strings.Cut(in, " ")
+		if got := operationID(method, path); got != want {
+			t.Errorf("operationID(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
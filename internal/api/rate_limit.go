@@ -0,0 +1,315 @@
+package api
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jordanhubbard/loom/internal/auth"
+	"github.com/jordanhubbard/loom/pkg/config"
+)
+
+// rateLimitSweepInterval is how often idle client buckets are dropped.
+const rateLimitSweepInterval = time.Minute
+
+// rateLimitExempt lists status endpoints that monitoring polls and that are
+// never rate limited. Paths under /health/ are exempt as well.
+var rateLimitExempt = map[string]bool{
+	"/health":                    true,
+	"/api/v1/health":             true,
+	"/api/v1/system/status":      true,
+	"/api/v1/system/rate-limits": true,
+}
+
+// RateLimitUsage is a snapshot of one client's token bucket.
+type RateLimitUsage struct {
+	Key       string  `json:"key"` // "subject:<name>" or "ip:<addr>"
+	Available float64 `json:"available"`
+	Burst     int     `json:"burst"`
+	Limited   int64   `json:"limited"` // Requests rejected since the bucket was created
+}
+
+// clientBucket is a token bucket for one client.
+type clientBucket struct {
+	tokens  float64
+	last    time.Time
+	limited int64
+}
+
+// clientRateLimiter keeps a token bucket per client, each refilled at rps
+// tokens per second up to burst.
+type clientRateLimiter struct {
+	mu        sync.Mutex
+	rps       float64
+	burst     int
+	buckets   map[string]*clientBucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// newClientRateLimiter returns nil when rps is not positive, which disables
+// limiting. A burst below one defaults to one second's worth of requests.
+func newClientRateLimiter(rps float64, burst int) *clientRateLimiter {
+	if rps <= 0 {
+		return nil
+	}
+	return &clientRateLimiter{
+		rps:     rps,
+		burst:   effectiveBurst(rps, burst),
+		buckets: make(map[string]*clientBucket),
+		now:     time.Now,
+	}
+}
+
+// effectiveBurst returns the burst a limiter uses: the configured one, or the
+// rate rounded up when none is set.
+func effectiveBurst(rps float64, burst int) int {
+	if burst < 1 {
+		return int(math.Ceil(rps))
+	}
+	return burst
+}
+
+// refill adds tokens earned since the bucket was last used. The caller must
+// hold l.mu.
+func (l *clientRateLimiter) refill(b *clientBucket, now time.Time) {
+	b.tokens = math.Min(float64(l.burst), b.tokens+now.Sub(b.last).Seconds()*l.rps)
+	b.last = now
+}
+
+// allow takes a token from key's bucket if one is available, otherwise it
+// reports how long until one will be.
+func (l *clientRateLimiter) allow(key string) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b := l.bucket(key)
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0, true
+	}
+	b.limited++
+	return time.Duration((1 - b.tokens) / l.rps * float64(time.Second)), false
+}
+
+// check reports, like allow, whether key has a token available, but does not
+// take it; spend takes one once the request turns out to count.
+func (l *clientRateLimiter) check(key string) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b := l.bucket(key)
+	if b.tokens >= 1 {
+		return 0, true
+	}
+	b.limited++
+	return time.Duration((1 - b.tokens) / l.rps * float64(time.Second)), false
+}
+
+// spend takes a token from key's bucket, going no lower than zero.
+func (l *clientRateLimiter) spend(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b := l.bucket(key)
+	b.tokens = math.Max(0, b.tokens-1)
+}
+
+// bucket returns key's refilled bucket, creating a full one if needed. The
+// caller must hold l.mu.
+func (l *clientRateLimiter) bucket(key string) *clientBucket {
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &clientBucket{tokens: float64(l.burst), last: now}
+		l.buckets[key] = b
+	}
+	l.refill(b, now)
+	return b
+}
+
+// sweep drops buckets that have refilled completely, since a new bucket would
+// be identical. The caller must hold l.mu.
+func (l *clientRateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < rateLimitSweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*l.rps >= float64(l.burst) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// usage returns a snapshot of every tracked client, busiest first.
+func (l *clientRateLimiter) usage() []RateLimitUsage {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	out := make([]RateLimitUsage, 0, len(l.buckets))
+	for key, b := range l.buckets {
+		l.refill(b, now)
+		out = append(out, RateLimitUsage{Key: key, Available: b.tokens, Burst: l.burst, Limited: b.limited})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Available != out[j].Available {
+			return out[i].Available < out[j].Available
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out
+}
+
+// remoteIP returns the host part of the request's remote address.
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// rateLimitKey identifies the client of a request: the authenticated subject
+// when auth is enabled, otherwise the remote IP.
+func (s *Server) rateLimitKey(r *http.Request) string {
+	if s.config != nil && s.config.Security.EnableAuth {
+		if p, ok := auth.PrincipalFromRequest(r); ok && p.Subject != "" {
+			return "subject:" + p.Subject
+		}
+	}
+	return "ip:" + remoteIP(r)
+}
+
+// rateLimitMiddleware rejects requests over the client's rate with 429 and a
+// Retry-After header. It runs inside authMiddleware so the subject is known.
+func (s *Server) rateLimitMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limiter := s.currentRateLimiter()
+		if limiter == nil || rateLimitExempt[r.URL.Path] || strings.HasPrefix(r.URL.Path, "/health/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		retryAfter, ok := limiter.allow(s.rateLimitKey(r))
+		if !ok {
+			if s.metrics != nil {
+				s.metrics.RecordRateLimited()
+			}
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			s.respondError(w, http.StatusTooManyRequests, "Rate limit exceeded")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// authFailureLimitMiddleware runs ahead of authMiddleware and limits, per
+// remote IP, requests that end in 401 or 403. rateLimitMiddleware only sees
+// requests that passed auth, so without this credential guessing and floods
+// of bad tokens would be unlimited. Callers behind a shared address are not
+// throttled by each other's successful requests.
+func (s *Server) authFailureLimitMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limiter := s.currentAuthFailureLimiter()
+		if limiter == nil || rateLimitExempt[r.URL.Path] || strings.HasPrefix(r.URL.Path, "/health/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		key := "ip:" + remoteIP(r)
+		if retryAfter, ok := limiter.check(key); !ok {
+			if s.metrics != nil {
+				s.metrics.RecordRateLimited()
+			}
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			s.respondError(w, http.StatusTooManyRequests, "Too many failed authentication attempts")
+			return
+		}
+		sw := &authStatusWriter{ResponseWriter: w}
+		next.ServeHTTP(sw, r)
+		if sw.status == http.StatusUnauthorized || sw.status == http.StatusForbidden {
+			limiter.spend(key)
+		}
+	})
+}
+
+// authStatusWriter records the status code written by the handler.
+type authStatusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *authStatusWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Flush implements http.Flusher to support streaming
+func (w *authStatusWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// handleRateLimits handles GET /api/v1/system/rate-limits
+func (s *Server) handleRateLimits(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	limiter := s.currentRateLimiter()
+	resp := map[string]interface{}{"enabled": limiter != nil}
+	if limiter != nil {
+		resp["rps"] = limiter.rps
+		resp["burst"] = limiter.burst
+		resp["clients"] = limiter.usage()
+	}
+	s.respondJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) currentRateLimiter() *clientRateLimiter {
+	s.rateLimitMu.RLock()
+	defer s.rateLimitMu.RUnlock()
+	return s.rateLimiter
+}
+
+func (s *Server) currentAuthFailureLimiter() *clientRateLimiter {
+	s.rateLimitMu.RLock()
+	defer s.rateLimitMu.RUnlock()
+	return s.authFailureLimiter
+}
+
+// newAuthFailureLimiter returns nil unless both auth and rate limiting are
+// enabled; it shares the configured rate and burst.
+func newAuthFailureLimiter(cfg *config.Config) *clientRateLimiter {
+	if cfg == nil || !cfg.Security.EnableAuth {
+		return nil
+	}
+	return newClientRateLimiter(cfg.Security.RateLimitRPS, cfg.Security.RateLimitBurst)
+}
+
+// ApplyConfig applies reloaded rate limits. Clients start with full buckets
+// when the limits change. It is registered with config.OnReload.
+func (s *Server) ApplyConfig(cfg *config.Config) {
+	rps := cfg.Security.RateLimitRPS
+	s.rateLimitMu.Lock()
+	defer s.rateLimitMu.Unlock()
+	// Compare the burst after its default is applied, or a config without
+	// one would look changed on every reload
+	unchanged := s.rateLimiter == nil && rps <= 0 ||
+		s.rateLimiter != nil && s.rateLimiter.rps == rps && s.rateLimiter.burst == effectiveBurst(rps, cfg.Security.RateLimitBurst)
+	if unchanged && (s.authFailureLimiter != nil) == (cfg.Security.EnableAuth && rps > 0) {
+		return
+	}
+	s.rateLimiter = newClientRateLimiter(cfg.Security.RateLimitRPS, cfg.Security.RateLimitBurst)
+	s.authFailureLimiter = newAuthFailureLimiter(cfg)
+}
@@ -0,0 +1,221 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jordanhubbard/loom/internal/auth"
+	"github.com/jordanhubbard/loom/pkg/config"
+)
+
+func TestClientRateLimiter_Bucket(t *testing.T) {
+	now := time.Unix(1000, 0)
+	l := newClientRateLimiter(2, 3)
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if _, ok := l.allow("ip:1.2.3.4"); !ok {
+			t.Fatalf("request %d should fit in the burst", i+1)
+		}
+	}
+	retry, ok := l.allow("ip:1.2.3.4")
+	if ok {
+		t.Fatal("expected burst to be exhausted")
+	}
+	if retry != 500*time.Millisecond {
+		t.Errorf("expected 500ms until the next token, got %v", retry)
+	}
+
+	// Other clients have their own bucket.
+	if _, ok := l.allow("ip:5.6.7.8"); !ok {
+		t.Error("a different client should not be limited")
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if _, ok := l.allow("ip:1.2.3.4"); !ok {
+		t.Error("expected a token after refill")
+	}
+}
+
+func TestClientRateLimiter_SweepAndUsage(t *testing.T) {
+	now := time.Unix(1000, 0)
+	l := newClientRateLimiter(1, 1)
+	l.now = func() time.Time { return now }
+
+	l.allow("ip:1.2.3.4")
+	l.allow("ip:1.2.3.4")
+	usage := l.usage()
+	if len(usage) != 1 || usage[0].Limited != 1 || usage[0].Burst != 1 {
+		t.Fatalf("unexpected usage %+v", usage)
+	}
+
+	now = now.Add(2 * rateLimitSweepInterval)
+	l.allow("ip:5.6.7.8")
+	if _, tracked := l.buckets["ip:1.2.3.4"]; tracked {
+		t.Error("idle bucket should have been swept")
+	}
+}
+
+func TestNewClientRateLimiter_Disabled(t *testing.T) {
+	if newClientRateLimiter(0, 10) != nil {
+		t.Error("zero RPS should disable the limiter")
+	}
+	if l := newClientRateLimiter(2.5, 0); l.burst != 3 {
+		t.Errorf("expected burst to default to ceil(rps), got %d", l.burst)
+	}
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	cfg := &config.Config{Security: config.SecurityConfig{EnableAuth: true, RateLimitRPS: 1, RateLimitBurst: 1}}
+	s := &Server{config: cfg, rateLimiter: newClientRateLimiter(1, 1)}
+	handler := s.rateLimitMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	send := func(path, remote, subject string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = remote
+		if subject != "" {
+			req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{Subject: subject}))
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := send("/api/v1/beads", "10.0.0.1:1234", ""); w.Code != http.StatusNoContent {
+		t.Fatalf("first request: expected 204, got %d", w.Code)
+	}
+	w := send("/api/v1/beads", "10.0.0.1:5678", "")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", w.Code)
+	}
+	if w.Header().Get("Retry-After") != "1" {
+		t.Errorf("expected Retry-After 1, got %q", w.Header().Get("Retry-After"))
+	}
+
+	// Authenticated callers are keyed by subject, not address.
+	if w := send("/api/v1/beads", "10.0.0.1:1234", "ci-bot"); w.Code != http.StatusNoContent {
+		t.Errorf("subject bucket should be separate from the IP bucket, got %d", w.Code)
+	}
+	if w := send("/api/v1/beads", "10.0.0.2:1234", "ci-bot"); w.Code != http.StatusTooManyRequests {
+		t.Errorf("same subject from another address should share a bucket, got %d", w.Code)
+	}
+
+	// Health and status endpoints are never limited.
+	for _, path := range []string{"/health", "/health/live", "/api/v1/health", "/api/v1/system/status"} {
+		if w := send(path, "10.0.0.1:1234", ""); w.Code != http.StatusNoContent {
+			t.Errorf("%s should be exempt, got %d", path, w.Code)
+		}
+	}
+}
+
+func TestAuthFailureLimitMiddleware(t *testing.T) {
+	cfg := &config.Config{Security: config.SecurityConfig{EnableAuth: true, RateLimitRPS: 1, RateLimitBurst: 2}}
+	s := &Server{config: cfg, authFailureLimiter: newAuthFailureLimiter(cfg)}
+	calls := 0
+	handler := s.authFailureLimitMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Header.Get("Authorization") != "Bearer good" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	send := func(remote, token string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/beads", nil)
+		req.RemoteAddr = remote
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Successful requests do not use up the address's allowance.
+	for i := 0; i < 5; i++ {
+		if code := send("10.0.0.1:1234", "good"); code != http.StatusNoContent {
+			t.Fatalf("authenticated request %d: expected 204, got %d", i, code)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if code := send("10.0.0.1:1234", "bad"); code != http.StatusUnauthorized {
+			t.Fatalf("failed attempt %d: expected 401, got %d", i, code)
+		}
+	}
+	before := calls
+	if code := send("10.0.0.1:5678", "bad"); code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 once failures exhaust the burst, got %d", code)
+	}
+	if code := send("10.0.0.1:5678", "good"); code != http.StatusTooManyRequests {
+		t.Errorf("address should stay limited until it refills, got %d", code)
+	}
+	if calls != before {
+		t.Error("limited requests should not reach auth")
+	}
+	if code := send("10.0.0.2:1234", "bad"); code != http.StatusUnauthorized {
+		t.Errorf("other addresses should not be limited, got %d", code)
+	}
+
+	if newAuthFailureLimiter(&config.Config{Security: config.SecurityConfig{RateLimitRPS: 1}}) != nil {
+		t.Error("limiter should be disabled when auth is off")
+	}
+}
+
+func TestHandleRateLimits(t *testing.T) {
+	s := &Server{config: &config.Config{}, rateLimiter: newClientRateLimiter(5, 10)}
+	s.rateLimiter.allow("ip:10.0.0.1")
+
+	w := httptest.NewRecorder()
+	s.handleRateLimits(w, httptest.NewRequest(http.MethodGet, "/api/v1/system/rate-limits", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	var resp struct {
+		Enabled bool             `json:"enabled"`
+		Burst   int              `json:"burst"`
+		Clients []RateLimitUsage `json:"clients"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if !resp.Enabled || resp.Burst != 10 || len(resp.Clients) != 1 || resp.Clients[0].Key != "ip:10.0.0.1" {
+		t.Errorf("unexpected response %+v", resp)
+	}
+}
+
+func TestServer_ApplyConfig(t *testing.T) {
+	s := &Server{config: &config.Config{}}
+	s.ApplyConfig(&config.Config{Security: config.SecurityConfig{RateLimitRPS: 2, RateLimitBurst: 4}})
+	limiter := s.currentRateLimiter()
+	if limiter == nil || limiter.rps != 2 || limiter.burst != 4 {
+		t.Fatalf("limiter = %+v, want rps 2 burst 4", limiter)
+	}
+
+	s.ApplyConfig(&config.Config{Security: config.SecurityConfig{RateLimitRPS: 2, RateLimitBurst: 4}})
+	if s.currentRateLimiter() != limiter {
+		t.Error("unchanged limits should keep the existing limiter")
+	}
+
+	// Without a burst the default is the rate, which is not a change
+	s.ApplyConfig(&config.Config{Security: config.SecurityConfig{RateLimitRPS: 2.5, EnableAuth: true}})
+	limiter = s.currentRateLimiter()
+	authLimiter := s.currentAuthFailureLimiter()
+	if limiter == nil || limiter.burst != 3 || authLimiter == nil {
+		t.Fatalf("limiter = %+v, auth limiter = %+v, want burst 3 and both set", limiter, authLimiter)
+	}
+	s.ApplyConfig(&config.Config{Security: config.SecurityConfig{RateLimitRPS: 2.5, EnableAuth: true}})
+	if s.currentRateLimiter() != limiter || s.currentAuthFailureLimiter() != authLimiter {
+		t.Error("reloading a config without a burst should keep the existing limiters")
+	}
+
+	s.ApplyConfig(&config.Config{})
+	if s.currentRateLimiter() != nil {
+		t.Error("zero rps should disable rate limiting")
+	}
+}
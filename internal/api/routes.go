@@ -0,0 +1,448 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/jordanhubbard/loom/internal/agent"
+	"github.com/jordanhubbard/loom/internal/analytics"
+	"github.com/jordanhubbard/loom/internal/auth"
+	"github.com/jordanhubbard/loom/internal/beads"
+	"github.com/jordanhubbard/loom/internal/comments"
+	"github.com/jordanhubbard/loom/internal/dispatch"
+	internalmodels "github.com/jordanhubbard/loom/internal/models"
+	"github.com/jordanhubbard/loom/internal/workflow"
+	"github.com/jordanhubbard/loom/pkg/models"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// apiRoute is one entry of the route table. SetupRoutes registers each
+// pattern and handleOpenAPISpec documents its operations, so the two can't
+// drift apart.
+type apiRoute struct {
+	pattern string // ServeMux pattern; a trailing slash matches sub-paths
+	handler http.HandlerFunc
+	tag     string
+	ops     []apiOp
+}
+
+// apiOp documents one method on a route.
+type apiOp struct {
+	method   string
+	path     string // OpenAPI path when it differs from the pattern, e.g. /api/v1/beads/{id}
+	summary  string
+	request  interface{} // Value whose type is the JSON request body, if any
+	response interface{} // Value whose type is the JSON success response, if known
+	public   bool        // Served without authentication
+	scope    string      // Required scope when it differs from the tag default
+}
+
+func opGet(summary string, response interface{}) apiOp {
+	return apiOp{method: http.MethodGet, summary: summary, response: response}
+}
+
+func opPost(summary string, request, response interface{}) apiOp {
+	return apiOp{method: http.MethodPost, summary: summary, request: request, response: response}
+}
+
+func opPut(summary string, request, response interface{}) apiOp {
+	return apiOp{method: http.MethodPut, summary: summary, request: request, response: response}
+}
+
+func opPatch(summary string, request, response interface{}) apiOp {
+	return apiOp{method: http.MethodPatch, summary: summary, request: request, response: response}
+}
+
+func opDelete(summary string) apiOp {
+	return apiOp{method: http.MethodDelete, summary: summary}
+}
+
+// at sets the documented path of an operation on a sub-path route.
+func (op apiOp) at(path string) apiOp {
+	op.path = path
+	return op
+}
+
+// unauthenticated marks an operation as not requiring credentials.
+func (op apiOp) unauthenticated() apiOp {
+	op.public = true
+	return op
+}
+
+// requires sets the scope a caller must hold for the operation.
+func (op apiOp) requires(scope string) apiOp {
+	op.scope = scope
+	return op
+}
+
+// tagScopes maps route tags to the permission resource guarding them. Routes
+// under other tags are open to any authenticated caller.
+var tagScopes = map[string]string{
+	"Agents":    "agents",
+	"Analytics": "analytics",
+	"Beads":     "beads",
+	"Decisions": "decisions",
+	"Projects":  "projects",
+	"Providers": "providers",
+}
+
+// requiredScope returns the scope needed to call the operation, or "" when
+// none is. Reads need resource:read, deletes resource:delete and every other
+// method resource:write.
+func (op apiOp) requiredScope(tag string) string {
+	if op.public {
+		return ""
+	}
+	if op.scope != "" {
+		return op.scope
+	}
+	resource, ok := tagScopes[tag]
+	if !ok {
+		return ""
+	}
+	switch op.method {
+	case http.MethodGet:
+		return resource + ":read"
+	case http.MethodDelete:
+		return resource + ":delete"
+	default:
+		return resource + ":write"
+	}
+}
+
+// requireScopes enforces the scopes of a route's operations. Operations that
+// share a method on a route share its scope; undocumented methods fall
+// through to the handler, which rejects them itself.
+func (s *Server) requireScopes(rt apiRoute, next http.HandlerFunc) http.HandlerFunc {
+	scopes := make(map[string]string)
+	for _, op := range rt.ops {
+		if scope := op.requiredScope(rt.tag); scope != "" {
+			scopes[op.method] = scope
+		}
+	}
+	if len(scopes) == 0 {
+		return next
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		scope, ok := scopes[r.Method]
+		if !ok {
+			next(w, r)
+			return
+		}
+		auth.RequireScope(scope)(next).ServeHTTP(w, r)
+	}
+}
+
+// routes returns the API route table in registration order.
+func (s *Server) routes() []apiRoute {
+	authHandlers := auth.NewHandlers(s.authManager)
+
+	return []apiRoute{
+		// Serve OpenAPI spec
+		{"/api/openapi.yaml", func(w http.ResponseWriter, r *http.Request) {
+			http.ServeFile(w, r, "./api/openapi.yaml")
+		}, "Meta", []apiOp{opGet("Hand-written OpenAPI document (YAML)", nil).unauthenticated()}},
+		{"/api/v1/openapi.json", s.handleOpenAPISpec, "Meta", []apiOp{opGet("Generated OpenAPI document for every registered route", nil).unauthenticated()}},
+
+		// Health check
+		{"/api/v1/health", s.handleHealth, "Health", []apiOp{opGet("Health check", map[string]string{}).unauthenticated()}},
+
+		// Prometheus metrics endpoint
+		{"/metrics", promhttp.Handler().ServeHTTP, "Health", []apiOp{opGet("Prometheus metrics", nil)}},
+
+		// Auth endpoints
+		{"/api/v1/auth/login", authHandlers.HandleLogin, "Auth", []apiOp{opPost("Log in", auth.LoginRequest{}, auth.LoginResponse{}).unauthenticated()}},
+		{"/api/v1/auth/refresh", authHandlers.HandleRefreshToken, "Auth", []apiOp{opPost("Refresh a token", auth.RefreshTokenRequest{}, auth.LoginResponse{}).unauthenticated()}},
+		{"/api/v1/auth/change-password", authHandlers.HandleChangePassword, "Auth", []apiOp{opPost("Change the current user's password", auth.ChangePasswordRequest{}, nil)}},
+		{"/api/v1/auth/api-keys", authHandlers.HandleCreateAPIKey, "Auth", []apiOp{opPost("Create an API key", auth.CreateAPIKeyRequest{}, auth.CreateAPIKeyResponse{})}},
+		{"/api/v1/auth/me", authHandlers.HandleGetCurrentUser, "Auth", []apiOp{opGet("Get the current user", auth.User{})}},
+		{"/api/v1/auth/users", func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodPost:
+				authHandlers.HandleCreateUser(w, r)
+			case http.MethodGet:
+				authHandlers.HandleListUsers(w, r)
+			default:
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			}
+		}, "Auth", []apiOp{opGet("List users", []auth.User{}), opPost("Create a user", nil, auth.User{})}},
+
+		// Personas
+		{"/api/v1/personas", s.handlePersonas, "Personas", []apiOp{opGet("List personas", []models.Persona{})}},
+		{"/api/v1/personas/", s.handlePersona, "Personas", []apiOp{
+			opGet("Get a persona", models.Persona{}).at("/api/v1/personas/{name}"),
+			opPut("Update a persona", models.Persona{}, models.Persona{}).at("/api/v1/personas/{name}"),
+		}},
+
+		// Agents
+		{"/api/v1/agents", s.handleAgents, "Agents", []apiOp{
+			opGet("List agents", []models.Agent{}),
+			opPost("Spawn an agent", createAgentRequest{}, models.Agent{}),
+		}},
+		{"/api/v1/agents/", s.handleAgent, "Agents", []apiOp{
+			opGet("Get an agent", models.Agent{}).at("/api/v1/agents/{id}"),
+			opPut("Update an agent", nil, models.Agent{}).at("/api/v1/agents/{id}"),
+			opDelete("Stop an agent").at("/api/v1/agents/{id}"),
+			opPost("Clone an agent", nil, models.Agent{}).at("/api/v1/agents/{id}/clone"),
+			opGet("Recent task history of an agent", []agent.TaskHistoryEntry{}).at("/api/v1/agents/{id}/history"),
+			opGet("Recent execution logs of an agent; ?follow=true streams new lines (SSE)", []agent.AgentLogLine{}).at("/api/v1/agents/{id}/logs"),
+		}},
+		{"/api/v1/project-agents/", s.handleProjectAgent, "Agents", []apiOp{
+			opPost("Register a project agent container", projectAgentHeartbeat{}, nil).at("/api/v1/project-agents/register"),
+			opPost("Heartbeat from a project agent container", projectAgentHeartbeat{}, nil).at("/api/v1/project-agents/{project_id}/heartbeat"),
+			opPost("Task result from a project agent container", projectAgentResult{}, nil).at("/api/v1/project-agents/{project_id}/results"),
+		}},
+
+		// Projects (includes /projects/{id}/files/*)
+		{"/api/v1/projects/bootstrap", s.handleBootstrapProject, "Projects", []apiOp{opPost("Bootstrap a new project", nil, nil)}},
+		{"/api/v1/projects", s.handleProjects, "Projects", []apiOp{
+			opGet("List projects", []models.Project{}),
+			opPost("Create a project", createProjectRequest{}, models.Project{}),
+		}},
+		{"/api/v1/projects/", s.handleProject, "Projects", []apiOp{
+			opGet("Get a project", models.Project{}).at("/api/v1/projects/{id}"),
+			opPut("Update a project", nil, models.Project{}).at("/api/v1/projects/{id}"),
+			opDelete("Delete a project").at("/api/v1/projects/{id}"),
+			opGet("Project bead progress and spend", nil).at("/api/v1/projects/{id}/dashboard"),
+			opGet("Project beads as a Markdown report", nil).at("/api/v1/projects/{id}/export.md"),
+			opPost("Import beads from a YAML or Markdown task file", nil, nil).at("/api/v1/projects/{id}/import"),
+		}},
+
+		// Org Charts
+		{"/api/v1/org-charts/", s.handleOrgChart, "Projects", []apiOp{opGet("Get a project's org chart", nil).at("/api/v1/org-charts/{project_id}")}},
+
+		// Beads
+		{"/api/v1/beads", s.handleBeads, "Beads", []apiOp{
+			opGet("List beads", []models.Bead{}),
+			opPost("Create a bead", createBeadRequest{}, models.Bead{}),
+		}},
+		{"/api/v1/beads/", s.handleBead, "Beads", []apiOp{
+			opGet("Get a bead", models.Bead{}).at("/api/v1/beads/{id}"),
+			opPatch("Update a bead", updateBeadRequest{}, models.Bead{}).at("/api/v1/beads/{id}"),
+			opPost("Claim a bead for an agent", claimBeadRequest{}, nil).at("/api/v1/beads/{id}/claim"),
+			opPost("Cancel a bead's running task and reopen it", nil, nil).at("/api/v1/beads/{id}/cancel"),
+			opGet("Comments on a bead, threaded unless flat=true", []comments.Comment{}).at("/api/v1/beads/{id}/comments"),
+			opPost("Comment on a bead or reply to a comment", nil, comments.Comment{}).at("/api/v1/beads/{id}/comments"),
+		}},
+		{"/api/v1/beads/claim", s.handleClaimBeads, "Beads", []apiOp{opPost("Claim a batch of beads for an agent", claimBeadsRequest{}, beads.ClaimResult{})}},
+
+		// Connectors
+		{"/api/v1/connectors", s.HandleConnectors, "Connectors", []apiOp{
+			opGet("List connectors", []ConnectorResponse{}),
+			opPost("Create a connector", nil, ConnectorResponse{}),
+		}},
+		{"/api/v1/connectors/", s.HandleConnectors, "Connectors", []apiOp{
+			opGet("Get a connector", ConnectorResponse{}).at("/api/v1/connectors/{id}"),
+			opPut("Update a connector", nil, ConnectorResponse{}).at("/api/v1/connectors/{id}"),
+			opDelete("Delete a connector").at("/api/v1/connectors/{id}"),
+		}},
+
+		// Federation
+		{"/api/v1/federation/status", s.handleFederationStatus, "Federation", []apiOp{opGet("Federation status", nil)}},
+		{"/api/v1/federation/sync", s.handleFederationSync, "Federation", []apiOp{opPost("Sync with federated peers", nil, nil)}},
+		{"/api/v1/federation/beads", s.handleFederationBeads, "Federation", []apiOp{
+			opGet("Bead index served to federation peers", []models.Bead{}).requires("beads:read"),
+			opPost("Merge beads pushed by a federation peer", []models.Bead{}, beads.FederationMergeResult{}).requires("beads:write"),
+		}},
+
+		// Comments on beads are served by handleBead at /beads/{id}/comments
+		{"/api/v1/comments/", s.handleComment, "Beads", []apiOp{
+			opPatch("Edit a comment", nil, nil).at("/api/v1/comments/{id}"),
+			opDelete("Delete a comment").at("/api/v1/comments/{id}"),
+		}},
+
+		// Conversations
+		{"/api/v1/conversations", s.handleConversationsList, "Conversations", []apiOp{opGet("List conversations of a project", nil)}},
+		{"/api/v1/conversations/", s.handleConversation, "Conversations", []apiOp{
+			opGet("Get a conversation", models.ConversationContext{}).at("/api/v1/conversations/{id}"),
+			opDelete("Delete a conversation").at("/api/v1/conversations/{id}"),
+			opPost("Reset a conversation", nil, nil).at("/api/v1/conversations/{id}/reset"),
+		}},
+
+		// Decisions
+		{"/api/v1/decisions", s.handleDecisions, "Decisions", []apiOp{opGet("List decisions", []models.DecisionBead{})}},
+		{"/api/v1/decisions/", s.handleDecision, "Decisions", []apiOp{
+			opGet("Get a decision", models.DecisionBead{}).at("/api/v1/decisions/{id}"),
+			opPost("Resolve a decision", nil, nil).at("/api/v1/decisions/{id}/decide"),
+		}},
+
+		// File locks
+		{"/api/v1/file-locks", s.handleFileLocks, "File Locks", []apiOp{
+			opGet("List file locks", []models.FileLock{}),
+			opPost("Acquire a file lock", nil, models.FileLock{}),
+		}},
+		{"/api/v1/file-locks/", s.handleFileLock, "File Locks", []apiOp{opDelete("Release a file lock").at("/api/v1/file-locks/{project_id}/{file_path}")}},
+
+		// Work graph
+		{"/api/v1/work-graph", s.handleWorkGraph, "Beads", []apiOp{opGet("Get the bead dependency graph; with since, only what changed after it", models.WorkGraph{})}},
+
+		// Providers
+		{"/api/v1/providers", s.handleProviders, "Providers", []apiOp{
+			opGet("List providers", []internalmodels.Provider{}),
+			opPost("Register a provider", ProviderRequest{}, internalmodels.Provider{}),
+		}},
+		{"/api/v1/providers/", s.handleProvider, "Providers", []apiOp{
+			opGet("Get a provider", internalmodels.Provider{}).at("/api/v1/providers/{id}"),
+			opPut("Update a provider", internalmodels.Provider{}, internalmodels.Provider{}).at("/api/v1/providers/{id}"),
+			opDelete("Delete a provider").at("/api/v1/providers/{id}"),
+			opGet("List a provider's models", nil).at("/api/v1/providers/{id}/models"),
+			opPost("Refresh a provider's cached models", nil, nil).at("/api/v1/providers/{id}/models/refresh"),
+			opPost("Negotiate a provider's model", nil, internalmodels.Provider{}).at("/api/v1/providers/{id}/negotiate"),
+		}},
+		{"/api/v1/routing/select", s.handleSelectProvider, "Providers", []apiOp{opPost("Select a provider for a request", nil, nil).requires("providers:read")}},
+		{"/api/v1/routing/policies", s.handleGetRoutingPolicies, "Providers", []apiOp{opGet("List routing policies", nil)}},
+
+		// Models
+		{"/api/v1/models/recommended", s.handleRecommendedModels, "Providers", []apiOp{opGet("Recommended models", nil)}},
+
+		// System
+		{"/api/v1/system/status", s.handleSystemStatus, "System", []apiOp{opGet("Dispatcher status", dispatch.SystemStatus{})}},
+		{"/api/v1/system/rate-limits", s.handleRateLimits, "System", []apiOp{opGet("Per-client rate limiter usage", nil)}},
+		{"/api/v1/dispatch/metrics", s.handleDispatchMetrics, "System", []apiOp{opGet("Dispatch metrics", dispatch.DispatchMetrics{})}},
+		{"/api/v1/dispatch/status/stream", s.handleDispatchStatusStream, "System", []apiOp{opGet("Stream dispatcher status changes (SSE)", nil)}},
+
+		// Work (non-bead prompts)
+		{"/api/v1/work", s.handleWork, "Agents", []apiOp{opPost("Run a one-off prompt on an agent", nil, nil)}},
+
+		// CEO REPL
+		{"/api/v1/repl", s.handleRepl, "Agents", []apiOp{opPost("Send a CEO REPL message", nil, nil).requires("repl:use")}},
+
+		// Shell command execution
+		{"/api/v1/commands/execute", s.HandleExecuteCommand, "Commands", []apiOp{opPost("Execute a shell command", nil, models.CommandLog{})}},
+		{"/api/v1/commands", s.HandleGetCommandLogs, "Commands", []apiOp{opGet("List command logs", []models.CommandLog{})}},
+		{"/api/v1/commands/", s.HandleGetCommandLogs, "Commands", []apiOp{opGet("Get a command log", models.CommandLog{}).at("/api/v1/commands/{id}")}},
+
+		// Auto-filed bug reports
+		{"/api/v1/beads/auto-file", s.HandleAutoFileBug, "Beads", []apiOp{opPost("File a bug bead automatically", AutoFileBugRequest{}, nil)}},
+
+		// Logging endpoints
+		{"/api/v1/logs/recent", s.HandleLogsRecent, "Logs", []apiOp{opGet("Recent log entries", nil)}},
+		{"/api/v1/logs/stream", s.HandleLogsStream, "Logs", []apiOp{opGet("Stream log entries (SSE)", nil)}},
+		{"/api/v1/logs/export", s.HandleLogsExport, "Logs", []apiOp{opGet("Export log entries", nil)}},
+
+		// Chat completions (with streaming support)
+		{"/api/v1/chat/completions/stream", s.handleStreamChatCompletion, "Chat", []apiOp{opPost("Stream a chat completion (SSE)", StreamChatCompletionRequest{}, nil).unauthenticated()}},
+		{"/api/v1/chat/completions", s.handleChatCompletion, "Chat", []apiOp{opPost("Create a chat completion", StreamChatCompletionRequest{}, nil).unauthenticated()}},
+
+		// Pair-programming chat (SSE streaming with conversation persistence)
+		{"/api/v1/pair", s.handlePairChat, "Chat", []apiOp{opPost("Pair-programming chat (SSE)", PairChatRequest{}, nil).unauthenticated()}},
+
+		// Git operations
+		{"/api/v1/projects/git/sync", s.handleGitSync, "Git", []apiOp{opPost("Sync a project repository", nil, nil)}},
+		{"/api/v1/projects/git/commit", s.handleGitCommit, "Git", []apiOp{opPost("Commit project changes", nil, nil)}},
+		{"/api/v1/projects/git/push", s.handleGitPush, "Git", []apiOp{opPost("Push project commits", nil, nil)}},
+		{"/api/v1/projects/git/status", s.handleGitStatus, "Git", []apiOp{opGet("Project repository status", nil)}},
+		{"/api/v1/projects/git/diff", s.handleGitDiff, "Git", []apiOp{opGet("Uncommitted changes of a project repository", gitDiffResponse{})}},
+
+		// Analytics and cost tracking
+		{"/api/v1/analytics/logs", s.handleGetLogs, "Analytics", []apiOp{opGet("List request logs", []analytics.RequestLog{})}},
+		{"/api/v1/analytics/stats", s.handleGetLogStats, "Analytics", []apiOp{opGet("Request statistics", analytics.LogStats{})}},
+		{"/api/v1/analytics/export", s.handleExportLogs, "Analytics", []apiOp{opGet("Export request logs", nil)}},
+		{"/api/v1/analytics/export-stats", s.handleExportStats, "Analytics", []apiOp{opGet("Export request statistics", nil)}},
+		{"/api/v1/analytics/costs", s.handleGetCostReport, "Analytics", []apiOp{opGet("Cost report", nil)}},
+		{"/api/v1/analytics/forecast", s.handleCostForecast, "Analytics", []apiOp{opGet("Cost forecast", analytics.CostForecast{})}},
+		{"/api/v1/analytics/batching", s.handleGetBatchingRecommendations, "Analytics", []apiOp{opGet("Batching recommendations", analytics.BatchingRecommendations{})}},
+		{"/api/v1/analytics/batching/execute", s.handleExecuteBatchPlan, "Analytics", []apiOp{opPost("Execute an auto-batch plan", executeBatchPlanRequest{}, analytics.BatchPlanResult{})}},
+		{"/api/v1/analytics/change-velocity", s.handleGetChangeVelocity, "Analytics", []apiOp{opGet("Change velocity metrics", analytics.ChangeVelocityMetrics{})}},
+
+		// Debug endpoints
+		{"/api/v1/debug/capture-ui", s.handleCaptureUI, "Debug", []apiOp{opPost("Capture the web UI state", UICaptureRequest{}, nil)}},
+
+		// Cache management
+		{"/api/v1/cache/stats", s.handleGetCacheStats, "Cache", []apiOp{opGet("Cache statistics", nil)}},
+		{"/api/v1/cache/config", s.handleGetCacheConfig, "Cache", []apiOp{opGet("Cache configuration", nil)}},
+		{"/api/v1/cache/clear", s.handleClearCache, "Cache", []apiOp{opPost("Clear the cache", nil, nil)}},
+		{"/api/v1/cache/invalidate", s.handleInvalidateCache, "Cache", []apiOp{opPost("Invalidate cache entries", nil, nil)}},
+
+		// Cache analysis and optimization
+		{"/api/v1/cache/analysis", s.handleCacheAnalysis, "Cache", []apiOp{opGet("Cache usage analysis", nil)}},
+		{"/api/v1/cache/opportunities", s.handleCacheOpportunities, "Cache", []apiOp{opGet("Caching opportunities", nil)}},
+		{"/api/v1/cache/optimize", s.handleCacheOptimize, "Cache", []apiOp{opPost("Apply cache optimizations", nil, nil)}},
+		{"/api/v1/cache/recommendations", s.handleCacheRecommendations, "Cache", []apiOp{opGet("Cache recommendations", nil)}},
+
+		// Pattern analysis routes
+		{"/api/v1/patterns/analysis", s.handlePatternAnalysis, "Patterns", []apiOp{opGet("Usage pattern analysis", nil)}},
+		{"/api/v1/patterns/expensive", s.handleExpensivePatterns, "Patterns", []apiOp{opGet("Most expensive patterns", nil)}},
+		{"/api/v1/patterns/anomalies", s.handleAnomalies, "Patterns", []apiOp{opGet("Usage anomalies", nil)}},
+		{"/api/v1/optimizations", s.handleOptimizations, "Patterns", []apiOp{opGet("Cost optimizations", nil)}},
+		{"/api/v1/prompts/analysis", s.handlePromptAnalysis, "Patterns", []apiOp{opGet("Prompt analysis", nil)}},
+		{"/api/v1/prompts/optimizations", s.handlePromptOptimizations, "Patterns", []apiOp{opGet("Prompt optimizations", nil)}},
+		{"/api/v1/optimizations/substitutions", s.handleSubstitutions, "Patterns", []apiOp{opGet("Model substitution suggestions", nil)}},
+		{"/api/v1/optimizations/", s.handleOptimizationActions, "Patterns", []apiOp{opPost("Apply an optimization", nil, nil).at("/api/v1/optimizations/{id}/apply")}},
+
+		// Health check endpoints
+		{"/health", s.handleHealthDetail, "Health", []apiOp{opGet("Detailed health", nil).unauthenticated()}},
+		{"/health/live", s.handleHealthLive, "Health", []apiOp{opGet("Liveness probe", nil).unauthenticated()}},
+		{"/health/ready", s.handleHealthReady, "Health", []apiOp{opGet("Readiness probe", nil).unauthenticated()}},
+
+		// Configuration
+		{"/api/v1/config", s.handleConfig, "Config", []apiOp{opGet("Get configuration", nil), opPut("Update configuration", nil, nil)}},
+		{"/api/v1/config/export.yaml", s.handleConfigExportYAML, "Config", []apiOp{opGet("Export configuration as YAML", nil)}},
+		{"/api/v1/config/import.yaml", s.handleConfigImportYAML, "Config", []apiOp{opPost("Import configuration from YAML", nil, nil)}},
+
+		// Events (real-time updates and event bus)
+		{"/api/v1/events/stream", s.handleEventStream, "Events", []apiOp{opGet("Stream events (SSE)", nil).unauthenticated()}},
+		{"/api/v1/events/stats", s.handleGetEventStats, "Events", []apiOp{opGet("Event bus statistics", nil)}},
+		{"/api/v1/events", s.handleGetEvents, "Events", []apiOp{opGet("Event history", nil)}},
+
+		// Activity feed
+		{"/api/v1/activity-feed", s.handleGetActivityFeed, "Activity", []apiOp{opGet("Activity feed", nil)}},
+		{"/api/v1/activity-feed/stream", s.handleActivityFeedStream, "Activity", []apiOp{opGet("Stream the activity feed (SSE)", nil)}},
+
+		// Notifications
+		{"/api/v1/notifications", s.handleGetNotifications, "Notifications", []apiOp{opGet("List notifications", nil)}},
+		{"/api/v1/notifications/stream", s.handleNotificationStream, "Notifications", []apiOp{opGet("Stream notifications (SSE)", nil)}},
+		{"/api/v1/notifications/", s.handleNotificationActions, "Notifications", []apiOp{opPost("Mark a notification read", nil, nil).at("/api/v1/notifications/{id}/read")}},
+		{"/api/v1/notifications/mark-all-read", s.handleMarkAllRead, "Notifications", []apiOp{opPost("Mark all notifications read", nil, nil)}},
+		{"/api/v1/notifications/preferences", s.handleNotificationPreferences, "Notifications", []apiOp{
+			opGet("Get notification preferences", nil),
+			opPatch("Update notification preferences", nil, nil),
+		}},
+
+		// Motivations
+		{"/api/v1/motivations", s.handleMotivations, "Motivations", []apiOp{
+			opGet("List motivations", nil),
+			opPost("Create a motivation", CreateMotivationRequest{}, MotivationResponse{}),
+		}},
+		{"/api/v1/motivations/", s.handleMotivation, "Motivations", []apiOp{
+			opGet("Get a motivation", MotivationResponse{}).at("/api/v1/motivations/{id}"),
+			opPut("Update a motivation", UpdateMotivationRequest{}, MotivationResponse{}).at("/api/v1/motivations/{id}"),
+			opDelete("Delete a motivation").at("/api/v1/motivations/{id}"),
+			opPost("Enable a motivation", nil, nil).at("/api/v1/motivations/{id}/enable"),
+			opPost("Disable a motivation", nil, nil).at("/api/v1/motivations/{id}/disable"),
+			opPost("Trigger a motivation", nil, nil).at("/api/v1/motivations/{id}/trigger"),
+		}},
+		{"/api/v1/motivations/history", s.handleMotivationHistory, "Motivations", []apiOp{opGet("Motivation trigger history", []TriggerHistoryResponse{})}},
+		{"/api/v1/motivations/idle", s.handleIdleState, "Motivations", []apiOp{opGet("System idle state", IdleStateResponse{})}},
+		{"/api/v1/motivations/roles", s.handleMotivationRoles, "Motivations", []apiOp{opGet("Motivations by role", nil)}},
+		{"/api/v1/motivations/defaults", s.handleMotivationDefaults, "Motivations", []apiOp{opPost("Register default motivations", nil, nil)}},
+
+		// Workflows (Phase 4 & 5)
+		{"/api/v1/workflows", s.handleWorkflows, "Workflows", []apiOp{
+			opGet("List workflows", []workflow.Workflow{}),
+			opPost("Create a workflow", workflow.Workflow{}, workflow.Workflow{}),
+		}},
+		{"/api/v1/workflows/start", s.handleWorkflowStart, "Workflows", []apiOp{opPost("Start a workflow for a bead", StartWorkflowRequest{}, nil)}},
+		{"/api/v1/workflows/", s.handleWorkflow, "Workflows", []apiOp{
+			opGet("Get a workflow", workflow.Workflow{}).at("/api/v1/workflows/{id}"),
+			opPut("Replace a workflow's definition", workflow.Workflow{}, workflow.Workflow{}).at("/api/v1/workflows/{id}"),
+			opDelete("Delete a workflow").at("/api/v1/workflows/{id}"),
+		}},
+		{"/api/v1/workflows/executions", s.handleWorkflowExecutions, "Workflows", []apiOp{opGet("List workflow executions", nil)}},
+		{"/api/v1/workflows/executions/", s.handleWorkflowExecution, "Workflows", []apiOp{opGet("Get a workflow execution with its graph and progress", workflow.ExecutionView{}).at("/api/v1/workflows/executions/{id}")}},
+		{"/api/v1/workflows/analytics", s.handleWorkflowAnalytics, "Workflows", []apiOp{opGet("Workflow analytics", nil)}},
+		{"/api/v1/beads/workflow", s.handleBeadWorkflow, "Workflows", []apiOp{opGet("Get a bead's workflow execution", nil)}},
+
+		// Webhooks (external event integration)
+		{"/api/v1/webhooks/github", s.handleGitHubWebhook, "Webhooks", []apiOp{opPost("Receive a GitHub webhook", nil, nil)}},
+		{"/api/v1/webhooks/gitlab", s.handleGitLabWebhook, "Webhooks", []apiOp{opPost("Receive a GitLab webhook", nil, nil)}},
+		{"/api/v1/webhooks/openclaw", s.handleOpenClawWebhook, "Webhooks", []apiOp{opPost("Receive an OpenClaw webhook", nil, nil).unauthenticated()}},
+		{"/api/v1/webhooks/status", s.handleWebhookStatus, "Webhooks", []apiOp{opGet("Webhook status", nil)}},
+
+		// OpenClaw messaging gateway
+		{"/api/v1/openclaw/status", s.handleOpenClawStatus, "Webhooks", []apiOp{opGet("OpenClaw gateway status", nil)}},
+
+		// Database export/import
+		{"/api/v1/export", s.handleExport, "Export", []apiOp{opGet("Export the database", nil)}},
+		{"/api/v1/import", s.handleImport, "Export", []apiOp{opPost("Import a database export", nil, nil)}},
+	}
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jordanhubbard/loom/internal/auth"
+	"github.com/jordanhubbard/loom/pkg/config"
+)
+
+func TestAPIOp_RequiredScope(t *testing.T) {
+	tests := []struct {
+		tag  string
+		op   apiOp
+		want string
+	}{
+		{"Beads", opGet("List beads", nil), "beads:read"},
+		{"Beads", opPost("Create a bead", nil, nil), "beads:write"},
+		{"Beads", opPatch("Update a bead", nil, nil), "beads:write"},
+		{"Beads", opDelete("Delete a bead"), "beads:delete"},
+		{"Analytics", opGet("Cost report", nil), "analytics:read"},
+		{"Agents", opPost("Send a CEO REPL message", nil, nil).requires("repl:use"), "repl:use"},
+		{"Cache", opGet("Cache statistics", nil), ""},
+		{"Health", opGet("Health check", nil).unauthenticated(), ""},
+	}
+	for _, tt := range tests {
+		if got := tt.op.requiredScope(tt.tag); got != tt.want {
+			t.Errorf("%s %s (%s): got %q, want %q", tt.op.method, tt.op.summary, tt.tag, got, tt.want)
+		}
+	}
+}
+
+func TestRequireScopes(t *testing.T) {
+	s := &Server{}
+	rt := apiRoute{"/api/v1/beads", nil, "Beads", []apiOp{
+		opGet("List beads", nil),
+		opPost("Create a bead", nil, nil),
+	}}
+	handler := s.requireScopes(rt, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	reader := &auth.Principal{Subject: "reader", Scopes: []string{"beads:read"}}
+	tests := []struct {
+		method    string
+		principal *auth.Principal
+		want      int
+	}{
+		{http.MethodGet, reader, http.StatusNoContent},
+		{http.MethodPost, reader, http.StatusForbidden},
+		{http.MethodPost, nil, http.StatusUnauthorized},
+		{http.MethodPut, nil, http.StatusNoContent}, // Undocumented method: handler decides
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, "/api/v1/beads", nil)
+		if tt.principal != nil {
+			req = req.WithContext(auth.WithPrincipal(req.Context(), tt.principal))
+		}
+		w := httptest.NewRecorder()
+		handler(w, req)
+		if w.Code != tt.want {
+			t.Errorf("%s as %v: expected %d, got %d", tt.method, tt.principal, tt.want, w.Code)
+		}
+	}
+}
+
+func TestSetupRoutes_EnforcesTokenScopes(t *testing.T) {
+	am := auth.NewManager("test-secret")
+	am.SetStaticTokens([]auth.StaticToken{{Subject: "reader", Token: "read-token", Scopes: []string{"beads:read"}}})
+	cfg := &config.Config{Security: config.SecurityConfig{EnableAuth: true}}
+	handler := NewServer(nil, nil, am, cfg).SetupRoutes()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/beads", strings.NewReader(`{"title":"x"}`))
+	req.Header.Set("Authorization", "Bearer read-token")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected 403 for missing beads:write, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/beads", strings.NewReader(`{"title":"x"}`))
+	req.Header.Set("Authorization", "Bearer wrong-token")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for unknown token, got %d", w.Code)
+	}
+}
+
+func TestOpenAPISpec_RequiredScopes(t *testing.T) {
+	paths := getOpenAPISpec(t)["paths"].(map[string]interface{})
+	post := paths["/api/v1/beads"].(map[string]interface{})["post"].(map[string]interface{})
+	if post["x-required-scope"] != "beads:write" {
+		t.Errorf("expected beads:write, got %v", post["x-required-scope"])
+	}
+	health := paths["/api/v1/health"].(map[string]interface{})["get"].(map[string]interface{})
+	if _, ok := health["x-required-scope"]; ok {
+		t.Error("public operations should not require a scope")
+	}
+}
@@ -1,6 +1,8 @@
 package api
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -16,24 +18,27 @@ import (
 	"github.com/jordanhubbard/loom/internal/logging"
 	"github.com/jordanhubbard/loom/internal/loom"
 	"github.com/jordanhubbard/loom/internal/metrics"
+	"github.com/jordanhubbard/loom/internal/observability"
 	"github.com/jordanhubbard/loom/pkg/config"
 	"github.com/jordanhubbard/loom/pkg/models"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 // Server represents the HTTP API server
 type Server struct {
-	app             *loom.Loom
-	keyManager      *keymanager.KeyManager
-	authManager     *auth.Manager
-	analyticsLogger *analytics.Logger
-	logManager      *logging.Manager
-	cache           *cache.Cache
-	config          *config.Config
-	fileManager     *files.Manager
-	metrics         *metrics.Metrics
-	apiFailureMu    sync.Mutex
-	apiFailureLast  map[string]time.Time
+	app                *loom.Loom
+	keyManager         *keymanager.KeyManager
+	authManager        *auth.Manager
+	analyticsLogger    *analytics.Logger
+	logManager         *logging.Manager
+	cache              *cache.Cache
+	config             *config.Config
+	fileManager        *files.Manager
+	metrics            *metrics.Metrics
+	rateLimiter        *clientRateLimiter // Replaced on config reload; guarded by rateLimitMu
+	authFailureLimiter *clientRateLimiter // Per-IP limit on failed auth; guarded by rateLimitMu
+	rateLimitMu        sync.RWMutex
+	apiFailureMu       sync.Mutex
+	apiFailureLast     map[string]time.Time
 
 	// Circuit breaker for auto-filing API failures as beads.
 	// Prevents cascading failures when the bead subsystem itself is broken.
@@ -52,6 +57,7 @@ func NewServer(arb *loom.Loom, km *keymanager.KeyManager, am *auth.Manager, cfg
 		storage, err := analytics.NewDatabaseStorage(arb.GetDatabase().DB())
 		if err == nil {
 			analyticsLogger = analytics.NewLogger(storage, analytics.DefaultPrivacyConfig())
+			analyticsLogger.SetMetrics(metrics.NewMetrics())
 		}
 	}
 
@@ -106,17 +112,25 @@ func NewServer(arb *loom.Loom, km *keymanager.KeyManager, am *auth.Manager, cfg
 	// Initialize Prometheus metrics
 	promMetrics := metrics.NewMetrics()
 
+	var rateLimiter *clientRateLimiter
+	if cfg != nil {
+		rateLimiter = newClientRateLimiter(cfg.Security.RateLimitRPS, cfg.Security.RateLimitBurst)
+	}
+	authFailureLimiter := newAuthFailureLimiter(cfg)
+
 	return &Server{
-		app:             arb,
-		keyManager:      km,
-		authManager:     am,
-		analyticsLogger: analyticsLogger,
-		logManager:      logMgr,
-		cache:           responseCache,
-		config:          cfg,
-		fileManager:     fileManager,
-		metrics:         promMetrics,
-		apiFailureLast:  make(map[string]time.Time),
+		app:                arb,
+		keyManager:         km,
+		authManager:        am,
+		analyticsLogger:    analyticsLogger,
+		logManager:         logMgr,
+		cache:              responseCache,
+		config:             cfg,
+		fileManager:        fileManager,
+		metrics:            promMetrics,
+		rateLimiter:        rateLimiter,
+		authFailureLimiter: authFailureLimiter,
+		apiFailureLast:     make(map[string]time.Time),
 	}
 }
 
@@ -139,221 +153,17 @@ func (s *Server) SetupRoutes() http.Handler {
 		})
 	}
 
-	// Serve OpenAPI spec
-	mux.HandleFunc("/api/openapi.yaml", func(w http.ResponseWriter, r *http.Request) {
-		http.ServeFile(w, r, "./api/openapi.yaml")
-	})
-
-	// Health check
-	mux.HandleFunc("/api/v1/health", s.handleHealth)
-
-	// Prometheus metrics endpoint
-	mux.Handle("/metrics", promhttp.Handler())
-
-	// Auth endpoints
-	authHandlers := auth.NewHandlers(s.authManager)
-	mux.HandleFunc("/api/v1/auth/login", authHandlers.HandleLogin)
-	mux.HandleFunc("/api/v1/auth/refresh", authHandlers.HandleRefreshToken)
-	mux.HandleFunc("/api/v1/auth/change-password", authHandlers.HandleChangePassword)
-	mux.HandleFunc("/api/v1/auth/api-keys", authHandlers.HandleCreateAPIKey)
-	mux.HandleFunc("/api/v1/auth/me", authHandlers.HandleGetCurrentUser)
-	mux.HandleFunc("/api/v1/auth/users", func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case http.MethodPost:
-			authHandlers.HandleCreateUser(w, r)
-		case http.MethodGet:
-			authHandlers.HandleListUsers(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	})
-
-	// Personas
-	mux.HandleFunc("/api/v1/personas", s.handlePersonas)
-	mux.HandleFunc("/api/v1/personas/", s.handlePersona)
-
-	// Agents
-	mux.HandleFunc("/api/v1/agents", s.handleAgents)
-	mux.HandleFunc("/api/v1/agents/", s.handleAgent)
-
-	// Projects (includes /projects/{id}/files/*)
-	mux.HandleFunc("/api/v1/projects/bootstrap", s.handleBootstrapProject)
-	mux.HandleFunc("/api/v1/projects", s.handleProjects)
-	mux.HandleFunc("/api/v1/projects/", s.handleProject)
-
-	// Org Charts
-	mux.HandleFunc("/api/v1/org-charts/", s.handleOrgChart)
-
-	// Beads
-	mux.HandleFunc("/api/v1/beads", s.handleBeads)
-	mux.HandleFunc("/api/v1/beads/", s.handleBead)
-
-	// Connectors
-	mux.HandleFunc("/api/v1/connectors", s.HandleConnectors)
-	mux.HandleFunc("/api/v1/connectors/", s.HandleConnectors)
-
-	// Federation
-	mux.HandleFunc("/api/v1/federation/status", s.handleFederationStatus)
-	mux.HandleFunc("/api/v1/federation/sync", s.handleFederationSync)
-
-	// Comments (must be registered before other /beads/ routes to avoid conflicts)
-	// Note: This is already handled by handleBead which routes to specific sub-paths
-	// but we also need a dedicated handler for comments
-	// Actually, we'll use a pattern that matches /beads/{id}/comments
-	mux.HandleFunc("/api/v1/comments/", s.handleComment)
-
-	// Conversations
-	mux.HandleFunc("/api/v1/conversations", s.handleConversationsList)
-	mux.HandleFunc("/api/v1/conversations/", s.handleConversation)
-
-	// Decisions
-	mux.HandleFunc("/api/v1/decisions", s.handleDecisions)
-	mux.HandleFunc("/api/v1/decisions/", s.handleDecision)
-
-	// File locks
-	mux.HandleFunc("/api/v1/file-locks", s.handleFileLocks)
-	mux.HandleFunc("/api/v1/file-locks/", s.handleFileLock)
-
-	// Work graph
-	mux.HandleFunc("/api/v1/work-graph", s.handleWorkGraph)
-
-	// Providers
-	mux.HandleFunc("/api/v1/providers", s.handleProviders)
-	mux.HandleFunc("/api/v1/providers/", s.handleProvider)
-	mux.HandleFunc("/api/v1/routing/select", s.handleSelectProvider)
-	mux.HandleFunc("/api/v1/routing/policies", s.handleGetRoutingPolicies)
-
-	// Models
-	mux.HandleFunc("/api/v1/models/recommended", s.handleRecommendedModels)
-
-	// System
-	mux.HandleFunc("/api/v1/system/status", s.handleSystemStatus)
-
-	// Work (non-bead prompts)
-	mux.HandleFunc("/api/v1/work", s.handleWork)
-
-	// CEO REPL
-	mux.HandleFunc("/api/v1/repl", s.handleRepl)
-
-	// Shell command execution
-	mux.HandleFunc("/api/v1/commands/execute", s.HandleExecuteCommand)
-	mux.HandleFunc("/api/v1/commands", s.HandleGetCommandLogs)
-	mux.HandleFunc("/api/v1/commands/", s.HandleGetCommandLogs)
-
-	// Auto-filed bug reports
-	mux.HandleFunc("/api/v1/beads/auto-file", s.HandleAutoFileBug)
-
-	// Logging endpoints
-	mux.HandleFunc("/api/v1/logs/recent", s.HandleLogsRecent)
-	mux.HandleFunc("/api/v1/logs/stream", s.HandleLogsStream)
-	mux.HandleFunc("/api/v1/logs/export", s.HandleLogsExport)
-
-	// Chat completions (with streaming support)
-	mux.HandleFunc("/api/v1/chat/completions/stream", s.handleStreamChatCompletion)
-	mux.HandleFunc("/api/v1/chat/completions", s.handleChatCompletion)
-
-	// Pair-programming chat (SSE streaming with conversation persistence)
-	mux.HandleFunc("/api/v1/pair", s.handlePairChat)
-
-	// Git operations
-	mux.HandleFunc("/api/v1/projects/git/sync", s.handleGitSync)
-	mux.HandleFunc("/api/v1/projects/git/commit", s.handleGitCommit)
-	mux.HandleFunc("/api/v1/projects/git/push", s.handleGitPush)
-	mux.HandleFunc("/api/v1/projects/git/status", s.handleGitStatus)
-
-	// Analytics and cost tracking
-	mux.HandleFunc("/api/v1/analytics/logs", s.handleGetLogs)
-	mux.HandleFunc("/api/v1/analytics/stats", s.handleGetLogStats)
-	mux.HandleFunc("/api/v1/analytics/export", s.handleExportLogs)
-	mux.HandleFunc("/api/v1/analytics/export-stats", s.handleExportStats)
-	mux.HandleFunc("/api/v1/analytics/costs", s.handleGetCostReport)
-	mux.HandleFunc("/api/v1/analytics/batching", s.handleGetBatchingRecommendations)
-	mux.HandleFunc("/api/v1/analytics/change-velocity", s.handleGetChangeVelocity)
-
-	// Debug endpoints
-	mux.HandleFunc("/api/v1/debug/capture-ui", s.handleCaptureUI)
-
-	// Cache management
-	mux.HandleFunc("/api/v1/cache/stats", s.handleGetCacheStats)
-	mux.HandleFunc("/api/v1/cache/config", s.handleGetCacheConfig)
-	mux.HandleFunc("/api/v1/cache/clear", s.handleClearCache)
-	mux.HandleFunc("/api/v1/cache/invalidate", s.handleInvalidateCache)
-
-	// Cache analysis and optimization
-	mux.HandleFunc("/api/v1/cache/analysis", s.handleCacheAnalysis)
-	mux.HandleFunc("/api/v1/cache/opportunities", s.handleCacheOpportunities)
-	mux.HandleFunc("/api/v1/cache/optimize", s.handleCacheOptimize)
-	mux.HandleFunc("/api/v1/cache/recommendations", s.handleCacheRecommendations)
-
-	// Pattern analysis routes
-	mux.HandleFunc("/api/v1/patterns/analysis", s.handlePatternAnalysis)
-	mux.HandleFunc("/api/v1/patterns/expensive", s.handleExpensivePatterns)
-	mux.HandleFunc("/api/v1/patterns/anomalies", s.handleAnomalies)
-	mux.HandleFunc("/api/v1/optimizations", s.handleOptimizations)
-	mux.HandleFunc("/api/v1/prompts/analysis", s.handlePromptAnalysis)
-	mux.HandleFunc("/api/v1/prompts/optimizations", s.handlePromptOptimizations)
-	mux.HandleFunc("/api/v1/optimizations/substitutions", s.handleSubstitutions)
-	mux.HandleFunc("/api/v1/optimizations/", s.handleOptimizationActions)
-
-	// Health check endpoints
-	mux.HandleFunc("/health", s.handleHealthDetail)      // Detailed health
-	mux.HandleFunc("/health/live", s.handleHealthLive)   // Liveness probe
-	mux.HandleFunc("/health/ready", s.handleHealthReady) // Readiness probe
-
-	// Configuration
-	mux.HandleFunc("/api/v1/config", s.handleConfig)
-	mux.HandleFunc("/api/v1/config/export.yaml", s.handleConfigExportYAML)
-	mux.HandleFunc("/api/v1/config/import.yaml", s.handleConfigImportYAML)
-
-	// Events (real-time updates and event bus)
-	mux.HandleFunc("/api/v1/events/stream", s.handleEventStream)
-	mux.HandleFunc("/api/v1/events/stats", s.handleGetEventStats)
-	mux.HandleFunc("/api/v1/events", s.handleGetEvents) // GET for history
-	// POST /api/v1/events for publishing is available but should be restricted
-
-	// Activity feed
-	mux.HandleFunc("/api/v1/activity-feed", s.handleGetActivityFeed)
-	mux.HandleFunc("/api/v1/activity-feed/stream", s.handleActivityFeedStream)
-
-	// Notifications
-	mux.HandleFunc("/api/v1/notifications", s.handleGetNotifications)
-	mux.HandleFunc("/api/v1/notifications/stream", s.handleNotificationStream)
-	mux.HandleFunc("/api/v1/notifications/", s.handleNotificationActions)
-	mux.HandleFunc("/api/v1/notifications/mark-all-read", s.handleMarkAllRead)
-	mux.HandleFunc("/api/v1/notifications/preferences", s.handleNotificationPreferences)
-
-	// Motivations
-	mux.HandleFunc("/api/v1/motivations", s.handleMotivations)
-	mux.HandleFunc("/api/v1/motivations/", s.handleMotivation)
-	mux.HandleFunc("/api/v1/motivations/history", s.handleMotivationHistory)
-	mux.HandleFunc("/api/v1/motivations/idle", s.handleIdleState)
-	mux.HandleFunc("/api/v1/motivations/roles", s.handleMotivationRoles)
-	mux.HandleFunc("/api/v1/motivations/defaults", s.handleMotivationDefaults)
-
-	// Workflows (Phase 4 & 5)
-	mux.HandleFunc("/api/v1/workflows", s.handleWorkflows)
-	mux.HandleFunc("/api/v1/workflows/start", s.handleWorkflowStart)
-	mux.HandleFunc("/api/v1/workflows/", s.handleWorkflow)
-	mux.HandleFunc("/api/v1/workflows/executions", s.handleWorkflowExecutions)
-	mux.HandleFunc("/api/v1/workflows/analytics", s.handleWorkflowAnalytics)
-	mux.HandleFunc("/api/v1/beads/workflow", s.handleBeadWorkflow)
-
-	// Webhooks (external event integration)
-	mux.HandleFunc("/api/v1/webhooks/github", s.handleGitHubWebhook)
-	mux.HandleFunc("/api/v1/webhooks/openclaw", s.handleOpenClawWebhook)
-	mux.HandleFunc("/api/v1/webhooks/status", s.handleWebhookStatus)
-
-	// OpenClaw messaging gateway
-	mux.HandleFunc("/api/v1/openclaw/status", s.handleOpenClawStatus)
-
-	// Database export/import
-	mux.HandleFunc("/api/v1/export", s.handleExport)
-	mux.HandleFunc("/api/v1/import", s.handleImport)
+	for _, rt := range s.routes() {
+		mux.HandleFunc(rt.pattern, s.requireScopes(rt, s.limitRequestBody(rt.pattern, rt.handler)))
+	}
 
 	// Apply middleware
 	handler := s.loggingMiddleware(mux)
-	handler = s.corsMiddleware(handler)
+	handler = s.rateLimitMiddleware(handler)
 	handler = s.authMiddleware(handler)
+	handler = s.authFailureLimitMiddleware(handler)
+	handler = s.corsMiddleware(handler)
+	handler = traceMiddleware(handler)
 
 	return handler
 }
@@ -370,6 +180,34 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 
 // Middleware
 
+// traceMiddleware gives each request a trace ID, taken from the X-Trace-ID
+// header when the caller sent a usable one, and echoes it in the response so
+// the request can be matched to its dispatch, provider calls and analytics
+// logs.
+func traceMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceID := r.Header.Get(observability.TraceHeader)
+		if !validTraceID(traceID) {
+			traceID = observability.NewTraceID()
+		}
+		w.Header().Set(observability.TraceHeader, traceID)
+		next.ServeHTTP(w, r.WithContext(observability.WithTraceID(r.Context(), traceID)))
+	})
+}
+
+// validTraceID accepts up to 128 letters, digits, '-', '_' and '.'.
+func validTraceID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for _, c := range id {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.') {
+			return false
+		}
+	}
+	return true
+}
+
 // loggingMiddleware logs HTTP requests
 func (s *Server) loggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -533,33 +371,6 @@ func (s *Server) defaultProjectID() string {
 	return ""
 }
 
-// corsMiddleware handles CORS headers
-func (s *Server) corsMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Set CORS headers
-		if len(s.config.Security.AllowedOrigins) > 0 {
-			origin := r.Header.Get("Origin")
-			for _, allowedOrigin := range s.config.Security.AllowedOrigins {
-				if allowedOrigin == "*" || allowedOrigin == origin {
-					w.Header().Set("Access-Control-Allow-Origin", allowedOrigin)
-					break
-				}
-			}
-		}
-
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-API-Key, Authorization")
-
-		// Handle preflight
-		if r.Method == http.MethodOptions {
-			w.WriteHeader(http.StatusOK)
-			return
-		}
-
-		next.ServeHTTP(w, r)
-	})
-}
-
 // authMiddleware handles authentication
 func (s *Server) authMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -572,6 +383,7 @@ func (s *Server) authMiddleware(next http.Handler) http.Handler {
 			r.URL.Path == "/api/v1/auth/refresh" ||
 			r.URL.Path == "/" ||
 			r.URL.Path == "/api/openapi.yaml" ||
+			r.URL.Path == "/api/v1/openapi.json" ||
 			r.URL.Path == "/api/v1/events/stream" ||
 			r.URL.Path == "/api/v1/chat/completions/stream" ||
 			r.URL.Path == "/api/v1/chat/completions" ||
@@ -587,6 +399,7 @@ func (s *Server) authMiddleware(next http.Handler) http.Handler {
 			r.Header.Set("X-User-ID", "admin")
 			r.Header.Set("X-Username", "admin")
 			r.Header.Set("X-Role", "admin")
+			r = r.WithContext(auth.WithPrincipal(r.Context(), &auth.Principal{Subject: "admin", Scopes: []string{"*:*"}}))
 			next.ServeHTTP(w, r)
 			return
 		}
@@ -629,6 +442,42 @@ func (s *Server) respondJSON(w http.ResponseWriter, status int, data interface{}
 	w.Write([]byte("\n"))
 }
 
+// respondJSONWithETag writes a 200 JSON response tagged with a hash of the
+// body. When If-None-Match already names that tag the body is skipped and
+// 304 Not Modified is returned, so polling clients only download changes.
+func (s *Server) respondJSONWithETag(w http.ResponseWriter, r *http.Request, data interface{}) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+	body = append(body, '\n')
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "no-cache")
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+// etagMatches reports whether an If-None-Match header lists etag, using the
+// weak comparison RFC 9110 prescribes for that header.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
 // respondError writes an error response
 func (s *Server) respondError(w http.ResponseWriter, status int, message string) {
 	s.respondJSON(w, status, map[string]string{"error": message})
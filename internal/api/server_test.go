@@ -7,6 +7,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/jordanhubbard/loom/internal/observability"
 	"github.com/jordanhubbard/loom/pkg/config"
 )
 
@@ -351,3 +352,70 @@ func TestServer_ConcurrentResponses(t *testing.T) {
 		<-done
 	}
 }
+
+func TestServer_respondJSONWithETag(t *testing.T) {
+	server := &Server{}
+	data := map[string]int{"beads": 3}
+
+	w := httptest.NewRecorder()
+	server.respondJSONWithETag(w, httptest.NewRequest(http.MethodGet, "/", nil), data)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	etag := w.Header().Get("ETag")
+	if etag == "" || w.Body.Len() == 0 {
+		t.Fatalf("expected ETag and body, got %q / %q", etag, w.Body.String())
+	}
+
+	for _, ifNoneMatch := range []string{etag, "W/" + etag, `"other", ` + etag, "*"} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("If-None-Match", ifNoneMatch)
+		w = httptest.NewRecorder()
+		server.respondJSONWithETag(w, req, data)
+		if w.Code != http.StatusNotModified {
+			t.Errorf("If-None-Match %s: expected 304, got %d", ifNoneMatch, w.Code)
+		}
+		if w.Body.Len() != 0 {
+			t.Errorf("If-None-Match %s: 304 should have no body", ifNoneMatch)
+		}
+		if w.Header().Get("ETag") != etag {
+			t.Errorf("If-None-Match %s: 304 should repeat the ETag", ifNoneMatch)
+		}
+	}
+
+	// A changed body gets a new tag.
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	server.respondJSONWithETag(w, req, map[string]int{"beads": 4})
+	if w.Code != http.StatusOK || w.Header().Get("ETag") == etag {
+		t.Errorf("expected 200 with a new ETag, got %d %s", w.Code, w.Header().Get("ETag"))
+	}
+}
+
+func TestTraceMiddleware(t *testing.T) {
+	var seen string
+	handler := traceMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = observability.TraceID(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/beads", nil)
+	req.Header.Set(observability.TraceHeader, "client-trace-1")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if seen != "client-trace-1" || w.Header().Get(observability.TraceHeader) != "client-trace-1" {
+		t.Errorf("trace = %q, header = %q, want the caller's trace ID", seen, w.Header().Get(observability.TraceHeader))
+	}
+
+	for _, sent := range []string{"", "bad trace\nid", strings.Repeat("x", 129)} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/beads", nil)
+		if sent != "" {
+			req.Header.Set(observability.TraceHeader, sent)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if seen == "" || seen == sent || w.Header().Get(observability.TraceHeader) != seen {
+			t.Errorf("sent %q: trace = %q, header = %q, want a new trace ID", sent, seen, w.Header().Get(observability.TraceHeader))
+		}
+	}
+}
@@ -2,20 +2,32 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/jordanhubbard/loom/internal/workflow"
 )
 
-// handleWorkflows handles GET /api/v1/workflows - list all workflows
+// handleWorkflows handles /api/v1/workflows
+// GET - list all workflows
+// POST - create a workflow
 func (s *Server) handleWorkflows(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
+	switch r.Method {
+	case http.MethodGet:
+		s.listWorkflows(w, r)
+	case http.MethodPost:
+		s.createWorkflow(w, r)
+	default:
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
 	}
+}
 
+func (s *Server) listWorkflows(w http.ResponseWriter, r *http.Request) {
 	// Get query parameters
 	workflowType := r.URL.Query().Get("type")
 	projectID := r.URL.Query().Get("project_id")
@@ -43,22 +55,62 @@ func (s *Server) handleWorkflows(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// handleWorkflow handles GET /api/v1/workflows/{id} - get workflow details
-func (s *Server) handleWorkflow(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+func (s *Server) createWorkflow(w http.ResponseWriter, r *http.Request) {
+	wf, err := decodeWorkflow(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	if wf.ID == "" {
+		wf.ID = fmt.Sprintf("wf-%s", uuid.New().String()[:8])
+	}
 
+	store, ok := s.workflowStore(w)
+	if !ok {
+		return
+	}
+	if existing, err := s.app.GetWorkflowEngine().GetDatabase().GetWorkflow(wf.ID); err == nil && existing != nil {
+		http.Error(w, "Workflow already exists: "+wf.ID, http.StatusConflict)
+		return
+	}
+
+	s.saveWorkflow(w, store, wf, http.StatusCreated)
+}
+
+// handleWorkflow handles /api/v1/workflows/{id}
+// GET - get workflow details
+// PUT - replace the workflow's definition
+// DELETE - delete the workflow
+// PUT and DELETE refuse a default workflow, and one with active executions
+// unless ?force=true.
+func (s *Server) handleWorkflow(w http.ResponseWriter, r *http.Request) {
 	// Extract workflow ID from path
 	path := strings.TrimPrefix(r.URL.Path, "/api/v1/workflows/")
 	workflowID := strings.Split(path, "/")[0]
 
+	switch r.Method {
+	case http.MethodGet, http.MethodPut, http.MethodDelete:
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
 	if workflowID == "" {
 		http.Error(w, "Workflow ID required", http.StatusBadRequest)
 		return
 	}
 
+	switch r.Method {
+	case http.MethodGet:
+		s.getWorkflow(w, workflowID)
+	case http.MethodPut:
+		s.updateWorkflow(w, r, workflowID)
+	case http.MethodDelete:
+		s.deleteWorkflow(w, r, workflowID)
+	}
+}
+
+func (s *Server) getWorkflow(w http.ResponseWriter, workflowID string) {
 	// Get workflow engine
 	engine := s.app.GetWorkflowEngine()
 	if engine == nil {
@@ -83,6 +135,138 @@ func (s *Server) handleWorkflow(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (s *Server) updateWorkflow(w http.ResponseWriter, r *http.Request, workflowID string) {
+	wf, err := decodeWorkflow(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if wf.ID != "" && wf.ID != workflowID {
+		http.Error(w, "Workflow ID in body does not match the path", http.StatusBadRequest)
+		return
+	}
+	wf.ID = workflowID
+
+	store, ok := s.workflowStore(w)
+	if !ok {
+		return
+	}
+	existing, ok := s.editableWorkflow(w, r, store, workflowID)
+	if !ok {
+		return
+	}
+	wf.CreatedAt = existing.CreatedAt
+
+	s.saveWorkflow(w, store, wf, http.StatusOK)
+}
+
+func (s *Server) deleteWorkflow(w http.ResponseWriter, r *http.Request, workflowID string) {
+	store, ok := s.workflowStore(w)
+	if !ok {
+		return
+	}
+	if _, ok := s.editableWorkflow(w, r, store, workflowID); !ok {
+		return
+	}
+
+	if err := store.DeleteWorkflow(workflowID); err != nil {
+		http.Error(w, "Failed to delete workflow: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decodeWorkflow reads a workflow definition from a request body and checks
+// its graph. Node and edge IDs are assigned by the server.
+func decodeWorkflow(r *http.Request) (*workflow.Workflow, error) {
+	var wf workflow.Workflow
+	if err := json.NewDecoder(r.Body).Decode(&wf); err != nil {
+		return nil, fmt.Errorf("invalid request body: %w", err)
+	}
+	if err := workflow.ValidateWorkflow(&wf); err != nil {
+		return nil, fmt.Errorf("invalid workflow: %w", err)
+	}
+	for i := range wf.Nodes {
+		wf.Nodes[i].ID = fmt.Sprintf("wfn-%s", uuid.New().String()[:8])
+		wf.Nodes[i].CreatedAt = time.Time{}
+		if wf.Nodes[i].Metadata == nil {
+			wf.Nodes[i].Metadata = map[string]string{}
+		}
+	}
+	for i := range wf.Edges {
+		wf.Edges[i].ID = fmt.Sprintf("wfe-%s", uuid.New().String()[:8])
+		wf.Edges[i].CreatedAt = time.Time{}
+	}
+	wf.CreatedAt = time.Time{}
+	return &wf, nil
+}
+
+// workflowStore returns the workflow database when it supports editing
+// definitions, writing an error response when it does not.
+func (s *Server) workflowStore(w http.ResponseWriter) (workflow.DefinitionStore, bool) {
+	engine := s.app.GetWorkflowEngine()
+	if engine == nil {
+		http.Error(w, "Workflow engine not available", http.StatusServiceUnavailable)
+		return nil, false
+	}
+	store, ok := engine.GetDatabase().(workflow.DefinitionStore)
+	if !ok {
+		http.Error(w, "Workflow database does not support editing workflows", http.StatusNotImplemented)
+		return nil, false
+	}
+	return store, true
+}
+
+// editableWorkflow returns the workflow to be changed, refusing a default
+// workflow, which is reinstalled from its definition file on every startup,
+// and one that still has active executions unless the request sets force.
+func (s *Server) editableWorkflow(w http.ResponseWriter, r *http.Request, store workflow.DefinitionStore, workflowID string) (*workflow.Workflow, bool) {
+	existing, err := s.app.GetWorkflowEngine().GetDatabase().GetWorkflow(workflowID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, "Workflow not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Failed to get workflow: "+err.Error(), http.StatusInternalServerError)
+		}
+		return nil, false
+	}
+	if existing.IsDefault {
+		http.Error(w, fmt.Sprintf("Workflow %s is a default workflow and is reinstalled on startup; create a copy under a new ID to change it", workflowID), http.StatusConflict)
+		return nil, false
+	}
+
+	force, _ := strconv.ParseBool(r.URL.Query().Get("force"))
+	if !force {
+		active, err := store.CountActiveWorkflowExecutions(workflowID)
+		if err != nil {
+			http.Error(w, "Failed to check workflow executions: "+err.Error(), http.StatusInternalServerError)
+			return nil, false
+		}
+		if active > 0 {
+			http.Error(w, fmt.Sprintf("Workflow %s has %d active executions; set force=true to change it anyway", workflowID, active), http.StatusConflict)
+			return nil, false
+		}
+	}
+	return existing, true
+}
+
+// saveWorkflow stores a workflow definition and responds with it as saved
+func (s *Server) saveWorkflow(w http.ResponseWriter, store workflow.DefinitionStore, wf *workflow.Workflow, status int) {
+	if err := store.SaveWorkflowDefinition(wf); err != nil {
+		http.Error(w, "Failed to save workflow: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if saved, err := s.app.GetWorkflowEngine().GetDatabase().GetWorkflow(wf.ID); err == nil {
+		wf = saved
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(wf); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
 // handleWorkflowExecutions handles GET /api/v1/workflows/executions - list workflow executions
 func (s *Server) handleWorkflowExecutions(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -144,6 +328,44 @@ func (s *Server) handleWorkflowExecutions(w http.ResponseWriter, r *http.Request
 	}
 }
 
+// handleWorkflowExecution handles GET /api/v1/workflows/executions/{id} - get
+// an execution merged with its workflow graph, for drawing its progress
+func (s *Server) handleWorkflowExecution(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1/workflows/executions/")
+	executionID := strings.Split(path, "/")[0]
+	if executionID == "" {
+		http.Error(w, "Execution ID required", http.StatusBadRequest)
+		return
+	}
+
+	// Get workflow engine
+	engine := s.app.GetWorkflowEngine()
+	if engine == nil {
+		http.Error(w, "Workflow engine not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	view, err := engine.GetExecutionView(executionID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, "Execution not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Failed to get execution: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(view); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
 // handleBeadWorkflow handles GET /api/v1/beads/workflow?bead_id={id} - get workflow for a bead
 func (s *Server) handleBeadWorkflow(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
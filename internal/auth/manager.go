@@ -4,7 +4,6 @@ import (
 	"crypto/rand"
 	"fmt"
 	"log"
-	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -20,6 +19,8 @@ type Manager struct {
 	passwords map[string]string  // userID -> password hash
 	roles     map[string]Role    // roleName -> Role
 	tokenTTL  time.Duration
+
+	staticTokens []staticToken // Pre-shared bearer tokens from config
 }
 
 // NewManager creates a new auth manager
@@ -328,26 +329,7 @@ func (m *Manager) ListUsers() []*User {
 
 // HasPermission checks if a user has a permission
 func (m *Manager) HasPermission(claims *Claims, permission string) bool {
-	for _, p := range claims.Permissions {
-		// Check for exact match
-		if p == permission {
-			return true
-		}
-		// Check for wildcard
-		if p == "*:*" {
-			return true
-		}
-		// Check for resource wildcard (e.g., "agents:*")
-		// Extract resource part (before :) and check if permission matches with wildcard
-		parts := strings.Split(permission, ":")
-		if len(parts) == 2 {
-			resourceWildcard := parts[0] + ":*"
-			if p == resourceWildcard {
-				return true
-			}
-		}
-	}
-	return false
+	return scopeGranted(claims.Permissions, permission)
 }
 
 // generateRandomID generates a random ID
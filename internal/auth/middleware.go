@@ -28,22 +28,14 @@ func (m *Manager) Middleware(requiredPermission string) func(http.Handler) http.
 				}
 
 				// Check permission
-				if requiredPermission != "" {
-					hasPermission := false
-					for _, p := range permissions {
-						if p == requiredPermission || p == "*:*" {
-							hasPermission = true
-							break
-						}
-					}
-					if !hasPermission {
-						http.Error(w, "Insufficient permissions", http.StatusForbidden)
-						return
-					}
+				if requiredPermission != "" && !scopeGranted(permissions, requiredPermission) {
+					http.Error(w, "Insufficient permissions", http.StatusForbidden)
+					return
 				}
 
 				// Store userID in context
 				r.Header.Set("X-User-ID", userID)
+				r = r.WithContext(WithPrincipal(r.Context(), &Principal{Subject: userID, Scopes: permissions}))
 				next.ServeHTTP(w, r)
 				return
 			}
@@ -57,6 +49,19 @@ func (m *Manager) Middleware(requiredPermission string) func(http.Handler) http.
 
 			tokenString := parts[1]
 
+			// Configured static tokens carry their own scopes
+			if principal, ok := m.validateStaticToken(tokenString); ok {
+				if requiredPermission != "" && !principal.HasScope(requiredPermission) {
+					http.Error(w, "Insufficient permissions", http.StatusForbidden)
+					return
+				}
+				r.Header.Set("X-User-ID", principal.Subject)
+				r.Header.Set("X-Username", principal.Subject)
+				r.Header.Del("X-Role")
+				next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), principal)))
+				return
+			}
+
 			// Validate token
 			claims, err := m.ValidateToken(tokenString)
 			if err != nil {
@@ -74,6 +79,7 @@ func (m *Manager) Middleware(requiredPermission string) func(http.Handler) http.
 			r.Header.Set("X-User-ID", claims.UserID)
 			r.Header.Set("X-Username", claims.Username)
 			r.Header.Set("X-Role", claims.Role)
+			r = r.WithContext(WithPrincipal(r.Context(), &Principal{Subject: claims.UserID, Scopes: claims.Permissions}))
 
 			next.ServeHTTP(w, r)
 		})
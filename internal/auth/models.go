@@ -152,6 +152,7 @@ var PreDefinedRoles = map[string]Role{
 			"projects:write",
 			"decisions:read",
 			"decisions:write",
+			"analytics:read",
 			"repl:use",
 		},
 	},
@@ -164,6 +165,7 @@ var PreDefinedRoles = map[string]Role{
 			"providers:read",
 			"projects:read",
 			"decisions:read",
+			"analytics:read",
 		},
 	},
 	"service": {
@@ -208,6 +210,9 @@ var PreDefinedPermissions = []Permission{
 	{Name: "decisions:delete", Resource: "decisions", Action: "delete", Description: "Delete decisions"},
 	{Name: "decisions:admin", Resource: "decisions", Action: "admin", Description: "Admin access to decisions"},
 
+	// Analytics
+	{Name: "analytics:read", Resource: "analytics", Action: "read", Description: "Read usage and cost analytics"},
+
 	// System
 	{Name: "repl:use", Resource: "repl", Action: "write", Description: "Use CEO REPL"},
 	{Name: "system:admin", Resource: "system", Action: "admin", Description: "Full system administration"},
@@ -0,0 +1,119 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// StaticToken is a pre-shared bearer token configured by the operator
+type StaticToken struct {
+	Subject string
+	Token   string
+	Scopes  []string
+}
+
+// staticToken stores a configured token by its digest so every comparison
+// works on fixed-length values.
+type staticToken struct {
+	subject string
+	digest  [sha256.Size]byte
+	scopes  []string
+}
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	Subject string
+	Scopes  []string
+}
+
+// HasScope reports whether the principal was granted scope, honoring the
+// "*:*" and "resource:*" wildcards.
+func (p *Principal) HasScope(scope string) bool {
+	if p == nil {
+		return false
+	}
+	return scopeGranted(p.Scopes, scope)
+}
+
+func scopeGranted(granted []string, scope string) bool {
+	resource, _, _ := strings.Cut(scope, ":")
+	for _, g := range granted {
+		if g == scope || g == "*:*" || g == resource+":*" {
+			return true
+		}
+	}
+	return false
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying p
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the principal attached by the middleware, if any
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
+
+// PrincipalFromRequest returns the principal of an authenticated request
+func PrincipalFromRequest(r *http.Request) (*Principal, bool) {
+	return PrincipalFromContext(r.Context())
+}
+
+// SetStaticTokens replaces the configured bearer tokens. Entries without a
+// token are ignored.
+func (m *Manager) SetStaticTokens(tokens []StaticToken) {
+	m.staticTokens = m.staticTokens[:0]
+	for _, t := range tokens {
+		if t.Token == "" {
+			continue
+		}
+		m.staticTokens = append(m.staticTokens, staticToken{
+			subject: t.Subject,
+			digest:  sha256.Sum256([]byte(t.Token)),
+			scopes:  append([]string(nil), t.Scopes...),
+		})
+	}
+}
+
+// validateStaticToken resolves a bearer token against the configured set.
+// Every entry is compared in constant time so the response time does not
+// reveal which token, or how much of one, matched.
+func (m *Manager) validateStaticToken(token string) (*Principal, bool) {
+	digest := sha256.Sum256([]byte(token))
+	var match *staticToken
+	for i := range m.staticTokens {
+		if subtle.ConstantTimeCompare(digest[:], m.staticTokens[i].digest[:]) == 1 && match == nil {
+			match = &m.staticTokens[i]
+		}
+	}
+	if match == nil {
+		return nil, false
+	}
+	return &Principal{Subject: match.subject, Scopes: match.scopes}, true
+}
+
+// RequireScope wraps a handler so it only runs for principals holding scope.
+// Requests without a principal get 401; principals lacking the scope get 403.
+func RequireScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			p, ok := PrincipalFromRequest(r)
+			if !ok {
+				http.Error(w, "Authentication required", http.StatusUnauthorized)
+				return
+			}
+			if !p.HasScope(scope) {
+				http.Error(w, "Insufficient scope: requires "+scope, http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
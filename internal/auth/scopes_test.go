@@ -0,0 +1,137 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newStaticTokenManager() *Manager {
+	m := NewManager("test-secret")
+	m.SetStaticTokens([]StaticToken{
+		{Subject: "ci-bot", Token: "ci-token", Scopes: []string{"beads:write", "analytics:read"}},
+		{Subject: "reader", Token: "read-token", Scopes: []string{"beads:read"}},
+		{Subject: "ignored", Token: ""},
+	})
+	return m
+}
+
+func TestPrincipal_HasScope(t *testing.T) {
+	p := &Principal{Subject: "svc", Scopes: []string{"beads:write", "analytics:*"}}
+	tests := map[string]bool{
+		"beads:write":     true,
+		"beads:read":      false,
+		"analytics:read":  true,
+		"analytics:write": true,
+		"agents:read":     false,
+	}
+	for scope, want := range tests {
+		if got := p.HasScope(scope); got != want {
+			t.Errorf("HasScope(%q) = %v, want %v", scope, got, want)
+		}
+	}
+
+	admin := &Principal{Scopes: []string{"*:*"}}
+	if !admin.HasScope("anything:at-all") {
+		t.Error("*:* should grant every scope")
+	}
+	var nilPrincipal *Principal
+	if nilPrincipal.HasScope("beads:read") {
+		t.Error("nil principal should hold no scopes")
+	}
+}
+
+func TestManager_ValidateStaticToken(t *testing.T) {
+	m := newStaticTokenManager()
+	if len(m.staticTokens) != 2 {
+		t.Fatalf("expected empty tokens to be dropped, got %d entries", len(m.staticTokens))
+	}
+
+	p, ok := m.validateStaticToken("ci-token")
+	if !ok || p.Subject != "ci-bot" {
+		t.Fatalf("expected ci-bot principal, got %+v ok=%v", p, ok)
+	}
+	if !p.HasScope("analytics:read") {
+		t.Error("expected analytics:read scope")
+	}
+
+	for _, bad := range []string{"", "ci-toke", "ci-token ", "unknown"} {
+		if _, ok := m.validateStaticToken(bad); ok {
+			t.Errorf("token %q should not validate", bad)
+		}
+	}
+}
+
+func scopedHandler(m *Manager, scope string) http.Handler {
+	return m.Middleware("")(RequireScope(scope)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p, _ := PrincipalFromRequest(r)
+		w.Header().Set("X-Subject", p.Subject)
+		w.WriteHeader(http.StatusNoContent)
+	})))
+}
+
+func TestMiddleware_StaticTokenScopes(t *testing.T) {
+	m := newStaticTokenManager()
+
+	tests := []struct {
+		name   string
+		header string
+		scope  string
+		want   int
+	}{
+		{"missing credentials", "", "beads:write", http.StatusUnauthorized},
+		{"unknown token", "Bearer nope", "beads:write", http.StatusUnauthorized},
+		{"granted scope", "Bearer ci-token", "beads:write", http.StatusNoContent},
+		{"insufficient scope", "Bearer read-token", "beads:write", http.StatusForbidden},
+		{"read scope", "Bearer read-token", "beads:read", http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/beads", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+			scopedHandler(m, tt.scope).ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, w.Code)
+			}
+		})
+	}
+}
+
+func TestMiddleware_JWTAndAPIKeyAttachPrincipal(t *testing.T) {
+	m := NewManager("test-secret")
+
+	login, err := m.Login("admin", "admin")
+	if err != nil {
+		t.Fatalf("login failed: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+login.Token)
+	w := httptest.NewRecorder()
+	scopedHandler(m, "analytics:read").ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent || w.Header().Get("X-Subject") != "user-admin" {
+		t.Errorf("expected admin JWT to pass, got %d subject=%q", w.Code, w.Header().Get("X-Subject"))
+	}
+
+	key, err := m.CreateAPIKey("user-admin", CreateAPIKeyRequest{Name: "reader", Permissions: []string{"beads:read"}})
+	if err != nil {
+		t.Fatalf("CreateAPIKey failed: %v", err)
+	}
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-API-Key", key.Key)
+	w = httptest.NewRecorder()
+	scopedHandler(m, "beads:write").ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected 403 for API key without beads:write, got %d", w.Code)
+	}
+}
+
+func TestRequireScope_NoPrincipal(t *testing.T) {
+	w := httptest.NewRecorder()
+	RequireScope("beads:read")(http.NotFoundHandler()).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", w.Code)
+	}
+}
@@ -0,0 +1,222 @@
+package beads
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/jordanhubbard/loom/pkg/models"
+)
+
+// archiveDir is the directory under the beads path holding archived beads,
+// one gzip-compressed JSON-lines file per project.
+const archiveDir = "archive"
+
+// ArchiveClosedBeads moves closed beads of a project (all projects if
+// projectID is empty) that were closed before olderThan out of memory and
+// into the project's compressed archive file, deleting their YAML files.
+// Archived beads can be read back with LoadArchivedBead. It returns the
+// number of beads archived.
+func (m *Manager) ArchiveClosedBeads(projectID string, olderThan time.Time) (int, error) {
+	m.archiveMu.Lock()
+	defer m.archiveMu.Unlock()
+
+	// Encode under the read lock so concurrent updates can't race the copy.
+	m.mu.RLock()
+	lines := make(map[string][]byte)
+	ids := make(map[string][]string)
+	for id, bead := range m.beads {
+		if !archivable(bead, projectID, olderThan) {
+			continue
+		}
+		data, err := json.Marshal(bead)
+		if err != nil {
+			m.mu.RUnlock()
+			return 0, fmt.Errorf("failed to marshal bead %s: %w", id, err)
+		}
+		path := m.archivePath(bead.ProjectID)
+		lines[path] = append(append(lines[path], data...), '\n')
+		ids[path] = append(ids[path], id)
+	}
+	m.mu.RUnlock()
+
+	paths := make([]string, 0, len(lines))
+	for path := range lines {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	archived := 0
+	for _, path := range paths {
+		if err := appendArchive(path, lines[path]); err != nil {
+			return archived, err
+		}
+		archived += m.dropArchived(ids[path], path)
+	}
+	return archived, nil
+}
+
+// LoadArchivedBead reads an archived bead back from disk. The bead is not
+// returned to the active set, so listing and dispatch stay unaffected.
+func (m *Manager) LoadArchivedBead(id string) (*models.Bead, error) {
+	m.mu.RLock()
+	path, ok := m.archivedBeads[id]
+	beadsPath := m.beadsPath
+	m.mu.RUnlock()
+
+	paths := []string{path}
+	if !ok {
+		// Not archived by this process; search every archive file.
+		paths, _ = filepath.Glob(filepath.Join(beadsPath, archiveDir, "*.jsonl.gz"))
+	}
+	for _, p := range paths {
+		bead, err := findInArchive(p, id)
+		if err != nil {
+			return nil, err
+		}
+		if bead != nil {
+			return bead, nil
+		}
+	}
+	return nil, fmt.Errorf("archived bead not found: %s", id)
+}
+
+// archivable reports whether a bead is closed, belongs to projectID (any
+// project if empty) and was closed before cutoff.
+func archivable(bead *models.Bead, projectID string, cutoff time.Time) bool {
+	if bead.Status != models.BeadStatusClosed {
+		return false
+	}
+	if projectID != "" && bead.ProjectID != projectID {
+		return false
+	}
+	closedAt := bead.UpdatedAt
+	if bead.ClosedAt != nil {
+		closedAt = *bead.ClosedAt
+	}
+	return closedAt.Before(cutoff)
+}
+
+// archivePath returns the archive file for a project. Callers must hold m.mu.
+func (m *Manager) archivePath(projectID string) string {
+	name := sanitizeFilename(projectID)
+	if name == "" {
+		name = "unassigned"
+	}
+	return filepath.Join(m.beadsPath, archiveDir, name+".jsonl.gz")
+}
+
+// dropArchived removes archived beads from memory and deletes their YAML
+// files. Beads reopened since they were written to the archive are kept.
+func (m *Manager) dropArchived(ids []string, path string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	removed := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		bead, ok := m.beads[id]
+		if !ok || bead.Status != models.BeadStatusClosed {
+			continue
+		}
+		if file, ok := m.beadFiles[id]; ok {
+			if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+				fmt.Fprintf(os.Stderr, "Warning: failed to remove archived bead file %s: %v\n", file, err)
+			}
+			delete(m.beadFiles, id)
+		}
+		delete(m.beads, id)
+		delete(m.workGraph.Beads, id)
+		m.recordDeleted(bead, now)
+		if m.archivedBeads == nil {
+			m.archivedBeads = make(map[string]string)
+		}
+		m.archivedBeads[id] = path
+		removed[id] = true
+	}
+	if len(removed) == 0 {
+		return 0
+	}
+
+	edges := m.workGraph.Edges[:0]
+	for _, edge := range m.workGraph.Edges {
+		if !removed[edge.From] && !removed[edge.To] {
+			edges = append(edges, edge)
+		}
+	}
+	m.workGraph.Edges = edges
+	m.workGraph.UpdatedAt = time.Now()
+	return len(removed)
+}
+
+// appendArchive appends lines to the archive as a new gzip member; readers
+// see the concatenated members as one stream.
+func appendArchive(path string, lines []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create archive directory: %w", err)
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(lines); err != nil {
+		return fmt.Errorf("failed to compress archive: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to compress archive: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	return nil
+}
+
+// findInArchive scans an archive file for a bead. The last copy wins, since
+// a bead archived again after being restored is appended later.
+func findInArchive(path, id string) (*models.Bead, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer f.Close()
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive %s: %w", path, err)
+	}
+	defer zr.Close()
+
+	var found *models.Bead
+	scanner := bufio.NewScanner(zr)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var bead models.Bead
+		if err := json.Unmarshal(scanner.Bytes(), &bead); err != nil {
+			continue
+		}
+		if bead.ID == id {
+			found = &bead
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read archive %s: %w", path, err)
+	}
+	return found, nil
+}
@@ -0,0 +1,570 @@
+package beads
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jordanhubbard/loom/pkg/config"
+	"github.com/jordanhubbard/loom/pkg/models"
+)
+
+// FederationSyncModeHTTP selects HTTP federation, where each peer's
+// remote_url is the base URL of another Loom instance.
+const FederationSyncModeHTTP = "http"
+
+// FederationBeadsPath is the peer endpoint that serves the bead index on GET
+// and merges pushed beads on POST.
+const FederationBeadsPath = "/api/v1/federation/beads"
+
+// federationBaseFile holds, inside a beads directory, the UpdatedAt each
+// bead had when this instance and each peer last agreed on it, so conflicts
+// are still detected after a restart.
+const federationBaseFile = "federation_base.json"
+
+// Context keys of a bead imported under a namespaced ID because its ID was
+// already taken by a different local bead: the peer it came from and its ID
+// there.
+const (
+	federationPeerKey = "federation_peer"
+	federationIDKey   = "federation_id"
+)
+
+// federatedIDPattern matches bead IDs accepted from peers: a single path
+// component, since bead files are named after their ID.
+var federatedIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// FederationConflict is a bead whose content differs between this instance
+// and a peer when neither version can be picked safely: both sides changed
+// it since they last agreed, they never agreed on it, or both changed it at
+// the same instant.
+// Conflicting beads are left untouched until their content matches again.
+type FederationConflict struct {
+	BeadID          string    `json:"bead_id"`
+	RemoteBeadID    string    `json:"remote_bead_id,omitempty"` // The peer's ID for the bead, when it differs
+	Peer            string    `json:"peer"`
+	LocalUpdatedAt  time.Time `json:"local_updated_at"`
+	RemoteUpdatedAt time.Time `json:"remote_updated_at"`
+	DetectedAt      time.Time `json:"detected_at"`
+}
+
+// FederationMergeResult lists what merging a peer's beads changed locally.
+type FederationMergeResult struct {
+	Imported  []string             `json:"imported"` // Beads new to this instance
+	Updated   []string             `json:"updated"`  // Local beads replaced by newer peer versions
+	Conflicts []FederationConflict `json:"conflicts,omitempty"`
+	Rejected  []string             `json:"rejected,omitempty"` // Peer bead IDs that are not safe to store
+}
+
+// FederationSyncResult is the outcome of an HTTP sync with one peer.
+type FederationSyncResult struct {
+	Peer string `json:"peer"`
+	FederationMergeResult
+	Pushed []string `json:"pushed"` // Beads sent to the peer
+	Error  string   `json:"error,omitempty"`
+}
+
+// federationState tracks HTTP federation per peer.
+type federationState struct {
+	mu        sync.Mutex
+	base      map[string]map[string]time.Time // Peer -> bead ID -> UpdatedAt both sides last agreed on
+	baseDir   string                          // Beads directory base was loaded from; see loadBaseLocked
+	conflicts map[string]FederationConflict   // Keyed by peer + "/" + bead ID
+	lastSync  map[string]*FederationSyncResult
+	client    *http.Client
+}
+
+// FederationIndex returns a snapshot of every local bead, as served to peers.
+func (m *Manager) FederationIndex() []*models.Bead {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	out := make([]*models.Bead, 0, len(m.beads))
+	for _, b := range m.beads {
+		copied := *b
+		out = append(out, &copied)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// MergeFederatedBeads merges beads received from a peer into the local
+// graph. Unknown beads are imported and, when both sides changed a bead, the
+// most recent UpdatedAt wins unless the change is a conflict. peer must be
+// the name this instance syncs with the peer under, so pulls and pushes
+// share the agreed versions.
+//
+// Bead IDs are allocated per instance, so a peer bead whose ID is taken by a
+// different local bead (one created at another time) is imported under an
+// ID namespaced by the peer rather than reported as a conflict. Beads whose
+// IDs are not a safe file name are rejected.
+func (m *Manager) MergeFederatedBeads(peer string, remote []*models.Bead) *FederationMergeResult {
+	result := &FederationMergeResult{Imported: []string{}, Updated: []string{}}
+	var changed []*models.Bead
+	baseChanged := false
+
+	m.mu.Lock()
+	beadsPath := m.beadsPath
+	m.federation.mu.Lock()
+	m.federation.loadBaseLocked(beadsPath)
+	base := m.federation.peerBase(peer)
+	aliases := m.federationAliasesLocked(peer)
+	now := time.Now()
+	for _, rb := range remote {
+		if rb == nil || rb.ID == "" {
+			continue
+		}
+		if !federatedIDPattern.MatchString(rb.ID) {
+			result.Rejected = append(result.Rejected, rb.ID)
+			continue
+		}
+		remoteID := rb.ID
+		if ownFederatedID(rb, m.beads) != "" {
+			rb = withoutFederationID(rb)
+		} else if id, ok := aliases[rb.ID]; ok {
+			rb = namespacedBead(rb, id, peer)
+		} else if lb, ok := m.beads[rb.ID]; ok && !lb.CreatedAt.Equal(rb.CreatedAt) {
+			rb = namespacedBead(rb, federatedBeadID(peer, rb.ID), peer)
+		}
+		key := peer + "/" + rb.ID
+		lb, exists := m.beads[rb.ID]
+		switch {
+		case !exists:
+			result.Imported = append(result.Imported, rb.ID)
+		case sameBeadContent(lb, rb):
+			if agreed := latest(lb.UpdatedAt, rb.UpdatedAt); !base[rb.ID].Equal(agreed) {
+				base[rb.ID] = agreed
+				baseChanged = true
+			}
+			delete(m.federation.conflicts, key)
+			continue
+		case isFederationConflict(lb, rb, base):
+			conflict := FederationConflict{
+				BeadID:          rb.ID,
+				Peer:            peer,
+				LocalUpdatedAt:  lb.UpdatedAt,
+				RemoteUpdatedAt: rb.UpdatedAt,
+				DetectedAt:      now,
+			}
+			if remoteID != rb.ID {
+				conflict.RemoteBeadID = remoteID
+			}
+			m.federation.conflicts[key] = conflict
+			result.Conflicts = append(result.Conflicts, conflict)
+			continue
+		case rb.UpdatedAt.After(lb.UpdatedAt):
+			result.Updated = append(result.Updated, rb.ID)
+		default:
+			continue // Local version is newer; the peer gets it on push
+		}
+
+		m.beads[rb.ID] = rb
+		m.workGraph.Beads[rb.ID] = rb
+		base[rb.ID] = rb.UpdatedAt
+		baseChanged = true
+		delete(m.federation.conflicts, key)
+		changed = append(changed, rb)
+	}
+	if len(changed) > 0 {
+		m.workGraph.UpdatedAt = now
+	}
+	var snapshot map[string]map[string]time.Time
+	if baseChanged {
+		snapshot = m.federation.snapshotBaseLocked()
+	}
+	m.federation.mu.Unlock()
+	m.mu.Unlock()
+
+	if snapshot != nil {
+		if err := saveFederationBase(beadsPath, snapshot); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to save federation base: %v\n", err)
+		}
+	}
+
+	for _, bead := range changed {
+		if err := m.SaveBeadToFilesystem(bead, m.beadsPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to save bead to filesystem: %v\n", err)
+		}
+	}
+	return result
+}
+
+// federationAliasesLocked maps the IDs a peer knows beads by to the local
+// IDs they were imported under. The caller must hold m.mu.
+func (m *Manager) federationAliasesLocked(peer string) map[string]string {
+	aliases := make(map[string]string)
+	for id, b := range m.beads {
+		if b.Context[federationPeerKey] == peer && b.Context[federationIDKey] != "" {
+			aliases[b.Context[federationIDKey]] = id
+		}
+	}
+	return aliases
+}
+
+// federatedBeadID namespaces a peer's bead ID by the peer's name.
+func federatedBeadID(peer, id string) string {
+	slug := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' {
+			return r
+		}
+		return '-'
+	}, strings.ToLower(peer))
+	return slug + "-" + id
+}
+
+// namespacedBead returns a copy of a peer's bead stored under the local ID
+// id, recording the peer and its ID there.
+func namespacedBead(rb *models.Bead, id, peer string) *models.Bead {
+	copied := *rb
+	copied.ID = id
+	copied.Context = make(map[string]string, len(rb.Context)+2)
+	for k, v := range rb.Context {
+		copied.Context[k] = v
+	}
+	copied.Context[federationPeerKey] = peer
+	copied.Context[federationIDKey] = rb.ID
+	return &copied
+}
+
+// peerView returns a local bead as the peer knows it: a bead imported from
+// that peer under a namespaced ID goes back under its original ID.
+func peerView(lb *models.Bead, peer string) *models.Bead {
+	if lb.Context[federationPeerKey] != peer || lb.Context[federationIDKey] == "" {
+		return lb
+	}
+	return withoutFederationID(lb)
+}
+
+// withoutFederationID returns a copy of a namespaced bead under its original
+// ID, without the keys recording where it came from.
+func withoutFederationID(b *models.Bead) *models.Bead {
+	copied := *b
+	copied.ID = b.Context[federationIDKey]
+	copied.Context = make(map[string]string, len(b.Context))
+	for k, v := range b.Context {
+		if k != federationPeerKey && k != federationIDKey {
+			copied.Context[k] = v
+		}
+	}
+	if len(copied.Context) == 0 {
+		copied.Context = nil
+	}
+	return &copied
+}
+
+// ownFederatedID returns the local ID of a bead a peer imported from this
+// instance under a namespaced ID, or "" when rb is not one: its recorded
+// original ID must name a local bead created at the same time.
+func ownFederatedID(rb *models.Bead, local map[string]*models.Bead) string {
+	id := rb.Context[federationIDKey]
+	if id == "" {
+		return ""
+	}
+	if lb, ok := local[id]; ok && lb.CreatedAt.Equal(rb.CreatedAt) {
+		return id
+	}
+	return ""
+}
+
+// isFederationConflict reports whether differing local and remote versions
+// of a bead cannot be ordered. Without a version both sides agreed on there
+// is no telling which copy is a later edit of the other, so that is a
+// conflict too. The caller must hold m.federation.mu.
+func isFederationConflict(local, remote *models.Bead, base map[string]time.Time) bool {
+	if local.UpdatedAt.Equal(remote.UpdatedAt) {
+		return true
+	}
+	agreed, known := base[local.ID]
+	if !known {
+		return true
+	}
+	return local.UpdatedAt.After(agreed) && remote.UpdatedAt.After(agreed)
+}
+
+// sameBeadContent compares two beads ignoring UpdatedAt.
+func sameBeadContent(a, b *models.Bead) bool {
+	ac, bc := *a, *b
+	ac.UpdatedAt, bc.UpdatedAt = time.Time{}, time.Time{}
+	aj, errA := json.Marshal(ac)
+	bj, errB := json.Marshal(bc)
+	return errA == nil && errB == nil && bytes.Equal(aj, bj)
+}
+
+func latest(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// initLocked allocates the state's maps. The caller must hold f.mu.
+func (f *federationState) initLocked() {
+	if f.base == nil {
+		f.base = make(map[string]map[string]time.Time)
+		f.conflicts = make(map[string]FederationConflict)
+		f.lastSync = make(map[string]*FederationSyncResult)
+	}
+}
+
+// peerBase returns the agreed UpdatedAt values for a peer. The caller must
+// hold f.mu.
+func (f *federationState) peerBase(peer string) map[string]time.Time {
+	f.initLocked()
+	if f.base[peer] == nil {
+		f.base[peer] = make(map[string]time.Time)
+	}
+	return f.base[peer]
+}
+
+// loadBaseLocked merges the agreed versions stored in beadsPath the first
+// time base is used with that directory. The caller must hold f.mu.
+func (f *federationState) loadBaseLocked(beadsPath string) {
+	f.initLocked()
+	if beadsPath == "" || f.baseDir == beadsPath {
+		return
+	}
+	f.baseDir = beadsPath
+	data, err := os.ReadFile(filepath.Join(beadsPath, federationBaseFile))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "Warning: failed to read federation base: %v\n", err)
+		}
+		return
+	}
+	var stored map[string]map[string]time.Time
+	if err := json.Unmarshal(data, &stored); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to parse federation base: %v\n", err)
+		return
+	}
+	for peer, beads := range stored {
+		base := f.peerBase(peer)
+		for id, agreed := range beads {
+			if _, ok := base[id]; !ok {
+				base[id] = agreed
+			}
+		}
+	}
+}
+
+// snapshotBaseLocked copies base for saving. The caller must hold f.mu.
+func (f *federationState) snapshotBaseLocked() map[string]map[string]time.Time {
+	out := make(map[string]map[string]time.Time, len(f.base))
+	for peer, beads := range f.base {
+		copied := make(map[string]time.Time, len(beads))
+		for id, agreed := range beads {
+			copied[id] = agreed
+		}
+		out[peer] = copied
+	}
+	return out
+}
+
+// saveFederationBase writes the agreed versions to beadsPath, replacing the
+// file atomically.
+func saveFederationBase(beadsPath string, base map[string]map[string]time.Time) error {
+	if beadsPath == "" {
+		return nil
+	}
+	if err := os.MkdirAll(beadsPath, 0755); err != nil {
+		return fmt.Errorf("failed to create beads directory: %w", err)
+	}
+	data, err := json.MarshalIndent(base, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal federation base: %w", err)
+	}
+	path := filepath.Join(beadsPath, federationBaseFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write federation base: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to replace federation base: %w", err)
+	}
+	return nil
+}
+
+// FederationConflicts returns unresolved conflicts across all peers.
+func (m *Manager) FederationConflicts() []FederationConflict {
+	m.federation.mu.Lock()
+	defer m.federation.mu.Unlock()
+
+	out := make([]FederationConflict, 0, len(m.federation.conflicts))
+	for _, c := range m.federation.conflicts {
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Peer != out[j].Peer {
+			return out[i].Peer < out[j].Peer
+		}
+		return out[i].BeadID < out[j].BeadID
+	})
+	return out
+}
+
+// FederationSyncResults returns the outcome of the latest HTTP sync with
+// each peer.
+func (m *Manager) FederationSyncResults() []*FederationSyncResult {
+	m.federation.mu.Lock()
+	defer m.federation.mu.Unlock()
+
+	out := make([]*FederationSyncResult, 0, len(m.federation.lastSync))
+	for _, r := range m.federation.lastSync {
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Peer < out[j].Peer })
+	return out
+}
+
+// syncWithHTTPPeer pulls a peer's bead index, merges it, then pushes the
+// beads the peer is missing or has an older version of.
+func (m *Manager) syncWithHTTPPeer(ctx context.Context, peer config.FederationPeer) (*FederationSyncResult, error) {
+	result := &FederationSyncResult{Peer: peer.Name, Pushed: []string{}}
+	defer m.recordFederationSync(result)
+
+	url := strings.TrimRight(peer.RemoteURL, "/") + FederationBeadsPath
+	var remote []*models.Bead
+	if err := m.federationRequest(ctx, http.MethodGet, url, peer.Token, nil, &remote); err != nil {
+		result.Error = err.Error()
+		return result, fmt.Errorf("failed to pull beads from peer %s: %w", peer.Name, err)
+	}
+	result.FederationMergeResult = *m.MergeFederatedBeads(peer.Name, remote)
+
+	index := m.FederationIndex()
+	localByID := make(map[string]*models.Bead, len(index))
+	for _, lb := range index {
+		localByID[lb.ID] = lb
+	}
+	remoteByID := make(map[string]*models.Bead, len(remote))
+	remoteOwn := make(map[string]*models.Bead) // Our beads the peer holds under namespaced IDs
+	for _, rb := range remote {
+		if rb == nil {
+			continue
+		}
+		remoteByID[rb.ID] = rb
+		if id := ownFederatedID(rb, localByID); id != "" {
+			remoteOwn[id] = withoutFederationID(rb)
+		}
+	}
+	conflicted := make(map[string]bool, len(result.Conflicts))
+	for _, c := range result.Conflicts {
+		conflicted[c.BeadID] = true
+	}
+	var push []*models.Bead
+	localIDs := make(map[string]string) // Peer's ID -> local ID of each pushed bead
+	for _, lb := range index {
+		if conflicted[lb.ID] {
+			continue
+		}
+		pb := peerView(lb, peer.Name)
+		rb, ok := remoteByID[pb.ID]
+		if own, held := remoteOwn[lb.ID]; held && pb.ID == lb.ID {
+			rb, ok = own, true
+		}
+		// A peer bead with our ID but another creation time is a different
+		// bead; the peer namespaces ours on receipt.
+		if !ok || !rb.CreatedAt.Equal(pb.CreatedAt) || (pb.UpdatedAt.After(rb.UpdatedAt) && !sameBeadContent(pb, rb)) {
+			push = append(push, pb)
+			localIDs[pb.ID] = lb.ID
+		}
+	}
+	if len(push) == 0 {
+		return result, nil
+	}
+	var pushed FederationMergeResult
+	if err := m.federationRequest(ctx, http.MethodPost, url, peer.Token, push, &pushed); err != nil {
+		result.Error = err.Error()
+		return result, fmt.Errorf("failed to push beads to peer %s: %w", peer.Name, err)
+	}
+	// Beads the peer refused as conflicts were not agreed on.
+	refused := make(map[string]bool, len(pushed.Conflicts))
+	for _, c := range pushed.Conflicts {
+		if c.RemoteBeadID != "" {
+			refused[c.RemoteBeadID] = true
+		} else {
+			refused[c.BeadID] = true
+		}
+	}
+
+	m.mu.RLock()
+	beadsPath := m.beadsPath
+	m.mu.RUnlock()
+	m.federation.mu.Lock()
+	m.federation.loadBaseLocked(beadsPath)
+	base := m.federation.peerBase(peer.Name)
+	for _, b := range push {
+		if refused[b.ID] {
+			continue
+		}
+		base[localIDs[b.ID]] = b.UpdatedAt
+		result.Pushed = append(result.Pushed, localIDs[b.ID])
+	}
+	snapshot := m.federation.snapshotBaseLocked()
+	m.federation.mu.Unlock()
+
+	if err := saveFederationBase(beadsPath, snapshot); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to save federation base: %v\n", err)
+	}
+	return result, nil
+}
+
+func (m *Manager) recordFederationSync(result *FederationSyncResult) {
+	m.federation.mu.Lock()
+	defer m.federation.mu.Unlock()
+	m.federation.initLocked()
+	m.federation.lastSync[result.Peer] = result
+}
+
+// federationRequest sends a JSON request to a peer and decodes the JSON
+// response into out when out is non-nil.
+func (m *Manager) federationRequest(ctx context.Context, method, url, token string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	client := m.federation.client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("peer returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
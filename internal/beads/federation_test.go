@@ -0,0 +1,295 @@
+package beads
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jordanhubbard/loom/pkg/config"
+	"github.com/jordanhubbard/loom/pkg/models"
+)
+
+// newFederationPeer serves a Manager's bead index the way the API's
+// federation endpoint does.
+func newFederationPeer(t *testing.T, peer *Manager) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != FederationBeadsPath {
+			http.NotFound(w, r)
+			return
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer peer-token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			_ = json.NewEncoder(w).Encode(peer.FederationIndex())
+		case http.MethodPost:
+			var remote []*models.Bead
+			if err := json.NewDecoder(r.Body).Decode(&remote); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(peer.MergeFederatedBeads("local", remote))
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestManager_SyncFederationHTTP(t *testing.T) {
+	local := NewManager("")
+	local.SetBeadsPath(t.TempDir())
+	local.SetProjectPrefix("proj", "lo")
+	remote := NewManager("")
+	remote.SetBeadsPath(t.TempDir())
+	remote.SetProjectPrefix("proj", "re")
+
+	server := newFederationPeer(t, remote)
+	cfg := &config.BeadsFederationConfig{
+		Enabled:  true,
+		SyncMode: FederationSyncModeHTTP,
+		Peers: []config.FederationPeer{
+			{Name: "remote", RemoteURL: server.URL + "/", Enabled: true, Token: "peer-token"},
+			{Name: "disabled", RemoteURL: "http://127.0.0.1:1", Enabled: false},
+		},
+	}
+	runSync := func() {
+		t.Helper()
+		if err := local.SyncFederation(context.Background(), cfg); err != nil {
+			t.Fatalf("SyncFederation() error = %v", err)
+		}
+	}
+	title := func(m *Manager, id string) string {
+		t.Helper()
+		b, err := m.GetBead(id)
+		if err != nil {
+			t.Fatalf("GetBead(%s) error = %v", id, err)
+		}
+		return b.Title
+	}
+
+	mine, _ := local.CreateBead("Local task", "", models.BeadPriorityP2, "task", "proj")
+	theirs, _ := remote.CreateBead("Remote task", "", models.BeadPriorityP2, "task", "proj")
+
+	// First sync imports the peer's bead and pushes ours.
+	runSync()
+	if got := title(local, theirs.ID); got != "Remote task" {
+		t.Errorf("imported title = %q", got)
+	}
+	if got := title(remote, mine.ID); got != "Local task" {
+		t.Errorf("pushed title = %q", got)
+	}
+	results := local.FederationSyncResults()
+	if len(results) != 1 || results[0].Peer != "remote" {
+		t.Fatalf("FederationSyncResults() = %+v, want one result for remote", results)
+	}
+	if len(results[0].Imported) != 1 || len(results[0].Pushed) != 1 {
+		t.Errorf("result = %+v, want 1 imported and 1 pushed", results[0])
+	}
+
+	// A newer remote edit replaces the local copy.
+	time.Sleep(time.Millisecond)
+	if err := remote.UpdateBead(theirs.ID, map[string]interface{}{"title": "Remote task v2"}); err != nil {
+		t.Fatal(err)
+	}
+	runSync()
+	if got := title(local, theirs.ID); got != "Remote task v2" {
+		t.Errorf("title after remote edit = %q, want newer remote version", got)
+	}
+
+	// A newer local edit is pushed to the peer.
+	time.Sleep(time.Millisecond)
+	if err := local.UpdateBead(mine.ID, map[string]interface{}{"title": "Local task v2"}); err != nil {
+		t.Fatal(err)
+	}
+	runSync()
+	if got := title(remote, mine.ID); got != "Local task v2" {
+		t.Errorf("peer title after local edit = %q, want newer local version", got)
+	}
+	if len(local.FederationConflicts()) != 0 {
+		t.Fatalf("unexpected conflicts: %+v", local.FederationConflicts())
+	}
+
+	// Edits on both sides since the last sync are surfaced, not overwritten.
+	time.Sleep(time.Millisecond)
+	if err := local.UpdateBead(mine.ID, map[string]interface{}{"title": "Local edit"}); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond)
+	if err := remote.UpdateBead(mine.ID, map[string]interface{}{"title": "Remote edit"}); err != nil {
+		t.Fatal(err)
+	}
+	runSync()
+	if got := title(local, mine.ID); got != "Local edit" {
+		t.Errorf("local title = %q, conflicting bead must not be overwritten", got)
+	}
+	if got := title(remote, mine.ID); got != "Remote edit" {
+		t.Errorf("peer title = %q, conflicting bead must not be overwritten", got)
+	}
+	conflicts := local.FederationConflicts()
+	if len(conflicts) != 1 || conflicts[0].BeadID != mine.ID || conflicts[0].Peer != "remote" {
+		t.Fatalf("FederationConflicts() = %+v, want conflict on %s", conflicts, mine.ID)
+	}
+
+	// Resolving the conflict on either side clears it.
+	time.Sleep(time.Millisecond)
+	if err := remote.UpdateBead(mine.ID, map[string]interface{}{"title": "Local edit"}); err != nil {
+		t.Fatal(err)
+	}
+	runSync()
+	if len(local.FederationConflicts()) != 0 {
+		t.Errorf("conflict not cleared after content converged: %+v", local.FederationConflicts())
+	}
+}
+
+func TestManager_SyncFederationHTTPPeerError(t *testing.T) {
+	local := NewManager("")
+	local.SetBeadsPath(t.TempDir())
+	server := newFederationPeer(t, NewManager(""))
+
+	cfg := &config.BeadsFederationConfig{
+		Enabled:  true,
+		SyncMode: FederationSyncModeHTTP,
+		Peers:    []config.FederationPeer{{Name: "remote", RemoteURL: server.URL, Enabled: true, Token: "wrong"}},
+	}
+	if err := local.SyncFederation(context.Background(), cfg); err == nil {
+		t.Fatal("SyncFederation() error = nil, want unauthorized peer error")
+	}
+	results := local.FederationSyncResults()
+	if len(results) != 1 || results[0].Error == "" {
+		t.Errorf("FederationSyncResults() = %+v, want recorded error", results)
+	}
+}
+
+func TestManager_MergeFederatedBeadsNoBaseIsConflict(t *testing.T) {
+	local := NewManager("")
+	local.SetBeadsPath(t.TempDir())
+	local.SetProjectPrefix("proj", "lo")
+	mine, _ := local.CreateBead("Shared task", "", models.BeadPriorityP2, "task", "proj")
+
+	// The peer holds a different copy that this instance never agreed on,
+	// e.g. both sides started from the same checkout and edited it.
+	theirs := *mine
+	theirs.Title = "Peer edit"
+	theirs.UpdatedAt = mine.UpdatedAt.Add(time.Minute)
+
+	result := local.MergeFederatedBeads("remote", []*models.Bead{&theirs})
+	if len(result.Conflicts) != 1 || len(result.Updated) != 0 {
+		t.Fatalf("result = %+v, want a conflict without a base", result)
+	}
+	if b, _ := local.GetBead(mine.ID); b.Title != "Shared task" {
+		t.Errorf("title = %q, conflicting bead must not be overwritten", b.Title)
+	}
+}
+
+func TestManager_FederationBasePersists(t *testing.T) {
+	dir := t.TempDir()
+	remote := &models.Bead{ID: "re-1", Title: "Remote task", Status: models.BeadStatusOpen, ProjectID: "proj", UpdatedAt: time.Now().Add(-time.Hour).Truncate(time.Second)}
+
+	first := NewManager("")
+	first.SetBeadsPath(dir)
+	if result := first.MergeFederatedBeads("remote", []*models.Bead{remote}); len(result.Imported) != 1 {
+		t.Fatalf("result = %+v, want the bead imported", result)
+	}
+
+	// After a restart the agreed version is still known, so a peer-only
+	// edit is taken rather than reported as a conflict.
+	restarted := NewManager("")
+	restarted.SetBeadsPath(dir)
+	if err := restarted.LoadBeadsFromFilesystem("proj", dir); err != nil {
+		t.Fatal(err)
+	}
+	edited := *remote
+	edited.Title = "Remote task v2"
+	edited.UpdatedAt = remote.UpdatedAt.Add(time.Minute)
+	result := restarted.MergeFederatedBeads("remote", []*models.Bead{&edited})
+	if len(result.Conflicts) != 0 || len(result.Updated) != 1 {
+		t.Fatalf("result = %+v, want the peer edit applied", result)
+	}
+
+	// Another peer never agreed on it.
+	if result := restarted.MergeFederatedBeads("other", []*models.Bead{remote}); len(result.Conflicts) != 1 {
+		t.Errorf("result = %+v, want a conflict for a peer with no base", result)
+	}
+}
+
+func TestManager_SyncFederationHTTPSameSequentialIDs(t *testing.T) {
+	local := NewManager("")
+	local.SetBeadsPath(t.TempDir())
+	local.SetProjectPrefix("proj", "bd")
+	remote := NewManager("")
+	remote.SetBeadsPath(t.TempDir())
+	remote.SetProjectPrefix("proj", "bd")
+
+	server := newFederationPeer(t, remote)
+	cfg := &config.BeadsFederationConfig{
+		Enabled:  true,
+		SyncMode: FederationSyncModeHTTP,
+		Peers:    []config.FederationPeer{{Name: "remote", RemoteURL: server.URL, Enabled: true, Token: "peer-token"}},
+	}
+
+	// Both instances allocate the same sequential ID for different beads.
+	mine, _ := local.CreateBead("Local task", "", models.BeadPriorityP2, "task", "proj")
+	time.Sleep(time.Millisecond)
+	theirs, _ := remote.CreateBead("Remote task", "", models.BeadPriorityP2, "task", "proj")
+	if mine.ID != theirs.ID {
+		t.Fatalf("IDs %s and %s, want the same sequential ID", mine.ID, theirs.ID)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := local.SyncFederation(context.Background(), cfg); err != nil {
+			t.Fatalf("SyncFederation() error = %v", err)
+		}
+	}
+	if conflicts := local.FederationConflicts(); len(conflicts) != 0 {
+		t.Fatalf("FederationConflicts() = %+v, want distinct beads rather than conflicts", conflicts)
+	}
+	if b, _ := local.GetBead(mine.ID); b.Title != "Local task" {
+		t.Errorf("local %s title = %q, want it kept", mine.ID, b.Title)
+	}
+	if b, err := local.GetBead("remote-" + theirs.ID); err != nil || b.Title != "Remote task" {
+		t.Errorf("GetBead(remote-%s) = %+v, %v; want the peer's bead namespaced", theirs.ID, b, err)
+	}
+	if b, _ := remote.GetBead(theirs.ID); b.Title != "Remote task" {
+		t.Errorf("peer %s title = %q, want it kept", theirs.ID, b.Title)
+	}
+	if b, err := remote.GetBead("local-" + mine.ID); err != nil || b.Title != "Local task" {
+		t.Errorf("peer GetBead(local-%s) = %+v, %v; want our bead namespaced", mine.ID, b, err)
+	}
+	if n := len(remote.FederationIndex()); n != 2 {
+		t.Errorf("peer holds %d beads, want 2: its own and ours", n)
+	}
+	if results := local.FederationSyncResults(); len(results[0].Imported) != 0 || len(results[0].Pushed) != 0 {
+		t.Errorf("second sync = %+v, want nothing left to exchange", results[0])
+	}
+}
+
+func TestManager_MergeFederatedBeadsRejectsUnsafeIDs(t *testing.T) {
+	dir := t.TempDir()
+	local := NewManager("")
+	local.SetBeadsPath(dir)
+
+	now := time.Now()
+	result := local.MergeFederatedBeads("remote", []*models.Bead{
+		{ID: "../../escape", Title: "Escape", UpdatedAt: now},
+		{ID: "..", Title: "Parent", UpdatedAt: now},
+		{ID: "re-1", Title: "Fine", UpdatedAt: now},
+	})
+	if len(result.Rejected) != 2 || len(result.Imported) != 1 {
+		t.Fatalf("result = %+v, want 2 rejected and 1 imported", result)
+	}
+	if _, err := local.GetBead("../../escape"); err == nil {
+		t.Error("a rejected bead was merged")
+	}
+	matches, _ := filepath.Glob(filepath.Join(dir, "..", "*escape*"))
+	if len(matches) != 0 {
+		t.Errorf("bead file written outside the beads directory: %v", matches)
+	}
+}
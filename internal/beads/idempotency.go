@@ -0,0 +1,104 @@
+package beads
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jordanhubbard/loom/pkg/models"
+)
+
+// idempotencyFile holds the idempotency key -> bead ID mapping inside a
+// beads directory, so retried creates are recognised after a restart.
+const idempotencyFile = "idempotency.json"
+
+// CreateBeadIdempotent creates a bead like CreateBead, unless a bead was
+// already created with the same key, in which case that bead is returned.
+// created reports whether a new bead was made. An empty key always creates.
+func (m *Manager) CreateBeadIdempotent(key, title, description string, priority models.BeadPriority, beadType, projectID string) (bead *models.Bead, created bool, err error) {
+	if key == "" {
+		bead, err = m.CreateBead(title, description, priority, beadType, projectID)
+		return bead, err == nil, err
+	}
+
+	// Serialize keyed creates so concurrent retries can't both create.
+	m.idempotencyMu.Lock()
+	defer m.idempotencyMu.Unlock()
+
+	m.mu.RLock()
+	beadID, ok := m.idempotencyKeys[key]
+	m.mu.RUnlock()
+	if ok {
+		if existing, err := m.GetBead(beadID); err == nil && existing != nil {
+			return existing, false, nil
+		}
+	}
+
+	bead, err = m.CreateBead(title, description, priority, beadType, projectID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	m.mu.Lock()
+	if m.idempotencyKeys == nil {
+		m.idempotencyKeys = make(map[string]string)
+	}
+	m.idempotencyKeys[key] = bead.ID
+	beadsPath := m.beadsPath
+	keys := make(map[string]string, len(m.idempotencyKeys))
+	for k, v := range m.idempotencyKeys {
+		keys[k] = v
+	}
+	m.mu.Unlock()
+
+	if err := saveIdempotencyKeys(beadsPath, keys); err != nil {
+		// The mapping still holds for this process.
+		fmt.Fprintf(os.Stderr, "Warning: failed to save bead idempotency keys: %v\n", err)
+	}
+	return bead, true, nil
+}
+
+// loadIdempotencyKeys merges the key mapping stored in beadsPath. Callers
+// must hold m.mu.
+func (m *Manager) loadIdempotencyKeys(beadsPath string) {
+	data, err := os.ReadFile(filepath.Join(beadsPath, idempotencyFile))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "Warning: failed to read bead idempotency keys: %v\n", err)
+		}
+		return
+	}
+	var keys map[string]string
+	if err := json.Unmarshal(data, &keys); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to parse bead idempotency keys: %v\n", err)
+		return
+	}
+	if m.idempotencyKeys == nil {
+		m.idempotencyKeys = make(map[string]string, len(keys))
+	}
+	for k, v := range keys {
+		m.idempotencyKeys[k] = v
+	}
+}
+
+// saveIdempotencyKeys writes the key mapping to beadsPath, replacing the
+// file atomically.
+func saveIdempotencyKeys(beadsPath string, keys map[string]string) error {
+	if err := os.MkdirAll(beadsPath, 0755); err != nil {
+		return fmt.Errorf("failed to create beads directory: %w", err)
+	}
+	data, err := json.MarshalIndent(keys, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal idempotency keys: %w", err)
+	}
+	path := filepath.Join(beadsPath, idempotencyFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write idempotency keys: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to replace idempotency keys: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,260 @@
+package beads
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jordanhubbard/loom/pkg/models"
+	"gopkg.in/yaml.v3"
+)
+
+// Import formats accepted by ImportBeads.
+const (
+	ImportFormatYAML     = "yaml"
+	ImportFormatMarkdown = "markdown"
+)
+
+// ErrInvalidImport is returned by ImportBeads when the document cannot be
+// imported as given; no beads were created.
+var ErrInvalidImport = errors.New("invalid import file")
+
+// importFile is the document read by ImportBeads. The beads may also be
+// given as a bare list.
+type importFile struct {
+	Beads []importBead `yaml:"beads"`
+}
+
+// importBead is one bead of an import file. Key names the bead for other
+// entries' depends_on; depends_on may also name beads that already exist.
+type importBead struct {
+	Key         string         `yaml:"id"`
+	Title       string         `yaml:"title"`
+	Description string         `yaml:"description"`
+	Priority    importPriority `yaml:"priority"`
+	Type        string         `yaml:"type"`
+	Tags        []string       `yaml:"tags"`
+	DependsOn   []string       `yaml:"depends_on"`
+}
+
+// importPriority accepts a priority as 0-3 or P0-P3. Unset means P2.
+type importPriority struct {
+	set   bool
+	value models.BeadPriority
+}
+
+func (p *importPriority) UnmarshalYAML(node *yaml.Node) error {
+	n, err := strconv.Atoi(strings.TrimPrefix(strings.ToUpper(node.Value), "P"))
+	if err != nil {
+		return fmt.Errorf("invalid priority %q", node.Value)
+	}
+	p.set, p.value = true, models.BeadPriority(n)
+	return nil
+}
+
+// ImportBeads creates the beads described by a YAML document, or by the
+// YAML front matter of a Markdown document, in a project. Beads are created
+// first and their depends_on references wired up as blocking dependencies
+// once all IDs are known. Nothing is created when the document is invalid
+// or references unknown beads. The created beads are returned in document
+// order.
+func (m *Manager) ImportBeads(projectID string, data []byte, format string) ([]*models.Bead, error) {
+	if projectID == "" {
+		return nil, fmt.Errorf("project ID is required")
+	}
+
+	entries, err := parseImport(data, format)
+	if err == nil {
+		err = m.validateImport(entries)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+	}
+
+	specs := make([]BeadSpec, len(entries))
+	for i, entry := range entries {
+		specs[i] = BeadSpec{
+			Title:       entry.Title,
+			Description: entry.Description,
+			Priority:    models.BeadPriorityP2,
+			Type:        entry.Type,
+			ProjectID:   projectID,
+			Tags:        entry.Tags,
+		}
+		if entry.Priority.set {
+			specs[i].Priority = entry.Priority.value
+		}
+		if specs[i].Type == "" {
+			specs[i].Type = "task"
+		}
+	}
+	created, err := m.CreateBeads(specs)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]string, len(entries))
+	for i, entry := range entries {
+		if entry.Key != "" {
+			ids[entry.Key] = created[i].ID
+		}
+	}
+	for i, entry := range entries {
+		if len(entry.DependsOn) == 0 {
+			continue
+		}
+		for _, ref := range entry.DependsOn {
+			target, ok := ids[ref]
+			if !ok {
+				target = ref
+			}
+			if err := m.AddDependency(created[i].ID, target, "blocks"); err != nil {
+				return created, fmt.Errorf("failed to add dependency %s -> %s: %w", created[i].ID, target, err)
+			}
+		}
+		if err := m.SaveBeadToFilesystem(created[i], m.beadsPath); err != nil {
+			return created, fmt.Errorf("failed to save bead %s: %w", created[i].ID, err)
+		}
+	}
+
+	return created, nil
+}
+
+// parseImport decodes an import document. Its YAML lists the beads either
+// under a beads key or as a bare list.
+func parseImport(data []byte, format string) ([]importBead, error) {
+	switch strings.ToLower(format) {
+	case ImportFormatYAML, "yml", "":
+	case ImportFormatMarkdown, "md":
+		frontMatter, err := markdownFrontMatter(data)
+		if err != nil {
+			return nil, err
+		}
+		data = frontMatter
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+
+	var entries []importBead
+	if doc.Content[0].Kind == yaml.SequenceNode {
+		if err := doc.Content[0].Decode(&entries); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML: %w", err)
+		}
+		return entries, nil
+	}
+
+	var file importFile
+	if err := doc.Content[0].Decode(&file); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	return file.Beads, nil
+}
+
+// markdownFrontMatter returns the YAML between the leading --- lines of a
+// Markdown document.
+func markdownFrontMatter(data []byte) ([]byte, error) {
+	data = bytes.TrimLeft(data, "\ufeff \t\r\n")
+	rest, ok := bytes.CutPrefix(data, []byte("---"))
+	if !ok {
+		return nil, fmt.Errorf("markdown import needs YAML front matter between --- lines")
+	}
+	end := bytes.Index(rest, []byte("\n---"))
+	if end < 0 {
+		return nil, fmt.Errorf("markdown front matter is not closed with ---")
+	}
+	return rest[:end], nil
+}
+
+// validateImport checks the entries before anything is created: there must
+// be at least one, each needs a title and a valid priority, keys must be
+// unique, every depends_on must name another entry or an existing bead, and
+// entries must not depend on each other in a cycle.
+func (m *Manager) validateImport(entries []importBead) error {
+	if len(entries) == 0 {
+		return fmt.Errorf("no beads to import")
+	}
+
+	keys := make(map[string]int, len(entries))
+	for i, entry := range entries {
+		if strings.TrimSpace(entry.Title) == "" {
+			return fmt.Errorf("bead %d: title is required", i)
+		}
+		if p := entry.Priority.value; p < models.BeadPriorityP0 || p > models.BeadPriorityP3 {
+			return fmt.Errorf("bead %d (%s): invalid priority %d", i, entry.Title, p)
+		}
+		if entry.Key == "" {
+			continue
+		}
+		if _, dup := keys[entry.Key]; dup {
+			return fmt.Errorf("duplicate bead id %q", entry.Key)
+		}
+		keys[entry.Key] = i
+	}
+
+	m.mu.RLock()
+	var unknown []string
+	for _, entry := range entries {
+		for _, ref := range entry.DependsOn {
+			if _, ok := keys[ref]; ok {
+				continue
+			}
+			if _, ok := m.beads[ref]; ok {
+				continue
+			}
+			unknown = append(unknown, fmt.Sprintf("%q (in %q)", ref, entry.Title))
+		}
+	}
+	m.mu.RUnlock()
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown depends_on references: %s", strings.Join(unknown, ", "))
+	}
+
+	// Only dependencies between imported beads can form a cycle: existing
+	// beads cannot depend on beads that do not exist yet.
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make([]int, len(entries))
+	var visit func(i int) error
+	visit = func(i int) error {
+		state[i] = visiting
+		for _, ref := range entries[i].DependsOn {
+			j, ok := keys[ref]
+			if !ok {
+				continue
+			}
+			switch state[j] {
+			case visiting:
+				return fmt.Errorf("depends_on cycle through %q and %q", entries[i].Key, ref)
+			case unvisited:
+				if err := visit(j); err != nil {
+					return err
+				}
+			}
+		}
+		state[i] = done
+		return nil
+	}
+	for i := range entries {
+		if state[i] == unvisited {
+			if err := visit(i); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
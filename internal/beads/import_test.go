@@ -0,0 +1,97 @@
+package beads
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jordanhubbard/loom/pkg/models"
+)
+
+func TestManager_ImportBeads(t *testing.T) {
+	manager := NewManager("")
+	manager.SetBeadsPath(t.TempDir())
+	existing, _ := manager.CreateBead("Existing", "", models.BeadPriorityP2, "task", "proj-a")
+
+	data := []byte(`beads:
+  - id: client
+    title: Build the client
+    priority: P0
+    depends_on: [api, ` + existing.ID + `]
+  - id: api
+    title: Design the API
+    description: REST first
+    priority: 1
+    type: epic
+    tags: [api]
+`)
+	created, err := manager.ImportBeads("proj-a", data, ImportFormatYAML)
+	if err != nil {
+		t.Fatalf("ImportBeads() error = %v", err)
+	}
+	if len(created) != 2 {
+		t.Fatalf("ImportBeads() created %d beads, want 2", len(created))
+	}
+	client, api := created[0], created[1]
+	if client.Priority != models.BeadPriorityP0 || client.Type != "task" || client.ProjectID != "proj-a" {
+		t.Errorf("client = %+v, want a P0 task in proj-a", client)
+	}
+	if api.Priority != models.BeadPriorityP1 || api.Type != "epic" || api.Description != "REST first" || !slices.Equal(api.Tags, []string{"api"}) {
+		t.Errorf("api = %+v, want the fields from the file", api)
+	}
+	if !slices.Equal(client.BlockedBy, []string{api.ID, existing.ID}) {
+		t.Errorf("client.BlockedBy = %v, want [%s %s]", client.BlockedBy, api.ID, existing.ID)
+	}
+	if !slices.Contains(api.Blocks, client.ID) {
+		t.Errorf("api.Blocks = %v, want %s", api.Blocks, client.ID)
+	}
+}
+
+func TestManager_ImportBeads_Markdown(t *testing.T) {
+	manager := NewManager("")
+	manager.SetBeadsPath(t.TempDir())
+
+	data := []byte("---\n- title: From front matter\n---\n\n# Sprint plan\n\nNotes.\n")
+	created, err := manager.ImportBeads("proj-a", data, ImportFormatMarkdown)
+	if err != nil {
+		t.Fatalf("ImportBeads() error = %v", err)
+	}
+	if len(created) != 1 || created[0].Title != "From front matter" || created[0].Priority != models.BeadPriorityP2 {
+		t.Errorf("ImportBeads() = %+v, want one P2 bead from the front matter", created)
+	}
+}
+
+func TestManager_ImportBeads_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		format string
+		want   string
+	}{
+		{"unknown refs", "- {id: a, title: A, depends_on: [b, nope]}\n- {id: b, title: B}\n- {title: C, depends_on: [missing]}\n", "yaml", `unknown depends_on references: "missing" (in "C"), "nope" (in "A")`},
+		{"cycle", "- {id: a, title: A, depends_on: [b]}\n- {id: b, title: B, depends_on: [a]}\n", "yaml", "cycle"},
+		{"duplicate id", "- {id: a, title: A}\n- {id: a, title: B}\n", "yaml", "duplicate bead id"},
+		{"missing title", "- {id: a}\n", "yaml", "title is required"},
+		{"bad priority", "- {title: A, priority: P7}\n", "yaml", "invalid priority"},
+		{"empty", "beads: []\n", "yaml", "no beads"},
+		{"no front matter", "# Plan\n", "markdown", "front matter"},
+		{"format", "- {title: A}\n", "toml", "unsupported format"},
+	}
+	for _, tt := range tests {
+		manager := NewManager("")
+		manager.SetBeadsPath(t.TempDir())
+		manager.CreateBead("Existing", "", models.BeadPriorityP2, "task", "proj-a")
+
+		created, err := manager.ImportBeads("proj-a", []byte(tt.data), tt.format)
+		if !errors.Is(err, ErrInvalidImport) || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error = %v, want ErrInvalidImport mentioning %q", tt.name, err, tt.want)
+		}
+		if created != nil {
+			t.Errorf("%s: created %d beads, want none", tt.name, len(created))
+		}
+		if beads, _ := manager.ListBeads(nil); len(beads) != 1 {
+			t.Errorf("%s: %d beads after a failed import, want only the existing one", tt.name, len(beads))
+		}
+	}
+}
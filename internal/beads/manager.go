@@ -9,6 +9,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -34,6 +35,26 @@ type Manager struct {
 
 	// Git-centric storage fields (per-project)
 	gitConfigs map[string]*GitConfig // Project ID -> git configuration
+
+	// Idempotency key -> bead ID for CreateBeadIdempotent
+	idempotencyKeys map[string]string
+	idempotencyMu   sync.Mutex
+
+	// Bead ID -> archive file for beads archived by this process
+	archivedBeads map[string]string
+	archiveMu     sync.Mutex
+
+	// Outbound status change webhooks (per-project)
+	webhooks map[string]statusWebhook
+
+	// HTTP federation state; see federation.go
+	federation federationState
+
+	// Beads removed from the work graph and when beads last gained an edge,
+	// for GetWorkGraphSince; deletions before deletionsFrom are forgotten
+	deletedBeads  map[string]deletedBead
+	deletionsFrom time.Time
+	edgesChanged  map[string]time.Time
 }
 
 // GitConfig stores git storage configuration for a project
@@ -60,6 +81,7 @@ func NewManager(bdPath string) *Manager {
 		projectPrefixes: make(map[string]string),
 		projectNextIDs:  make(map[string]int),
 		gitConfigs:      make(map[string]*GitConfig),
+		deletionsFrom:   time.Now(),
 	}
 }
 
@@ -101,6 +123,8 @@ func (m *Manager) Reset() {
 	m.nextID = 1
 	m.projectPrefixes = make(map[string]string)
 	m.projectNextIDs = make(map[string]int)
+	m.idempotencyKeys = nil
+	m.archivedBeads = nil
 }
 
 // SetBeadsPath sets the path to the beads directory
@@ -216,26 +240,7 @@ func (m *Manager) CreateBead(title, description string, priority models.BeadPrio
 
 	// Fallback to filesystem-based bead creation
 	if beadID == "" {
-		// Get or initialize project-specific counter
-		nextID := m.projectNextIDs[projectID]
-		if nextID == 0 {
-			nextID = 1
-		}
-
-		// Generate a new ID with project prefix
-		beadID = fmt.Sprintf("%s-%03d", prefix, nextID)
-		nextID++
-
-		// Check for existing beads to avoid ID collision
-		for {
-			if _, exists := m.beads[beadID]; !exists {
-				break
-			}
-			beadID = fmt.Sprintf("%s-%03d", prefix, nextID)
-			nextID++
-		}
-
-		m.projectNextIDs[projectID] = nextID
+		beadID = m.allocateBeadID(projectID, prefix)
 	}
 
 	// Create internal bead representation
@@ -269,6 +274,125 @@ func (m *Manager) CreateBead(title, description string, priority models.BeadPrio
 	return bead, nil
 }
 
+// allocateBeadID returns the next unused ID from the project's sequence.
+// Callers must hold m.mu.
+func (m *Manager) allocateBeadID(projectID, prefix string) string {
+	// Get or initialize project-specific counter
+	nextID := m.projectNextIDs[projectID]
+	if nextID == 0 {
+		nextID = 1
+	}
+
+	// Generate a new ID with project prefix
+	beadID := fmt.Sprintf("%s-%03d", prefix, nextID)
+	nextID++
+
+	// Check for existing beads to avoid ID collision
+	for {
+		if _, exists := m.beads[beadID]; !exists {
+			break
+		}
+		beadID = fmt.Sprintf("%s-%03d", prefix, nextID)
+		nextID++
+	}
+
+	m.projectNextIDs[projectID] = nextID
+	return beadID
+}
+
+// BeadSpec describes a bead to create with CreateBeads
+type BeadSpec struct {
+	Title       string
+	Description string
+	Priority    models.BeadPriority
+	Type        string
+	ProjectID   string
+	Tags        []string
+}
+
+// validate checks that a spec can be turned into a bead
+func (s BeadSpec) validate() error {
+	if strings.TrimSpace(s.Title) == "" {
+		return fmt.Errorf("title is required")
+	}
+	if s.Priority < models.BeadPriorityP0 || s.Priority > models.BeadPriorityP3 {
+		return fmt.Errorf("invalid priority %d", s.Priority)
+	}
+	return nil
+}
+
+// CreateBeads creates several beads at once, allocating IDs from each
+// project's sequence under a single lock and writing them to the filesystem
+// in one pass. Beads are always stored on the filesystem, even when the bd
+// CLI is configured. If any spec is invalid, or any bead fails to persist,
+// no beads are created. The created beads are returned in input order.
+func (m *Manager) CreateBeads(specs []BeadSpec) ([]*models.Bead, error) {
+	for i, spec := range specs {
+		if err := spec.validate(); err != nil {
+			return nil, fmt.Errorf("bead spec %d: %w", i, err)
+		}
+	}
+
+	m.mu.Lock()
+	now := time.Now()
+	created := make([]*models.Bead, 0, len(specs))
+	for _, spec := range specs {
+		prefix := "bd" // default
+		if p, ok := m.projectPrefixes[spec.ProjectID]; ok && p != "" {
+			prefix = p
+		}
+
+		bead := &models.Bead{
+			ID:          m.allocateBeadID(spec.ProjectID, prefix),
+			Type:        spec.Type,
+			Title:       spec.Title,
+			Description: spec.Description,
+			Status:      models.BeadStatusOpen,
+			Priority:    spec.Priority,
+			ProjectID:   spec.ProjectID,
+			Tags:        spec.Tags,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		m.beads[bead.ID] = bead
+		m.workGraph.Beads[bead.ID] = bead
+		created = append(created, bead)
+	}
+	m.workGraph.UpdatedAt = now
+
+	// Release lock before I/O operations
+	m.mu.Unlock()
+
+	for i, bead := range created {
+		if err := m.SaveBeadToFilesystem(bead, m.beadsPath); err != nil {
+			m.removeBeads(created)
+			return nil, fmt.Errorf("failed to save bead %d (%s): %w", i, bead.ID, err)
+		}
+	}
+
+	return created, nil
+}
+
+// removeBeads drops beads from memory and deletes any files written for them
+func (m *Manager) removeBeads(beads []*models.Bead) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	for _, bead := range beads {
+		if path, ok := m.beadFiles[bead.ID]; ok {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				log.Printf("[Beads] failed to remove %s during rollback: %v", path, err)
+			}
+			delete(m.beadFiles, bead.ID)
+		}
+		delete(m.beads, bead.ID)
+		delete(m.workGraph.Beads, bead.ID)
+		m.recordDeleted(bead, now)
+	}
+	m.workGraph.UpdatedAt = now
+}
+
 // GetBead retrieves a bead by ID
 func (m *Manager) GetBead(id string) (*models.Bead, error) {
 	m.mu.RLock()
@@ -302,6 +426,86 @@ func (m *Manager) ListBeads(filters map[string]interface{}) ([]*models.Bead, err
 	return beads, nil
 }
 
+// Match weights for SearchBeads ranking. Each query term scores the weight
+// of the best place it was found.
+const (
+	searchWeightTitle       = 4
+	searchWeightTag         = 2
+	searchWeightDescription = 1
+)
+
+// SearchBeads returns beads matching every whitespace-separated term of
+// query, case-insensitively, in their title, description or tags. Results
+// honor the same structured filters as ListBeads and are ranked by where
+// the terms matched (title first, then tags, then description), with ties
+// broken by priority and ID. An empty query returns all filtered beads.
+func (m *Manager) SearchBeads(query string, filters map[string]interface{}) ([]*models.Bead, error) {
+	terms := strings.Fields(strings.ToLower(query))
+
+	m.mu.RLock()
+	type hit struct {
+		bead  *models.Bead
+		score int
+	}
+	hits := make([]hit, 0)
+	for _, bead := range m.beads {
+		if !m.matchesFilters(bead, filters) {
+			continue
+		}
+		if score, ok := searchScore(bead, terms); ok {
+			hits = append(hits, hit{bead: bead, score: score})
+		}
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].score != hits[j].score {
+			return hits[i].score > hits[j].score
+		}
+		if hits[i].bead.Priority != hits[j].bead.Priority {
+			return hits[i].bead.Priority < hits[j].bead.Priority
+		}
+		return hits[i].bead.ID < hits[j].bead.ID
+	})
+
+	beads := make([]*models.Bead, len(hits))
+	for i, h := range hits {
+		beads[i] = h.bead
+	}
+	return beads, nil
+}
+
+// searchScore reports whether bead matches all terms and, if so, the sum of
+// the best match weight for each term
+func searchScore(bead *models.Bead, terms []string) (int, bool) {
+	title := strings.ToLower(bead.Title)
+	description := strings.ToLower(bead.Description)
+
+	score := 0
+	for _, term := range terms {
+		switch {
+		case strings.Contains(title, term):
+			score += searchWeightTitle
+		case tagsContain(bead.Tags, term):
+			score += searchWeightTag
+		case strings.Contains(description, term):
+			score += searchWeightDescription
+		default:
+			return 0, false
+		}
+	}
+	return score, true
+}
+
+func tagsContain(tags []string, term string) bool {
+	for _, tag := range tags {
+		if strings.Contains(strings.ToLower(tag), term) {
+			return true
+		}
+	}
+	return false
+}
+
 // UpdateBead updates a bead
 func (m *Manager) UpdateBead(id string, updates map[string]interface{}) error {
 	// Update in-memory state with write lock
@@ -314,6 +518,7 @@ func (m *Manager) UpdateBead(id string, updates map[string]interface{}) error {
 	}
 
 	previousAssigned := bead.AssignedTo
+	previousStatus := bead.Status
 	assignedUpdated := false
 
 	// Apply updates
@@ -350,6 +555,9 @@ func (m *Manager) UpdateBead(id string, updates map[string]interface{}) error {
 	if parent, ok := updates["parent"].(string); ok {
 		bead.Parent = parent
 	}
+	if capability, ok := updates["required_capability"].(string); ok {
+		bead.RequiredCapability = capability
+	}
 	if tags, ok := updates["tags"].([]string); ok {
 		bead.Tags = tags
 	}
@@ -377,6 +585,16 @@ func (m *Manager) UpdateBead(id string, updates map[string]interface{}) error {
 	bead.UpdatedAt = time.Now()
 	m.workGraph.UpdatedAt = time.Now()
 
+	if bead.Status != previousStatus {
+		m.notifyStatusChangeLocked(StatusChangeEvent{
+			BeadID:    bead.ID,
+			ProjectID: bead.ProjectID,
+			OldStatus: previousStatus,
+			NewStatus: bead.Status,
+			Timestamp: bead.UpdatedAt,
+		})
+	}
+
 	if assignedUpdated && previousAssigned != bead.AssignedTo {
 		observability.Info("bead.assignment_updated", map[string]interface{}{
 			"bead_id":              bead.ID,
@@ -447,6 +665,67 @@ func (m *Manager) ClaimBead(beadID, agentID string) error {
 	return nil
 }
 
+// ClaimResult reports the outcome of a batched claim. Beads are claimed
+// independently, so callers can proceed with whatever was claimed.
+type ClaimResult struct {
+	Claimed  []string          `json:"claimed"`
+	Taken    map[string]string `json:"taken,omitempty"` // bead ID -> agent already holding it
+	NotFound []string          `json:"not_found,omitempty"`
+}
+
+// ClaimBeads claims a set of beads for an agent under a single lock, so no
+// other claim can interleave with the batch. Beads already held by the same
+// agent count as claimed; duplicate IDs are ignored.
+func (m *Manager) ClaimBeads(beadIDs []string, agentID string) (*ClaimResult, error) {
+	if agentID == "" {
+		return nil, fmt.Errorf("agent ID is required")
+	}
+
+	result := &ClaimResult{Claimed: []string{}, Taken: make(map[string]string)}
+	var claimed []*models.Bead
+	seen := make(map[string]bool, len(beadIDs))
+
+	m.mu.Lock()
+	now := time.Now()
+	for _, beadID := range beadIDs {
+		if seen[beadID] {
+			continue
+		}
+		seen[beadID] = true
+
+		bead, ok := m.beads[beadID]
+		switch {
+		case !ok:
+			result.NotFound = append(result.NotFound, beadID)
+		case bead.AssignedTo != "" && bead.AssignedTo != agentID:
+			result.Taken[beadID] = bead.AssignedTo
+		default:
+			bead.AssignedTo = agentID
+			bead.Status = models.BeadStatusInProgress
+			bead.UpdatedAt = now
+			result.Claimed = append(result.Claimed, beadID)
+			claimed = append(claimed, bead)
+		}
+	}
+	m.mu.Unlock()
+
+	observability.Info("bead.claim_batch", map[string]interface{}{
+		"agent_id":  agentID,
+		"requested": len(seen),
+		"claimed":   len(result.Claimed),
+		"taken":     len(result.Taken),
+		"not_found": len(result.NotFound),
+	})
+
+	for _, bead := range claimed {
+		if err := m.SaveBeadToFilesystem(bead, m.beadsPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to save bead to filesystem: %v\n", err)
+		}
+	}
+
+	return result, nil
+}
+
 // AddDependency adds a dependency between beads
 func (m *Manager) AddDependency(childID, parentID, relationship string) error {
 	m.mu.Lock()
@@ -462,6 +741,15 @@ func (m *Manager) AddDependency(childID, parentID, relationship string) error {
 		return fmt.Errorf("parent bead not found: %s", parentID)
 	}
 
+	// Reject edges that would close a loop; related edges are bidirectional by design
+	if relationship == "blocks" || relationship == "parent" {
+		if path := m.dependencyPath(parentID, childID, relationship); path != nil {
+			cycle := append([]string{childID}, path...)
+			return fmt.Errorf("adding %s dependency %s -> %s would create a cycle: %s",
+				relationship, childID, parentID, strings.Join(cycle, " -> "))
+		}
+	}
+
 	// Update bead relationships
 	switch relationship {
 	case "blocks":
@@ -487,7 +775,54 @@ func (m *Manager) AddDependency(childID, parentID, relationship string) error {
 		Relationship: relationship,
 	})
 	m.workGraph.UpdatedAt = time.Now()
+	m.recordEdgeChange(m.workGraph.UpdatedAt, childID, parentID)
+
+	return nil
+}
+
+// dependencyPath returns the chain of bead IDs leading from one bead to
+// another along existing edges of the given relationship (child -> parent
+// direction), or nil if to is unreachable. Callers must hold m.mu.
+func (m *Manager) dependencyPath(from, to, relationship string) []string {
+	adjacency := make(map[string][]string)
+	for _, edge := range m.workGraph.Edges {
+		if edge.Relationship == relationship {
+			adjacency[edge.From] = append(adjacency[edge.From], edge.To)
+		}
+	}
+	// Beads loaded from disk carry their links but have no work graph edges
+	for id, bead := range m.beads {
+		switch relationship {
+		case "blocks":
+			adjacency[id] = append(adjacency[id], bead.BlockedBy...)
+		case "parent":
+			if bead.Parent != "" {
+				adjacency[id] = append(adjacency[id], bead.Parent)
+			}
+		}
+	}
 
+	visited := make(map[string]bool)
+	var path []string
+	var visit func(id string) bool
+	visit = func(id string) bool {
+		path = append(path, id)
+		if id == to {
+			return true
+		}
+		visited[id] = true
+		for _, next := range adjacency[id] {
+			if !visited[next] && visit(next) {
+				return true
+			}
+		}
+		path = path[:len(path)-1]
+		return false
+	}
+
+	if visit(from) {
+		return path
+	}
 	return nil
 }
 
@@ -728,6 +1063,8 @@ func (m *Manager) LoadBeadsFromFilesystem(projectID, beadsPath string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	m.loadIdempotencyKeys(beadsPath)
+
 	if m.bdPath != "" {
 		if err := m.loadBeadsFromBD(projectID, beadsPath); err == nil {
 			return nil
@@ -807,11 +1144,13 @@ type bdIssue struct {
 
 func (m *Manager) loadBeadsFromBD(projectID, beadsPath string) error {
 	// Only load non-closed beads to avoid loading thousands of historical entries.
-	// Closed beads are not needed for dispatch, routing, or stuck detection.
+	// Closed beads are not needed for dispatch, routing, or stuck detection;
+	// dead-lettered ones are, since they wait on a decision to reopen them.
 	var allOutput []byte
 	dir := beadsRootDir(beadsPath)
 	failCount := 0
-	for _, status := range []string{"open", "in_progress", "blocked"} {
+	statuses := []string{"open", "in_progress", "blocked", string(models.BeadStatusDeadLetter)}
+	for _, status := range statuses {
 		cmd := m.buildBDCommand("list", "--json", "--limit", "0", "--allow-stale", "--status="+status)
 		if dir != "" {
 			cmd.Dir = dir
@@ -844,7 +1183,7 @@ func (m *Manager) loadBeadsFromBD(projectID, beadsPath string) error {
 	}
 
 	// If all bd commands failed, report error so YAML fallback can run.
-	if failCount == 3 {
+	if failCount == len(statuses) {
 		return fmt.Errorf("all bd list commands failed")
 	}
 
@@ -899,6 +1238,10 @@ func (m *Manager) loadBeadsFromBD(projectID, beadsPath string) error {
 
 // SaveBeadToFilesystem saves a bead to the filesystem
 func (m *Manager) SaveBeadToFilesystem(bead *models.Bead, beadsPath string) error {
+	// The file is named after the ID, which must not leave the beads directory
+	if bead.ID == "" || bead.ID == "." || bead.ID == ".." || strings.ContainsAny(bead.ID, `/\`) {
+		return fmt.Errorf("invalid bead ID %q", bead.ID)
+	}
 	beadsDir := filepath.Join(beadsPath, "beads")
 
 	// Ensure directory exists
@@ -1048,7 +1391,9 @@ func (m *Manager) SaveBeadToGit(ctx context.Context, bead *models.Bead, beadsPat
 	return fmt.Errorf("git push failed after %d retries due to conflicts", maxRetries)
 }
 
-// SyncFederation syncs with all enabled federation peers.
+// SyncFederation syncs with all enabled federation peers. In "http" sync
+// mode peers are other Loom instances reached over HTTP; otherwise the bd
+// CLI syncs them.
 func (m *Manager) SyncFederation(ctx context.Context, cfg *config.BeadsFederationConfig) error {
 	if cfg == nil || !cfg.Enabled {
 		return nil
@@ -1059,6 +1404,16 @@ func (m *Manager) SyncFederation(ctx context.Context, cfg *config.BeadsFederatio
 		if !peer.Enabled {
 			continue
 		}
+		if cfg.SyncMode == FederationSyncModeHTTP {
+			result, err := m.syncWithHTTPPeer(ctx, peer)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: federation sync with peer %s failed: %v\n", peer.Name, err)
+				lastErr = err
+			} else if len(result.Conflicts) > 0 {
+				log.Printf("[Federation] %d conflicting beads with peer %s", len(result.Conflicts), peer.Name)
+			}
+			continue
+		}
 		if err := m.syncWithPeer(ctx, peer, cfg.SyncStrategy); err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: federation sync with peer %s failed: %v\n", peer.Name, err)
 			lastErr = err
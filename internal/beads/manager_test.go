@@ -2,8 +2,10 @@ package beads
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -187,6 +189,230 @@ func TestManager_CreateBead_WithPrefix(t *testing.T) {
 	}
 }
 
+// TestManager_CreateBeads tests bulk bead creation
+func TestManager_CreateBeads(t *testing.T) {
+	manager := NewManager("")
+	beadsPath := t.TempDir()
+	manager.SetBeadsPath(beadsPath)
+	manager.SetProjectPrefix("proj-a", "pa")
+
+	// Existing bead advances the project sequence
+	if _, err := manager.CreateBead("Existing", "", models.BeadPriorityP2, "task", "proj-a"); err != nil {
+		t.Fatalf("CreateBead() error = %v", err)
+	}
+
+	beads, err := manager.CreateBeads([]BeadSpec{
+		{Title: "First", Priority: models.BeadPriorityP1, Type: "task", ProjectID: "proj-a", Tags: []string{"setup"}},
+		{Title: "Second", Priority: models.BeadPriorityP2, Type: "task", ProjectID: "proj-b"},
+		{Title: "Third", Priority: models.BeadPriorityP3, Type: "epic", ProjectID: "proj-a"},
+	})
+	if err != nil {
+		t.Fatalf("CreateBeads() error = %v", err)
+	}
+
+	wantIDs := []string{"pa-002", "bd-001", "pa-003"}
+	wantTitles := []string{"First", "Second", "Third"}
+	if len(beads) != len(wantIDs) {
+		t.Fatalf("len(beads) = %d, want %d", len(beads), len(wantIDs))
+	}
+	for i, bead := range beads {
+		if bead.ID != wantIDs[i] {
+			t.Errorf("beads[%d].ID = %q, want %q", i, bead.ID, wantIDs[i])
+		}
+		if bead.Title != wantTitles[i] {
+			t.Errorf("beads[%d].Title = %q, want %q", i, bead.Title, wantTitles[i])
+		}
+		if bead.Status != models.BeadStatusOpen {
+			t.Errorf("beads[%d].Status = %q, want open", i, bead.Status)
+		}
+		if _, err := manager.GetBead(bead.ID); err != nil {
+			t.Errorf("GetBead(%s) error = %v", bead.ID, err)
+		}
+	}
+	if len(beads[0].Tags) != 1 || beads[0].Tags[0] != "setup" {
+		t.Errorf("beads[0].Tags = %v, want [setup]", beads[0].Tags)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(beadsPath, "beads", "*.yaml"))
+	if len(files) != 4 {
+		t.Errorf("expected 4 bead files on disk, got %d", len(files))
+	}
+}
+
+// TestManager_CreateBeadIdempotent tests that a retried create returns the original bead
+func TestManager_CreateBeadIdempotent(t *testing.T) {
+	manager := NewManager("")
+	beadsPath := t.TempDir()
+	manager.SetBeadsPath(beadsPath)
+
+	first, created, err := manager.CreateBeadIdempotent("retry-1", "Fix bug", "", models.BeadPriorityP1, "task", "proj-a")
+	if err != nil || !created {
+		t.Fatalf("CreateBeadIdempotent() = %v, %v; want new bead", created, err)
+	}
+	second, created, err := manager.CreateBeadIdempotent("retry-1", "Fix bug", "", models.BeadPriorityP1, "task", "proj-a")
+	if err != nil {
+		t.Fatalf("CreateBeadIdempotent() retry error = %v", err)
+	}
+	if created || second.ID != first.ID {
+		t.Errorf("retry created = %v, id = %s; want existing %s", created, second.ID, first.ID)
+	}
+	if beads, _ := manager.ListBeads(nil); len(beads) != 1 {
+		t.Errorf("expected 1 bead, got %d", len(beads))
+	}
+
+	other, created, _ := manager.CreateBeadIdempotent("retry-2", "Fix bug", "", models.BeadPriorityP1, "task", "proj-a")
+	if !created || other.ID == first.ID {
+		t.Errorf("different key reused bead %s", other.ID)
+	}
+
+	// The mapping survives a reload into a fresh manager.
+	reloaded := NewManager("")
+	reloaded.SetBeadsPath(beadsPath)
+	if err := reloaded.LoadBeadsFromFilesystem("proj-a", beadsPath); err != nil {
+		t.Fatalf("LoadBeadsFromFilesystem() error = %v", err)
+	}
+	again, created, err := reloaded.CreateBeadIdempotent("retry-1", "Fix bug", "", models.BeadPriorityP1, "task", "proj-a")
+	if err != nil || created || again.ID != first.ID {
+		t.Errorf("after reload: id = %v, created = %v, err = %v; want existing %s", again, created, err, first.ID)
+	}
+}
+
+// TestManager_ArchiveClosedBeads tests that old closed beads move to the archive
+func TestManager_ArchiveClosedBeads(t *testing.T) {
+	manager := NewManager("")
+	beadsPath := t.TempDir()
+	manager.SetBeadsPath(beadsPath)
+
+	old, _ := manager.CreateBead("Old closed", "", models.BeadPriorityP2, "task", "proj-a")
+	recent, _ := manager.CreateBead("Recent closed", "", models.BeadPriorityP2, "task", "proj-a")
+	open, _ := manager.CreateBead("Still open", "", models.BeadPriorityP2, "task", "proj-a")
+	other, _ := manager.CreateBead("Other project", "", models.BeadPriorityP2, "task", "proj-b")
+	for _, b := range []*models.Bead{old, recent, other} {
+		if err := manager.UpdateBead(b.ID, map[string]interface{}{"status": models.BeadStatusClosed}); err != nil {
+			t.Fatalf("UpdateBead() error = %v", err)
+		}
+	}
+	if err := manager.AddDependency(open.ID, old.ID, "related"); err != nil {
+		t.Fatalf("AddDependency() error = %v", err)
+	}
+	past := time.Now().Add(-48 * time.Hour)
+	old.ClosedAt = &past
+
+	n, err := manager.ArchiveClosedBeads("proj-a", time.Now().Add(-24*time.Hour))
+	if err != nil {
+		t.Fatalf("ArchiveClosedBeads() error = %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("archived %d beads, want 1", n)
+	}
+	if _, err := manager.GetBead(old.ID); err == nil {
+		t.Error("archived bead still active")
+	}
+	for _, b := range []*models.Bead{recent, open, other} {
+		if _, err := manager.GetBead(b.ID); err != nil {
+			t.Errorf("GetBead(%s) error = %v", b.ID, err)
+		}
+	}
+	graph, _ := manager.GetWorkGraph("")
+	for _, e := range graph.Edges {
+		if e.From == old.ID || e.To == old.ID {
+			t.Errorf("edge %+v to archived bead kept", e)
+		}
+	}
+	if files, _ := filepath.Glob(filepath.Join(beadsPath, "beads", old.ID+"-*.yaml")); len(files) != 0 {
+		t.Errorf("archived bead file not removed: %v", files)
+	}
+
+	// A second run appends to the same archive.
+	if n, err := manager.ArchiveClosedBeads("", time.Now().Add(time.Hour)); err != nil || n != 2 {
+		t.Fatalf("ArchiveClosedBeads() = %d, %v; want 2", n, err)
+	}
+
+	// Archived beads reload on demand, also from a fresh manager.
+	for _, m := range []*Manager{manager, NewManager("")} {
+		m.SetBeadsPath(beadsPath)
+		for _, b := range []*models.Bead{old, recent, other} {
+			got, err := m.LoadArchivedBead(b.ID)
+			if err != nil {
+				t.Fatalf("LoadArchivedBead(%s) error = %v", b.ID, err)
+			}
+			if got.Title != b.Title || got.Status != models.BeadStatusClosed {
+				t.Errorf("LoadArchivedBead(%s) = %+v", b.ID, got)
+			}
+		}
+	}
+	if _, err := manager.LoadArchivedBead(open.ID); err == nil {
+		t.Error("LoadArchivedBead() found a bead that was never archived")
+	}
+}
+
+// TestManager_CreateBeads_InvalidSpecRollsBack tests that an invalid spec creates nothing
+func TestManager_CreateBeads_InvalidSpecRollsBack(t *testing.T) {
+	manager := NewManager("")
+	beadsPath := t.TempDir()
+	manager.SetBeadsPath(beadsPath)
+
+	_, err := manager.CreateBeads([]BeadSpec{
+		{Title: "Valid", Priority: models.BeadPriorityP2, Type: "task", ProjectID: "proj"},
+		{Title: "   ", Priority: models.BeadPriorityP2, Type: "task", ProjectID: "proj"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "bead spec 1") {
+		t.Fatalf("CreateBeads() error = %v, want error for spec 1", err)
+	}
+
+	_, err = manager.CreateBeads([]BeadSpec{
+		{Title: "Bad priority", Priority: models.BeadPriority(9), Type: "task", ProjectID: "proj"},
+	})
+	if err == nil {
+		t.Fatal("CreateBeads() expected error for invalid priority")
+	}
+
+	all, _ := manager.ListBeads(nil)
+	if len(all) != 0 {
+		t.Errorf("expected no beads after failed batch, got %d", len(all))
+	}
+	files, _ := filepath.Glob(filepath.Join(beadsPath, "beads", "*.yaml"))
+	if len(files) != 0 {
+		t.Errorf("expected no bead files after failed batch, got %d", len(files))
+	}
+
+	// The sequence was not consumed
+	bead, err := manager.CreateBead("After", "", models.BeadPriorityP2, "task", "proj")
+	if err != nil {
+		t.Fatalf("CreateBead() error = %v", err)
+	}
+	if bead.ID != "bd-001" {
+		t.Errorf("bead.ID = %q, want bd-001", bead.ID)
+	}
+}
+
+// TestManager_CreateBeads_PersistFailureRollsBack tests rollback when a write fails
+func TestManager_CreateBeads_PersistFailureRollsBack(t *testing.T) {
+	manager := NewManager("")
+	// A regular file where the beads directory should be makes writes fail
+	beadsPath := filepath.Join(t.TempDir(), "beads-root")
+	if err := os.WriteFile(beadsPath, []byte("not a dir"), 0644); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+	manager.SetBeadsPath(beadsPath)
+
+	_, err := manager.CreateBeads([]BeadSpec{
+		{Title: "One", Priority: models.BeadPriorityP2, Type: "task", ProjectID: "proj"},
+		{Title: "Two", Priority: models.BeadPriorityP2, Type: "task", ProjectID: "proj"},
+	})
+	if err == nil {
+		t.Fatal("CreateBeads() expected persistence error")
+	}
+
+	all, _ := manager.ListBeads(nil)
+	if len(all) != 0 {
+		t.Errorf("expected no beads after failed batch, got %d", len(all))
+	}
+	if len(manager.workGraph.Beads) != 0 {
+		t.Errorf("expected empty work graph after failed batch, got %d", len(manager.workGraph.Beads))
+	}
+}
+
 // TestManager_GetBead tests getting a bead by ID
 func TestManager_GetBead(t *testing.T) {
 	manager := NewManager("")
@@ -272,6 +498,95 @@ func TestManager_ListBeads(t *testing.T) {
 	_ = bead3 // Silence unused warning
 }
 
+// TestManager_SearchBeads tests full-text search ranking and filters
+func TestManager_SearchBeads(t *testing.T) {
+	manager := NewManager("")
+	manager.SetBeadsPath(t.TempDir())
+
+	titleHit, _ := manager.CreateBead("Fix login timeout", "Users are logged out", models.BeadPriorityP2, "task", "web")
+	descHit, _ := manager.CreateBead("Session cleanup", "Related to the LOGIN timeout bug", models.BeadPriorityP1, "task", "web")
+	_, _ = manager.CreateBead("Update docs", "Nothing relevant", models.BeadPriorityP2, "task", "web")
+	otherProject, _ := manager.CreateBead("Login page redesign", "", models.BeadPriorityP2, "task", "mobile")
+
+	results, err := manager.SearchBeads("Login", map[string]interface{}{"project_id": "web"})
+	if err != nil {
+		t.Fatalf("SearchBeads() error = %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("len(results) = %d, want 2", len(results))
+	}
+	// Title hit ranks first even though the description hit has higher priority
+	if results[0].ID != titleHit.ID || results[1].ID != descHit.ID {
+		t.Errorf("results = [%s, %s], want [%s, %s]", results[0].ID, results[1].ID, titleHit.ID, descHit.ID)
+	}
+	for _, b := range results {
+		if b.ID == otherProject.ID {
+			t.Error("project filter not honored")
+		}
+	}
+}
+
+// TestManager_SearchBeads_MultiTerm tests that every term must match
+func TestManager_SearchBeads_MultiTerm(t *testing.T) {
+	manager := NewManager("")
+	manager.SetBeadsPath(t.TempDir())
+
+	both, _ := manager.CreateBead("Database migration", "Add index for timeout queries", models.BeadPriorityP2, "task", "p")
+	_, _ = manager.CreateBead("Database backup", "Nightly job", models.BeadPriorityP2, "task", "p")
+	_, _ = manager.CreateBead("Request timeout", "HTTP layer", models.BeadPriorityP2, "task", "p")
+
+	results, err := manager.SearchBeads("  database   TIMEOUT ", nil)
+	if err != nil {
+		t.Fatalf("SearchBeads() error = %v", err)
+	}
+	if len(results) != 1 || results[0].ID != both.ID {
+		t.Errorf("results = %v, want only %s", beadIDs(results), both.ID)
+	}
+
+	results, _ = manager.SearchBeads("", nil)
+	if len(results) != 3 {
+		t.Errorf("empty query returned %d beads, want 3", len(results))
+	}
+}
+
+// TestManager_SearchBeads_Tags tests tag matches and their ranking
+func TestManager_SearchBeads_Tags(t *testing.T) {
+	manager := NewManager("")
+	manager.SetBeadsPath(t.TempDir())
+
+	beads, err := manager.CreateBeads([]BeadSpec{
+		{Title: "Rotate keys", Description: "security review follow-up", Priority: models.BeadPriorityP2, Type: "task", ProjectID: "p"},
+		{Title: "Audit logging", Priority: models.BeadPriorityP2, Type: "task", ProjectID: "p", Tags: []string{"Security", "compliance"}},
+		{Title: "Security headers", Priority: models.BeadPriorityP2, Type: "task", ProjectID: "p"},
+		{Title: "Refactor", Priority: models.BeadPriorityP2, Type: "task", ProjectID: "p", Tags: []string{"cleanup"}},
+	})
+	if err != nil {
+		t.Fatalf("CreateBeads() error = %v", err)
+	}
+
+	results, err := manager.SearchBeads("security", nil)
+	if err != nil {
+		t.Fatalf("SearchBeads() error = %v", err)
+	}
+	want := []string{beads[2].ID, beads[1].ID, beads[0].ID} // title, tag, description
+	if got := beadIDs(results); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("results = %v, want %v", got, want)
+	}
+
+	results, _ = manager.SearchBeads("compliance", nil)
+	if len(results) != 1 || results[0].ID != beads[1].ID {
+		t.Errorf("tag search results = %v, want [%s]", beadIDs(results), beads[1].ID)
+	}
+}
+
+func beadIDs(beads []*models.Bead) []string {
+	ids := make([]string, len(beads))
+	for i, b := range beads {
+		ids[i] = b.ID
+	}
+	return ids
+}
+
 // TestManager_UpdateBead tests updating a bead
 func TestManager_UpdateBead(t *testing.T) {
 	manager := NewManager("")
@@ -408,6 +723,75 @@ func TestManager_ClaimBead_NotFound(t *testing.T) {
 	}
 }
 
+// TestManager_ClaimBeads tests partial success of a batched claim
+func TestManager_ClaimBeads(t *testing.T) {
+	manager := NewManager("")
+
+	free, _ := manager.CreateBead("Free", "Desc", models.BeadPriorityP2, "task", "project1")
+	mine, _ := manager.CreateBead("Mine", "Desc", models.BeadPriorityP2, "task", "project1")
+	taken, _ := manager.CreateBead("Taken", "Desc", models.BeadPriorityP2, "task", "project1")
+	manager.ClaimBead(mine.ID, "agent-1")
+	manager.ClaimBead(taken.ID, "agent-2")
+
+	result, err := manager.ClaimBeads([]string{free.ID, mine.ID, taken.ID, "nonexistent", free.ID}, "agent-1")
+	if err != nil {
+		t.Fatalf("ClaimBeads() error = %v", err)
+	}
+	if len(result.Claimed) != 2 || result.Claimed[0] != free.ID || result.Claimed[1] != mine.ID {
+		t.Errorf("Claimed = %v, want [%s %s]", result.Claimed, free.ID, mine.ID)
+	}
+	if result.Taken[taken.ID] != "agent-2" || len(result.Taken) != 1 {
+		t.Errorf("Taken = %v, want %s held by agent-2", result.Taken, taken.ID)
+	}
+	if len(result.NotFound) != 1 || result.NotFound[0] != "nonexistent" {
+		t.Errorf("NotFound = %v, want [nonexistent]", result.NotFound)
+	}
+
+	claimed, _ := manager.GetBead(free.ID)
+	if claimed.AssignedTo != "agent-1" || claimed.Status != models.BeadStatusInProgress {
+		t.Errorf("free bead not claimed: assigned=%q status=%q", claimed.AssignedTo, claimed.Status)
+	}
+
+	if _, err := manager.ClaimBeads([]string{free.ID}, ""); err == nil {
+		t.Error("Expected error for empty agent ID")
+	}
+}
+
+// TestManager_ClaimBeads_Concurrent tests that racing batches never share a bead
+func TestManager_ClaimBeads_Concurrent(t *testing.T) {
+	manager := NewManager("")
+
+	var ids []string
+	for i := 0; i < 20; i++ {
+		b, _ := manager.CreateBead("Bead", "Desc", models.BeadPriorityP2, "task", "project1")
+		ids = append(ids, b.ID)
+	}
+
+	results := make([]*ClaimResult, 4)
+	done := make(chan int)
+	for i := range results {
+		go func(i int) {
+			results[i], _ = manager.ClaimBeads(ids, fmt.Sprintf("agent-%d", i))
+			done <- i
+		}(i)
+	}
+	for range results {
+		<-done
+	}
+
+	owners := make(map[string]int)
+	for _, r := range results {
+		for _, id := range r.Claimed {
+			owners[id]++
+		}
+	}
+	for _, id := range ids {
+		if owners[id] != 1 {
+			t.Errorf("bead %s claimed %d times, want exactly once", id, owners[id])
+		}
+	}
+}
+
 // TestManager_AddDependency tests adding dependencies between beads
 func TestManager_AddDependency(t *testing.T) {
 	manager := NewManager("")
@@ -438,6 +822,85 @@ func TestManager_AddDependency(t *testing.T) {
 	}
 }
 
+// TestManager_AddDependency_SelfCycle tests that a bead cannot depend on itself
+func TestManager_AddDependency_SelfCycle(t *testing.T) {
+	manager := NewManager("")
+	manager.SetBeadsPath(t.TempDir())
+
+	bead, _ := manager.CreateBead("Bead", "Desc", models.BeadPriorityP2, "task", "project1")
+
+	for _, rel := range []string{"blocks", "parent"} {
+		err := manager.AddDependency(bead.ID, bead.ID, rel)
+		if err == nil || !strings.Contains(err.Error(), "cycle") {
+			t.Errorf("AddDependency(%s) self-cycle error = %v, want cycle error", rel, err)
+		}
+	}
+
+	b, _ := manager.GetBead(bead.ID)
+	if len(b.BlockedBy) != 0 || b.Parent != "" {
+		t.Errorf("rejected dependency was applied: BlockedBy=%v Parent=%q", b.BlockedBy, b.Parent)
+	}
+	if len(manager.workGraph.Edges) != 0 {
+		t.Errorf("workGraph.Edges length = %d, want 0", len(manager.workGraph.Edges))
+	}
+}
+
+// TestManager_AddDependency_ThreeNodeCycle tests that closing a loop is rejected
+func TestManager_AddDependency_ThreeNodeCycle(t *testing.T) {
+	manager := NewManager("")
+	manager.SetBeadsPath(t.TempDir())
+
+	a, _ := manager.CreateBead("A", "Desc", models.BeadPriorityP2, "task", "project1")
+	b, _ := manager.CreateBead("B", "Desc", models.BeadPriorityP2, "task", "project1")
+	c, _ := manager.CreateBead("C", "Desc", models.BeadPriorityP2, "task", "project1")
+
+	// a blocked by b, b blocked by c
+	if err := manager.AddDependency(a.ID, b.ID, "blocks"); err != nil {
+		t.Fatalf("AddDependency(a, b) error = %v", err)
+	}
+	if err := manager.AddDependency(b.ID, c.ID, "blocks"); err != nil {
+		t.Fatalf("AddDependency(b, c) error = %v", err)
+	}
+
+	// c blocked by a would close the loop
+	err := manager.AddDependency(c.ID, a.ID, "blocks")
+	if err == nil {
+		t.Fatal("expected cycle error, got nil")
+	}
+	wantPath := strings.Join([]string{c.ID, a.ID, b.ID, c.ID}, " -> ")
+	if !strings.Contains(err.Error(), wantPath) {
+		t.Errorf("error = %q, want cycle path %q", err.Error(), wantPath)
+	}
+
+	cBead, _ := manager.GetBead(c.ID)
+	if len(cBead.BlockedBy) != 0 {
+		t.Errorf("c.BlockedBy = %v, want empty", cBead.BlockedBy)
+	}
+
+	// Related edges are exempt, and other relationship types are tracked separately
+	if err := manager.AddDependency(c.ID, a.ID, "related"); err != nil {
+		t.Errorf("related edge should not be cycle-checked: %v", err)
+	}
+	if err := manager.AddDependency(c.ID, a.ID, "parent"); err != nil {
+		t.Errorf("parent edge should not conflict with blocks edges: %v", err)
+	}
+}
+
+// TestManager_AddDependency_CycleThroughLoadedBeads tests cycle detection for
+// links that exist on beads but not as work graph edges
+func TestManager_AddDependency_CycleThroughLoadedBeads(t *testing.T) {
+	manager := NewManager("")
+	manager.SetBeadsPath(t.TempDir())
+
+	a, _ := manager.CreateBead("A", "Desc", models.BeadPriorityP2, "task", "project1")
+	b, _ := manager.CreateBead("B", "Desc", models.BeadPriorityP2, "task", "project1")
+	a.Parent = b.ID
+
+	if err := manager.AddDependency(b.ID, a.ID, "parent"); err == nil {
+		t.Error("expected cycle error for parent loop, got nil")
+	}
+}
+
 // TestManager_AddDependency_Parent tests parent-child relationship
 func TestManager_AddDependency_Parent(t *testing.T) {
 	manager := NewManager("")
@@ -609,6 +1072,110 @@ func TestManager_GetWorkGraph(t *testing.T) {
 	}
 }
 
+func TestManager_GetWorkGraphSince(t *testing.T) {
+	manager := NewManager("")
+	manager.SetBeadsPath(t.TempDir())
+
+	stale, _ := manager.CreateBead("Stale", "", models.BeadPriorityP2, "task", "proj-a")
+	closed, _ := manager.CreateBead("Closed", "", models.BeadPriorityP2, "task", "proj-a")
+	manager.CreateBead("Other project", "", models.BeadPriorityP2, "task", "proj-b")
+	if err := manager.UpdateBead(closed.ID, map[string]interface{}{"status": models.BeadStatusClosed}); err != nil {
+		t.Fatalf("UpdateBead() error = %v", err)
+	}
+
+	since := time.Now()
+	time.Sleep(time.Millisecond)
+	fresh, _ := manager.CreateBead("Fresh", "", models.BeadPriorityP2, "task", "proj-a")
+	if err := manager.AddDependency(fresh.ID, stale.ID, "related"); err != nil {
+		t.Fatalf("AddDependency() error = %v", err)
+	}
+	if n, err := manager.ArchiveClosedBeads("", time.Now().Add(time.Hour)); err != nil || n != 1 {
+		t.Fatalf("ArchiveClosedBeads() = %d, %v; want 1", n, err)
+	}
+
+	delta, err := manager.GetWorkGraphSince("proj-a", since)
+	if err != nil {
+		t.Fatalf("GetWorkGraphSince() error = %v", err)
+	}
+	if delta.Full {
+		t.Error("delta within the deletion history should not be full")
+	}
+	if len(delta.Beads) != 2 || delta.Beads[fresh.ID] == nil || delta.Beads[stale.ID] == nil {
+		t.Errorf("delta beads = %v, want %s and its new dependency %s", delta.Beads, fresh.ID, stale.ID)
+	}
+	if len(delta.Edges) != 1 {
+		t.Errorf("delta edges = %v, want the edge to the fresh bead", delta.Edges)
+	}
+	if len(delta.DeletedBeadIDs) != 1 || delta.DeletedBeadIDs[0] != closed.ID {
+		t.Errorf("DeletedBeadIDs = %v, want [%s]", delta.DeletedBeadIDs, closed.ID)
+	}
+
+	if delta, _ := manager.GetWorkGraphSince("proj-b", since); len(delta.Beads) != 0 || len(delta.DeletedBeadIDs) != 0 {
+		t.Errorf("proj-b delta = %+v, want nothing changed", delta)
+	}
+
+	full, _ := manager.GetWorkGraphSince("proj-a", since.Add(-48*time.Hour))
+	if !full.Full || len(full.Beads) != 2 || len(full.DeletedBeadIDs) != 0 {
+		t.Errorf("old cutoff = %+v, want the full proj-a graph", full)
+	}
+}
+
+func TestManager_ExportProjectMarkdown(t *testing.T) {
+	manager := NewManager("")
+
+	blocker, _ := manager.CreateBead("Design the API", "", models.BeadPriorityP1, "task", "proj-a")
+	blocked, _ := manager.CreateBead("Build the client", "", models.BeadPriorityP0, "task", "proj-a")
+	done, _ := manager.CreateBead("Write the spec", "", models.BeadPriorityP2, "task", "proj-a")
+	manager.CreateBead("Other project", "", models.BeadPriorityP2, "task", "proj-b")
+	if err := manager.AddDependency(blocked.ID, blocker.ID, "blocks"); err != nil {
+		t.Fatalf("AddDependency() error = %v", err)
+	}
+	if err := manager.UpdateBead(done.ID, map[string]interface{}{"status": models.BeadStatusClosed}); err != nil {
+		t.Fatalf("UpdateBead() error = %v", err)
+	}
+	stuck, _ := manager.CreateBead("Keeps failing", "", models.BeadPriorityP2, "task", "proj-a")
+	if err := manager.UpdateBead(stuck.ID, map[string]interface{}{"status": models.BeadStatusDeadLetter}); err != nil {
+		t.Fatalf("UpdateBead() error = %v", err)
+	}
+	// Statuses loom does not know, such as bd's deferred, are still listed
+	later, _ := manager.CreateBead("Later", "", models.BeadPriorityP3, "task", "proj-a")
+	if err := manager.UpdateBead(later.ID, map[string]interface{}{"status": models.BeadStatus("deferred")}); err != nil {
+		t.Fatalf("UpdateBead() error = %v", err)
+	}
+
+	report, err := manager.ExportProjectMarkdown("proj-a")
+	if err != nil {
+		t.Fatalf("ExportProjectMarkdown() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"# Project proj-a",
+		"**5 beads:** 0 in progress · 0 blocked · 1 dead letter · 2 open · 1 closed",
+		"## Dead Letter (1)\n\n- `P2` **Keeps failing**",
+		"## deferred (1)\n\n- `P3` **Later**",
+		"- `P0` **Build the client** `" + blocked.ID + "`",
+		"  - Blocked by `" + blocker.ID + "` Design the API _(open)_",
+		"<summary>Show closed beads</summary>",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report missing %q:\n%s", want, report)
+		}
+	}
+	if strings.Index(report, blocked.ID) > strings.Index(report, "Design the API") {
+		t.Error("P0 bead should be listed before P1")
+	}
+	if strings.Index(report, "Write the spec") < strings.Index(report, "## Closed (1)") {
+		t.Error("closed bead listed outside the closed section")
+	}
+	if strings.Contains(report, "Other project") {
+		t.Error("report includes another project's bead")
+	}
+
+	if _, err := manager.ExportProjectMarkdown(""); err == nil {
+		t.Error("ExportProjectMarkdown(\"\") should fail")
+	}
+}
+
 // Helper function tests
 
 // TestSanitizeFilename tests filename sanitization
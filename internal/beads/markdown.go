@@ -0,0 +1,142 @@
+package beads
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jordanhubbard/loom/pkg/models"
+)
+
+// markdownSection is a group of beads with one status in a Markdown report.
+type markdownSection struct {
+	status models.BeadStatus
+	title  string
+}
+
+// markdownSections is the order of the status sections in
+// ExportProjectMarkdown and of the counts in its header. Closed beads get
+// their own collapsed section; beads with any other status follow these
+// sections under their status name.
+var markdownSections = []markdownSection{
+	{models.BeadStatusInProgress, "In Progress"},
+	{models.BeadStatusBlocked, "Blocked"},
+	{models.BeadStatusDeadLetter, "Dead Letter"},
+	{models.BeadStatusOpen, "Open"},
+}
+
+// ExportProjectMarkdown renders a project's beads as a Markdown report: a
+// summary header, then the beads grouped by status with priority badges and
+// their blockers and children as nested lists. Closed beads are collapsed
+// into a section of their own.
+func (m *Manager) ExportProjectMarkdown(projectID string) (string, error) {
+	if projectID == "" {
+		return "", fmt.Errorf("project ID is required")
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	byStatus := make(map[models.BeadStatus][]*models.Bead)
+	total := 0
+	for _, bead := range m.beads {
+		if bead.ProjectID != projectID {
+			continue
+		}
+		byStatus[bead.Status] = append(byStatus[bead.Status], bead)
+		total++
+	}
+	for _, beads := range byStatus {
+		sort.Slice(beads, func(i, j int) bool {
+			if beads[i].Priority != beads[j].Priority {
+				return beads[i].Priority < beads[j].Priority
+			}
+			return beads[i].ID < beads[j].ID
+		})
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Project %s\n\n", projectID)
+	fmt.Fprintf(&b, "_Exported %s_\n\n", time.Now().UTC().Format("2006-01-02 15:04 MST"))
+	fmt.Fprintf(&b, "**%d beads:**", total)
+	for i, section := range markdownSections {
+		if i > 0 {
+			b.WriteString(" ·")
+		}
+		fmt.Fprintf(&b, " %d %s", len(byStatus[section.status]), strings.ToLower(section.title))
+	}
+	fmt.Fprintf(&b, " · %d closed\n", len(byStatus[models.BeadStatusClosed]))
+
+	sections := append([]markdownSection{}, markdownSections...)
+	known := map[models.BeadStatus]bool{models.BeadStatusClosed: true}
+	for _, section := range markdownSections {
+		known[section.status] = true
+	}
+	var other []models.BeadStatus
+	for status := range byStatus {
+		if !known[status] {
+			other = append(other, status)
+		}
+	}
+	sort.Slice(other, func(i, j int) bool { return other[i] < other[j] })
+	for _, status := range other {
+		title := string(status)
+		if title == "" {
+			title = "No Status"
+		}
+		sections = append(sections, markdownSection{status, title})
+	}
+
+	for _, section := range sections {
+		beads := byStatus[section.status]
+		if len(beads) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n## %s (%d)\n\n", section.title, len(beads))
+		for _, bead := range beads {
+			m.writeMarkdownBead(&b, bead)
+		}
+	}
+
+	if closed := byStatus[models.BeadStatusClosed]; len(closed) > 0 {
+		fmt.Fprintf(&b, "\n## Closed (%d)\n\n<details>\n<summary>Show closed beads</summary>\n\n", len(closed))
+		for _, bead := range closed {
+			m.writeMarkdownBead(&b, bead)
+		}
+		b.WriteString("\n</details>\n")
+	}
+
+	return b.String(), nil
+}
+
+// writeMarkdownBead writes one bead as a list item. Callers must hold m.mu.
+func (m *Manager) writeMarkdownBead(b *strings.Builder, bead *models.Bead) {
+	fmt.Fprintf(b, "- `P%d` **%s** `%s`", bead.Priority, markdownText(bead.Title), bead.ID)
+	if bead.AssignedTo != "" {
+		fmt.Fprintf(b, " — %s", bead.AssignedTo)
+	}
+	b.WriteString("\n")
+
+	for _, id := range bead.BlockedBy {
+		fmt.Fprintf(b, "  - Blocked by %s\n", m.markdownRef(id))
+	}
+	for _, id := range bead.Children {
+		fmt.Fprintf(b, "  - Subtask %s\n", m.markdownRef(id))
+	}
+}
+
+// markdownRef describes a related bead by ID, title and status, or by ID
+// alone when it is not loaded. Callers must hold m.mu.
+func (m *Manager) markdownRef(id string) string {
+	bead, ok := m.beads[id]
+	if !ok {
+		return fmt.Sprintf("`%s`", id)
+	}
+	return fmt.Sprintf("`%s` %s _(%s)_", id, markdownText(bead.Title), bead.Status)
+}
+
+// markdownText keeps a title on one line.
+func markdownText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
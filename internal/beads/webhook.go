@@ -0,0 +1,133 @@
+package beads
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/jordanhubbard/loom/pkg/models"
+)
+
+const (
+	// StatusWebhookEvent is sent in the X-Loom-Event header.
+	StatusWebhookEvent = "bead.status_changed"
+	// StatusWebhookSignatureHeader carries "sha256=<hex HMAC of the body>",
+	// the same scheme GitHub uses for X-Hub-Signature-256.
+	StatusWebhookSignatureHeader = "X-Loom-Signature-256"
+
+	defaultWebhookAttempts = 5
+	defaultWebhookBackoff  = time.Second
+)
+
+var webhookClient = &http.Client{Timeout: 10 * time.Second}
+
+// StatusChangeEvent is the JSON payload of a bead status webhook.
+type StatusChangeEvent struct {
+	BeadID    string            `json:"bead_id"`
+	ProjectID string            `json:"project_id"`
+	OldStatus models.BeadStatus `json:"old_status"`
+	NewStatus models.BeadStatus `json:"new_status"`
+	Timestamp time.Time         `json:"timestamp"`
+}
+
+// statusWebhook is a project's outbound webhook target.
+type statusWebhook struct {
+	url    string
+	secret string
+}
+
+// SetStatusWebhook sends a signed POST to url whenever a bead in the project
+// changes status. The body is signed with HMAC-SHA256 using secret when it is
+// set. An empty url removes the webhook.
+func (m *Manager) SetStatusWebhook(projectID, url, secret string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if url == "" {
+		delete(m.webhooks, projectID)
+		return
+	}
+	if m.webhooks == nil {
+		m.webhooks = make(map[string]statusWebhook)
+	}
+	m.webhooks[projectID] = statusWebhook{url: url, secret: secret}
+}
+
+// notifyStatusChangeLocked delivers the event in the background if the
+// project has a webhook. Callers must hold m.mu.
+func (m *Manager) notifyStatusChangeLocked(event StatusChangeEvent) {
+	hook, ok := m.webhooks[event.ProjectID]
+	if !ok {
+		return
+	}
+	go deliverStatusWebhook(webhookClient, hook, event, defaultWebhookAttempts, defaultWebhookBackoff)
+}
+
+// deliverStatusWebhook posts the event, retrying network errors, 429s and
+// 5xx responses with exponential backoff.
+func deliverStatusWebhook(client *http.Client, hook statusWebhook, event StatusChangeEvent, attempts int, backoff time.Duration) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("[Beads] Failed to encode status webhook for %s: %v", event.BeadID, err)
+		return
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoff << (attempt - 1))
+		}
+		retry, err := postStatusWebhook(client, hook, body)
+		if err == nil {
+			return
+		}
+		lastErr = err
+		if !retry {
+			break
+		}
+	}
+	log.Printf("[Beads] Status webhook for bead %s to %s failed: %v", event.BeadID, hook.url, lastErr)
+}
+
+// postStatusWebhook makes one delivery attempt and reports whether a failure
+// is worth retrying.
+func postStatusWebhook(client *http.Client, hook statusWebhook, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, hook.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Loom-Event", StatusWebhookEvent)
+	if hook.secret != "" {
+		req.Header.Set(StatusWebhookSignatureHeader, "sha256="+signWebhookPayload(body, hook.secret))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return true, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	default:
+		return false, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+}
+
+// signWebhookPayload returns the hex HMAC-SHA256 of payload.
+func signWebhookPayload(payload []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
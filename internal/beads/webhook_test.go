@@ -0,0 +1,102 @@
+package beads
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jordanhubbard/loom/pkg/models"
+)
+
+func TestManager_StatusWebhook(t *testing.T) {
+	release := make(chan struct{})
+	received := make(chan *http.Request, 1)
+	bodies := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		body, _ := io.ReadAll(r.Body)
+		received <- r
+		bodies <- body
+	}))
+	defer server.Close()
+
+	manager := NewManager("")
+	manager.SetBeadsPath(t.TempDir())
+	manager.SetStatusWebhook("proj-a", server.URL, "s3cret")
+	bead, _ := manager.CreateBead("Task", "", models.BeadPriorityP2, "task", "proj-a")
+
+	// The update must not wait for the (blocked) delivery.
+	done := make(chan error, 1)
+	go func() {
+		done <- manager.UpdateBead(bead.ID, map[string]interface{}{"status": models.BeadStatusInProgress})
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("UpdateBead() error = %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("UpdateBead blocked on webhook delivery")
+	}
+	close(release)
+
+	var r *http.Request
+	var body []byte
+	select {
+	case r = <-received:
+		body = <-bodies
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook not delivered")
+	}
+	if got := r.Header.Get(StatusWebhookSignatureHeader); got != "sha256="+signWebhookPayload(body, "s3cret") {
+		t.Errorf("signature = %q does not match body", got)
+	}
+	var event StatusChangeEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		t.Fatalf("payload: %v", err)
+	}
+	if event.BeadID != bead.ID || event.ProjectID != "proj-a" || event.OldStatus != models.BeadStatusOpen ||
+		event.NewStatus != models.BeadStatusInProgress || event.Timestamp.IsZero() {
+		t.Errorf("event = %+v", event)
+	}
+
+	// Updates that don't change status send nothing.
+	_ = manager.UpdateBead(bead.ID, map[string]interface{}{"title": "Renamed"})
+	select {
+	case <-received:
+		t.Error("webhook sent without a status change")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestDeliverStatusWebhook_Retries(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	event := StatusChangeEvent{BeadID: "bd-001", NewStatus: models.BeadStatusClosed}
+	deliverStatusWebhook(server.Client(), statusWebhook{url: server.URL}, event, 5, time.Millisecond)
+	if got := calls.Load(); got != 3 {
+		t.Errorf("attempts = %d, want 3 (two 503s then success)", got)
+	}
+
+	// Client errors are not retried.
+	calls.Store(0)
+	rejecting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer rejecting.Close()
+	deliverStatusWebhook(rejecting.Client(), statusWebhook{url: rejecting.URL}, event, 5, time.Millisecond)
+	if got := calls.Load(); got != 1 {
+		t.Errorf("attempts = %d, want 1 for a 400", got)
+	}
+}
@@ -0,0 +1,101 @@
+package beads
+
+import (
+	"sort"
+	"time"
+
+	"github.com/jordanhubbard/loom/pkg/models"
+)
+
+// deletionRetention is how long removed beads are remembered for
+// GetWorkGraphSince. Older cutoffs get the full graph instead.
+const deletionRetention = 24 * time.Hour
+
+// deletedBead records a bead removed from the work graph.
+type deletedBead struct {
+	projectID string
+	at        time.Time
+}
+
+// recordDeleted remembers that a bead left the work graph and forgets
+// deletions older than deletionRetention. Callers must hold m.mu.
+func (m *Manager) recordDeleted(bead *models.Bead, now time.Time) {
+	if m.deletedBeads == nil {
+		m.deletedBeads = make(map[string]deletedBead)
+	}
+	m.deletedBeads[bead.ID] = deletedBead{projectID: bead.ProjectID, at: now}
+	delete(m.edgesChanged, bead.ID)
+
+	cutoff := now.Add(-deletionRetention)
+	if !m.deletionsFrom.Before(cutoff) {
+		return
+	}
+	for id, d := range m.deletedBeads {
+		if d.at.Before(cutoff) {
+			delete(m.deletedBeads, id)
+		}
+	}
+	m.deletionsFrom = cutoff
+}
+
+// recordEdgeChange notes that the beads gained an edge. Their UpdatedAt is
+// left alone so that dependency changes do not reset priority aging.
+// Callers must hold m.mu.
+func (m *Manager) recordEdgeChange(now time.Time, ids ...string) {
+	if m.edgesChanged == nil {
+		m.edgesChanged = make(map[string]time.Time)
+	}
+	for _, id := range ids {
+		m.edgesChanged[id] = now
+	}
+}
+
+// GetWorkGraphSince returns the part of a project's work graph that changed
+// after since: beads updated or given new edges after it, edges with such a
+// bead at either end, and the IDs of beads removed since. An empty projectID covers all
+// projects. When since predates the deletion history, the whole graph is
+// returned with Full set.
+func (m *Manager) GetWorkGraphSince(projectID string, since time.Time) (*models.WorkGraphDelta, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	delta := &models.WorkGraphDelta{
+		Beads:          make(map[string]*models.Bead),
+		Edges:          []models.Edge{},
+		DeletedBeadIDs: []string{},
+		Since:          since,
+		UpdatedAt:      m.workGraph.UpdatedAt,
+		Full:           since.Before(m.deletionsFrom),
+	}
+
+	inProject := func(id string) bool {
+		bead, ok := m.workGraph.Beads[id]
+		return ok && (projectID == "" || bead.ProjectID == projectID)
+	}
+	changed := func(id string) bool {
+		return delta.Full || m.workGraph.Beads[id].UpdatedAt.After(since) || m.edgesChanged[id].After(since)
+	}
+
+	for id := range m.workGraph.Beads {
+		if inProject(id) && changed(id) {
+			delta.Beads[id] = m.workGraph.Beads[id]
+		}
+	}
+
+	for _, edge := range m.workGraph.Edges {
+		if inProject(edge.From) && inProject(edge.To) && (changed(edge.From) || changed(edge.To)) {
+			delta.Edges = append(delta.Edges, edge)
+		}
+	}
+
+	if !delta.Full {
+		for id, d := range m.deletedBeads {
+			if d.at.After(since) && (projectID == "" || d.projectID == projectID) {
+				delta.DeletedBeadIDs = append(delta.DeletedBeadIDs, id)
+			}
+		}
+		sort.Strings(delta.DeletedBeadIDs)
+	}
+
+	return delta, nil
+}
@@ -1,6 +1,7 @@
 package comments
 
 import (
+	"errors"
 	"fmt"
 	"regexp"
 	"time"
@@ -29,10 +30,15 @@ type Comment struct {
 	CreatedAt      time.Time  `json:"created_at"`
 	UpdatedAt      time.Time  `json:"updated_at"`
 	Edited         bool       `json:"edited"`
+	Depth          int        `json:"depth"` // 0 for top-level comments, 1 for their replies, ...
 	Replies        []*Comment `json:"replies,omitempty"`
 	Mentions       []string   `json:"mentions,omitempty"`
 }
 
+// ErrInvalidParent is returned when a reply's parent does not exist, was
+// deleted or is on another bead.
+var ErrInvalidParent = errors.New("invalid parent comment")
+
 var mentionRegex = regexp.MustCompile(`@([a-zA-Z0-9_-]+)`)
 
 // NewManager creates a new comments manager
@@ -46,6 +52,19 @@ func NewManager(db *database.Database, notificationMgr *notifications.Manager, e
 
 // CreateComment creates a new comment
 func (m *Manager) CreateComment(beadID, authorID, authorUsername, content, parentID string) (*Comment, error) {
+	if parentID != "" {
+		parent, err := m.db.GetComment(parentID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidParent, err)
+		}
+		if parent.Deleted {
+			return nil, fmt.Errorf("%w: comment %s was deleted", ErrInvalidParent, parentID)
+		}
+		if parent.BeadID != beadID {
+			return nil, fmt.Errorf("%w: comment %s is on bead %s", ErrInvalidParent, parentID, parent.BeadID)
+		}
+	}
+
 	now := time.Now()
 	comment := &Comment{
 		ID:             uuid.New().String(),
@@ -94,18 +113,38 @@ func (m *Manager) CreateComment(beadID, authorID, authorUsername, content, paren
 	return comment, nil
 }
 
-// GetComments retrieves all comments for a bead with threading
+// GetComments retrieves the comments of a bead as threads: top-level
+// comments with their replies nested under them, oldest first.
 func (m *Manager) GetComments(beadID string) ([]*Comment, error) {
-	dbComments, err := m.db.GetCommentsByBeadID(beadID)
+	comments, err := m.loadComments(beadID)
 	if err != nil {
 		return nil, err
 	}
+	return thread(comments), nil
+}
 
-	// Build comment map
-	commentMap := make(map[string]*Comment)
-	var topLevel []*Comment
+// GetCommentsFlat retrieves the comments of a bead oldest first, without
+// nesting. Each comment keeps its ParentID and Depth.
+func (m *Manager) GetCommentsFlat(beadID string) ([]*Comment, error) {
+	comments, err := m.loadComments(beadID)
+	if err != nil {
+		return nil, err
+	}
+	thread(comments)
+	for _, comment := range comments {
+		comment.Replies = nil
+	}
+	return comments, nil
+}
 
-	// First pass: create Comment objects
+// loadComments reads a bead's comments, oldest first.
+func (m *Manager) loadComments(beadID string) ([]*Comment, error) {
+	dbComments, err := m.db.GetCommentsByBeadID(beadID)
+	if err != nil {
+		return nil, err
+	}
+
+	comments := make([]*Comment, 0, len(dbComments))
 	for _, dbComment := range dbComments {
 		comment := &Comment{
 			ID:             dbComment.ID,
@@ -117,29 +156,45 @@ func (m *Manager) GetComments(beadID string) ([]*Comment, error) {
 			CreatedAt:      dbComment.CreatedAt,
 			UpdatedAt:      dbComment.UpdatedAt,
 			Edited:         dbComment.Edited,
-			Replies:        []*Comment{},
 		}
 
 		// Parse mentions from content
 		comment.Mentions = m.parseMentions(comment.Content)
 
-		commentMap[comment.ID] = comment
+		comments = append(comments, comment)
+	}
+	return comments, nil
+}
 
-		if comment.ParentID == "" {
+// thread nests replies under their parents, sets each comment's Depth and
+// returns the top-level comments. Replies whose parent was deleted are
+// shown at the top level.
+func thread(comments []*Comment) []*Comment {
+	byID := make(map[string]*Comment, len(comments))
+	for _, comment := range comments {
+		byID[comment.ID] = comment
+	}
+
+	var topLevel []*Comment
+	for _, comment := range comments {
+		if parent, ok := byID[comment.ParentID]; ok && comment.ParentID != "" {
+			parent.Replies = append(parent.Replies, comment)
+		} else {
 			topLevel = append(topLevel, comment)
 		}
 	}
 
-	// Second pass: build thread structure
-	for _, comment := range commentMap {
-		if comment.ParentID != "" {
-			if parent, exists := commentMap[comment.ParentID]; exists {
-				parent.Replies = append(parent.Replies, comment)
-			}
+	var setDepth func(comment *Comment, depth int)
+	setDepth = func(comment *Comment, depth int) {
+		comment.Depth = depth
+		for _, reply := range comment.Replies {
+			setDepth(reply, depth+1)
 		}
 	}
-
-	return topLevel, nil
+	for _, comment := range topLevel {
+		setDepth(comment, 0)
+	}
+	return topLevel
 }
 
 // UpdateComment updates a comment's content
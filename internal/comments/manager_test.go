@@ -0,0 +1,90 @@
+package comments
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/jordanhubbard/loom/internal/database"
+)
+
+func newTestManager(t *testing.T) *Manager {
+	t.Helper()
+	db, err := database.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return NewManager(db, nil, nil)
+}
+
+func TestManager_Threading(t *testing.T) {
+	m := newTestManager(t)
+
+	root, err := m.CreateComment("bead-1", "ceo", "ceo", "Why this approach?", "")
+	if err != nil {
+		t.Fatalf("CreateComment() error = %v", err)
+	}
+	reply, err := m.CreateComment("bead-1", "agent-1", "agent", "It keeps the API stable.", root.ID)
+	if err != nil {
+		t.Fatalf("CreateComment(reply) error = %v", err)
+	}
+	if _, err := m.CreateComment("bead-1", "ceo", "ceo", "Fine.", reply.ID); err != nil {
+		t.Fatalf("CreateComment(nested reply) error = %v", err)
+	}
+	if _, err := m.CreateComment("bead-1", "agent-2", "other", "Separate thread", ""); err != nil {
+		t.Fatalf("CreateComment() error = %v", err)
+	}
+
+	threads, err := m.GetComments("bead-1")
+	if err != nil {
+		t.Fatalf("GetComments() error = %v", err)
+	}
+	if len(threads) != 2 || threads[0].ID != root.ID {
+		t.Fatalf("GetComments() = %d threads, want 2 starting with the first comment", len(threads))
+	}
+	if len(threads[0].Replies) != 1 || threads[0].Replies[0].ID != reply.ID {
+		t.Fatalf("root replies = %+v, want the reply", threads[0].Replies)
+	}
+	nested := threads[0].Replies[0].Replies
+	if len(nested) != 1 || nested[0].Depth != 2 || threads[0].Replies[0].Depth != 1 {
+		t.Errorf("nested reply = %+v, want one reply at depth 2", nested)
+	}
+
+	flat, err := m.GetCommentsFlat("bead-1")
+	if err != nil {
+		t.Fatalf("GetCommentsFlat() error = %v", err)
+	}
+	wantDepths := []int{0, 1, 2, 0}
+	if len(flat) != len(wantDepths) {
+		t.Fatalf("GetCommentsFlat() = %d comments, want %d", len(flat), len(wantDepths))
+	}
+	for i, comment := range flat {
+		if comment.Depth != wantDepths[i] || len(comment.Replies) != 0 {
+			t.Errorf("flat[%d] depth = %d with %d replies, want depth %d and no nesting", i, comment.Depth, len(comment.Replies), wantDepths[i])
+		}
+	}
+}
+
+func TestManager_CreateComment_ValidatesParent(t *testing.T) {
+	m := newTestManager(t)
+
+	other, err := m.CreateComment("bead-2", "ceo", "ceo", "Elsewhere", "")
+	if err != nil {
+		t.Fatalf("CreateComment() error = %v", err)
+	}
+	deleted, _ := m.CreateComment("bead-1", "ceo", "ceo", "Gone soon", "")
+	if err := m.DeleteComment(deleted.ID, "ceo"); err != nil {
+		t.Fatalf("DeleteComment() error = %v", err)
+	}
+
+	for name, parentID := range map[string]string{
+		"missing":    "no-such-comment",
+		"other bead": other.ID,
+		"deleted":    deleted.ID,
+	} {
+		if _, err := m.CreateComment("bead-1", "agent-1", "agent", "reply", parentID); !errors.Is(err, ErrInvalidParent) {
+			t.Errorf("%s parent: error = %v, want ErrInvalidParent", name, err)
+		}
+	}
+}
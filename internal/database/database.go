@@ -68,6 +68,21 @@ func New(dbPath string) (*Database, error) {
 		return nil, fmt.Errorf("failed to migrate provider scoring: %w", err)
 	}
 
+	if err := d.migrateProviderConnection(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate provider connection settings: %w", err)
+	}
+
+	if err := d.migrateProviderLimits(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate provider limits: %w", err)
+	}
+
+	if err := d.migrateAgentConcurrency(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate agent concurrency: %w", err)
+	}
+
 	if err := d.migrateMotivations(); err != nil {
 		db.Close()
 		return nil, fmt.Errorf("failed to migrate motivations: %w", err)
@@ -207,6 +222,21 @@ func NewPostgreSQL() (*Database, error) {
 		return nil, fmt.Errorf("failed to migrate provider scoring: %w", err)
 	}
 
+	if err := d.migrateProviderConnection(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate provider connection settings: %w", err)
+	}
+
+	if err := d.migrateProviderLimits(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate provider limits: %w", err)
+	}
+
+	if err := d.migrateAgentConcurrency(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate agent concurrency: %w", err)
+	}
+
 	if err := d.migrateMotivations(); err != nil {
 		db.Close()
 		return nil, fmt.Errorf("failed to migrate motivations: %w", err)
@@ -707,8 +737,8 @@ func (d *Database) UpsertAgent(agent *models.Agent) error {
 	}
 
 	query := `
-		INSERT INTO agents (id, name, role, persona_name, provider_id, status, current_bead, project_id, started_at, last_active)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO agents (id, name, role, persona_name, provider_id, status, current_bead, project_id, max_concurrent, started_at, last_active)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(id) DO UPDATE SET
 			name = excluded.name,
 			role = excluded.role,
@@ -717,6 +747,7 @@ func (d *Database) UpsertAgent(agent *models.Agent) error {
 			status = excluded.status,
 			current_bead = excluded.current_bead,
 			project_id = excluded.project_id,
+			max_concurrent = excluded.max_concurrent,
 			last_active = excluded.last_active
 	`
 
@@ -741,6 +772,7 @@ func (d *Database) UpsertAgent(agent *models.Agent) error {
 		agent.Status,
 		currentBead,
 		projectID,
+		agent.MaxConcurrent,
 		agent.StartedAt,
 		agent.LastActive,
 	)
@@ -752,7 +784,7 @@ func (d *Database) UpsertAgent(agent *models.Agent) error {
 
 func (d *Database) ListAgents() ([]*models.Agent, error) {
 	query := `
-		SELECT id, name, role, persona_name, provider_id, status, current_bead, project_id, started_at, last_active
+		SELECT id, name, role, persona_name, provider_id, status, current_bead, project_id, max_concurrent, started_at, last_active
 		FROM agents
 		ORDER BY started_at DESC
 	`
@@ -775,6 +807,7 @@ func (d *Database) ListAgents() ([]*models.Agent, error) {
 			&a.Status,
 			&currentBead,
 			&projectID,
+			&a.MaxConcurrent,
 			&a.StartedAt,
 			&a.LastActive,
 		)
@@ -857,8 +890,8 @@ func (d *Database) UpsertProvider(provider *internalmodels.Provider) error {
 	provider.UpdatedAt = time.Now()
 
 	query := `
-		INSERT INTO providers (id, name, type, endpoint, model, configured_model, selected_model, selection_reason, model_score, selected_gpu, description, requires_key, key_id, owner_id, is_shared, status, last_heartbeat_at, last_heartbeat_latency_ms, last_heartbeat_error, context_window, model_params_b, capability_score, avg_latency_ms, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO providers (id, name, type, endpoint, model, configured_model, selected_model, selection_reason, model_score, selected_gpu, description, requires_key, key_id, owner_id, is_shared, status, last_heartbeat_at, last_heartbeat_latency_ms, last_heartbeat_error, context_window, model_params_b, capability_score, avg_latency_ms, deployment_name, api_version, region, timeout_seconds, max_retries, requests_per_minute, mock_response, mock_latency_ms, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(id) DO UPDATE SET
 			name = excluded.name,
 			type = excluded.type,
@@ -882,6 +915,14 @@ func (d *Database) UpsertProvider(provider *internalmodels.Provider) error {
 			model_params_b = excluded.model_params_b,
 			capability_score = excluded.capability_score,
 			avg_latency_ms = excluded.avg_latency_ms,
+			deployment_name = excluded.deployment_name,
+			api_version = excluded.api_version,
+			region = excluded.region,
+			timeout_seconds = excluded.timeout_seconds,
+			max_retries = excluded.max_retries,
+			requests_per_minute = excluded.requests_per_minute,
+			mock_response = excluded.mock_response,
+			mock_latency_ms = excluded.mock_latency_ms,
 			updated_at = excluded.updated_at
 	`
 
@@ -909,6 +950,14 @@ func (d *Database) UpsertProvider(provider *internalmodels.Provider) error {
 		provider.ModelParamsB,
 		provider.CapabilityScore,
 		provider.AvgLatencyMs,
+		provider.DeploymentName,
+		provider.APIVersion,
+		provider.Region,
+		provider.TimeoutSeconds,
+		provider.MaxRetries,
+		provider.RequestsPerMinute,
+		provider.MockResponse,
+		provider.MockLatencyMs,
 		provider.CreatedAt,
 		provider.UpdatedAt,
 	)
@@ -946,7 +995,7 @@ func (d *Database) DeleteAllAgents() error {
 // GetProvider retrieves a provider by ID
 func (d *Database) GetProvider(id string) (*internalmodels.Provider, error) {
 	query := `
-		SELECT id, name, type, endpoint, model, configured_model, selected_model, selection_reason, model_score, selected_gpu, description, requires_key, key_id, status, last_heartbeat_at, last_heartbeat_latency_ms, last_heartbeat_error, context_window, model_params_b, capability_score, avg_latency_ms, created_at, updated_at
+		SELECT id, name, type, endpoint, model, configured_model, selected_model, selection_reason, model_score, selected_gpu, description, requires_key, key_id, status, last_heartbeat_at, last_heartbeat_latency_ms, last_heartbeat_error, context_window, model_params_b, capability_score, avg_latency_ms, deployment_name, api_version, region, timeout_seconds, max_retries, requests_per_minute, mock_response, mock_latency_ms, created_at, updated_at
 		FROM providers
 		WHERE id = ?
 	`
@@ -975,6 +1024,14 @@ func (d *Database) GetProvider(id string) (*internalmodels.Provider, error) {
 		&modelParamsB,
 		&capabilityScore,
 		&avgLatencyMs,
+		&provider.DeploymentName,
+		&provider.APIVersion,
+		&provider.Region,
+		&provider.TimeoutSeconds,
+		&provider.MaxRetries,
+		&provider.RequestsPerMinute,
+		&provider.MockResponse,
+		&provider.MockLatencyMs,
 		&provider.CreatedAt,
 		&provider.UpdatedAt,
 	)
@@ -1001,7 +1058,7 @@ func (d *Database) GetProvider(id string) (*internalmodels.Provider, error) {
 // ListProviders retrieves all providers
 func (d *Database) ListProviders() ([]*internalmodels.Provider, error) {
 	query := `
-		SELECT id, name, type, endpoint, model, configured_model, selected_model, selection_reason, model_score, selected_gpu, description, requires_key, key_id, owner_id, is_shared, status, last_heartbeat_at, last_heartbeat_latency_ms, last_heartbeat_error, model_params_b, capability_score, avg_latency_ms, created_at, updated_at
+		SELECT id, name, type, endpoint, model, configured_model, selected_model, selection_reason, model_score, selected_gpu, description, requires_key, key_id, owner_id, is_shared, status, last_heartbeat_at, last_heartbeat_latency_ms, last_heartbeat_error, model_params_b, capability_score, avg_latency_ms, deployment_name, api_version, region, timeout_seconds, max_retries, requests_per_minute, mock_response, mock_latency_ms, created_at, updated_at
 		FROM providers
 		ORDER BY created_at DESC
 	`
@@ -1041,6 +1098,14 @@ func (d *Database) ListProviders() ([]*internalmodels.Provider, error) {
 			&modelParamsB,
 			&capabilityScore,
 			&avgLatencyMs,
+			&provider.DeploymentName,
+			&provider.APIVersion,
+			&provider.Region,
+			&provider.TimeoutSeconds,
+			&provider.MaxRetries,
+			&provider.RequestsPerMinute,
+			&provider.MockResponse,
+			&provider.MockLatencyMs,
 			&provider.CreatedAt,
 			&provider.UpdatedAt,
 		)
@@ -537,6 +537,23 @@ func TestUpsertAgent_Update(t *testing.T) {
 	}
 }
 
+func TestUpsertAgent_MaxConcurrent(t *testing.T) {
+	db := newTestDB(t)
+	a := makeTestAgent("agent-1", "Parallel")
+	a.MaxConcurrent = 3
+	if err := db.UpsertAgent(a); err != nil {
+		t.Fatalf("UpsertAgent failed: %v", err)
+	}
+
+	agents, err := db.ListAgents()
+	if err != nil {
+		t.Fatalf("ListAgents failed: %v", err)
+	}
+	if len(agents) != 1 || agents[0].MaxConcurrent != 3 {
+		t.Fatalf("agents = %+v, want one with MaxConcurrent 3", agents)
+	}
+}
+
 func TestUpsertAgent_WithTimestamps(t *testing.T) {
 	db := newTestDB(t)
 	now := time.Now().Add(-1 * time.Hour) // set explicit past time
@@ -916,6 +933,38 @@ func TestGetProvider_FieldIntegrity(t *testing.T) {
 	}
 }
 
+func TestProvider_ConnectionSettings(t *testing.T) {
+	db := newTestDB(t)
+	p := makeTestProvider("prov-azure", "Azure")
+	p.Type = "azure-openai"
+	p.DeploymentName = "gpt4o-prod"
+	p.APIVersion = "2024-10-21"
+	p.Region = "us-west-2"
+
+	if err := db.UpsertProvider(p); err != nil {
+		t.Fatalf("UpsertProvider failed: %v", err)
+	}
+
+	got, err := db.GetProvider("prov-azure")
+	if err != nil {
+		t.Fatalf("GetProvider failed: %v", err)
+	}
+	if got.DeploymentName != "gpt4o-prod" || got.APIVersion != "2024-10-21" {
+		t.Errorf("GetProvider deployment/version = %q/%q, want gpt4o-prod/2024-10-21", got.DeploymentName, got.APIVersion)
+	}
+	if got.Region != "us-west-2" {
+		t.Errorf("GetProvider region = %q, want us-west-2", got.Region)
+	}
+
+	providers, err := db.ListProviders()
+	if err != nil {
+		t.Fatalf("ListProviders failed: %v", err)
+	}
+	if len(providers) != 1 || providers[0].DeploymentName != "gpt4o-prod" || providers[0].APIVersion != "2024-10-21" || providers[0].Region != "us-west-2" {
+		t.Errorf("ListProviders did not return the connection settings: %+v", providers)
+	}
+}
+
 func TestListProviders_Empty(t *testing.T) {
 	db := newTestDB(t)
 	providers, err := db.ListProviders()
@@ -2417,6 +2466,7 @@ func TestWorkflowNode_CRUD(t *testing.T) {
 		PersonaHint:    "senior-dev",
 		MaxAttempts:    3,
 		TimeoutMinutes: 30,
+		TimeoutSeconds: 90,
 		Instructions:   "Investigate the bug",
 		Metadata:       map[string]string{"priority": "high"},
 	}
@@ -2440,6 +2490,9 @@ func TestWorkflowNode_CRUD(t *testing.T) {
 	if nodes[0].MaxAttempts != 3 {
 		t.Errorf("MaxAttempts = %d, want 3", nodes[0].MaxAttempts)
 	}
+	if nodes[0].TimeoutMinutes != 30 || nodes[0].TimeoutSeconds != 90 {
+		t.Errorf("Timeout = %dm/%ds, want 30m/90s", nodes[0].TimeoutMinutes, nodes[0].TimeoutSeconds)
+	}
 	if nodes[0].Metadata["priority"] != "high" {
 		t.Errorf("Metadata[priority] = %q, want %q", nodes[0].Metadata["priority"], "high")
 	}
@@ -2600,6 +2653,21 @@ func TestWorkflowExecution_CRUD(t *testing.T) {
 	if got.Status != workflow.ExecutionStatusActive {
 		t.Errorf("Status = %q, want %q", got.Status, workflow.ExecutionStatusActive)
 	}
+	if got.NodeDispatchedAt != nil {
+		t.Errorf("NodeDispatchedAt = %v, want nil before dispatch", *got.NodeDispatchedAt)
+	}
+
+	dispatchedAt := time.Now().UTC().Truncate(time.Second)
+	got.NodeDispatchedAt = &dispatchedAt
+	if err := db.UpsertWorkflowExecution(got); err != nil {
+		t.Fatalf("UpsertWorkflowExecution failed: %v", err)
+	}
+	if got, err = db.GetWorkflowExecution("exec-1"); err != nil {
+		t.Fatalf("GetWorkflowExecution failed: %v", err)
+	}
+	if got.NodeDispatchedAt == nil || !got.NodeDispatchedAt.Equal(dispatchedAt) {
+		t.Errorf("NodeDispatchedAt = %v, want %v", got.NodeDispatchedAt, dispatchedAt)
+	}
 
 	byBead, err := db.GetWorkflowExecutionByBeadID("bead-exec-1")
 	if err != nil {
@@ -2766,6 +2834,200 @@ func TestListWorkflowHistory_Empty(t *testing.T) {
 	}
 }
 
+func TestSaveWorkflowDefinition_ReplacesGraph(t *testing.T) {
+	db := newTestDB(t)
+	var _ workflow.DefinitionStore = db // The workflow API edits definitions through this
+
+	wf := &workflow.Workflow{
+		ID:           "wf-def",
+		Name:         "Definition",
+		WorkflowType: "custom",
+		Nodes: []workflow.WorkflowNode{
+			{ID: "n-1", NodeKey: "work", NodeType: workflow.NodeTypeTask},
+			{ID: "n-2", NodeKey: "review", NodeType: workflow.NodeTypeApproval},
+		},
+		Edges: []workflow.WorkflowEdge{
+			{ID: "e-1", ToNodeKey: "work", Condition: workflow.EdgeConditionSuccess},
+			{ID: "e-2", FromNodeKey: "work", ToNodeKey: "review", Condition: workflow.EdgeConditionSuccess},
+			{ID: "e-3", FromNodeKey: "review", Condition: workflow.EdgeConditionApproved},
+		},
+	}
+	if err := db.SaveWorkflowDefinition(wf); err != nil {
+		t.Fatalf("SaveWorkflowDefinition (create) failed: %v", err)
+	}
+
+	// Dropping the review node must drop its node row and edges too
+	wf.Nodes = wf.Nodes[:1]
+	wf.Edges = []workflow.WorkflowEdge{
+		{ID: "e-4", ToNodeKey: "work", Condition: workflow.EdgeConditionSuccess},
+		{ID: "e-5", FromNodeKey: "work", Condition: workflow.EdgeConditionSuccess},
+	}
+	if err := db.SaveWorkflowDefinition(wf); err != nil {
+		t.Fatalf("SaveWorkflowDefinition (update) failed: %v", err)
+	}
+
+	got, err := db.GetWorkflow("wf-def")
+	if err != nil {
+		t.Fatalf("GetWorkflow failed: %v", err)
+	}
+	if len(got.Nodes) != 1 || got.Nodes[0].NodeKey != "work" {
+		t.Errorf("Nodes = %+v, want only work", got.Nodes)
+	}
+	if len(got.Edges) != 2 {
+		t.Errorf("Expected 2 edges, got %d", len(got.Edges))
+	}
+	for _, edge := range got.Edges {
+		if edge.WorkflowID != "wf-def" {
+			t.Errorf("Edge %s WorkflowID = %q, want %q", edge.ID, edge.WorkflowID, "wf-def")
+		}
+	}
+}
+
+func TestInstallDefaultWorkflows_Idempotent(t *testing.T) {
+	db := newTestDB(t)
+	for i := 0; i < 2; i++ {
+		if err := workflow.InstallDefaultWorkflows(db, "../../workflows/defaults"); err != nil {
+			t.Fatalf("InstallDefaultWorkflows() error = %v", err)
+		}
+	}
+
+	defs, err := workflow.LoadDefaultWorkflows("../../workflows/defaults")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, def := range defs {
+		got, err := db.GetWorkflow(def.ID)
+		if err != nil {
+			t.Fatalf("GetWorkflow(%s) failed: %v", def.ID, err)
+		}
+		// Reinstalling must not leave a second copy of each edge
+		if len(got.Edges) != len(def.Edges) || len(got.Nodes) != len(def.Nodes) {
+			t.Errorf("%s has %d nodes and %d edges after two installs, want %d and %d",
+				def.ID, len(got.Nodes), len(got.Edges), len(def.Nodes), len(def.Edges))
+		}
+	}
+}
+
+func TestDeleteWorkflow(t *testing.T) {
+	db := newTestDB(t)
+	ensureProjectExists(t, db, "proj-wfd")
+
+	wf := &workflow.Workflow{
+		ID:           "wf-del",
+		Name:         "Delete Me",
+		WorkflowType: "custom",
+		Nodes:        []workflow.WorkflowNode{{ID: "n-del", NodeKey: "work", NodeType: workflow.NodeTypeTask}},
+		Edges:        []workflow.WorkflowEdge{{ID: "e-del", ToNodeKey: "work", Condition: workflow.EdgeConditionSuccess}},
+	}
+	if err := db.SaveWorkflowDefinition(wf); err != nil {
+		t.Fatalf("SaveWorkflowDefinition failed: %v", err)
+	}
+	exec := &workflow.WorkflowExecution{
+		ID:         "exec-del",
+		WorkflowID: "wf-del",
+		BeadID:     "bead-del",
+		ProjectID:  "proj-wfd",
+		Status:     workflow.ExecutionStatusActive,
+	}
+	if err := db.UpsertWorkflowExecution(exec); err != nil {
+		t.Fatalf("UpsertWorkflowExecution failed: %v", err)
+	}
+
+	active, err := db.CountActiveWorkflowExecutions("wf-del")
+	if err != nil {
+		t.Fatalf("CountActiveWorkflowExecutions failed: %v", err)
+	}
+	if active != 1 {
+		t.Errorf("Expected 1 active execution, got %d", active)
+	}
+
+	if err := db.DeleteWorkflow("wf-del"); err != nil {
+		t.Fatalf("DeleteWorkflow failed: %v", err)
+	}
+	if _, err := db.GetWorkflow("wf-del"); err == nil {
+		t.Error("Expected workflow to be gone")
+	}
+	if got, _ := db.GetWorkflowExecutionByBeadID("bead-del"); got != nil {
+		t.Error("Expected the workflow's execution to be deleted")
+	}
+	if nodes, _ := db.ListWorkflowNodes("wf-del"); len(nodes) != 0 {
+		t.Errorf("Expected 0 nodes, got %d", len(nodes))
+	}
+	if err := db.DeleteWorkflow("wf-del"); err == nil {
+		t.Error("Expected error deleting a missing workflow")
+	}
+}
+
+func TestCountActiveWorkflowExecutions_IgnoresFinished(t *testing.T) {
+	db := newTestDB(t)
+	ensureProjectExists(t, db, "proj-wfc")
+
+	if err := db.UpsertWorkflow(&workflow.Workflow{ID: "wf-cnt", Name: "Count", WorkflowType: "custom"}); err != nil {
+		t.Fatalf("UpsertWorkflow failed: %v", err)
+	}
+	statuses := []workflow.ExecutionStatus{
+		workflow.ExecutionStatusActive,
+		workflow.ExecutionStatusBlocked,
+		workflow.ExecutionStatusCompleted,
+		workflow.ExecutionStatusEscalated,
+	}
+	for i, status := range statuses {
+		exec := &workflow.WorkflowExecution{
+			ID:         fmt.Sprintf("exec-cnt-%d", i),
+			WorkflowID: "wf-cnt",
+			BeadID:     fmt.Sprintf("bead-cnt-%d", i),
+			ProjectID:  "proj-wfc",
+			Status:     status,
+		}
+		if err := db.UpsertWorkflowExecution(exec); err != nil {
+			t.Fatalf("UpsertWorkflowExecution failed: %v", err)
+		}
+	}
+
+	active, err := db.CountActiveWorkflowExecutions("wf-cnt")
+	if err != nil {
+		t.Fatalf("CountActiveWorkflowExecutions failed: %v", err)
+	}
+	if active != 2 {
+		t.Errorf("Expected 2 active executions, got %d", active)
+	}
+}
+
+func TestListActiveWorkflowExecutions(t *testing.T) {
+	db := newTestDB(t)
+	ensureProjectExists(t, db, "proj-wfa")
+
+	if err := db.UpsertWorkflow(&workflow.Workflow{ID: "wf-act", Name: "Active", WorkflowType: "custom"}); err != nil {
+		t.Fatalf("UpsertWorkflow failed: %v", err)
+	}
+	now := time.Now()
+	execs := []*workflow.WorkflowExecution{
+		{ID: "exec-act-new", Status: workflow.ExecutionStatusActive, LastNodeAt: now},
+		{ID: "exec-act-old", Status: workflow.ExecutionStatusActive, LastNodeAt: now.Add(-time.Hour)},
+		{ID: "exec-act-blocked", Status: workflow.ExecutionStatusBlocked, LastNodeAt: now},
+		{ID: "exec-act-escalated", Status: workflow.ExecutionStatusEscalated, LastNodeAt: now},
+	}
+	for _, exec := range execs {
+		exec.WorkflowID = "wf-act"
+		exec.BeadID = "bead-" + exec.ID
+		exec.ProjectID = "proj-wfa"
+		if err := db.UpsertWorkflowExecution(exec); err != nil {
+			t.Fatalf("UpsertWorkflowExecution failed: %v", err)
+		}
+	}
+
+	active, err := db.ListActiveWorkflowExecutions()
+	if err != nil {
+		t.Fatalf("ListActiveWorkflowExecutions failed: %v", err)
+	}
+	if len(active) != 2 {
+		t.Fatalf("Expected 2 active executions, got %d", len(active))
+	}
+	if active[0].ID != "exec-act-old" || active[1].ID != "exec-act-new" {
+		t.Errorf("Expected oldest first, got %s then %s", active[0].ID, active[1].ID)
+	}
+}
+
 // ---------------------------------------------------------------------------
 // 14. Distributed (no-HA early returns for SQLite)
 // ---------------------------------------------------------------------------
@@ -0,0 +1,33 @@
+package database
+
+// migrateAgentConcurrency adds how many tasks an agent may run at once, so a
+// limit set through the API survives a restart. Zero means one at a time.
+func (d *Database) migrateAgentConcurrency() error {
+	if d.dbType == "postgres" {
+		_, err := d.db.Exec("ALTER TABLE agents ADD COLUMN IF NOT EXISTS max_concurrent INTEGER NOT NULL DEFAULT 0")
+		return err
+	}
+
+	rows, err := d.db.Query("PRAGMA table_info(agents)")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cid int
+		var name, dataType string
+		var notNull, pk int
+		var dfltValue interface{}
+
+		if err := rows.Scan(&cid, &name, &dataType, &notNull, &dfltValue, &pk); err != nil {
+			continue
+		}
+		if name == "max_concurrent" {
+			return nil
+		}
+	}
+
+	_, err = d.db.Exec("ALTER TABLE agents ADD COLUMN max_concurrent INTEGER NOT NULL DEFAULT 0")
+	return err
+}
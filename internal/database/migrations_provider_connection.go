@@ -0,0 +1,48 @@
+package database
+
+// providerConnectionColumns hold settings that some provider types need to
+// build requests, such as the Azure OpenAI deployment and API version or the
+// Bedrock region.
+var providerConnectionColumns = []string{"deployment_name", "api_version", "region"}
+
+func (d *Database) migrateProviderConnection() error {
+	if d.dbType == "postgres" {
+		for _, col := range providerConnectionColumns {
+			if _, err := d.db.Exec("ALTER TABLE providers ADD COLUMN IF NOT EXISTS " + col + " TEXT NOT NULL DEFAULT ''"); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	existing := make(map[string]bool)
+
+	rows, err := d.db.Query("PRAGMA table_info(providers)")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cid int
+		var name, dataType string
+		var notNull, pk int
+		var dfltValue interface{}
+
+		if err := rows.Scan(&cid, &name, &dataType, &notNull, &dfltValue, &pk); err != nil {
+			continue
+		}
+		existing[name] = true
+	}
+
+	for _, col := range providerConnectionColumns {
+		if existing[col] {
+			continue
+		}
+		if _, err := d.db.Exec("ALTER TABLE providers ADD COLUMN " + col + " TEXT NOT NULL DEFAULT ''"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
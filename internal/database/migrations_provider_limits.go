@@ -0,0 +1,67 @@
+package database
+
+// providerColumn is a providers column added after the table was created
+type providerColumn struct {
+	name       string
+	definition string
+}
+
+// providerLimitColumns hold the per-provider request limits: how long a
+// request may take, how often a failed one is retried and how many requests
+// may start per minute. Zero means the registry default, which for the rate
+// is unlimited.
+var providerLimitColumns = []providerColumn{
+	{"timeout_seconds", "INTEGER NOT NULL DEFAULT 0"},
+	{"max_retries", "INTEGER NOT NULL DEFAULT 0"},
+	{"requests_per_minute", "INTEGER NOT NULL DEFAULT 0"},
+}
+
+// providerMockColumns configure the offline mock provider: a canned reply,
+// empty to echo the last message, and a simulated delay per response.
+var providerMockColumns = []providerColumn{
+	{"mock_response", "TEXT NOT NULL DEFAULT ''"},
+	{"mock_latency_ms", "INTEGER NOT NULL DEFAULT 0"},
+}
+
+func (d *Database) migrateProviderLimits() error {
+	columns := append(append([]providerColumn{}, providerLimitColumns...), providerMockColumns...)
+	if d.dbType == "postgres" {
+		for _, col := range columns {
+			if _, err := d.db.Exec("ALTER TABLE providers ADD COLUMN IF NOT EXISTS " + col.name + " " + col.definition); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	existing := make(map[string]bool)
+
+	rows, err := d.db.Query("PRAGMA table_info(providers)")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cid int
+		var name, dataType string
+		var notNull, pk int
+		var dfltValue interface{}
+
+		if err := rows.Scan(&cid, &name, &dataType, &notNull, &dfltValue, &pk); err != nil {
+			continue
+		}
+		existing[name] = true
+	}
+
+	for _, col := range columns {
+		if existing[col.name] {
+			continue
+		}
+		if _, err := d.db.Exec("ALTER TABLE providers ADD COLUMN " + col.name + " " + col.definition); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -45,6 +45,7 @@ func (d *Database) migrateWorkflows() error {
 		persona_hint TEXT,
 		max_attempts INTEGER NOT NULL DEFAULT 0,
 		timeout_minutes INTEGER NOT NULL DEFAULT 0,
+		timeout_seconds INTEGER NOT NULL DEFAULT 0,
 		instructions TEXT,
 		metadata_json TEXT,
 		created_at DATETIME NOT NULL,
@@ -61,6 +62,9 @@ func (d *Database) migrateWorkflows() error {
 		return err
 	}
 
+	// Best-effort column addition for existing databases
+	_, _ = d.db.Exec("ALTER TABLE workflow_nodes ADD COLUMN timeout_seconds INTEGER NOT NULL DEFAULT 0")
+
 	// Workflow edges table
 	edgesSchema := `
 	CREATE TABLE IF NOT EXISTS workflow_edges (
@@ -98,6 +102,9 @@ func (d *Database) migrateWorkflows() error {
 		completed_at DATETIME,
 		escalated_at DATETIME,
 		last_node_at DATETIME NOT NULL,
+		node_dispatched_at DATETIME,
+		parent_execution_id TEXT,
+		parallel_children TEXT,
 		FOREIGN KEY (workflow_id) REFERENCES workflows(id) ON DELETE CASCADE,
 		FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
 		UNIQUE(bead_id)
@@ -113,6 +120,11 @@ func (d *Database) migrateWorkflows() error {
 		return err
 	}
 
+	// Best-effort column additions for existing databases
+	_, _ = d.db.Exec("ALTER TABLE workflow_executions ADD COLUMN parent_execution_id TEXT")
+	_, _ = d.db.Exec("ALTER TABLE workflow_executions ADD COLUMN parallel_children TEXT")
+	_, _ = d.db.Exec("ALTER TABLE workflow_executions ADD COLUMN node_dispatched_at DATETIME")
+
 	// Workflow execution history table
 	historySchema := `
 	CREATE TABLE IF NOT EXISTS workflow_execution_history (
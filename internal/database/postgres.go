@@ -48,6 +48,21 @@ func NewPostgres(dsn string) (*Database, error) {
 		return nil, fmt.Errorf("failed to migrate provider scoring: %w", err)
 	}
 
+	if err := d.migrateProviderConnection(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate provider connection settings: %w", err)
+	}
+
+	if err := d.migrateProviderLimits(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate provider limits: %w", err)
+	}
+
+	if err := d.migrateAgentConcurrency(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate agent concurrency: %w", err)
+	}
+
 	return d, nil
 }
 
@@ -9,8 +9,18 @@ import (
 	"github.com/jordanhubbard/loom/internal/workflow"
 )
 
+// execer is satisfied by *sql.DB and *sql.Tx, so the workflow upserts can
+// run alone or as part of SaveWorkflowDefinition's transaction
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
 // UpsertWorkflow inserts or updates a workflow
 func (d *Database) UpsertWorkflow(wf *workflow.Workflow) error {
+	return upsertWorkflow(d.db, wf)
+}
+
+func upsertWorkflow(ex execer, wf *workflow.Workflow) error {
 	if wf == nil {
 		return fmt.Errorf("workflow cannot be nil")
 	}
@@ -36,7 +46,7 @@ func (d *Database) UpsertWorkflow(wf *workflow.Workflow) error {
 		projectID = wf.ProjectID
 	}
 
-	_, err := d.db.Exec(query,
+	_, err := ex.Exec(query,
 		wf.ID,
 		wf.Name,
 		wf.Description,
@@ -165,6 +175,10 @@ func (d *Database) ListWorkflows(workflowType, projectID string) ([]*workflow.Wo
 
 // UpsertWorkflowNode inserts or updates a workflow node
 func (d *Database) UpsertWorkflowNode(node *workflow.WorkflowNode) error {
+	return upsertWorkflowNode(d.db, node)
+}
+
+func upsertWorkflowNode(ex execer, node *workflow.WorkflowNode) error {
 	if node == nil {
 		return fmt.Errorf("workflow node cannot be nil")
 	}
@@ -182,19 +196,20 @@ func (d *Database) UpsertWorkflowNode(node *workflow.WorkflowNode) error {
 	}
 
 	query := `
-		INSERT INTO workflow_nodes (id, workflow_id, node_key, node_type, role_required, persona_hint, max_attempts, timeout_minutes, instructions, metadata_json, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO workflow_nodes (id, workflow_id, node_key, node_type, role_required, persona_hint, max_attempts, timeout_minutes, timeout_seconds, instructions, metadata_json, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(workflow_id, node_key) DO UPDATE SET
 			node_type = excluded.node_type,
 			role_required = excluded.role_required,
 			persona_hint = excluded.persona_hint,
 			max_attempts = excluded.max_attempts,
 			timeout_minutes = excluded.timeout_minutes,
+			timeout_seconds = excluded.timeout_seconds,
 			instructions = excluded.instructions,
 			metadata_json = excluded.metadata_json
 	`
 
-	_, err := d.db.Exec(query,
+	_, err := ex.Exec(query,
 		node.ID,
 		node.WorkflowID,
 		node.NodeKey,
@@ -203,6 +218,7 @@ func (d *Database) UpsertWorkflowNode(node *workflow.WorkflowNode) error {
 		node.PersonaHint,
 		node.MaxAttempts,
 		node.TimeoutMinutes,
+		node.TimeoutSeconds,
 		node.Instructions,
 		metadataJSON,
 		node.CreatedAt,
@@ -213,7 +229,7 @@ func (d *Database) UpsertWorkflowNode(node *workflow.WorkflowNode) error {
 // ListWorkflowNodes retrieves all nodes for a workflow
 func (d *Database) ListWorkflowNodes(workflowID string) ([]workflow.WorkflowNode, error) {
 	query := `
-		SELECT id, workflow_id, node_key, node_type, role_required, persona_hint, max_attempts, timeout_minutes, instructions, metadata_json, created_at
+		SELECT id, workflow_id, node_key, node_type, role_required, persona_hint, max_attempts, timeout_minutes, timeout_seconds, instructions, metadata_json, created_at
 		FROM workflow_nodes
 		WHERE workflow_id = ?
 		ORDER BY created_at ASC
@@ -238,6 +254,7 @@ func (d *Database) ListWorkflowNodes(workflowID string) ([]workflow.WorkflowNode
 			&node.PersonaHint,
 			&node.MaxAttempts,
 			&node.TimeoutMinutes,
+			&node.TimeoutSeconds,
 			&node.Instructions,
 			&metadataJSON,
 			&node.CreatedAt,
@@ -261,6 +278,10 @@ func (d *Database) ListWorkflowNodes(workflowID string) ([]workflow.WorkflowNode
 
 // UpsertWorkflowEdge inserts or updates a workflow edge
 func (d *Database) UpsertWorkflowEdge(edge *workflow.WorkflowEdge) error {
+	return upsertWorkflowEdge(d.db, edge)
+}
+
+func upsertWorkflowEdge(ex execer, edge *workflow.WorkflowEdge) error {
 	if edge == nil {
 		return fmt.Errorf("workflow edge cannot be nil")
 	}
@@ -286,7 +307,7 @@ func (d *Database) UpsertWorkflowEdge(edge *workflow.WorkflowEdge) error {
 		toNodeKey = edge.ToNodeKey
 	}
 
-	_, err := d.db.Exec(query,
+	_, err := ex.Exec(query,
 		edge.ID,
 		edge.WorkflowID,
 		fromNodeKey,
@@ -343,6 +364,82 @@ func (d *Database) ListWorkflowEdges(workflowID string) ([]workflow.WorkflowEdge
 	return edges, nil
 }
 
+// SaveWorkflowDefinition writes a workflow with its nodes and edges in one
+// transaction, replacing whatever graph the workflow had before.
+func (d *Database) SaveWorkflowDefinition(wf *workflow.Workflow) error {
+	if wf == nil {
+		return fmt.Errorf("workflow cannot be nil")
+	}
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := upsertWorkflow(tx, wf); err != nil {
+		return fmt.Errorf("failed to save workflow: %w", err)
+	}
+	if _, err := tx.Exec("DELETE FROM workflow_edges WHERE workflow_id = ?", wf.ID); err != nil {
+		return fmt.Errorf("failed to clear workflow edges: %w", err)
+	}
+	if _, err := tx.Exec("DELETE FROM workflow_nodes WHERE workflow_id = ?", wf.ID); err != nil {
+		return fmt.Errorf("failed to clear workflow nodes: %w", err)
+	}
+	for i := range wf.Nodes {
+		wf.Nodes[i].WorkflowID = wf.ID
+		if err := upsertWorkflowNode(tx, &wf.Nodes[i]); err != nil {
+			return fmt.Errorf("failed to save node %s: %w", wf.Nodes[i].NodeKey, err)
+		}
+	}
+	for i := range wf.Edges {
+		wf.Edges[i].WorkflowID = wf.ID
+		if err := upsertWorkflowEdge(tx, &wf.Edges[i]); err != nil {
+			return fmt.Errorf("failed to save edge %s: %w", wf.Edges[i].ID, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// DeleteWorkflow removes a workflow with its nodes, edges, executions and
+// execution history.
+func (d *Database) DeleteWorkflow(id string) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, query := range []string{
+		"DELETE FROM workflow_execution_history WHERE execution_id IN (SELECT id FROM workflow_executions WHERE workflow_id = ?)",
+		"DELETE FROM workflow_executions WHERE workflow_id = ?",
+		"DELETE FROM workflow_edges WHERE workflow_id = ?",
+		"DELETE FROM workflow_nodes WHERE workflow_id = ?",
+	} {
+		if _, err := tx.Exec(query, id); err != nil {
+			return fmt.Errorf("failed to delete workflow: %w", err)
+		}
+	}
+	result, err := tx.Exec("DELETE FROM workflows WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete workflow: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return fmt.Errorf("workflow not found: %s", id)
+	}
+	return tx.Commit()
+}
+
+// CountActiveWorkflowExecutions returns how many executions of a workflow
+// are still running, counting blocked ones.
+func (d *Database) CountActiveWorkflowExecutions(workflowID string) (int, error) {
+	var count int
+	err := d.db.QueryRow(
+		"SELECT COUNT(*) FROM workflow_executions WHERE workflow_id = ? AND status IN (?, ?)",
+		workflowID, string(workflow.ExecutionStatusActive), string(workflow.ExecutionStatusBlocked),
+	).Scan(&count)
+	return count, err
+}
+
 // UpsertWorkflowExecution inserts or updates a workflow execution
 func (d *Database) UpsertWorkflowExecution(exec *workflow.WorkflowExecution) error {
 	if exec == nil {
@@ -356,8 +453,8 @@ func (d *Database) UpsertWorkflowExecution(exec *workflow.WorkflowExecution) err
 	}
 
 	query := `
-		INSERT INTO workflow_executions (id, workflow_id, bead_id, project_id, current_node_key, status, cycle_count, node_attempt_count, started_at, completed_at, escalated_at, last_node_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO workflow_executions (id, workflow_id, bead_id, project_id, current_node_key, status, cycle_count, node_attempt_count, started_at, completed_at, escalated_at, last_node_at, node_dispatched_at, parent_execution_id, parallel_children)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(bead_id) DO UPDATE SET
 			current_node_key = excluded.current_node_key,
 			status = excluded.status,
@@ -365,13 +462,28 @@ func (d *Database) UpsertWorkflowExecution(exec *workflow.WorkflowExecution) err
 			node_attempt_count = excluded.node_attempt_count,
 			completed_at = excluded.completed_at,
 			escalated_at = excluded.escalated_at,
-			last_node_at = excluded.last_node_at
+			last_node_at = excluded.last_node_at,
+			node_dispatched_at = excluded.node_dispatched_at,
+			parent_execution_id = excluded.parent_execution_id,
+			parallel_children = excluded.parallel_children
 	`
 
 	var currentNodeKey interface{}
 	if exec.CurrentNodeKey != "" {
 		currentNodeKey = exec.CurrentNodeKey
 	}
+	var parentExecutionID interface{}
+	if exec.ParentExecutionID != "" {
+		parentExecutionID = exec.ParentExecutionID
+	}
+	var parallelChildren interface{}
+	if len(exec.ParallelChildren) > 0 {
+		b, err := json.Marshal(exec.ParallelChildren)
+		if err != nil {
+			return fmt.Errorf("failed to marshal parallel children: %w", err)
+		}
+		parallelChildren = string(b)
+	}
 
 	_, err := d.db.Exec(query,
 		exec.ID,
@@ -386,22 +498,28 @@ func (d *Database) UpsertWorkflowExecution(exec *workflow.WorkflowExecution) err
 		exec.CompletedAt,
 		exec.EscalatedAt,
 		exec.LastNodeAt,
+		exec.NodeDispatchedAt,
+		parentExecutionID,
+		parallelChildren,
 	)
 	return err
 }
 
-// GetWorkflowExecution retrieves a workflow execution by ID
-func (d *Database) GetWorkflowExecution(id string) (*workflow.WorkflowExecution, error) {
-	query := `
-		SELECT id, workflow_id, bead_id, project_id, current_node_key, status, cycle_count, node_attempt_count, started_at, completed_at, escalated_at, last_node_at
-		FROM workflow_executions
-		WHERE id = ?
-	`
+// workflowExecutionColumns are the columns scanWorkflowExecution reads, in order
+const workflowExecutionColumns = `id, workflow_id, bead_id, project_id, current_node_key, status, cycle_count, node_attempt_count, started_at, completed_at, escalated_at, last_node_at, node_dispatched_at, parent_execution_id, parallel_children`
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
 
+// scanWorkflowExecution reads one workflow execution selected with
+// workflowExecutionColumns
+func scanWorkflowExecution(row rowScanner) (*workflow.WorkflowExecution, error) {
 	exec := &workflow.WorkflowExecution{}
-	var currentNodeKey sql.NullString
-	var completedAt, escalatedAt sql.NullTime
-	err := d.db.QueryRow(query, id).Scan(
+	var currentNodeKey, parentExecutionID, parallelChildren sql.NullString
+	var completedAt, escalatedAt, nodeDispatchedAt sql.NullTime
+	err := row.Scan(
 		&exec.ID,
 		&exec.WorkflowID,
 		&exec.BeadID,
@@ -414,10 +532,10 @@ func (d *Database) GetWorkflowExecution(id string) (*workflow.WorkflowExecution,
 		&completedAt,
 		&escalatedAt,
 		&exec.LastNodeAt,
+		&nodeDispatchedAt,
+		&parentExecutionID,
+		&parallelChildren,
 	)
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("workflow execution not found: %s", id)
-	}
 	if err != nil {
 		return nil, err
 	}
@@ -431,53 +549,59 @@ func (d *Database) GetWorkflowExecution(id string) (*workflow.WorkflowExecution,
 	if escalatedAt.Valid {
 		exec.EscalatedAt = &escalatedAt.Time
 	}
+	if nodeDispatchedAt.Valid {
+		exec.NodeDispatchedAt = &nodeDispatchedAt.Time
+	}
+	exec.ParentExecutionID = parentExecutionID.String
+	if parallelChildren.Valid && parallelChildren.String != "" {
+		_ = json.Unmarshal([]byte(parallelChildren.String), &exec.ParallelChildren)
+	}
 
 	return exec, nil
 }
 
+// GetWorkflowExecution retrieves a workflow execution by ID
+func (d *Database) GetWorkflowExecution(id string) (*workflow.WorkflowExecution, error) {
+	query := "SELECT " + workflowExecutionColumns + " FROM workflow_executions WHERE id = ?"
+
+	exec, err := scanWorkflowExecution(d.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("workflow execution not found: %s", id)
+	}
+	return exec, err
+}
+
 // GetWorkflowExecutionByBeadID retrieves a workflow execution by bead ID
 func (d *Database) GetWorkflowExecutionByBeadID(beadID string) (*workflow.WorkflowExecution, error) {
-	query := `
-		SELECT id, workflow_id, bead_id, project_id, current_node_key, status, cycle_count, node_attempt_count, started_at, completed_at, escalated_at, last_node_at
-		FROM workflow_executions
-		WHERE bead_id = ?
-	`
+	query := "SELECT " + workflowExecutionColumns + " FROM workflow_executions WHERE bead_id = ?"
 
-	exec := &workflow.WorkflowExecution{}
-	var currentNodeKey sql.NullString
-	var completedAt, escalatedAt sql.NullTime
-	err := d.db.QueryRow(query, beadID).Scan(
-		&exec.ID,
-		&exec.WorkflowID,
-		&exec.BeadID,
-		&exec.ProjectID,
-		&currentNodeKey,
-		&exec.Status,
-		&exec.CycleCount,
-		&exec.NodeAttemptCount,
-		&exec.StartedAt,
-		&completedAt,
-		&escalatedAt,
-		&exec.LastNodeAt,
-	)
+	exec, err := scanWorkflowExecution(d.db.QueryRow(query, beadID))
 	if err == sql.ErrNoRows {
 		return nil, nil // Not an error - just no execution for this bead yet
 	}
+	return exec, err
+}
+
+// ListActiveWorkflowExecutions returns every execution that is running,
+// oldest node first, for the workflow engine's timeout sweep.
+func (d *Database) ListActiveWorkflowExecutions() ([]*workflow.WorkflowExecution, error) {
+	query := "SELECT " + workflowExecutionColumns + " FROM workflow_executions WHERE status = ? ORDER BY last_node_at ASC"
+
+	rows, err := d.db.Query(query, string(workflow.ExecutionStatusActive))
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	if currentNodeKey.Valid {
-		exec.CurrentNodeKey = currentNodeKey.String
-	}
-	if completedAt.Valid {
-		exec.CompletedAt = &completedAt.Time
-	}
-	if escalatedAt.Valid {
-		exec.EscalatedAt = &escalatedAt.Time
+	var executions []*workflow.WorkflowExecution
+	for rows.Next() {
+		exec, err := scanWorkflowExecution(rows)
+		if err != nil {
+			return nil, err
+		}
+		executions = append(executions, exec)
 	}
-
-	return exec, nil
+	return executions, rows.Err()
 }
 
 // DeleteWorkflowExecutionByBeadID removes workflow executions for a bead,
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/jordanhubbard/loom/pkg/models"
 )
@@ -1517,3 +1518,68 @@ func TestEscalateDecision_PreservesOtherFields(t *testing.T) {
 		t.Errorf("EscalateDecision() changed ProjectID from %q to %q", originalProjectID, got.ProjectID)
 	}
 }
+
+// TestSetAutoResolve_Validation verifies the timeout and default decision
+// checks.
+func TestSetAutoResolve_Validation(t *testing.T) {
+	m, d := createTestDecision(t)
+
+	tests := []struct {
+		name            string
+		id              string
+		timeout         time.Duration
+		defaultDecision string
+	}{
+		{"unknown decision", "missing", time.Hour, "MySQL"},
+		{"zero timeout", d.ID, 0, "MySQL"},
+		{"no default", d.ID, time.Hour, ""},
+		{"default not an option", d.ID, time.Hour, "Oracle"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := m.SetAutoResolve(tt.id, tt.timeout, tt.defaultDecision); err == nil {
+				t.Error("SetAutoResolve() expected error, got nil")
+			}
+		})
+	}
+
+	if err := m.SetAutoResolve(d.ID, time.Hour, "MySQL"); err != nil {
+		t.Fatalf("SetAutoResolve() error = %v", err)
+	}
+	if d.AutoResolveAt == nil || !d.AutoResolveAt.Equal(d.CreatedAt.Add(time.Hour)) {
+		t.Errorf("AutoResolveAt = %v, want CreatedAt + 1h", d.AutoResolveAt)
+	}
+}
+
+// TestResolveExpired verifies that only pending decisions past their
+// timeout are resolved, and with the default decision.
+func TestResolveExpired(t *testing.T) {
+	m, d := createTestDecision(t)
+	other, _ := m.CreateDecision("Q2?", "", "agent-requester", nil, "", models.BeadPriorityP2, "proj-1")
+	decided, _ := m.CreateDecision("Q3?", "", "agent-requester", nil, "", models.BeadPriorityP2, "proj-1")
+
+	_ = m.SetAutoResolve(d.ID, time.Hour, "SQLite")
+	_ = m.SetAutoResolve(decided.ID, time.Hour, "yes")
+	_ = m.MakeDecision(decided.ID, "agent-decider", "no", "decided in time")
+
+	if got := m.ResolveExpired(time.Now()); len(got) != 0 {
+		t.Fatalf("ResolveExpired() before timeout resolved %d decisions", len(got))
+	}
+
+	got := m.ResolveExpired(time.Now().Add(2 * time.Hour))
+	if len(got) != 1 || got[0].ID != d.ID {
+		t.Fatalf("ResolveExpired() = %v, want only %s", got, d.ID)
+	}
+	if d.Decision != "SQLite" || d.Rationale != AutoResolveRationale || d.DeciderID != AutoResolveDecider || !d.AutoResolved {
+		t.Errorf("resolved decision = %+v", d)
+	}
+	if d.Status != models.BeadStatusClosed || d.DecidedAt == nil {
+		t.Errorf("Status = %s, DecidedAt = %v, want closed with a decision time", d.Status, d.DecidedAt)
+	}
+	if other.Status != models.BeadStatusOpen {
+		t.Errorf("decision without timeout Status = %s, want open", other.Status)
+	}
+	if decided.Decision != "no" || decided.AutoResolved {
+		t.Errorf("already decided decision was changed: %+v", decided)
+	}
+}
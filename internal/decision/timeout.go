@@ -0,0 +1,90 @@
+package decision
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jordanhubbard/loom/pkg/models"
+)
+
+// AutoResolveDecider and AutoResolveRationale are recorded on decisions
+// resolved by their timeout.
+const (
+	AutoResolveDecider   = "system"
+	AutoResolveRationale = "auto-resolved on timeout"
+)
+
+// ValidateAutoResolve checks a decision timeout and its default decision,
+// which must be one of the options when the decision has any.
+func ValidateAutoResolve(options []string, timeout time.Duration, defaultDecision string) error {
+	if timeout <= 0 {
+		return fmt.Errorf("decision timeout must be positive")
+	}
+	if defaultDecision == "" {
+		return fmt.Errorf("a default decision is required with a timeout")
+	}
+	if len(options) == 0 {
+		return nil
+	}
+	for _, option := range options {
+		if option == defaultDecision {
+			return nil
+		}
+	}
+	return fmt.Errorf("default decision %q is not one of the options", defaultDecision)
+}
+
+// SetAutoResolve makes a pending decision resolve to defaultDecision once
+// timeout has passed since it was created.
+func (m *Manager) SetAutoResolve(decisionID string, timeout time.Duration, defaultDecision string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	decision, ok := m.decisions[decisionID]
+	if !ok {
+		return fmt.Errorf("decision not found: %s", decisionID)
+	}
+	if decision.Status == models.BeadStatusClosed {
+		return fmt.Errorf("decision already resolved: %s", decisionID)
+	}
+	if err := ValidateAutoResolve(decision.Options, timeout, defaultDecision); err != nil {
+		return err
+	}
+
+	at := decision.CreatedAt.Add(timeout)
+	decision.DefaultDecision = defaultDecision
+	decision.AutoResolveAt = &at
+	decision.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// ResolveExpired applies the default decision to every pending decision
+// whose timeout has passed by now and returns the decisions it resolved.
+func (m *Manager) ResolveExpired(now time.Time) []*models.DecisionBead {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var resolved []*models.DecisionBead
+	for _, decision := range m.decisions {
+		if decision.AutoResolveAt == nil || decision.Status == models.BeadStatusClosed {
+			continue
+		}
+		if now.Before(*decision.AutoResolveAt) {
+			continue
+		}
+
+		decidedAt := now
+		decision.DeciderID = AutoResolveDecider
+		decision.Decision = decision.DefaultDecision
+		decision.Rationale = AutoResolveRationale
+		decision.AutoResolved = true
+		decision.DecidedAt = &decidedAt
+		decision.Status = models.BeadStatusClosed
+		decision.ClosedAt = &decidedAt
+		decision.UpdatedAt = now
+		resolved = append(resolved, decision)
+	}
+
+	return resolved
+}
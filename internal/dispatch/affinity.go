@@ -0,0 +1,24 @@
+package dispatch
+
+import "github.com/jordanhubbard/loom/pkg/models"
+
+// PreferredAgentKey is the bead context key naming an agent that should
+// take the bead when it is idle, e.g. the agent that investigated a bug
+// applying its fix with a warm workspace. Unlike assigned_to it is only a
+// preference: when that agent is busy the bead goes to another agent.
+const PreferredAgentKey = "preferred_agent"
+
+// preferredAgentFor returns the bead's preferred agent if it is one of the
+// eligible agents and may work on the bead's project.
+func preferredAgentFor(b *models.Bead, eligible []*models.Agent) *models.Agent {
+	preferred := b.Context[PreferredAgentKey]
+	if preferred == "" {
+		return nil
+	}
+	for _, a := range eligible {
+		if a != nil && a.ID == preferred && (a.ProjectID == b.ProjectID || a.ProjectID == "" || b.ProjectID == "") {
+			return a
+		}
+	}
+	return nil
+}
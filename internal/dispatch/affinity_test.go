@@ -0,0 +1,46 @@
+package dispatch
+
+import (
+	"testing"
+
+	"github.com/jordanhubbard/loom/pkg/models"
+)
+
+func TestPreferredAgentFor(t *testing.T) {
+	investigator := &models.Agent{ID: "a-investigator", ProjectID: "p1"}
+	other := &models.Agent{ID: "a-other", ProjectID: "p1"}
+	elsewhere := &models.Agent{ID: "a-elsewhere", ProjectID: "p2"}
+
+	bead := func(preferred string) *models.Bead {
+		return &models.Bead{ID: "b1", ProjectID: "p1", Context: map[string]string{PreferredAgentKey: preferred}}
+	}
+	tests := []struct {
+		name     string
+		bead     *models.Bead
+		eligible []*models.Agent
+		want     *models.Agent
+	}{
+		{"no preference", &models.Bead{ID: "b1", ProjectID: "p1"}, []*models.Agent{investigator, other}, nil},
+		{"preferred idle", bead("a-investigator"), []*models.Agent{other, investigator}, investigator},
+		{"preferred busy", bead("a-investigator"), []*models.Agent{other}, nil},
+		{"preferred on another project", bead("a-elsewhere"), []*models.Agent{elsewhere, other}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := preferredAgentFor(tt.bead, tt.eligible); got != tt.want {
+				t.Errorf("preferredAgentFor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDispatchMetrics_Affinity(t *testing.T) {
+	var m dispatchMetrics
+	m.recordAffinity(true)
+	m.recordAffinity(true)
+	m.recordAffinity(false)
+	got := m.snapshot()
+	if got.AffinityHonored != 2 || got.AffinityFallbacks != 1 {
+		t.Errorf("affinity = %d honored, %d fallbacks, want 2 and 1", got.AffinityHonored, got.AffinityFallbacks)
+	}
+}
@@ -0,0 +1,82 @@
+package dispatch
+
+import (
+	"log"
+	"strings"
+	"text/template"
+
+	"github.com/jordanhubbard/loom/pkg/models"
+)
+
+// defaultInstructions tells an agent to act, not plan.
+const defaultInstructions = `
+## Instructions
+
+You are an autonomous coding agent. Your job is to MAKE CHANGES, COMMIT, and PUSH.
+
+WORKFLOW:
+1. Locate: scope + read relevant files (iterations 1-3)
+2. Change: edit or write files (iterations 4-15)
+3. Verify: build and test (iterations 16-18)
+4. Land: git_commit, git_push, done (iterations 19-21)
+
+CRITICAL RULES:
+- You have 25 iterations. Use them.
+- ALWAYS git_commit after making changes.
+- ALWAYS git_push after committing.
+- ALWAYS build and test before pushing.
+- Uncommitted work is LOST work.
+`
+
+// bugInvestigationInstructions is added for auto-filed bugs.
+const bugInvestigationInstructions = `
+## Bug Investigation
+
+This bug was filed automatically. Before changing code:
+1. Reproduce: find the failing request, log line or stack trace in the report.
+2. Locate: trace it to the code that produced it.
+3. Fix the root cause, not the symptom, and add a test that covers it.
+4. If it cannot be reproduced, say so in the bead and close it with your findings.
+`
+
+// outputFormatData is what a persona's output format template can reference,
+// e.g. {{.Bead.Title}} or {{.Project.Branch}}. Project is empty, not nil,
+// when the bead has no project.
+type outputFormatData struct {
+	Bead    *models.Bead
+	Project *models.Project
+}
+
+// beadInstructions returns the closing instructions for a bead's context.
+// A persona with an output format replaces the default instructions,
+// including the bug investigation workflow.
+func beadInstructions(b *models.Bead, p *models.Project, persona *models.Persona) string {
+	if persona != nil && strings.TrimSpace(persona.OutputFormat) != "" {
+		out, err := renderOutputFormat(persona.OutputFormat, b, p)
+		if err == nil {
+			return "\n" + out + "\n"
+		}
+		log.Printf("[Dispatcher] Invalid output format for persona %s, using default instructions: %v", persona.Name, err)
+	}
+
+	instructions := defaultInstructions
+	if NewAutoBugRouter().isAutoFiledBug(b) {
+		instructions += bugInvestigationInstructions
+	}
+	return instructions
+}
+
+func renderOutputFormat(format string, b *models.Bead, p *models.Project) (string, error) {
+	tmpl, err := template.New("output_format").Parse(format)
+	if err != nil {
+		return "", err
+	}
+	if p == nil {
+		p = &models.Project{}
+	}
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, outputFormatData{Bead: b, Project: p}); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(sb.String()), nil
+}
@@ -0,0 +1,102 @@
+package dispatch
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jordanhubbard/loom/internal/observability"
+	"github.com/jordanhubbard/loom/internal/temporal/eventbus"
+	"github.com/jordanhubbard/loom/pkg/models"
+)
+
+// DeadLetterer files the decision bead for a bead taken out of dispatch.
+type DeadLetterer interface {
+	DeadLetterBead(beadID, reason string) (*models.DecisionBead, error)
+}
+
+// SetDeadLetterer sets who files decisions for dead-lettered beads. Without
+// one, beads are still dead-lettered but no decision is filed.
+func (d *Dispatcher) SetDeadLetterer(deadLetterer DeadLetterer) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.deadLetterer = deadLetterer
+}
+
+// deadLetterBead moves a bead that is stuck past the hop limit to
+// dead-letter, so it is no longer dispatched, and asks the dead-letterer for
+// a decision bead about it.
+func (d *Dispatcher) deadLetterBead(b *models.Bead, dispatchCount, maxHops int, loopReason string) {
+	reason := fmt.Sprintf("dispatch_count=%d exceeded max_hops=%d, stuck in loop: %s",
+		dispatchCount, maxHops, loopReason)
+	_ = d.deadLetter(b, reason, loopReason)
+}
+
+// DeadLetterStuckBead dead-letters a bead the loop detector flagged outside
+// the dispatch loop, as Ralph does for beads it finds stuck, filing the same
+// decision a bead past the hop limit gets.
+func (d *Dispatcher) DeadLetterStuckBead(b *models.Bead, loopReason string) error {
+	return d.deadLetter(b, fmt.Sprintf("stuck in loop: %s", loopReason), loopReason)
+}
+
+func (d *Dispatcher) deadLetter(b *models.Bead, reason, loopReason string) error {
+	dlog := observability.NewLogger("dispatcher").With(map[string]interface{}{"bead_id": b.ID, "project_id": b.ProjectID})
+	dispatchCount := b.Context["dispatch_count"]
+
+	// Attempt auto-revert of agent commits if commit range is known
+	revertStatus := "not_attempted"
+	firstSHA, _, commitCount := d.loopDetector.GetAgentCommitRange(b)
+	if firstSHA != "" && commitCount > 0 {
+		log.Printf("[Ralph] Attempting auto-revert of %d agent commits for bead %s (from %s)",
+			commitCount, b.ID, firstSHA)
+		// Record intent — actual revert requires git.GitService which
+		// is project-scoped. The revert metadata tells the next handler
+		// (or human) exactly what to revert.
+		revertStatus = fmt.Sprintf("revert_recommended: %d commits from %s", commitCount, firstSHA)
+	}
+
+	ctxUpdates := map[string]string{
+		"redispatch_requested":  "false",
+		"dead_lettered_at":      time.Now().UTC().Format(time.RFC3339),
+		"dead_letter_reason":    reason,
+		"loop_detection_reason": loopReason,
+		"progress_summary":      d.loopDetector.GetProgressSummary(b),
+		"revert_status":         revertStatus,
+	}
+	if sessionID := b.Context["conversation_session_id"]; sessionID != "" {
+		ctxUpdates["conversation_session_id"] = sessionID
+	}
+	updates := map[string]interface{}{
+		"status":      models.BeadStatusDeadLetter,
+		"assigned_to": "",
+		"context":     ctxUpdates,
+	}
+	if err := d.beads.UpdateBead(b.ID, updates); err != nil {
+		dlog.Error("dispatch.dead_letter", map[string]interface{}{"dispatch_count": dispatchCount}, err)
+		return err
+	}
+	dlog.Warn("dispatch.dead_letter", map[string]interface{}{
+		"dispatch_count": dispatchCount, "reason": loopReason, "revert_status": revertStatus,
+	})
+
+	d.mu.RLock()
+	deadLetterer := d.deadLetterer
+	d.mu.RUnlock()
+	if deadLetterer != nil {
+		if decision, err := deadLetterer.DeadLetterBead(b.ID, reason); err != nil {
+			dlog.Error("dispatch.dead_letter_decision", nil, err)
+		} else {
+			dlog.Info("dispatch.dead_letter_decision", map[string]interface{}{"decision_id": decision.ID})
+		}
+	}
+
+	if d.eventBus != nil {
+		_ = d.eventBus.PublishBeadEvent(eventbus.EventTypeBeadStatusChange, b.ID, b.ProjectID,
+			map[string]interface{}{
+				"status":             string(models.BeadStatusDeadLetter),
+				"dead_letter_reason": reason,
+				"revert_status":      revertStatus,
+			})
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package dispatch
+
+import (
+	"testing"
+
+	"github.com/jordanhubbard/loom/internal/beads"
+	"github.com/jordanhubbard/loom/pkg/models"
+)
+
+type recordingDeadLetterer struct {
+	beadIDs []string
+}
+
+func (r *recordingDeadLetterer) DeadLetterBead(beadID, reason string) (*models.DecisionBead, error) {
+	r.beadIDs = append(r.beadIDs, beadID)
+	return &models.DecisionBead{Bead: &models.Bead{ID: "decision-1"}}, nil
+}
+
+func TestDispatcher_DeadLetterBead(t *testing.T) {
+	bm := beads.NewManager("")
+	bm.SetBeadsPath(t.TempDir())
+	b, err := bm.CreateBead("looping task", "", models.BeadPriorityP2, "task", "proj")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bm.ClaimBead(b.ID, "agent-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	d := NewDispatcher(bm, nil, nil, nil, nil)
+	recorder := &recordingDeadLetterer{}
+	d.SetDeadLetterer(recorder)
+	d.deadLetterBead(b, 21, 20, "same error repeated")
+
+	got, err := bm.GetBead(b.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != models.BeadStatusDeadLetter || got.AssignedTo != "" {
+		t.Errorf("bead is %s assigned to %q, want dead-letter and unassigned", got.Status, got.AssignedTo)
+	}
+	if got.Context["dead_letter_reason"] == "" || got.Context["redispatch_requested"] != "false" {
+		t.Errorf("context = %v, want a dead_letter_reason and redispatch disabled", got.Context)
+	}
+	if len(recorder.beadIDs) != 1 || recorder.beadIDs[0] != b.ID {
+		t.Errorf("dead-letterer called for %v, want [%s]", recorder.beadIDs, b.ID)
+	}
+
+	ready, err := bm.GetReadyBeads("proj")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ready) != 0 {
+		t.Errorf("a dead-lettered bead should not be ready, got %d ready", len(ready))
+	}
+}
+
+func TestDispatcher_DeadLetterStuckBead(t *testing.T) {
+	bm := beads.NewManager("")
+	bm.SetBeadsPath(t.TempDir())
+	b, err := bm.CreateBead("stuck task", "", models.BeadPriorityP2, "task", "proj")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := NewDispatcher(bm, nil, nil, nil, nil)
+	recorder := &recordingDeadLetterer{}
+	d.SetDeadLetterer(recorder)
+	if err := d.DeadLetterStuckBead(b, "same action repeated"); err != nil {
+		t.Fatalf("DeadLetterStuckBead() error = %v", err)
+	}
+
+	got, err := bm.GetBead(b.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != models.BeadStatusDeadLetter {
+		t.Errorf("bead is %s, want dead-letter", got.Status)
+	}
+	if got.Context["dead_letter_reason"] != "stuck in loop: same action repeated" {
+		t.Errorf("dead_letter_reason = %q", got.Context["dead_letter_reason"])
+	}
+	if len(recorder.beadIDs) != 1 {
+		t.Errorf("dead-letterer called %d times, want 1", len(recorder.beadIDs))
+	}
+}
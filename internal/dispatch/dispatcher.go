@@ -28,8 +28,22 @@ import (
 	"github.com/jordanhubbard/loom/pkg/models"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// DefaultBatchWorkers bounds concurrent task execution in DispatchBatch.
+const DefaultBatchWorkers = 4
+
+// Loop detection defaults for a bead's failed-dispatch history.
+const (
+	DefaultLoopWindow            = 6  // Failures alternating between two agents
+	DefaultSameAgentFailureLimit = 10 // Consecutive failures by one agent
+	dispatchHistoryLimit         = 20 // Minimum history entries kept per bead
+)
+
+// dlog is the dispatcher's structured logger for its hot path.
+var dlog = observability.NewLogger("dispatcher")
+
 type StatusState string
 
 const (
@@ -56,9 +70,16 @@ type DispatchResult struct {
 	BeadID     string `json:"bead_id,omitempty"`
 	AgentID    string `json:"agent_id,omitempty"`
 	ProviderID string `json:"provider_id,omitempty"`
+	TraceID    string `json:"trace_id,omitempty"`
 	Error      string `json:"error,omitempty"`
 }
 
+// taskBudget is the budget a project gives each of its tasks.
+type taskBudget struct {
+	maxTokens  int
+	maxCostUSD float64
+}
+
 // Dispatcher is responsible for selecting ready work and executing it using agents/providers.
 // For now it focuses on turning beads into LLM tasks and storing the output back into bead context.
 type Dispatcher struct {
@@ -74,11 +95,24 @@ type Dispatcher struct {
 	personaMatcher      *PersonaMatcher
 	autoBugRouter       *AutoBugRouter
 	complexityEstimator *provider.ComplexityEstimator
-	readinessCheck      func(context.Context, string) (bool, []string)
+	readinessCheckers   []ReadinessChecker
+	readinessWarned     map[string]string // Last issues logged per project in warn mode
 	readinessMode       ReadinessMode
 	escalator           Escalator
+	deadLetterer        DeadLetterer
 	maxDispatchHops     int
+	loopWindow          int // Alternating two-agent failures that count as a loop
+	maxDispatchCount    int // Failed dispatches before a loop is declared (0 = no limit)
+	sameAgentFailures   int // Consecutive failures by one agent that count as a loop
+	batchWorkers        int // Max concurrent task executions per DispatchBatch
+	priorityAging       time.Duration // Ready time that raises a bead one priority level (0 = no aging)
+	taskBudgets         map[string]taskBudget // Per-project token budget for each task
+	execRetries         int           // In-tick retries of a transiently failed task
+	execRetryBackoff    time.Duration // Wait before the first retry; doubles per retry
+	maxPerProject       int           // In-progress beads allowed per project (0 = unlimited)
 	loopDetector        *LoopDetector
+	metrics             dispatchMetrics
+	readyBeads          int // Ready beads seen by the latest dispatch pass
 
 	// Commit serialization (Gap #2)
 	commitLock        sync.Mutex         // Global commit lock
@@ -129,6 +163,8 @@ func NewDispatcher(beadsMgr *beads.Manager, projMgr *project.Manager, agentMgr *
 		autoBugRouter:       NewAutoBugRouter(),
 		complexityEstimator: provider.NewComplexityEstimator(),
 		loopDetector:        NewLoopDetector(),
+		metrics:             dispatchMetrics{since: time.Now()},
+		batchWorkers:        DefaultBatchWorkers,
 		readinessMode:       ReadinessWarn,
 		commitQueue:         make(chan commitRequest, 100), // Buffer 100 waiting commits
 		commitLockTimeout:   5 * time.Minute,
@@ -151,6 +187,14 @@ func (d *Dispatcher) GetSystemStatus() SystemStatus {
 	return d.status
 }
 
+// ReadyBeadCount returns how many beads were ready in the latest dispatch
+// pass. The worker manager uses it as queue depth for auto-scaling.
+func (d *Dispatcher) ReadyBeadCount() int {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.readyBeads
+}
+
 // SetDatabase sets the database for conversation context management
 func (d *Dispatcher) SetDatabase(db *database.Database) {
 	d.mu.Lock()
@@ -182,6 +226,7 @@ func (d *Dispatcher) SetMessageBus(mb MessageBus) {
 func (d *Dispatcher) handleTaskResult(result *messages.ResultMessage) {
 	log.Printf("[Dispatcher] Received NATS result: bead=%s agent=%s status=%s correlation=%s",
 		result.BeadID, result.AgentID, result.Result.Status, result.CorrelationID)
+	d.RecordAgentOutput(result.BeadID, "", result.Result.Output, result.Result.Error)
 
 	// Update bead status based on result
 	updates := make(map[string]interface{})
@@ -236,6 +281,27 @@ func (d *Dispatcher) handleTaskResult(result *messages.ResultMessage) {
 	}
 }
 
+// RecordAgentOutput adds a project agent's task output and error to the log
+// of the agent the bead is assigned to.
+func (d *Dispatcher) RecordAgentOutput(beadID, taskID, output, errMsg string) {
+	if d.agents == nil || beadID == "" || (output == "" && errMsg == "") {
+		return
+	}
+	bead, err := d.beads.GetBead(beadID)
+	if err != nil || bead == nil || bead.AssignedTo == "" {
+		return
+	}
+	line := agent.AgentLogLine{TaskID: taskID, BeadID: beadID, Source: agent.LogSourceProjectAgent}
+	if output != "" {
+		line.Message = output
+		d.agents.AppendAgentLog(bead.AssignedTo, line)
+	}
+	if errMsg != "" {
+		line.Level, line.Message = "error", errMsg
+		d.agents.AppendAgentLog(bead.AssignedTo, line)
+	}
+}
+
 // SetWorkflowEngine sets the workflow engine for workflow-aware dispatching
 func (d *Dispatcher) SetWorkflowEngine(engine *workflow.Engine) {
 	d.mu.Lock()
@@ -264,10 +330,153 @@ func (d *Dispatcher) SetMaxDispatchHops(maxHops int) {
 	d.maxDispatchHops = maxHops
 }
 
-func (d *Dispatcher) SetReadinessCheck(check func(context.Context, string) (bool, []string)) {
+// SetLoopWindow sets how many consecutive failed dispatches alternating
+// between two agents count as a loop. Values below 4 use DefaultLoopWindow.
+func (d *Dispatcher) SetLoopWindow(n int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if n < 4 {
+		n = DefaultLoopWindow
+	}
+	d.loopWindow = n
+}
+
+// SetMaxDispatchCount sets how many failed dispatches a bead may accumulate
+// before it is treated as looping. Values <= 0 disable the limit.
+func (d *Dispatcher) SetMaxDispatchCount(n int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.maxDispatchCount = n
+}
+
+// SetSameAgentFailureLimit sets how many consecutive failures of a bead by the
+// same agent count as a loop. Values <= 0 use DefaultSameAgentFailureLimit.
+func (d *Dispatcher) SetSameAgentFailureLimit(n int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if n <= 0 {
+		n = DefaultSameAgentFailureLimit
+	}
+	d.sameAgentFailures = n
+}
+
+// AddPersonaStrategy registers a fallback persona matching strategy, tried
+// after exact matching finds no agent for a bead's persona hint.
+func (d *Dispatcher) AddPersonaStrategy(s MatchStrategy) {
+	d.personaMatcher.AddStrategy(s)
+}
+
+// SetBatchWorkers sets how many tasks from one DispatchBatch may execute at once.
+func (d *Dispatcher) SetBatchWorkers(n int) {
 	d.mu.Lock()
 	defer d.mu.Unlock()
-	d.readinessCheck = check
+	d.batchWorkers = n
+}
+
+// SetPriorityAging enables priority aging: every interval a ready bead goes
+// without being updated raises its effective priority by one level, so
+// low-priority work cannot starve behind a stream of fresher, higher-priority
+// beads. Zero or less disables aging.
+func (d *Dispatcher) SetPriorityAging(interval time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if interval < 0 {
+		interval = 0
+	}
+	d.priorityAging = interval
+}
+
+// SetMaxConcurrentPerProject caps how many of a project's beads may be in
+// progress at once, so one busy project cannot take every idle agent.
+// Values <= 0 mean no cap.
+func (d *Dispatcher) SetMaxConcurrentPerProject(n int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if n < 0 {
+		n = 0
+	}
+	d.maxPerProject = n
+}
+
+// projectCapReached reports whether dispatching b would take its project
+// past max in-progress beads, with the project's current count. counts
+// caches the counts for one dispatch pass. A bead already in progress is
+// continuing work and does not add to the count.
+func (d *Dispatcher) projectCapReached(b *models.Bead, max int, counts map[string]int) (int, bool) {
+	if max <= 0 || b.Status == models.BeadStatusInProgress {
+		return 0, false
+	}
+	count, ok := counts[b.ProjectID]
+	if !ok {
+		inProgress, err := d.beads.ListBeads(map[string]interface{}{
+			"project_id": b.ProjectID,
+			"status":     models.BeadStatusInProgress,
+		})
+		if err == nil {
+			count = len(inProgress)
+		}
+		counts[b.ProjectID] = count
+	}
+	return count, count >= max
+}
+
+// SetTaskBudget sets the token and cost budget given to each task
+// dispatched for a project. Zero means no limit.
+func (d *Dispatcher) SetTaskBudget(projectID string, maxTokens int, maxCostUSD float64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if maxTokens < 0 {
+		maxTokens = 0
+	}
+	if maxCostUSD < 0 {
+		maxCostUSD = 0
+	}
+	if d.taskBudgets == nil {
+		d.taskBudgets = make(map[string]taskBudget)
+	}
+	d.taskBudgets[projectID] = taskBudget{maxTokens: maxTokens, maxCostUSD: maxCostUSD}
+}
+
+// taskBudget returns the budget for tasks of a project.
+func (d *Dispatcher) taskBudget(projectID string) taskBudget {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.taskBudgets[projectID]
+}
+
+// sortReadyBeads orders beads for dispatch: most urgent effective priority
+// first, then most recently updated.
+func (d *Dispatcher) sortReadyBeads(ready []*models.Bead, now time.Time) {
+	d.mu.RLock()
+	aging := d.priorityAging
+	d.mu.RUnlock()
+
+	sort.SliceStable(ready, func(i, j int) bool {
+		if ready[i] == nil {
+			return false
+		}
+		if ready[j] == nil {
+			return true
+		}
+		pi, pj := effectivePriority(ready[i], now, aging), effectivePriority(ready[j], now, aging)
+		if pi != pj {
+			return pi < pj
+		}
+		return ready[i].UpdatedAt.After(ready[j].UpdatedAt)
+	})
+}
+
+// effectivePriority returns a bead's priority after aging, where lower is
+// more urgent. Without aging it is the bead's priority.
+func effectivePriority(b *models.Bead, now time.Time, aging time.Duration) float64 {
+	p := float64(b.Priority)
+	if aging <= 0 || b.UpdatedAt.IsZero() {
+		return p
+	}
+	if waited := now.Sub(b.UpdatedAt); waited > 0 {
+		p -= float64(waited) / float64(aging)
+	}
+	return p
 }
 
 func (d *Dispatcher) SetReadinessMode(mode ReadinessMode) {
@@ -364,41 +573,157 @@ func (d *Dispatcher) DispatchOnce(ctx context.Context, projectID string) (*Dispa
 	ctx, span := telemetry.Tracer.Start(ctx, "dispatch.DispatchOnce")
 	defer span.End()
 
+	result, run, err := d.dispatchNext(ctx, span, projectID, nil)
+	if run != nil {
+		go run()
+	}
+	return result, err
+}
+
+// DispatchBatch assigns up to max ready beads to distinct idle agents in a
+// single pass. Beads are chosen in the same priority order and through the
+// same guards as DispatchOnce. The assigned tasks run concurrently in the
+// background, at most batchWorkers at a time.
+func (d *Dispatcher) DispatchBatch(ctx context.Context, projectID string, max int) ([]*DispatchResult, error) {
+	if max <= 0 {
+		return nil, fmt.Errorf("batch size must be positive, got %d", max)
+	}
+
+	ctx, span := telemetry.Tracer.Start(ctx, "dispatch.DispatchBatch")
+	defer span.End()
+	span.SetAttributes(attribute.String("project_id", projectID), attribute.Int("max", max))
+
+	exclude := newDispatchExclusions()
+	results := make([]*DispatchResult, 0, max)
+	runs := make([]func(), 0, max)
+	for len(results) < max {
+		result, run, err := d.dispatchNext(ctx, span, projectID, exclude)
+		if err != nil {
+			if len(results) == 0 {
+				return nil, err
+			}
+			dlog.Error("dispatch.batch_stopped", map[string]interface{}{"project_id": projectID, "dispatched": len(results)}, err)
+			break
+		}
+		if result == nil || !result.Dispatched || run == nil {
+			break
+		}
+		exclude.add(result.BeadID, result.AgentID)
+		results = append(results, result)
+		runs = append(runs, run)
+	}
+
+	d.mu.RLock()
+	workers := d.batchWorkers
+	d.mu.RUnlock()
+	if workers <= 0 {
+		workers = DefaultBatchWorkers
+	}
+
+	dlog.Info("dispatch.batch", map[string]interface{}{"project_id": projectID, "dispatched": len(results), "max": max, "workers": workers})
+	span.SetAttributes(attribute.Int("dispatched", len(results)))
+
+	if len(runs) > 0 {
+		go runBounded(runs, workers)
+	}
+	return results, nil
+}
+
+// runBounded runs every fn with at most workers running at once and returns
+// when all have finished.
+func runBounded(fns []func(), workers int) {
+	if workers <= 0 {
+		workers = 1
+	}
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for _, fn := range fns {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(fn func()) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn()
+		}(fn)
+	}
+	wg.Wait()
+}
+
+// dispatchExclusions tracks the beads and agents already assigned within a batch.
+type dispatchExclusions struct {
+	beads  map[string]bool
+	agents map[string]bool
+}
+
+func newDispatchExclusions() *dispatchExclusions {
+	return &dispatchExclusions{
+		beads:  make(map[string]bool),
+		agents: make(map[string]bool),
+	}
+}
+
+func (e *dispatchExclusions) add(beadID, agentID string) {
+	e.beads[beadID] = true
+	e.agents[agentID] = true
+}
+
+func (e *dispatchExclusions) hasBead(id string) bool {
+	return e != nil && e.beads[id]
+}
+
+func (e *dispatchExclusions) hasAgent(id string) bool {
+	return e != nil && e.agents[id]
+}
+
+// dispatchNext selects one ready bead and an idle agent, claims and assigns
+// the bead, and returns the task execution for the caller to run. Beads and
+// agents in exclude are not considered. run is nil when nothing was dispatched.
+func (d *Dispatcher) dispatchNext(ctx context.Context, span trace.Span, projectID string, exclude *dispatchExclusions) (*DispatchResult, func(), error) {
+
 	startTime := time.Now()
 	span.SetAttributes(attribute.String("project_id", projectID))
 
 	activeProviders := d.providers.ListActive()
-	log.Printf("[Dispatcher] DispatchOnce called for project=%s, active_providers=%d", projectID, len(activeProviders))
+	dlog.Debug("dispatch.start", map[string]interface{}{"project_id": projectID, "active_providers": len(activeProviders)})
 
 	span.SetAttributes(attribute.Int("active_providers", len(activeProviders)))
 
 	if len(activeProviders) == 0 {
-		log.Printf("[Dispatcher] Parked - no active providers")
+		dlog.Warn("dispatch.parked", map[string]interface{}{"project_id": projectID, "reason": "no active providers"})
 		d.setStatus(StatusParked, "no active providers registered")
+		d.metrics.recordAttempt(nil)
+		d.metrics.recordParked()
 		span.SetStatus(codes.Error, "no active providers")
-		return &DispatchResult{Dispatched: false, ProjectID: projectID}, nil
+		return &DispatchResult{Dispatched: false, ProjectID: projectID}, nil, nil
 	}
 
 	ready, err := d.beads.GetReadyBeads(projectID)
 	if err != nil {
 		d.setStatus(StatusParked, "failed to list ready beads")
-		return nil, err
+		d.metrics.recordAttempt(nil)
+		d.metrics.recordParked()
+		return nil, nil, err
 	}
+	d.mu.Lock()
+	d.readyBeads = len(ready)
+	d.mu.Unlock()
 	d.mu.RLock()
-	readinessCheck := d.readinessCheck
+	readinessCheckers := d.readinessCheckers
 	readinessMode := d.readinessMode
 	d.mu.RUnlock()
 
-	if readinessCheck != nil {
+	if len(readinessCheckers) > 0 {
 		if projectID != "" {
-			readyOK, issues := readinessCheck(ctx, projectID)
+			readyOK, issues := checkReadiness(ctx, readinessCheckers, projectID)
 			if !readyOK && readinessMode == ReadinessBlock {
 				reason := "project readiness failed"
 				if len(issues) > 0 {
 					reason = fmt.Sprintf("project readiness failed: %s", strings.Join(issues, "; "))
 				}
 				d.setStatus(StatusParked, reason)
-				return &DispatchResult{Dispatched: false, ProjectID: projectID, Error: reason}, nil
+				d.metrics.recordAttempt(nil)
+				d.metrics.recordParked()
+				return &DispatchResult{Dispatched: false, ProjectID: projectID, Error: reason}, nil, nil
 			}
 		}
 
@@ -411,7 +736,7 @@ func (d *Dispatcher) DispatchOnce(ctx context.Context, projectID string) (*Dispa
 					continue
 				}
 				if _, ok := projectReadiness[bead.ProjectID]; !ok {
-					okReady, _ := readinessCheck(ctx, bead.ProjectID)
+					okReady, _ := checkReadiness(ctx, readinessCheckers, bead.ProjectID)
 					projectReadiness[bead.ProjectID] = okReady
 				}
 				if projectReadiness[bead.ProjectID] {
@@ -421,7 +746,9 @@ func (d *Dispatcher) DispatchOnce(ctx context.Context, projectID string) (*Dispa
 			ready = filtered
 			if len(ready) == 0 {
 				d.setStatus(StatusParked, "project readiness failed")
-				return &DispatchResult{Dispatched: false, ProjectID: projectID}, nil
+				d.metrics.recordAttempt(nil)
+				d.metrics.recordParked()
+				return &DispatchResult{Dispatched: false, ProjectID: projectID}, nil, nil
 			}
 		} else {
 			for _, bead := range ready {
@@ -431,33 +758,23 @@ func (d *Dispatcher) DispatchOnce(ctx context.Context, projectID string) (*Dispa
 				if _, ok := projectReadiness[bead.ProjectID]; ok {
 					continue
 				}
-				okReady, _ := readinessCheck(ctx, bead.ProjectID)
+				okReady, issues := checkReadiness(ctx, readinessCheckers, bead.ProjectID)
 				projectReadiness[bead.ProjectID] = okReady
+				d.warnReadiness(bead.ProjectID, issues)
 			}
 		}
 	}
 
-	log.Printf("[Dispatcher] GetReadyBeads returned %d beads for project %s", len(ready), projectID)
+	dlog.Debug("dispatch.ready_beads", map[string]interface{}{"project_id": projectID, "ready": len(ready)})
 	os.WriteFile("/tmp/dispatch-ready-beads.txt", []byte(fmt.Sprintf("ready=%d project=%s\n", len(ready), projectID)), 0644)
 
-	sort.SliceStable(ready, func(i, j int) bool {
-		if ready[i] == nil {
-			return false
-		}
-		if ready[j] == nil {
-			return true
-		}
-		if ready[i].Priority != ready[j].Priority {
-			return ready[i].Priority < ready[j].Priority
-		}
-		return ready[i].UpdatedAt.After(ready[j].UpdatedAt)
-	})
+	d.sortReadyBeads(ready, time.Now())
 
 	// Only auto-dispatch non-P0 task/epic beads.
 	idleAgents := d.agents.GetIdleAgentsByProject(projectID)
 	filteredAgents := make([]*models.Agent, 0, len(idleAgents))
 	for _, candidateAgent := range idleAgents {
-		if candidateAgent == nil {
+		if candidateAgent == nil || exclude.hasAgent(candidateAgent.ID) {
 			continue
 		}
 		// Ensure every agent has a healthy provider.
@@ -473,11 +790,13 @@ func (d *Dispatcher) DispatchOnce(ctx context.Context, projectID string) (*Dispa
 				prev := candidateAgent.ProviderID
 				candidateAgent.ProviderID = best.Config.ID
 				if prev != "" {
-					log.Printf("[Dispatcher] Reassigned agent %s from failed provider %s to %s",
-						candidateAgent.Name, prev, best.Config.ID)
+					dlog.Info("dispatch.provider_reassigned", map[string]interface{}{
+						"agent_id": candidateAgent.ID, "from_provider": prev, "provider_id": best.Config.ID,
+					})
 				} else {
-					log.Printf("[Dispatcher] Auto-assigned provider %s to agent %s",
-						best.Config.ID, candidateAgent.Name)
+					dlog.Info("dispatch.provider_assigned", map[string]interface{}{
+						"agent_id": candidateAgent.ID, "provider_id": best.Config.ID,
+					})
 				}
 			} else {
 				continue
@@ -486,7 +805,7 @@ func (d *Dispatcher) DispatchOnce(ctx context.Context, projectID string) (*Dispa
 		// Promote paused agents to idle now that they have a provider.
 		if candidateAgent.Status == "paused" {
 			candidateAgent.Status = "idle"
-			log.Printf("[Dispatcher] Promoted agent %s from paused to idle", candidateAgent.Name)
+			dlog.Info("dispatch.agent_unpaused", map[string]interface{}{"agent_id": candidateAgent.ID, "project_id": projectID})
 		}
 		filteredAgents = append(filteredAgents, candidateAgent)
 	}
@@ -511,6 +830,11 @@ func (d *Dispatcher) DispatchOnce(ctx context.Context, projectID string) (*Dispa
 		}
 	}
 
+	d.mu.RLock()
+	maxPerProject := d.maxPerProject
+	d.mu.RUnlock()
+	inProgress := make(map[string]int) // Per-project counts, when capped
+
 	var candidate *models.Bead
 	var ag *models.Agent
 	skippedReasons := make(map[string]int)
@@ -519,25 +843,39 @@ func (d *Dispatcher) DispatchOnce(ctx context.Context, projectID string) (*Dispa
 			skippedReasons["nil_bead"]++
 			continue
 		}
+		if exclude.hasBead(b.ID) {
+			skippedReasons["already_in_batch"]++
+			continue
+		}
+
+		if count, capped := d.projectCapReached(b, maxPerProject, inProgress); capped {
+			skippedReasons["project_concurrency_cap"]++
+			dlog.Debug("dispatch.skip", map[string]interface{}{
+				"bead_id": b.ID, "project_id": b.ProjectID, "reason": "project_concurrency_cap", "in_progress": count,
+			})
+			continue
+		}
 
 		// Skip beads that require human configuration (SSH keys, infrastructure, etc.)
 		// These should be handled manually or escalated to CEO, not auto-assigned to agents
 		if d.hasTag(b, "requires-human-config") {
 			skippedReasons["requires_human_config"]++
-			log.Printf("[Dispatcher] Skipping bead %s: requires human configuration", b.ID)
+			dlog.Debug("dispatch.skip", map[string]interface{}{"bead_id": b.ID, "project_id": b.ProjectID, "reason": "requires_human_config"})
 			continue
 		}
 
 		// Check if this is an auto-filed bug that needs routing
 		if routeInfo := d.autoBugRouter.AnalyzeBugForRouting(b); routeInfo.ShouldRoute {
-			log.Printf("[Dispatcher] Auto-bug detected: %s - routing to %s (%s)", b.ID, routeInfo.PersonaHint, routeInfo.RoutingReason)
+			dlog.Info("dispatch.auto_bug_routed", map[string]interface{}{
+				"bead_id": b.ID, "project_id": b.ProjectID, "persona_hint": routeInfo.PersonaHint, "reason": routeInfo.RoutingReason,
+			})
 
 			// Update the bead with persona hint in title
 			updates := map[string]interface{}{
 				"title": routeInfo.UpdatedTitle,
 			}
 			if err := d.beads.UpdateBead(b.ID, updates); err != nil {
-				log.Printf("[Dispatcher] Failed to update bead %s with persona hint: %v", b.ID, err)
+				dlog.Error("dispatch.auto_bug_routed", map[string]interface{}{"bead_id": b.ID, "project_id": b.ProjectID}, err)
 			} else {
 				// Refresh the bead to get updated title
 				b.Title = routeInfo.UpdatedTitle
@@ -589,71 +927,22 @@ func (d *Dispatcher) DispatchOnce(ctx context.Context, projectID string) (*Dispa
 
 			if !stuck {
 				// Making progress - allow to continue beyond hop limit
-				log.Printf("[Dispatcher] Bead %s has %d dispatches but is making progress, allowing to continue. Progress: %s",
-					b.ID, dispatchCount, d.loopDetector.GetProgressSummary(b))
+				dlog.Info("dispatch.over_hops_progressing", map[string]interface{}{
+					"bead_id": b.ID, "project_id": b.ProjectID, "dispatch_count": dispatchCount,
+					"progress": d.loopDetector.GetProgressSummary(b),
+				})
 				skippedReasons["dispatch_limit_but_progressing"]++
 				// Don't continue - allow this bead to be dispatched
 			} else {
-				// Ralph auto-block: stuck in loop — block autonomously instead of CEO escalation
-				reason := fmt.Sprintf("dispatch_count=%d exceeded max_hops=%d, stuck in loop: %s",
-					dispatchCount, maxHops, loopReason)
-				log.Printf("[Ralph] Bead %s is stuck after %d dispatches, auto-blocking: %s",
-					b.ID, dispatchCount, loopReason)
-
-				progressSummary := d.loopDetector.GetProgressSummary(b)
-
-				// Attempt auto-revert of agent commits if commit range is known
-				revertStatus := "not_attempted"
-				firstSHA, _, commitCount := d.loopDetector.GetAgentCommitRange(b)
-				if firstSHA != "" && commitCount > 0 {
-					log.Printf("[Ralph] Attempting auto-revert of %d agent commits for bead %s (from %s)",
-						commitCount, b.ID, firstSHA)
-					// Record intent — actual revert requires git.GitService which
-					// is project-scoped. The revert metadata tells the next handler
-					// (or human) exactly what to revert.
-					revertStatus = fmt.Sprintf("revert_recommended: %d commits from %s", commitCount, firstSHA)
-				}
-
-				ctxUpdates := map[string]string{
-					"redispatch_requested":  "false",
-					"ralph_blocked_at":      time.Now().UTC().Format(time.RFC3339),
-					"ralph_blocked_reason":  reason,
-					"loop_detection_reason": loopReason,
-					"progress_summary":      progressSummary,
-					"revert_status":         revertStatus,
-				}
-				if sessionID := b.Context["conversation_session_id"]; sessionID != "" {
-					ctxUpdates["conversation_session_id"] = sessionID
-				}
-
-				triageAgent := d.findDefaultTriageAgent(b.ProjectID)
-				updates := map[string]interface{}{
-					"status":      models.BeadStatusBlocked,
-					"assigned_to": triageAgent,
-					"context":     ctxUpdates,
-				}
-				if err := d.beads.UpdateBead(b.ID, updates); err != nil {
-					log.Printf("[Ralph] Failed to block bead %s: %v", b.ID, err)
-				} else if triageAgent != "" {
-					log.Printf("[Ralph] Blocked bead %s reassigned to triage agent %s", b.ID, triageAgent)
-				}
-
-				if d.eventBus != nil {
-					_ = d.eventBus.PublishBeadEvent(eventbus.EventTypeBeadStatusChange, b.ID, b.ProjectID,
-						map[string]interface{}{
-							"status":        string(models.BeadStatusBlocked),
-							"ralph_reason":  reason,
-							"revert_status": revertStatus,
-						})
-				}
-
-				skippedReasons["ralph_auto_blocked"]++
+				// Stuck in a loop: dead-letter it for a human decision
+				d.deadLetterBead(b, dispatchCount, maxHops, loopReason)
+				skippedReasons["dead_lettered"]++
 				continue
 			}
 		}
 
 		if dispatchCount >= maxHops-1 {
-			log.Printf("[Dispatcher] WARNING: Bead %s has been dispatched %d times", b.ID, dispatchCount)
+			dlog.Warn("dispatch.near_hop_limit", map[string]interface{}{"bead_id": b.ID, "project_id": b.ProjectID, "dispatch_count": dispatchCount})
 		}
 
 		// Skip beads that recently failed — cooldown prevents re-dispatching
@@ -682,16 +971,33 @@ func (d *Dispatcher) DispatchOnce(ctx context.Context, projectID string) (*Dispa
 		// If bead is assigned to an agent, only dispatch to that agent.
 		if b.AssignedTo != "" {
 			// First check if the agent still exists (not a dead agent from before restart)
-			_, agentExists := allAgentsByID[b.AssignedTo]
-			if !agentExists {
+			assignedAgent, agentExists := allAgentsByID[b.AssignedTo]
+			if agentExists && b.RequiredCapability != "" && len(filterByCapability([]*models.Agent{assignedAgent}, b.RequiredCapability)) == 0 {
+				// The assigned agent cannot do the work; release the bead so
+				// an agent with the capability can take it below
+				dlog.Warn("dispatch.assignment_lacks_capability", map[string]interface{}{
+					"bead_id": b.ID, "agent_id": b.AssignedTo, "project_id": b.ProjectID, "capability": b.RequiredCapability,
+				})
+				updates := map[string]interface{}{
+					"assigned_to": "",
+					"status":      models.BeadStatusOpen,
+				}
+				if err := d.beads.UpdateBead(b.ID, updates); err != nil {
+					dlog.Error("dispatch.assignment_lacks_capability", map[string]interface{}{"bead_id": b.ID, "agent_id": b.AssignedTo, "project_id": b.ProjectID}, err)
+					skippedReasons["capability_not_available"]++
+					continue
+				}
+				b.AssignedTo = ""
+				skippedReasons["assignment_lacks_capability"]++
+			} else if !agentExists {
 				// Agent no longer exists - clear assignment so bead can be reassigned
-				log.Printf("[Dispatcher] Bead %s assigned to dead agent %s, clearing assignment", b.ID, b.AssignedTo)
+				dlog.Warn("dispatch.dead_agent_cleared", map[string]interface{}{"bead_id": b.ID, "agent_id": b.AssignedTo, "project_id": b.ProjectID})
 				updates := map[string]interface{}{
 					"assigned_to": "",
 					"status":      models.BeadStatusOpen,
 				}
 				if err := d.beads.UpdateBead(b.ID, updates); err != nil {
-					log.Printf("[Dispatcher] Failed to clear dead agent assignment for bead %s: %v", b.ID, err)
+					dlog.Error("dispatch.dead_agent_cleared", map[string]interface{}{"bead_id": b.ID, "agent_id": b.AssignedTo, "project_id": b.ProjectID}, err)
 				} else {
 					// Bead is now unassigned, continue to normal dispatch logic below
 					b.AssignedTo = ""
@@ -712,6 +1018,20 @@ func (d *Dispatcher) DispatchOnce(ctx context.Context, projectID string) (*Dispa
 			}
 		}
 
+		// Only agents with the bead's required capability may take it; with
+		// none idle the bead waits rather than going to an arbitrary agent.
+		eligibleAgents := idleAgents
+		if b.RequiredCapability != "" {
+			eligibleAgents = filterByCapability(idleAgents, b.RequiredCapability)
+			if len(eligibleAgents) == 0 {
+				skippedReasons["capability_not_available"]++
+				dlog.Debug("dispatch.skip", map[string]interface{}{
+					"bead_id": b.ID, "project_id": b.ProjectID, "reason": "capability_not_available", "capability": b.RequiredCapability,
+				})
+				continue
+			}
+		}
+
 		// Check if bead has a workflow and needs specific role
 		var workflowRoleRequired string
 		// TEMPORARY FIX: Only enforce workflows for beads that explicitly opt-in via tags
@@ -749,7 +1069,7 @@ func (d *Dispatcher) DispatchOnce(ctx context.Context, projectID string) (*Dispa
 				if workflowRoleRequired != "" {
 					requiredRoleKey := normalizeRoleName(workflowRoleRequired)
 					// Find agent with matching role
-					for _, agent := range idleAgents {
+					for _, agent := range eligibleAgents {
 						if agent != nil && normalizeRoleName(agent.Role) == requiredRoleKey {
 							ag = agent
 							candidate = b
@@ -767,31 +1087,43 @@ func (d *Dispatcher) DispatchOnce(ctx context.Context, projectID string) (*Dispa
 					// design: the wrong persona would run investigation, approval,
 					// verification, and commit phases identically.
 					skippedReasons["workflow_role_not_available"]++
-					log.Printf("[Dispatcher] Bead %s needs workflow role %q but no idle agent has it - skipping (will retry when role available)", b.ID, workflowRoleRequired)
+					dlog.Debug("dispatch.skip", map[string]interface{}{
+						"bead_id": b.ID, "project_id": b.ProjectID, "reason": "workflow_role_not_available", "role": workflowRoleRequired,
+					})
 					continue
 				}
 			}
 		}
 
-		// Try persona-based routing first, but fall back to any idle agent
+		// A follow-up goes back to the agent that did the earlier work when
+		// it is free.
+		if preferred := preferredAgentFor(b, eligibleAgents); preferred != nil {
+			ag = preferred
+			candidate = b
+			break
+		}
+
+		// Try persona-based routing next, but fall back to any idle agent
 		personaHint := d.personaMatcher.ExtractPersonaHint(b)
 		if personaHint != "" {
-			matchedAgent := d.personaMatcher.FindAgentByPersonaHint(personaHint, idleAgents)
+			matchedAgent := d.personaMatcher.FindAgentByPersonaHint(personaHint, eligibleAgents)
 			if matchedAgent != nil {
 				ag = matchedAgent
 				candidate = b
-				log.Printf("[Dispatcher] Matched bead %s to agent %s via persona hint '%s'", b.ID, matchedAgent.Name, personaHint)
+				dlog.Info("dispatch.match", map[string]interface{}{
+					"bead_id": b.ID, "agent_id": matchedAgent.ID, "project_id": b.ProjectID, "persona_hint": personaHint,
+				})
 				break
 			}
 			// Persona hint found but no match - log it but fall through to assign any idle agent
-			log.Printf("[Dispatcher] Bead %s has persona hint '%s' but no exact match - will assign to any idle agent", b.ID, personaHint)
+			dlog.Debug("dispatch.persona_unmatched", map[string]interface{}{"bead_id": b.ID, "project_id": b.ProjectID, "persona_hint": personaHint})
 		}
 
 		// Pick an idle agent for this bead's project.
 		// Prefer Engineering Manager as default assignee for unassigned beads.
 		var matchedAgent *models.Agent
 		var fallbackAgent *models.Agent
-		for _, a := range idleAgents {
+		for _, a := range eligibleAgents {
 			if a.ProjectID == b.ProjectID || a.ProjectID == "" || b.ProjectID == "" {
 				if fallbackAgent == nil {
 					fallbackAgent = a
@@ -809,22 +1141,26 @@ func (d *Dispatcher) DispatchOnce(ctx context.Context, projectID string) (*Dispa
 			skippedReasons["no_idle_agents_for_project"]++
 			continue
 		}
-		log.Printf("[Dispatcher] Assigning bead %s (project %s) to agent %s", b.ID, b.ProjectID, matchedAgent.Name)
+		dlog.Info("dispatch.match", map[string]interface{}{"bead_id": b.ID, "agent_id": matchedAgent.ID, "project_id": b.ProjectID})
 		ag = matchedAgent
 		candidate = b
 		break
 	}
 
+	d.metrics.recordAttempt(skippedReasons)
 	if len(skippedReasons) > 0 {
-		log.Printf("[Dispatcher] Skipped beads: %+v", skippedReasons)
+		dlog.Debug("dispatch.skipped", map[string]interface{}{"project_id": projectID, "skipped": skippedReasons})
 	}
 
 	if candidate == nil {
 		reasonsJSON, _ := json.Marshal(skippedReasons)
-		log.Printf("[Dispatcher] No dispatchable beads found (ready: %d, idle agents: %d, skipped: %s)", len(ready), len(idleAgents), string(reasonsJSON))
+		dlog.Debug("dispatch.no_candidate", map[string]interface{}{
+			"project_id": projectID, "ready": len(ready), "idle_agents": len(idleAgents), "skipped": skippedReasons,
+		})
 		os.WriteFile("/tmp/dispatch-no-candidate.txt", []byte(fmt.Sprintf("ready=%d idle=%d skipped=%s\n", len(ready), len(idleAgents), string(reasonsJSON))), 0644)
 		d.setStatus(StatusParked, "no dispatchable beads")
-		return &DispatchResult{Dispatched: false, ProjectID: projectID}, nil
+		d.metrics.recordParked()
+		return &DispatchResult{Dispatched: false, ProjectID: projectID}, nil, nil
 	}
 
 	selectedProjectID := projectID
@@ -833,7 +1169,8 @@ func (d *Dispatcher) DispatchOnce(ctx context.Context, projectID string) (*Dispa
 	}
 	if ag == nil {
 		d.setStatus(StatusParked, "no idle agents with active providers")
-		return &DispatchResult{Dispatched: false, ProjectID: selectedProjectID}, nil
+		d.metrics.recordParked()
+		return &DispatchResult{Dispatched: false, ProjectID: selectedProjectID}, nil, nil
 	}
 
 	// Estimate task complexity for smart provider routing
@@ -850,27 +1187,35 @@ func (d *Dispatcher) DispatchOnce(ctx context.Context, projectID string) (*Dispa
 		prevProvider := ag.ProviderID
 		ag.ProviderID = selected.Config.ID
 		if selected.Config.ID != prevProvider {
-			log.Printf("[Dispatcher] Selected provider %s (%d/%d) for %s complexity task %s (prev=%s)",
-				selected.Config.ID, idx+1, len(activeProviders),
-				complexity.String(), candidate.ID, prevProvider)
+			dlog.Info("dispatch.provider_selected", map[string]interface{}{
+				"bead_id": candidate.ID, "agent_id": ag.ID, "project_id": candidate.ProjectID,
+				"provider_id": selected.Config.ID, "from_provider": prevProvider, "complexity": complexity.String(),
+			})
 		}
 	} else {
 		d.setStatus(StatusParked, "no active providers available")
-		return &DispatchResult{Dispatched: false, ProjectID: selectedProjectID, AgentID: ag.ID}, nil
+		d.metrics.recordParked()
+		return &DispatchResult{Dispatched: false, ProjectID: selectedProjectID, AgentID: ag.ID}, nil, nil
 	}
 
+	// Everything done for this bead shares one trace ID, unless the caller,
+	// such as an API request, already started a trace.
+	ctx, traceID := observability.EnsureTraceID(ctx)
+	tlog := dlog.WithTrace(ctx)
+
 	// Ensure bead is claimed/assigned.
 	if candidate.AssignedTo == "" {
 		if err := d.beads.ClaimBead(candidate.ID, ag.ID); err != nil {
 			d.setStatus(StatusParked, "failed to claim bead")
-			observability.Error("dispatch.claim", map[string]interface{}{
+			d.metrics.recordParked()
+			tlog.Error("dispatch.claim", map[string]interface{}{
 				"agent_id":   ag.ID,
 				"bead_id":    candidate.ID,
 				"project_id": candidate.ProjectID,
 			}, err)
-			return &DispatchResult{Dispatched: false, ProjectID: projectID}, nil
+			return &DispatchResult{Dispatched: false, ProjectID: projectID}, nil, nil
 		}
-		observability.Info("dispatch.claim", map[string]interface{}{
+		tlog.Info("dispatch.claim", map[string]interface{}{
 			"agent_id":   ag.ID,
 			"bead_id":    candidate.ID,
 			"project_id": candidate.ProjectID,
@@ -887,23 +1232,36 @@ func (d *Dispatcher) DispatchOnce(ctx context.Context, projectID string) (*Dispa
 	dispatchCount++
 
 	// Update bead context with incremented dispatch count
+	countContext := map[string]string{
+		"dispatch_count": fmt.Sprintf("%d", dispatchCount),
+	}
+	if preferred := candidate.Context[PreferredAgentKey]; preferred != "" {
+		honored := ag.ID == preferred
+		d.metrics.recordAffinity(honored)
+		countContext["affinity"] = "fallback"
+		if honored {
+			countContext["affinity"] = "honored"
+		}
+		tlog.Info("dispatch.affinity", map[string]interface{}{
+			"bead_id": candidate.ID, "agent_id": ag.ID, "project_id": selectedProjectID,
+			"preferred_agent": preferred, "honored": honored,
+		})
+	}
 	countUpdates := map[string]interface{}{
-		"context": map[string]string{
-			"dispatch_count": fmt.Sprintf("%d", dispatchCount),
-		},
+		"context": countContext,
 	}
 	if err := d.beads.UpdateBead(candidate.ID, countUpdates); err != nil {
-		log.Printf("[Dispatcher] WARNING: Failed to update dispatch count for bead %s: %v", candidate.ID, err)
+		tlog.Error("dispatch.count", map[string]interface{}{"bead_id": candidate.ID, "project_id": selectedProjectID}, err)
 		// Don't fail dispatch on this error - just log it
 	}
-	log.Printf("[Dispatcher] Bead %s dispatch count: %d", candidate.ID, dispatchCount)
+	tlog.Debug("dispatch.count", map[string]interface{}{"bead_id": candidate.ID, "project_id": selectedProjectID, "dispatch_count": dispatchCount})
 
 	// FIX #7: Log errors instead of silently discarding them
 	if err := d.agents.AssignBead(ag.ID, candidate.ID); err != nil {
-		log.Printf("[Dispatcher] CRITICAL: Failed to assign bead %s to agent %s: %v", candidate.ID, ag.ID, err)
+		tlog.Error("dispatch.assign", map[string]interface{}{"bead_id": candidate.ID, "agent_id": ag.ID, "project_id": selectedProjectID}, err)
 		// Continue anyway - the task will still be submitted to the worker
 	}
-	observability.Info("dispatch.assign", map[string]interface{}{
+	tlog.Info("dispatch.assign", map[string]interface{}{
 		"agent_id":    ag.ID,
 		"bead_id":     candidate.ID,
 		"project_id":  selectedProjectID,
@@ -926,15 +1284,18 @@ func (d *Dispatcher) DispatchOnce(ctx context.Context, projectID string) (*Dispa
 					"status":       string(candidate.Status),
 					"assigned_at":  time.Now().UTC().Format(time.RFC3339),
 					"dispatch_hop": dispatchCount,
+					"trace_id":     traceID,
 				},
 			},
 			correlationID,
 		)
 		if err := d.messageBus.PublishTask(ctx, selectedProjectID, taskMsg); err != nil {
-			log.Printf("[Dispatcher] Warning: Failed to publish task to NATS for bead %s: %v", candidate.ID, err)
+			tlog.Error("dispatch.publish_task", map[string]interface{}{"bead_id": candidate.ID, "agent_id": ag.ID, "project_id": selectedProjectID}, err)
 			// Don't fail dispatch - agent can still get task via other means
 		} else {
-			log.Printf("[Dispatcher] Published task to NATS: bead=%s agent=%s correlation=%s", candidate.ID, ag.ID, correlationID)
+			tlog.Debug("dispatch.publish_task", map[string]interface{}{
+				"bead_id": candidate.ID, "agent_id": ag.ID, "project_id": selectedProjectID, "correlation_id": correlationID,
+			})
 		}
 	}
 
@@ -955,22 +1316,27 @@ func (d *Dispatcher) DispatchOnce(ctx context.Context, projectID string) (*Dispa
 		var err error
 		conversationSession, err = d.getOrCreateConversationSession(candidate, selectedProjectID)
 		if err != nil {
-			log.Printf("[Dispatcher] Warning: Failed to get/create conversation session for bead %s: %v", candidate.ID, err)
+			tlog.Error("dispatch.conversation_session", map[string]interface{}{"bead_id": candidate.ID, "project_id": selectedProjectID}, err)
 			// Continue without conversation session (falls back to single-shot mode)
 		} else if conversationSession != nil {
-			log.Printf("[Dispatcher] Using conversation session %s for bead %s (messages: %d)",
-				conversationSession.SessionID, candidate.ID, len(conversationSession.Messages))
+			tlog.Debug("dispatch.conversation_session", map[string]interface{}{
+				"bead_id": candidate.ID, "project_id": selectedProjectID,
+				"session_id": conversationSession.SessionID, "messages": len(conversationSession.Messages),
+			})
 		}
 	}
 
 	task := &worker.Task{
 		ID:                  fmt.Sprintf("task-%s-%d", candidate.ID, time.Now().UnixNano()),
 		Description:         buildBeadDescription(candidate),
-		Context:             buildBeadContext(candidate, proj),
+		Context:             buildBeadContext(candidate, proj, ag.Persona),
 		BeadID:              candidate.ID,
 		ProjectID:           selectedProjectID,
 		ConversationSession: conversationSession,
 	}
+	budget := d.taskBudget(selectedProjectID)
+	task.MaxTokens = budget.maxTokens
+	task.MaxCostUSD = budget.maxCostUSD
 
 	d.setStatus(StatusActive, fmt.Sprintf("dispatching %s", candidate.ID))
 
@@ -978,17 +1344,28 @@ func (d *Dispatcher) DispatchOnce(ctx context.Context, projectID string) (*Dispa
 	// loop can assign other agents in the same tick. The agent's status is
 	// set to "working" by ExecuteTask before the LLM call starts, so the
 	// next DispatchOnce won't re-assign it.
-	dispatchResult := &DispatchResult{Dispatched: true, ProjectID: selectedProjectID, BeadID: candidate.ID, AgentID: ag.ID, ProviderID: ag.ProviderID}
+	dispatchResult := &DispatchResult{Dispatched: true, ProjectID: selectedProjectID, BeadID: candidate.ID, AgentID: ag.ID, ProviderID: ag.ProviderID, TraceID: traceID}
+	d.metrics.recordDispatch()
 
-	go func() {
+	run := func() {
 		// Create independent context for task execution - don't inherit cancellation from dispatch loop
 		// The task should run to completion even if the dispatch loop moves on
-		taskCtx := context.Background()
+		taskCtx := observability.WithTraceID(context.Background(), traceID)
 
 		// Check if this is a commit node that needs serialization (Gap #2)
+		dispatchedNode := ""
 		if d.workflowEngine != nil {
 			execution, err := d.workflowEngine.GetDatabase().GetWorkflowExecutionByBeadID(candidate.ID)
 			if err == nil && execution != nil {
+				// Start the node's timeout clock and remember which node this
+				// work is for, so a result that arrives after a timeout moved
+				// the execution on is not applied to the new node.
+				if marked, markErr := d.workflowEngine.MarkNodeDispatched(execution.ID); markErr != nil {
+					log.Printf("[Workflow] Failed to record dispatch for bead %s: %v", candidate.ID, markErr)
+				} else if marked != nil {
+					execution = marked
+				}
+				dispatchedNode = execution.CurrentNodeKey
 				node, err := d.workflowEngine.GetCurrentNode(execution.ID)
 				if err == nil && node != nil && node.NodeType == workflow.NodeTypeCommit {
 					// Acquire commit lock before executing
@@ -1003,23 +1380,42 @@ func (d *Dispatcher) DispatchOnce(ctx context.Context, projectID string) (*Dispa
 			}
 		}
 
-		result, execErr := d.agents.ExecuteTask(taskCtx, ag.ID, task)
+		// Transient failures are retried here, within this dispatch; the
+		// dispatch count and history below reflect only the final outcome.
+		execStart := time.Now()
+		result, execErr := d.executeWithRetry(taskCtx, candidate.ID, func(ctx context.Context) (*worker.TaskResult, error) {
+			return d.agents.ExecuteTask(ctx, ag.ID, task)
+		})
+		d.metrics.recordExecution(time.Since(execStart), execErr == nil)
+		if execErr != nil && d.cancelledDuring(candidate.ID, execErr, execStart) {
+			// CancelBead already reopened the bead; a cancel is not a failure,
+			// so skip the history, loop detection and workflow bookkeeping.
+			d.setStatus(StatusParked, "execution cancelled")
+			tlog.Info("dispatch.cancelled", map[string]interface{}{
+				"agent_id":   ag.ID,
+				"bead_id":    candidate.ID,
+				"project_id": selectedProjectID,
+			})
+			return
+		}
 		if execErr != nil {
 			d.setStatus(StatusParked, "execution failed")
-			observability.Error("dispatch.execute", map[string]interface{}{
+			tlog.Error("dispatch.execute", map[string]interface{}{
 				"agent_id":    ag.ID,
 				"bead_id":     candidate.ID,
 				"project_id":  selectedProjectID,
 				"provider_id": ag.ProviderID,
 			}, execErr)
 
-			historyJSON, loopDetected, loopReason := buildDispatchHistory(candidate, ag.ID)
+			historyJSON, loopDetected, loopReason := buildDispatchHistoryWithRules(candidate, ag.ID, d.loopRules())
 
 			// Check if the error is due to max_iterations - if so, don't redispatch
 			shouldRedispatch := "true"
 			if candidate.Context != nil && candidate.Context["terminal_reason"] == "max_iterations" {
 				shouldRedispatch = "false"
-				log.Printf("[Dispatcher] Bead %s previously hit max_iterations, not redispatching after error", candidate.ID)
+				tlog.Info("dispatch.redispatch_disabled", map[string]interface{}{
+					"bead_id": candidate.ID, "agent_id": ag.ID, "project_id": selectedProjectID, "reason": "max_iterations",
+				})
 			}
 
 			ctxUpdates := map[string]string{
@@ -1041,10 +1437,12 @@ func (d *Dispatcher) DispatchOnce(ctx context.Context, projectID string) (*Dispa
 				updates["priority"] = models.BeadPriorityP0
 				updates["status"] = models.BeadStatusOpen
 				updates["assigned_to"] = triageAgent
-				log.Printf("[Dispatcher] Loop detected for bead %s, reassigning to triage agent %s", candidate.ID, triageAgent)
+				tlog.Warn("dispatch.loop_detected", map[string]interface{}{
+					"bead_id": candidate.ID, "agent_id": ag.ID, "project_id": selectedProjectID, "triage_agent_id": triageAgent, "reason": loopReason,
+				})
 			}
 			if err := d.beads.UpdateBead(candidate.ID, updates); err != nil {
-				log.Printf("[Dispatcher] CRITICAL: Failed to update bead %s with context/loop detection: %v", candidate.ID, err)
+				tlog.Error("dispatch.record_failure", map[string]interface{}{"bead_id": candidate.ID, "agent_id": ag.ID, "project_id": selectedProjectID}, err)
 			}
 			if d.eventBus != nil {
 				status := string(models.BeadStatusInProgress)
@@ -1059,7 +1457,9 @@ func (d *Dispatcher) DispatchOnce(ctx context.Context, projectID string) (*Dispa
 			// Handle workflow failure — map to correct condition for node type
 			if d.workflowEngine != nil {
 				execution, err := d.workflowEngine.GetDatabase().GetWorkflowExecutionByBeadID(candidate.ID)
-				if err == nil && execution != nil {
+				if err == nil && execution != nil && leftDispatchedNode(execution, dispatchedNode) {
+					log.Printf("[Workflow] Ignoring late failure for bead %s: execution moved from node %s to %s", candidate.ID, dispatchedNode, execution.CurrentNodeKey)
+				} else if err == nil && execution != nil {
 					// For approval/verify nodes, use "rejected" instead of "failure"
 					// since their edges use approved/rejected conditions.
 					failCondition := workflow.EdgeConditionFailure
@@ -1122,33 +1522,39 @@ func (d *Dispatcher) DispatchOnce(ctx context.Context, projectID string) (*Dispa
 					ctxUpdates["redispatch_requested"] = "true"
 					ctxUpdates["max_iterations_retries"] = "1"
 					ctxUpdates["max_iterations_reached_at"] = time.Now().UTC().Format(time.RFC3339)
-					log.Printf("[Dispatcher] Bead %s hit max_iterations (first time), allowing one retry", candidate.ID)
+					tlog.Info("dispatch.max_iterations", map[string]interface{}{
+						"bead_id": candidate.ID, "agent_id": ag.ID, "project_id": selectedProjectID, "redispatch": true,
+					})
 				} else {
 					// Already retried - disable further redispatches to prevent infinite loops
 					ctxUpdates["redispatch_requested"] = "false"
 					ctxUpdates["max_iterations_retry_exhausted"] = "true"
-					log.Printf("[Dispatcher] Bead %s hit max_iterations again after retry, disabling redispatch", candidate.ID)
+					tlog.Warn("dispatch.max_iterations", map[string]interface{}{
+						"bead_id": candidate.ID, "agent_id": ag.ID, "project_id": selectedProjectID, "redispatch": false,
+					})
 				}
 			}
 
 			// On failure, set cooldown to prevent re-dispatching the same bead
 			// 50 times in a single ralph beat
 			switch result.LoopTerminalReason {
-			case "parse_failures", "validation_failures", "error":
+			case "parse_failures", "validation_failures", "error", "budget_exceeded":
 				ctxUpdates["last_failed_at"] = time.Now().UTC().Format(time.RFC3339)
 			case "progress_stagnant", "inner_loop":
 				// Agent is stuck - trigger remediation
 				ctxUpdates["last_failed_at"] = time.Now().UTC().Format(time.RFC3339)
 				ctxUpdates["remediation_needed"] = "true"
 				ctxUpdates["remediation_requested_at"] = time.Now().UTC().Format(time.RFC3339)
-				log.Printf("[Dispatcher] Agent stuck on bead %s (reason: %s), remediation needed", candidate.ID, result.LoopTerminalReason)
+				tlog.Warn("dispatch.agent_stuck", map[string]interface{}{
+					"bead_id": candidate.ID, "agent_id": ag.ID, "project_id": selectedProjectID, "reason": result.LoopTerminalReason,
+				})
 
 				// Create remediation bead to analyze and fix the blocker
 				go d.createRemediationBead(candidate, ag, result)
 			}
 		}
 
-		historyJSON, loopDetected, loopReason := buildDispatchHistory(candidate, ag.ID)
+		historyJSON, loopDetected, loopReason := buildDispatchHistoryWithRules(candidate, ag.ID, d.loopRules())
 		ctxUpdates["dispatch_history"] = historyJSON
 		ctxUpdates["loop_detected"] = fmt.Sprintf("%t", loopDetected)
 		if loopDetected {
@@ -1162,10 +1568,12 @@ func (d *Dispatcher) DispatchOnce(ctx context.Context, projectID string) (*Dispa
 			updates["priority"] = models.BeadPriorityP0
 			updates["status"] = models.BeadStatusOpen
 			updates["assigned_to"] = triageAgent
-			log.Printf("[Dispatcher] Task failure loop for bead %s, reassigning to triage agent %s", candidate.ID, triageAgent)
+			tlog.Warn("dispatch.loop_detected", map[string]interface{}{
+				"bead_id": candidate.ID, "agent_id": ag.ID, "project_id": selectedProjectID, "triage_agent_id": triageAgent, "reason": loopReason,
+			})
 		}
 		if err := d.beads.UpdateBead(candidate.ID, updates); err != nil {
-			log.Printf("[Dispatcher] CRITICAL: Failed to update bead %s after task failure: %v", candidate.ID, err)
+			tlog.Error("dispatch.record_failure", map[string]interface{}{"bead_id": candidate.ID, "agent_id": ag.ID, "project_id": selectedProjectID}, err)
 		}
 		if d.eventBus != nil {
 			status := string(models.BeadStatusInProgress)
@@ -1180,7 +1588,9 @@ func (d *Dispatcher) DispatchOnce(ctx context.Context, projectID string) (*Dispa
 		// Advance workflow after successful task execution
 		if d.workflowEngine != nil && !loopDetected {
 			execution, err := d.workflowEngine.GetDatabase().GetWorkflowExecutionByBeadID(candidate.ID)
-			if err == nil && execution != nil {
+			if err == nil && execution != nil && leftDispatchedNode(execution, dispatchedNode) {
+				log.Printf("[Workflow] Ignoring late result for bead %s: execution moved from node %s to %s", candidate.ID, dispatchedNode, execution.CurrentNodeKey)
+			} else if err == nil && execution != nil {
 				// Determine the correct edge condition based on the current node type.
 				// Approval and verify nodes define edges with "approved"/"rejected",
 				// not "success", so we must translate the dispatcher's generic
@@ -1216,52 +1626,7 @@ func (d *Dispatcher) DispatchOnce(ctx context.Context, projectID string) (*Dispa
 
 						// Check if workflow was escalated and needs CEO bead
 						if updatedExec.Status == workflow.ExecutionStatusEscalated && candidate.Context["escalation_bead_created"] != "true" {
-							log.Printf("[Workflow] Creating CEO escalation bead for workflow %s (bead %s)", updatedExec.ID, candidate.ID)
-
-							// Get escalation info from workflow engine
-							title, description, err := d.workflowEngine.GetEscalationInfo(updatedExec)
-							if err != nil {
-								log.Printf("[Workflow] Failed to get escalation info for workflow %s: %v", updatedExec.ID, err)
-							} else {
-								// Create CEO escalation bead
-								createdBead, err := d.beads.CreateBead(
-									title,
-									description,
-									models.BeadPriorityP0,
-									"decision",
-									candidate.ProjectID,
-								)
-								if err != nil {
-									log.Printf("[Workflow] Failed to create CEO escalation bead: %v", err)
-								} else {
-									log.Printf("[Workflow] Created CEO escalation bead %s for workflow %s", createdBead.ID, updatedExec.ID)
-
-									// Update the escalation bead with tags and context
-									escalationBeadUpdates := map[string]interface{}{
-										"tags": []string{"workflow-escalation", "ceo-review", "urgent"},
-										"context": map[string]string{
-											"original_bead_id":      candidate.ID,
-											"workflow_execution_id": updatedExec.ID,
-											"escalation_reason":     candidate.Context["escalation_reason"],
-											"escalated_at":          time.Now().UTC().Format(time.RFC3339),
-										},
-									}
-									if err := d.beads.UpdateBead(createdBead.ID, escalationBeadUpdates); err != nil {
-										log.Printf("[Workflow] Failed to update escalation bead with tags and context: %v", err)
-									}
-
-									// Mark original bead as having escalation bead created
-									originalUpdates := map[string]interface{}{
-										"context": map[string]string{
-											"escalation_bead_created": "true",
-											"escalation_bead_id":      createdBead.ID,
-										},
-									}
-									if err := d.beads.UpdateBead(candidate.ID, originalUpdates); err != nil {
-										log.Printf("[Workflow] Failed to update original bead with escalation info: %v", err)
-									}
-								}
-							}
+							d.createEscalationBead(updatedExec, candidate)
 						}
 					}
 				}
@@ -1269,14 +1634,14 @@ func (d *Dispatcher) DispatchOnce(ctx context.Context, projectID string) (*Dispa
 		}
 
 		d.setStatus(StatusParked, "idle")
-		observability.Info("dispatch.execute", map[string]interface{}{
+		tlog.Info("dispatch.execute", map[string]interface{}{
 			"agent_id":    ag.ID,
 			"bead_id":     candidate.ID,
 			"project_id":  selectedProjectID,
 			"provider_id": ag.ProviderID,
 			"status":      "success",
 		})
-	}() // end async goroutine
+	} // end task execution
 
 	// Record dispatch metrics
 	latency := float64(time.Since(startTime).Milliseconds())
@@ -1290,10 +1655,42 @@ func (d *Dispatcher) DispatchOnce(ctx context.Context, projectID string) (*Dispa
 	)
 	span.SetStatus(codes.Ok, "dispatch successful")
 
-	return dispatchResult, nil
+	return dispatchResult, run, nil
+}
+
+// loopRules are the thresholds applied to a bead's failed-dispatch history.
+type loopRules struct {
+	window         int // Alternating two-agent failures that count as a loop
+	maxDispatches  int // Failed dispatches before a loop is declared (0 = no limit)
+	sameAgentLimit int // Consecutive failures by one agent that count as a loop
 }
 
+func defaultLoopRules() loopRules {
+	return loopRules{window: DefaultLoopWindow, sameAgentLimit: DefaultSameAgentFailureLimit}
+}
+
+func (d *Dispatcher) loopRules() loopRules {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	rules := defaultLoopRules()
+	if d.loopWindow > 0 {
+		rules.window = d.loopWindow
+	}
+	if d.sameAgentFailures > 0 {
+		rules.sameAgentLimit = d.sameAgentFailures
+	}
+	rules.maxDispatches = d.maxDispatchCount
+	return rules
+}
+
+// buildDispatchHistory applies the default loop rules.
 func buildDispatchHistory(bead *models.Bead, agentID string) (historyJSON string, loopDetected bool, loopReason string) {
+	return buildDispatchHistoryWithRules(bead, agentID, defaultLoopRules())
+}
+
+// buildDispatchHistoryWithRules appends agentID to the bead's failed-dispatch history
+// and reports whether any loop rule fired, and which.
+func buildDispatchHistoryWithRules(bead *models.Bead, agentID string, rules loopRules) (historyJSON string, loopDetected bool, loopReason string) {
 	history := make([]string, 0)
 	if bead != nil && bead.Context != nil {
 		if raw := bead.Context["dispatch_history"]; raw != "" {
@@ -1301,38 +1698,63 @@ func buildDispatchHistory(bead *models.Bead, agentID string) (historyJSON string
 		}
 	}
 	history = append(history, agentID)
-	if len(history) > 20 {
-		history = history[len(history)-20:]
+	keep := max(dispatchHistoryLimit, rules.window, rules.maxDispatches, rules.sameAgentLimit)
+	if len(history) > keep {
+		history = history[len(history)-keep:]
 	}
 	b, _ := json.Marshal(history)
 	historyJSON = string(b)
 
-	if len(history) < 6 {
-		return historyJSON, false, ""
+	if isAlternating(history, rules.window) {
+		return historyJSON, true, fmt.Sprintf("dispatch alternated between two agents for %d runs", rules.window)
 	}
-	last := history[len(history)-6:]
-	unique := map[string]struct{}{}
-	for _, id := range last {
-		unique[id] = struct{}{}
+	if rules.sameAgentLimit > 0 && len(history) >= rules.sameAgentLimit {
+		same := true
+		for _, id := range history[len(history)-rules.sameAgentLimit:] {
+			if id != agentID {
+				same = false
+				break
+			}
+		}
+		if same {
+			return historyJSON, true, fmt.Sprintf("agent %s failed %d times in a row", agentID, rules.sameAgentLimit)
+		}
 	}
-	if len(unique) != 2 {
-		return historyJSON, false, ""
+	if rules.maxDispatches > 0 && len(history) >= rules.maxDispatches {
+		return historyJSON, true, fmt.Sprintf("bead failed %d dispatches (max %d)", len(history), rules.maxDispatches)
 	}
+	return historyJSON, false, ""
+}
+
+// isAlternating reports whether the last window entries of history alternate
+// between exactly two agents.
+func isAlternating(history []string, window int) bool {
+	if window < 2 || len(history) < window {
+		return false
+	}
+	last := history[len(history)-window:]
 	if last[0] == last[1] {
-		return historyJSON, false, ""
+		return false
 	}
 	for i := 2; i < len(last); i++ {
 		if last[i] != last[i%2] {
-			return historyJSON, false, ""
+			return false
 		}
 	}
-	return historyJSON, true, "dispatch alternated between two agents for 6 runs"
+	return true
 }
 
 func (d *Dispatcher) setStatus(state StatusState, reason string) {
 	d.mu.Lock()
-	defer d.mu.Unlock()
+	changed := d.status.State != state || d.status.Reason != reason
 	d.status = SystemStatus{State: state, Reason: reason, UpdatedAt: time.Now()}
+	status := d.status
+	d.mu.Unlock()
+
+	d.metrics.recordStatus(state)
+	if changed {
+		d.publishStatus(status)
+	}
 }
 
 // getOrCreateConversationSession retrieves an existing conversation session for a bead,
@@ -1423,7 +1845,7 @@ func buildBeadDescription(b *models.Bead) string {
 	return fmt.Sprintf("Work on bead %s: %s\n\n%s", b.ID, b.Title, b.Description)
 }
 
-func buildBeadContext(b *models.Bead, p *models.Project) string {
+func buildBeadContext(b *models.Bead, p *models.Project, persona *models.Persona) string {
 	var sb strings.Builder
 
 	// Project identity and context
@@ -1463,25 +1885,8 @@ func buildBeadContext(b *models.Bead, p *models.Project) string {
 		}
 	}
 
-	// Directive: act, don't plan
-	sb.WriteString(`
-## Instructions
-
-You are an autonomous coding agent. Your job is to MAKE CHANGES, COMMIT, and PUSH.
-
-WORKFLOW:
-1. Locate: scope + read relevant files (iterations 1-3)
-2. Change: edit or write files (iterations 4-15)
-3. Verify: build and test (iterations 16-18)
-4. Land: git_commit, git_push, done (iterations 19-21)
-
-CRITICAL RULES:
-- You have 25 iterations. Use them.
-- ALWAYS git_commit after making changes.
-- ALWAYS git_push after committing.
-- ALWAYS build and test before pushing.
-- Uncommitted work is LOST work.
-`)
+	// Directive: the persona's output format, or act, don't plan
+	sb.WriteString(beadInstructions(b, p, persona))
 
 	return sb.String()
 }
@@ -1692,6 +2097,25 @@ func normalizeRoleName(role string) string {
 	return role
 }
 
+// filterByCapability returns the agents whose persona lists capability,
+// compared case-insensitively.
+func filterByCapability(agents []*models.Agent, capability string) []*models.Agent {
+	capability = strings.TrimSpace(capability)
+	var out []*models.Agent
+	for _, a := range agents {
+		if a == nil || a.Persona == nil {
+			continue
+		}
+		for _, c := range a.Persona.Capabilities {
+			if strings.EqualFold(strings.TrimSpace(c), capability) {
+				out = append(out, a)
+				break
+			}
+		}
+	}
+	return out
+}
+
 // hasTag checks if a bead has a specific tag
 func (d *Dispatcher) hasTag(bead *models.Bead, tag string) bool {
 	if bead == nil || len(bead.Tags) == 0 {
@@ -0,0 +1,94 @@
+package dispatch
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jordanhubbard/loom/internal/provider"
+)
+
+func TestDispatchBatch_RejectsNonPositiveMax(t *testing.T) {
+	d := NewDispatcher(nil, nil, nil, provider.NewRegistry(), nil)
+	for _, max := range []int{0, -1} {
+		if _, err := d.DispatchBatch(context.Background(), "", max); err == nil {
+			t.Errorf("expected error for max=%d", max)
+		}
+	}
+}
+
+func TestDispatchBatch_NoActiveProviders(t *testing.T) {
+	d := NewDispatcher(nil, nil, nil, provider.NewRegistry(), nil)
+	results, err := d.DispatchBatch(context.Background(), "proj-1", 5)
+	if err != nil {
+		t.Fatalf("DispatchBatch: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("expected no results without providers, got %d", len(results))
+	}
+	if status := d.GetSystemStatus(); status.State != StatusParked {
+		t.Errorf("expected parked status, got %s", status.State)
+	}
+}
+
+func TestDispatcher_SetBatchWorkers(t *testing.T) {
+	d := NewDispatcher(nil, nil, nil, nil, nil)
+	if d.batchWorkers != DefaultBatchWorkers {
+		t.Errorf("expected default batchWorkers %d, got %d", DefaultBatchWorkers, d.batchWorkers)
+	}
+	d.SetBatchWorkers(2)
+	if d.batchWorkers != 2 {
+		t.Errorf("expected batchWorkers 2, got %d", d.batchWorkers)
+	}
+}
+
+func TestDispatchExclusions(t *testing.T) {
+	var nilExclude *dispatchExclusions
+	if nilExclude.hasBead("b-1") || nilExclude.hasAgent("a-1") {
+		t.Error("nil exclusions should exclude nothing")
+	}
+
+	e := newDispatchExclusions()
+	e.add("b-1", "a-1")
+	if !e.hasBead("b-1") || !e.hasAgent("a-1") {
+		t.Error("expected added bead and agent to be excluded")
+	}
+	if e.hasBead("a-1") || e.hasAgent("b-1") {
+		t.Error("bead and agent IDs should be tracked separately")
+	}
+}
+
+func TestRunBounded_LimitsConcurrency(t *testing.T) {
+	const workers = 3
+	var running, peak, done int32
+	var mu sync.Mutex
+
+	fns := make([]func(), 10)
+	for i := range fns {
+		fns[i] = func() {
+			n := atomic.AddInt32(&running, 1)
+			mu.Lock()
+			if n > peak {
+				peak = n
+			}
+			mu.Unlock()
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			atomic.AddInt32(&done, 1)
+		}
+	}
+
+	runBounded(fns, workers)
+
+	if done != int32(len(fns)) {
+		t.Errorf("expected %d tasks to run, got %d", len(fns), done)
+	}
+	if peak > workers {
+		t.Errorf("expected at most %d concurrent tasks, saw %d", workers, peak)
+	}
+	if peak < 2 {
+		t.Errorf("expected tasks to run concurrently, peak was %d", peak)
+	}
+}
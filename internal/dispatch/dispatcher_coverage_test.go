@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/jordanhubbard/loom/internal/provider"
+	"github.com/jordanhubbard/loom/internal/workflow"
 	"github.com/jordanhubbard/loom/pkg/models"
 )
 
@@ -180,7 +181,7 @@ func TestBuildBeadContext_ProjectWithWorkDir(t *testing.T) {
 		Branch:  "main",
 		WorkDir: "/nonexistent/workdir",
 	}
-	result := buildBeadContext(bead, project)
+	result := buildBeadContext(bead, project, nil)
 	if !strings.Contains(result, "WorkDirProject") {
 		t.Error("Expected project name in context")
 	}
@@ -209,7 +210,7 @@ func TestBuildBeadContext_ProjectWithWorkDirAndAgentsMD(t *testing.T) {
 		Branch:  "main",
 		WorkDir: tmpDir,
 	}
-	result := buildBeadContext(bead, project)
+	result := buildBeadContext(bead, project, nil)
 	if !strings.Contains(result, "Project Instructions") {
 		t.Error("Expected AGENTS.md section header")
 	}
@@ -229,7 +230,7 @@ func TestBuildBeadContext_EmptyProject(t *testing.T) {
 		Name:   "",
 		Branch: "",
 	}
-	result := buildBeadContext(bead, project)
+	result := buildBeadContext(bead, project, nil)
 	if !strings.Contains(result, "Project:") {
 		t.Error("Expected project section even with empty fields")
 	}
@@ -374,6 +375,50 @@ func TestDispatcher_SetMaxDispatchHops_Values(t *testing.T) {
 	}
 }
 
+func TestDispatcher_LoopRuleSetters(t *testing.T) {
+	d := &Dispatcher{}
+	if got := d.loopRules(); got != defaultLoopRules() {
+		t.Errorf("zero-value dispatcher rules = %+v, want defaults %+v", got, defaultLoopRules())
+	}
+
+	d.SetLoopWindow(8)
+	d.SetMaxDispatchCount(15)
+	d.SetSameAgentFailureLimit(5)
+	want := loopRules{window: 8, maxDispatches: 15, sameAgentLimit: 5}
+	if got := d.loopRules(); got != want {
+		t.Errorf("rules = %+v, want %+v", got, want)
+	}
+
+	// Out-of-range values fall back to defaults
+	d.SetLoopWindow(2)
+	d.SetMaxDispatchCount(-1)
+	d.SetSameAgentFailureLimit(0)
+	want = loopRules{window: DefaultLoopWindow, maxDispatches: -1, sameAgentLimit: DefaultSameAgentFailureLimit}
+	if got := d.loopRules(); got != want {
+		t.Errorf("rules = %+v, want %+v", got, want)
+	}
+}
+
+func TestDispatcher_SetTaskBudget(t *testing.T) {
+	d := &Dispatcher{}
+	if got := d.taskBudget("proj"); got != (taskBudget{}) {
+		t.Errorf("unset budget = %+v, want none", got)
+	}
+
+	d.SetTaskBudget("proj", 20000, 0.5)
+	if got, want := d.taskBudget("proj"), (taskBudget{maxTokens: 20000, maxCostUSD: 0.5}); got != want {
+		t.Errorf("budget = %+v, want %+v", got, want)
+	}
+	if got := d.taskBudget("other"); got != (taskBudget{}) {
+		t.Errorf("other project budget = %+v, want none", got)
+	}
+
+	d.SetTaskBudget("proj", -1, -1)
+	if got := d.taskBudget("proj"); got != (taskBudget{}) {
+		t.Errorf("negative budget = %+v, want none", got)
+	}
+}
+
 func TestDispatcher_SetWorkflowEngine(t *testing.T) {
 	d := &Dispatcher{}
 
@@ -392,16 +437,34 @@ func TestDispatcher_SetWorkflowEngine(t *testing.T) {
 	}
 }
 
+func TestDispatcher_EscalateWorkflowExecution_Guards(t *testing.T) {
+	d := &Dispatcher{}
+
+	// Only escalated executions need a CEO bead
+	if err := d.EscalateWorkflowExecution(nil); err != nil {
+		t.Errorf("Expected nil error for nil execution, got %v", err)
+	}
+	active := &workflow.WorkflowExecution{ID: "exec-1", Status: workflow.ExecutionStatusActive}
+	if err := d.EscalateWorkflowExecution(active); err != nil {
+		t.Errorf("Expected nil error for active execution, got %v", err)
+	}
+
+	escalated := &workflow.WorkflowExecution{ID: "exec-1", Status: workflow.ExecutionStatusEscalated}
+	if err := d.EscalateWorkflowExecution(escalated); err == nil {
+		t.Error("Expected error without a bead manager or workflow engine")
+	}
+}
+
 func TestDispatcher_SetReadinessCheck_WithFunc(t *testing.T) {
 	d := &Dispatcher{}
 
 	// Setting nil should work
 	d.SetReadinessCheck(nil)
 	d.mu.RLock()
-	rc := d.readinessCheck
+	rc := d.readinessCheckers
 	d.mu.RUnlock()
-	if rc != nil {
-		t.Error("Expected nil readinessCheck")
+	if len(rc) != 0 {
+		t.Error("Expected no readiness checkers")
 	}
 
 	// Setting a real function should work
@@ -409,10 +472,10 @@ func TestDispatcher_SetReadinessCheck_WithFunc(t *testing.T) {
 		return true, nil
 	})
 	d.mu.RLock()
-	rc2 := d.readinessCheck
+	rc2 := d.readinessCheckers
 	d.mu.RUnlock()
-	if rc2 == nil {
-		t.Error("Expected readinessCheck to be non-nil after setting")
+	if len(rc2) != 1 {
+		t.Error("Expected one readiness checker after setting")
 	}
 }
 
@@ -704,9 +767,9 @@ func TestNewDispatcher_DefaultFields(t *testing.T) {
 		t.Error("Expected workflowEngine to be nil by default")
 	}
 
-	// readinessCheck should be nil
-	if d.readinessCheck != nil {
-		t.Error("Expected readinessCheck to be nil by default")
+	// No readiness checkers by default
+	if len(d.readinessCheckers) != 0 {
+		t.Error("Expected no readiness checkers by default")
 	}
 }
 
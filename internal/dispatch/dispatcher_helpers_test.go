@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/jordanhubbard/loom/internal/provider"
+	"github.com/jordanhubbard/loom/internal/workflow"
 	"github.com/jordanhubbard/loom/pkg/models"
 )
 
@@ -249,7 +250,7 @@ func TestBuildBeadContext(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := buildBeadContext(tt.bead, tt.project)
+			result := buildBeadContext(tt.bead, tt.project, nil)
 			for _, expected := range tt.contains {
 				if !strings.Contains(result, expected) {
 					t.Errorf("buildBeadContext() result does not contain %q\nGot: %s", expected, result)
@@ -407,6 +408,108 @@ func TestBuildDispatchHistory_InvalidJSON(t *testing.T) {
 	}
 }
 
+func TestBuildDispatchHistoryWithRules(t *testing.T) {
+	history := func(ids ...string) *models.Bead {
+		b, _ := json.Marshal(ids)
+		return &models.Bead{ID: "b", Context: map[string]string{"dispatch_history": string(b)}}
+	}
+
+	tests := []struct {
+		name           string
+		bead           *models.Bead
+		agentID        string
+		rules          loopRules
+		expectedReason string
+	}{
+		{
+			name:           "custom alternation window",
+			bead:           history("a1", "a2", "a1"),
+			agentID:        "a2",
+			rules:          loopRules{window: 4},
+			expectedReason: "dispatch alternated between two agents for 4 runs",
+		},
+		{
+			name:           "same agent failing repeatedly",
+			bead:           history("a1", "a1"),
+			agentID:        "a1",
+			rules:          loopRules{window: 6, sameAgentLimit: 3},
+			expectedReason: "agent a1 failed 3 times in a row",
+		},
+		{
+			name:    "same agent interrupted by another agent",
+			bead:    history("a1", "a2"),
+			agentID: "a1",
+			rules:   loopRules{window: 6, sameAgentLimit: 3},
+		},
+		{
+			name:           "max dispatch count reached",
+			bead:           history("a1", "a2", "a3"),
+			agentID:        "a4",
+			rules:          loopRules{window: 6, maxDispatches: 4},
+			expectedReason: "bead failed 4 dispatches (max 4)",
+		},
+		{
+			name:    "max dispatch count disabled",
+			bead:    history("a1", "a2", "a3"),
+			agentID: "a4",
+			rules:   loopRules{window: 6},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, loopDetected, loopReason := buildDispatchHistoryWithRules(tt.bead, tt.agentID, tt.rules)
+			if loopDetected != (tt.expectedReason != "") {
+				t.Errorf("loopDetected = %v, want %v", loopDetected, tt.expectedReason != "")
+			}
+			if loopReason != tt.expectedReason {
+				t.Errorf("loopReason = %q, want %q", loopReason, tt.expectedReason)
+			}
+		})
+	}
+}
+
+func TestBuildDispatchHistoryWithRules_KeepsEnoughHistory(t *testing.T) {
+	ids := make([]string, 30)
+	for i := range ids {
+		ids[i] = "a1"
+	}
+	b, _ := json.Marshal(ids)
+	bead := &models.Bead{ID: "b", Context: map[string]string{"dispatch_history": string(b)}}
+
+	historyJSON, loopDetected, _ := buildDispatchHistoryWithRules(bead, "a1", loopRules{window: 6, sameAgentLimit: 25})
+	var history []string
+	if err := json.Unmarshal([]byte(historyJSON), &history); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(history) != 25 {
+		t.Errorf("kept %d entries, want 25", len(history))
+	}
+	if !loopDetected {
+		t.Error("expected same-agent loop beyond the default history length")
+	}
+}
+
+// --- filterByCapability tests ---
+
+func TestFilterByCapability(t *testing.T) {
+	security := &models.Agent{ID: "sec", Persona: &models.Persona{Capabilities: []string{"Code review", " Security analysis "}}}
+	coder := &models.Agent{ID: "dev", Persona: &models.Persona{Capabilities: []string{"Code review"}}}
+	bare := &models.Agent{ID: "bare"}
+	agents := []*models.Agent{nil, bare, coder, security}
+
+	got := filterByCapability(agents, "security ANALYSIS")
+	if len(got) != 1 || got[0].ID != "sec" {
+		t.Errorf("Expected only sec, got %v", got)
+	}
+	if got := filterByCapability(agents, "code review"); len(got) != 2 {
+		t.Errorf("Expected 2 agents with code review, got %d", len(got))
+	}
+	if got := filterByCapability(agents, "Threat modeling"); len(got) != 0 {
+		t.Errorf("Expected no agents, got %d", len(got))
+	}
+}
+
 // --- hasTag tests ---
 
 func TestHasTag(t *testing.T) {
@@ -618,8 +721,8 @@ func TestDispatcher_SetReadinessCheck(t *testing.T) {
 	// Just verify the setter doesn't panic
 	d.SetReadinessCheck(nil)
 	d.mu.RLock()
-	if d.readinessCheck != nil {
-		t.Error("Expected readinessCheck to be nil after setting nil")
+	if len(d.readinessCheckers) != 0 {
+		t.Error("Expected no readiness checkers after setting nil")
 	}
 	d.mu.RUnlock()
 }
@@ -987,3 +1090,68 @@ func TestDispatcher_ConcurrentSetStatus(t *testing.T) {
 		t.Errorf("Expected valid state, got %q", status.State)
 	}
 }
+
+func TestBuildBeadContext_PersonaOutputFormat(t *testing.T) {
+	bug := &models.Bead{
+		ID:       "bead-bug",
+		Title:    "[auto-filed] Panic in handler",
+		Priority: models.BeadPriorityP1,
+		Type:     "bug",
+	}
+	project := &models.Project{ID: "proj-1", Name: "Proj", Branch: "main"}
+
+	// Auto-filed bugs get the investigation workflow by default.
+	result := buildBeadContext(bug, project, &models.Persona{Name: "coder"})
+	for _, want := range []string{"## Instructions", "## Bug Investigation"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("default context missing %q", want)
+		}
+	}
+
+	// A persona's output format replaces both.
+	reviewer := &models.Persona{
+		Name:         "reviewer",
+		OutputFormat: "## Output\n\nReply with a unified diff for {{.Bead.ID}} on {{.Project.Branch}}.",
+	}
+	result = buildBeadContext(bug, project, reviewer)
+	if !strings.Contains(result, "Reply with a unified diff for bead-bug on main.") {
+		t.Errorf("output format not rendered:\n%s", result)
+	}
+	for _, unwanted := range []string{"## Instructions", "## Bug Investigation"} {
+		if strings.Contains(result, unwanted) {
+			t.Errorf("context with output format still contains %q", unwanted)
+		}
+	}
+
+	// Without a project the template still renders.
+	result = buildBeadContext(bug, nil, reviewer)
+	if !strings.Contains(result, "Reply with a unified diff for bead-bug on .") {
+		t.Errorf("output format without project not rendered:\n%s", result)
+	}
+
+	// A broken template falls back to the default instructions.
+	broken := &models.Persona{Name: "broken", OutputFormat: "{{.Bead.Nope"}
+	result = buildBeadContext(bug, project, broken)
+	if !strings.Contains(result, "## Instructions") {
+		t.Errorf("broken output format should fall back to defaults:\n%s", result)
+	}
+}
+
+// --- leftDispatchedNode tests ---
+
+func TestLeftDispatchedNode(t *testing.T) {
+	exec := &workflow.WorkflowExecution{CurrentNodeKey: "review", Status: workflow.ExecutionStatusActive}
+	tests := []struct {
+		dispatchedNode string
+		want           bool
+	}{
+		{"", false},       // dispatched before the bead had a workflow
+		{"review", false}, // still on the node the task was for
+		{"work", true},    // a timeout moved the execution on
+	}
+	for _, tt := range tests {
+		if got := leftDispatchedNode(exec, tt.dispatchedNode); got != tt.want {
+			t.Errorf("leftDispatchedNode(%q) = %v, want %v", tt.dispatchedNode, got, tt.want)
+		}
+	}
+}
@@ -4,7 +4,11 @@ import (
 	"context"
 	"testing"
 
+	"github.com/jordanhubbard/loom/internal/agent"
+	"github.com/jordanhubbard/loom/internal/beads"
+	"github.com/jordanhubbard/loom/internal/provider"
 	"github.com/jordanhubbard/loom/pkg/messages"
+	"github.com/jordanhubbard/loom/pkg/models"
 )
 
 // MockSimpleMessageBus implements the MessageBus interface for testing
@@ -136,3 +140,37 @@ func TestMessageBusPublishPattern(t *testing.T) {
 	t.Log("")
 	t.Log("✅ Full async communication flow documented")
 }
+
+// TestHandleTaskResult_RecordsAgentOutput verifies project agent output lands
+// in the log of the agent the bead is assigned to
+func TestHandleTaskResult_RecordsAgentOutput(t *testing.T) {
+	bm := beads.NewManager("")
+	bm.SetBeadsPath(t.TempDir())
+	am := agent.NewWorkerManager(5, provider.NewRegistry(), nil)
+	a, err := am.CreateAgent(context.Background(), "Engineer", "default/engineer", "proj", "", &models.Persona{Name: "default/engineer"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := bm.CreateBead("build it", "", models.BeadPriorityP2, "task", "proj")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bm.ClaimBead(b.ID, a.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	d := NewDispatcher(bm, nil, am, nil, nil)
+	d.handleTaskResult(messages.TaskProgress("proj", b.ID, "proj", messages.ResultData{Status: "in_progress", Output: "compiling"}, "c1"))
+	d.handleTaskResult(messages.TaskFailed("proj", b.ID, "proj", messages.ResultData{Status: "failure", Output: "2 tests failed", Error: "exit status 1"}, "c1"))
+
+	logs := am.GetAgentLogs(a.ID, 0)
+	if len(logs) != 3 {
+		t.Fatalf("got %d log lines, want 3: %+v", len(logs), logs)
+	}
+	if logs[0].Message != "compiling" || logs[0].Source != agent.LogSourceProjectAgent {
+		t.Errorf("first line = %+v, want the progress output from the project agent", logs[0])
+	}
+	if logs[2].Message != "exit status 1" || logs[2].Level != "error" {
+		t.Errorf("last line = %+v, want the error", logs[2])
+	}
+}
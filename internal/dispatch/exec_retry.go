@@ -0,0 +1,83 @@
+package dispatch
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/jordanhubbard/loom/internal/provider"
+	"github.com/jordanhubbard/loom/internal/worker"
+)
+
+// DefaultExecRetryBackoff is the wait before the first in-tick retry when
+// none is configured.
+const DefaultExecRetryBackoff = 2 * time.Second
+
+// SetExecRetry sets how many times a task that failed transiently is retried
+// within the same dispatch, and the wait before the first retry. Zero
+// retries disables retrying; a non-positive backoff uses the default.
+func (d *Dispatcher) SetExecRetry(retries int, backoff time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if retries < 0 {
+		retries = 0
+	}
+	if backoff <= 0 {
+		backoff = DefaultExecRetryBackoff
+	}
+	d.execRetries = retries
+	d.execRetryBackoff = backoff
+}
+
+// executeWithRetry runs exec and retries it while it fails transiently,
+// i.e. the provider was unavailable or rate limited. Provider failover has
+// already been tried by then, so this waits out the outage instead. A task
+// whose action loop had already executed actions is not retried, since
+// running it again would repeat them.
+func (d *Dispatcher) executeWithRetry(ctx context.Context, beadID string, exec func(context.Context) (*worker.TaskResult, error)) (*worker.TaskResult, error) {
+	d.mu.RLock()
+	retries, backoff := d.execRetries, d.execRetryBackoff
+	d.mu.RUnlock()
+
+	result, err := exec(ctx)
+	for attempt := 1; attempt <= retries && retryable(err); attempt++ {
+		wait := backoff << (attempt - 1)
+		log.Printf("[Dispatcher] Task for bead %s failed transiently (%v), retry %d/%d in %s", beadID, err, attempt, retries, wait)
+		select {
+		case <-ctx.Done():
+			return result, err
+		case <-time.After(wait):
+		}
+		result, err = exec(ctx)
+	}
+	return result, err
+}
+
+// retryable reports whether err is a transient provider failure that struck
+// before the task executed any action.
+func retryable(err error) bool {
+	if err == nil || !provider.IsFailoverError(err) {
+		return false
+	}
+	var loopErr *worker.LoopCallError
+	return !errors.As(err, &loopErr) || loopErr.ActionsRun == 0
+}
+
+// cancelledDuring reports whether a failed task was stopped by CancelBead
+// rather than by a real failure: either the error is the task's cancelled
+// context, or the bead was marked cancelled after the task started.
+func (d *Dispatcher) cancelledDuring(beadID string, err error, since time.Time) bool {
+	if errors.Is(err, context.Canceled) {
+		return true
+	}
+	if d.beads == nil {
+		return false
+	}
+	b, getErr := d.beads.GetBead(beadID)
+	if getErr != nil || b == nil || b.Context["cancelled_by"] == "" {
+		return false
+	}
+	at, parseErr := time.Parse(time.RFC3339, b.Context["cancelled_at"])
+	return parseErr == nil && !at.Before(since.Truncate(time.Second))
+}
@@ -0,0 +1,134 @@
+package dispatch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jordanhubbard/loom/internal/beads"
+	"github.com/jordanhubbard/loom/internal/provider"
+	"github.com/jordanhubbard/loom/internal/worker"
+	"github.com/jordanhubbard/loom/pkg/models"
+)
+
+// flakyExec fails with each of errs in turn, then succeeds.
+func flakyExec(errs ...error) (func(context.Context) (*worker.TaskResult, error), *int) {
+	calls := 0
+	return func(ctx context.Context) (*worker.TaskResult, error) {
+		calls++
+		if calls <= len(errs) {
+			return nil, errs[calls-1]
+		}
+		return &worker.TaskResult{TaskID: "t1", Success: true}, nil
+	}, &calls
+}
+
+func TestExecuteWithRetry_TwoFailuresThenSuccess(t *testing.T) {
+	d := &Dispatcher{}
+	d.SetExecRetry(2, time.Millisecond)
+
+	unavailable := &provider.StatusError{StatusCode: 503, Body: "overloaded"}
+	exec, calls := flakyExec(unavailable, errors.New("unexpected status code 429: slow down"))
+	result, err := d.executeWithRetry(t.Context(), "b1", exec)
+	if err != nil {
+		t.Fatalf("executeWithRetry() error = %v", err)
+	}
+	if result == nil || !result.Success {
+		t.Errorf("result = %+v, want the successful third attempt", result)
+	}
+	if *calls != 3 {
+		t.Errorf("calls = %d, want 3", *calls)
+	}
+}
+
+func TestExecuteWithRetry_GivesUp(t *testing.T) {
+	unavailable := &provider.StatusError{StatusCode: 503, Body: "overloaded"}
+
+	d := &Dispatcher{}
+	d.SetExecRetry(1, time.Millisecond)
+	exec, calls := flakyExec(unavailable, unavailable)
+	if _, err := d.executeWithRetry(t.Context(), "b1", exec); !errors.Is(err, provider.ErrProviderUnavailable) {
+		t.Errorf("error = %v, want the last transient failure", err)
+	}
+	if *calls != 2 {
+		t.Errorf("calls = %d, want 2 with one retry", *calls)
+	}
+
+	// Permanent failures and a zero-value dispatcher do not retry
+	d.SetExecRetry(3, time.Millisecond)
+	exec, calls = flakyExec(&provider.StatusError{StatusCode: 401, Body: "bad key"})
+	if _, err := d.executeWithRetry(t.Context(), "b1", exec); err == nil || *calls != 1 {
+		t.Errorf("unauthorized: err = %v after %d calls, want failure after 1", err, *calls)
+	}
+	exec, calls = flakyExec(unavailable)
+	if _, err := (&Dispatcher{}).executeWithRetry(t.Context(), "b1", exec); err == nil || *calls != 1 {
+		t.Errorf("no retries configured: err = %v after %d calls, want failure after 1", err, *calls)
+	}
+}
+
+func TestExecuteWithRetry_NotAfterActions(t *testing.T) {
+	unavailable := &provider.StatusError{StatusCode: 503, Body: "overloaded"}
+	d := &Dispatcher{}
+	d.SetExecRetry(2, time.Millisecond)
+
+	// The loop failed on a later iteration after running actions.
+	midLoop := fmt.Errorf("action loop failed: %w", &worker.LoopCallError{Iteration: 3, ActionsRun: 2, Err: unavailable})
+	exec, calls := flakyExec(midLoop)
+	if _, err := d.executeWithRetry(t.Context(), "b1", exec); err == nil || *calls != 1 {
+		t.Errorf("err = %v after %d calls, want no retry once actions have run", err, *calls)
+	}
+
+	// Failing before any action ran is still retried.
+	firstCall := fmt.Errorf("action loop failed: %w", &worker.LoopCallError{Iteration: 1, Err: unavailable})
+	exec, calls = flakyExec(firstCall)
+	if _, err := d.executeWithRetry(t.Context(), "b1", exec); err != nil || *calls != 2 {
+		t.Errorf("err = %v after %d calls, want success on the retry", err, *calls)
+	}
+}
+
+func TestSetExecRetry_Normalizes(t *testing.T) {
+	d := &Dispatcher{}
+	d.SetExecRetry(-1, 0)
+	if d.execRetries != 0 || d.execRetryBackoff != DefaultExecRetryBackoff {
+		t.Errorf("retries = %d, backoff = %s, want 0 and the default", d.execRetries, d.execRetryBackoff)
+	}
+}
+
+func TestCancelledDuring(t *testing.T) {
+	bm := beads.NewManager("")
+	bm.SetBeadsPath(t.TempDir())
+	b, err := bm.CreateBead("cancelled task", "", models.BeadPriorityP2, "task", "proj")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := NewDispatcher(bm, nil, nil, nil, nil)
+	start := time.Now()
+
+	if !d.cancelledDuring(b.ID, fmt.Errorf("loop: %w", context.Canceled), start) {
+		t.Error("a cancelled task context should count as a cancel")
+	}
+	if d.cancelledDuring(b.ID, errors.New("build failed"), start) {
+		t.Error("a plain failure should not count as a cancel")
+	}
+
+	// The worker may surface the cancel as some other error; the bead's
+	// cancelled_by marks it as a cancel only if set after the task started.
+	cancel := func(at time.Time) {
+		if err := bm.UpdateBead(b.ID, map[string]interface{}{"context": map[string]string{
+			"cancelled_by": "alice",
+			"cancelled_at": at.UTC().Format(time.RFC3339),
+		}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cancel(start.Add(-time.Hour))
+	if d.cancelledDuring(b.ID, errors.New("stream closed"), start) {
+		t.Error("a cancel from an earlier run should not count")
+	}
+	cancel(start)
+	if !d.cancelledDuring(b.ID, errors.New("stream closed"), start) {
+		t.Error("a cancel during the run should count")
+	}
+}
@@ -0,0 +1,173 @@
+package dispatch
+
+import (
+	"sync"
+	"time"
+
+	"github.com/jordanhubbard/loom/internal/metrics"
+)
+
+// latencyWindow is how many recent task executions the average latency covers.
+const latencyWindow = 100
+
+// DispatchMetrics summarizes dispatcher activity since it started.
+type DispatchMetrics struct {
+	Attempts          int64            `json:"attempts"`   // Dispatch passes that looked for work
+	Dispatched        int64            `json:"dispatched"` // Beads handed to an agent
+	Parked            int64            `json:"parked"`     // Dispatch passes that parked without handing out a bead
+	Succeeded         int64            `json:"succeeded"`  // Task executions that returned a result
+	Failed            int64            `json:"failed"`     // Task executions that returned an error
+	SkipReasons       map[string]int64 `json:"skip_reasons"`
+	AffinityHonored   int64            `json:"affinity_honored"`   // Beads with a preferred agent that it took
+	AffinityFallbacks int64            `json:"affinity_fallbacks"` // Beads with a preferred agent that went to another agent
+	AvgExecutionMs    float64          `json:"avg_execution_ms"`   // Over the last 100 executions
+	LastDispatchAt    *time.Time       `json:"last_dispatch_at,omitempty"`
+	Status            SystemStatus     `json:"status"`
+	Since             time.Time        `json:"since"`
+}
+
+// dispatchMetrics accumulates DispatchMetrics. The zero value is ready to use.
+type dispatchMetrics struct {
+	mu             sync.Mutex
+	since          time.Time
+	attempts       int64
+	dispatched     int64
+	parked         int64
+	succeeded      int64
+	failed         int64
+	skipReasons    map[string]int64
+	affinityHit    int64
+	affinityMiss   int64
+	latencies      []time.Duration // Ring buffer of recent execution times
+	nextLatency    int
+	lastDispatchAt time.Time
+	exporter       *metrics.Metrics // Prometheus counterparts; nil when not exported
+}
+
+// recordAttempt records one dispatch pass and the reasons beads were skipped in it.
+func (m *dispatchMetrics) recordAttempt(skipped map[string]int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.init()
+	m.attempts++
+	for reason, n := range skipped {
+		m.skipReasons[reason] += int64(n)
+	}
+}
+
+func (m *dispatchMetrics) recordDispatch() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.init()
+	m.dispatched++
+	m.lastDispatchAt = time.Now()
+	if m.exporter != nil {
+		m.exporter.RecordDispatch()
+	}
+}
+
+// recordParked records a dispatch pass that parked the dispatcher.
+func (m *dispatchMetrics) recordParked() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.init()
+	m.parked++
+}
+
+func (m *dispatchMetrics) recordAffinity(honored bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if honored {
+		m.affinityHit++
+	} else {
+		m.affinityMiss++
+	}
+}
+
+func (m *dispatchMetrics) recordExecution(elapsed time.Duration, succeeded bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.init()
+	if succeeded {
+		m.succeeded++
+	} else {
+		m.failed++
+		if m.exporter != nil {
+			m.exporter.RecordDispatchFailure()
+		}
+	}
+	if len(m.latencies) < latencyWindow {
+		m.latencies = append(m.latencies, elapsed)
+		return
+	}
+	m.latencies[m.nextLatency] = elapsed
+	m.nextLatency = (m.nextLatency + 1) % latencyWindow
+}
+
+func (m *dispatchMetrics) recordStatus(state StatusState) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.exporter != nil {
+		m.exporter.SetDispatcherStatus(string(state))
+	}
+}
+
+func (m *dispatchMetrics) init() {
+	if m.skipReasons == nil {
+		m.skipReasons = make(map[string]int64)
+	}
+	if m.since.IsZero() {
+		m.since = time.Now()
+	}
+}
+
+func (m *dispatchMetrics) snapshot() DispatchMetrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.init()
+
+	out := DispatchMetrics{
+		Attempts:    m.attempts,
+		Dispatched:  m.dispatched,
+		Parked:      m.parked,
+		Succeeded:   m.succeeded,
+		Failed:      m.failed,
+		SkipReasons: make(map[string]int64, len(m.skipReasons)),
+		Since:       m.since,
+
+		AffinityHonored:   m.affinityHit,
+		AffinityFallbacks: m.affinityMiss,
+	}
+	for reason, n := range m.skipReasons {
+		out.SkipReasons[reason] = n
+	}
+	if len(m.latencies) > 0 {
+		var total time.Duration
+		for _, l := range m.latencies {
+			total += l
+		}
+		out.AvgExecutionMs = float64(total) / float64(len(m.latencies)) / float64(time.Millisecond)
+	}
+	if !m.lastDispatchAt.IsZero() {
+		last := m.lastDispatchAt
+		out.LastDispatchAt = &last
+	}
+	return out
+}
+
+// GetDispatchMetrics returns dispatch counts, skip reasons and execution
+// latency since the dispatcher started, along with its current status.
+func (d *Dispatcher) GetDispatchMetrics() DispatchMetrics {
+	metrics := d.metrics.snapshot()
+	metrics.Status = d.GetSystemStatus()
+	return metrics
+}
+
+// SetMetrics exports dispatches, failed executions and the dispatcher's
+// status to Prometheus through m.
+func (d *Dispatcher) SetMetrics(m *metrics.Metrics) {
+	d.metrics.mu.Lock()
+	d.metrics.exporter = m
+	d.metrics.mu.Unlock()
+	d.metrics.recordStatus(d.GetSystemStatus().State)
+}
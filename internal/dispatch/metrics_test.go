@@ -0,0 +1,84 @@
+package dispatch
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jordanhubbard/loom/internal/provider"
+)
+
+func TestDispatchMetrics_Snapshot(t *testing.T) {
+	var m dispatchMetrics
+	m.recordAttempt(map[string]int{"already_run": 2, "no_idle_agents_for_project": 1})
+	m.recordAttempt(map[string]int{"already_run": 1})
+	m.recordDispatch()
+	m.recordExecution(100*time.Millisecond, true)
+	m.recordExecution(300*time.Millisecond, false)
+
+	got := m.snapshot()
+	if got.Attempts != 2 || got.Dispatched != 1 || got.Succeeded != 1 || got.Failed != 1 {
+		t.Errorf("counts = %+v", got)
+	}
+	if got.SkipReasons["already_run"] != 3 || got.SkipReasons["no_idle_agents_for_project"] != 1 {
+		t.Errorf("SkipReasons = %v", got.SkipReasons)
+	}
+	if got.AvgExecutionMs != 200 {
+		t.Errorf("AvgExecutionMs = %v, want 200", got.AvgExecutionMs)
+	}
+	if got.LastDispatchAt == nil {
+		t.Error("expected LastDispatchAt to be set")
+	}
+
+	// The snapshot is a copy
+	got.SkipReasons["already_run"] = 0
+	if m.snapshot().SkipReasons["already_run"] != 3 {
+		t.Error("snapshot shares its map with the accumulator")
+	}
+}
+
+func TestDispatchMetrics_LatencyWindow(t *testing.T) {
+	var m dispatchMetrics
+	for i := 0; i < latencyWindow; i++ {
+		m.recordExecution(time.Second, true)
+	}
+	for i := 0; i < latencyWindow; i++ {
+		m.recordExecution(10*time.Millisecond, true)
+	}
+
+	got := m.snapshot()
+	if got.AvgExecutionMs != 10 {
+		t.Errorf("AvgExecutionMs = %v, want 10 once old executions roll off", got.AvgExecutionMs)
+	}
+	if got.Succeeded != 2*latencyWindow {
+		t.Errorf("Succeeded = %d, want %d", got.Succeeded, 2*latencyWindow)
+	}
+}
+
+func TestDispatcher_GetDispatchMetrics(t *testing.T) {
+	d := &Dispatcher{}
+	d.setStatus(StatusParked, "no dispatchable beads")
+	d.metrics.recordAttempt(map[string]int{"decision_type": 1})
+
+	got := d.GetDispatchMetrics()
+	if got.Status.Reason != "no dispatchable beads" {
+		t.Errorf("Status.Reason = %q", got.Status.Reason)
+	}
+	if got.SkipReasons["decision_type"] != 1 {
+		t.Errorf("SkipReasons = %v", got.SkipReasons)
+	}
+}
+
+func TestDispatcher_MetricsCountParkedPasses(t *testing.T) {
+	d := NewDispatcher(nil, nil, nil, provider.NewRegistry(), nil)
+	for i := 0; i < 2; i++ {
+		if _, err := d.DispatchOnce(context.Background(), "proj-1"); err != nil {
+			t.Fatalf("DispatchOnce: %v", err)
+		}
+	}
+
+	got := d.GetDispatchMetrics()
+	if got.Attempts != 2 || got.Parked != 2 || got.Dispatched != 0 {
+		t.Errorf("counts = %+v, want 2 attempts parked without providers", got)
+	}
+}
@@ -3,6 +3,7 @@ package dispatch
 import (
 	"regexp"
 	"strings"
+	"sync"
 
 	"github.com/jordanhubbard/loom/pkg/models"
 )
@@ -11,6 +12,27 @@ import (
 type PersonaMatcher struct {
 	// Patterns for extracting persona hints from text
 	patterns []*regexp.Regexp
+
+	mu         sync.RWMutex
+	strategies []MatchStrategy
+}
+
+// MatchStrategy is a fallback way of matching a persona hint to an agent,
+// tried when the built-in name and role matching finds nothing. Match
+// returns the best candidate and its score, or nil if none qualifies.
+type MatchStrategy interface {
+	Match(hint string, agents []*models.Agent) (*models.Agent, float64)
+}
+
+// AddStrategy appends a fallback strategy. Strategies are tried in the order
+// they were added, after the built-in matching.
+func (pm *PersonaMatcher) AddStrategy(s MatchStrategy) {
+	if s == nil {
+		return
+	}
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	pm.strategies = append(pm.strategies, s)
 }
 
 func NewPersonaMatcher() *PersonaMatcher {
@@ -115,13 +137,34 @@ func normalizePersonaHint(hint string) string {
 	return hint
 }
 
-// FindAgentByPersonaHint finds the best matching agent for a persona hint
+// FindAgentByPersonaHint finds the best matching agent for a persona hint.
+// Exact and substring matching on persona name and role run first; added
+// strategies are only consulted when those find nothing.
 func (pm *PersonaMatcher) FindAgentByPersonaHint(hint string, agents []*models.Agent) *models.Agent {
 	if hint == "" || len(agents) == 0 {
 		return nil
 	}
 
 	hint = strings.ToLower(hint)
+	if agent := matchPersonaNameOrRole(hint, agents); agent != nil {
+		return agent
+	}
+
+	pm.mu.RLock()
+	strategies := pm.strategies
+	pm.mu.RUnlock()
+	for _, s := range strategies {
+		if agent, _ := s.Match(hint, agents); agent != nil {
+			return agent
+		}
+	}
+
+	return nil
+}
+
+// matchPersonaNameOrRole is the default matching: exact persona name, then
+// substring on persona name, then role.
+func matchPersonaNameOrRole(hint string, agents []*models.Agent) *models.Agent {
 
 	// First pass: exact match on PersonaName (without default/ prefix)
 	for _, agent := range agents {
@@ -168,6 +211,5 @@ func (pm *PersonaMatcher) FindAgentByPersonaHint(hint string, agents []*models.A
 		}
 	}
 
-	// No match found
 	return nil
 }
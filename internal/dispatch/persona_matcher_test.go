@@ -406,3 +406,121 @@ func TestFindAgentByPersonaHint(t *testing.T) {
 		})
 	}
 }
+
+func TestFindAgentByPersonaHint_Strategies(t *testing.T) {
+	agents := []*models.Agent{
+		{ID: "a1", PersonaName: "default/backend-engineer", Role: "Backend Engineer"},
+		{ID: "a2", PersonaName: "security-auditor", Role: "Auditor",
+			Persona: &models.Persona{Capabilities: []string{"Security review", "threat modeling"}}},
+	}
+
+	pm := NewPersonaMatcher()
+	if got := pm.FindAgentByPersonaHint("backend-enginer", agents); got != nil {
+		t.Fatalf("Expected no match without strategies, got %s", got.ID)
+	}
+
+	pm.AddStrategy(FuzzyStrategy{})
+	pm.AddStrategy(CapabilityStrategy{})
+
+	tests := []struct {
+		hint       string
+		expectedID string
+	}{
+		{"backend-engineer", "a1"}, // exact match still wins
+		{"backend-enginer", "a1"},  // fuzzy
+		{"threat-modeling", "a2"},  // capability
+		{"security-review", "a2"},  // capability
+		{"frontend-designer", ""},  // nothing close
+	}
+	for _, tt := range tests {
+		got := pm.FindAgentByPersonaHint(tt.hint, agents)
+		gotID := ""
+		if got != nil {
+			gotID = got.ID
+		}
+		if gotID != tt.expectedID {
+			t.Errorf("FindAgentByPersonaHint(%q) = %q, want %q", tt.hint, gotID, tt.expectedID)
+		}
+	}
+}
+
+type fixedStrategy struct{ agent *models.Agent }
+
+func (s fixedStrategy) Match(string, []*models.Agent) (*models.Agent, float64) {
+	return s.agent, 1
+}
+
+func TestPersonaMatcher_StrategyOrder(t *testing.T) {
+	first := &models.Agent{ID: "first"}
+	second := &models.Agent{ID: "second"}
+	pm := NewPersonaMatcher()
+	pm.AddStrategy(nil)
+	pm.AddStrategy(fixedStrategy{})
+	pm.AddStrategy(fixedStrategy{agent: first})
+	pm.AddStrategy(fixedStrategy{agent: second})
+
+	agents := []*models.Agent{{ID: "other", PersonaName: "qa-engineer", Role: "QA"}}
+	if got := pm.FindAgentByPersonaHint("unrelated", agents); got != first {
+		t.Errorf("Expected first matching strategy to win, got %v", got)
+	}
+}
+
+func TestFuzzyStrategy(t *testing.T) {
+	agents := []*models.Agent{
+		{ID: "a1", PersonaName: "devops-engineer"},
+		{ID: "a2", PersonaName: "qa-engineer", Role: "QA Engineer"},
+	}
+	agent, score := FuzzyStrategy{}.Match("qa-enginere", agents)
+	if agent == nil || agent.ID != "a2" {
+		t.Fatalf("Expected a2, got %v", agent)
+	}
+	if score <= 0 || score >= 1 {
+		t.Errorf("Expected score in (0,1), got %f", score)
+	}
+	if agent, _ := (FuzzyStrategy{MaxDistance: 1}).Match("dev-ops-enginer", agents); agent != nil {
+		t.Errorf("Expected no match beyond max distance, got %s", agent.ID)
+	}
+}
+
+func TestCapabilityStrategy_MinScore(t *testing.T) {
+	agents := []*models.Agent{
+		{ID: "a1", Persona: &models.Persona{Capabilities: []string{"database tuning"}}},
+		{ID: "a2", Persona: &models.Persona{Capabilities: []string{"database migrations", "schema design"}}},
+	}
+	agent, score := CapabilityStrategy{}.Match("database-schema", agents)
+	if agent == nil || agent.ID != "a2" || score != 1 {
+		t.Fatalf("Expected a2 with score 1, got %v (%f)", agent, score)
+	}
+	if agent, _ := (CapabilityStrategy{MinScore: 0.9}).Match("database-index-tuning", agents); agent != nil {
+		t.Errorf("Expected no match below min score, got %s", agent.ID)
+	}
+}
+
+func TestPersonaStrategyByName(t *testing.T) {
+	if _, ok := PersonaStrategyByName("Fuzzy"); !ok {
+		t.Error("Expected fuzzy strategy")
+	}
+	if _, ok := PersonaStrategyByName("capability"); !ok {
+		t.Error("Expected capability strategy")
+	}
+	if _, ok := PersonaStrategyByName("semantic"); ok {
+		t.Error("Expected unknown strategy to be rejected")
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"kitten", "sitting", 3},
+		{"qa-engineer", "qa-engineer", 0},
+	}
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package dispatch
+
+import (
+	"strings"
+
+	"github.com/jordanhubbard/loom/pkg/models"
+)
+
+const (
+	// DefaultFuzzyMaxDistance is the edit distance FuzzyStrategy accepts
+	// when MaxDistance is unset.
+	DefaultFuzzyMaxDistance = 2
+	// DefaultCapabilityMinScore is the share of hint words CapabilityStrategy
+	// requires when MinScore is unset.
+	DefaultCapabilityMinScore = 0.5
+)
+
+// FuzzyStrategy matches hints with typos or small spelling differences by
+// Levenshtein distance to each agent's persona name and role.
+type FuzzyStrategy struct {
+	MaxDistance int // Largest accepted edit distance (0 = DefaultFuzzyMaxDistance)
+}
+
+// Match returns the agent closest to hint, scored 1 for identical names down
+// to 0 for completely different ones.
+func (s FuzzyStrategy) Match(hint string, agents []*models.Agent) (*models.Agent, float64) {
+	maxDist := s.MaxDistance
+	if maxDist <= 0 {
+		maxDist = DefaultFuzzyMaxDistance
+	}
+	hint = normalizePersonaHint(hint)
+	if hint == "" {
+		return nil, 0
+	}
+
+	var best *models.Agent
+	bestScore := 0.0
+	for _, agent := range agents {
+		if agent == nil {
+			continue
+		}
+		names := []string{
+			strings.TrimPrefix(strings.ToLower(agent.PersonaName), "default/"),
+			normalizePersonaHint(agent.Role),
+		}
+		for _, name := range names {
+			if name == "" {
+				continue
+			}
+			dist := levenshtein(hint, name)
+			if dist > maxDist {
+				continue
+			}
+			score := 1 - float64(dist)/float64(max(len([]rune(hint)), len([]rune(name))))
+			if score > bestScore {
+				best, bestScore = agent, score
+			}
+		}
+	}
+	return best, bestScore
+}
+
+// CapabilityStrategy matches hints against the capabilities listed on each
+// agent's persona, e.g. "security-review" against a persona declaring
+// "security review".
+type CapabilityStrategy struct {
+	MinScore float64 // Share of hint words that must appear (0 = DefaultCapabilityMinScore)
+}
+
+// Match returns the agent whose capabilities cover the largest share of the
+// hint's words.
+func (s CapabilityStrategy) Match(hint string, agents []*models.Agent) (*models.Agent, float64) {
+	minScore := s.MinScore
+	if minScore <= 0 {
+		minScore = DefaultCapabilityMinScore
+	}
+	words := strings.Split(normalizePersonaHint(hint), "-")
+	if len(words) == 0 || words[0] == "" {
+		return nil, 0
+	}
+
+	var best *models.Agent
+	bestScore := 0.0
+	for _, agent := range agents {
+		if agent == nil || agent.Persona == nil || len(agent.Persona.Capabilities) == 0 {
+			continue
+		}
+		have := make(map[string]bool)
+		for _, capability := range agent.Persona.Capabilities {
+			for _, w := range strings.Split(normalizePersonaHint(capability), "-") {
+				have[w] = true
+			}
+		}
+		matched := 0
+		for _, w := range words {
+			if have[w] {
+				matched++
+			}
+		}
+		score := float64(matched) / float64(len(words))
+		if score >= minScore && score > bestScore {
+			best, bestScore = agent, score
+		}
+	}
+	return best, bestScore
+}
+
+// PersonaStrategyByName returns the built-in strategy for a config name
+// ("fuzzy" or "capability") with default settings.
+func PersonaStrategyByName(name string) (MatchStrategy, bool) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "fuzzy", "levenshtein":
+		return FuzzyStrategy{}, true
+	case "capability", "capabilities":
+		return CapabilityStrategy{}, true
+	default:
+		return nil, false
+	}
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
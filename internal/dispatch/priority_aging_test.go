@@ -0,0 +1,58 @@
+package dispatch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jordanhubbard/loom/pkg/models"
+)
+
+func TestSortReadyBeads_PriorityAging(t *testing.T) {
+	now := time.Now()
+	ready := func() []*models.Bead {
+		return []*models.Bead{
+			{ID: "fresh-p2", Priority: models.BeadPriorityP2, UpdatedAt: now.Add(-time.Minute)},
+			{ID: "old-p3", Priority: models.BeadPriorityP3, UpdatedAt: now.Add(-3 * time.Hour)},
+			{ID: "fresh-p1", Priority: models.BeadPriorityP1, UpdatedAt: now.Add(-time.Minute)},
+		}
+	}
+
+	d := NewDispatcher(nil, nil, nil, nil, nil)
+	beads := ready()
+	d.sortReadyBeads(beads, now)
+	if beads[0].ID != "fresh-p1" || beads[2].ID != "old-p3" {
+		t.Fatalf("without aging got order %s, %s, %s; want strict priority", beads[0].ID, beads[1].ID, beads[2].ID)
+	}
+
+	// Three hours at one level per hour takes the P3 to an effective P0.
+	d.SetPriorityAging(time.Hour)
+	beads = ready()
+	d.sortReadyBeads(beads, now)
+	if beads[0].ID != "old-p3" {
+		t.Fatalf("with aging selected %s first, want the aged old-p3", beads[0].ID)
+	}
+	if beads[1].ID != "fresh-p1" || beads[2].ID != "fresh-p2" {
+		t.Errorf("with aging got %s, %s after old-p3; want fresh-p1, fresh-p2", beads[1].ID, beads[2].ID)
+	}
+}
+
+func TestEffectivePriority(t *testing.T) {
+	now := time.Now()
+	b := &models.Bead{Priority: models.BeadPriorityP3, UpdatedAt: now.Add(-90 * time.Minute)}
+
+	if got := effectivePriority(b, now, 0); got != 3 {
+		t.Errorf("no aging: got %v, want 3", got)
+	}
+	if got := effectivePriority(b, now, time.Hour); got != 1.5 {
+		t.Errorf("hourly aging: got %v, want 1.5", got)
+	}
+	if got := effectivePriority(&models.Bead{Priority: models.BeadPriorityP2}, now, time.Hour); got != 2 {
+		t.Errorf("zero UpdatedAt: got %v, want 2", got)
+	}
+
+	d := NewDispatcher(nil, nil, nil, nil, nil)
+	d.SetPriorityAging(-time.Hour)
+	if d.priorityAging != 0 {
+		t.Errorf("negative interval should disable aging, got %v", d.priorityAging)
+	}
+}
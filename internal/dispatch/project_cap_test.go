@@ -0,0 +1,63 @@
+package dispatch
+
+import (
+	"testing"
+
+	"github.com/jordanhubbard/loom/internal/beads"
+	"github.com/jordanhubbard/loom/pkg/models"
+)
+
+func TestDispatcher_SetMaxConcurrentPerProject(t *testing.T) {
+	d := NewDispatcher(nil, nil, nil, nil, nil)
+	if d.maxPerProject != 0 {
+		t.Errorf("default maxPerProject = %d, want 0 (unlimited)", d.maxPerProject)
+	}
+	d.SetMaxConcurrentPerProject(3)
+	if d.maxPerProject != 3 {
+		t.Errorf("maxPerProject = %d, want 3", d.maxPerProject)
+	}
+	d.SetMaxConcurrentPerProject(-1)
+	if d.maxPerProject != 0 {
+		t.Errorf("negative cap should mean unlimited, got %d", d.maxPerProject)
+	}
+}
+
+func TestDispatcher_ProjectCapReached(t *testing.T) {
+	bm := beads.NewManager("")
+	bm.SetBeadsPath(t.TempDir())
+	var hot []*models.Bead
+	for i := 0; i < 3; i++ {
+		b, err := bm.CreateBead("hot task", "", models.BeadPriorityP2, "task", "hot")
+		if err != nil {
+			t.Fatal(err)
+		}
+		hot = append(hot, b)
+	}
+	cold, err := bm.CreateBead("cold task", "", models.BeadPriorityP2, "task", "cold")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range hot[:2] {
+		if err := bm.ClaimBead(b.ID, "agent-"+b.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	d := NewDispatcher(bm, nil, nil, nil, nil)
+	counts := make(map[string]int)
+	if count, capped := d.projectCapReached(hot[2], 2, counts); !capped || count != 2 {
+		t.Errorf("hot project = %d, %v, want capped at 2", count, capped)
+	}
+	if _, capped := d.projectCapReached(hot[0], 2, counts); capped {
+		t.Error("a bead already in progress should not be capped")
+	}
+	if _, capped := d.projectCapReached(cold, 2, counts); capped {
+		t.Error("cold project should not be capped")
+	}
+	if _, capped := d.projectCapReached(hot[2], 3, make(map[string]int)); capped {
+		t.Error("hot project is under a cap of 3")
+	}
+	if _, capped := d.projectCapReached(hot[2], 0, make(map[string]int)); capped {
+		t.Error("a zero cap means unlimited")
+	}
+}
@@ -0,0 +1,93 @@
+package dispatch
+
+import (
+	"context"
+	"strings"
+)
+
+// ReadinessChecker decides whether a project is ready to have work
+// dispatched, e.g. that its tests pass or its branch exists. Issues explain
+// a failure and may also be reported for a project that is ready.
+type ReadinessChecker interface {
+	Name() string
+	Check(ctx context.Context, projectID string) (bool, []string)
+}
+
+type readinessFunc struct {
+	name string
+	fn   func(context.Context, string) (bool, []string)
+}
+
+func (r readinessFunc) Name() string { return r.name }
+
+func (r readinessFunc) Check(ctx context.Context, projectID string) (bool, []string) {
+	return r.fn(ctx, projectID)
+}
+
+// NewReadinessCheck adapts a function to a ReadinessChecker.
+func NewReadinessCheck(name string, fn func(context.Context, string) (bool, []string)) ReadinessChecker {
+	return readinessFunc{name: name, fn: fn}
+}
+
+// SetReadinessCheck replaces the readiness checkers with a single check.
+// A nil check removes them all.
+func (d *Dispatcher) SetReadinessCheck(check func(context.Context, string) (bool, []string)) {
+	if check == nil {
+		d.SetReadinessCheckers()
+		return
+	}
+	d.SetReadinessCheckers(NewReadinessCheck("readiness", check))
+}
+
+// SetReadinessCheckers replaces the readiness checkers. They run in order
+// and a project is ready only if every one passes.
+func (d *Dispatcher) SetReadinessCheckers(checkers ...ReadinessChecker) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.readinessCheckers = append([]ReadinessChecker(nil), checkers...)
+}
+
+// AddReadinessChecker appends a checker to run after the existing ones.
+func (d *Dispatcher) AddReadinessChecker(checker ReadinessChecker) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.readinessCheckers = append(d.readinessCheckers, checker)
+}
+
+// checkReadiness runs every checker and reports whether all passed, with
+// their issues prefixed by the checker's name. A checker that fails without
+// saying why is reported as "<name>: not ready".
+func checkReadiness(ctx context.Context, checkers []ReadinessChecker, projectID string) (bool, []string) {
+	ready := true
+	var issues []string
+	for _, checker := range checkers {
+		ok, checkerIssues := checker.Check(ctx, projectID)
+		for _, issue := range checkerIssues {
+			issues = append(issues, checker.Name()+": "+issue)
+		}
+		if !ok {
+			ready = false
+			if len(checkerIssues) == 0 {
+				issues = append(issues, checker.Name()+": not ready")
+			}
+		}
+	}
+	return ready, issues
+}
+
+// warnReadiness logs a project's readiness issues in ReadinessWarn mode.
+// Each set of issues is logged once, when it first appears.
+func (d *Dispatcher) warnReadiness(projectID string, issues []string) {
+	key := strings.Join(issues, "\n")
+	d.mu.Lock()
+	if d.readinessWarned == nil {
+		d.readinessWarned = make(map[string]string)
+	}
+	last, seen := d.readinessWarned[projectID]
+	d.readinessWarned[projectID] = key
+	d.mu.Unlock()
+	if len(issues) == 0 || (seen && last == key) {
+		return
+	}
+	dlog.Warn("dispatch.readiness_warning", map[string]interface{}{"project_id": projectID, "issues": issues})
+}
@@ -0,0 +1,79 @@
+package dispatch
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jordanhubbard/loom/internal/observability"
+)
+
+func staticCheck(name string, ok bool, issues ...string) ReadinessChecker {
+	return NewReadinessCheck(name, func(context.Context, string) (bool, []string) { return ok, issues })
+}
+
+func TestCheckReadiness(t *testing.T) {
+	tests := []struct {
+		name       string
+		checkers   []ReadinessChecker
+		wantReady  bool
+		wantIssues []string
+	}{
+		{"none", nil, true, nil},
+		{"all pass", []ReadinessChecker{staticCheck("tests", true), staticCheck("branch", true)}, true, nil},
+		{"one fails", []ReadinessChecker{staticCheck("tests", true), staticCheck("branch", false, "main missing")}, false, []string{"branch: main missing"}},
+		{"failure without issues", []ReadinessChecker{staticCheck("conflicts", false)}, false, []string{"conflicts: not ready"}},
+		{"issues in order", []ReadinessChecker{
+			staticCheck("tests", false, "2 failing"),
+			staticCheck("lint", true, "slow"),
+			staticCheck("branch", false, "main missing", "detached"),
+		}, false, []string{"tests: 2 failing", "lint: slow", "branch: main missing", "branch: detached"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ready, issues := checkReadiness(context.Background(), tt.checkers, "p1")
+			if ready != tt.wantReady || !reflect.DeepEqual(issues, tt.wantIssues) {
+				t.Errorf("checkReadiness() = %v, %q, want %v, %q", ready, issues, tt.wantReady, tt.wantIssues)
+			}
+		})
+	}
+}
+
+func TestDispatcher_ReadinessCheckers(t *testing.T) {
+	d := NewDispatcher(nil, nil, nil, nil, nil)
+	d.SetReadinessCheckers(staticCheck("a", true), staticCheck("b", true))
+	d.AddReadinessChecker(staticCheck("c", true))
+
+	var names []string
+	for _, c := range d.readinessCheckers {
+		names = append(names, c.Name())
+	}
+	if strings.Join(names, ",") != "a,b,c" {
+		t.Errorf("checkers = %v, want a,b,c", names)
+	}
+
+	d.SetReadinessCheck(func(context.Context, string) (bool, []string) { return true, nil })
+	if len(d.readinessCheckers) != 1 {
+		t.Errorf("SetReadinessCheck should replace the checkers, have %d", len(d.readinessCheckers))
+	}
+}
+
+func TestDispatcher_WarnReadinessLogsChanges(t *testing.T) {
+	var buf bytes.Buffer
+	observability.SetOutput(&buf)
+	t.Cleanup(func() { observability.SetOutput(os.Stderr) })
+
+	d := NewDispatcher(nil, nil, nil, nil, nil)
+	d.warnReadiness("p1", []string{"tests: 2 failing"})
+	d.warnReadiness("p1", []string{"tests: 2 failing"})
+	d.warnReadiness("p1", []string{"tests: 3 failing"})
+	d.warnReadiness("p1", nil)
+	d.warnReadiness("p1", []string{"tests: 3 failing"})
+
+	if got := strings.Count(buf.String(), "dispatch.readiness_warning"); got != 3 {
+		t.Errorf("logged %d warnings, want 3:\n%s", got, buf.String())
+	}
+}
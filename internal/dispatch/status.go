@@ -0,0 +1,42 @@
+package dispatch
+
+import (
+	"log"
+	"time"
+
+	"github.com/jordanhubbard/loom/internal/temporal/eventbus"
+)
+
+// publishStatus announces a dispatcher status change on the event bus.
+func (d *Dispatcher) publishStatus(status SystemStatus) {
+	if d.eventBus == nil {
+		return
+	}
+	err := d.eventBus.Publish(&eventbus.Event{
+		Type:   eventbus.EventTypeDispatchStatusChange,
+		Source: "dispatcher",
+		Data: map[string]interface{}{
+			"state":      string(status.State),
+			"reason":     status.Reason,
+			"updated_at": status.UpdatedAt,
+		},
+	})
+	if err != nil {
+		log.Printf("[Dispatcher] Failed to publish status change: %v", err)
+	}
+}
+
+// StatusFromEvent returns the status carried by a dispatch status change
+// event.
+func StatusFromEvent(event *eventbus.Event) (SystemStatus, bool) {
+	if event == nil || event.Type != eventbus.EventTypeDispatchStatusChange {
+		return SystemStatus{}, false
+	}
+	state, _ := event.Data["state"].(string)
+	reason, _ := event.Data["reason"].(string)
+	updatedAt, ok := event.Data["updated_at"].(time.Time)
+	if !ok {
+		updatedAt = event.Timestamp
+	}
+	return SystemStatus{State: StatusState(state), Reason: reason, UpdatedAt: updatedAt}, true
+}
@@ -0,0 +1,54 @@
+package dispatch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jordanhubbard/loom/internal/temporal/eventbus"
+	"github.com/jordanhubbard/loom/pkg/config"
+)
+
+func TestDispatcher_SetStatusPublishesChanges(t *testing.T) {
+	eb := eventbus.NewEventBus(nil, &config.TemporalConfig{})
+	defer eb.Close()
+	sub := eb.Subscribe("test", func(e *eventbus.Event) bool {
+		return e.Type == eventbus.EventTypeDispatchStatusChange
+	})
+	d := NewDispatcher(nil, nil, nil, nil, eb)
+
+	next := func() (SystemStatus, bool) {
+		select {
+		case e := <-sub.Channel:
+			return StatusFromEvent(e)
+		case <-time.After(200 * time.Millisecond):
+			return SystemStatus{}, false
+		}
+	}
+
+	d.setStatus(StatusParked, "no dispatchable beads")
+	got, ok := next()
+	if !ok || got.State != StatusParked || got.Reason != "no dispatchable beads" || got.UpdatedAt.IsZero() {
+		t.Fatalf("first status event = %+v, %v", got, ok)
+	}
+
+	// Repeating the same status is not a change.
+	d.setStatus(StatusParked, "no dispatchable beads")
+	if got, ok := next(); ok {
+		t.Fatalf("unexpected event for unchanged status: %+v", got)
+	}
+
+	d.setStatus(StatusActive, "dispatching bd-1")
+	got, ok = next()
+	if !ok || got.State != StatusActive || got != d.GetSystemStatus() {
+		t.Fatalf("status event = %+v, want %+v", got, d.GetSystemStatus())
+	}
+}
+
+func TestStatusFromEvent_WrongType(t *testing.T) {
+	if _, ok := StatusFromEvent(&eventbus.Event{Type: eventbus.EventTypeSystemIdle}); ok {
+		t.Error("StatusFromEvent accepted a non-status event")
+	}
+	if _, ok := StatusFromEvent(nil); ok {
+		t.Error("StatusFromEvent accepted nil")
+	}
+}
@@ -0,0 +1,88 @@
+package dispatch
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jordanhubbard/loom/internal/workflow"
+	"github.com/jordanhubbard/loom/pkg/models"
+)
+
+// EscalateWorkflowExecution files the CEO decision bead for a workflow
+// execution the engine escalated outside a dispatch, such as on a node
+// timeout. It does nothing if the execution's bead already has one.
+func (d *Dispatcher) EscalateWorkflowExecution(exec *workflow.WorkflowExecution) error {
+	if exec == nil || exec.Status != workflow.ExecutionStatusEscalated {
+		return nil
+	}
+	if d.beads == nil || d.workflowEngine == nil {
+		return fmt.Errorf("dispatcher has no bead manager or workflow engine")
+	}
+	bead, err := d.beads.GetBead(exec.BeadID)
+	if err != nil {
+		return fmt.Errorf("failed to get bead %s: %w", exec.BeadID, err)
+	}
+	if bead.Context["escalation_bead_created"] == "true" {
+		return nil
+	}
+	d.createEscalationBead(exec, bead)
+	return nil
+}
+
+// createEscalationBead files a P0 decision bead asking the CEO to review an
+// escalated workflow execution and marks the original bead as escalated.
+func (d *Dispatcher) createEscalationBead(exec *workflow.WorkflowExecution, bead *models.Bead) {
+	log.Printf("[Workflow] Creating CEO escalation bead for workflow %s (bead %s)", exec.ID, bead.ID)
+
+	// Get escalation info from workflow engine
+	title, description, err := d.workflowEngine.GetEscalationInfo(exec)
+	if err != nil {
+		log.Printf("[Workflow] Failed to get escalation info for workflow %s: %v", exec.ID, err)
+		return
+	}
+
+	createdBead, err := d.beads.CreateBead(
+		title,
+		description,
+		models.BeadPriorityP0,
+		"decision",
+		bead.ProjectID,
+	)
+	if err != nil {
+		log.Printf("[Workflow] Failed to create CEO escalation bead: %v", err)
+		return
+	}
+	log.Printf("[Workflow] Created CEO escalation bead %s for workflow %s", createdBead.ID, exec.ID)
+
+	// Update the escalation bead with tags and context
+	escalationBeadUpdates := map[string]interface{}{
+		"tags": []string{"workflow-escalation", "ceo-review", "urgent"},
+		"context": map[string]string{
+			"original_bead_id":      bead.ID,
+			"workflow_execution_id": exec.ID,
+			"escalation_reason":     bead.Context["escalation_reason"],
+			"escalated_at":          time.Now().UTC().Format(time.RFC3339),
+		},
+	}
+	if err := d.beads.UpdateBead(createdBead.ID, escalationBeadUpdates); err != nil {
+		log.Printf("[Workflow] Failed to update escalation bead with tags and context: %v", err)
+	}
+
+	// Mark original bead as having escalation bead created
+	originalUpdates := map[string]interface{}{
+		"context": map[string]string{
+			"escalation_bead_created": "true",
+			"escalation_bead_id":      createdBead.ID,
+		},
+	}
+	if err := d.beads.UpdateBead(bead.ID, originalUpdates); err != nil {
+		log.Printf("[Workflow] Failed to update original bead with escalation info: %v", err)
+	}
+}
+
+// leftDispatchedNode reports whether an execution is no longer on the node a
+// task was dispatched for, e.g. because the node timed out while the task ran.
+func leftDispatchedNode(exec *workflow.WorkflowExecution, dispatchedNode string) bool {
+	return dispatchedNode != "" && exec.CurrentNodeKey != dispatchedNode
+}
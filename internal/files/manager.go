@@ -214,6 +214,9 @@ func (m *Manager) SearchText(ctx context.Context, projectID, relPath, query stri
 			return nil
 		}
 		defer file.Close()
+		if isBinaryFile(file) {
+			return nil
+		}
 
 		scanner := bufio.NewScanner(file)
 		scanner.Buffer(make([]byte, 0, 64*1024), defaultMaxFileBytes)
@@ -244,6 +247,21 @@ func (m *Manager) SearchText(ctx context.Context, projectID, relPath, query stri
 	return matches, nil
 }
 
+// binarySniffBytes is how much of a file is checked for NUL bytes, matching
+// git's binary detection heuristic
+const binarySniffBytes = 8000
+
+// isBinaryFile reports whether the file looks binary and rewinds it so it can
+// be read from the start
+func isBinaryFile(file *os.File) bool {
+	buf := make([]byte, binarySniffBytes)
+	n, _ := io.ReadFull(file, buf)
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return true
+	}
+	return bytes.IndexByte(buf[:n], 0) >= 0
+}
+
 // extractPatchFiles parses a unified diff patch and extracts the file paths
 func extractPatchFiles(patch string) ([]string, error) {
 	var files []string
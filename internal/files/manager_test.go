@@ -441,6 +441,24 @@ func TestSearchText_SkipsGitDir(t *testing.T) {
 	}
 }
 
+func TestSearchText_SkipsBinaryFiles(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "blob.bin"), []byte("findme\x00\x01\x02\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "ok.txt"), []byte("findme\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mgr := NewManager(staticResolver{dir: dir})
+	results, err := mgr.SearchText(context.Background(), "proj-1", ".", "findme", 100)
+	if err != nil {
+		t.Fatalf("SearchText: %v", err)
+	}
+	if len(results) != 1 || results[0].Path != "ok.txt" || results[0].Line != 1 {
+		t.Errorf("Expected a single match in ok.txt, got %+v", results)
+	}
+}
+
 // --- WriteFile ---
 
 func TestWriteFile(t *testing.T) {
@@ -0,0 +1,164 @@
+package gitops
+
+import (
+	"strconv"
+	"strings"
+)
+
+// File statuses in a parsed diff
+const (
+	DiffFileAdded    = "added"
+	DiffFileDeleted  = "deleted"
+	DiffFileModified = "modified"
+	DiffFileRenamed  = "renamed"
+)
+
+// ParsedDiff is git diff output split into files and hunks.
+type ParsedDiff struct {
+	Files []FileDiff `json:"files"`
+	Stats DiffStats  `json:"stats"`
+}
+
+// DiffStats totals a diff, like git diff --shortstat.
+type DiffStats struct {
+	FilesChanged int `json:"files_changed"`
+	Insertions   int `json:"insertions"`
+	Deletions    int `json:"deletions"`
+}
+
+// FileDiff is the part of a diff that changes one file.
+type FileDiff struct {
+	Path      string     `json:"path"`
+	OldPath   string     `json:"old_path,omitempty"` // Set when the file was renamed
+	Status    string     `json:"status"`
+	Binary    bool       `json:"binary,omitempty"`
+	Additions int        `json:"additions"`
+	Deletions int        `json:"deletions"`
+	Hunks     []DiffHunk `json:"hunks"`
+}
+
+// DiffHunk is one @@ section of a file diff. Lines keep their leading
+// " ", "+", "-" or "\" marker.
+type DiffHunk struct {
+	OldStart int      `json:"old_start"`
+	OldLines int      `json:"old_lines"`
+	NewStart int      `json:"new_start"`
+	NewLines int      `json:"new_lines"`
+	Section  string   `json:"section,omitempty"` // Text after the ranges, usually the enclosing function
+	Lines    []string `json:"lines"`
+}
+
+// ParseDiff parses unified diff output from git diff.
+func ParseDiff(diff string) *ParsedDiff {
+	parsed := &ParsedDiff{Files: []FileDiff{}}
+	var file *FileDiff
+	var hunk *DiffHunk
+	oldLeft, newLeft := 0, 0 // Lines of the current hunk still to come
+
+	flush := func() {
+		if file == nil {
+			return
+		}
+		parsed.Files = append(parsed.Files, *file)
+		parsed.Stats.Insertions += file.Additions
+		parsed.Stats.Deletions += file.Deletions
+		file, hunk = nil, nil
+	}
+
+	for _, line := range strings.Split(diff, "\n") {
+		// Inside a hunk the range counts say which lines belong to it, so a
+		// removed "-- x" line is not taken for a "--- " header
+		if hunk != nil && (oldLeft > 0 || newLeft > 0 || strings.HasPrefix(line, `\`)) {
+			hunk.Lines = append(hunk.Lines, line)
+			switch {
+			case strings.HasPrefix(line, "+"):
+				file.Additions++
+				newLeft--
+			case strings.HasPrefix(line, "-"):
+				file.Deletions++
+				oldLeft--
+			case strings.HasPrefix(line, `\`):
+				// No newline at end of file
+			default:
+				oldLeft--
+				newLeft--
+			}
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			flush()
+			file = &FileDiff{Status: DiffFileModified, Hunks: []DiffHunk{}}
+			if _, b, ok := strings.Cut(line[len("diff --git "):], " b/"); ok {
+				file.Path = b
+			}
+		case file == nil:
+			continue
+		case strings.HasPrefix(line, "new file mode"):
+			file.Status = DiffFileAdded
+		case strings.HasPrefix(line, "deleted file mode"):
+			file.Status = DiffFileDeleted
+		case strings.HasPrefix(line, "rename from "):
+			file.Status = DiffFileRenamed
+			file.OldPath = strings.TrimPrefix(line, "rename from ")
+		case strings.HasPrefix(line, "rename to "):
+			file.Path = strings.TrimPrefix(line, "rename to ")
+		case strings.HasPrefix(line, "Binary files "):
+			file.Binary = true
+		case strings.HasPrefix(line, "+++ "):
+			if name := strings.TrimPrefix(line, "+++ "); name != "/dev/null" {
+				file.Path = strings.TrimPrefix(name, "b/")
+			}
+		case strings.HasPrefix(line, "@@ "):
+			h, ok := parseHunkHeader(line)
+			if !ok {
+				continue
+			}
+			file.Hunks = append(file.Hunks, h)
+			hunk = &file.Hunks[len(file.Hunks)-1]
+			oldLeft, newLeft = h.OldLines, h.NewLines
+		}
+	}
+	flush()
+
+	parsed.Stats.FilesChanged = len(parsed.Files)
+	return parsed
+}
+
+// parseHunkHeader parses "@@ -oldStart[,oldLines] +newStart[,newLines] @@ section".
+func parseHunkHeader(line string) (DiffHunk, bool) {
+	rest := strings.TrimPrefix(line, "@@ ")
+	ranges, section, ok := strings.Cut(rest, " @@")
+	if !ok {
+		return DiffHunk{}, false
+	}
+	oldRange, newRange, ok := strings.Cut(ranges, " ")
+	if !ok || !strings.HasPrefix(oldRange, "-") || !strings.HasPrefix(newRange, "+") {
+		return DiffHunk{}, false
+	}
+	h := DiffHunk{Section: strings.TrimSpace(section), Lines: []string{}}
+	if h.OldStart, h.OldLines, ok = parseHunkRange(oldRange[1:]); !ok {
+		return DiffHunk{}, false
+	}
+	if h.NewStart, h.NewLines, ok = parseHunkRange(newRange[1:]); !ok {
+		return DiffHunk{}, false
+	}
+	return h, true
+}
+
+// parseHunkRange parses "start[,lines]"; lines defaults to 1.
+func parseHunkRange(r string) (int, int, bool) {
+	startStr, linesStr, hasLines := strings.Cut(r, ",")
+	start, err := strconv.Atoi(startStr)
+	if err != nil {
+		return 0, 0, false
+	}
+	lines := 1
+	if hasLines {
+		if lines, err = strconv.Atoi(linesStr); err != nil {
+			return 0, 0, false
+		}
+	}
+	return start, lines, true
+}
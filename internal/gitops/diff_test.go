@@ -0,0 +1,117 @@
+package gitops
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseDiff_MultiFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	mgr, err := NewManager(tmpDir, filepath.Join(tmpDir, "keys"), nil, nil)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	repoDir := filepath.Join(tmpDir, "repo")
+	if err := os.MkdirAll(repoDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	mgr.SetProjectWorkDir("p1", repoDir)
+
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repoDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	gitIn(t, repoDir, "init")
+	write("main.go", "package main\n\nfunc main() {\n\tprintln(\"hi\")\n}\n")
+	write("schema.sql", "-- users\nCREATE TABLE users (id int);\n")
+	write("old.txt", "one\ntwo\n")
+	gitIn(t, repoDir, "add", ".")
+	gitIn(t, repoDir, "commit", "-m", "initial")
+
+	write("main.go", "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"hi\")\n}\n")
+	// Removing a "-- " line yields a "--- " line inside the hunk
+	write("schema.sql", "CREATE TABLE users (id int);\n")
+	if err := os.Remove(filepath.Join(repoDir, "old.txt")); err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := mgr.Diff(context.Background(), "p1")
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	parsed := ParseDiff(diff)
+
+	want := DiffStats{FilesChanged: 3, Insertions: 3, Deletions: 4}
+	if parsed.Stats != want {
+		t.Errorf("stats = %+v, want %+v", parsed.Stats, want)
+	}
+	if len(parsed.Files) != 3 {
+		t.Fatalf("expected 3 files, got %d: %+v", len(parsed.Files), parsed.Files)
+	}
+
+	files := make(map[string]FileDiff)
+	for _, f := range parsed.Files {
+		files[f.Path] = f
+	}
+
+	main := files["main.go"]
+	if main.Status != DiffFileModified || main.Additions != 3 || main.Deletions != 1 || len(main.Hunks) != 1 {
+		t.Errorf("main.go = %+v", main)
+	} else if h := main.Hunks[0]; h.OldStart != 1 || h.OldLines != 5 || h.NewStart != 1 || h.NewLines != 7 || len(h.Lines) != 8 {
+		t.Errorf("main.go hunk = %+v", h)
+	}
+
+	schema := files["schema.sql"]
+	if schema.Status != DiffFileModified || schema.Additions != 0 || schema.Deletions != 1 {
+		t.Errorf("schema.sql = %+v", schema)
+	}
+
+	old := files["old.txt"]
+	if old.Status != DiffFileDeleted || old.Deletions != 2 || len(old.Hunks) != 1 || old.Hunks[0].NewLines != 0 {
+		t.Errorf("old.txt = %+v", old)
+	}
+}
+
+func TestParseDiff_Headers(t *testing.T) {
+	diff := `diff --git a/a.txt b/b.txt
+similarity index 90%
+rename from a.txt
+rename to b.txt
+--- a/a.txt
++++ b/b.txt
+@@ -3 +3 @@ func f() {
+-x
+\ No newline at end of file
++y
+\ No newline at end of file
+diff --git a/logo.png b/logo.png
+new file mode 100644
+Binary files /dev/null and b/logo.png differ`
+
+	parsed := ParseDiff(diff)
+	if len(parsed.Files) != 2 {
+		t.Fatalf("expected 2 files, got %+v", parsed.Files)
+	}
+
+	renamed := parsed.Files[0]
+	if renamed.Status != DiffFileRenamed || renamed.Path != "b.txt" || renamed.OldPath != "a.txt" {
+		t.Errorf("renamed file = %+v", renamed)
+	}
+	h := renamed.Hunks[0]
+	if h.OldStart != 3 || h.OldLines != 1 || h.NewStart != 3 || h.NewLines != 1 || h.Section != "func f() {" || len(h.Lines) != 4 {
+		t.Errorf("hunk = %+v", h)
+	}
+
+	binary := parsed.Files[1]
+	if binary.Status != DiffFileAdded || !binary.Binary || binary.Path != "logo.png" || len(binary.Hunks) != 0 {
+		t.Errorf("binary file = %+v", binary)
+	}
+
+	if empty := ParseDiff(""); len(empty.Files) != 0 || empty.Stats != (DiffStats{}) {
+		t.Errorf("empty diff = %+v", empty)
+	}
+}
@@ -0,0 +1,64 @@
+package gitops
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// maxDirtyFilesListed caps the files named in a clean tree issue.
+const maxDirtyFilesListed = 10
+
+// CleanTreeChecker is a dispatch readiness check that fails while a
+// project's work dir has uncommitted changes, e.g. left behind by a task
+// that failed part way. Projects that are not cloned are left to other
+// checks and pass.
+type CleanTreeChecker struct {
+	manager *Manager
+}
+
+// NewCleanTreeChecker returns a checker for the work dirs of m's projects.
+func NewCleanTreeChecker(m *Manager) *CleanTreeChecker {
+	return &CleanTreeChecker{manager: m}
+}
+
+func (c *CleanTreeChecker) Name() string { return "clean-tree" }
+
+// Check runs git status --porcelain in the project's work dir and reports
+// the dirty files.
+func (c *CleanTreeChecker) Check(ctx context.Context, projectID string) (bool, []string) {
+	workDir := c.manager.GetProjectWorkDir(projectID)
+	if _, err := os.Stat(filepath.Join(workDir, ".git")); os.IsNotExist(err) {
+		return true, nil
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "status", "--porcelain")
+	cmd.Dir = workDir
+	output, err := cmd.Output()
+	if err != nil {
+		return false, []string{fmt.Sprintf("git status failed in %s: %v", workDir, err)}
+	}
+
+	var files []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if len(line) > 3 {
+			files = append(files, line[3:])
+		}
+	}
+	if len(files) == 0 {
+		return true, nil
+	}
+
+	listed := files
+	if len(listed) > maxDirtyFilesListed {
+		listed = listed[:maxDirtyFilesListed]
+	}
+	issue := fmt.Sprintf("uncommitted changes in %s: %s", workDir, strings.Join(listed, ", "))
+	if more := len(files) - len(listed); more > 0 {
+		issue += fmt.Sprintf(" and %d more", more)
+	}
+	return false, []string{issue}
+}
@@ -0,0 +1,63 @@
+package gitops
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func gitIn(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-c", "user.name=Test", "-c", "user.email=test@example.com"}, args...)...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+func TestCleanTreeChecker(t *testing.T) {
+	tmpDir := t.TempDir()
+	mgr, err := NewManager(tmpDir, filepath.Join(tmpDir, "keys"), nil, nil)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	checker := NewCleanTreeChecker(mgr)
+	ctx := context.Background()
+
+	if ok, issues := checker.Check(ctx, "not-cloned"); !ok {
+		t.Errorf("uncloned project should pass, got %v", issues)
+	}
+
+	repoDir := filepath.Join(tmpDir, "repo")
+	if err := os.MkdirAll(repoDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	mgr.SetProjectWorkDir("p1", repoDir)
+	gitIn(t, repoDir, "init")
+	if err := os.WriteFile(filepath.Join(repoDir, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitIn(t, repoDir, "add", ".")
+	gitIn(t, repoDir, "commit", "-m", "initial")
+
+	if ok, issues := checker.Check(ctx, "p1"); !ok {
+		t.Fatalf("clean tree should pass, got %v", issues)
+	}
+
+	if err := os.WriteFile(filepath.Join(repoDir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repoDir, "leftover.txt"), []byte("tmp"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ok, issues := checker.Check(ctx, "p1")
+	if ok {
+		t.Fatal("dirty tree should fail")
+	}
+	if len(issues) != 1 || !strings.Contains(issues[0], "main.go") || !strings.Contains(issues[0], "leftover.txt") {
+		t.Errorf("issues = %v, want the dirty files listed", issues)
+	}
+}
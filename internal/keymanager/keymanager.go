@@ -105,19 +105,27 @@ func (km *KeyManager) Unlock(password string) error {
 
 // initializePasswordSalt creates a new password salt and verification hash
 func (km *KeyManager) initializePasswordSalt() error {
+	salt, verify, err := newPasswordVerifier(km.password)
+	if err != nil {
+		return err
+	}
+	km.store.PasswordSalt = salt
+	km.store.PasswordVerify = verify
+	return nil
+}
+
+// newPasswordVerifier returns a random base64 salt and the matching
+// verification hash for password
+func newPasswordVerifier(password []byte) (string, string, error) {
 	// Generate random salt
 	salt := make([]byte, saltSize)
 	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
-		return err
+		return "", "", err
 	}
 
-	km.store.PasswordSalt = base64.StdEncoding.EncodeToString(salt)
-
 	// Create verification hash: PBKDF2(password, salt, iterations, 32 bytes)
-	verifyHash := pbkdf2.Key(km.password, salt, iterations, keySize, sha256.New)
-	km.store.PasswordVerify = base64.StdEncoding.EncodeToString(verifyHash)
-
-	return nil
+	verifyHash := pbkdf2.Key(password, salt, iterations, keySize, sha256.New)
+	return base64.StdEncoding.EncodeToString(salt), base64.StdEncoding.EncodeToString(verifyHash), nil
 }
 
 // verifyPassword verifies that the provided password is correct
@@ -255,59 +263,72 @@ func (km *KeyManager) ListKeys() ([]*KeyEntry, error) {
 	return keys, nil
 }
 
-// ChangePassword changes the master password and re-encrypts all stored keys
+// ChangePassword changes the master password and re-encrypts all stored keys.
+// It is equivalent to Rotate.
 func (km *KeyManager) ChangePassword(oldPassword, newPassword string) error {
+	return km.Rotate(oldPassword, newPassword)
+}
+
+// Rotate re-encrypts every stored key under newPassword. The rotated store is
+// built in memory and written to disk in a single rename, so if any step
+// fails both the file and the unlocked store keep the old password.
+func (km *KeyManager) Rotate(oldPassword, newPassword string) error {
 	km.mu.Lock()
 	defer km.mu.Unlock()
 
 	if !km.unlocked {
 		return errors.New("key store is locked")
 	}
+	if newPassword == "" {
+		return errors.New("new password is required")
+	}
 
 	// Verify old password
 	if err := km.verifyPassword(oldPassword); err != nil {
 		return fmt.Errorf("old password is incorrect: %w", err)
 	}
 
-	// Store all decrypted keys temporarily using current password
-	decryptedKeys := make(map[string]string)
+	newKey := []byte(newPassword)
+	salt, verify, err := newPasswordVerifier(newKey)
+	if err != nil {
+		return fmt.Errorf("failed to initialize new password: %w", err)
+	}
+	rotated := &KeyStore{
+		Version:        km.store.Version,
+		PasswordSalt:   salt,
+		PasswordVerify: verify,
+		Keys:           make(map[string]*KeyEntry, len(km.store.Keys)),
+	}
+
+	now := time.Now()
 	for id, entry := range km.store.Keys {
-		decryptedData, err := km.decrypt([]byte(entry.EncryptedData))
+		encryptedData, err := base64.StdEncoding.DecodeString(entry.EncryptedData)
 		if err != nil {
-			return fmt.Errorf("failed to decrypt key %s: %w", id, err)
+			return fmt.Errorf("failed to decode key %s: %w", id, err)
 		}
-		decrypted, err := base64.StdEncoding.DecodeString(string(decryptedData))
+		plaintext, err := decryptWith(km.password, encryptedData)
 		if err != nil {
-			return fmt.Errorf("failed to decode key %s: %w", id, err)
+			return fmt.Errorf("failed to decrypt key %s: %w", id, err)
 		}
-		decryptedKeys[id] = string(decrypted)
-	}
-
-	// Change the password
-	km.password = []byte(newPassword)
-
-	// Generate new salt and verification hash
-	if err := km.initializePasswordSalt(); err != nil {
-		return fmt.Errorf("failed to initialize new password: %w", err)
-	}
-
-	// Re-encrypt all keys with new password
-	for id, plaintext := range decryptedKeys {
-		encryptedData, err := km.encrypt([]byte(plaintext))
+		reencrypted, err := encryptWith(newKey, plaintext)
+		zero(plaintext)
 		if err != nil {
 			return fmt.Errorf("failed to re-encrypt key %s: %w", id, err)
 		}
 
-		entry := km.store.Keys[id]
-		entry.EncryptedData = base64.StdEncoding.EncodeToString(encryptedData)
-		entry.UpdatedAt = time.Now()
+		rotatedEntry := *entry
+		rotatedEntry.EncryptedData = base64.StdEncoding.EncodeToString(reencrypted)
+		rotatedEntry.UpdatedAt = now
+		rotated.Keys[id] = &rotatedEntry
 	}
 
-	// Persist to disk
-	if err := km.saveStore(); err != nil {
+	if err := km.writeStore(rotated); err != nil {
 		return fmt.Errorf("failed to save key store: %w", err)
 	}
 
+	zero(km.password)
+	km.password = newKey
+	km.store = rotated
 	return nil
 }
 
@@ -317,18 +338,24 @@ func (km *KeyManager) Lock() {
 	defer km.mu.Unlock()
 
 	// Clear password from memory
-	if km.password != nil {
-		for i := range km.password {
-			km.password[i] = 0
-		}
-		km.password = nil
-	}
+	zero(km.password)
+	km.password = nil
 
 	km.unlocked = false
 }
 
 // encrypt encrypts data using AES-GCM
 func (km *KeyManager) encrypt(plaintext []byte) ([]byte, error) {
+	return encryptWith(km.password, plaintext)
+}
+
+// decrypt decrypts data using AES-GCM
+func (km *KeyManager) decrypt(data []byte) ([]byte, error) {
+	return decryptWith(km.password, data)
+}
+
+// encryptWith encrypts data with a key derived from password
+func encryptWith(password, plaintext []byte) ([]byte, error) {
 	// Generate salt
 	salt := make([]byte, saltSize)
 	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
@@ -336,7 +363,7 @@ func (km *KeyManager) encrypt(plaintext []byte) ([]byte, error) {
 	}
 
 	// Derive key from password
-	key := pbkdf2.Key(km.password, salt, iterations, keySize, sha256.New)
+	key := pbkdf2.Key(password, salt, iterations, keySize, sha256.New)
 
 	// Create cipher
 	block, err := aes.NewCipher(key)
@@ -367,8 +394,8 @@ func (km *KeyManager) encrypt(plaintext []byte) ([]byte, error) {
 	return result, nil
 }
 
-// decrypt decrypts data using AES-GCM
-func (km *KeyManager) decrypt(data []byte) ([]byte, error) {
+// decryptWith decrypts data with a key derived from password
+func decryptWith(password, data []byte) ([]byte, error) {
 	if len(data) < saltSize {
 		return nil, errors.New("invalid encrypted data")
 	}
@@ -378,7 +405,7 @@ func (km *KeyManager) decrypt(data []byte) ([]byte, error) {
 	data = data[saltSize:]
 
 	// Derive key from password
-	key := pbkdf2.Key(km.password, salt, iterations, keySize, sha256.New)
+	key := pbkdf2.Key(password, salt, iterations, keySize, sha256.New)
 
 	// Create cipher
 	block, err := aes.NewCipher(key)
@@ -429,20 +456,52 @@ func (km *KeyManager) loadStore() error {
 
 // saveStore saves the key store to disk
 func (km *KeyManager) saveStore() error {
-	data, err := json.MarshalIndent(km.store, "", "  ")
+	return km.writeStore(km.store)
+}
+
+// writeStore writes store to a temporary file and renames it over the store
+// path, so a failed write never leaves a partial store behind
+func (km *KeyManager) writeStore(store *KeyStore) error {
+	data, err := json.MarshalIndent(store, "", "  ")
 	if err != nil {
 		return err
 	}
 
 	// Create directory if it doesn't exist
-	if err := os.MkdirAll(filepath.Dir(km.storePath), 0700); err != nil {
+	dir := filepath.Dir(km.storePath)
+	if err := os.MkdirAll(dir, 0700); err != nil {
 		return err
 	}
 
 	// Write with restricted permissions
-	if err := os.WriteFile(km.storePath, data, 0600); err != nil {
+	tmp, err := os.CreateTemp(dir, filepath.Base(km.storePath)+".tmp-*")
+	if err != nil {
 		return err
 	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // No-op once renamed
 
-	return nil
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, km.storePath)
+}
+
+// zero overwrites b so secrets do not linger in memory
+func zero(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
 }
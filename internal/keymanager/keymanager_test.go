@@ -1,7 +1,9 @@
 package keymanager
 
 import (
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -200,6 +202,115 @@ func TestKeyManager_ChangePassword(t *testing.T) {
 	}
 }
 
+func TestKeyManager_Rotate(t *testing.T) {
+	tmpDir := t.TempDir()
+	storePath := filepath.Join(tmpDir, "test_keystore.json")
+
+	oldPassword := "old-password-123"
+	newPassword := "new-password-456"
+	secrets := map[string]string{
+		"key1": "secret-value-1",
+		"key2": "secret-value-2",
+		"key3": "secret-value-3",
+	}
+
+	km := NewKeyManager(storePath)
+	if err := km.Rotate(oldPassword, newPassword); err == nil {
+		t.Error("Rotate on locked store should fail")
+	}
+
+	if err := km.Unlock(oldPassword); err != nil {
+		t.Fatalf("Failed to unlock: %v", err)
+	}
+	for id, value := range secrets {
+		if err := km.StoreKey(id, id, "desc", value); err != nil {
+			t.Fatalf("Failed to store key: %v", err)
+		}
+	}
+
+	if err := km.Rotate("wrong-old", newPassword); err == nil {
+		t.Error("Rotate with wrong old password should fail")
+	}
+	if err := km.Rotate(oldPassword, newPassword); err != nil {
+		t.Fatalf("Rotate() error = %v", err)
+	}
+
+	// Keys stay readable without re-unlocking
+	if got, err := km.GetKey("key1"); err != nil || got != secrets["key1"] {
+		t.Errorf("GetKey after rotate = %q, %v", got, err)
+	}
+
+	data, err := os.ReadFile(storePath)
+	if err != nil {
+		t.Fatalf("Failed to read store: %v", err)
+	}
+	for _, value := range secrets {
+		if strings.Contains(string(data), value) {
+			t.Errorf("store file contains plaintext %q", value)
+		}
+	}
+
+	// Lock and re-unlock with the new password
+	km.Lock()
+	if err := km.Unlock(oldPassword); err == nil {
+		t.Error("Old password should not work after rotate")
+	}
+	km2 := NewKeyManager(storePath)
+	if err := km2.Unlock(newPassword); err != nil {
+		t.Fatalf("Failed to unlock with new password: %v", err)
+	}
+	for id, want := range secrets {
+		got, err := km2.GetKey(id)
+		if err != nil {
+			t.Fatalf("GetKey(%s) error = %v", id, err)
+		}
+		if got != want {
+			t.Errorf("GetKey(%s) = %q, want %q", id, got, want)
+		}
+	}
+}
+
+func TestKeyManager_RotateFailureLeavesStoreUnchanged(t *testing.T) {
+	tmpDir := t.TempDir()
+	storePath := filepath.Join(tmpDir, "test_keystore.json")
+
+	km := NewKeyManager(storePath)
+	if err := km.Unlock("old-password"); err != nil {
+		t.Fatalf("Failed to unlock: %v", err)
+	}
+	if err := km.StoreKey("good", "Good", "desc", "good-value"); err != nil {
+		t.Fatalf("Failed to store key: %v", err)
+	}
+	if err := km.StoreKey("bad", "Bad", "desc", "bad-value"); err != nil {
+		t.Fatalf("Failed to store key: %v", err)
+	}
+	before, err := os.ReadFile(storePath)
+	if err != nil {
+		t.Fatalf("Failed to read store: %v", err)
+	}
+
+	// An undecryptable entry makes rotation fail partway through
+	km.store.Keys["bad"].EncryptedData = "not-base64!"
+	if err := km.Rotate("old-password", "new-password"); err == nil {
+		t.Fatal("Rotate should fail with a corrupt entry")
+	}
+
+	after, err := os.ReadFile(storePath)
+	if err != nil {
+		t.Fatalf("Failed to read store: %v", err)
+	}
+	if string(after) != string(before) {
+		t.Error("store file changed after failed rotate")
+	}
+	if got, err := km.GetKey("good"); err != nil || got != "good-value" {
+		t.Errorf("GetKey after failed rotate = %q, %v", got, err)
+	}
+	entries, _ := os.ReadDir(tmpDir)
+	if len(entries) != 1 {
+		t.Errorf("expected only the store file in %s, found %d entries", tmpDir, len(entries))
+	}
+}
+
 func TestKeyManager_StoreAndDelete(t *testing.T) {
 	tmpDir := t.TempDir()
 	storePath := filepath.Join(tmpDir, "test_keystore.json")
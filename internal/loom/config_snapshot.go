@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log"
 
 	internalmodels "github.com/jordanhubbard/loom/internal/models"
 	"github.com/jordanhubbard/loom/internal/provider"
@@ -235,14 +236,43 @@ func (a *Loom) ReloadFromDatabase(ctx context.Context) error {
 		if p == nil {
 			continue
 		}
-		_ = a.providerRegistry.Register(&provider.ProviderConfig{
-			ID:       p.ID,
-			Name:     p.Name,
-			Type:     p.Type,
-			Endpoint: normalizeProviderEndpoint(p.Endpoint),
-			APIKey:   "",
-			Model:    p.Model,
-		})
+		selected := p.SelectedModel
+		if selected == "" {
+			selected = p.Model
+		}
+		if selected == "" {
+			selected = p.ConfiguredModel
+		}
+		var apiKey string
+		if p.KeyID != "" && a.keyManager != nil && a.keyManager.IsUnlocked() {
+			apiKey, _ = a.keyManager.GetKey(p.KeyID)
+		}
+		// Upsert, as in New, lets a stored provider that has not picked a
+		// model yet load; other invalid settings are logged and skipped.
+		if err := a.providerRegistry.Upsert(&provider.ProviderConfig{
+			ID:                     p.ID,
+			Name:                   p.Name,
+			Type:                   p.Type,
+			Endpoint:               normalizeProviderEndpoint(p.Endpoint),
+			DeploymentName:         p.DeploymentName,
+			APIVersion:             p.APIVersion,
+			Region:                 p.Region,
+			APIKey:                 apiKey,
+			Model:                  selected,
+			ConfiguredModel:        p.ConfiguredModel,
+			SelectedModel:          selected,
+			SelectedGPU:            p.SelectedGPU,
+			TimeoutSeconds:         p.TimeoutSeconds,
+			MaxRetries:             p.MaxRetries,
+			RequestsPerMinute:      p.RequestsPerMinute,
+			MockResponse:           p.MockResponse,
+			MockLatencyMs:          p.MockLatencyMs,
+			Status:                 p.Status,
+			LastHeartbeatAt:        p.LastHeartbeatAt,
+			LastHeartbeatLatencyMs: p.LastHeartbeatLatencyMs,
+		}); err != nil {
+			log.Printf("[Loom] Failed to load provider %s from database: %v", p.ID, err)
+		}
 	}
 
 	agents, err := a.database.ListAgents()
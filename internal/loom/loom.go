@@ -55,6 +55,9 @@ import (
 
 const readinessCacheTTL = 2 * time.Minute
 
+// agentShutdownTimeout bounds how long Shutdown waits for running tasks.
+const agentShutdownTimeout = 30 * time.Second
+
 type projectReadinessState struct {
 	ready     bool
 	issues    []string
@@ -104,12 +107,20 @@ type Loom struct {
 
 // New creates a new Loom instance
 func New(cfg *config.Config) (*Loom, error) {
+	logLevel, err := observability.ParseLevel(cfg.Logging.Level)
+	if err != nil {
+		return nil, fmt.Errorf("invalid logging config: %w", err)
+	}
+	observability.SetLevel(logLevel)
+
 	personaPath := cfg.Agents.DefaultPersonaPath
 	if personaPath == "" {
 		personaPath = "./personas"
 	}
 
 	providerRegistry := provider.NewRegistry()
+	providerRegistry.SetCircuitBreaker(cfg.Dispatch.ProviderFailureThreshold, cfg.Dispatch.ProviderCooldown)
+	providerRegistry.SetModelCacheTTL(cfg.Dispatch.ModelCacheTTL)
 
 	// Initialize Temporal manager if configured
 	var temporalMgr *temporal.Manager
@@ -273,11 +284,21 @@ func New(cfg *config.Config) (*Loom, error) {
 	// Initialize pattern manager and analytics logger if database is available
 	var patternMgr *patterns.Manager
 	if db != nil {
-		analyticsStorage, err := analytics.NewDatabaseStorage(db.DB())
-		if err == nil && analyticsStorage != nil {
+		var analyticsStorage analytics.Storage
+		var err error
+		if db.Type() == "postgres" {
+			analyticsStorage, err = analytics.NewPostgresStorage(db.DB())
+		} else {
+			analyticsStorage, err = analytics.NewDatabaseStorage(db.DB())
+		}
+		if err != nil {
+			log.Printf("[Loom] Analytics storage unavailable: %v", err)
+		} else {
 			patternMgr = patterns.NewManager(analyticsStorage, nil)
 			// Wire analytics logger to WorkerManager so LLM completions are logged
-			agentMgr.SetAnalyticsLogger(analytics.NewLogger(analyticsStorage, analytics.DefaultPrivacyConfig()))
+			analyticsLogger := analytics.NewLogger(analyticsStorage, analytics.DefaultPrivacyConfig())
+			analyticsLogger.SetMetrics(metrics.NewMetrics())
+			agentMgr.SetAnalyticsLogger(analyticsLogger)
 		}
 	}
 
@@ -319,6 +340,16 @@ func New(cfg *config.Config) (*Loom, error) {
 
 	beadsMgr := beads.NewManager(cfg.Beads.BDPath)
 	beadsMgr.SetBackend(cfg.Beads.Backend)
+	for _, p := range cfg.Projects {
+		if p.StatusWebhookURL == "" {
+			continue
+		}
+		secret := p.StatusWebhookSecret
+		if secret == "" {
+			secret = cfg.Security.WebhookSecret
+		}
+		beadsMgr.SetStatusWebhook(p.ID, p.StatusWebhookURL, secret)
+	}
 
 	arb := &Loom{
 		config:                cfg,
@@ -384,10 +415,38 @@ func New(cfg *config.Config) (*Loom, error) {
 	arb.dispatcher = dispatch.NewDispatcher(arb.beadsManager, arb.projectManager, arb.agentManager, arb.providerRegistry, eb)
 	arb.readinessCache = make(map[string]projectReadinessState)
 	arb.readinessFailures = make(map[string]time.Time)
-	arb.dispatcher.SetReadinessCheck(arb.CheckProjectReadiness)
+	arb.dispatcher.SetReadinessCheckers(
+		dispatch.NewReadinessCheck("project", arb.CheckProjectReadiness),
+	)
+	if cfg.Readiness.CleanTree {
+		arb.dispatcher.AddReadinessChecker(gitops.NewCleanTreeChecker(gitopsMgr))
+	}
 	arb.dispatcher.SetReadinessMode(dispatch.ReadinessMode(cfg.Readiness.Mode))
 	arb.dispatcher.SetMaxDispatchHops(cfg.Dispatch.MaxHops)
+	arb.dispatcher.SetLoopWindow(cfg.Dispatch.LoopWindow)
+	arb.dispatcher.SetMaxDispatchCount(cfg.Dispatch.MaxDispatchCount)
+	arb.dispatcher.SetSameAgentFailureLimit(cfg.Dispatch.SameAgentFailureLimit)
+	arb.dispatcher.SetPriorityAging(cfg.Dispatch.PriorityAgingInterval)
+	arb.dispatcher.SetExecRetry(cfg.Dispatch.ExecRetries, cfg.Dispatch.ExecRetryBackoff)
+	arb.dispatcher.SetMaxConcurrentPerProject(cfg.Dispatch.MaxConcurrentPerProject)
+	arb.dispatcher.SetMetrics(arb.metrics)
+	agentMgr.GetWorkerPool().SetStatsObserver(arb.metrics.RecordWorkerPool)
+	for _, p := range cfg.Projects {
+		if p.MaxTaskTokens > 0 || p.MaxTaskCostUSD > 0 {
+			arb.dispatcher.SetTaskBudget(p.ID, p.MaxTaskTokens, p.MaxTaskCostUSD)
+		}
+	}
+	for _, name := range cfg.Dispatch.PersonaStrategies {
+		if s, ok := dispatch.PersonaStrategyByName(name); ok {
+			arb.dispatcher.AddPersonaStrategy(s)
+		} else {
+			log.Printf("[Loom] Unknown persona strategy %q in dispatch config, ignoring", name)
+		}
+	}
 	arb.dispatcher.SetEscalator(arb)
+	arb.dispatcher.SetDeadLetterer(arb)
+	agentMgr.SetReadyBeadCounter(arb.dispatcher)
+	agentMgr.SetAutoScale(cfg.Agents.AutoScaleMin, cfg.Agents.AutoScaleMax, cfg.Agents.AutoScaleInterval)
 	// Enable conversation context support for multi-turn conversations
 	if db != nil {
 		arb.dispatcher.SetDatabase(db)
@@ -808,13 +867,21 @@ func (a *Loom) Initialize(ctx context.Context) error {
 					continue
 				}
 				seed := &internalmodels.Provider{
-					ID:          providerID,
-					Name:        cfgProvider.Name,
-					Type:        cfgProvider.Type,
-					Endpoint:    cfgProvider.Endpoint,
-					Model:       cfgProvider.Model,
-					RequiresKey: cfgProvider.APIKey != "",
-					Status:      "pending",
+					ID:                providerID,
+					Name:              cfgProvider.Name,
+					Type:              cfgProvider.Type,
+					Endpoint:          cfgProvider.Endpoint,
+					Model:             cfgProvider.Model,
+					DeploymentName:    cfgProvider.DeploymentName,
+					APIVersion:        cfgProvider.APIVersion,
+					Region:            cfgProvider.Region,
+					TimeoutSeconds:    cfgProvider.TimeoutSeconds,
+					MaxRetries:        cfgProvider.MaxRetries,
+					RequestsPerMinute: cfgProvider.RequestsPerMinute,
+					MockResponse:      cfgProvider.MockResponse,
+					MockLatencyMs:     cfgProvider.MockLatencyMs,
+					RequiresKey:       cfgProvider.APIKey != "" || provider.RequiresSignedCredentials(cfgProvider.Type),
+					Status:            "pending",
 				}
 				if _, regErr := a.RegisterProvider(ctx, seed); regErr != nil {
 					log.Printf("Failed to seed provider %s: %v", providerID, regErr)
@@ -842,11 +909,19 @@ func (a *Loom) Initialize(ctx context.Context) error {
 				Name:                   p.Name,
 				Type:                   p.Type,
 				Endpoint:               normalizeProviderEndpoint(p.Endpoint),
+				DeploymentName:         p.DeploymentName,
+				APIVersion:             p.APIVersion,
+				Region:                 p.Region,
 				APIKey:                 apiKey,
 				Model:                  selected,
 				ConfiguredModel:        p.ConfiguredModel,
 				SelectedModel:          selected,
 				SelectedGPU:            p.SelectedGPU,
+				TimeoutSeconds:         p.TimeoutSeconds,
+				MaxRetries:             p.MaxRetries,
+				RequestsPerMinute:      p.RequestsPerMinute,
+				MockResponse:           p.MockResponse,
+				MockLatencyMs:          p.MockLatencyMs,
 				Status:                 p.Status,
 				LastHeartbeatAt:        p.LastHeartbeatAt,
 				LastHeartbeatLatencyMs: p.LastHeartbeatLatencyMs,
@@ -1032,6 +1107,9 @@ func (a *Loom) Initialize(ctx context.Context) error {
 			a.dispatcher.SetWorkflowEngine(a.workflowEngine)
 			log.Printf("Workflow engine connected to dispatcher")
 		}
+
+		// Publish node timeouts and file the CEO bead for ones that escalate
+		a.workflowEngine.SetTimeoutHandler(a.handleWorkflowNodeTimeout)
 	}
 
 	log.Printf("[Loom] DEBUG: Initialize completed successfully")
@@ -1103,7 +1181,12 @@ func (a *Loom) kickstartOpenBeads(ctx context.Context) {
 
 // Shutdown gracefully shuts down loom
 func (a *Loom) Shutdown() {
-	a.agentManager.StopAll()
+	// Let running tasks finish before tearing down workers.
+	ctx, cancel := context.WithTimeout(context.Background(), agentShutdownTimeout)
+	if err := a.agentManager.Shutdown(ctx); err != nil {
+		log.Printf("Agent shutdown: %v", err)
+	}
+	cancel()
 	if a.openclawBridge != nil {
 		a.openclawBridge.Close()
 	}
@@ -1212,6 +1295,20 @@ func (a *Loom) GetGitOpsManager() *gitops.Manager {
 	return a.gitopsManager
 }
 
+// ApplyConfig applies the settings that can change while Loom runs: the log
+// level and the dispatcher's readiness mode. It is registered with
+// config.OnReload.
+func (a *Loom) ApplyConfig(cfg *config.Config) {
+	if level, err := observability.ParseLevel(cfg.Logging.Level); err == nil {
+		observability.SetLevel(level)
+	}
+	mode := dispatch.ReadinessMode(cfg.Readiness.Mode)
+	if mode == "" {
+		mode = dispatch.ReadinessWarn
+	}
+	a.dispatcher.SetReadinessMode(mode)
+}
+
 // SetKeyManager sets the key manager for encrypted credential storage.
 // This must be called after Loom is created (since KeyManager is initialized separately in main).
 func (a *Loom) SetKeyManager(km *keymanager.KeyManager) {
@@ -1951,6 +2048,10 @@ func (a *Loom) SpawnAgent(ctx context.Context, name, personaName, projectID stri
 		providerID = providers[0].Config.ID
 	}
 
+	if err := a.validateProviderModel(ctx, providerID); err != nil {
+		return nil, err
+	}
+
 	// Spawn agent + worker
 	agent, err := a.agentManager.SpawnAgentWorker(ctx, name, personaName, projectID, providerID, persona)
 	if err != nil {
@@ -2007,7 +2108,16 @@ func (a *Loom) ListProviders() ([]*internalmodels.Provider, error) {
 	if a.database == nil {
 		return []*internalmodels.Provider{}, nil
 	}
-	return a.database.ListProviders()
+	providers, err := a.database.ListProviders()
+	if err != nil {
+		return nil, err
+	}
+	if a.providerRegistry != nil {
+		for _, p := range providers {
+			p.CircuitState = string(a.providerRegistry.CircuitState(p.ID))
+		}
+	}
+	return providers, nil
 }
 
 func (a *Loom) RegisterProvider(ctx context.Context, p *internalmodels.Provider, apiKeys ...string) (*internalmodels.Provider, error) {
@@ -2044,29 +2154,46 @@ func (a *Loom) RegisterProvider(ctx context.Context, p *internalmodels.Provider,
 	}
 	p.Model = p.SelectedModel
 
-	if err := a.database.UpsertProvider(p); err != nil {
-		return nil, err
-	}
-
 	// Pass API key to the registry so the Protocol gets authentication
 	regAPIKey := ""
 	if len(apiKeys) > 0 {
 		regAPIKey = apiKeys[0]
 	}
-	_ = a.providerRegistry.Upsert(&provider.ProviderConfig{
+	cfg := &provider.ProviderConfig{
 		ID:                     p.ID,
 		Name:                   p.Name,
 		Type:                   p.Type,
 		Endpoint:               p.Endpoint,
+		DeploymentName:         p.DeploymentName,
+		APIVersion:             p.APIVersion,
+		Region:                 p.Region,
 		APIKey:                 regAPIKey,
 		Model:                  p.SelectedModel,
 		ConfiguredModel:        p.ConfiguredModel,
 		SelectedModel:          p.SelectedModel,
 		SelectedGPU:            p.SelectedGPU,
+		TimeoutSeconds:         p.TimeoutSeconds,
+		MaxRetries:             p.MaxRetries,
+		RequestsPerMinute:      p.RequestsPerMinute,
+		MockResponse:           p.MockResponse,
+		MockLatencyMs:          p.MockLatencyMs,
 		Status:                 p.Status,
 		LastHeartbeatAt:        p.LastHeartbeatAt,
 		LastHeartbeatLatencyMs: p.LastHeartbeatLatencyMs,
-	})
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := a.database.UpsertProvider(p); err != nil {
+		return nil, err
+	}
+	if err := a.providerRegistry.Upsert(cfg); err != nil {
+		log.Printf("Failed to register provider %s: %v", p.ID, err)
+	} else {
+		// The stored rate is authoritative, including 0 to remove a limit
+		_ = a.providerRegistry.SetRateLimit(p.ID, p.RequestsPerMinute)
+	}
 	if a.eventBus != nil {
 		_ = a.eventBus.Publish(&eventbus.Event{
 			Type:   eventbus.EventTypeProviderRegistered,
@@ -2124,23 +2251,39 @@ func (a *Loom) UpdateProvider(ctx context.Context, p *internalmodels.Provider) (
 	}
 	p.Model = p.SelectedModel
 
-	if err := a.database.UpsertProvider(p); err != nil {
-		return nil, err
-	}
-
-	_ = a.providerRegistry.Upsert(&provider.ProviderConfig{
+	cfg := &provider.ProviderConfig{
 		ID:                     p.ID,
 		Name:                   p.Name,
 		Type:                   p.Type,
 		Endpoint:               p.Endpoint,
+		DeploymentName:         p.DeploymentName,
+		APIVersion:             p.APIVersion,
+		Region:                 p.Region,
 		Model:                  p.SelectedModel,
 		ConfiguredModel:        p.ConfiguredModel,
 		SelectedModel:          p.SelectedModel,
 		SelectedGPU:            p.SelectedGPU,
+		TimeoutSeconds:         p.TimeoutSeconds,
+		MaxRetries:             p.MaxRetries,
+		RequestsPerMinute:      p.RequestsPerMinute,
+		MockResponse:           p.MockResponse,
+		MockLatencyMs:          p.MockLatencyMs,
 		Status:                 p.Status,
 		LastHeartbeatAt:        p.LastHeartbeatAt,
 		LastHeartbeatLatencyMs: p.LastHeartbeatLatencyMs,
-	})
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := a.database.UpsertProvider(p); err != nil {
+		return nil, err
+	}
+	if err := a.providerRegistry.Upsert(cfg); err != nil {
+		log.Printf("Failed to update provider %s: %v", p.ID, err)
+	} else {
+		_ = a.providerRegistry.SetRateLimit(p.ID, p.RequestsPerMinute)
+	}
 	if a.eventBus != nil {
 		_ = a.eventBus.Publish(&eventbus.Event{
 			Type:   eventbus.EventTypeProviderUpdated,
@@ -2181,6 +2324,45 @@ func (a *Loom) GetProviderModels(ctx context.Context, providerID string) ([]prov
 	return a.providerRegistry.GetModels(ctx, providerID)
 }
 
+// ListProviderModels returns a provider's models from the registry's model
+// cache, querying the provider when the cache is stale.
+// ProviderRateLimitStats returns the current state of a provider's request
+// rate limiter.
+func (a *Loom) ProviderRateLimitStats(providerID string) (*provider.RateLimitStats, error) {
+	if a.providerRegistry == nil {
+		return nil, fmt.Errorf("provider registry not configured")
+	}
+	return a.providerRegistry.RateLimitStats(providerID)
+}
+
+func (a *Loom) ListProviderModels(ctx context.Context, providerID string) ([]provider.Model, error) {
+	return a.providerRegistry.ListModels(ctx, providerID)
+}
+
+// RefreshProviderModels re-queries a provider's models, bypassing the cache.
+func (a *Loom) RefreshProviderModels(ctx context.Context, providerID string) ([]provider.Model, error) {
+	return a.providerRegistry.RefreshModels(ctx, providerID)
+}
+
+// validateProviderModel checks that the provider serves its configured model
+// before an agent is bound to it. A provider whose models cannot be listed is
+// not treated as invalid, since it may just be temporarily unreachable.
+func (a *Loom) validateProviderModel(ctx context.Context, providerID string) error {
+	registered, err := a.providerRegistry.Get(providerID)
+	if err != nil {
+		return nil // Unknown providers are reported by the agent manager
+	}
+	err = a.providerRegistry.ValidateModel(ctx, providerID, registered.Config.Model)
+	var notAvailable *provider.ModelNotAvailableError
+	if errors.As(err, &notAvailable) {
+		return err
+	}
+	if err != nil {
+		log.Printf("[Loom] Skipping model validation for provider %s: %v", providerID, err)
+	}
+	return nil
+}
+
 // ReplResult represents a CEO REPL response.
 type ReplResult struct {
 	BeadID       string `json:"bead_id"`
@@ -2571,15 +2753,23 @@ func (a *Loom) NegotiateProviderModel(ctx context.Context, providerID string) (*
 		return nil, err
 	}
 	_ = a.providerRegistry.Upsert(&provider.ProviderConfig{
-		ID:              providerRecord.ID,
-		Name:            providerRecord.Name,
-		Type:            providerRecord.Type,
-		Endpoint:        providerRecord.Endpoint,
-		Model:           providerRecord.SelectedModel,
-		ConfiguredModel: providerRecord.ConfiguredModel,
-		SelectedModel:   providerRecord.SelectedModel,
-		SelectedGPU:     providerRecord.SelectedGPU,
-		Status:          "active",
+		ID:                providerRecord.ID,
+		Name:              providerRecord.Name,
+		Type:              providerRecord.Type,
+		Endpoint:          providerRecord.Endpoint,
+		DeploymentName:    providerRecord.DeploymentName,
+		APIVersion:        providerRecord.APIVersion,
+		Region:            providerRecord.Region,
+		Model:             providerRecord.SelectedModel,
+		ConfiguredModel:   providerRecord.ConfiguredModel,
+		SelectedModel:     providerRecord.SelectedModel,
+		SelectedGPU:       providerRecord.SelectedGPU,
+		TimeoutSeconds:    providerRecord.TimeoutSeconds,
+		MaxRetries:        providerRecord.MaxRetries,
+		RequestsPerMinute: providerRecord.RequestsPerMinute,
+		MockResponse:      providerRecord.MockResponse,
+		MockLatencyMs:     providerRecord.MockLatencyMs,
+		Status:            "active",
 	})
 	if a.eventBus != nil {
 		_ = a.eventBus.Publish(&eventbus.Event{
@@ -2693,15 +2883,25 @@ func normalizeRole(role string) string {
 
 // CreateBead creates a new work bead
 func (a *Loom) CreateBead(title, description string, priority models.BeadPriority, beadType, projectID string) (*models.Bead, error) {
+	return a.CreateBeadIdempotent("", title, description, priority, beadType, projectID)
+}
+
+// CreateBeadIdempotent creates a new work bead, or returns the bead already
+// created with the same idempotency key so retried requests don't duplicate
+// work. An empty key always creates a bead.
+func (a *Loom) CreateBeadIdempotent(key, title, description string, priority models.BeadPriority, beadType, projectID string) (*models.Bead, error) {
 	// Verify project exists
 	if _, err := a.projectManager.GetProject(projectID); err != nil {
 		return nil, fmt.Errorf("project not found: %w", err)
 	}
 
-	bead, err := a.beadsManager.CreateBead(title, description, priority, beadType, projectID)
+	bead, created, err := a.beadsManager.CreateBeadIdempotent(key, title, description, priority, beadType, projectID)
 	if err != nil {
 		return nil, err
 	}
+	if !created {
+		return bead, nil
+	}
 
 	// Auto-assign to default triage agent (CTO > Engineering Manager > any)
 	if bead.AssignedTo == "" {
@@ -2867,16 +3067,17 @@ func (a *Loom) createApplyFixBead(approvalBead *models.Bead, closeReason string)
 		"created_by":       "auto_fix_system",
 	}
 
+	// Prefer the agent who investigated the bug and proposed the fix; if
+	// it is busy another agent applies it.
+	if agentID != "" {
+		ctx[dispatch.PreferredAgentKey] = agentID
+	}
+
 	updates := map[string]interface{}{
 		"tags":    tags,
 		"context": ctx,
 	}
 
-	// Assign to the agent who created the proposal, if available
-	if agentID != "" {
-		updates["assigned_to"] = agentID
-	}
-
 	if err := a.beadsManager.UpdateBead(bead.ID, updates); err != nil {
 		log.Printf("[AutoFix] Failed to update apply-fix bead %s: %v", bead.ID, err)
 		// Don't fail - bead is created, just missing some metadata
@@ -2920,18 +3121,35 @@ func extractOriginalBugID(description string) string {
 
 // CreateDecisionBead creates a decision bead when an agent needs a decision
 func (a *Loom) CreateDecisionBead(question, parentBeadID, requesterID string, options []string, recommendation string, priority models.BeadPriority, projectID string) (*models.DecisionBead, error) {
+	return a.CreateDecisionBeadWithTimeout(question, parentBeadID, requesterID, options, recommendation, priority, projectID, 0, "")
+}
+
+// CreateDecisionBeadWithTimeout creates a decision bead that resolves to
+// defaultDecision if nobody decides within timeout. A zero timeout waits
+// for a decider indefinitely.
+func (a *Loom) CreateDecisionBeadWithTimeout(question, parentBeadID, requesterID string, options []string, recommendation string, priority models.BeadPriority, projectID string, timeout time.Duration, defaultDecision string) (*models.DecisionBead, error) {
 	// Verify requester exists (agent or user/system)
 	if requesterID != "system" && !strings.HasPrefix(requesterID, "user-") {
 		if _, err := a.agentManager.GetAgent(requesterID); err != nil {
 			return nil, fmt.Errorf("requester agent not found: %w", err)
 		}
 	}
+	if timeout != 0 {
+		if err := decision.ValidateAutoResolve(options, timeout, defaultDecision); err != nil {
+			return nil, fmt.Errorf("invalid decision timeout: %w", err)
+		}
+	}
 
 	// Create decision
 	decision, err := a.decisionManager.CreateDecision(question, parentBeadID, requesterID, options, recommendation, priority, projectID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create decision: %w", err)
 	}
+	if timeout != 0 {
+		if err := a.decisionManager.SetAutoResolve(decision.ID, timeout, defaultDecision); err != nil {
+			return nil, fmt.Errorf("failed to set decision timeout: %w", err)
+		}
+	}
 
 	// Block parent bead on this decision
 	if parentBeadID != "" {
@@ -3051,6 +3269,73 @@ func (a *Loom) EscalateBeadToCEO(beadID, reason, returnedTo string) (*models.Dec
 	return decision, nil
 }
 
+// DeadLetterBead takes a bead that keeps failing dispatch out of the
+// dispatch loop: it moves the bead to dead-letter and files a P0 decision,
+// related to it, that sums up its failure history for human review. The
+// decision resolves like a CEO escalation.
+func (a *Loom) DeadLetterBead(beadID, reason string) (*models.DecisionBead, error) {
+	b, err := a.beadsManager.GetBead(beadID)
+	if err != nil {
+		return nil, fmt.Errorf("bead not found: %w", err)
+	}
+
+	var summary strings.Builder
+	fmt.Fprintf(&summary, "Bead %s (%s) was moved to dead-letter and is no longer dispatched.\n\nReason: %s\n", b.ID, b.Title, reason)
+	for _, key := range []string{"dispatch_count", "dispatch_history", "last_run_error", "loop_detected_reason", "progress_summary"} {
+		if v := b.Context[key]; v != "" {
+			fmt.Fprintf(&summary, "%s: %s\n", key, v)
+		}
+	}
+	summary.WriteString("\nChoose: approve (close it) | deny (reopen for triage) | needs_more_info (reopen for its last agent)")
+
+	decision, err := a.decisionManager.CreateDecision(summary.String(), beadID, "system",
+		[]string{"approve", "deny", "needs_more_info"}, "", models.BeadPriorityP0, b.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dead-letter decision: %w", err)
+	}
+	if decision.Context == nil {
+		decision.Context = make(map[string]string)
+	}
+	// The dispatcher clears assigned_to before dead-lettering, so return
+	// the bead to the agent that last ran it.
+	returnedTo := b.Context["agent_id"]
+	if returnedTo == "" {
+		returnedTo = b.AssignedTo
+	}
+	decision.Context["escalated_to"] = "ceo"
+	decision.Context["returned_to"] = returnedTo
+	decision.Context["escalation_reason"] = reason
+	decision.Context["dead_letter_bead_id"] = beadID
+	decision.RelatedTo = append(decision.RelatedTo, beadID)
+
+	_, err = a.UpdateBead(beadID, map[string]interface{}{
+		"status":     models.BeadStatusDeadLetter,
+		"related_to": append(append([]string{}, b.RelatedTo...), decision.ID),
+		"context": map[string]string{
+			"redispatch_requested":    "false",
+			"dead_letter_decision_id": decision.ID,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if a.eventBus != nil {
+		_ = a.eventBus.Publish(&eventbus.Event{
+			Type:      eventbus.EventTypeDecisionCreated,
+			Source:    "dead-letter",
+			ProjectID: b.ProjectID,
+			Data: map[string]interface{}{
+				"decision_id": decision.ID,
+				"bead_id":     beadID,
+				"reason":      reason,
+			},
+		})
+	}
+
+	return decision, nil
+}
+
 func (a *Loom) applyCEODecisionToParent(decisionID string) error {
 	d, err := a.decisionManager.GetDecision(decisionID)
 	if err != nil || d == nil || d.Context == nil {
@@ -3077,28 +3362,67 @@ func (a *Loom) applyCEODecisionToParent(decisionID string) error {
 		_, _ = a.UpdateBead(parentID, map[string]interface{}{
 			"status":      models.BeadStatusOpen,
 			"assigned_to": denyAssignee,
-			"context": map[string]string{
+			"context": resetDispatchCounters(map[string]string{
 				"ceo_denied_at":      time.Now().UTC().Format(time.RFC3339),
 				"ceo_comment":        d.Rationale,
 				"reassigned_to_role": "default-triage",
-			},
+			}),
 		})
 	case "needs_more_info":
 		returnedTo := d.Context["returned_to"]
 		_, _ = a.UpdateBead(parentID, map[string]interface{}{
 			"status":      models.BeadStatusOpen,
 			"assigned_to": returnedTo,
-			"context": map[string]string{
+			"context": resetDispatchCounters(map[string]string{
 				"redispatch_requested":   "true",
 				"ceo_needs_more_info_at": time.Now().UTC().Format(time.RFC3339),
 				"ceo_comment":            d.Rationale,
-			},
+			}),
 		})
 	}
 
 	return nil
 }
 
+// resetDispatchCounters adds to ctx the updates that clear a bead's dispatch
+// count and the histories loop detection reads, so a bead reopened by a
+// decision is not dead-lettered again on the next dispatch pass.
+func resetDispatchCounters(ctx map[string]string) map[string]string {
+	for _, key := range []string{"dispatch_count", "dispatch_history", "action_history", "error_history", "progress_metrics", "loop_detected_reason"} {
+		ctx[key] = ""
+	}
+	ctx["loop_detected"] = "false"
+	return ctx
+}
+
+// AutoResolveDecisions applies the default decision to decisions whose
+// timeout has passed, unblocks the beads waiting on them and announces each
+// one so the UI can flag it. It returns the number resolved.
+func (a *Loom) AutoResolveDecisions() int {
+	resolved := a.decisionManager.ResolveExpired(time.Now())
+	for _, d := range resolved {
+		log.Printf("[Decisions] Decision %s auto-resolved on timeout: %s", d.ID, d.Decision)
+		if err := a.UnblockDependents(d.ID); err != nil {
+			log.Printf("[Decisions] Failed to unblock dependents of %s: %v", d.ID, err)
+		}
+		if a.eventBus != nil {
+			_ = a.eventBus.Publish(&eventbus.Event{
+				Type:      eventbus.EventTypeDecisionAutoResolved,
+				Source:    "decision-manager",
+				ProjectID: d.ProjectID,
+				Data: map[string]interface{}{
+					"decision_id": d.ID,
+					"decision":    d.Decision,
+					"decider_id":  d.DeciderID,
+					"rationale":   d.Rationale,
+				},
+			})
+		}
+		_ = a.applyCEODecisionToParent(d.ID)
+	}
+	return len(resolved)
+}
+
 // UnblockDependents unblocks beads that were waiting on a decision
 func (a *Loom) UnblockDependents(decisionID string) error {
 	blocked := a.decisionManager.GetBlockedBeads(decisionID)
@@ -3168,6 +3492,44 @@ func (a *Loom) ClaimBead(beadID, agentID string) error {
 	return nil
 }
 
+// ClaimBeads atomically claims a batch of beads for an agent. Beads taken by
+// other agents or missing are reported in the result rather than failing the
+// batch. The agent's current bead becomes the first one claimed.
+func (a *Loom) ClaimBeads(beadIDs []string, agentID string) (*beads.ClaimResult, error) {
+	if _, err := a.agentManager.GetAgent(agentID); err != nil {
+		return nil, fmt.Errorf("agent not found: %w", err)
+	}
+
+	result, err := a.beadsManager.ClaimBeads(beadIDs, agentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim beads: %w", err)
+	}
+	if len(result.Claimed) == 0 {
+		return result, nil
+	}
+
+	if err := a.agentManager.AssignBead(agentID, result.Claimed[0]); err != nil {
+		return result, fmt.Errorf("failed to assign bead to agent: %w", err)
+	}
+
+	if a.eventBus != nil {
+		for _, beadID := range result.Claimed {
+			projectID := ""
+			if b, err := a.beadsManager.GetBead(beadID); err == nil && b != nil {
+				projectID = b.ProjectID
+			}
+			_ = a.eventBus.PublishBeadEvent(eventbus.EventTypeBeadAssigned, beadID, projectID, map[string]interface{}{
+				"assigned_to": agentID,
+			})
+			_ = a.eventBus.PublishBeadEvent(eventbus.EventTypeBeadStatusChange, beadID, projectID, map[string]interface{}{
+				"status": string(models.BeadStatusInProgress),
+			})
+		}
+	}
+
+	return result, nil
+}
+
 // UpdateBead updates a bead and publishes relevant events.
 func (a *Loom) UpdateBead(beadID string, updates map[string]interface{}) (*models.Bead, error) {
 	if err := a.beadsManager.UpdateBead(beadID, updates); err != nil {
@@ -3198,6 +3560,38 @@ func (a *Loom) UpdateBead(beadID string, updates map[string]interface{}) (*model
 	return bead, nil
 }
 
+// CancelBead stops the task running for a bead, if any, and reopens the
+// bead unassigned with cancelled_by recorded in its context. It reports
+// whether a running task was cancelled.
+func (a *Loom) CancelBead(beadID, cancelledBy string) (*models.Bead, bool, error) {
+	if _, err := a.beadsManager.GetBead(beadID); err != nil {
+		return nil, false, fmt.Errorf("bead not found: %w", err)
+	}
+	if cancelledBy == "" {
+		cancelledBy = "system"
+	}
+
+	cancelled := a.agentManager.CancelTask(beadID)
+	bead, err := a.UpdateBead(beadID, map[string]interface{}{
+		"status":      models.BeadStatusOpen,
+		"assigned_to": "",
+		"context": map[string]string{
+			"cancelled_by": cancelledBy,
+			"cancelled_at": time.Now().UTC().Format(time.RFC3339),
+		},
+	})
+	if err != nil {
+		return nil, cancelled, err
+	}
+	observability.Info("bead.cancel", map[string]interface{}{
+		"bead_id":        beadID,
+		"project_id":     bead.ProjectID,
+		"cancelled_by":   cancelledBy,
+		"task_cancelled": cancelled,
+	})
+	return bead, cancelled, nil
+}
+
 // GetReadyBeads returns beads that are ready to work on
 func (a *Loom) GetReadyBeads(projectID string) ([]*models.Bead, error) {
 	return a.beadsManager.GetReadyBeads(projectID)
@@ -3208,6 +3602,11 @@ func (a *Loom) GetWorkGraph(projectID string) (*models.WorkGraph, error) {
 	return a.beadsManager.GetWorkGraph(projectID)
 }
 
+// GetWorkGraphSince returns the part of the dependency graph changed after since
+func (a *Loom) GetWorkGraphSince(projectID string, since time.Time) (*models.WorkGraphDelta, error) {
+	return a.beadsManager.GetWorkGraphSince(projectID, since)
+}
+
 // GetFileLockManager returns the file lock manager
 func (a *Loom) GetFileLockManager() *FileLockManager {
 	return a.fileLockManager
@@ -3218,8 +3617,53 @@ func (a *Loom) GetGitopsManager() *gitops.Manager {
 	return a.gitopsManager
 }
 
+// handleWorkflowNodeTimeout publishes a workflow node timeout, cancels the
+// task still working on the timed-out node and, when the execution was
+// escalated, has the dispatcher file the CEO escalation bead.
+func (a *Loom) handleWorkflowNodeTimeout(t workflow.NodeTimeout) {
+	var bead *models.Bead
+	if a.beadsManager != nil {
+		bead, _ = a.beadsManager.GetBead(t.Execution.BeadID)
+	}
+	if a.eventBus != nil {
+		projectID := ""
+		if bead != nil {
+			projectID = bead.ProjectID
+		}
+		_ = a.eventBus.Publish(&eventbus.Event{
+			Type:      eventbus.EventTypeWorkflowNodeTimeout,
+			Source:    "workflow-engine",
+			ProjectID: projectID,
+			Data: map[string]interface{}{
+				"execution_id":    t.Execution.ID,
+				"workflow_id":     t.Execution.WorkflowID,
+				"bead_id":         t.Execution.BeadID,
+				"node_key":        t.NodeKey,
+				"timeout_seconds": int(t.Timeout.Seconds()),
+				"elapsed_seconds": int(t.Elapsed.Seconds()),
+				"escalated":       t.Escalated,
+				"status":          string(t.Execution.Status),
+			},
+		})
+	}
+	if bead != nil && bead.Status == models.BeadStatusInProgress && a.agentManager != nil {
+		if _, _, err := a.CancelBead(bead.ID, "workflow-timeout"); err != nil {
+			log.Printf("[Workflow] Failed to cancel timed-out task for bead %s: %v", bead.ID, err)
+		}
+	}
+	if t.Escalated && a.dispatcher != nil {
+		if err := a.dispatcher.EscalateWorkflowExecution(t.Execution); err != nil {
+			log.Printf("[Workflow] Failed to escalate timed-out execution %s: %v", t.Execution.ID, err)
+		}
+	}
+}
+
 // StartMaintenanceLoop starts background maintenance tasks
 func (a *Loom) StartMaintenanceLoop(ctx context.Context) {
+	if a.workflowEngine != nil {
+		a.workflowEngine.StartTimeoutSweeper(ctx, workflow.DefaultTimeoutSweepInterval)
+	}
+
 	ticker := time.NewTicker(1 * time.Minute)
 	defer ticker.Stop()
 
@@ -3246,12 +3690,18 @@ func (a *Loom) StartMaintenanceLoop(ctx context.Context) {
 				}
 			}
 
+			// Mark agents whose project agent went silent as unreachable
+			a.reapStaleAgents()
+
 			// FIX #5: Reset agents stuck in working state for > 5 minutes
 			resetCount := a.agentManager.ResetStuckAgents(5 * time.Minute)
 			if resetCount > 0 {
 				log.Printf("[Maintenance] Reset %d stuck agents", resetCount)
 			}
 
+			// Apply default decisions whose timeout has passed
+			a.AutoResolveDecisions()
+
 			// NOTE: Stuck bead resolution is handled by the Ralph Loop
 			// (LoomHeartbeatActivity). CEO escalation is only available via
 			// explicit CLI/REPL commands.
@@ -3317,6 +3767,50 @@ func (a *Loom) resetInconsistentAgents() int {
 	return count
 }
 
+// reapStaleAgents marks agents without a recent heartbeat as unreachable,
+// releases their file locks and reopens the beads they were working on.
+func (a *Loom) reapStaleAgents() {
+	timeout := a.config.Agents.HeartbeatTimeout
+	if timeout <= 0 {
+		timeout = 3 * a.config.Agents.HeartbeatInterval
+	}
+	for _, stale := range a.agentManager.ReapStaleAgents(time.Now(), timeout) {
+		_ = a.fileLockManager.ReleaseAgentLocks(stale.AgentID)
+
+		beads, err := a.beadsManager.ListBeads(map[string]interface{}{
+			"assigned_to": stale.AgentID,
+			"status":      models.BeadStatusInProgress,
+		})
+		if err != nil {
+			log.Printf("[Maintenance] Failed to list beads of unreachable agent %s: %v", stale.AgentID, err)
+			continue
+		}
+		for _, b := range beads {
+			if err := a.beadsManager.UpdateBead(b.ID, map[string]interface{}{
+				"status":      models.BeadStatusOpen,
+				"assigned_to": "",
+			}); err != nil {
+				log.Printf("[Maintenance] Failed to reopen bead %s of unreachable agent %s: %v", b.ID, stale.AgentID, err)
+				continue
+			}
+			log.Printf("[Maintenance] Reopened bead %s of unreachable agent %s", b.ID, stale.AgentID)
+		}
+	}
+}
+
+// RecordProjectHeartbeat records a heartbeat from a project's agent container
+func (a *Loom) RecordProjectHeartbeat(projectID string) int {
+	return a.agentManager.RecordProjectHeartbeat(projectID, time.Now())
+}
+
+// RecordProjectAgentResult adds the output of a task run in a project's agent
+// container to the log of the agent its bead is assigned to.
+func (a *Loom) RecordProjectAgentResult(taskID, beadID, output, errMsg string) {
+	if a.dispatcher != nil {
+		a.dispatcher.RecordAgentOutput(beadID, taskID, output, errMsg)
+	}
+}
+
 // StartDispatchLoop runs a periodic dispatcher that fills all idle agents with work.
 func (a *Loom) StartDispatchLoop(ctx context.Context, interval time.Duration) {
 	os.WriteFile("/tmp/dispatch-loop-entered.txt", []byte(fmt.Sprintf("a=%v dispatcher=%v\n", a != nil, a != nil && a.dispatcher != nil)), 0644)
@@ -3401,10 +3895,18 @@ func (a *Loom) checkProviderHealthAndActivate(providerID string) {
 			Name:                   dbProvider.Name,
 			Type:                   dbProvider.Type,
 			Endpoint:               dbProvider.Endpoint,
+			DeploymentName:         dbProvider.DeploymentName,
+			APIVersion:             dbProvider.APIVersion,
+			Region:                 dbProvider.Region,
 			Model:                  dbProvider.SelectedModel,
 			ConfiguredModel:        dbProvider.ConfiguredModel,
 			SelectedModel:          dbProvider.SelectedModel,
 			SelectedGPU:            dbProvider.SelectedGPU,
+			TimeoutSeconds:         dbProvider.TimeoutSeconds,
+			MaxRetries:             dbProvider.MaxRetries,
+			RequestsPerMinute:      dbProvider.RequestsPerMinute,
+			MockResponse:           dbProvider.MockResponse,
+			MockLatencyMs:          dbProvider.MockLatencyMs,
 			Status:                 "active",
 			LastHeartbeatAt:        dbProvider.LastHeartbeatAt,
 			LastHeartbeatLatencyMs: dbProvider.LastHeartbeatLatencyMs,
@@ -2,6 +2,7 @@ package loom
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -1005,6 +1006,93 @@ func TestLoom_RegisterProvider(t *testing.T) {
 		}
 	})
 
+	t.Run("invalid config fails", func(t *testing.T) {
+		p := &internalmodels.Provider{ID: "bad-endpoint", Type: "ollama", Endpoint: "ftp://host"}
+		_, err := l.RegisterProvider(ctx, p)
+		var cfgErr *provider.ConfigError
+		if !errors.As(err, &cfgErr) || cfgErr.Field != "endpoint" {
+			t.Fatalf("RegisterProvider() error = %v, want an endpoint ConfigError", err)
+		}
+		if _, err := l.database.GetProvider("bad-endpoint"); err == nil {
+			t.Error("invalid provider should not be stored")
+		}
+	})
+
+	t.Run("limits persisted", func(t *testing.T) {
+		p := &internalmodels.Provider{ID: "limits", Endpoint: "http://localhost:8000", TimeoutSeconds: 60, MaxRetries: 5}
+		if _, err := l.RegisterProvider(ctx, p); err != nil {
+			t.Fatalf("RegisterProvider() error = %v", err)
+		}
+		stored, err := l.database.GetProvider("limits")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stored.TimeoutSeconds != 60 || stored.MaxRetries != 5 {
+			t.Errorf("stored limits = %ds, %d retries, want 60s, 5", stored.TimeoutSeconds, stored.MaxRetries)
+		}
+		reg, err := l.providerRegistry.Get("limits")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if reg.Config.TimeoutSeconds != 60 || reg.Config.MaxRetries != 5 {
+			t.Errorf("registry limits = %ds, %d retries, want 60s, 5", reg.Config.TimeoutSeconds, reg.Config.MaxRetries)
+		}
+	})
+
+	t.Run("rate limit persisted and cleared", func(t *testing.T) {
+		p := &internalmodels.Provider{ID: "rpm", Endpoint: "http://localhost:8000", RequestsPerMinute: 30}
+		if _, err := l.RegisterProvider(ctx, p); err != nil {
+			t.Fatalf("RegisterProvider() error = %v", err)
+		}
+		stored, err := l.database.GetProvider("rpm")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stored.RequestsPerMinute != 30 {
+			t.Errorf("stored RequestsPerMinute = %d, want 30", stored.RequestsPerMinute)
+		}
+		stats, err := l.ProviderRateLimitStats("rpm")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stats.RequestsPerMinute != 30 {
+			t.Errorf("registry RequestsPerMinute = %d, want 30", stats.RequestsPerMinute)
+		}
+
+		stored.RequestsPerMinute = 0
+		if _, err := l.UpdateProvider(ctx, stored); err != nil {
+			t.Fatalf("UpdateProvider() error = %v", err)
+		}
+		stats, err = l.ProviderRateLimitStats("rpm")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stats.RequestsPerMinute != 0 {
+			t.Errorf("registry RequestsPerMinute after clearing = %d, want 0", stats.RequestsPerMinute)
+		}
+	})
+
+	t.Run("mock settings persisted", func(t *testing.T) {
+		p := &internalmodels.Provider{ID: "mock", Type: "mock", Model: "mock-model", MockResponse: "done", MockLatencyMs: 5}
+		if _, err := l.RegisterProvider(ctx, p); err != nil {
+			t.Fatalf("RegisterProvider() error = %v", err)
+		}
+		stored, err := l.database.GetProvider("mock")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stored.MockResponse != "done" || stored.MockLatencyMs != 5 {
+			t.Errorf("stored mock settings = %q, %dms, want \"done\", 5ms", stored.MockResponse, stored.MockLatencyMs)
+		}
+		reg, err := l.providerRegistry.Get("mock")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if reg.Config.MockResponse != "done" || reg.Config.MockLatencyMs != 5 {
+			t.Errorf("registry mock settings = %q, %dms, want \"done\", 5ms", reg.Config.MockResponse, reg.Config.MockLatencyMs)
+		}
+	})
+
 	t.Run("empty ID fails", func(t *testing.T) {
 		p := &internalmodels.Provider{Name: "No ID"}
 		_, err := l.RegisterProvider(ctx, p)
@@ -1014,7 +1102,7 @@ func TestLoom_RegisterProvider(t *testing.T) {
 	})
 
 	t.Run("defaults filled in", func(t *testing.T) {
-		p := &internalmodels.Provider{ID: "defaults-test"}
+		p := &internalmodels.Provider{ID: "defaults-test", Endpoint: "http://gpu-box:8000"}
 		result, err := l.RegisterProvider(ctx, p)
 		if err != nil {
 			t.Fatalf("RegisterProvider() error = %v", err)
@@ -1106,7 +1194,7 @@ func TestLoom_UpdateProvider_Errors(t *testing.T) {
 	})
 
 	t.Run("defaults filled in", func(t *testing.T) {
-		p := &internalmodels.Provider{ID: "update-defaults"}
+		p := &internalmodels.Provider{ID: "update-defaults", Endpoint: "http://gpu-box:8000"}
 		result, err := l.UpdateProvider(ctx, p)
 		if err != nil {
 			t.Fatalf("UpdateProvider() error = %v", err)
@@ -1513,7 +1601,8 @@ func TestLoom_RegisterProvider_ModelDefaults(t *testing.T) {
 
 	t.Run("no model defaults to Nemotron", func(t *testing.T) {
 		p := &internalmodels.Provider{
-			ID: "no-model",
+			ID:       "no-model",
+			Endpoint: "http://localhost:8000",
 		}
 		result, err := l.RegisterProvider(ctx, p)
 		if err != nil {
@@ -1529,8 +1618,9 @@ func TestLoom_RegisterProvider_ModelDefaults(t *testing.T) {
 
 	t.Run("explicit model preserved", func(t *testing.T) {
 		p := &internalmodels.Provider{
-			ID:    "explicit-model",
-			Model: "custom-model-v1",
+			ID:       "explicit-model",
+			Endpoint: "http://localhost:8000",
+			Model:    "custom-model-v1",
 		}
 		result, err := l.RegisterProvider(ctx, p)
 		if err != nil {
@@ -1619,6 +1709,48 @@ func TestLoom_CreateDecisionBead_InvalidRequester(t *testing.T) {
 	}
 }
 
+func TestLoom_CreateDecisionBeadWithTimeout_AutoResolves(t *testing.T) {
+	l, tmpDir := testLoom(t)
+	defer os.RemoveAll(tmpDir)
+
+	proj, err := l.CreateProject("decision-timeout", ".", "", "", nil)
+	if err != nil {
+		t.Fatalf("CreateProject() error = %v", err)
+	}
+
+	if _, err := l.CreateDecisionBeadWithTimeout("Ship?", "", "system", []string{"yes", "no"}, "yes", models.BeadPriorityP2, proj.ID, time.Minute, "maybe"); err == nil {
+		t.Error("CreateDecisionBeadWithTimeout() should reject a default that is not an option")
+	}
+
+	d, err := l.CreateDecisionBeadWithTimeout("Ship?", "", "system", []string{"yes", "no"}, "yes", models.BeadPriorityP2, proj.ID, time.Millisecond, "no")
+	if err != nil {
+		t.Fatalf("CreateDecisionBeadWithTimeout() error = %v", err)
+	}
+
+	// A bead waiting on the decision is unblocked when it auto-resolves.
+	waiting, err := l.CreateBead("Waiting on decision", "", models.BeadPriorityP2, "task", proj.ID)
+	if err != nil {
+		t.Fatalf("CreateBead() error = %v", err)
+	}
+	waiting.BlockedBy = []string{d.ID}
+	waiting.Status = models.BeadStatusBlocked
+	d.Blocks = []string{waiting.ID}
+
+	time.Sleep(5 * time.Millisecond)
+	if n := l.AutoResolveDecisions(); n != 1 {
+		t.Fatalf("AutoResolveDecisions() = %d, want 1", n)
+	}
+	if !d.AutoResolved || d.Decision != "no" || d.Status != models.BeadStatusClosed {
+		t.Errorf("decision = %+v, want auto-resolved to no", d)
+	}
+	if waiting.Status != models.BeadStatusOpen || len(waiting.BlockedBy) != 0 {
+		t.Errorf("waiting bead status = %s, blocked by %v, want open", waiting.Status, waiting.BlockedBy)
+	}
+	if n := l.AutoResolveDecisions(); n != 0 {
+		t.Errorf("AutoResolveDecisions() again = %d, want 0", n)
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Loom method tests: MakeDecision
 // ---------------------------------------------------------------------------
@@ -2075,6 +2207,27 @@ func TestLoom_ApplyConfigSnapshot_NoDatabase(t *testing.T) {
 	}
 }
 
+func TestLoom_ApplyConfigSnapshot_LoadsProviders(t *testing.T) {
+	l, tmpDir := testLoom(t)
+	defer os.RemoveAll(tmpDir)
+
+	// A provider that has not picked a model yet fails Register's
+	// validation but must still be loaded.
+	snap := &ConfigSnapshot{Providers: []*internalmodels.Provider{
+		{ID: "snap-openai", Name: "Snapshot OpenAI", Type: "openai", Endpoint: "http://127.0.0.1:1/v1", Region: "us-east-1"},
+	}}
+	if err := l.ApplyConfigSnapshot(context.Background(), snap); err != nil {
+		t.Fatalf("ApplyConfigSnapshot() error = %v", err)
+	}
+	rp, err := l.GetProviderRegistry().Get("snap-openai")
+	if err != nil {
+		t.Fatalf("provider not loaded into the registry: %v", err)
+	}
+	if rp.Config.Region != "us-east-1" {
+		t.Errorf("Region = %q, want us-east-1", rp.Config.Region)
+	}
+}
+
 func TestLoom_ReloadFromDatabase_NoDatabase(t *testing.T) {
 	l, tmpDir := testLoom(t, func(c *config.Config) {
 		c.Database = config.DatabaseConfig{}
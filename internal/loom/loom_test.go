@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/jordanhubbard/loom/internal/provider"
 	"github.com/jordanhubbard/loom/pkg/config"
 	"github.com/jordanhubbard/loom/pkg/models"
 )
@@ -668,6 +670,172 @@ func TestLoom_ClaimBead(t *testing.T) {
 	}
 }
 
+func TestLoom_ClaimBeads_UnknownAgent(t *testing.T) {
+	loom, tmpDir := testLoom(t)
+	defer os.RemoveAll(tmpDir)
+
+	if _, err := loom.ClaimBeads([]string{"b1"}, "nonexistent-agent"); err == nil {
+		t.Error("ClaimBeads with nonexistent agent should fail")
+	}
+}
+
+func TestLoom_CancelBead(t *testing.T) {
+	loom, tmpDir := testLoom(t)
+	defer os.RemoveAll(tmpDir)
+
+	if _, _, err := loom.CancelBead("nonexistent", "alice"); err == nil {
+		t.Error("CancelBead('nonexistent') should fail")
+	}
+
+	project, err := loom.CreateProject("cancel-project", ".", "", "", nil)
+	if err != nil {
+		t.Fatalf("CreateProject() error = %v", err)
+	}
+	bead, err := loom.CreateBead("To Cancel", "will be cancelled", models.BeadPriorityP2, "task", project.ID)
+	if err != nil {
+		t.Fatalf("CreateBead() error = %v", err)
+	}
+	if _, err := loom.UpdateBead(bead.ID, map[string]interface{}{
+		"status":      models.BeadStatusInProgress,
+		"assigned_to": "agent-1",
+	}); err != nil {
+		t.Fatalf("UpdateBead() error = %v", err)
+	}
+
+	cancelled, taskCancelled, err := loom.CancelBead(bead.ID, "alice")
+	if err != nil {
+		t.Fatalf("CancelBead() error = %v", err)
+	}
+	if taskCancelled {
+		t.Error("CancelBead() reported a cancelled task when none was running")
+	}
+	if cancelled.Status != models.BeadStatusOpen || cancelled.AssignedTo != "" {
+		t.Errorf("bead is %s assigned to %q, want open and unassigned", cancelled.Status, cancelled.AssignedTo)
+	}
+	if got := cancelled.Context["cancelled_by"]; got != "alice" {
+		t.Errorf("cancelled_by = %q, want alice", got)
+	}
+}
+
+func TestLoom_DeadLetterBead(t *testing.T) {
+	loom, tmpDir := testLoom(t)
+	defer os.RemoveAll(tmpDir)
+
+	if _, err := loom.DeadLetterBead("nonexistent", "stuck"); err == nil {
+		t.Error("DeadLetterBead('nonexistent') should fail")
+	}
+
+	project, err := loom.CreateProject("dead-letter-project", ".", "", "", nil)
+	if err != nil {
+		t.Fatalf("CreateProject() error = %v", err)
+	}
+	bead, err := loom.CreateBead("Looping", "keeps failing", models.BeadPriorityP2, "task", project.ID)
+	if err != nil {
+		t.Fatalf("CreateBead() error = %v", err)
+	}
+	if _, err := loom.UpdateBead(bead.ID, map[string]interface{}{
+		"context": map[string]string{"dispatch_history": `["agent-1","agent-1"]`, "last_run_error": "tests fail"},
+	}); err != nil {
+		t.Fatalf("UpdateBead() error = %v", err)
+	}
+
+	decision, err := loom.DeadLetterBead(bead.ID, "dispatch_count=21 exceeded max_hops=20")
+	if err != nil {
+		t.Fatalf("DeadLetterBead() error = %v", err)
+	}
+	if decision.Priority != models.BeadPriorityP0 || decision.Type != "decision" {
+		t.Errorf("decision is %s P%d, want a P0 decision", decision.Type, decision.Priority)
+	}
+	if !strings.Contains(decision.Question, `["agent-1","agent-1"]`) || !strings.Contains(decision.Question, "tests fail") {
+		t.Errorf("question %q should carry the dispatch history and last error", decision.Question)
+	}
+	if len(decision.RelatedTo) != 1 || decision.RelatedTo[0] != bead.ID {
+		t.Errorf("decision related_to = %v, want [%s]", decision.RelatedTo, bead.ID)
+	}
+
+	got, err := loom.GetBeadsManager().GetBead(bead.ID)
+	if err != nil {
+		t.Fatalf("GetBead() error = %v", err)
+	}
+	if got.Status != models.BeadStatusDeadLetter {
+		t.Errorf("status = %s, want dead-letter", got.Status)
+	}
+	if len(got.RelatedTo) != 1 || got.RelatedTo[0] != decision.ID {
+		t.Errorf("bead related_to = %v, want [%s]", got.RelatedTo, decision.ID)
+	}
+}
+
+func TestLoom_DeadLetterDecisionReopensBead(t *testing.T) {
+	loom, tmpDir := testLoom(t)
+	defer os.RemoveAll(tmpDir)
+
+	project, err := loom.CreateProject("dead-letter-reopen", ".", "", "", nil)
+	if err != nil {
+		t.Fatalf("CreateProject() error = %v", err)
+	}
+	bead, err := loom.CreateBead("Looping", "keeps failing", models.BeadPriorityP2, "task", project.ID)
+	if err != nil {
+		t.Fatalf("CreateBead() error = %v", err)
+	}
+	// As left by the dispatcher: assigned_to is cleared, agent_id kept.
+	if _, err := loom.UpdateBead(bead.ID, map[string]interface{}{
+		"assigned_to": "",
+		"context": map[string]string{
+			"agent_id":         "agent-1",
+			"dispatch_count":   "21",
+			"dispatch_history": `["agent-1","agent-1"]`,
+			"error_history":    `[{"error":"tests fail"}]`,
+		},
+	}); err != nil {
+		t.Fatalf("UpdateBead() error = %v", err)
+	}
+
+	decision, err := loom.DeadLetterBead(bead.ID, "dispatch_count=21 exceeded max_hops=20")
+	if err != nil {
+		t.Fatalf("DeadLetterBead() error = %v", err)
+	}
+	if got := decision.Context["returned_to"]; got != "agent-1" {
+		t.Errorf("returned_to = %q, want the last agent", got)
+	}
+
+	if err := loom.MakeDecision(decision.ID, "user-admin", "needs_more_info", "try a smaller change"); err != nil {
+		t.Fatalf("MakeDecision() error = %v", err)
+	}
+	got, err := loom.GetBeadsManager().GetBead(bead.ID)
+	if err != nil {
+		t.Fatalf("GetBead() error = %v", err)
+	}
+	if got.Status != models.BeadStatusOpen || got.AssignedTo != "agent-1" {
+		t.Errorf("bead is %s assigned to %q, want open and assigned to agent-1", got.Status, got.AssignedTo)
+	}
+	for _, key := range []string{"dispatch_count", "dispatch_history", "error_history"} {
+		if v := got.Context[key]; v != "" {
+			t.Errorf("%s = %q, want it reset", key, v)
+		}
+	}
+}
+
+func TestLoom_ValidateProviderModel(t *testing.T) {
+	loom, tmpDir := testLoom(t)
+	defer os.RemoveAll(tmpDir)
+
+	ctx := context.Background()
+	if err := loom.providerRegistry.Upsert(&provider.ProviderConfig{ID: "mock-ok", Type: "mock", Model: "mock-model"}); err != nil {
+		t.Fatalf("Upsert() error = %v", err)
+	}
+	if err := loom.validateProviderModel(ctx, "mock-ok"); err != nil {
+		t.Errorf("validateProviderModel() error = %v", err)
+	}
+
+	if err := loom.providerRegistry.Upsert(&provider.ProviderConfig{ID: "mock-bad", Type: "mock", Model: "gpt-5"}); err != nil {
+		t.Fatalf("Upsert() error = %v", err)
+	}
+	err := loom.validateProviderModel(ctx, "mock-bad")
+	if err == nil || err.Error() != "model gpt-5 not available on provider mock-bad" {
+		t.Errorf("validateProviderModel() error = %v, want model not available", err)
+	}
+}
+
 func TestLoom_UpdateBead(t *testing.T) {
 	loom, tmpDir := testLoom(t)
 	defer os.RemoveAll(tmpDir)
@@ -1,6 +1,7 @@
 package metrics
 
 import (
+	"github.com/jordanhubbard/loom/internal/worker"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"sync"
@@ -29,6 +30,14 @@ type Metrics struct {
 	ProviderTokens   *prometheus.CounterVec
 	ProviderCost     *prometheus.CounterVec
 
+	// Dispatch and worker pool metrics
+	DispatchesTotal   prometheus.Counter
+	DispatchFailures  prometheus.Counter
+	DispatcherStatus  *prometheus.GaugeVec
+	WorkerPoolWorkers *prometheus.GaugeVec
+	WorkerPoolTasks   *prometheus.GaugeVec
+	CostUSD           *prometheus.CounterVec
+
 	// Workflow metrics
 	WorkflowsTotal     *prometheus.GaugeVec
 	WorkflowExecutions *prometheus.CounterVec
@@ -42,6 +51,7 @@ type Metrics struct {
 	EventsPublished     *prometheus.CounterVec
 	HTTPRequestsTotal   *prometheus.CounterVec
 	HTTPRequestDuration *prometheus.HistogramVec
+	HTTPRateLimited     prometheus.Counter
 }
 
 var (
@@ -167,6 +177,48 @@ func NewMetrics() *Metrics {
 				[]string{"provider_id", "model", "user_id"},
 			),
 
+			// Dispatch and worker pool metrics
+			DispatchesTotal: promauto.NewCounter(
+				prometheus.CounterOpts{
+					Name: "loom_dispatches_total",
+					Help: "Total number of beads dispatched to agents",
+				},
+			),
+			DispatchFailures: promauto.NewCounter(
+				prometheus.CounterOpts{
+					Name: "loom_dispatch_failures_total",
+					Help: "Total number of dispatched tasks whose execution failed",
+				},
+			),
+			DispatcherStatus: promauto.NewGaugeVec(
+				prometheus.GaugeOpts{
+					Name: "loom_dispatcher_status",
+					Help: "Dispatcher state (1 for the current state: active or parked)",
+				},
+				[]string{"state"},
+			),
+			WorkerPoolWorkers: promauto.NewGaugeVec(
+				prometheus.GaugeOpts{
+					Name: "loom_worker_pool_workers",
+					Help: "Workers in the pool by state, plus the pool's maximum",
+				},
+				[]string{"state"}, // total, idle, working, error, stopped, max
+			),
+			WorkerPoolTasks: promauto.NewGaugeVec(
+				prometheus.GaugeOpts{
+					Name: "loom_worker_pool_tasks",
+					Help: "Tasks running on or queued for the worker pool",
+				},
+				[]string{"state"}, // running, queued
+			),
+			CostUSD: promauto.NewCounterVec(
+				prometheus.CounterOpts{
+					Name: "loom_cost_usd_total",
+					Help: "Total cost in USD of requests logged by analytics",
+				},
+				[]string{"provider_id", "project_id"},
+			),
+
 			// Workflow metrics
 			WorkflowsTotal: promauto.NewGaugeVec(
 				prometheus.GaugeOpts{
@@ -239,6 +291,12 @@ func NewMetrics() *Metrics {
 				},
 				[]string{"method", "path"},
 			),
+			HTTPRateLimited: promauto.NewCounter(
+				prometheus.CounterOpts{
+					Name: "loom_http_rate_limited_total",
+					Help: "Total number of HTTP requests rejected by the rate limiter",
+				},
+			),
 		}
 	})
 
@@ -268,3 +326,43 @@ func (m *Metrics) RecordHTTPRequest(method, path, status string, duration float6
 	m.HTTPRequestsTotal.WithLabelValues(method, path, status).Inc()
 	m.HTTPRequestDuration.WithLabelValues(method, path).Observe(duration)
 }
+
+// RecordRateLimited records an HTTP request rejected by the rate limiter
+func (m *Metrics) RecordRateLimited() {
+	m.HTTPRateLimited.Inc()
+}
+
+// RecordDispatch records a bead handed to an agent
+func (m *Metrics) RecordDispatch() {
+	m.DispatchesTotal.Inc()
+}
+
+// RecordDispatchFailure records a dispatched task whose execution failed
+func (m *Metrics) RecordDispatchFailure() {
+	m.DispatchFailures.Inc()
+}
+
+// SetDispatcherStatus marks state as the dispatcher's current state
+func (m *Metrics) SetDispatcherStatus(state string) {
+	m.DispatcherStatus.Reset()
+	m.DispatcherStatus.WithLabelValues(state).Set(1)
+}
+
+// RecordWorkerPool records the worker pool's current size and load
+func (m *Metrics) RecordWorkerPool(stats worker.PoolStats) {
+	m.WorkerPoolWorkers.WithLabelValues("total").Set(float64(stats.TotalWorkers))
+	m.WorkerPoolWorkers.WithLabelValues("idle").Set(float64(stats.IdleWorkers))
+	m.WorkerPoolWorkers.WithLabelValues("working").Set(float64(stats.WorkingWorkers))
+	m.WorkerPoolWorkers.WithLabelValues("error").Set(float64(stats.ErrorWorkers))
+	m.WorkerPoolWorkers.WithLabelValues("stopped").Set(float64(stats.StoppedWorkers))
+	m.WorkerPoolWorkers.WithLabelValues("max").Set(float64(stats.MaxWorkers))
+	m.WorkerPoolTasks.WithLabelValues("running").Set(float64(stats.RunningTasks))
+	m.WorkerPoolTasks.WithLabelValues("queued").Set(float64(stats.QueuedTasks))
+}
+
+// RecordCost records the cost of a logged request
+func (m *Metrics) RecordCost(providerID, projectID string, costUSD float64) {
+	if costUSD > 0 {
+		m.CostUSD.WithLabelValues(providerID, projectID).Add(costUSD)
+	}
+}
@@ -0,0 +1,55 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/jordanhubbard/loom/internal/worker"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetrics_DispatchAndPool(t *testing.T) {
+	m := NewMetrics()
+
+	dispatches := testutil.ToFloat64(m.DispatchesTotal)
+	failures := testutil.ToFloat64(m.DispatchFailures)
+	m.RecordDispatch()
+	m.RecordDispatch()
+	m.RecordDispatchFailure()
+	if got := testutil.ToFloat64(m.DispatchesTotal) - dispatches; got != 2 {
+		t.Errorf("dispatches increased by %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(m.DispatchFailures) - failures; got != 1 {
+		t.Errorf("dispatch failures increased by %v, want 1", got)
+	}
+
+	m.SetDispatcherStatus("active")
+	m.SetDispatcherStatus("parked")
+	if got := testutil.ToFloat64(m.DispatcherStatus.WithLabelValues("parked")); got != 1 {
+		t.Errorf("parked = %v, want 1", got)
+	}
+	if got := testutil.CollectAndCount(m.DispatcherStatus); got != 1 {
+		t.Errorf("dispatcher status series = %d, want only the current state", got)
+	}
+
+	m.RecordWorkerPool(worker.PoolStats{TotalWorkers: 3, IdleWorkers: 1, WorkingWorkers: 2, MaxWorkers: 10, RunningTasks: 4, QueuedTasks: 2})
+	if got := testutil.ToFloat64(m.WorkerPoolWorkers.WithLabelValues("working")); got != 2 {
+		t.Errorf("working workers = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(m.WorkerPoolWorkers.WithLabelValues("max")); got != 10 {
+		t.Errorf("max workers = %v, want 10", got)
+	}
+	if got := testutil.ToFloat64(m.WorkerPoolTasks.WithLabelValues("queued")); got != 2 {
+		t.Errorf("queued tasks = %v, want 2", got)
+	}
+}
+
+func TestMetrics_RecordCost(t *testing.T) {
+	m := NewMetrics()
+	cost := m.CostUSD.WithLabelValues("p1", "proj")
+	before := testutil.ToFloat64(cost)
+	m.RecordCost("p1", "proj", 0.25)
+	m.RecordCost("p1", "proj", 0)
+	if got := testutil.ToFloat64(cost) - before; got != 0.25 {
+		t.Errorf("cost increased by %v, want 0.25", got)
+	}
+}
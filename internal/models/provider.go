@@ -40,10 +40,25 @@ type Provider struct {
 	SupportsStreaming bool     `json:"supports_streaming"` // Supports streaming responses
 	Tags              []string `json:"tags"`               // Custom tags for filtering
 
+	// Connection settings some provider types need
+	DeploymentName string `json:"deployment_name,omitempty"` // azure-openai: deployment that serves requests
+	APIVersion     string `json:"api_version,omitempty"`     // azure-openai: api-version query parameter
+	Region         string `json:"region,omitempty"`          // bedrock: AWS region, e.g. us-east-1
+
+	// Request limits; zero uses the registry default
+	TimeoutSeconds    int `json:"timeout_seconds,omitempty"`     // How long a non-streaming request may take
+	MaxRetries        int `json:"max_retries,omitempty"`         // How often a failed request is retried
+	RequestsPerMinute int `json:"requests_per_minute,omitempty"` // Request rate limit; 0 is unlimited
+
+	// mock: offline provider settings
+	MockResponse  string `json:"mock_response,omitempty"`   // Canned reply; empty echoes the last message
+	MockLatencyMs int    `json:"mock_latency_ms,omitempty"` // Simulated delay per response
+
 	// Dynamic scoring metadata (computed from Registry, not persisted)
 	ModelParamsB    float64 `json:"model_params_b,omitempty"`    // Model parameters in billions (from model name)
 	CapabilityScore float64 `json:"capability_score,omitempty"`  // Dynamic composite score from Scorer
 	AvgLatencyMs    float64 `json:"avg_latency_ms,omitempty"`    // Rolling average request latency
+	CircuitState    string  `json:"circuit_state,omitempty"`     // closed, open or half-open
 
 	// Runtime metrics for dynamic scoring
 	Metrics ProviderMetrics `json:"metrics"`
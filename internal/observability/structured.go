@@ -2,12 +2,85 @@ package observability
 
 import (
 	"encoding/json"
+	"fmt"
+	"io"
 	"log"
+	"os"
+	"strings"
+	"sync/atomic"
 	"time"
 )
 
+// Level is the severity of a log event. Events below the configured level
+// are dropped.
+type Level int32
+
+const (
+	LevelDebug Level = iota - 1
+	LevelInfo        // The zero value, so info is the default
+	LevelWarn
+	LevelError
+)
+
+var minLevel atomic.Int32
+
+// out writes one JSON object per line, without the standard log prefix.
+var out = log.New(os.Stderr, "", 0)
+
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	}
+	return fmt.Sprintf("level(%d)", int32(l))
+}
+
+// ParseLevel parses debug, info, warn (or warning) and error.
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return LevelDebug, nil
+	case "info", "":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	}
+	return LevelInfo, fmt.Errorf("unknown log level %q (want debug, info, warn or error)", s)
+}
+
+// SetLevel sets the lowest level that is logged.
+func SetLevel(level Level) {
+	minLevel.Store(int32(level))
+}
+
+// SetOutput sets where events are written (stderr by default).
+func SetOutput(w io.Writer) {
+	out.SetOutput(w)
+}
+
+// Enabled reports whether events at level are logged.
+func Enabled(level Level) bool {
+	return int32(level) >= minLevel.Load()
+}
+
+func Debug(event string, fields map[string]interface{}) {
+	logEvent(LevelDebug, event, fields)
+}
+
 func Info(event string, fields map[string]interface{}) {
-	logEvent("info", event, fields)
+	logEvent(LevelInfo, event, fields)
+}
+
+func Warn(event string, fields map[string]interface{}) {
+	logEvent(LevelWarn, event, fields)
 }
 
 func Error(event string, fields map[string]interface{}, err error) {
@@ -15,13 +88,68 @@ func Error(event string, fields map[string]interface{}, err error) {
 	if err != nil {
 		payload["error"] = err.Error()
 	}
-	logEvent("error", event, payload)
+	logEvent(LevelError, event, payload)
+}
+
+// Logger logs events tagged with a component and a fixed set of fields,
+// such as bead_id, agent_id and project_id. A nil Logger logs untagged
+// events.
+type Logger struct {
+	fields map[string]interface{}
+}
+
+// NewLogger returns a Logger whose events carry the component field.
+func NewLogger(component string) *Logger {
+	return &Logger{fields: map[string]interface{}{"component": component}}
+}
+
+// With returns a Logger that adds fields to every event. Empty string values
+// are left out so callers can pass IDs that may not be known yet.
+func (l *Logger) With(fields map[string]interface{}) *Logger {
+	return &Logger{fields: l.merge(fields)}
+}
+
+func (l *Logger) Debug(event string, fields map[string]interface{}) {
+	if Enabled(LevelDebug) {
+		logEvent(LevelDebug, event, l.merge(fields))
+	}
+}
+
+func (l *Logger) Info(event string, fields map[string]interface{}) {
+	logEvent(LevelInfo, event, l.merge(fields))
+}
+
+func (l *Logger) Warn(event string, fields map[string]interface{}) {
+	logEvent(LevelWarn, event, l.merge(fields))
+}
+
+func (l *Logger) Error(event string, fields map[string]interface{}, err error) {
+	Error(event, l.merge(fields), err)
+}
+
+func (l *Logger) merge(fields map[string]interface{}) map[string]interface{} {
+	var merged map[string]interface{}
+	if l != nil {
+		merged = cloneFields(l.fields)
+	} else {
+		merged = make(map[string]interface{})
+	}
+	for k, v := range fields {
+		if s, ok := v.(string); ok && s == "" {
+			continue
+		}
+		merged[k] = v
+	}
+	return merged
 }
 
-func logEvent(level, event string, fields map[string]interface{}) {
+func logEvent(level Level, event string, fields map[string]interface{}) {
+	if !Enabled(level) {
+		return
+	}
 	payload := cloneFields(fields)
 	payload["ts"] = time.Now().UTC().Format(time.RFC3339Nano)
-	payload["level"] = level
+	payload["level"] = level.String()
 	payload["event"] = event
 	raw, err := json.Marshal(payload)
 	if err != nil {
@@ -35,10 +163,10 @@ func logEvent(level, event string, fields map[string]interface{}) {
 			fallback["event_payload"] = fields
 		}
 		fallbackRaw, _ := json.Marshal(fallback)
-		log.Print(string(fallbackRaw))
+		out.Print(string(fallbackRaw))
 		return
 	}
-	log.Print(string(raw))
+	out.Print(string(raw))
 }
 
 func cloneFields(fields map[string]interface{}) map[string]interface{} {
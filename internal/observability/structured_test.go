@@ -0,0 +1,110 @@
+package observability
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func captureEvents(t *testing.T, level Level) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	SetOutput(&buf)
+	SetLevel(level)
+	t.Cleanup(func() {
+		SetOutput(os.Stderr)
+		SetLevel(LevelInfo)
+	})
+	return &buf
+}
+
+func decodeEvents(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var events []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var event map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("line %q is not JSON: %v", line, err)
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestLogger_LevelsAndFields(t *testing.T) {
+	buf := captureEvents(t, LevelInfo)
+	logger := NewLogger("dispatcher").With(map[string]interface{}{"project_id": "p1", "agent_id": ""})
+
+	logger.Debug("dispatch.start", nil)
+	logger.Info("dispatch.assign", map[string]interface{}{"bead_id": "b1", "agent_id": "a1"})
+	logger.Error("dispatch.execute", map[string]interface{}{"bead_id": "b1"}, errors.New("boom"))
+
+	events := decodeEvents(t, buf)
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want the debug event dropped: %v", len(events), events)
+	}
+	assign := events[0]
+	for key, want := range map[string]string{"component": "dispatcher", "project_id": "p1", "bead_id": "b1", "agent_id": "a1", "level": "info", "event": "dispatch.assign"} {
+		if assign[key] != want {
+			t.Errorf("%s = %v, want %q", key, assign[key], want)
+		}
+	}
+	if events[1]["level"] != "error" || events[1]["error"] != "boom" {
+		t.Errorf("error event = %v", events[1])
+	}
+
+	SetLevel(LevelDebug)
+	logger.Debug("dispatch.start", nil)
+	Info("legacy.event", map[string]interface{}{"k": "v"})
+	events = decodeEvents(t, buf)
+	if len(events) != 4 || events[2]["level"] != "debug" || events[3]["event"] != "legacy.event" {
+		t.Errorf("events after SetLevel(debug) = %v", events)
+	}
+	if _, ok := events[2]["agent_id"]; ok {
+		t.Error("empty agent_id should be left out")
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	for in, want := range map[string]Level{"": LevelInfo, "debug": LevelDebug, "WARN": LevelWarn, "warning": LevelWarn, "error": LevelError} {
+		got, err := ParseLevel(in)
+		if err != nil || got != want {
+			t.Errorf("ParseLevel(%q) = %v, %v, want %v", in, got, err, want)
+		}
+	}
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Error("ParseLevel(verbose) should fail")
+	}
+}
+
+func TestTraceID(t *testing.T) {
+	ctx := context.Background()
+	if TraceID(ctx) != "" {
+		t.Fatal("a bare context should have no trace ID")
+	}
+	ctx, traceID := EnsureTraceID(ctx)
+	if traceID == "" || TraceID(ctx) != traceID {
+		t.Fatalf("EnsureTraceID() = %q, context has %q", traceID, TraceID(ctx))
+	}
+	if _, again := EnsureTraceID(ctx); again != traceID {
+		t.Errorf("EnsureTraceID() replaced %q with %q", traceID, again)
+	}
+
+	buf := captureEvents(t, LevelInfo)
+	NewLogger("worker").WithTrace(ctx).Info("task.start", nil)
+	NewLogger("worker").WithTrace(context.Background()).Info("task.start", nil)
+	events := decodeEvents(t, buf)
+	if events[0]["trace_id"] != traceID {
+		t.Errorf("trace_id = %v, want %q", events[0]["trace_id"], traceID)
+	}
+	if _, ok := events[1]["trace_id"]; ok {
+		t.Error("an untraced event should have no trace_id")
+	}
+}
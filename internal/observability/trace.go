@@ -0,0 +1,46 @@
+package observability
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// TraceHeader carries a trace ID on HTTP requests and responses.
+const TraceHeader = "X-Trace-ID"
+
+type traceKey struct{}
+
+// NewTraceID returns a new random trace ID.
+func NewTraceID() string {
+	return uuid.New().String()
+}
+
+// WithTraceID returns a context carrying the trace ID.
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceKey{}, traceID)
+}
+
+// TraceID returns the context's trace ID, or "" when it has none.
+func TraceID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	traceID, _ := ctx.Value(traceKey{}).(string)
+	return traceID
+}
+
+// EnsureTraceID returns ctx and its trace ID, first attaching a new one when
+// ctx has none.
+func EnsureTraceID(ctx context.Context) (context.Context, string) {
+	if traceID := TraceID(ctx); traceID != "" {
+		return ctx, traceID
+	}
+	traceID := NewTraceID()
+	return WithTraceID(ctx, traceID), traceID
+}
+
+// WithTrace returns a Logger that tags events with ctx's trace ID, if any.
+func (l *Logger) WithTrace(ctx context.Context) *Logger {
+	return l.With(map[string]interface{}{"trace_id": TraceID(ctx)})
+}
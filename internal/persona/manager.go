@@ -36,6 +36,7 @@ type SkillFrontmatter struct {
 	License       string                 `yaml:"license"`
 	Compatibility string                 `yaml:"compatibility"`
 	Metadata      map[string]interface{} `yaml:"metadata"`
+	OutputFormat  string                 `yaml:"output_format"`
 }
 
 // LoadPersona loads a persona from a directory (SKILL.md format)
@@ -68,6 +69,7 @@ func (m *Manager) LoadPersona(name string) (*models.Persona, error) {
 		License:       frontmatter.License,
 		Compatibility: frontmatter.Compatibility,
 		Metadata:      frontmatter.Metadata,
+		OutputFormat:  frontmatter.OutputFormat,
 		PersonaFile:   skillFile,
 		CreatedAt:     time.Now(),
 		UpdatedAt:     time.Now(),
@@ -174,6 +174,30 @@ Body content here.
 	}
 }
 
+func TestLoadPersona_OutputFormat(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := `---
+name: reviewer
+description: Reviews code
+output_format: |
+  Reply with a unified diff for {{.Bead.ID}}.
+---
+
+Body content here.
+`
+	createTestSkillMd(t, tmpDir, "reviewer", content)
+
+	m := NewManager(tmpDir)
+	persona, err := m.LoadPersona("reviewer")
+	if err != nil {
+		t.Fatalf("LoadPersona() error = %v", err)
+	}
+
+	if want := "Reply with a unified diff for {{.Bead.ID}}."; persona.OutputFormat != want {
+		t.Errorf("OutputFormat = %q, want %q", persona.OutputFormat, want)
+	}
+}
+
 func TestInvalidateCache(t *testing.T) {
 	tmpDir := t.TempDir()
 	createTestSkillMd(t, tmpDir, "cache-test", validSkillMd)
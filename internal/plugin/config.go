@@ -0,0 +1,65 @@
+package plugin
+
+import (
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/jordanhubbard/loom/pkg/plugin"
+)
+
+// ConfigValidationError lists every field of a manifest's config that does
+// not match the plugin's declared ConfigSchema.
+type ConfigValidationError struct {
+	ProviderType string
+	Errors       []plugin.ConfigError
+}
+
+func (e *ConfigValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		msgs[i] = fe.Error()
+	}
+	return "invalid config for plugin " + e.ProviderType + ": " + strings.Join(msgs, "; ")
+}
+
+// envRefPattern matches a config value that is entirely a "${VAR}"
+// reference to an environment variable.
+var envRefPattern = regexp.MustCompile(`^\$\{([A-Za-z_][A-Za-z0-9_]*)\}$`)
+
+// pluginConfig returns the config to initialize a plugin with: the
+// manifest's config with "${VAR}" values read from the environment and schema
+// defaults filled in. A reference to an unset or empty variable counts as
+// missing, so secrets such as API keys need not be written into the
+// manifest. Field paths in validation errors are relative to the manifest,
+// e.g. "config.api_key".
+func pluginConfig(manifest *PluginManifest) (map[string]interface{}, error) {
+	config := make(map[string]interface{}, len(manifest.Config))
+	for k, v := range manifest.Config {
+		if s, ok := v.(string); ok {
+			if m := envRefPattern.FindStringSubmatch(s); m != nil {
+				if v = os.Getenv(m[1]); v == "" {
+					continue
+				}
+			}
+		}
+		config[k] = v
+	}
+	if manifest.Metadata == nil {
+		return config, nil
+	}
+
+	schema := manifest.Metadata.ConfigSchema
+	if errs := plugin.CheckConfig(config, schema); len(errs) > 0 {
+		for i := range errs {
+			errs[i].Path = "config." + errs[i].Path
+		}
+		return nil, &ConfigValidationError{ProviderType: manifest.Metadata.ProviderType, Errors: errs}
+	}
+	for _, field := range schema {
+		if _, ok := config[field.Name]; !ok && field.Default != nil {
+			config[field.Name] = field.Default
+		}
+	}
+	return config, nil
+}
@@ -0,0 +1,155 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jordanhubbard/loom/pkg/plugin"
+)
+
+func schemaManifest(config map[string]interface{}) *PluginManifest {
+	return &PluginManifest{
+		Type: "http",
+		Metadata: &plugin.Metadata{
+			Name:         "Schema Plugin",
+			ProviderType: "schema-provider",
+			ConfigSchema: []plugin.ConfigField{
+				{Name: "api_key", Type: "string", Required: true},
+				{Name: "timeout", Type: "int", Default: 30},
+				{Name: "verbose", Type: "bool"},
+			},
+		},
+		Config: config,
+	}
+}
+
+func TestPluginConfig(t *testing.T) {
+	config, err := pluginConfig(schemaManifest(map[string]interface{}{"api_key": "k"}))
+	if err != nil {
+		t.Fatalf("pluginConfig: %v", err)
+	}
+	if config["api_key"] != "k" || config["timeout"] != 30 {
+		t.Errorf("expected config with defaults applied, got %v", config)
+	}
+	if _, ok := config["verbose"]; ok {
+		t.Error("fields without defaults should stay unset")
+	}
+
+	_, err = pluginConfig(schemaManifest(map[string]interface{}{"timeout": 1.5, "verbose": "yes"}))
+	var invalid *ConfigValidationError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("expected ConfigValidationError, got %v", err)
+	}
+	paths := make([]string, len(invalid.Errors))
+	for i, fe := range invalid.Errors {
+		paths[i] = fe.Path
+	}
+	if strings.Join(paths, ",") != "config.api_key,config.timeout,config.verbose" {
+		t.Errorf("unexpected error paths %v", paths)
+	}
+	if !strings.Contains(err.Error(), "config.api_key: required field is missing") {
+		t.Errorf("error should name the missing field, got %q", err.Error())
+	}
+}
+
+func TestLoadPlugin_InvalidConfigNotContacted(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	manifest := schemaManifest(nil)
+	manifest.Endpoint = srv.URL
+	err := NewLoader(t.TempDir()).LoadPlugin(context.Background(), manifest)
+	var invalid *ConfigValidationError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("expected ConfigValidationError, got %v", err)
+	}
+	if n := hits.Load(); n != 0 {
+		t.Errorf("plugin should not be contacted, got %d requests", n)
+	}
+}
+
+func TestPluginConfig_EnvReference(t *testing.T) {
+	t.Setenv("SCHEMA_TEST_KEY", "from-env")
+	config, err := pluginConfig(schemaManifest(map[string]interface{}{"api_key": "${SCHEMA_TEST_KEY}"}))
+	if err != nil {
+		t.Fatalf("pluginConfig: %v", err)
+	}
+	if config["api_key"] != "from-env" {
+		t.Errorf("api_key = %v, want the environment value", config["api_key"])
+	}
+
+	// Only whole-value references are read; other strings are kept as is
+	config, err = pluginConfig(schemaManifest(map[string]interface{}{"api_key": "pa$$${SCHEMA_TEST_KEY}"}))
+	if err != nil || config["api_key"] != "pa$$${SCHEMA_TEST_KEY}" {
+		t.Errorf("pluginConfig() = %v, %v, want the literal value", config, err)
+	}
+
+	// An unset variable counts as missing
+	_, err = pluginConfig(schemaManifest(map[string]interface{}{"api_key": "${SCHEMA_TEST_UNSET}"}))
+	if err == nil || !strings.Contains(err.Error(), "config.api_key: required field is missing") {
+		t.Errorf("pluginConfig() error = %v, want api_key missing", err)
+	}
+}
+
+// TestExamplePluginManifests loads every example manifest against a server
+// that answers as the example plugin would.
+func TestExamplePluginManifests(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "sk-test")
+
+	var paths []string
+	err := filepath.WalkDir("../../examples/plugins", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && (d.Name() == "plugin.yaml" || d.Name() == "plugin.yml" || d.Name() == "plugin.json") {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Fatal("no example manifests found")
+	}
+
+	for _, path := range paths {
+		t.Run(path, func(t *testing.T) {
+			manifest, err := readManifest(path)
+			if err != nil {
+				t.Fatalf("readManifest: %v", err)
+			}
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/metadata":
+					_ = json.NewEncoder(w).Encode(manifest.Metadata)
+				case "/health":
+					_ = json.NewEncoder(w).Encode(plugin.HealthStatus{Healthy: true})
+				default:
+					_, _ = w.Write([]byte(`{}`))
+				}
+			}))
+			defer srv.Close()
+			manifest.Endpoint = srv.URL
+
+			loader := NewLoader(t.TempDir())
+			defer loader.Stop()
+			if err := loader.LoadPlugin(context.Background(), manifest); err != nil {
+				t.Fatalf("LoadPlugin: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package plugin
+
+import (
+	"context"
+	"log"
+
+	"github.com/jordanhubbard/loom/internal/analytics"
+	"github.com/jordanhubbard/loom/pkg/plugin"
+)
+
+// EstimateCost returns the cost in USD of a completion with the given usage.
+// Prompt and completion tokens are priced separately when the model has
+// per-token prices, falling back to CostPerMToken for all tokens. A cost the
+// plugin reported itself takes precedence. Models without pricing cost zero.
+func EstimateCost(model *plugin.ModelInfo, usage *plugin.UsageInfo) float64 {
+	if usage == nil {
+		return 0
+	}
+	if usage.CostUSD != nil {
+		return *usage.CostUSD
+	}
+	if model == nil {
+		return 0
+	}
+	if model.PromptCostPerToken != nil || model.CompletionCostPerToken != nil {
+		return analytics.CalculateCost(perMillion(model.PromptCostPerToken), int64(usage.PromptTokens)) +
+			analytics.CalculateCost(perMillion(model.CompletionCostPerToken), int64(usage.CompletionTokens))
+	}
+	if model.CostPerMToken != nil {
+		return analytics.CalculateCost(*model.CostPerMToken, int64(usage.TotalTokens))
+	}
+	return 0
+}
+
+// perMillion converts an optional per-token price to a per-million price.
+func perMillion(perToken *float64) float64 {
+	if perToken == nil {
+		return 0
+	}
+	return *perToken * 1000000
+}
+
+// modelInfo returns the plugin's description of modelID, fetching and
+// caching the plugin's model list on first use. It returns nil when the
+// model is unknown or the list cannot be fetched.
+func (loaded *LoadedPlugin) modelInfo(ctx context.Context, modelID string) *plugin.ModelInfo {
+	loaded.modelsMu.Lock()
+	defer loaded.modelsMu.Unlock()
+
+	if loaded.models == nil {
+		models, err := loaded.Client.GetModels(ctx)
+		if err != nil {
+			log.Printf("[Plugin] Failed to load model pricing: %v", err)
+			return nil
+		}
+		loaded.models = models
+	}
+	for i := range loaded.models {
+		if loaded.models[i].ID == modelID {
+			return &loaded.models[i]
+		}
+	}
+	return nil
+}
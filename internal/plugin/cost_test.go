@@ -0,0 +1,83 @@
+package plugin
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/jordanhubbard/loom/pkg/plugin"
+)
+
+func price(v float64) *float64 { return &v }
+
+func TestEstimateCost(t *testing.T) {
+	usage := &plugin.UsageInfo{PromptTokens: 1000, CompletionTokens: 500, TotalTokens: 1500}
+	tests := []struct {
+		name  string
+		model *plugin.ModelInfo
+		usage *plugin.UsageInfo
+		want  float64
+	}{
+		{"per-direction pricing", &plugin.ModelInfo{PromptCostPerToken: price(0.000003), CompletionCostPerToken: price(0.000015)}, usage, 0.0105},
+		{"prompt pricing only", &plugin.ModelInfo{PromptCostPerToken: price(0.000003)}, usage, 0.003},
+		{"per-million fallback", &plugin.ModelInfo{CostPerMToken: price(2)}, usage, 0.003},
+		{"no pricing", &plugin.ModelInfo{}, usage, 0},
+		{"unknown model", nil, usage, 0},
+		{"no usage", &plugin.ModelInfo{CostPerMToken: price(2)}, nil, 0},
+		{"reported cost wins", &plugin.ModelInfo{CostPerMToken: price(2)}, &plugin.UsageInfo{TotalTokens: 1500, CostUSD: price(0.5)}, 0.5},
+	}
+	for _, tt := range tests {
+		if got := EstimateCost(tt.model, tt.usage); math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// pricedPlugin reports usage for a model priced per million tokens.
+type pricedPlugin struct {
+	stubPlugin
+	cost       *float64
+	modelCalls int
+}
+
+func (p *pricedPlugin) CreateChatCompletion(ctx context.Context, req *plugin.ChatCompletionRequest) (*plugin.ChatCompletionResponse, error) {
+	return &plugin.ChatCompletionResponse{
+		Model: req.Model,
+		Usage: &plugin.UsageInfo{PromptTokens: 1000, CompletionTokens: 500, TotalTokens: 1500, CostUSD: p.cost},
+	}, nil
+}
+
+func (p *pricedPlugin) GetModels(ctx context.Context) ([]plugin.ModelInfo, error) {
+	p.modelCalls++
+	return []plugin.ModelInfo{{ID: "priced-model", CostPerMToken: price(2)}}, nil
+}
+
+func TestLoader_CreateChatCompletionEstimatesCost(t *testing.T) {
+	client := &pricedPlugin{}
+	l := NewLoader(t.TempDir())
+	l.plugins["priced"] = &LoadedPlugin{Manifest: &PluginManifest{Metadata: &plugin.Metadata{ProviderType: "priced"}}, Client: client}
+	req := &plugin.ChatCompletionRequest{Model: "priced-model"}
+
+	for i := 0; i < 2; i++ {
+		resp, err := l.CreateChatCompletion(context.Background(), "priced", req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.Usage.CostUSD == nil || math.Abs(*resp.Usage.CostUSD-0.003) > 1e-12 {
+			t.Fatalf("cost_usd = %v, want 0.003", resp.Usage.CostUSD)
+		}
+	}
+	if client.modelCalls != 1 {
+		t.Errorf("GetModels called %d times, want the prices cached after 1", client.modelCalls)
+	}
+
+	// A cost the plugin reports is kept
+	client.cost = price(0.5)
+	resp, err := l.CreateChatCompletion(context.Background(), "priced", req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *resp.Usage.CostUSD != 0.5 {
+		t.Errorf("cost_usd = %v, want the reported 0.5", *resp.Usage.CostUSD)
+	}
+}
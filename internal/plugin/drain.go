@@ -0,0 +1,113 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"log"
+)
+
+// acquire registers an in-flight request against the plugin. It fails once
+// the plugin has started unloading.
+func (p *LoadedPlugin) acquire() bool {
+	p.reqMu.Lock()
+	defer p.reqMu.Unlock()
+	if p.draining {
+		return false
+	}
+	p.active++
+	return true
+}
+
+// release marks an in-flight request as finished.
+func (p *LoadedPlugin) release() {
+	p.reqMu.Lock()
+	defer p.reqMu.Unlock()
+	p.active--
+	if p.active == 0 && p.idle != nil {
+		close(p.idle)
+		p.idle = nil
+	}
+}
+
+// drain stops the plugin from accepting requests and, when wait is set,
+// blocks until in-flight requests finish or ctx is done. It returns the
+// number of requests still in flight.
+func (p *LoadedPlugin) drain(ctx context.Context, wait bool) int {
+	p.reqMu.Lock()
+	p.draining = true
+	if p.active == 0 || !wait {
+		active := p.active
+		p.reqMu.Unlock()
+		return active
+	}
+	if p.idle == nil {
+		p.idle = make(chan struct{})
+	}
+	idle := p.idle
+	p.reqMu.Unlock()
+
+	select {
+	case <-idle:
+	case <-ctx.Done():
+	}
+
+	p.reqMu.Lock()
+	defer p.reqMu.Unlock()
+	return p.active
+}
+
+// acquirePlugin looks up a loaded plugin and registers a request against it.
+// The caller must release the plugin when the request completes.
+func (l *Loader) acquirePlugin(providerType string) (*LoadedPlugin, error) {
+	loaded, err := l.GetPlugin(providerType)
+	if err != nil {
+		return nil, err
+	}
+	if !loaded.acquire() {
+		return nil, fmt.Errorf("plugin %s is unloading", providerType)
+	}
+	return loaded, nil
+}
+
+// UnloadPlugin stops routing requests to a plugin, waits for in-flight
+// requests to finish or ctx to be done, then cleans the plugin up and
+// removes it.
+func (l *Loader) UnloadPlugin(ctx context.Context, providerType string) error {
+	return l.unload(ctx, providerType, true)
+}
+
+// ForceUnload is UnloadPlugin without waiting for in-flight requests.
+func (l *Loader) ForceUnload(ctx context.Context, providerType string) error {
+	return l.unload(ctx, providerType, false)
+}
+
+func (l *Loader) unload(ctx context.Context, providerType string, wait bool) error {
+	loaded, err := l.GetPlugin(providerType)
+	if err != nil {
+		return err
+	}
+
+	if active := loaded.drain(ctx, wait); active > 0 {
+		log.Printf("[Plugin] Unloading %s with %d requests still in flight", providerType, active)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.plugins[providerType] != loaded {
+		return nil // Already unloaded, e.g. by ForceUnload while draining
+	}
+
+	// Cleanup plugin; ctx may have expired while draining
+	if err := loaded.Client.Cleanup(context.WithoutCancel(ctx)); err != nil {
+		loaded.reqMu.Lock()
+		loaded.draining = false // Still loaded, so keep serving
+		loaded.reqMu.Unlock()
+		return fmt.Errorf("plugin cleanup failed: %w", err)
+	}
+
+	// Remove from loaded plugins
+	stopMonitor(loaded)
+	delete(l.plugins, providerType)
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package plugin
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jordanhubbard/loom/pkg/plugin"
+)
+
+// stubPlugin is a healthy plugin with no models that returns empty
+// completions.
+type stubPlugin struct{}
+
+func (p *stubPlugin) GetMetadata() *plugin.Metadata { return &plugin.Metadata{ProviderType: "stub"} }
+func (p *stubPlugin) Initialize(ctx context.Context, config map[string]interface{}) error {
+	return nil
+}
+func (p *stubPlugin) HealthCheck(ctx context.Context) (*plugin.HealthStatus, error) {
+	return &plugin.HealthStatus{Healthy: true}, nil
+}
+func (p *stubPlugin) CreateChatCompletion(ctx context.Context, req *plugin.ChatCompletionRequest) (*plugin.ChatCompletionResponse, error) {
+	return &plugin.ChatCompletionResponse{Model: req.Model}, nil
+}
+func (p *stubPlugin) GetModels(ctx context.Context) ([]plugin.ModelInfo, error) { return nil, nil }
+func (p *stubPlugin) Cleanup(ctx context.Context) error                         { return nil }
+
+// slowPlugin blocks completions until release is closed.
+type slowPlugin struct {
+	stubPlugin
+	started  chan struct{}
+	release  chan struct{}
+	cleanups atomic.Int32
+}
+
+func (p *slowPlugin) CreateChatCompletion(ctx context.Context, req *plugin.ChatCompletionRequest) (*plugin.ChatCompletionResponse, error) {
+	p.started <- struct{}{}
+	<-p.release
+	return &plugin.ChatCompletionResponse{}, nil
+}
+
+func (p *slowPlugin) Cleanup(ctx context.Context) error {
+	p.cleanups.Add(1)
+	return nil
+}
+
+func newSlowPluginLoader(t *testing.T) (*Loader, *slowPlugin) {
+	t.Helper()
+	client := &slowPlugin{started: make(chan struct{}, 1), release: make(chan struct{})}
+	l := NewLoader(t.TempDir())
+	l.plugins["slow"] = &LoadedPlugin{Manifest: &PluginManifest{Metadata: &plugin.Metadata{ProviderType: "slow"}}, Client: client}
+	return l, client
+}
+
+func TestUnloadPlugin_WaitsForInFlightRequests(t *testing.T) {
+	l, client := newSlowPluginLoader(t)
+	ctx := context.Background()
+
+	requestDone := make(chan error, 1)
+	go func() {
+		_, err := l.CreateChatCompletion(ctx, "slow", &plugin.ChatCompletionRequest{})
+		requestDone <- err
+	}()
+	<-client.started
+
+	unloaded := make(chan error, 1)
+	go func() { unloaded <- l.UnloadPlugin(ctx, "slow") }()
+
+	select {
+	case err := <-unloaded:
+		t.Fatalf("UnloadPlugin returned while a request was in flight: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	if _, err := l.CreateChatCompletion(ctx, "slow", &plugin.ChatCompletionRequest{}); err == nil {
+		t.Error("new requests should be rejected while draining")
+	}
+	if client.cleanups.Load() != 0 {
+		t.Error("Cleanup ran before the in-flight request finished")
+	}
+
+	close(client.release)
+	if err := <-requestDone; err != nil {
+		t.Errorf("in-flight request failed: %v", err)
+	}
+	select {
+	case err := <-unloaded:
+		if err != nil {
+			t.Fatalf("UnloadPlugin: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("UnloadPlugin did not return after the request finished")
+	}
+	if _, err := l.GetPlugin("slow"); err == nil {
+		t.Error("plugin should be removed")
+	}
+	if n := client.cleanups.Load(); n != 1 {
+		t.Errorf("expected one Cleanup, got %d", n)
+	}
+}
+
+func TestUnloadPlugin_DeadlineAndForce(t *testing.T) {
+	l, client := newSlowPluginLoader(t)
+	defer close(client.release)
+
+	go func() { _, _ = l.CreateChatCompletion(context.Background(), "slow", &plugin.ChatCompletionRequest{}) }()
+	<-client.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := l.UnloadPlugin(ctx, "slow"); err != nil {
+		t.Fatalf("UnloadPlugin after deadline: %v", err)
+	}
+	if _, err := l.GetPlugin("slow"); err == nil {
+		t.Error("plugin should be removed once the deadline passes")
+	}
+
+	l, client2 := newSlowPluginLoader(t)
+	defer close(client2.release)
+	go func() { _, _ = l.CreateChatCompletion(context.Background(), "slow", &plugin.ChatCompletionRequest{}) }()
+	<-client2.started
+
+	start := time.Now()
+	if err := l.ForceUnload(context.Background(), "slow"); err != nil {
+		t.Fatalf("ForceUnload: %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Error("ForceUnload should not wait for in-flight requests")
+	}
+	if n := client2.cleanups.Load(); n != 1 {
+		t.Errorf("expected one Cleanup, got %d", n)
+	}
+}
@@ -0,0 +1,351 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/jordanhubbard/loom/internal/plugin/pluginpb"
+	"github.com/jordanhubbard/loom/pkg/plugin"
+)
+
+// GRPCPluginClient implements the plugin.Plugin interface over gRPC.
+// Plugins serve pluginpb.PluginService plus the standard grpc.health.v1
+// health service on the manifest endpoint.
+type GRPCPluginClient struct {
+	endpoint string
+	conn     *grpc.ClientConn
+	client   pluginpb.PluginServiceClient
+	health   healthpb.HealthClient
+	timeout  time.Duration
+	metadata *plugin.Metadata
+}
+
+// NewGRPCPluginClient creates a new gRPC plugin client. The connection is
+// established lazily, so an unreachable endpoint surfaces as an error on the
+// first call (Initialize or HealthCheck) rather than here.
+func NewGRPCPluginClient(endpoint string) (*GRPCPluginClient, error) {
+	if endpoint == "" {
+		return nil, fmt.Errorf("endpoint is required")
+	}
+
+	target := strings.TrimPrefix(endpoint, "grpc://")
+	conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial %s: %w", endpoint, err)
+	}
+
+	return &GRPCPluginClient{
+		endpoint: endpoint,
+		conn:     conn,
+		client:   pluginpb.NewPluginServiceClient(conn),
+		health:   healthpb.NewHealthClient(conn),
+		timeout:  30 * time.Second,
+	}, nil
+}
+
+// GetMetadata returns plugin metadata.
+func (c *GRPCPluginClient) GetMetadata() *plugin.Metadata {
+	if c.metadata != nil {
+		return c.metadata
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	resp, err := c.client.GetMetadata(ctx, &pluginpb.GetMetadataRequest{})
+	if err != nil {
+		return nil
+	}
+
+	c.metadata = metadataFromProto(resp)
+	return c.metadata
+}
+
+// Initialize initializes the plugin with configuration.
+func (c *GRPCPluginClient) Initialize(ctx context.Context, config map[string]interface{}) error {
+	cfg, err := structpb.NewStruct(config)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	if _, err := c.client.Initialize(ctx, &pluginpb.InitializeRequest{Config: cfg}); err != nil {
+		return fmt.Errorf("initialize request failed: %w", pluginErrorFromStatus(err))
+	}
+
+	// Cache metadata after initialization
+	c.GetMetadata()
+
+	return nil
+}
+
+// HealthCheck performs a health check using the standard gRPC health protocol.
+func (c *GRPCPluginClient) HealthCheck(ctx context.Context) (*plugin.HealthStatus, error) {
+	start := time.Now()
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	resp, err := c.health.Check(ctx, &healthpb.HealthCheckRequest{})
+	latency := time.Since(start).Milliseconds()
+	if err != nil {
+		return plugin.NewUnhealthyStatus(err.Error(), latency), nil
+	}
+
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return plugin.NewUnhealthyStatus(fmt.Sprintf("plugin status %s", resp.GetStatus()), latency), nil
+	}
+
+	return plugin.NewHealthyStatus(latency), nil
+}
+
+// CreateChatCompletion sends a chat completion request.
+func (c *GRPCPluginClient) CreateChatCompletion(ctx context.Context, req *plugin.ChatCompletionRequest) (*plugin.ChatCompletionResponse, error) {
+	pbReq, err := chatRequestToProto(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	resp, err := c.client.CreateChatCompletion(ctx, pbReq)
+	if err != nil {
+		return nil, fmt.Errorf("completion request failed: %w", pluginErrorFromStatus(err))
+	}
+
+	return chatResponseFromProto(resp), nil
+}
+
+// GetModels retrieves the list of available models.
+func (c *GRPCPluginClient) GetModels(ctx context.Context) ([]plugin.ModelInfo, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	resp, err := c.client.GetModels(ctx, &pluginpb.GetModelsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("models request failed: %w", pluginErrorFromStatus(err))
+	}
+
+	models := make([]plugin.ModelInfo, 0, len(resp.GetModels()))
+	for _, m := range resp.GetModels() {
+		models = append(models, modelInfoFromProto(m))
+	}
+	return models, nil
+}
+
+// Cleanup performs plugin cleanup and closes the connection.
+func (c *GRPCPluginClient) Cleanup(ctx context.Context) error {
+	defer c.conn.Close()
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	if _, err := c.client.Cleanup(ctx, &pluginpb.CleanupRequest{}); err != nil {
+		return fmt.Errorf("cleanup request failed: %w", pluginErrorFromStatus(err))
+	}
+
+	return nil
+}
+
+// Close closes the underlying connection without calling Cleanup.
+func (c *GRPCPluginClient) Close() error {
+	return c.conn.Close()
+}
+
+// withTimeout applies the client's default timeout if ctx has no deadline.
+func (c *GRPCPluginClient) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, c.timeout)
+}
+
+// pluginErrorFromStatus converts a gRPC status error into a PluginError so
+// callers can use plugin.IsTransientError and plugin.GetErrorCode uniformly.
+func pluginErrorFromStatus(err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	switch st.Code() {
+	case codes.Unauthenticated, codes.PermissionDenied:
+		return plugin.NewPluginError(plugin.ErrorCodeAuthenticationFailed, st.Message(), false)
+	case codes.ResourceExhausted:
+		return plugin.NewPluginError(plugin.ErrorCodeRateLimitExceeded, st.Message(), true)
+	case codes.InvalidArgument:
+		return plugin.NewPluginError(plugin.ErrorCodeInvalidRequest, st.Message(), false)
+	case codes.NotFound:
+		return plugin.NewPluginError(plugin.ErrorCodeModelNotFound, st.Message(), false)
+	case codes.Unavailable:
+		return plugin.NewPluginError(plugin.ErrorCodeProviderUnavailable, st.Message(), true)
+	case codes.DeadlineExceeded:
+		return plugin.NewPluginError(plugin.ErrorCodeTimeout, st.Message(), true)
+	default:
+		return plugin.NewPluginError(plugin.ErrorCodeInternalError, st.Message(), false)
+	}
+}
+
+func metadataFromProto(m *pluginpb.Metadata) *plugin.Metadata {
+	md := &plugin.Metadata{
+		Name:             m.GetName(),
+		Version:          m.GetVersion(),
+		PluginAPIVersion: m.GetPluginApiVersion(),
+		ProviderType:     m.GetProviderType(),
+		Description:      m.GetDescription(),
+		Author:           m.GetAuthor(),
+		Homepage:         m.GetHomepage(),
+		License:          m.GetLicense(),
+		Capabilities:     capabilitiesFromProto(m.GetCapabilities()),
+	}
+	for _, f := range m.GetConfigSchema() {
+		field := plugin.ConfigField{
+			Name:        f.GetName(),
+			Type:        f.GetType(),
+			Required:    f.GetRequired(),
+			Description: f.GetDescription(),
+			Sensitive:   f.GetSensitive(),
+		}
+		if f.GetDefault() != nil {
+			field.Default = f.GetDefault().AsInterface()
+		}
+		if v := f.GetValidation(); v != nil {
+			rule := &plugin.ValidationRule{
+				MinLength: int(v.GetMinLength()),
+				MaxLength: int(v.GetMaxLength()),
+				Pattern:   v.GetPattern(),
+				Min:       v.Min,
+				Max:       v.Max,
+			}
+			for _, e := range v.GetEnum() {
+				rule.Enum = append(rule.Enum, e.AsInterface())
+			}
+			field.Validation = rule
+		}
+		md.ConfigSchema = append(md.ConfigSchema, field)
+	}
+	return md
+}
+
+func capabilitiesFromProto(c *pluginpb.Capabilities) plugin.Capabilities {
+	return plugin.Capabilities{
+		Streaming:          c.GetStreaming(),
+		FunctionCalling:    c.GetFunctionCalling(),
+		Vision:             c.GetVision(),
+		Embeddings:         c.GetEmbeddings(),
+		FineTuning:         c.GetFineTuning(),
+		CustomCapabilities: c.GetCustomCapabilities(),
+	}
+}
+
+func chatRequestToProto(req *plugin.ChatCompletionRequest) (*pluginpb.ChatCompletionRequest, error) {
+	pb := &pluginpb.ChatCompletionRequest{
+		Model:            req.Model,
+		Temperature:      req.Temperature,
+		TopP:             req.TopP,
+		FrequencyPenalty: req.FrequencyPenalty,
+		PresencePenalty:  req.PresencePenalty,
+		Stop:             req.Stop,
+		Stream:           req.Stream,
+		User:             req.User,
+	}
+	if req.MaxTokens != nil {
+		maxTokens := int32(*req.MaxTokens)
+		pb.MaxTokens = &maxTokens
+	}
+	for _, msg := range req.Messages {
+		pb.Messages = append(pb.Messages, chatMessageToProto(msg))
+	}
+	if len(req.PluginSpecific) > 0 {
+		s, err := structpb.NewStruct(req.PluginSpecific)
+		if err != nil {
+			return nil, err
+		}
+		pb.PluginSpecific = s
+	}
+	return pb, nil
+}
+
+func chatMessageToProto(msg plugin.ChatMessage) *pluginpb.ChatMessage {
+	pb := &pluginpb.ChatMessage{
+		Role:    msg.Role,
+		Content: msg.Content,
+		Name:    msg.Name,
+	}
+	if msg.FunctionCall != nil {
+		pb.FunctionCall = &pluginpb.FunctionCall{
+			Name:      msg.FunctionCall.Name,
+			Arguments: msg.FunctionCall.Arguments,
+		}
+	}
+	return pb
+}
+
+func chatMessageFromProto(pb *pluginpb.ChatMessage) plugin.ChatMessage {
+	msg := plugin.ChatMessage{
+		Role:    pb.GetRole(),
+		Content: pb.GetContent(),
+		Name:    pb.GetName(),
+	}
+	if fc := pb.GetFunctionCall(); fc != nil {
+		msg.FunctionCall = &plugin.FunctionCall{Name: fc.GetName(), Arguments: fc.GetArguments()}
+	}
+	return msg
+}
+
+func chatResponseFromProto(pb *pluginpb.ChatCompletionResponse) *plugin.ChatCompletionResponse {
+	resp := &plugin.ChatCompletionResponse{
+		ID:      pb.GetId(),
+		Object:  pb.GetObject(),
+		Created: pb.GetCreated(),
+		Model:   pb.GetModel(),
+	}
+	for _, ch := range pb.GetChoices() {
+		resp.Choices = append(resp.Choices, plugin.Choice{
+			Index:        int(ch.GetIndex()),
+			Message:      chatMessageFromProto(ch.GetMessage()),
+			FinishReason: ch.GetFinishReason(),
+		})
+	}
+	if u := pb.GetUsage(); u != nil {
+		resp.Usage = &plugin.UsageInfo{
+			PromptTokens:     int(u.GetPromptTokens()),
+			CompletionTokens: int(u.GetCompletionTokens()),
+			TotalTokens:      int(u.GetTotalTokens()),
+			CostUSD:          u.CostUsd,
+		}
+	}
+	if pb.GetPluginSpecific() != nil {
+		resp.PluginSpecific = pb.GetPluginSpecific().AsMap()
+	}
+	return resp
+}
+
+func modelInfoFromProto(pb *pluginpb.ModelInfo) plugin.ModelInfo {
+	m := plugin.ModelInfo{
+		ID:              pb.GetId(),
+		Name:            pb.GetName(),
+		Description:     pb.GetDescription(),
+		ContextWindow:   int(pb.GetContextWindow()),
+		MaxOutputTokens: int(pb.GetMaxOutputTokens()),
+		CostPerMToken:   pb.CostPerMtoken,
+		Capabilities:    capabilitiesFromProto(pb.GetCapabilities()),
+		Deprecated:      pb.GetDeprecated(),
+	}
+	if pb.GetMetadata() != nil {
+		m.Metadata = pb.GetMetadata().AsMap()
+	}
+	return m
+}
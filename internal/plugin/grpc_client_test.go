@@ -0,0 +1,211 @@
+package plugin
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"github.com/jordanhubbard/loom/internal/plugin/pluginpb"
+	"github.com/jordanhubbard/loom/pkg/plugin"
+)
+
+type fakePluginServer struct {
+	pluginpb.UnimplementedPluginServiceServer
+	providerType string
+	initConfig   map[string]interface{}
+	lastRequest  *pluginpb.ChatCompletionRequest
+	cleanedUp    bool
+}
+
+func (s *fakePluginServer) GetMetadata(ctx context.Context, _ *pluginpb.GetMetadataRequest) (*pluginpb.Metadata, error) {
+	return &pluginpb.Metadata{
+		Name:         "gRPC Test Plugin",
+		Version:      "1.0.0",
+		ProviderType: s.providerType,
+		Capabilities: &pluginpb.Capabilities{Streaming: true},
+	}, nil
+}
+
+func (s *fakePluginServer) Initialize(ctx context.Context, req *pluginpb.InitializeRequest) (*pluginpb.InitializeResponse, error) {
+	s.initConfig = req.GetConfig().AsMap()
+	return &pluginpb.InitializeResponse{}, nil
+}
+
+func (s *fakePluginServer) CreateChatCompletion(ctx context.Context, req *pluginpb.ChatCompletionRequest) (*pluginpb.ChatCompletionResponse, error) {
+	s.lastRequest = req
+	if req.GetModel() == "rate-limited" {
+		return nil, status.Error(codes.ResourceExhausted, "slow down")
+	}
+	return &pluginpb.ChatCompletionResponse{
+		Id:    "cmpl-1",
+		Model: req.GetModel(),
+		Choices: []*pluginpb.Choice{{
+			Message:      &pluginpb.ChatMessage{Role: "assistant", Content: "pong"},
+			FinishReason: "stop",
+		}},
+		Usage: &pluginpb.UsageInfo{PromptTokens: 3, CompletionTokens: 1, TotalTokens: 4},
+	}, nil
+}
+
+func (s *fakePluginServer) GetModels(ctx context.Context, _ *pluginpb.GetModelsRequest) (*pluginpb.GetModelsResponse, error) {
+	return &pluginpb.GetModelsResponse{Models: []*pluginpb.ModelInfo{{Id: "m1", Name: "Model One", ContextWindow: 8192}}}, nil
+}
+
+func (s *fakePluginServer) Cleanup(ctx context.Context, _ *pluginpb.CleanupRequest) (*pluginpb.CleanupResponse, error) {
+	s.cleanedUp = true
+	return &pluginpb.CleanupResponse{}, nil
+}
+
+// startFakeGRPCPlugin serves the fake plugin and health service on a local port.
+func startFakeGRPCPlugin(t *testing.T, servingStatus healthpb.HealthCheckResponse_ServingStatus) (*fakePluginServer, string) {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	srv := grpc.NewServer()
+	fake := &fakePluginServer{providerType: "grpc-provider"}
+	pluginpb.RegisterPluginServiceServer(srv, fake)
+	hs := health.NewServer()
+	hs.SetServingStatus("", servingStatus)
+	healthpb.RegisterHealthServer(srv, hs)
+
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	return fake, lis.Addr().String()
+}
+
+func TestGRPCPluginClient_RoundTrip(t *testing.T) {
+	fake, addr := startFakeGRPCPlugin(t, healthpb.HealthCheckResponse_SERVING)
+	ctx := context.Background()
+
+	client, err := NewGRPCPluginClient("grpc://" + addr)
+	if err != nil {
+		t.Fatalf("NewGRPCPluginClient: %v", err)
+	}
+
+	if err := client.Initialize(ctx, map[string]interface{}{"api_key": "secret"}); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	if fake.initConfig["api_key"] != "secret" {
+		t.Errorf("Expected config to reach plugin, got %v", fake.initConfig)
+	}
+
+	md := client.GetMetadata()
+	if md == nil || md.ProviderType != "grpc-provider" || !md.Capabilities.Streaming {
+		t.Fatalf("Unexpected metadata: %+v", md)
+	}
+
+	health, err := client.HealthCheck(ctx)
+	if err != nil || !health.Healthy {
+		t.Fatalf("Expected healthy plugin, got %+v, %v", health, err)
+	}
+
+	temp := 0.5
+	maxTokens := 64
+	resp, err := client.CreateChatCompletion(ctx, &plugin.ChatCompletionRequest{
+		Model:       "m1",
+		Messages:    []plugin.ChatMessage{{Role: "user", Content: "ping"}},
+		Temperature: &temp,
+		MaxTokens:   &maxTokens,
+	})
+	if err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+	if resp.Choices[0].Message.Content != "pong" || resp.Usage.TotalTokens != 4 {
+		t.Errorf("Unexpected response: %+v", resp)
+	}
+	if fake.lastRequest.GetTemperature() != 0.5 || fake.lastRequest.GetMaxTokens() != 64 {
+		t.Errorf("Optional fields not forwarded: %+v", fake.lastRequest)
+	}
+
+	models, err := client.GetModels(ctx)
+	if err != nil || len(models) != 1 || models[0].ContextWindow != 8192 {
+		t.Errorf("Unexpected models: %+v, %v", models, err)
+	}
+
+	if err := client.Cleanup(ctx); err != nil {
+		t.Fatalf("Cleanup: %v", err)
+	}
+	if !fake.cleanedUp {
+		t.Error("Expected Cleanup RPC to reach plugin")
+	}
+}
+
+func TestGRPCPluginClient_StatusMapsToPluginError(t *testing.T) {
+	_, addr := startFakeGRPCPlugin(t, healthpb.HealthCheckResponse_SERVING)
+	client, _ := NewGRPCPluginClient(addr)
+	defer client.Close()
+
+	_, err := client.CreateChatCompletion(context.Background(), &plugin.ChatCompletionRequest{Model: "rate-limited"})
+	if plugin.GetErrorCode(err) != plugin.ErrorCodeRateLimitExceeded {
+		t.Errorf("Expected rate_limit_exceeded, got %v", err)
+	}
+	if !plugin.IsTransientError(err) {
+		t.Error("Expected rate limit error to be transient")
+	}
+}
+
+func TestGRPCPluginClient_NotServing(t *testing.T) {
+	_, addr := startFakeGRPCPlugin(t, healthpb.HealthCheckResponse_NOT_SERVING)
+	client, _ := NewGRPCPluginClient(addr)
+	defer client.Close()
+
+	health, err := client.HealthCheck(context.Background())
+	if err != nil {
+		t.Fatalf("HealthCheck: %v", err)
+	}
+	if health.Healthy {
+		t.Error("Expected NOT_SERVING plugin to be unhealthy")
+	}
+}
+
+func TestLoadPlugin_Grpc(t *testing.T) {
+	_, addr := startFakeGRPCPlugin(t, healthpb.HealthCheckResponse_SERVING)
+	loader := NewLoader(t.TempDir())
+	ctx := context.Background()
+
+	err := loader.LoadPlugin(ctx, &PluginManifest{
+		Type:     "grpc",
+		Endpoint: addr,
+		Metadata: &plugin.Metadata{Name: "gRPC Test Plugin", Version: "1.0.0", ProviderType: "grpc-provider"},
+	})
+	if err != nil {
+		t.Fatalf("LoadPlugin: %v", err)
+	}
+
+	loaded, err := loader.GetPlugin("grpc-provider")
+	if err != nil {
+		t.Fatalf("GetPlugin: %v", err)
+	}
+	if _, ok := loaded.Client.(*GRPCPluginClient); !ok {
+		t.Errorf("Expected *GRPCPluginClient, got %T", loaded.Client)
+	}
+
+	if err := loader.UnloadPlugin(ctx, "grpc-provider"); err != nil {
+		t.Fatalf("UnloadPlugin: %v", err)
+	}
+}
+
+func TestLoadPlugin_GrpcUnhealthy(t *testing.T) {
+	_, addr := startFakeGRPCPlugin(t, healthpb.HealthCheckResponse_NOT_SERVING)
+	loader := NewLoader(t.TempDir())
+
+	err := loader.LoadPlugin(context.Background(), &PluginManifest{
+		Type:     "grpc",
+		Endpoint: addr,
+		Metadata: &plugin.Metadata{Name: "gRPC Test Plugin", Version: "1.0.0", ProviderType: "grpc-provider"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "unhealthy") {
+		t.Errorf("Expected unhealthy load error, got %v", err)
+	}
+}
@@ -0,0 +1,153 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// DefaultMaxHealthFailures is how many consecutive failed health checks
+// trigger an automatic reload of a plugin.
+const DefaultMaxHealthFailures = 3
+
+// PluginHealth is a snapshot of a loaded plugin's health.
+type PluginHealth struct {
+	ProviderType        string    `json:"provider_type"`
+	Healthy             bool      `json:"healthy"`
+	LastCheck           time.Time `json:"last_check"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	LastError           string    `json:"last_error,omitempty"`
+}
+
+// WithMaxHealthFailures sets how many consecutive failed health checks
+// trigger an automatic reload. Values <= 0 use DefaultMaxHealthFailures.
+func WithMaxHealthFailures(n int) LoaderOption {
+	return func(l *Loader) {
+		l.maxHealthFailures = n
+	}
+}
+
+// GetHealth returns the latest health of a loaded plugin.
+func (l *Loader) GetHealth(providerType string) (*PluginHealth, error) {
+	loaded, err := l.GetPlugin(providerType)
+	if err != nil {
+		return nil, err
+	}
+
+	loaded.healthMu.RLock()
+	defer loaded.healthMu.RUnlock()
+	return &PluginHealth{
+		ProviderType:        providerType,
+		Healthy:             loaded.Healthy,
+		LastCheck:           loaded.LastCheck,
+		ConsecutiveFailures: loaded.failures,
+		LastError:           loaded.lastError,
+	}, nil
+}
+
+// Stop shuts down all health monitors and waits for them to exit. Loaded
+// plugins stay loaded.
+func (l *Loader) Stop() {
+	l.stopOnce.Do(func() {
+		l.mu.Lock()
+		l.stopped = true
+		close(l.stopCh)
+		l.mu.Unlock()
+	})
+	l.monitors.Wait()
+}
+
+// startMonitor begins periodic health checks for a plugin whose manifest sets
+// a health check interval. The caller must hold l.mu.
+func (l *Loader) startMonitor(loaded *LoadedPlugin) {
+	interval := time.Duration(loaded.Manifest.HealthCheckInterval) * l.healthIntervalUnit
+	if interval <= 0 || l.stopped {
+		return
+	}
+	loaded.stopMonitor = make(chan struct{})
+	l.monitors.Add(1)
+	go l.monitor(loaded, interval, loaded.stopMonitor)
+}
+
+// stopMonitor signals a plugin's monitor to exit without waiting for it, so
+// a monitor can itself trigger a reload. The caller must hold l.mu.
+func stopMonitor(loaded *LoadedPlugin) {
+	if loaded.stopMonitor != nil {
+		close(loaded.stopMonitor)
+		loaded.stopMonitor = nil
+	}
+}
+
+func (l *Loader) monitor(loaded *LoadedPlugin, interval time.Duration, stop <-chan struct{}) {
+	defer l.monitors.Done()
+
+	providerType := loaded.Manifest.Metadata.ProviderType
+	maxFailures := l.maxHealthFailures
+	if maxFailures <= 0 {
+		maxFailures = DefaultMaxHealthFailures
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-l.stopCh:
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), interval)
+		status, err := loaded.Client.HealthCheck(ctx)
+		cancel()
+		if err == nil && status != nil && !status.Healthy {
+			err = fmt.Errorf("plugin is unhealthy: %s", status.Message)
+		} else if err == nil && status == nil {
+			err = fmt.Errorf("plugin returned no health status")
+		}
+
+		failures := loaded.recordHealth(err)
+		if err == nil || failures < maxFailures {
+			continue
+		}
+
+		log.Printf("[Plugin] %s failed %d consecutive health checks, reloading: %v", providerType, failures, err)
+		if current, getErr := l.GetPlugin(providerType); getErr != nil || current != loaded {
+			return // Unloaded or replaced since this check started
+		}
+		if reloadErr := l.ReloadPlugin(context.Background(), providerType); reloadErr != nil {
+			log.Printf("[Plugin] Reload of %s failed: %v", providerType, reloadErr)
+			loaded.resetFailures()
+			continue
+		}
+		return // The reloaded plugin has its own monitor
+	}
+}
+
+// recordHealth stores a health check outcome and returns the number of
+// consecutive failures.
+func (p *LoadedPlugin) recordHealth(err error) int {
+	p.healthMu.Lock()
+	defer p.healthMu.Unlock()
+
+	p.LastCheck = time.Now()
+	if err == nil {
+		p.Healthy = true
+		p.failures = 0
+		p.lastError = ""
+		return 0
+	}
+	p.Healthy = false
+	p.failures++
+	p.lastError = err.Error()
+	return p.failures
+}
+
+func (p *LoadedPlugin) resetFailures() {
+	p.healthMu.Lock()
+	defer p.healthMu.Unlock()
+	p.failures = 0
+}
@@ -1,23 +1,57 @@
 package plugin
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"math/rand/v2"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/jordanhubbard/loom/pkg/plugin"
 )
 
+// Retry defaults used when the corresponding RetryConfig field is zero.
+const (
+	DefaultRetryMaxAttempts = 3
+	DefaultRetryBaseDelay   = 100 * time.Millisecond
+	DefaultRetryMaxDelay    = 2 * time.Second
+)
+
+// RetryConfig controls how HTTPPluginClient retries failed requests.
+// Connection errors and 5xx responses are retried with exponential backoff;
+// 4xx responses are never retried. Zero values use the defaults above.
+type RetryConfig struct {
+	MaxAttempts int           // Total attempts, including the first
+	BaseDelay   time.Duration // Delay before the first retry; doubles on each retry
+	MaxDelay    time.Duration // Upper bound for a single delay
+	Jitter      float64       // Fraction of each delay to randomize (0-1); 0 disables jitter
+}
+
+// DefaultRetryConfig returns the retry settings used by NewHTTPPluginClient.
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxAttempts: DefaultRetryMaxAttempts,
+		BaseDelay:   DefaultRetryBaseDelay,
+		MaxDelay:    DefaultRetryMaxDelay,
+		Jitter:      0.2,
+	}
+}
+
 // HTTPPluginClient implements the plugin.Plugin interface over HTTP.
 // This allows plugins to run as separate processes, providing isolation.
 type HTTPPluginClient struct {
-	endpoint string
-	client   *http.Client
-	metadata *plugin.Metadata
+	// RetryConfig controls retries for unary requests (not streams).
+	RetryConfig RetryConfig
+
+	endpoint        string
+	client          *http.Client
+	streamingClient *http.Client // No overall timeout; relies on context cancellation
+	metadata        *plugin.Metadata
 }
 
 // NewHTTPPluginClient creates a new HTTP plugin client.
@@ -27,10 +61,16 @@ func NewHTTPPluginClient(endpoint string) (*HTTPPluginClient, error) {
 	}
 
 	return &HTTPPluginClient{
-		endpoint: endpoint,
+		RetryConfig: DefaultRetryConfig(),
+		endpoint:    endpoint,
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		streamingClient: &http.Client{
+			Transport: &http.Transport{
+				ResponseHeaderTimeout: 30 * time.Second,
+			},
+		},
 	}, nil
 }
 
@@ -117,6 +157,125 @@ func (c *HTTPPluginClient) CreateChatCompletion(ctx context.Context, req *plugin
 	return &completion, nil
 }
 
+// CreateChatCompletionStream sends a streaming chat completion request and
+// returns a channel of partial responses read from the plugin's
+// text/event-stream. The chunk channel is closed when the plugin sends
+// [DONE], the stream ends, or ctx is cancelled. At most one error is
+// delivered on the error channel, which is closed after the chunk channel.
+// A PluginError frame received mid-stream is surfaced as *plugin.PluginError.
+func (c *HTTPPluginClient) CreateChatCompletionStream(ctx context.Context, req *plugin.ChatCompletionRequest) (<-chan *plugin.ChatCompletionResponse, <-chan error, error) {
+	streamReq := *req
+	streamReq.Stream = true
+
+	body, err := json.Marshal(&streamReq)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.endpoint+"/chat/completions?stream=true", bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "text/event-stream")
+
+	resp, err := c.streamingClient.Do(httpReq)
+	if err != nil {
+		return nil, nil, fmt.Errorf("stream request failed: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(resp.Body)
+		var pluginErr plugin.PluginError
+		if err := json.Unmarshal(respBody, &pluginErr); err == nil && pluginErr.Message != "" {
+			return nil, nil, &pluginErr
+		}
+		return nil, nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	chunks := make(chan *plugin.ChatCompletionResponse)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(chunks)
+		defer resp.Body.Close()
+
+		if err := readEventStream(ctx, resp.Body, chunks); err != nil {
+			errs <- err
+		}
+	}()
+
+	return chunks, errs, nil
+}
+
+// readEventStream parses server-sent events from r and forwards each data
+// frame as a ChatCompletionResponse until [DONE] or EOF.
+func readEventStream(ctx context.Context, r io.Reader, chunks chan<- *plugin.ChatCompletionResponse) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	event := ""
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		switch {
+		case line == "":
+			// Blank line terminates an event
+			event = ""
+			continue
+		case strings.HasPrefix(line, ":"):
+			// SSE comment / keepalive
+			continue
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+			continue
+		case !strings.HasPrefix(line, "data:"):
+			continue
+		}
+
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "[DONE]" {
+			return nil
+		}
+
+		if event == "error" {
+			var pluginErr plugin.PluginError
+			if err := json.Unmarshal([]byte(data), &pluginErr); err != nil {
+				return fmt.Errorf("failed to parse error frame: %w", err)
+			}
+			return &pluginErr
+		}
+
+		var frame struct {
+			plugin.ChatCompletionResponse
+			Error *plugin.PluginError `json:"error,omitempty"`
+		}
+		if err := json.Unmarshal([]byte(data), &frame); err != nil {
+			return fmt.Errorf("failed to parse stream chunk: %w", err)
+		}
+		if frame.Error != nil {
+			return frame.Error
+		}
+
+		chunk := frame.ChatCompletionResponse
+		select {
+		case chunks <- &chunk:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read stream: %w", err)
+	}
+	return nil
+}
+
 // GetModels retrieves the list of available models.
 func (c *HTTPPluginClient) GetModels(ctx context.Context) ([]plugin.ModelInfo, error) {
 	resp, err := c.doRequest(ctx, "GET", "/models", nil)
@@ -142,8 +301,45 @@ func (c *HTTPPluginClient) Cleanup(ctx context.Context) error {
 	return nil
 }
 
-// doRequest performs an HTTP request to the plugin.
+// doRequest performs an HTTP request to the plugin, retrying transient
+// failures according to c.RetryConfig.
 func (c *HTTPPluginClient) doRequest(ctx context.Context, method, path string, body []byte) ([]byte, error) {
+	maxAttempts := c.RetryConfig.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultRetryMaxAttempts
+	}
+
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		respBody, retryable, err := c.doRequestOnce(ctx, method, path, body)
+		if err == nil {
+			return respBody, nil
+		}
+		lastErr = err
+
+		if !retryable || attempt >= maxAttempts || ctx.Err() != nil {
+			return nil, lastErr
+		}
+
+		delay := c.retryDelay(attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= delay {
+			// Not enough time left for another attempt
+			return nil, lastErr
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, lastErr
+		case <-timer.C:
+		}
+	}
+}
+
+// doRequestOnce performs a single HTTP request to the plugin and reports
+// whether a failure is worth retrying.
+func (c *HTTPPluginClient) doRequestOnce(ctx context.Context, method, path string, body []byte) ([]byte, bool, error) {
 	url := c.endpoint + path
 
 	var bodyReader io.Reader
@@ -153,7 +349,7 @@ func (c *HTTPPluginClient) doRequest(ctx context.Context, method, path string, b
 
 	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, false, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -161,24 +357,55 @@ func (c *HTTPPluginClient) doRequest(ctx context.Context, method, path string, b
 
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, true, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, true, fmt.Errorf("failed to read response: %w", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
+		retryable := resp.StatusCode >= 500
+
 		// Try to parse as PluginError
 		var pluginErr plugin.PluginError
 		if err := json.Unmarshal(respBody, &pluginErr); err == nil {
-			return nil, &pluginErr
+			return nil, retryable, &pluginErr
 		}
 
-		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(respBody))
+		return nil, retryable, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(respBody))
 	}
 
-	return respBody, nil
+	return respBody, false, nil
+}
+
+// retryDelay returns the backoff before retry number attempt (1-based).
+func (c *HTTPPluginClient) retryDelay(attempt int) time.Duration {
+	base := c.RetryConfig.BaseDelay
+	if base <= 0 {
+		base = DefaultRetryBaseDelay
+	}
+	maxDelay := c.RetryConfig.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = DefaultRetryMaxDelay
+	}
+
+	delay := base
+	for i := 1; i < attempt && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+
+	if jitter := c.RetryConfig.Jitter; jitter > 0 {
+		if jitter > 1 {
+			jitter = 1
+		}
+		// Spread uniformly over [delay*(1-jitter), delay*(1+jitter)]
+		delay = time.Duration(float64(delay) * (1 + jitter*(2*rand.Float64()-1)))
+	}
+	return delay
 }
@@ -2,11 +2,15 @@ package plugin
 
 import (
 	"context"
+	"crypto/ed25519"
 	"encoding/json"
 	"fmt"
+	"io"
+	"log"
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/jordanhubbard/loom/pkg/plugin"
 	"gopkg.in/yaml.v3"
@@ -17,12 +21,42 @@ type Loader struct {
 	pluginsDir string
 	plugins    map[string]*LoadedPlugin
 	mu         sync.RWMutex
+
+	// Manifest signature verification; see WithPublicKeys
+	requireSignature bool
+	publicKeys       []ed25519.PublicKey
+	keyErr           error
+
+	// Health monitoring; see health.go
+	maxHealthFailures  int
+	healthIntervalUnit time.Duration // Unit of HealthCheckInterval (seconds; shortened in tests)
+	monitors           sync.WaitGroup
+	stopCh             chan struct{}
+	stopOnce           sync.Once
+	stopped            bool
 }
 
 // LoadedPlugin represents a loaded plugin with its manifest.
 type LoadedPlugin struct {
-	Manifest *PluginManifest
-	Client   plugin.Plugin
+	Manifest  *PluginManifest
+	Client    plugin.Plugin
+	Healthy   bool      // Result of the latest health check; use Loader.GetHealth to read safely
+	LastCheck time.Time // When the latest health check ran
+
+	healthMu    sync.RWMutex
+	failures    int
+	lastError   string
+	stopMonitor chan struct{}
+
+	// Model list with prices, fetched on first use; see cost.go
+	modelsMu sync.Mutex
+	models   []plugin.ModelInfo
+
+	// In-flight requests; see drain.go
+	reqMu    sync.Mutex
+	active   int
+	draining bool
+	idle     chan struct{} // Closed when active drops to zero while draining
 }
 
 // PluginManifest describes a plugin's configuration and how to load it.
@@ -48,16 +82,31 @@ type PluginManifest struct {
 	// AutoStart indicates if the plugin should be started automatically
 	AutoStart bool `json:"auto_start" yaml:"auto_start"`
 
+	// Config is passed to the plugin's Initialize call. It is validated
+	// against Metadata.ConfigSchema before the plugin is contacted.
+	Config map[string]interface{} `json:"config,omitempty" yaml:"config,omitempty"`
+
 	// HealthCheckInterval is how often to check plugin health (seconds)
 	HealthCheckInterval int `json:"health_check_interval,omitempty" yaml:"health_check_interval,omitempty"`
+
+	// Signature is a base64 ed25519 signature over the manifest's JSON
+	// encoding without this field
+	Signature string `json:"signature,omitempty" yaml:"signature,omitempty"`
 }
 
 // NewLoader creates a new plugin loader.
-func NewLoader(pluginsDir string) *Loader {
-	return &Loader{
-		pluginsDir: pluginsDir,
-		plugins:    make(map[string]*LoadedPlugin),
-	}
+func NewLoader(pluginsDir string, opts ...LoaderOption) *Loader {
+	l := &Loader{
+		pluginsDir:         pluginsDir,
+		plugins:            make(map[string]*LoadedPlugin),
+		maxHealthFailures:  DefaultMaxHealthFailures,
+		healthIntervalUnit: time.Second,
+		stopCh:             make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
 }
 
 // DiscoverPlugins scans the plugins directory for plugin manifests.
@@ -126,88 +175,190 @@ func (l *Loader) LoadPlugin(ctx context.Context, manifest *PluginManifest) error
 		return fmt.Errorf("plugin %s already loaded", manifest.Metadata.ProviderType)
 	}
 
+	client, err := l.connectPlugin(ctx, manifest)
+	if err != nil {
+		return err
+	}
+
+	// Store loaded plugin
+	loaded := &LoadedPlugin{
+		Manifest:  manifest,
+		Client:    client,
+		Healthy:   true,
+		LastCheck: time.Now(),
+	}
+	l.plugins[manifest.Metadata.ProviderType] = loaded
+	l.startMonitor(loaded)
+
+	return nil
+}
+
+// connectPlugin creates a client for a manifest, initializes it and checks
+// that it reports the expected metadata and is healthy.
+func (l *Loader) connectPlugin(ctx context.Context, manifest *PluginManifest) (plugin.Plugin, error) {
+	if err := l.verifyManifest(manifest); err != nil {
+		return nil, fmt.Errorf("manifest signature verification failed: %w", err)
+	}
+	if err := checkManifestAPIVersion(manifest); err != nil {
+		return nil, err
+	}
+
+	config, err := pluginConfig(manifest)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create plugin client based on type
 	var client plugin.Plugin
-	var err error
 
 	switch manifest.Type {
 	case "http":
 		client, err = NewHTTPPluginClient(manifest.Endpoint)
 	case "grpc":
-		return fmt.Errorf("grpc plugins not yet implemented")
+		client, err = NewGRPCPluginClient(manifest.Endpoint)
 	case "builtin":
-		return fmt.Errorf("builtin plugins not yet implemented")
+		return nil, fmt.Errorf("builtin plugins not yet implemented")
 	default:
-		return fmt.Errorf("unsupported plugin type: %s", manifest.Type)
+		return nil, fmt.Errorf("unsupported plugin type: %s", manifest.Type)
 	}
 
 	if err != nil {
-		return fmt.Errorf("failed to create plugin client: %w", err)
+		return nil, fmt.Errorf("failed to create plugin client: %w", err)
+	}
+
+	// Release transport resources (e.g. gRPC connections) if loading fails
+	loadOK := false
+	if closer, ok := client.(io.Closer); ok {
+		defer func() {
+			if !loadOK {
+				_ = closer.Close()
+			}
+		}()
 	}
 
 	// Initialize plugin
-	config := make(map[string]interface{})
 	if err := client.Initialize(ctx, config); err != nil {
-		return fmt.Errorf("failed to initialize plugin: %w", err)
+		return nil, fmt.Errorf("failed to initialize plugin: %w", err)
 	}
 
 	// Verify metadata matches
 	pluginMetadata := client.GetMetadata()
+	if pluginMetadata == nil {
+		return nil, fmt.Errorf("plugin returned no metadata")
+	}
 	if pluginMetadata.ProviderType != manifest.Metadata.ProviderType {
-		return fmt.Errorf("provider type mismatch: manifest=%s, plugin=%s",
+		return nil, fmt.Errorf("provider type mismatch: manifest=%s, plugin=%s",
 			manifest.Metadata.ProviderType, pluginMetadata.ProviderType)
 	}
+	if v := pluginMetadata.PluginAPIVersion; v != "" && v != manifest.Metadata.PluginAPIVersion {
+		if err := checkAPIVersion(pluginMetadata.ProviderType, v, plugin.PluginVersion); err != nil {
+			return nil, err
+		}
+	}
 
 	// Health check
 	health, err := client.HealthCheck(ctx)
 	if err != nil {
-		return fmt.Errorf("plugin health check failed: %w", err)
+		return nil, fmt.Errorf("plugin health check failed: %w", err)
 	}
 	if !health.Healthy {
-		return fmt.Errorf("plugin is unhealthy: %s", health.Message)
-	}
-
-	// Store loaded plugin
-	l.plugins[manifest.Metadata.ProviderType] = &LoadedPlugin{
-		Manifest: manifest,
-		Client:   client,
+		return nil, fmt.Errorf("plugin is unhealthy: %s", health.Message)
 	}
-
-	return nil
+	loadOK = true
+	return client, nil
 }
 
-// UnloadPlugin unloads a plugin and performs cleanup.
-func (l *Loader) UnloadPlugin(ctx context.Context, providerType string) error {
-	l.mu.Lock()
-	defer l.mu.Unlock()
+// GetPlugin retrieves a loaded plugin.
+func (l *Loader) GetPlugin(providerType string) (*LoadedPlugin, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
 
 	loaded, exists := l.plugins[providerType]
 	if !exists {
-		return fmt.Errorf("plugin %s not loaded", providerType)
+		return nil, fmt.Errorf("plugin %s not loaded", providerType)
 	}
 
-	// Cleanup plugin
-	if err := loaded.Client.Cleanup(ctx); err != nil {
-		return fmt.Errorf("plugin cleanup failed: %w", err)
+	return loaded, nil
+}
+
+// CreateChatCompletion routes a chat completion request to a loaded plugin.
+// When the plugin reports usage without a cost, the cost is estimated from
+// the model's prices and set in the response's usage.
+func (l *Loader) CreateChatCompletion(ctx context.Context, providerType string, req *plugin.ChatCompletionRequest) (*plugin.ChatCompletionResponse, error) {
+	loaded, err := l.acquirePlugin(providerType)
+	if err != nil {
+		return nil, err
 	}
+	defer loaded.release()
 
-	// Remove from loaded plugins
-	delete(l.plugins, providerType)
+	resp, err := loaded.Client.CreateChatCompletion(ctx, req)
+	if err == nil && resp != nil && resp.Usage != nil && resp.Usage.CostUSD == nil {
+		model := resp.Model
+		if model == "" {
+			model = req.Model
+		}
+		cost := EstimateCost(loaded.modelInfo(ctx, model), resp.Usage)
+		resp.Usage.CostUSD = &cost
+	}
+	return resp, err
+}
 
-	return nil
+// chatCompletionStreamer is implemented by plugin clients that can stream
+// chat completion chunks over a channel.
+type chatCompletionStreamer interface {
+	CreateChatCompletionStream(ctx context.Context, req *plugin.ChatCompletionRequest) (<-chan *plugin.ChatCompletionResponse, <-chan error, error)
 }
 
-// GetPlugin retrieves a loaded plugin.
-func (l *Loader) GetPlugin(providerType string) (*LoadedPlugin, error) {
-	l.mu.RLock()
-	defer l.mu.RUnlock()
+// CreateChatCompletionStream routes a streaming chat completion request to a
+// loaded plugin. It returns an error unless the plugin's metadata declares
+// the streaming capability and its client supports streaming.
+func (l *Loader) CreateChatCompletionStream(ctx context.Context, providerType string, req *plugin.ChatCompletionRequest) (<-chan *plugin.ChatCompletionResponse, <-chan error, error) {
+	loaded, err := l.acquirePlugin(providerType)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	loaded, exists := l.plugins[providerType]
-	if !exists {
-		return nil, fmt.Errorf("plugin %s not loaded", providerType)
+	metadata := loaded.Client.GetMetadata()
+	if metadata == nil {
+		metadata = loaded.Manifest.Metadata
+	}
+	if metadata == nil || !metadata.Capabilities.Streaming {
+		loaded.release()
+		return nil, nil, fmt.Errorf("plugin %s does not support streaming", providerType)
 	}
 
-	return loaded, nil
+	streamer, ok := loaded.Client.(chatCompletionStreamer)
+	if !ok {
+		loaded.release()
+		return nil, nil, fmt.Errorf("plugin %s client does not implement streaming", providerType)
+	}
+
+	chunks, errs, err := streamer.CreateChatCompletionStream(ctx, req)
+	if err != nil {
+		loaded.release()
+		return nil, nil, err
+	}
+
+	// Relay the stream so the request counts as in flight until it ends
+	out := make(chan *plugin.ChatCompletionResponse)
+	outErrs := make(chan error, 1)
+	go func() {
+		defer loaded.release()
+		defer close(outErrs)
+		defer close(out)
+		for chunk := range chunks {
+			select {
+			case out <- chunk:
+			case <-ctx.Done():
+				outErrs <- ctx.Err()
+				return
+			}
+		}
+		if err := <-errs; err != nil {
+			outErrs <- err
+		}
+	}()
+	return out, outErrs, nil
 }
 
 // ListPlugins returns all loaded plugins.
@@ -223,29 +374,54 @@ func (l *Loader) ListPlugins() []*LoadedPlugin {
 	return plugins
 }
 
-// ReloadPlugin reloads a plugin (unload then load).
+// ReloadPlugin replaces a plugin with a new instance loaded from the same
+// manifest. The new instance is connected first and swapped in only once it
+// is healthy, so a failed reload leaves the current instance, and its health
+// monitor, in place.
 func (l *Loader) ReloadPlugin(ctx context.Context, providerType string) error {
-	// Get current manifest
-	loaded, err := l.GetPlugin(providerType)
+	old, err := l.GetPlugin(providerType)
 	if err != nil {
 		return err
 	}
 
-	manifest := loaded.Manifest
-
-	// Unload
-	if err := l.UnloadPlugin(ctx, providerType); err != nil {
-		return fmt.Errorf("failed to unload plugin: %w", err)
-	}
-
-	// Load
-	if err := l.LoadPlugin(ctx, manifest); err != nil {
+	client, err := l.connectPlugin(ctx, old.Manifest)
+	if err != nil {
 		return fmt.Errorf("failed to load plugin: %w", err)
 	}
 
+	l.mu.Lock()
+	if l.plugins[providerType] != old {
+		l.mu.Unlock()
+		closeClient(client)
+		return fmt.Errorf("plugin %s was unloaded or replaced during reload", providerType)
+	}
+	loaded := &LoadedPlugin{
+		Manifest:  old.Manifest,
+		Client:    client,
+		Healthy:   true,
+		LastCheck: time.Now(),
+	}
+	l.plugins[providerType] = loaded
+	stopMonitor(old)
+	l.startMonitor(loaded)
+	l.mu.Unlock()
+
+	// The new instance initialized the same endpoint, so the old one is
+	// closed once its requests finish rather than cleaned up.
+	if active := old.drain(ctx, true); active > 0 {
+		log.Printf("[Plugin] Retiring %s with %d requests still in flight", providerType, active)
+	}
+	closeClient(old.Client)
 	return nil
 }
 
+// closeClient releases a client's transport resources, if it holds any.
+func closeClient(client plugin.Plugin) {
+	if closer, ok := client.(io.Closer); ok {
+		_ = closer.Close()
+	}
+}
+
 // LoadAll discovers and loads all plugins.
 func (l *Loader) LoadAll(ctx context.Context) (int, error) {
 	manifests, err := l.DiscoverPlugins(ctx)
@@ -273,6 +449,11 @@ func (l *Loader) LoadAll(ctx context.Context) (int, error) {
 
 // loadManifest loads a plugin manifest from a file.
 func (l *Loader) loadManifest(path string) (*PluginManifest, error) {
+	return readManifest(path)
+}
+
+// readManifest reads a JSON or YAML manifest and checks its required fields.
+func readManifest(path string) (*PluginManifest, error) {
 	// Read file
 	data, err := os.ReadFile(path)
 	if err != nil {
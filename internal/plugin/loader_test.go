@@ -2,6 +2,8 @@ package plugin
 
 import (
 	"context"
+	"crypto/ed25519"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -9,10 +11,12 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/jordanhubbard/loom/pkg/plugin"
+	"gopkg.in/yaml.v3"
 )
 
 // --- Loader tests ---
@@ -595,12 +599,13 @@ func TestLoadPlugin_UnsupportedType(t *testing.T) {
 	}
 }
 
-func TestLoadPlugin_GrpcNotImplemented(t *testing.T) {
+func TestLoadPlugin_GrpcUnreachable(t *testing.T) {
 	loader := NewLoader(t.TempDir())
-	ctx := context.Background()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
 	manifest := &PluginManifest{
 		Type:     "grpc",
-		Endpoint: "localhost:50051",
+		Endpoint: "127.0.0.1:1",
 		Metadata: &plugin.Metadata{
 			Name:         "GRPC Plugin",
 			ProviderType: "grpc-provider",
@@ -608,10 +613,13 @@ func TestLoadPlugin_GrpcNotImplemented(t *testing.T) {
 	}
 	err := loader.LoadPlugin(ctx, manifest)
 	if err == nil {
-		t.Fatal("Expected error for grpc (not yet implemented)")
+		t.Fatal("Expected error for unreachable grpc plugin")
 	}
-	if !strings.Contains(err.Error(), "not yet implemented") {
-		t.Errorf("Expected 'not yet implemented' error, got: %v", err)
+	if !strings.Contains(err.Error(), "failed to initialize plugin") {
+		t.Errorf("Expected initialize error, got: %v", err)
+	}
+	if _, err := loader.GetPlugin("grpc-provider"); err == nil {
+		t.Error("Unreachable plugin should not be registered")
 	}
 }
 
@@ -1499,6 +1507,59 @@ func TestRegistry_Search_LocalSource(t *testing.T) {
 	}
 }
 
+func TestRegistry_SearchWithFilters(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := CreateLocalRegistry(tmpDir); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []*RegistryEntry{
+		{ID: "p1", Name: "OpenAI Plugin", Downloads: 100, Rating: 4.5, Verified: true, Tags: []string{"ai", "GPT", "streaming"}},
+		{ID: "p2", Name: "Claude Plugin", Downloads: 50, Rating: 4.0, Tags: []string{"ai", "claude", "streaming"}},
+		{ID: "p3", Name: "Local Plugin", Downloads: 500, Rating: 2.0, Verified: true, Tags: []string{"ai", "local"}},
+		{ID: "p4", Name: "Image Gen", Downloads: 200, Rating: 3.5, Verified: true, Tags: []string{"image"}},
+	} {
+		if err := AddToLocalRegistry(tmpDir, p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	reg := NewRegistry([]RegistrySource{{Name: "local", URL: "file://" + tmpDir, Enabled: true}})
+	ctx := context.Background()
+
+	ids := func(r *SearchResults) string {
+		var out []string
+		for _, p := range r.Plugins {
+			out = append(out, p.ID)
+		}
+		return strings.Join(out, ",")
+	}
+
+	// Text query alone, sorted by downloads * rating
+	results, err := reg.SearchWithFilters(ctx, "plugin", nil, false, 0)
+	if err != nil {
+		t.Fatalf("SearchWithFilters: %v", err)
+	}
+	if got := ids(results); got != "p3,p1,p2" {
+		t.Errorf("plugins = %s, want p3,p1,p2", got)
+	}
+	if results.TagCounts["ai"] != 3 || results.TagCounts["streaming"] != 2 || results.TagCounts["gpt"] != 1 || results.TagCounts["image"] != 0 {
+		t.Errorf("unexpected tag counts %v", results.TagCounts)
+	}
+
+	// Tags must all match, case-insensitively
+	results, _ = reg.SearchWithFilters(ctx, "", []string{"AI", "streaming"}, false, 0)
+	if got := ids(results); got != "p1,p2" {
+		t.Errorf("tag filter plugins = %s, want p1,p2", got)
+	}
+
+	results, _ = reg.SearchWithFilters(ctx, "", nil, true, 3.0)
+	if got := ids(results); got != "p4,p1" {
+		t.Errorf("verified, rated 3+ plugins = %s, want p4,p1", got)
+	}
+	if results.TagCounts["image"] != 1 || results.TagCounts["local"] != 0 {
+		t.Errorf("tag counts should cover filtered results only: %v", results.TagCounts)
+	}
+}
+
 func TestRegistry_Get_Found(t *testing.T) {
 	tmpDir := t.TempDir()
 	if err := CreateLocalRegistry(tmpDir); err != nil {
@@ -2134,3 +2195,721 @@ func TestLoadPlugin_HTTP_MetadataMismatch(t *testing.T) {
 		t.Errorf("Expected 'mismatch' error, got: %v", err)
 	}
 }
+
+// --- Streaming tests ---
+
+func newStreamingTestServer(t *testing.T, streaming bool, frames []string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/metadata":
+			json.NewEncoder(w).Encode(plugin.Metadata{
+				Name:         "Stream Plugin",
+				Version:      "1.0.0",
+				ProviderType: "stream-provider",
+				Capabilities: plugin.Capabilities{Streaming: streaming},
+			})
+		case "/health":
+			json.NewEncoder(w).Encode(plugin.HealthStatus{Healthy: true, Timestamp: time.Now()})
+		case "/chat/completions":
+			if r.URL.Query().Get("stream") != "true" {
+				t.Errorf("Expected stream=true query, got %q", r.URL.RawQuery)
+			}
+			if r.Header.Get("Accept") != "text/event-stream" {
+				t.Errorf("Expected Accept text/event-stream, got %q", r.Header.Get("Accept"))
+			}
+			w.Header().Set("Content-Type", "text/event-stream")
+			for _, f := range frames {
+				fmt.Fprint(w, f)
+				w.(http.Flusher).Flush()
+			}
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+}
+
+func drainStream(chunks <-chan *plugin.ChatCompletionResponse, errs <-chan error) ([]string, error) {
+	var content []string
+	for chunk := range chunks {
+		if len(chunk.Choices) > 0 {
+			content = append(content, chunk.Choices[0].Message.Content)
+		}
+	}
+	return content, <-errs
+}
+
+func TestHTTPPluginClient_CreateChatCompletionStream(t *testing.T) {
+	server := newStreamingTestServer(t, true, []string{
+		": keepalive\n\n",
+		"data: {\"id\":\"1\",\"choices\":[{\"index\":0,\"message\":{\"role\":\"assistant\",\"content\":\"Hel\"}}]}\n\n",
+		"data: {\"id\":\"1\",\"choices\":[{\"index\":0,\"message\":{\"role\":\"assistant\",\"content\":\"lo\"}}]}\n\n",
+		"data: [DONE]\n\n",
+		"data: {\"id\":\"ignored\",\"choices\":[{\"index\":0,\"message\":{\"content\":\"after done\"}}]}\n\n",
+	})
+	defer server.Close()
+
+	client, _ := NewHTTPPluginClient(server.URL)
+	chunks, errs, err := client.CreateChatCompletionStream(context.Background(), &plugin.ChatCompletionRequest{Model: "m"})
+	if err != nil {
+		t.Fatalf("CreateChatCompletionStream failed: %v", err)
+	}
+
+	content, err := drainStream(chunks, errs)
+	if err != nil {
+		t.Fatalf("Unexpected stream error: %v", err)
+	}
+	if strings.Join(content, "") != "Hello" {
+		t.Errorf("Expected 'Hello', got %q", strings.Join(content, ""))
+	}
+}
+
+func TestHTTPPluginClient_CreateChatCompletionStream_ErrorFrame(t *testing.T) {
+	server := newStreamingTestServer(t, true, []string{
+		"data: {\"id\":\"1\",\"choices\":[{\"index\":0,\"message\":{\"content\":\"partial\"}}]}\n\n",
+		"event: error\ndata: {\"code\":\"rate_limit_exceeded\",\"message\":\"slow down\",\"transient\":true}\n\n",
+	})
+	defer server.Close()
+
+	client, _ := NewHTTPPluginClient(server.URL)
+	chunks, errs, err := client.CreateChatCompletionStream(context.Background(), &plugin.ChatCompletionRequest{Model: "m"})
+	if err != nil {
+		t.Fatalf("CreateChatCompletionStream failed: %v", err)
+	}
+
+	content, err := drainStream(chunks, errs)
+	if len(content) != 1 {
+		t.Errorf("Expected 1 chunk before error, got %d", len(content))
+	}
+	pluginErr, ok := err.(*plugin.PluginError)
+	if !ok {
+		t.Fatalf("Expected *plugin.PluginError, got %T: %v", err, err)
+	}
+	if pluginErr.Code != plugin.ErrorCodeRateLimitExceeded || !pluginErr.Transient {
+		t.Errorf("Unexpected plugin error: %+v", pluginErr)
+	}
+}
+
+func TestHTTPPluginClient_CreateChatCompletionStream_InlineErrorFrame(t *testing.T) {
+	server := newStreamingTestServer(t, true, []string{
+		"data: {\"error\":{\"code\":\"internal_error\",\"message\":\"boom\"}}\n\n",
+	})
+	defer server.Close()
+
+	client, _ := NewHTTPPluginClient(server.URL)
+	chunks, errs, err := client.CreateChatCompletionStream(context.Background(), &plugin.ChatCompletionRequest{Model: "m"})
+	if err != nil {
+		t.Fatalf("CreateChatCompletionStream failed: %v", err)
+	}
+
+	_, err = drainStream(chunks, errs)
+	if plugin.GetErrorCode(err) != plugin.ErrorCodeInternalError {
+		t.Errorf("Expected internal_error, got %v", err)
+	}
+}
+
+func TestHTTPPluginClient_CreateChatCompletionStream_HTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"code":"authentication_failed","message":"bad key"}`))
+	}))
+	defer server.Close()
+
+	client, _ := NewHTTPPluginClient(server.URL)
+	_, _, err := client.CreateChatCompletionStream(context.Background(), &plugin.ChatCompletionRequest{Model: "m"})
+	if plugin.GetErrorCode(err) != plugin.ErrorCodeAuthenticationFailed {
+		t.Errorf("Expected authentication_failed, got %v", err)
+	}
+}
+
+func TestHTTPPluginClient_CreateChatCompletionStream_ContextCancel(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"id\":\"1\",\"choices\":[{\"index\":0,\"message\":{\"content\":\"a\"}}]}\n\n")
+		w.(http.Flusher).Flush()
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	client, _ := NewHTTPPluginClient(server.URL)
+	chunks, errs, err := client.CreateChatCompletionStream(ctx, &plugin.ChatCompletionRequest{Model: "m"})
+	if err != nil {
+		t.Fatalf("CreateChatCompletionStream failed: %v", err)
+	}
+
+	<-chunks
+	cancel()
+
+	select {
+	case _, ok := <-chunks:
+		if ok {
+			t.Error("Expected chunk channel to close after cancellation")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stream did not close after context cancellation")
+	}
+	if err := <-errs; err == nil {
+		t.Error("Expected context error after cancellation")
+	}
+}
+
+func TestLoader_CreateChatCompletionStream_RequiresCapability(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		streaming bool
+		wantErr   bool
+	}{
+		{"streaming declared", true, false},
+		{"streaming not declared", false, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newStreamingTestServer(t, tc.streaming, []string{
+				"data: {\"id\":\"1\",\"choices\":[{\"index\":0,\"message\":{\"content\":\"ok\"}}]}\n\n",
+				"data: [DONE]\n\n",
+			})
+			defer server.Close()
+
+			loader := NewLoader(t.TempDir())
+			ctx := context.Background()
+			err := loader.LoadPlugin(ctx, &PluginManifest{
+				Type:     "http",
+				Endpoint: server.URL,
+				Metadata: &plugin.Metadata{Name: "Stream Plugin", Version: "1.0.0", ProviderType: "stream-provider"},
+			})
+			if err != nil {
+				t.Fatalf("LoadPlugin failed: %v", err)
+			}
+
+			chunks, errs, err := loader.CreateChatCompletionStream(ctx, "stream-provider", &plugin.ChatCompletionRequest{Model: "m"})
+			if tc.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "does not support streaming") {
+					t.Errorf("Expected streaming capability error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateChatCompletionStream failed: %v", err)
+			}
+			content, err := drainStream(chunks, errs)
+			if err != nil || len(content) != 1 || content[0] != "ok" {
+				t.Errorf("Unexpected stream result: %v, %v", content, err)
+			}
+		})
+	}
+}
+
+// --- Retry tests ---
+
+func TestHTTPPluginClient_RetriesServerErrors(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			json.NewEncoder(w).Encode(plugin.PluginError{Code: plugin.ErrorCodeProviderUnavailable, Message: "warming up", Transient: true})
+			return
+		}
+		json.NewEncoder(w).Encode([]plugin.ModelInfo{{ID: "m1"}})
+	}))
+	defer server.Close()
+
+	client := &HTTPPluginClient{
+		endpoint:    server.URL,
+		client:      server.Client(),
+		RetryConfig: RetryConfig{MaxAttempts: 3, BaseDelay: time.Millisecond},
+	}
+
+	models, err := client.GetModels(context.Background())
+	if err != nil {
+		t.Fatalf("GetModels failed after retries: %v", err)
+	}
+	if len(models) != 1 || models[0].ID != "m1" {
+		t.Errorf("Unexpected models: %+v", models)
+	}
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Errorf("Expected 3 attempts, got %d", got)
+	}
+}
+
+func TestHTTPPluginClient_RetryExhausted(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := &HTTPPluginClient{
+		endpoint:    server.URL,
+		client:      server.Client(),
+		RetryConfig: RetryConfig{MaxAttempts: 4, BaseDelay: time.Millisecond},
+	}
+
+	if err := client.Cleanup(context.Background()); err == nil {
+		t.Fatal("Expected error after exhausting retries")
+	}
+	if got := atomic.LoadInt32(&calls); got != 4 {
+		t.Errorf("Expected 4 attempts, got %d", got)
+	}
+}
+
+func TestHTTPPluginClient_NoRetryOnClientError(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(plugin.PluginError{Code: plugin.ErrorCodeInvalidRequest, Message: "bad model"})
+	}))
+	defer server.Close()
+
+	client := &HTTPPluginClient{
+		endpoint:    server.URL,
+		client:      server.Client(),
+		RetryConfig: RetryConfig{MaxAttempts: 5, BaseDelay: time.Millisecond},
+	}
+
+	_, err := client.CreateChatCompletion(context.Background(), &plugin.ChatCompletionRequest{Model: "m"})
+	if plugin.GetErrorCode(err) != plugin.ErrorCodeInvalidRequest {
+		t.Errorf("Expected invalid_request PluginError, got %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("Expected a single attempt for 4xx, got %d", got)
+	}
+}
+
+func TestHTTPPluginClient_RetryRespectsContext(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := &HTTPPluginClient{
+		endpoint:    server.URL,
+		client:      server.Client(),
+		RetryConfig: RetryConfig{MaxAttempts: 5, BaseDelay: time.Second},
+	}
+
+	// Deadline is shorter than the first backoff, so no retry is attempted
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := client.GetModels(ctx); err == nil {
+		t.Fatal("Expected error")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Retry loop ignored context deadline (took %v)", elapsed)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("Expected 1 attempt before deadline, got %d", got)
+	}
+
+	// Cancellation during backoff stops the loop early
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start = time.Now()
+	if _, err := client.GetModels(ctx); err == nil {
+		t.Fatal("Expected error")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Retry loop ignored cancellation (took %v)", elapsed)
+	}
+}
+
+func TestHTTPPluginClient_RetryDelay(t *testing.T) {
+	client := &HTTPPluginClient{
+		RetryConfig: RetryConfig{BaseDelay: 100 * time.Millisecond, MaxDelay: 350 * time.Millisecond},
+	}
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 350 * time.Millisecond, 350 * time.Millisecond}
+	for i, w := range want {
+		if got := client.retryDelay(i + 1); got != w {
+			t.Errorf("retryDelay(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+
+	client.RetryConfig.Jitter = 0.5
+	for i := 0; i < 50; i++ {
+		d := client.retryDelay(1)
+		if d < 50*time.Millisecond || d > 150*time.Millisecond {
+			t.Fatalf("Jittered delay %v outside [50ms, 150ms]", d)
+		}
+	}
+}
+
+func TestNewHTTPPluginClient_DefaultRetryConfig(t *testing.T) {
+	client, err := NewHTTPPluginClient("http://localhost:1")
+	if err != nil {
+		t.Fatalf("NewHTTPPluginClient: %v", err)
+	}
+	if client.RetryConfig.MaxAttempts != DefaultRetryMaxAttempts {
+		t.Errorf("MaxAttempts = %d, want %d", client.RetryConfig.MaxAttempts, DefaultRetryMaxAttempts)
+	}
+}
+
+func newSigningKey(t *testing.T) (string, ed25519.PrivateKey) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(pub), priv
+}
+
+func signedTestManifest(t *testing.T, priv ed25519.PrivateKey) *PluginManifest {
+	t.Helper()
+	manifest := &PluginManifest{
+		Type: "builtin",
+		Metadata: &plugin.Metadata{
+			Name:         "Signed",
+			Version:      "1.0.0",
+			ProviderType: "signed",
+		},
+	}
+	if err := SignManifest(manifest, priv); err != nil {
+		t.Fatalf("SignManifest: %v", err)
+	}
+	return manifest
+}
+
+func TestLoadPlugin_SignatureVerification(t *testing.T) {
+	pubKey, priv := newSigningKey(t)
+	ctx := context.Background()
+
+	// builtin plugins fail after verification, so the error tells us which step rejected the manifest
+	loader := NewLoader(t.TempDir(), WithPublicKeys(pubKey))
+	err := loader.LoadPlugin(ctx, signedTestManifest(t, priv))
+	if err == nil || !strings.Contains(err.Error(), "builtin plugins not yet implemented") {
+		t.Errorf("expected valid signature to pass verification, got %v", err)
+	}
+
+	unsigned := signedTestManifest(t, priv)
+	unsigned.Signature = ""
+	if err := loader.LoadPlugin(ctx, unsigned); err == nil || !strings.Contains(err.Error(), "signature") {
+		t.Errorf("expected unsigned manifest to be rejected, got %v", err)
+	}
+
+	tampered := signedTestManifest(t, priv)
+	tampered.Endpoint = "http://evil.example.com"
+	if err := loader.LoadPlugin(ctx, tampered); err == nil || !strings.Contains(err.Error(), "signature") {
+		t.Errorf("expected tampered manifest to be rejected, got %v", err)
+	}
+
+	otherKey, _ := newSigningKey(t)
+	other := NewLoader(t.TempDir(), WithPublicKeys(otherKey))
+	if err := other.LoadPlugin(ctx, signedTestManifest(t, priv)); err == nil || !strings.Contains(err.Error(), "signature") {
+		t.Errorf("expected manifest signed by an untrusted key to be rejected, got %v", err)
+	}
+}
+
+func TestLoadPlugin_NoKeysSkipsVerification(t *testing.T) {
+	loader := NewLoader(t.TempDir())
+	manifest := &PluginManifest{Type: "builtin", Metadata: &plugin.Metadata{ProviderType: "unsigned"}}
+	err := loader.LoadPlugin(context.Background(), manifest)
+	if err == nil || strings.Contains(err.Error(), "signature") {
+		t.Errorf("expected unsigned manifest to be accepted without keys, got %v", err)
+	}
+}
+
+func TestLoadPlugin_InvalidPublicKeyFailsClosed(t *testing.T) {
+	_, priv := newSigningKey(t)
+	loader := NewLoader(t.TempDir(), WithPublicKeys("not-a-key"))
+	err := loader.LoadPlugin(context.Background(), signedTestManifest(t, priv))
+	if err == nil || !strings.Contains(err.Error(), "invalid trusted keys") {
+		t.Errorf("expected invalid key to reject loads, got %v", err)
+	}
+}
+
+func TestSignManifest_SurvivesYAMLRoundTrip(t *testing.T) {
+	pubKey, priv := newSigningKey(t)
+	manifest := signedTestManifest(t, priv)
+	path := filepath.Join(t.TempDir(), "plugin.yaml")
+	if err := SaveManifest(manifest, path); err != nil {
+		t.Fatalf("SaveManifest: %v", err)
+	}
+
+	loaded, err := NewLoader(t.TempDir()).loadManifest(path)
+	if err != nil {
+		t.Fatalf("loadManifest: %v", err)
+	}
+	keys, _ := parsePublicKeys([]string{pubKey})
+	if err := VerifyManifestSignature(loaded, keys); err != nil {
+		t.Errorf("VerifyManifestSignature after round trip: %v", err)
+	}
+}
+
+func TestRegistry_Install_VerifiesSignature(t *testing.T) {
+	pubKey, priv := newSigningKey(t)
+	manifest := &PluginManifest{
+		Type:     "http",
+		Endpoint: "http://localhost:8080",
+		Metadata: &plugin.Metadata{Name: "Signed Plugin", Version: "1.0.0", ProviderType: "signed"},
+	}
+	if err := SignManifest(manifest, priv); err != nil {
+		t.Fatalf("SignManifest: %v", err)
+	}
+	var manifestData []byte
+	manifestServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(manifestData)
+	}))
+	defer manifestServer.Close()
+
+	index := RegistryIndex{Version: "1.0", Plugins: []*RegistryEntry{{
+		ID:      "signed",
+		Install: InstallConfig{Type: "http", ManifestURL: manifestServer.URL + "/plugin.yaml"},
+	}}}
+	registryServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(index)
+	}))
+	defer registryServer.Close()
+
+	reg := NewRegistry([]RegistrySource{{Name: "remote", URL: registryServer.URL, Enabled: true}})
+	if err := reg.SetPublicKeys(pubKey); err != nil {
+		t.Fatalf("SetPublicKeys: %v", err)
+	}
+	if err := reg.SetPublicKeys("bad"); err == nil {
+		t.Error("expected invalid key to be rejected")
+	}
+	_ = reg.SetPublicKeys(pubKey)
+
+	// Tampered manifest must not be saved
+	tampered := *manifest
+	tampered.Endpoint = "http://evil.example.com"
+	manifestData, _ = yaml.Marshal(&tampered)
+	dir := t.TempDir()
+	if err := reg.Install(context.Background(), "signed", dir); err == nil {
+		t.Fatal("expected tampered manifest to be rejected")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "signed", "plugin.yaml")); !os.IsNotExist(err) {
+		t.Error("rejected manifest should not be saved")
+	}
+
+	manifestData, _ = yaml.Marshal(manifest)
+	if err := reg.Install(context.Background(), "signed", dir); err != nil {
+		t.Fatalf("Install signed manifest: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "signed", "plugin.yaml")); err != nil {
+		t.Errorf("expected signed manifest to be saved: %v", err)
+	}
+}
+
+// newHealthTestServer serves a minimal HTTP plugin whose health can be toggled.
+// The plugin recovers when it is cleaned up, as if restarted by a reload.
+func newHealthTestServer(t *testing.T, healthy *atomic.Bool, healthChecks, inits *atomic.Int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/metadata":
+			json.NewEncoder(w).Encode(plugin.Metadata{Name: "Monitored", ProviderType: "monitored"})
+		case "/initialize":
+			// Reinitializing recovers the plugin
+			inits.Add(1)
+			healthy.Store(true)
+			w.Write([]byte(`{}`))
+		case "/health":
+			healthChecks.Add(1)
+			json.NewEncoder(w).Encode(plugin.HealthStatus{Healthy: healthy.Load(), Message: "test"})
+		case "/cleanup":
+			w.Write([]byte(`{}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newMonitoredLoader(t *testing.T, endpoint string) *Loader {
+	t.Helper()
+	loader := NewLoader(t.TempDir(), WithMaxHealthFailures(2))
+	loader.healthIntervalUnit = 10 * time.Millisecond
+	t.Cleanup(loader.Stop)
+
+	manifest := &PluginManifest{
+		Type:                "http",
+		Endpoint:            endpoint,
+		HealthCheckInterval: 1,
+		Metadata:            &plugin.Metadata{Name: "Monitored", ProviderType: "monitored"},
+	}
+	if err := loader.LoadPlugin(context.Background(), manifest); err != nil {
+		t.Fatalf("LoadPlugin: %v", err)
+	}
+	return loader
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestLoader_HealthMonitor_ReloadsAfterFailures(t *testing.T) {
+	var healthy atomic.Bool
+	var checks, inits atomic.Int32
+	healthy.Store(true)
+	server := newHealthTestServer(t, &healthy, &checks, &inits)
+	loader := newMonitoredLoader(t, server.URL)
+
+	health, err := loader.GetHealth("monitored")
+	if err != nil {
+		t.Fatalf("GetHealth: %v", err)
+	}
+	if !health.Healthy || health.LastCheck.IsZero() {
+		t.Errorf("expected healthy plugin with a check time after load, got %+v", health)
+	}
+
+	before := checks.Load()
+	waitFor(t, "periodic health checks", func() bool { return checks.Load() >= before+2 })
+
+	healthy.Store(false)
+	waitFor(t, "automatic reload", func() bool { return inits.Load() >= 2 })
+	waitFor(t, "healthy after reload", func() bool {
+		h, err := loader.GetHealth("monitored")
+		return err == nil && h.Healthy && h.ConsecutiveFailures == 0
+	})
+}
+
+func TestLoader_HealthMonitor_KeepsPluginWhenReloadFails(t *testing.T) {
+	var healthy, initFails atomic.Bool
+	var checks, inits atomic.Int32
+	healthy.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/metadata":
+			json.NewEncoder(w).Encode(plugin.Metadata{Name: "Monitored", ProviderType: "monitored"})
+		case "/initialize":
+			inits.Add(1)
+			if initFails.Load() {
+				http.Error(w, `{"error":"still down"}`, http.StatusBadRequest)
+				return
+			}
+			healthy.Store(true)
+			w.Write([]byte(`{}`))
+		case "/health":
+			checks.Add(1)
+			json.NewEncoder(w).Encode(plugin.HealthStatus{Healthy: healthy.Load(), Message: "test"})
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	loader := newMonitoredLoader(t, server.URL)
+	original, err := loader.GetPlugin("monitored")
+	if err != nil {
+		t.Fatalf("GetPlugin: %v", err)
+	}
+
+	// Reloads fail while the plugin is down, but it stays loaded and
+	// monitored.
+	initFails.Store(true)
+	healthy.Store(false)
+	waitFor(t, "a failed reload", func() bool { return inits.Load() >= 2 })
+	if current, err := loader.GetPlugin("monitored"); err != nil || current != original {
+		t.Fatalf("GetPlugin after failed reload = %v, %v; want the original instance", current, err)
+	}
+
+	// Once the plugin can initialize again, the monitor's next reload
+	// replaces the instance.
+	initFails.Store(false)
+	waitFor(t, "recovery after a failed reload", func() bool {
+		current, err := loader.GetPlugin("monitored")
+		if err != nil || current == original {
+			return false
+		}
+		h, err := loader.GetHealth("monitored")
+		return err == nil && h.Healthy
+	})
+}
+
+func TestLoader_HealthMonitor_RecordsFailures(t *testing.T) {
+	var healthy atomic.Bool
+	var checks, inits atomic.Int32
+	server := newHealthTestServer(t, &healthy, &checks, &inits)
+	healthy.Store(true)
+
+	loader := NewLoader(t.TempDir(), WithMaxHealthFailures(100))
+	loader.healthIntervalUnit = 10 * time.Millisecond
+	defer loader.Stop()
+	manifest := &PluginManifest{
+		Type:                "http",
+		Endpoint:            server.URL,
+		HealthCheckInterval: 1,
+		Metadata:            &plugin.Metadata{Name: "Monitored", ProviderType: "monitored"},
+	}
+	if err := loader.LoadPlugin(context.Background(), manifest); err != nil {
+		t.Fatalf("LoadPlugin: %v", err)
+	}
+
+	healthy.Store(false)
+	waitFor(t, "failed health checks", func() bool {
+		h, err := loader.GetHealth("monitored")
+		return err == nil && !h.Healthy && h.ConsecutiveFailures >= 2 && h.LastError != ""
+	})
+	if inits.Load() != 1 {
+		t.Errorf("expected no reload below the failure threshold, got %d initializations", inits.Load())
+	}
+}
+
+func TestLoader_HealthMonitor_StopsOnUnload(t *testing.T) {
+	var healthy atomic.Bool
+	var checks, inits atomic.Int32
+	healthy.Store(true)
+	server := newHealthTestServer(t, &healthy, &checks, &inits)
+	loader := newMonitoredLoader(t, server.URL)
+
+	waitFor(t, "first health check", func() bool { return checks.Load() >= 2 })
+	if err := loader.UnloadPlugin(context.Background(), "monitored"); err != nil {
+		t.Fatalf("UnloadPlugin: %v", err)
+	}
+	// Let an in-flight check finish, then make sure no more run
+	time.Sleep(30 * time.Millisecond)
+	after := checks.Load()
+	time.Sleep(50 * time.Millisecond)
+	if checks.Load() != after {
+		t.Errorf("health checks continued after unload: %d -> %d", after, checks.Load())
+	}
+	if _, err := loader.GetHealth("monitored"); err == nil {
+		t.Error("expected GetHealth to fail for an unloaded plugin")
+	}
+}
+
+func TestLoader_Stop_ShutsDownMonitors(t *testing.T) {
+	var healthy atomic.Bool
+	var checks, inits atomic.Int32
+	healthy.Store(true)
+	server := newHealthTestServer(t, &healthy, &checks, &inits)
+	loader := newMonitoredLoader(t, server.URL)
+
+	done := make(chan struct{})
+	go func() {
+		loader.Stop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Stop did not return")
+	}
+
+	after := checks.Load()
+	time.Sleep(50 * time.Millisecond)
+	if checks.Load() != after {
+		t.Errorf("health checks continued after Stop: %d -> %d", after, checks.Load())
+	}
+	if _, err := loader.GetPlugin("monitored"); err != nil {
+		t.Errorf("Stop should leave plugins loaded: %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+package plugin
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// pluginIDPattern matches registry plugin IDs. An ID names the plugin's
+// directory, so it must be a single lowercase path component.
+var pluginIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// validatePluginID rejects IDs that are not safe to use as a directory name.
+func validatePluginID(id string) error {
+	if !pluginIDPattern.MatchString(id) {
+		return fmt.Errorf("invalid plugin ID %q: use lowercase letters, digits, '.', '_' and '-'", id)
+	}
+	return nil
+}
+
+// Publish adds the plugin described by the manifest at manifestPath to the
+// local registry in registryDir. The manifest is copied to
+// plugins/<id>/plugin.yaml and registry.json gains an entry derived from its
+// metadata, replacing any earlier version. The provider type must be a valid
+// plugin ID, and publishing a version that is not newer than the one in the
+// registry fails.
+func Publish(manifestPath, registryDir string) (*RegistryEntry, error) {
+	manifest, err := readManifest(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := ValidateManifest(manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	entry := registryEntryFromManifest(manifest)
+	if err := validatePluginID(entry.ID); err != nil {
+		return nil, err
+	}
+	existing, err := NewRegistry(nil).loadLocalRegistry(registryDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range existing {
+		if e.ID != entry.ID {
+			continue
+		}
+		newer, err := compareVersions(entry.Version, e.Version)
+		if err != nil {
+			return nil, err
+		}
+		if newer <= 0 {
+			return nil, fmt.Errorf("plugin %s version %s is not newer than published version %s", entry.ID, entry.Version, e.Version)
+		}
+		entry.PublishedAt = e.PublishedAt
+		entry.Downloads = e.Downloads
+		entry.Rating = e.Rating
+		entry.Reviews = e.Reviews
+	}
+
+	pluginDir, err := filepath.Abs(filepath.Join(registryDir, "plugins", entry.ID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve plugin directory: %w", err)
+	}
+	if err := os.MkdirAll(pluginDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create plugin directory: %w", err)
+	}
+	// JSON manifests are valid YAML, so the copy is always plugin.yaml
+	dest := filepath.Join(pluginDir, "plugin.yaml")
+	if err := os.WriteFile(dest, data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to copy manifest: %w", err)
+	}
+	entry.Install = InstallConfig{Type: manifest.Type, ManifestURL: "file://" + dest}
+
+	if err := AddToLocalRegistry(registryDir, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// registryEntryFromManifest derives a registry entry from a manifest's
+// metadata. The provider type is the plugin's ID, and the tags are the
+// provider type plus each supported capability.
+func registryEntryFromManifest(manifest *PluginManifest) *RegistryEntry {
+	md := manifest.Metadata
+	now := time.Now().UTC()
+
+	tags := []string{md.ProviderType}
+	caps := md.Capabilities
+	for tag, ok := range map[string]bool{
+		"streaming":        caps.Streaming,
+		"function-calling": caps.FunctionCalling,
+		"vision":           caps.Vision,
+		"embeddings":       caps.Embeddings,
+		"fine-tuning":      caps.FineTuning,
+	} {
+		if ok {
+			tags = append(tags, tag)
+		}
+	}
+	for tag, ok := range caps.CustomCapabilities {
+		if ok {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Strings(tags[1:])
+
+	return &RegistryEntry{
+		ID:           md.ProviderType,
+		Name:         md.Name,
+		ProviderType: md.ProviderType,
+		Description:  md.Description,
+		Author:       md.Author,
+		Version:      md.Version,
+		License:      md.License,
+		Homepage:     md.Homepage,
+		Tags:         tags,
+		Capabilities: caps,
+		PublishedAt:  now,
+		UpdatedAt:    now,
+	}
+}
@@ -0,0 +1,113 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jordanhubbard/loom/pkg/plugin"
+)
+
+func writePublishManifest(t *testing.T, dir, version string) string {
+	t.Helper()
+	path := filepath.Join(dir, "src", "plugin.yaml")
+	manifest := &PluginManifest{
+		Type:     "http",
+		Endpoint: "http://localhost:8090",
+		Metadata: &plugin.Metadata{
+			Name:         "Publish Plugin",
+			Version:      version,
+			ProviderType: "publish-provider",
+			Description:  "Publishes things",
+			Capabilities: plugin.Capabilities{Streaming: true, Vision: true},
+		},
+	}
+	if err := SaveManifest(manifest, path); err != nil {
+		t.Fatalf("SaveManifest: %v", err)
+	}
+	return path
+}
+
+func TestPublish(t *testing.T) {
+	dir := t.TempDir()
+	registryDir := filepath.Join(dir, "registry")
+	if err := CreateLocalRegistry(registryDir); err != nil {
+		t.Fatalf("CreateLocalRegistry: %v", err)
+	}
+
+	entry, err := Publish(writePublishManifest(t, dir, "1.0.0"), registryDir)
+	if err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if entry.ID != "publish-provider" || entry.Version != "1.0.0" || entry.Install.Type != "http" {
+		t.Errorf("unexpected entry %+v", entry)
+	}
+	if strings.Join(entry.Tags, ",") != "publish-provider,streaming,vision" {
+		t.Errorf("unexpected tags %v", entry.Tags)
+	}
+	if _, err := os.Stat(filepath.Join(registryDir, "plugins", "publish-provider", "plugin.yaml")); err != nil {
+		t.Errorf("manifest was not copied: %v", err)
+	}
+
+	if _, err := Publish(writePublishManifest(t, dir, "1.0.0"), registryDir); err == nil {
+		t.Error("publishing the same version twice should fail")
+	}
+
+	updated, err := Publish(writePublishManifest(t, dir, "1.1.0"), registryDir)
+	if err != nil {
+		t.Fatalf("Publish new version: %v", err)
+	}
+	if !updated.PublishedAt.Equal(entry.PublishedAt) {
+		t.Error("a new version should keep the original publish time")
+	}
+	if _, err := Publish(writePublishManifest(t, dir, "1.0.0"), registryDir); err == nil {
+		t.Error("publishing a version older than the registry's should fail")
+	}
+
+	r := NewRegistry([]RegistrySource{{Name: "local", URL: "file://" + registryDir, Enabled: true}})
+	plugins, err := r.List(context.Background())
+	if err != nil || len(plugins) != 1 || plugins[0].Version != "1.1.0" {
+		t.Fatalf("expected one entry at 1.1.0, got %v (err %v)", plugins, err)
+	}
+	installDir := filepath.Join(dir, "installed")
+	if err := r.Install(context.Background(), "publish-provider", installDir); err != nil {
+		t.Fatalf("Install of a published plugin: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(installDir, "publish-provider", "plugin.yaml")); err != nil {
+		t.Errorf("installed manifest missing: %v", err)
+	}
+}
+
+func TestPublish_InvalidManifest(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "plugin.yaml")
+	manifest := &PluginManifest{Type: "http", Metadata: &plugin.Metadata{Name: "No Endpoint", ProviderType: "p", Version: "1.0.0"}}
+	if err := SaveManifest(manifest, path); err != nil {
+		t.Fatalf("SaveManifest: %v", err)
+	}
+	if _, err := Publish(path, filepath.Join(dir, "registry")); err == nil || !strings.Contains(err.Error(), "endpoint is required") {
+		t.Errorf("expected validation error, got %v", err)
+	}
+}
+
+func TestPublish_InvalidID(t *testing.T) {
+	dir := t.TempDir()
+	registryDir := filepath.Join(dir, "registry")
+	path := filepath.Join(dir, "src", "plugin.yaml")
+	manifest := &PluginManifest{
+		Type:     "http",
+		Endpoint: "http://localhost:8090",
+		Metadata: &plugin.Metadata{Name: "Escape", ProviderType: "../../escape", Version: "1.0.0"},
+	}
+	if err := SaveManifest(manifest, path); err != nil {
+		t.Fatalf("SaveManifest: %v", err)
+	}
+	if _, err := Publish(path, registryDir); err == nil || !strings.Contains(err.Error(), "invalid plugin ID") {
+		t.Errorf("expected invalid plugin ID error, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "escape")); !os.IsNotExist(err) {
+		t.Errorf("manifest written outside the registry (stat err %v)", err)
+	}
+}
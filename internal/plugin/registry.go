@@ -2,9 +2,11 @@ package plugin
 
 import (
 	"context"
+	"crypto/ed25519"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -13,6 +15,7 @@ import (
 	"time"
 
 	"github.com/jordanhubbard/loom/pkg/plugin"
+	"gopkg.in/yaml.v3"
 )
 
 // RegistryEntry represents a plugin in the registry.
@@ -54,8 +57,9 @@ type RegistryIndex struct {
 
 // Registry manages plugin discovery and installation from registries.
 type Registry struct {
-	sources []RegistrySource
-	cache   map[string]*RegistryEntry
+	sources    []RegistrySource
+	cache      map[string]*RegistryEntry
+	publicKeys []ed25519.PublicKey // Verify downloaded manifests when set
 }
 
 // RegistrySource represents a plugin registry source.
@@ -93,30 +97,67 @@ func NewDefaultRegistry() *Registry {
 
 // Search searches for plugins matching the query.
 func (r *Registry) Search(ctx context.Context, query string) ([]*RegistryEntry, error) {
+	results, err := r.SearchWithFilters(ctx, query, nil, false, 0)
+	if err != nil {
+		return nil, err
+	}
+	return results.Plugins, nil
+}
+
+// SearchResults holds the plugins found by SearchWithFilters and how many
+// of them carry each tag.
+type SearchResults struct {
+	Plugins   []*RegistryEntry `json:"plugins"`
+	TagCounts map[string]int   `json:"tag_counts"`
+}
+
+// SearchWithFilters searches like Search and keeps only plugins that have
+// every one of tags, are verified when verifiedOnly is set, and are rated
+// at least minRating. Tag counts cover the filtered results.
+func (r *Registry) SearchWithFilters(ctx context.Context, query string, tags []string, verifiedOnly bool, minRating float64) (*SearchResults, error) {
 	// Load all plugins from sources
 	allPlugins, err := r.loadAll(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	// Filter by query
-	var results []*RegistryEntry
+	// Filter by query and constraints
+	results := &SearchResults{Plugins: []*RegistryEntry{}, TagCounts: make(map[string]int)}
 	queryLower := strings.ToLower(query)
 
 	for _, plugin := range allPlugins {
 		// Match against name, description, tags, author
-		if strings.Contains(strings.ToLower(plugin.Name), queryLower) ||
-			strings.Contains(strings.ToLower(plugin.Description), queryLower) ||
-			strings.Contains(strings.ToLower(plugin.Author), queryLower) ||
-			containsTag(plugin.Tags, queryLower) {
-			results = append(results, plugin)
+		if !strings.Contains(strings.ToLower(plugin.Name), queryLower) &&
+			!strings.Contains(strings.ToLower(plugin.Description), queryLower) &&
+			!strings.Contains(strings.ToLower(plugin.Author), queryLower) &&
+			!containsTag(plugin.Tags, queryLower) {
+			continue
+		}
+		if verifiedOnly && !plugin.Verified {
+			continue
+		}
+		if plugin.Rating < minRating {
+			continue
+		}
+		if !hasAllTags(plugin.Tags, tags) {
+			continue
+		}
+		results.Plugins = append(results.Plugins, plugin)
+
+		seen := make(map[string]bool)
+		for _, tag := range plugin.Tags {
+			tag = strings.ToLower(tag)
+			if !seen[tag] {
+				results.TagCounts[tag]++
+				seen[tag] = true
+			}
 		}
 	}
 
 	// Sort by relevance (downloads * rating)
-	sort.Slice(results, func(i, j int) bool {
-		scoreI := float64(results[i].Downloads) * results[i].Rating
-		scoreJ := float64(results[j].Downloads) * results[j].Rating
+	sort.Slice(results.Plugins, func(i, j int) bool {
+		scoreI := float64(results.Plugins[i].Downloads) * results.Plugins[i].Rating
+		scoreJ := float64(results.Plugins[j].Downloads) * results.Plugins[j].Rating
 		return scoreI > scoreJ
 	})
 
@@ -153,6 +194,10 @@ func (r *Registry) List(ctx context.Context) ([]*RegistryEntry, error) {
 
 // Install installs a plugin from the registry.
 func (r *Registry) Install(ctx context.Context, pluginID, targetDir string) error {
+	if err := validatePluginID(pluginID); err != nil {
+		return err
+	}
+
 	// Get plugin from registry
 	entry, err := r.Get(ctx, pluginID)
 	if err != nil {
@@ -165,6 +210,16 @@ func (r *Registry) Install(ctx context.Context, pluginID, targetDir string) erro
 		return fmt.Errorf("failed to download manifest: %w", err)
 	}
 
+	if len(r.publicKeys) > 0 {
+		var manifest PluginManifest
+		if err := yaml.Unmarshal(manifestData, &manifest); err != nil {
+			return fmt.Errorf("failed to parse manifest: %w", err)
+		}
+		if err := VerifyManifestSignature(&manifest, r.publicKeys); err != nil {
+			return fmt.Errorf("manifest signature verification failed: %w", err)
+		}
+	}
+
 	// Create plugin directory
 	pluginDir := filepath.Join(targetDir, pluginID)
 	if err := os.MkdirAll(pluginDir, 0755); err != nil {
@@ -177,8 +232,10 @@ func (r *Registry) Install(ctx context.Context, pluginID, targetDir string) erro
 		return fmt.Errorf("failed to save manifest: %w", err)
 	}
 
-	// Record installation
-	entry.Downloads++
+	// Record installation; a failure to count it does not undo the install
+	if err := r.IncrementDownloads(pluginID); err != nil {
+		log.Printf("[Plugin] Failed to record download of %s: %v", pluginID, err)
+	}
 
 	return nil
 }
@@ -278,8 +335,13 @@ func (r *Registry) loadHTTPRegistry(ctx context.Context, url string) ([]*Registr
 	return index.Plugins, nil
 }
 
-// downloadFile downloads a file from a URL.
+// downloadFile downloads a file from a URL. file:// URLs, as written by
+// Publish, are read from disk.
 func (r *Registry) downloadFile(ctx context.Context, url string) ([]byte, error) {
+	if path, ok := strings.CutPrefix(url, "file://"); ok {
+		return os.ReadFile(path)
+	}
+
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
@@ -378,6 +440,16 @@ func getLocalRegistryPath() string {
 	return filepath.Join(home, ".loom", "registry")
 }
 
+// hasAllTags reports whether tags match every one of wanted.
+func hasAllTags(tags, wanted []string) bool {
+	for _, w := range wanted {
+		if !containsTag(tags, strings.ToLower(w)) {
+			return false
+		}
+	}
+	return true
+}
+
 func containsTag(tags []string, query string) bool {
 	for _, tag := range tags {
 		if strings.Contains(strings.ToLower(tag), query) {
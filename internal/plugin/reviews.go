@@ -0,0 +1,158 @@
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Rating bounds for SubmitReview.
+const (
+	MinRating = 1
+	MaxRating = 5
+)
+
+// reviewsFile holds a local registry's reviews, next to registry.json.
+const reviewsFile = "reviews.json"
+
+// Review is a user's rating of a plugin.
+type Review struct {
+	PluginID    string    `json:"plugin_id"`
+	Rating      int       `json:"rating"`
+	Comment     string    `json:"comment,omitempty"`
+	SubmittedAt time.Time `json:"submitted_at"`
+}
+
+// SubmitReview records a review of a plugin in the local registry that
+// lists it and updates the plugin's average Rating and Reviews count in
+// that registry's registry.json.
+func (r *Registry) SubmitReview(pluginID string, rating int, comment string) error {
+	if rating < MinRating || rating > MaxRating {
+		return fmt.Errorf("rating must be between %d and %d", MinRating, MaxRating)
+	}
+	dir, err := r.localRegistryFor(pluginID)
+	if err != nil {
+		return err
+	}
+	if dir == "" {
+		return fmt.Errorf("plugin %s is not in a local registry", pluginID)
+	}
+
+	reviews, err := readReviews(dir)
+	if err != nil {
+		return err
+	}
+	reviews[pluginID] = append(reviews[pluginID], Review{
+		PluginID:    pluginID,
+		Rating:      rating,
+		Comment:     comment,
+		SubmittedAt: time.Now().UTC(),
+	})
+	if err := writeReviews(dir, reviews); err != nil {
+		return err
+	}
+
+	total := 0
+	for _, review := range reviews[pluginID] {
+		total += review.Rating
+	}
+	count := int64(len(reviews[pluginID]))
+	average := float64(total) / float64(count)
+
+	return r.updateLocalEntry(dir, pluginID, func(entry *RegistryEntry) {
+		entry.Rating = average
+		entry.Reviews = count
+	})
+}
+
+// IncrementDownloads counts a download of a plugin. The count is saved to
+// registry.json when the plugin is in a local registry; plugins from remote
+// registries are only counted in memory.
+func (r *Registry) IncrementDownloads(pluginID string) error {
+	dir, err := r.localRegistryFor(pluginID)
+	if err != nil {
+		return err
+	}
+	if dir == "" {
+		if cached, ok := r.cache[pluginID]; ok {
+			cached.Downloads++
+		}
+		return nil
+	}
+	return r.updateLocalEntry(dir, pluginID, func(entry *RegistryEntry) {
+		entry.Downloads++
+	})
+}
+
+// localRegistryFor returns the directory of the first enabled local
+// registry that lists the plugin, or "" when none does.
+func (r *Registry) localRegistryFor(pluginID string) (string, error) {
+	for _, source := range r.sources {
+		dir, ok := strings.CutPrefix(source.URL, "file://")
+		if !source.Enabled || !ok {
+			continue
+		}
+		plugins, err := r.loadLocalRegistry(dir)
+		if err != nil {
+			return "", err
+		}
+		for _, p := range plugins {
+			if p.ID == pluginID {
+				return dir, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// updateLocalEntry applies update to a plugin's entry in the local registry
+// in dir, saves it and refreshes the cached entry.
+func (r *Registry) updateLocalEntry(dir, pluginID string, update func(*RegistryEntry)) error {
+	plugins, err := r.loadLocalRegistry(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range plugins {
+		if entry.ID != pluginID {
+			continue
+		}
+		update(entry)
+		if err := AddToLocalRegistry(dir, entry); err != nil {
+			return err
+		}
+		if cached, ok := r.cache[pluginID]; ok {
+			cached.Downloads, cached.Rating, cached.Reviews = entry.Downloads, entry.Rating, entry.Reviews
+		}
+		return nil
+	}
+	return fmt.Errorf("plugin not found: %s", pluginID)
+}
+
+func readReviews(dir string) (map[string][]Review, error) {
+	reviews := make(map[string][]Review)
+	data, err := os.ReadFile(filepath.Join(dir, reviewsFile))
+	if os.IsNotExist(err) {
+		return reviews, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read reviews: %w", err)
+	}
+	if err := json.Unmarshal(data, &reviews); err != nil {
+		return nil, fmt.Errorf("failed to parse reviews: %w", err)
+	}
+	return reviews, nil
+}
+
+func writeReviews(dir string, reviews map[string][]Review) error {
+	data, err := json.MarshalIndent(reviews, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal reviews: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, reviewsFile), data, 0644); err != nil {
+		return fmt.Errorf("failed to write reviews: %w", err)
+	}
+	return nil
+}
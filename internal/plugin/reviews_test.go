@@ -0,0 +1,83 @@
+package plugin
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func newPublishedRegistry(t *testing.T) (*Registry, string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	registryDir := filepath.Join(dir, "registry")
+	if err := CreateLocalRegistry(registryDir); err != nil {
+		t.Fatalf("CreateLocalRegistry: %v", err)
+	}
+	if _, err := Publish(writePublishManifest(t, dir, "1.0.0"), registryDir); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	r := NewRegistry([]RegistrySource{{Name: "local", URL: "file://" + registryDir, Enabled: true}})
+	return r, dir, registryDir
+}
+
+func TestRegistry_SubmitReview(t *testing.T) {
+	r, dir, registryDir := newPublishedRegistry(t)
+
+	for _, rating := range []int{0, 6} {
+		if err := r.SubmitReview("publish-provider", rating, ""); err == nil {
+			t.Errorf("rating %d should be rejected", rating)
+		}
+	}
+	if err := r.SubmitReview("missing", 5, ""); err == nil {
+		t.Error("reviewing a plugin outside the local registry should fail")
+	}
+
+	if err := r.SubmitReview("publish-provider", 5, "great"); err != nil {
+		t.Fatalf("SubmitReview: %v", err)
+	}
+	if err := r.SubmitReview("publish-provider", 2, ""); err != nil {
+		t.Fatalf("SubmitReview: %v", err)
+	}
+
+	entry, err := NewRegistry([]RegistrySource{{Name: "local", URL: "file://" + registryDir, Enabled: true}}).Get(context.Background(), "publish-provider")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if entry.Rating != 3.5 || entry.Reviews != 2 {
+		t.Errorf("rating = %v over %d reviews, want 3.5 over 2", entry.Rating, entry.Reviews)
+	}
+	reviews, err := readReviews(registryDir)
+	if err != nil || len(reviews["publish-provider"]) != 2 || reviews["publish-provider"][0].Comment != "great" {
+		t.Errorf("stored reviews = %+v (err %v)", reviews, err)
+	}
+
+	// A new version keeps its ratings
+	updated, err := Publish(writePublishManifest(t, dir, "1.1.0"), registryDir)
+	if err != nil {
+		t.Fatalf("Publish new version: %v", err)
+	}
+	if updated.Rating != 3.5 || updated.Reviews != 2 {
+		t.Errorf("new version rating = %v over %d reviews, want 3.5 over 2", updated.Rating, updated.Reviews)
+	}
+}
+
+func TestRegistry_InstallCountsDownloads(t *testing.T) {
+	r, dir, registryDir := newPublishedRegistry(t)
+
+	for i := 0; i < 2; i++ {
+		if err := r.Install(context.Background(), "publish-provider", filepath.Join(dir, "installed")); err != nil {
+			t.Fatalf("Install: %v", err)
+		}
+	}
+
+	plugins, err := r.loadLocalRegistry(registryDir)
+	if err != nil || len(plugins) != 1 {
+		t.Fatalf("loadLocalRegistry = %v, %v", plugins, err)
+	}
+	if plugins[0].Downloads != 2 {
+		t.Errorf("saved downloads = %d, want 2", plugins[0].Downloads)
+	}
+	if cached, _ := r.Get(context.Background(), "publish-provider"); cached.Downloads != 2 {
+		t.Errorf("cached downloads = %d, want 2", cached.Downloads)
+	}
+}
@@ -0,0 +1,104 @@
+package plugin
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// LoaderOption configures a Loader.
+type LoaderOption func(*Loader)
+
+// WithPublicKeys makes the loader require manifests to carry a valid ed25519
+// signature from one of the given base64-encoded public keys. An invalid key
+// makes every load fail rather than silently weakening verification.
+func WithPublicKeys(keys ...string) LoaderOption {
+	return func(l *Loader) {
+		l.requireSignature = len(keys) > 0
+		l.publicKeys, l.keyErr = parsePublicKeys(keys)
+	}
+}
+
+// SetPublicKeys makes Install verify downloaded manifests against the given
+// base64-encoded ed25519 public keys before saving them. Passing no keys
+// disables verification.
+func (r *Registry) SetPublicKeys(keys ...string) error {
+	parsed, err := parsePublicKeys(keys)
+	if err != nil {
+		return err
+	}
+	r.publicKeys = parsed
+	return nil
+}
+
+// SignManifest signs the manifest with an ed25519 private key, replacing any
+// existing signature.
+func SignManifest(manifest *PluginManifest, key ed25519.PrivateKey) error {
+	payload, err := canonicalManifest(manifest)
+	if err != nil {
+		return err
+	}
+	manifest.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(key, payload))
+	return nil
+}
+
+// VerifyManifestSignature checks the manifest's signature against the public
+// keys and succeeds if any of them verifies it.
+func VerifyManifestSignature(manifest *PluginManifest, keys []ed25519.PublicKey) error {
+	if manifest.Signature == "" {
+		return fmt.Errorf("manifest is not signed")
+	}
+	sig, err := base64.StdEncoding.DecodeString(manifest.Signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature encoding: %w", err)
+	}
+	payload, err := canonicalManifest(manifest)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if ed25519.Verify(key, payload, sig) {
+			return nil
+		}
+	}
+	return fmt.Errorf("signature does not match any trusted key")
+}
+
+// canonicalManifest is the signed form of a manifest: its JSON encoding with
+// the signature field omitted.
+func canonicalManifest(manifest *PluginManifest) ([]byte, error) {
+	unsigned := *manifest
+	unsigned.Signature = ""
+	data, err := json.Marshal(&unsigned)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	return data, nil
+}
+
+func parsePublicKeys(keys []string) ([]ed25519.PublicKey, error) {
+	parsed := make([]ed25519.PublicKey, 0, len(keys))
+	for i, k := range keys {
+		raw, err := base64.StdEncoding.DecodeString(k)
+		if err != nil {
+			return nil, fmt.Errorf("public key %d: invalid base64: %w", i, err)
+		}
+		if len(raw) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("public key %d: expected %d bytes, got %d", i, ed25519.PublicKeySize, len(raw))
+		}
+		parsed = append(parsed, ed25519.PublicKey(raw))
+	}
+	return parsed, nil
+}
+
+// verifyManifest enforces the loader's signature policy.
+func (l *Loader) verifyManifest(manifest *PluginManifest) error {
+	if !l.requireSignature {
+		return nil
+	}
+	if l.keyErr != nil {
+		return fmt.Errorf("invalid trusted keys: %w", l.keyErr)
+	}
+	return VerifyManifestSignature(manifest, l.publicKeys)
+}
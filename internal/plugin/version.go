@@ -0,0 +1,111 @@
+package plugin
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/jordanhubbard/loom/pkg/plugin"
+)
+
+// parseAPIVersion parses a "major.minor.patch" plugin API version. A leading
+// "v" and missing minor or patch parts are accepted.
+func parseAPIVersion(v string) (major, minor int, err error) {
+	parts := strings.SplitN(strings.TrimPrefix(strings.TrimSpace(v), "v"), ".", 3)
+	nums := make([]int, 2)
+	for i := 0; i < len(parts) && i < 2; i++ {
+		n, convErr := strconv.Atoi(parts[i])
+		if convErr != nil || n < 0 {
+			return 0, 0, fmt.Errorf("invalid plugin API version %q", v)
+		}
+		nums[i] = n
+	}
+	return nums[0], nums[1], nil
+}
+
+// checkAPIVersion reports whether a plugin built against pluginVersion can
+// run on a host implementing hostVersion. A newer major version is
+// rejected; other differences are logged. Manifests that omit the version
+// still load for backward compatibility.
+func checkAPIVersion(providerType, pluginVersion, hostVersion string) error {
+	if strings.TrimSpace(pluginVersion) == "" {
+		log.Printf("[Plugin] %s does not declare plugin_api_version; this is deprecated and will be required in a future release", providerType)
+		return nil
+	}
+	pMajor, pMinor, err := parseAPIVersion(pluginVersion)
+	if err != nil {
+		return err
+	}
+	hMajor, hMinor, err := parseAPIVersion(hostVersion)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case pMajor > hMajor:
+		return fmt.Errorf("plugin %s requires API v%d, host supports v%d", providerType, pMajor, hMajor)
+	case pMajor < hMajor:
+		log.Printf("[Plugin] %s was built for API v%d, host supports v%d; it may not work as expected", providerType, pMajor, hMajor)
+	case pMinor != hMinor:
+		log.Printf("[Plugin] %s was built for API v%d.%d, host supports v%d.%d", providerType, pMajor, pMinor, hMajor, hMinor)
+	}
+	return nil
+}
+
+// checkManifestAPIVersion checks a manifest against the host's plugin API.
+func checkManifestAPIVersion(manifest *PluginManifest) error {
+	return checkAPIVersion(manifest.Metadata.ProviderType, manifest.Metadata.PluginAPIVersion, plugin.PluginVersion)
+}
+
+// compareVersions compares two "major.minor.patch" plugin versions, returning
+// -1, 0 or 1. A leading "v" is accepted, and a pre-release ("1.0.0-rc1")
+// sorts before its release.
+func compareVersions(a, b string) (int, error) {
+	an, apre, err := parseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	bn, bpre, err := parseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+	for i := range an {
+		if an[i] != bn[i] {
+			if an[i] < bn[i] {
+				return -1, nil
+			}
+			return 1, nil
+		}
+	}
+	switch {
+	case apre == bpre:
+		return 0, nil
+	case apre == "":
+		return 1, nil
+	case bpre == "":
+		return -1, nil
+	}
+	return strings.Compare(apre, bpre), nil
+}
+
+// parseVersion splits a plugin version into its numeric parts and its
+// pre-release suffix. Build metadata after "+" is ignored.
+func parseVersion(v string) ([3]int, string, error) {
+	var nums [3]int
+	core := strings.TrimPrefix(strings.TrimSpace(v), "v")
+	core, _, _ = strings.Cut(core, "+")
+	core, pre, _ := strings.Cut(core, "-")
+	parts := strings.Split(core, ".")
+	if len(parts) > 3 {
+		return nums, "", fmt.Errorf("invalid plugin version %q", v)
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nums, "", fmt.Errorf("invalid plugin version %q", v)
+		}
+		nums[i] = n
+	}
+	return nums, pre, nil
+}
@@ -0,0 +1,69 @@
+package plugin
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jordanhubbard/loom/pkg/plugin"
+)
+
+func TestCheckAPIVersion(t *testing.T) {
+	tests := []struct {
+		plugin, host string
+		wantErr      string
+	}{
+		{"1.0.0", "1.0.0", ""},
+		{"v1.2.0", "1.0.0", ""}, // Minor mismatch only warns
+		{"1", "1.3.0", ""},
+		{"", "1.0.0", ""}, // Omitted: deprecated but allowed
+		{"0.9.0", "1.0.0", ""},
+		{"2.0.0", "1.0.0", "plugin p requires API v2, host supports v1"},
+		{"two", "1.0.0", "invalid plugin API version"},
+	}
+	for _, tt := range tests {
+		err := checkAPIVersion("p", tt.plugin, tt.host)
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("%q on %q: unexpected error %v", tt.plugin, tt.host, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("%q on %q: expected %q, got %v", tt.plugin, tt.host, tt.wantErr, err)
+		}
+	}
+}
+
+func TestLoadPlugin_RejectsNewerAPIVersion(t *testing.T) {
+	manifest := &PluginManifest{
+		Type:     "http",
+		Endpoint: "http://127.0.0.1:1",
+		Metadata: &plugin.Metadata{Name: "Future", ProviderType: "future", PluginAPIVersion: "2.0.0"},
+	}
+	err := NewLoader(t.TempDir()).LoadPlugin(context.Background(), manifest)
+	if err == nil || !strings.Contains(err.Error(), "requires API v2, host supports v1") {
+		t.Errorf("expected API version error, got %v", err)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0.0", "1.0.0", 0},
+		{"1.1.0", "1.0.9", 1},
+		{"1.2", "1.10.0", -1},
+		{"v2.0.0", "1.9.9", 1},
+		{"1.0.0-rc1", "1.0.0", -1},
+		{"1.0.0-rc2", "1.0.0-rc1", 1},
+		{"1.0.0+build5", "1.0.0", 0},
+	}
+	for _, tt := range tests {
+		got, err := compareVersions(tt.a, tt.b)
+		if err != nil || got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, %v; want %d", tt.a, tt.b, got, err, tt.want)
+		}
+	}
+	if _, err := compareVersions("latest", "1.0.0"); err == nil {
+		t.Error("compareVersions should reject a non-numeric version")
+	}
+}
@@ -6,9 +6,12 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/jordanhubbard/loom/internal/messagebus"
@@ -21,16 +24,42 @@ type Config struct {
 	ControlPlaneURL   string
 	WorkDir           string
 	HeartbeatInterval time.Duration
-	NatsURL           string // NATS server URL (optional, for NATS-based communication)
+	NatsURL           string   // NATS server URL (optional, for NATS-based communication)
+	MaxConcurrent     int      // Tasks run at once; 0 means 1
+	QueueSize         int      // Accepted tasks that may wait to run; 0 means DefaultQueueSize
+	IsolationMode     string   // How bash tasks are isolated: none (default), chroot or container
+	IsolationImage    string   // Image for container isolation; empty means DefaultIsolationImage
+	MaxMemoryMB       int      // Memory a bash task may use; 0 means unlimited
+	MaxCPUSeconds     int      // CPU time a bash task may use; 0 means unlimited
+	ReadCacheMB       int      // File content read tasks may keep cached; 0 means DefaultReadCacheMB
+	AllowedCommands   []string // Programs bash tasks may run; empty allows any
+	DeniedCommands    []string // Programs bash tasks may never run
 }
 
+// DefaultTaskTimeout bounds a task that does not set TimeoutSeconds
+const DefaultTaskTimeout = 10 * time.Minute
+
+// DefaultQueueSize is how many accepted tasks may wait for a free slot
+const DefaultQueueSize = 64
+
+// commandWaitDelay is how long a killed command may hold its output pipes
+// open before they are closed
+const commandWaitDelay = 5 * time.Second
+
 // Agent is a lightweight agent that runs inside a project container
 type Agent struct {
 	config       Config
 	httpClient   *http.Client
-	currentTask  *TaskExecution
+	taskMu       sync.Mutex
+	tasks        map[string]*TaskExecution // Running tasks by task ID
+	queueOnce    sync.Once
+	queue        chan func() // Accepted tasks waiting for a runner; see queue.go
 	taskResultCh chan *TaskResult
 	messageBus   *messagebus.NatsMessageBus // NATS client for async communication
+
+	containerRuntime string    // docker or podman, for container isolation
+	limitCgroup      string    // cgroup that bash tasks are limited under; see limits.go
+	files            fileCache // Content of files read tasks returned; see filecache.go
 }
 
 // TaskRequest represents a task sent from the control plane
@@ -40,6 +69,8 @@ type TaskRequest struct {
 	Action    string                 `json:"action"`
 	ProjectID string                 `json:"project_id"`
 	Params    map[string]interface{} `json:"params"`
+	// TimeoutSeconds bounds the task's run time; 0 uses DefaultTaskTimeout
+	TimeoutSeconds int `json:"timeout_seconds,omitempty"`
 }
 
 // TaskResult represents the result of executing a task
@@ -53,10 +84,11 @@ type TaskResult struct {
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
 }
 
-// TaskExecution tracks the currently executing task
+// TaskExecution tracks an executing task
 type TaskExecution struct {
 	Request   *TaskRequest
 	StartTime time.Time
+	Timeout   time.Duration
 	Context   context.Context
 	Cancel    context.CancelFunc
 }
@@ -79,12 +111,37 @@ func New(config Config) (*Agent, error) {
 		config.HeartbeatInterval = 30 * time.Second
 	}
 
+	if config.IsolationMode == "" {
+		config.IsolationMode = IsolationNone
+	}
+	if config.IsolationMode == IsolationContainer {
+		// Container runtimes only mount absolute paths
+		workDir, err := filepath.Abs(config.WorkDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve work dir: %w", err)
+		}
+		config.WorkDir = workDir
+		if config.IsolationImage == "" {
+			config.IsolationImage = DefaultIsolationImage
+		}
+	}
+	containerRuntime, err := checkIsolation(config)
+	if err != nil {
+		return nil, err
+	}
+	limitCgroup, err := checkResourceLimits(config)
+	if err != nil {
+		return nil, err
+	}
+
 	agent := &Agent{
 		config: config,
 		httpClient: &http.Client{
 			Timeout: 60 * time.Second,
 		},
-		taskResultCh: make(chan *TaskResult, 10),
+		taskResultCh:     make(chan *TaskResult, 10),
+		containerRuntime: containerRuntime,
+		limitCgroup:      limitCgroup,
 	}
 
 	// Initialize NATS if URL is provided
@@ -149,6 +206,7 @@ func (a *Agent) Start(ctx context.Context) error {
 func (a *Agent) RegisterHandlers(mux *http.ServeMux) {
 	mux.HandleFunc("/health", a.handleHealth)
 	mux.HandleFunc("/task", a.handleTask)
+	mux.HandleFunc("/task/cancel", a.handleCancelTask)
 	mux.HandleFunc("/status", a.handleStatus)
 }
 
@@ -181,10 +239,19 @@ func (a *Agent) handleTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	log.Printf("Received task: %s (bead: %s, action: %s)", req.TaskID, req.BeadID, req.Action)
+	// Reject paths outside the workspace before accepting the task
+	if err := a.validateTaskPath(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Queue the task for execution; reject it when the queue is full
+	if !a.enqueue(func() { a.executeTask(&req) }) {
+		http.Error(w, "Task queue is full", http.StatusServiceUnavailable)
+		return
+	}
 
-	// Execute task asynchronously
-	go a.executeTask(&req)
+	log.Printf("Received task: %s (bead: %s, action: %s)", req.TaskID, req.BeadID, req.Action)
 
 	// Return accepted status immediately
 	w.WriteHeader(http.StatusAccepted)
@@ -198,44 +265,141 @@ func (a *Agent) handleTask(w http.ResponseWriter, r *http.Request) {
 func (a *Agent) handleStatus(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
+	running := a.runningTasks()
 	status := map[string]interface{}{
-		"project_id": a.config.ProjectID,
-		"work_dir":   a.config.WorkDir,
-		"busy":       a.currentTask != nil,
-	}
-
-	if a.currentTask != nil {
-		status["current_task"] = map[string]interface{}{
-			"task_id":  a.currentTask.Request.TaskID,
-			"bead_id":  a.currentTask.Request.BeadID,
-			"action":   a.currentTask.Request.Action,
-			"duration": time.Since(a.currentTask.StartTime).String(),
+		"project_id":     a.config.ProjectID,
+		"work_dir":       a.config.WorkDir,
+		"busy":           len(running) > 0,
+		"running":        len(running),
+		"queue_depth":    a.queueDepth(),
+		"max_concurrent": a.maxConcurrent(),
+		"file_cache":     a.files.stats(),
+	}
+
+	if len(running) > 0 {
+		tasks := make([]map[string]interface{}, len(running))
+		for i, task := range running {
+			tasks[i] = map[string]interface{}{
+				"task_id":  task.Request.TaskID,
+				"bead_id":  task.Request.BeadID,
+				"action":   task.Request.Action,
+				"duration": time.Since(task.StartTime).String(),
+			}
 		}
+		// current_task is the longest-running task
+		status["current_task"] = tasks[0]
+		status["running_tasks"] = tasks
 	}
 
 	json.NewEncoder(w).Encode(status)
 }
 
-// executeTask executes a task in the project's working directory
-func (a *Agent) executeTask(req *TaskRequest) {
-	startTime := time.Now()
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
-	defer cancel()
+// handleCancelTask cancels the in-flight task with the given task ID
+func (a *Agent) handleCancelTask(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
 
-	a.currentTask = &TaskExecution{
+	var req struct {
+		TaskID string `json:"task_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+	if req.TaskID == "" {
+		http.Error(w, "task_id is required", http.StatusBadRequest)
+		return
+	}
+
+	if !a.cancelTask(req.TaskID) {
+		http.Error(w, "Task not running", http.StatusNotFound)
+		return
+	}
+
+	log.Printf("Cancelled task: %s", req.TaskID)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"status":  "cancelled",
+		"task_id": req.TaskID,
+	})
+}
+
+// cancelTask cancels the running task with the given ID, reporting whether
+// a task was cancelled
+func (a *Agent) cancelTask(taskID string) bool {
+	a.taskMu.Lock()
+	defer a.taskMu.Unlock()
+	task, ok := a.tasks[taskID]
+	if !ok {
+		return false
+	}
+	task.Cancel()
+	return true
+}
+
+// runningTasks returns the running tasks, longest-running first
+func (a *Agent) runningTasks() []*TaskExecution {
+	a.taskMu.Lock()
+	tasks := make([]*TaskExecution, 0, len(a.tasks))
+	for _, task := range a.tasks {
+		tasks = append(tasks, task)
+	}
+	a.taskMu.Unlock()
+
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].StartTime.Before(tasks[j].StartTime) })
+	return tasks
+}
+
+// runningTask returns the longest-running task, or nil when idle
+func (a *Agent) runningTask() *TaskExecution {
+	if tasks := a.runningTasks(); len(tasks) > 0 {
+		return tasks[0]
+	}
+	return nil
+}
+
+// startTask records req as a running task under its deadline. The returned
+// func must be called when the task finishes.
+func (a *Agent) startTask(req *TaskRequest) (*TaskExecution, func()) {
+	timeout := DefaultTaskTimeout
+	if req.TimeoutSeconds > 0 {
+		timeout = time.Duration(req.TimeoutSeconds) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+
+	task := &TaskExecution{
 		Request:   req,
-		StartTime: startTime,
+		StartTime: time.Now(),
+		Timeout:   timeout,
 		Context:   ctx,
 		Cancel:    cancel,
 	}
-	defer func() { a.currentTask = nil }()
 
-	result := &TaskResult{
-		TaskID: req.TaskID,
-		BeadID: req.BeadID,
+	a.taskMu.Lock()
+	if a.tasks == nil {
+		a.tasks = make(map[string]*TaskExecution)
+	}
+	a.tasks[req.TaskID] = task
+	a.taskMu.Unlock()
+
+	return task, func() {
+		cancel()
+		a.taskMu.Lock()
+		if a.tasks[req.TaskID] == task {
+			delete(a.tasks, req.TaskID)
+		}
+		a.taskMu.Unlock()
 	}
+}
+
+// runAction dispatches the task's action under the execution's context. A
+// deadline or cancellation replaces the command's error with one saying so.
+func (a *Agent) runAction(task *TaskExecution) (string, error) {
+	ctx := task.Context
+	req := task.Request
 
-	// Execute action based on type
 	var output string
 	var err error
 
@@ -252,16 +416,42 @@ func (a *Agent) executeTask(req *TaskRequest) {
 		output, err = a.executeWrite(ctx, req.Params)
 	case "scope":
 		output, err = a.executeScope(ctx, req.Params)
+	case "apply_patch":
+		output, err = a.executeApplyPatch(ctx, req.Params)
+	case "create_bead":
+		output, err = a.executeCreateBead(ctx, req.TaskID, req.Params)
 	default:
 		err = fmt.Errorf("unsupported action: %s", req.Action)
 	}
 
-	result.Duration = time.Since(startTime)
+	switch ctx.Err() {
+	case context.DeadlineExceeded:
+		err = &LimitExceededError{Limit: LimitTime, Value: task.Timeout.String()}
+	case context.Canceled:
+		err = fmt.Errorf("task cancelled")
+	}
+	return output, err
+}
+
+// executeTask executes a task in the project's working directory
+func (a *Agent) executeTask(req *TaskRequest) {
+	task, done := a.startTask(req)
+	defer done()
+
+	result := &TaskResult{
+		TaskID: req.TaskID,
+		BeadID: req.BeadID,
+	}
+
+	output, err := a.runAction(task)
+
+	result.Duration = time.Since(task.StartTime)
 	result.Success = (err == nil)
 	result.Output = output
 
 	if err != nil {
 		result.Error = err.Error()
+		result.Metadata = limitContext(err)
 		log.Printf("Task %s failed: %v", req.TaskID, err)
 	} else {
 		log.Printf("Task %s completed successfully in %v", req.TaskID, result.Duration)
@@ -271,18 +461,30 @@ func (a *Agent) executeTask(req *TaskRequest) {
 	a.taskResultCh <- result
 }
 
-// executeBash executes a bash command in the work directory
+// newCommand builds a command that is killed along with its children when
+// ctx ends
+func newCommand(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	setProcessGroup(cmd)
+	cmd.WaitDelay = commandWaitDelay
+	return cmd
+}
+
+// executeBash executes a bash command in the work directory, isolated and
+// resource limited as configured, unless the command policy forbids it. Git
+// actions always run on the host, where credentials live.
 func (a *Agent) executeBash(ctx context.Context, params map[string]interface{}) (string, error) {
 	command, ok := params["command"].(string)
 	if !ok {
 		return "", fmt.Errorf("command parameter required")
 	}
 
-	cmd := exec.CommandContext(ctx, "bash", "-c", command)
-	cmd.Dir = a.config.WorkDir
+	if err := a.checkCommandPolicy(command); err != nil {
+		return "", err
+	}
 
-	output, err := cmd.CombinedOutput()
-	return string(output), err
+	cmd := a.shellCommand(ctx, command)
+	return a.runLimited(cmd)
 }
 
 // executeGitCommit creates a git commit
@@ -293,14 +495,14 @@ func (a *Agent) executeGitCommit(ctx context.Context, params map[string]interfac
 	}
 
 	// Git add
-	addCmd := exec.CommandContext(ctx, "git", "add", "-A")
+	addCmd := newCommand(ctx, "git", "add", "-A")
 	addCmd.Dir = a.config.WorkDir
 	if output, err := addCmd.CombinedOutput(); err != nil {
 		return string(output), fmt.Errorf("git add failed: %w", err)
 	}
 
 	// Git commit
-	commitCmd := exec.CommandContext(ctx, "git", "commit", "-m", message)
+	commitCmd := newCommand(ctx, "git", "commit", "-m", message)
 	commitCmd.Dir = a.config.WorkDir
 	output, err := commitCmd.CombinedOutput()
 	return string(output), err
@@ -308,22 +510,37 @@ func (a *Agent) executeGitCommit(ctx context.Context, params map[string]interfac
 
 // executeGitPush pushes commits to remote
 func (a *Agent) executeGitPush(ctx context.Context, params map[string]interface{}) (string, error) {
-	pushCmd := exec.CommandContext(ctx, "git", "push")
+	pushCmd := newCommand(ctx, "git", "push")
 	pushCmd.Dir = a.config.WorkDir
 	output, err := pushCmd.CombinedOutput()
 	return string(output), err
 }
 
-// executeRead reads a file from the project
+// executeRead reads a file from the project, from the read cache when the
+// file is unchanged since it was last read
 func (a *Agent) executeRead(ctx context.Context, params map[string]interface{}) (string, error) {
 	path, ok := params["path"].(string)
 	if !ok {
 		return "", fmt.Errorf("path parameter required")
 	}
 
-	fullPath := filepath.Join(a.config.WorkDir, path)
-	cmd := exec.CommandContext(ctx, "cat", fullPath)
+	fullPath, err := a.resolveSafePath(path)
+	if err != nil {
+		return "", err
+	}
+	info, statErr := os.Stat(fullPath)
+	cacheable := statErr == nil && info.Mode().IsRegular()
+	if cacheable {
+		if content, ok := a.files.get(fullPath, info); ok {
+			return content, nil
+		}
+	}
+
+	cmd := newCommand(ctx, "cat", fullPath)
 	output, err := cmd.CombinedOutput()
+	if err == nil && cacheable {
+		a.files.put(fullPath, info, string(output), a.readCacheBytes())
+	}
 	return string(output), err
 }
 
@@ -339,8 +556,13 @@ func (a *Agent) executeWrite(ctx context.Context, params map[string]interface{})
 		return "", fmt.Errorf("content parameter required")
 	}
 
-	fullPath := filepath.Join(a.config.WorkDir, path)
-	cmd := exec.CommandContext(ctx, "bash", "-c", fmt.Sprintf("cat > %s", fullPath))
+	fullPath, err := a.resolveSafePath(path)
+	if err != nil {
+		return "", err
+	}
+	defer a.files.invalidate(fullPath)
+	// Pass the path as an argument so it is never interpreted by the shell
+	cmd := newCommand(ctx, "bash", "-c", `cat > "$1"`, "bash", fullPath)
 	cmd.Stdin = strings.NewReader(content)
 	cmd.Dir = a.config.WorkDir
 
@@ -355,12 +577,44 @@ func (a *Agent) executeScope(ctx context.Context, params map[string]interface{})
 		path = p
 	}
 
-	fullPath := filepath.Join(a.config.WorkDir, path)
-	cmd := exec.CommandContext(ctx, "ls", "-la", fullPath)
+	fullPath, err := a.resolveSafePath(path)
+	if err != nil {
+		return "", err
+	}
+	cmd := newCommand(ctx, "ls", "-la", fullPath)
 	output, err := cmd.CombinedOutput()
 	return string(output), err
 }
 
+// resolveSafePath joins a workspace-relative path onto WorkDir, rejecting
+// absolute paths and paths that escape the workspace
+func (a *Agent) resolveSafePath(rel string) (string, error) {
+	if rel == "" {
+		rel = "."
+	}
+	clean := filepath.Clean(rel)
+	if filepath.IsAbs(clean) {
+		return "", fmt.Errorf("path must be relative to the workspace: %s", rel)
+	}
+	root := filepath.Clean(a.config.WorkDir)
+	joined := filepath.Join(root, clean)
+	if joined != root && !strings.HasPrefix(joined, root+string(filepath.Separator)) {
+		return "", fmt.Errorf("path escapes the workspace: %s", rel)
+	}
+	return joined, nil
+}
+
+// validateTaskPath checks the path param of file-touching actions
+func (a *Agent) validateTaskPath(req *TaskRequest) error {
+	switch req.Action {
+	case "read", "write", "scope", "apply_patch":
+		path, _ := req.Params["path"].(string)
+		_, err := a.resolveSafePath(path)
+		return err
+	}
+	return nil
+}
+
 // register announces the agent to the control plane
 func (a *Agent) register(ctx context.Context) error {
 	url := fmt.Sprintf("%s/api/v1/project-agents/register", a.config.ControlPlaneURL)
@@ -403,7 +657,7 @@ func (a *Agent) sendHeartbeat(ctx context.Context) error {
 
 	payload := map[string]interface{}{
 		"project_id": a.config.ProjectID,
-		"busy":       a.currentTask != nil,
+		"busy":       a.runningTask() != nil,
 		"timestamp":  time.Now().UTC().Format(time.RFC3339),
 	}
 
@@ -491,8 +745,12 @@ func (a *Agent) subscribeToTasks() error {
 			// TODO: Handle task updates
 			log.Printf("Received task update for bead %s", taskMsg.BeadID)
 		case "task.cancelled":
-			// TODO: Handle task cancellation
-			log.Printf("Received task cancellation for bead %s", taskMsg.BeadID)
+			// NATS tasks use the bead ID as their task ID
+			if a.cancelTask(taskMsg.BeadID) {
+				log.Printf("Cancelled task for bead %s", taskMsg.BeadID)
+			} else {
+				log.Printf("Received task cancellation for bead %s with no running task", taskMsg.BeadID)
+			}
 		default:
 			log.Printf("Unknown task message type: %s", taskMsg.Type)
 		}
@@ -514,46 +772,30 @@ func (a *Agent) handleNatsTask(taskMsg *messages.TaskMessage) {
 		},
 	}
 
-	// Execute task asynchronously (same as HTTP handler)
-	go a.executeTaskWithNats(req, taskMsg.CorrelationID)
+	// Queue the task for execution (same as HTTP handler)
+	if !a.enqueue(func() { a.executeTaskWithNats(req, taskMsg.CorrelationID) }) {
+		log.Printf("Task queue is full, rejecting NATS task for bead %s", taskMsg.BeadID)
+		if err := a.messageBus.PublishResult(context.Background(), req.ProjectID, messages.TaskFailed(
+			req.ProjectID,
+			req.BeadID,
+			a.config.ProjectID,
+			messages.ResultData{Status: "failure", Error: "task queue is full"},
+			taskMsg.CorrelationID,
+		)); err != nil {
+			log.Printf("Failed to publish result to NATS: %v", err)
+		}
+	}
 }
 
 // executeTaskWithNats executes a task and publishes result to NATS
 func (a *Agent) executeTaskWithNats(req *TaskRequest, correlationID string) {
-	startTime := time.Now()
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
-	defer cancel()
-
-	a.currentTask = &TaskExecution{
-		Request:   req,
-		StartTime: startTime,
-		Context:   ctx,
-		Cancel:    cancel,
-	}
-	defer func() { a.currentTask = nil }()
+	task, done := a.startTask(req)
+	defer done()
 
 	// Execute the task (reuse existing execution logic)
-	var output string
-	var err error
-
-	switch req.Action {
-	case "bash":
-		output, err = a.executeBash(ctx, req.Params)
-	case "git_commit":
-		output, err = a.executeGitCommit(ctx, req.Params)
-	case "git_push":
-		output, err = a.executeGitPush(ctx, req.Params)
-	case "read":
-		output, err = a.executeRead(ctx, req.Params)
-	case "write":
-		output, err = a.executeWrite(ctx, req.Params)
-	case "scope":
-		output, err = a.executeScope(ctx, req.Params)
-	default:
-		err = fmt.Errorf("unsupported action: %s", req.Action)
-	}
+	output, err := a.runAction(task)
 
-	duration := time.Since(startTime)
+	duration := time.Since(task.StartTime)
 
 	// Publish result to NATS
 	var resultMsg *messages.ResultMessage
@@ -568,6 +810,7 @@ func (a *Agent) executeTaskWithNats(req *TaskRequest, correlationID string) {
 				Output:   output,
 				Error:    err.Error(),
 				Duration: duration.Milliseconds(),
+				Context:  limitContext(err),
 			},
 			correlationID,
 		)
@@ -0,0 +1,282 @@
+package projectagent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestAgent(t *testing.T) *Agent {
+	t.Helper()
+	return &Agent{
+		config:       Config{ProjectID: "test-project", WorkDir: t.TempDir()},
+		taskResultCh: make(chan *TaskResult, 1),
+	}
+}
+
+func waitForResult(t *testing.T, agent *Agent) *TaskResult {
+	t.Helper()
+	select {
+	case result := <-agent.taskResultCh:
+		return result
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for task result")
+		return nil
+	}
+}
+
+func TestExecuteTask_Timeout(t *testing.T) {
+	agent := newTestAgent(t)
+
+	start := time.Now()
+	// The shell forks sleep, so only killing the process group ends it promptly
+	go agent.executeTask(&TaskRequest{
+		TaskID:         "task-1",
+		Action:         "bash",
+		Params:         map[string]interface{}{"command": "sleep 30; echo done"},
+		TimeoutSeconds: 1,
+	})
+
+	result := waitForResult(t, agent)
+	if result.Success {
+		t.Fatal("expected timed out task to fail")
+	}
+	if !strings.Contains(result.Error, "timed out") {
+		t.Errorf("expected timeout error, got %q", result.Error)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("task took %v to time out", elapsed)
+	}
+	if agent.runningTask() != nil {
+		t.Error("expected no current task after timeout")
+	}
+}
+
+func TestHandleCancelTask(t *testing.T) {
+	agent := newTestAgent(t)
+	mux := http.NewServeMux()
+	agent.RegisterHandlers(mux)
+
+	cancel := func(body string) int {
+		req := httptest.NewRequest(http.MethodPost, "/task/cancel", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := cancel(`{"task_id":"task-1"}`); code != http.StatusNotFound {
+		t.Errorf("expected 404 with no running task, got %d", code)
+	}
+	if code := cancel(`{}`); code != http.StatusBadRequest {
+		t.Errorf("expected 400 without task_id, got %d", code)
+	}
+
+	go agent.executeTask(&TaskRequest{
+		TaskID: "task-1",
+		Action: "bash",
+		Params: map[string]interface{}{"command": "sleep 30"},
+	})
+	deadline := time.Now().Add(5 * time.Second)
+	for agent.runningTask() == nil && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if code := cancel(`{"task_id":"other-task"}`); code != http.StatusNotFound {
+		t.Errorf("expected 404 for a different task ID, got %d", code)
+	}
+	if code := cancel(`{"task_id":"task-1"}`); code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+
+	result := waitForResult(t, agent)
+	if result.Success {
+		t.Fatal("expected cancelled task to fail")
+	}
+	if !strings.Contains(result.Error, "cancelled") {
+		t.Errorf("expected cancellation error, got %q", result.Error)
+	}
+}
+
+func TestStartTask_DefaultTimeout(t *testing.T) {
+	agent := newTestAgent(t)
+	task, done := agent.startTask(&TaskRequest{TaskID: "task-1"})
+	defer done()
+
+	if task.Timeout != DefaultTaskTimeout {
+		t.Errorf("expected default timeout %v, got %v", DefaultTaskTimeout, task.Timeout)
+	}
+	if _, ok := task.Context.Deadline(); !ok {
+		t.Error("expected task context to have a deadline")
+	}
+}
+
+func TestResolveSafePath(t *testing.T) {
+	agent := &Agent{config: Config{WorkDir: "/workspace"}}
+
+	tests := []struct {
+		rel     string
+		want    string
+		wantErr bool
+	}{
+		{rel: "", want: "/workspace"},
+		{rel: ".", want: "/workspace"},
+		{rel: "src/main.go", want: "/workspace/src/main.go"},
+		{rel: "src/../README.md", want: "/workspace/README.md"},
+		{rel: "../../../../etc/passwd", wantErr: true},
+		{rel: "src/../../etc/passwd", wantErr: true},
+		{rel: "..", wantErr: true},
+		{rel: "../workspace-other/file", wantErr: true},
+		{rel: "/etc/passwd", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := agent.resolveSafePath(tt.rel)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("resolveSafePath(%q) = %q, expected error", tt.rel, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("resolveSafePath(%q) error = %v", tt.rel, err)
+		} else if got != tt.want {
+			t.Errorf("resolveSafePath(%q) = %q, want %q", tt.rel, got, tt.want)
+		}
+	}
+}
+
+func TestFileExecutors_RejectEscapes(t *testing.T) {
+	root := t.TempDir()
+	workDir := filepath.Join(root, "workspace")
+	if err := os.Mkdir(workDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	secret := filepath.Join(root, "secret.txt")
+	if err := os.WriteFile(secret, []byte("top secret"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	agent := &Agent{config: Config{WorkDir: workDir}}
+	ctx := context.Background()
+
+	for _, path := range []string{"../secret.txt", secret} {
+		if out, err := agent.executeRead(ctx, map[string]interface{}{"path": path}); err == nil {
+			t.Errorf("executeRead(%q) succeeded with output %q", path, out)
+		}
+		if _, err := agent.executeScope(ctx, map[string]interface{}{"path": path}); err == nil {
+			t.Errorf("executeScope(%q) succeeded", path)
+		}
+		if _, err := agent.executeWrite(ctx, map[string]interface{}{"path": path, "content": "overwritten"}); err == nil {
+			t.Errorf("executeWrite(%q) succeeded", path)
+		}
+	}
+
+	data, err := os.ReadFile(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "top secret" {
+		t.Errorf("file outside workspace was modified: %q", data)
+	}
+
+	// Paths inside the workspace still work
+	if _, err := agent.executeWrite(ctx, map[string]interface{}{"path": "notes.txt", "content": "hello"}); err != nil {
+		t.Fatalf("executeWrite inside workspace failed: %v", err)
+	}
+	if out, err := agent.executeRead(ctx, map[string]interface{}{"path": "notes.txt"}); err != nil || out != "hello" {
+		t.Errorf("executeRead inside workspace = %q, %v", out, err)
+	}
+}
+
+func TestHandleTask_RejectsPathEscape(t *testing.T) {
+	agent := newTestAgent(t)
+	mux := http.NewServeMux()
+	agent.RegisterHandlers(mux)
+
+	body := `{"task_id":"task-1","project_id":"test-project","action":"read","params":{"path":"../../etc/passwd"}}`
+	req := httptest.NewRequest(http.MethodPost, "/task", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+	if agent.runningTask() != nil {
+		t.Error("rejected task should not run")
+	}
+}
+
+func TestHandleTask_QueueAndConcurrency(t *testing.T) {
+	agent := newTestAgent(t)
+	agent.config.MaxConcurrent = 2
+	agent.config.QueueSize = 1
+	agent.taskResultCh = make(chan *TaskResult, 4)
+	mux := http.NewServeMux()
+	agent.RegisterHandlers(mux)
+
+	submit := func(id string) int {
+		body := `{"task_id":"` + id + `","project_id":"test-project","action":"bash","params":{"command":"sleep 30"}}`
+		req := httptest.NewRequest(http.MethodPost, "/task", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w.Code
+	}
+	status := func() map[string]interface{} {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/status", nil))
+		var s map[string]interface{}
+		if err := json.NewDecoder(w.Body).Decode(&s); err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	waitRunning := func(n int) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for len(agent.runningTasks()) != n {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected %d running tasks, got %d", n, len(agent.runningTasks()))
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	for i, id := range []string{"task-1", "task-2"} {
+		if code := submit(id); code != http.StatusAccepted {
+			t.Fatalf("submit %s: expected 202, got %d", id, code)
+		}
+		waitRunning(i + 1)
+	}
+
+	if code := submit("task-3"); code != http.StatusAccepted {
+		t.Fatalf("expected task-3 to queue, got %d", code)
+	}
+	if code := submit("task-4"); code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 with a full queue, got %d", code)
+	}
+
+	s := status()
+	if s["running"] != float64(2) || s["queue_depth"] != float64(1) || s["max_concurrent"] != float64(2) {
+		t.Errorf("status = %v, want 2 running and queue depth 1", s)
+	}
+	if tasks, _ := s["running_tasks"].([]interface{}); len(tasks) != 2 {
+		t.Errorf("running_tasks = %v, want 2 entries", s["running_tasks"])
+	}
+
+	// Finishing a task lets the queued one run
+	agent.cancelTask("task-1")
+	waitForResult(t, agent)
+	waitRunning(2)
+	if s := status(); s["queue_depth"] != float64(0) {
+		t.Errorf("queue_depth = %v after a slot freed, want 0", s["queue_depth"])
+	}
+	for _, id := range []string{"task-2", "task-3"} {
+		agent.cancelTask(id)
+		waitForResult(t, agent)
+	}
+}
@@ -0,0 +1,96 @@
+package projectagent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// DefaultBeadPriority is the priority of beads created without one (P2)
+const DefaultBeadPriority = 2
+
+// beadTypes are the bead types the create_bead action accepts
+var beadTypes = []string{"task", "bug", "feature", "epic", "decision"}
+
+// executeCreateBead files a bead in the agent's project through the control
+// plane's bead API and returns the new bead's ID. The task ID keys the
+// request, so a task that is delivered twice creates one bead.
+func (a *Agent) executeCreateBead(ctx context.Context, taskID string, params map[string]interface{}) (string, error) {
+	title, _ := params["title"].(string)
+	if strings.TrimSpace(title) == "" {
+		return "", fmt.Errorf("title parameter required")
+	}
+	beadType, _ := params["type"].(string)
+	if beadType == "" {
+		return "", fmt.Errorf("type parameter required")
+	}
+	if !isBeadType(beadType) {
+		return "", fmt.Errorf("unknown bead type %q (want one of %s)", beadType, strings.Join(beadTypes, ", "))
+	}
+	priority := DefaultBeadPriority
+	if p, ok := params["priority"]; ok {
+		n, ok := p.(float64)
+		if !ok || n != float64(int(n)) || n < 0 || n > 4 {
+			return "", fmt.Errorf("priority must be an integer from 0 to 4")
+		}
+		priority = int(n)
+	}
+	if project, _ := params["project_id"].(string); project != "" && project != a.config.ProjectID {
+		return "", fmt.Errorf("beads can only be created in project %s", a.config.ProjectID)
+	}
+	description, _ := params["description"].(string)
+
+	payload := map[string]interface{}{
+		"title":       title,
+		"description": description,
+		"type":        beadType,
+		"priority":    priority,
+		"project_id":  a.config.ProjectID,
+	}
+	if taskID != "" {
+		payload["idempotency_key"] = fmt.Sprintf("project-agent:%s:%s", a.config.ProjectID, taskID)
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	url := fmt.Sprintf("%s/api/v1/beads", a.config.ControlPlaneURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(string(body)))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to create bead: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return "", fmt.Errorf("bead creation failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var bead struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&bead); err != nil || bead.ID == "" {
+		return "", fmt.Errorf("bead creation returned no bead ID")
+	}
+	return fmt.Sprintf("Created bead %s\n", bead.ID), nil
+}
+
+// isBeadType reports whether t is a bead type create_bead accepts
+func isBeadType(t string) bool {
+	for _, known := range beadTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package projectagent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newBeadAPI starts a fake control plane bead API that records the bodies of
+// create requests and answers with the given bead ID
+func newBeadAPI(t *testing.T, agent *Agent, beadID string) *[]map[string]interface{} {
+	t.Helper()
+	var requests []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/beads" {
+			http.NotFound(w, r)
+			return
+		}
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, "bad body", http.StatusBadRequest)
+			return
+		}
+		requests = append(requests, body)
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]string{"id": beadID, "title": body["title"].(string)})
+	}))
+	t.Cleanup(server.Close)
+	agent.config.ControlPlaneURL = server.URL
+	agent.httpClient = server.Client()
+	return &requests
+}
+
+func TestExecuteCreateBead(t *testing.T) {
+	agent := newTestAgent(t)
+	requests := newBeadAPI(t, agent, "bd-42")
+
+	output, err := agent.executeCreateBead(context.Background(), "task-1", map[string]interface{}{
+		"title":       "[CEO] Code Fix Approval: fix parsing",
+		"description": "proposal",
+		"type":        "decision",
+		"priority":    float64(0),
+	})
+	if err != nil {
+		t.Fatalf("executeCreateBead: %v", err)
+	}
+	if !strings.Contains(output, "bd-42") {
+		t.Errorf("output = %q, want the new bead ID", output)
+	}
+
+	if len(*requests) != 1 {
+		t.Fatalf("expected 1 create request, got %d", len(*requests))
+	}
+	got := (*requests)[0]
+	want := map[string]interface{}{
+		"title":           "[CEO] Code Fix Approval: fix parsing",
+		"description":     "proposal",
+		"type":            "decision",
+		"priority":        float64(0),
+		"project_id":      "test-project",
+		"idempotency_key": "project-agent:test-project:task-1",
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("request %s = %v, want %v", key, got[key], value)
+		}
+	}
+
+	// Priority defaults to P2
+	if _, err := agent.executeCreateBead(context.Background(), "task-2", map[string]interface{}{"title": "t", "type": "task"}); err != nil {
+		t.Fatalf("executeCreateBead: %v", err)
+	}
+	if p := (*requests)[1]["priority"]; p != float64(DefaultBeadPriority) {
+		t.Errorf("default priority = %v, want %d", p, DefaultBeadPriority)
+	}
+}
+
+func TestExecuteCreateBead_Rejects(t *testing.T) {
+	agent := newTestAgent(t)
+	requests := newBeadAPI(t, agent, "bd-1")
+
+	tests := []struct {
+		name   string
+		params map[string]interface{}
+		want   string
+	}{
+		{"missing title", map[string]interface{}{"type": "task"}, "title parameter required"},
+		{"missing type", map[string]interface{}{"title": "t"}, "type parameter required"},
+		{"unknown type", map[string]interface{}{"title": "t", "type": "chore"}, "unknown bead type"},
+		{"bad priority", map[string]interface{}{"title": "t", "type": "task", "priority": float64(7)}, "priority must be"},
+		{"other project", map[string]interface{}{"title": "t", "type": "task", "project_id": "other"}, "only be created in project test-project"},
+	}
+	for _, tt := range tests {
+		if _, err := agent.executeCreateBead(context.Background(), "task-1", tt.params); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+	if len(*requests) != 0 {
+		t.Errorf("expected no create requests for rejected params, got %d", len(*requests))
+	}
+}
+
+func TestExecuteCreateBead_ControlPlaneError(t *testing.T) {
+	agent := newTestAgent(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "project not found", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	agent.config.ControlPlaneURL = server.URL
+	agent.httpClient = server.Client()
+
+	_, err := agent.executeCreateBead(context.Background(), "task-1", map[string]interface{}{"title": "t", "type": "task"})
+	if err == nil || !strings.Contains(err.Error(), "status 500: project not found") {
+		t.Errorf("error = %v, want the control plane's error", err)
+	}
+}
+
+func TestHandleTask_CreateBead(t *testing.T) {
+	agent := newTestAgent(t)
+	newBeadAPI(t, agent, "bd-7")
+	mux := http.NewServeMux()
+	agent.RegisterHandlers(mux)
+
+	body := `{"task_id":"task-1","project_id":"test-project","action":"create_bead","params":{"title":"Approve fix","type":"decision"}}`
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/task", strings.NewReader(body)))
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d", w.Code)
+	}
+
+	result := waitForResult(t, agent)
+	if !result.Success || !strings.Contains(result.Output, "bd-7") {
+		t.Errorf("result = %+v, want the new bead ID", result)
+	}
+}
@@ -0,0 +1,485 @@
+package projectagent
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// shellKeywords are words that may lead a simple command without being the
+// program it runs
+var shellKeywords = map[string]bool{
+	"!": true, "{": true, "}": true, "if": true, "then": true, "else": true, "elif": true,
+	"fi": true, "do": true, "done": true, "while": true, "until": true, "time": true,
+	"exec": true, "command": true, "builtin": true, "nohup": true,
+}
+
+// optionKeywords are the shellKeywords that take options before the program
+// they run, such as command -p and time -p. exec -a takes the next word as
+// the name to run the program under.
+var optionKeywords = map[string]bool{
+	"time": true, "exec": true, "command": true, "builtin": true,
+}
+
+// commandWrappers are programs that run the program named in their
+// arguments. Each lists its short options that take the next word as an
+// argument, its long options that do unless given with =, and the operands
+// that come before the program, such as timeout's duration.
+var commandWrappers = map[string]struct {
+	argOptions     string
+	longArgOptions []string
+	operands       int
+}{
+	"env":     {argOptions: "uCS", longArgOptions: []string{"--unset", "--chdir", "--split-string"}},
+	"timeout": {argOptions: "sk", longArgOptions: []string{"--signal", "--kill-after"}, operands: 1},
+	"nice":    {argOptions: "n", longArgOptions: []string{"--adjustment"}},
+	"xargs": {argOptions: "adEILnPs", longArgOptions: []string{
+		"--arg-file", "--delimiter", "--max-lines", "--max-args", "--max-procs", "--max-chars", "--process-slot-var",
+	}},
+	"sudo": {argOptions: "CDgpRrtUu", longArgOptions: []string{
+		"--close-from", "--chdir", "--group", "--prompt", "--chroot", "--role", "--type", "--other-user", "--user", "--command-timeout",
+	}},
+	"stdbuf": {argOptions: "ioe", longArgOptions: []string{"--input", "--output", "--error"}},
+}
+
+// commandShells run the command string given with -c
+var commandShells = map[string]bool{
+	"sh": true, "bash": true, "dash": true, "zsh": true, "ksh": true,
+}
+
+// maxPolicyDepth bounds how deeply sh -c and eval strings are followed
+const maxPolicyDepth = 8
+
+// checkCommandPolicy rejects a bash command that runs a program on the
+// agent's denylist, or one missing from its allowlist when that is not
+// empty. Every command of a pipeline, list or substitution is checked, as
+// is the program run by a wrapper such as env, sudo or xargs and the
+// command string given to sh -c or eval.
+//
+// The check is advisory. A program can still run another one in ways the
+// command line does not show, such as a script or find -exec, so the
+// denylist cannot be made complete; an allowlist of the programs a project
+// needs is the stronger policy.
+func (a *Agent) checkCommandPolicy(command string) error {
+	allowed, denied := a.config.AllowedCommands, a.config.DeniedCommands
+	if len(allowed) == 0 && len(denied) == 0 {
+		return nil
+	}
+	return checkCommands(command, allowed, denied, 0)
+}
+
+func checkCommands(command string, allowed, denied []string, depth int) error {
+	if depth > maxPolicyDepth {
+		return fmt.Errorf("command nests shells too deeply to check against the command policy")
+	}
+	for _, segment := range commandSegments(command) {
+		words := shellWords(segment)
+		for i := programIndex(words, 0); i >= 0; {
+			name := unquoteWord(words[i])
+			if err := checkProgram(name, allowed, denied); err != nil {
+				return err
+			}
+			base := filepath.Base(name)
+			script, next := "", -1
+			switch {
+			case commandShells[base]:
+				script = shellScript(words[i+1:])
+			case base == "eval":
+				args := make([]string, 0, len(words)-i-1)
+				for _, w := range words[i+1:] {
+					args = append(args, unquoteWord(w))
+				}
+				script = strings.Join(args, " ")
+			default:
+				if _, ok := commandWrappers[base]; ok {
+					script, next = wrappedProgram(base, words, i)
+				}
+			}
+			if script != "" {
+				if err := checkCommands(script, allowed, denied, depth+1); err != nil {
+					return err
+				}
+			}
+			i = next
+		}
+	}
+	return nil
+}
+
+// checkProgram checks one program name against the allowlist and denylist
+func checkProgram(name string, allowed, denied []string) error {
+	if strings.Contains(name, "$") {
+		return fmt.Errorf("command %q cannot be checked against the command policy: its name is computed when it runs", name)
+	}
+	for _, d := range denied {
+		// A denied program stays denied whatever path runs it
+		if filepath.Base(name) == filepath.Base(d) {
+			return fmt.Errorf("command %q is denied by the command policy", name)
+		}
+	}
+	if len(allowed) > 0 && !commandAllowed(name, allowed) {
+		return fmt.Errorf("command %q is not on the command allowlist", name)
+	}
+	return nil
+}
+
+// wrappedProgram finds what the wrapper at words[i] runs. It returns the
+// index of the program, or -1 when it names none, and for env -S the command
+// string it runs instead.
+func wrappedProgram(wrapper string, words []string, i int) (string, int) {
+	spec := commandWrappers[wrapper]
+	operands, optionsDone := spec.operands, false
+	for i++; i < len(words); i++ {
+		w := unquoteWord(words[i])
+		if !optionsDone {
+			switch {
+			case w == "--":
+				optionsDone = true
+				continue
+			case strings.HasPrefix(w, "--"):
+				long, value, hasValue := strings.Cut(w, "=")
+				takesArg := false
+				for _, o := range spec.longArgOptions {
+					takesArg = takesArg || long == o
+				}
+				if takesArg && !hasValue && i+1 < len(words) {
+					i++
+					value = unquoteWord(words[i])
+				}
+				if wrapper == "env" && long == "--split-string" {
+					return splitStringCommand(value, words[i+1:]), -1
+				}
+				if wrapper == "sudo" && long == "--edit" {
+					return "", -1
+				}
+				continue
+			case strings.HasPrefix(w, "-") && len(w) > 1:
+				for j := 1; j < len(w); j++ {
+					if wrapper == "sudo" && w[j] == 'e' {
+						// sudo -e edits files rather than running a program
+						return "", -1
+					}
+					if !strings.ContainsRune(spec.argOptions, rune(w[j])) {
+						continue
+					}
+					value := w[j+1:]
+					if value == "" && i+1 < len(words) {
+						i++
+						value = unquoteWord(words[i])
+					}
+					if wrapper == "env" && w[j] == 'S' {
+						return splitStringCommand(value, words[i+1:]), -1
+					}
+					break
+				}
+				continue
+			case wrapper == "env" && w == "-":
+				continue
+			case (wrapper == "env" || wrapper == "sudo") && isAssignment(w):
+				continue
+			}
+		}
+		if operands > 0 {
+			operands--
+			continue
+		}
+		return "", programIndex(words, i)
+	}
+	return "", -1
+}
+
+// splitStringCommand joins env -S's string with the words that follow it
+// into the command line it runs
+func splitStringCommand(value string, rest []string) string {
+	return strings.TrimSpace(value + " " + strings.Join(rest, " "))
+}
+
+// shellScript returns the command string a shell is given with -c, or ""
+// when it runs a script file or standard input instead
+func shellScript(args []string) string {
+	script := false
+	for i := 0; i < len(args); i++ {
+		w := unquoteWord(args[i])
+		switch {
+		case w == "--":
+			continue
+		case w == "-o" || w == "+o" || w == "-O" || w == "+O":
+			i++
+		case strings.HasPrefix(w, "--"):
+		case strings.HasPrefix(w, "-") || strings.HasPrefix(w, "+"):
+			script = script || strings.Contains(w[1:], "c")
+		case script:
+			return w
+		default:
+			return ""
+		}
+	}
+	return ""
+}
+
+// commandAllowed reports whether the allowlist names the program. An entry
+// given as a path allows that path and the bare name; a bare entry allows
+// only the bare name, so a script elsewhere cannot borrow it.
+func commandAllowed(name string, allowed []string) bool {
+	for _, a := range allowed {
+		if name == a || (!strings.Contains(name, "/") && name == filepath.Base(a)) {
+			return true
+		}
+	}
+	return false
+}
+
+// commandSegments splits a shell command line into the simple commands it
+// runs. Pipelines, && and || chains, ; and & lists, subshells and command
+// substitutions are all split apart; a substitution leaves "$" in its place
+// so a command whose name it computes can be recognized.
+func commandSegments(command string) []string {
+	type frame struct {
+		cur    strings.Builder
+		quote  byte // ' or " while inside quotes
+		closer byte // ) or ` that ends this substitution; 0 at the top
+	}
+	var segments []string
+	flush := func(f *frame) {
+		if s := strings.TrimSpace(f.cur.String()); s != "" {
+			segments = append(segments, s)
+		}
+		f.cur.Reset()
+	}
+
+	var heredocs []string // Delimiters of here-documents whose bodies start on the next line
+	stack := []*frame{{}}
+	for i := 0; i < len(command); i++ {
+		f := stack[len(stack)-1]
+		c := command[i]
+		var prev, next byte
+		if i > 0 {
+			prev = command[i-1]
+		}
+		if i+1 < len(command) {
+			next = command[i+1]
+		}
+
+		switch {
+		case f.quote == '\'':
+			if c == '\'' {
+				f.quote = 0
+			}
+			f.cur.WriteByte(c)
+		case c == '\\' && next != 0:
+			f.cur.WriteByte(c)
+			f.cur.WriteByte(next)
+			i++
+		case c == '$' && strings.HasPrefix(command[i:], "$(("):
+			// Arithmetic runs no command; keep it as a word
+			end := strings.Index(command[i:], "))")
+			if end < 0 {
+				end = len(command) - i - 2
+			}
+			f.cur.WriteString(command[i : i+end+2])
+			i += end + 1
+		case c == '$' && next == '(':
+			f.cur.WriteByte('$')
+			stack = append(stack, &frame{closer: ')'})
+			i++
+		case c == '`' && f.closer == '`':
+			flush(f)
+			stack = stack[:len(stack)-1]
+		case c == '`':
+			f.cur.WriteByte('$')
+			stack = append(stack, &frame{closer: '`'})
+		case f.quote == '"':
+			if c == '"' {
+				f.quote = 0
+			}
+			f.cur.WriteByte(c)
+		case c == '\'' || c == '"':
+			f.quote = c
+			f.cur.WriteByte(c)
+		case c == ')' && f.closer == ')':
+			flush(f)
+			stack = stack[:len(stack)-1]
+		case c == '#' && (f.cur.Len() == 0 || isShellSpace(prev)):
+			// A comment runs to the end of the line
+			for i+1 < len(command) && command[i+1] != '\n' {
+				i++
+			}
+		case c == '<' && strings.HasPrefix(command[i:], "<<") && !strings.HasPrefix(command[i:], "<<<"):
+			start := i
+			i += 2
+			if i < len(command) && command[i] == '-' {
+				i++
+			}
+			for i < len(command) && isShellSpace(command[i]) {
+				i++
+			}
+			end := i
+			for end < len(command) && !strings.ContainsRune(" \t\n;|&<>()", rune(command[end])) {
+				end++
+			}
+			heredocs = append(heredocs, unquoteWord(command[i:end]))
+			f.cur.WriteString(command[start:end])
+			i = end - 1
+		case c == '\n' && len(heredocs) > 0:
+			// Here-document bodies are data, not commands
+			flush(f)
+			for _, delim := range heredocs {
+				for i < len(command) {
+					end := strings.IndexByte(command[i+1:], '\n')
+					if end < 0 {
+						end = len(command) - i - 1
+					}
+					line := command[i+1 : i+1+end]
+					i += end + 1
+					if strings.TrimLeft(line, "\t") == delim {
+						break
+					}
+				}
+			}
+			heredocs = nil
+		case (c == '&' && (prev == '>' || prev == '<' || next == '>')) || (c == '|' && prev == '>'):
+			// Redirections such as 2>&1, &>file and >|file
+			f.cur.WriteByte(c)
+		case c == ';' || c == '|' || c == '&' || c == '\n' || c == '(' || c == ')':
+			flush(f)
+		default:
+			f.cur.WriteByte(c)
+		}
+	}
+	for len(stack) > 0 {
+		flush(stack[len(stack)-1])
+		stack = stack[:len(stack)-1]
+	}
+	return segments
+}
+
+// commandName returns the program a simple command runs, skipping shell
+// keywords and their options, variable assignments and redirections, with
+// quoting removed. It returns "" when the command runs no program.
+func commandName(segment string) string {
+	words := shellWords(segment)
+	if i := programIndex(words, 0); i >= 0 {
+		return unquoteWord(words[i])
+	}
+	return ""
+}
+
+// programIndex returns the index of the word naming the program the simple
+// command in words runs, starting the search at from, or -1 if it runs none
+func programIndex(words []string, from int) int {
+	keyword := "" // Keyword whose options may follow
+	for i := from; i < len(words); i++ {
+		w := words[i]
+		if opt := unquoteWord(w); keyword != "" && strings.HasPrefix(opt, "-") {
+			if opt == "--" {
+				keyword = ""
+			} else if keyword == "exec" && strings.Contains(opt[1:], "a") {
+				i++
+			}
+			continue
+		}
+		keyword = ""
+		switch {
+		case shellKeywords[w]:
+			if optionKeywords[w] {
+				keyword = w
+			}
+		case w == "for" || w == "select" || w == "case":
+			// Loop and case headers name variables and words, not programs
+			return -1
+		case isAssignment(w):
+		case isRedirection(w):
+			// A bare operator takes its target from the next word
+			if redirectionOperators[strings.TrimLeft(w, "0123456789")] {
+				i++
+			}
+		default:
+			return i
+		}
+	}
+	return -1
+}
+
+// shellWords splits a simple command on unquoted whitespace
+func shellWords(segment string) []string {
+	var words []string
+	var cur strings.Builder
+	var quote byte
+	for i := 0; i < len(segment); i++ {
+		c := segment[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\\' && i+1 < len(segment):
+			cur.WriteByte(c)
+			i++
+			c = segment[i]
+		case c == '\'' || c == '"':
+			quote = c
+		case isShellSpace(c):
+			if cur.Len() > 0 {
+				words = append(words, cur.String())
+				cur.Reset()
+			}
+			continue
+		}
+		cur.WriteByte(c)
+	}
+	if cur.Len() > 0 {
+		words = append(words, cur.String())
+	}
+	return words
+}
+
+// unquoteWord removes the quotes and backslashes from a shell word
+func unquoteWord(w string) string {
+	var b strings.Builder
+	var quote byte
+	for i := 0; i < len(w); i++ {
+		c := w[i]
+		switch {
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote == 0 && (c == '\'' || c == '"'):
+			quote = c
+		case quote != '\'' && c == '\\' && i+1 < len(w):
+			i++
+			b.WriteByte(w[i])
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// isAssignment reports whether w is a NAME=value prefix assignment
+func isAssignment(w string) bool {
+	name, _, ok := strings.Cut(w, "=")
+	if !ok || name == "" {
+		return false
+	}
+	for i, c := range name {
+		if !(c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || i > 0 && c >= '0' && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// redirectionOperators are the redirections that take their target from the
+// next word
+var redirectionOperators = map[string]bool{
+	">": true, ">>": true, ">|": true, "<": true, "<<": true, "<<<": true, "<>": true, "&>": true, "&>>": true,
+}
+
+// isRedirection reports whether w is a redirection such as >out, 2>&1 or <in
+func isRedirection(w string) bool {
+	rest := strings.TrimLeft(w, "0123456789")
+	return strings.HasPrefix(rest, ">") || strings.HasPrefix(rest, "<") || strings.HasPrefix(w, "&>")
+}
+
+func isShellSpace(c byte) bool {
+	return c == ' ' || c == '\t'
+}
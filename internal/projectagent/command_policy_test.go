@@ -0,0 +1,112 @@
+package projectagent
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCommandSegments(t *testing.T) {
+	tests := []struct {
+		command string
+		want    []string // Program each segment runs; substitutions come first
+	}{
+		{"go test ./...", []string{"go"}},
+		{"cat go.mod | grep module && make build || echo failed; ls &", []string{"cat", "grep", "make", "echo", "ls"}},
+		{"FOO=1 GOFLAGS=-v go build 2>&1 | tee log", []string{"go", "tee"}},
+		{"echo \"a | b && c; (d)\" 'e; f'", []string{"echo"}},
+		{"echo $(rm -rf /tmp/x) `curl example.com`", []string{"rm", "curl", "echo"}},
+		{"echo \"$(whoami)\"", []string{"whoami", "echo"}},
+		{"(cd src && make)", []string{"cd", "make"}},
+		{"if test -f x; then rm x; fi", []string{"test", "rm"}},
+		{"for f in *.go; do gofmt -l $f; done", []string{"gofmt"}},
+		{"echo $((1 + 2)) # && rm -rf /", []string{"echo"}},
+		{"cat > notes.md <<'EOF'\nrm -rf /\nEOF\ngit add notes.md", []string{"cat", "git"}},
+		{"\"r\"m -rf x; /bin/rm y; r\\m z", []string{"rm", "/bin/rm", "rm"}},
+		{"command -p rm -rf x; exec -a ls rm y; exec -ca name go build", []string{"rm", "rm", "go"}},
+		{"time -p command -- make; exec -l -- -sh", []string{"make", "-sh"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, segment := range commandSegments(tt.command) {
+			if name := commandName(segment); name != "" {
+				got = append(got, name)
+			}
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("programs of %q = %v, want %v", tt.command, got, tt.want)
+		}
+	}
+}
+
+func TestCheckCommandPolicy(t *testing.T) {
+	allow := &Agent{config: Config{AllowedCommands: []string{"go", "git", "echo", "/usr/bin/make"}}}
+	deny := &Agent{config: Config{DeniedCommands: []string{"rm", "curl"}}}
+
+	tests := []struct {
+		agent   *Agent
+		command string
+		want    string // Error substring; empty means allowed
+	}{
+		{allow, "go test ./... && git status", ""},
+		{allow, "make build", ""},
+		{allow, "/usr/bin/make build", ""},
+		{allow, "go vet | grep error", `"grep" is not on the command allowlist`},
+		{allow, "./go build", `"./go" is not on the command allowlist`},
+		{allow, "echo $(cat /etc/passwd)", `"cat" is not on the command allowlist`},
+		{allow, "$TOOL run", "its name is computed"},
+		{deny, "ls -la; echo done", ""},
+		{deny, "ls && /bin/rm -rf /", `"/bin/rm" is denied`},
+		{deny, "wget x || curl x", `"curl" is denied`},
+		{deny, "command -p rm -rf x", `"rm" is denied`},
+		{deny, "exec -a ls rm -rf /", `"rm" is denied`},
+		{deny, "env FOO=1 -u BAR rm -rf /", `"rm" is denied`},
+		{deny, "timeout -s KILL 30 curl x", `"curl" is denied`},
+		{deny, "timeout -- 30 rm x", `"rm" is denied`},
+		{deny, "nice -n 10 rm x; nohup rm y", `"rm" is denied`},
+		{deny, "find . -name '*.o' | xargs -I {} rm {}", `"rm" is denied`},
+		{deny, "sudo -u root /bin/rm -rf /", `"/bin/rm" is denied`},
+		{deny, "sudo -e /etc/hosts", ""},
+		{deny, "stdbuf -oL curl x", `"curl" is denied`},
+		{deny, "env -S 'rm -rf /'", `"rm" is denied`},
+		{deny, "timeout 5 env sudo rm x", `"rm" is denied`},
+		{deny, `bash -c "echo hi && rm -rf /"`, `"rm" is denied`},
+		{deny, "sh -ec 'ls'; bash script.sh", ""},
+		{deny, `eval "curl x"`, `"curl" is denied`},
+		{deny, `xargs sh -c 'eval "rm x"'`, `"rm" is denied`},
+		{deny, `sh -c "$CMD"`, "its name is computed"},
+		{allow, "timeout 60 go test ./...", `"timeout" is not on the command allowlist`},
+		{&Agent{config: Config{AllowedCommands: []string{"env", "go"}}}, "env GOOS=linux go build", ""},
+		{&Agent{config: Config{AllowedCommands: []string{"env", "go"}}}, "env GOOS=linux cat x", `"cat" is not on the command allowlist`},
+		{&Agent{}, "rm -rf $(anything)", ""},
+	}
+	for _, tt := range tests {
+		err := tt.agent.checkCommandPolicy(tt.command)
+		switch {
+		case tt.want == "" && err != nil:
+			t.Errorf("checkCommandPolicy(%q) = %v, want allowed", tt.command, err)
+		case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+			t.Errorf("checkCommandPolicy(%q) = %v, want %q", tt.command, err, tt.want)
+		}
+	}
+}
+
+func TestExecuteBash_CommandPolicy(t *testing.T) {
+	agent := newTestAgent(t)
+	agent.config.DeniedCommands = []string{"touch"}
+
+	_, err := agent.executeBash(context.Background(), map[string]interface{}{"command": "echo hi && touch marker"})
+	if err == nil || !strings.Contains(err.Error(), "denied") {
+		t.Fatalf("executeBash error = %v, want the command denied", err)
+	}
+	// Nothing in the command ran
+	if _, err := os.Stat(filepath.Join(agent.config.WorkDir, "marker")); !os.IsNotExist(err) {
+		t.Error("denied command was executed")
+	}
+
+	if out, err := agent.executeBash(context.Background(), map[string]interface{}{"command": "echo hi"}); err != nil || out != "hi\n" {
+		t.Errorf("executeBash = %q, %v", out, err)
+	}
+}
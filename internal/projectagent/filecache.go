@@ -0,0 +1,130 @@
+package projectagent
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultReadCacheMB is how much file content read tasks may keep cached
+// when ReadCacheMB is unset
+const DefaultReadCacheMB = 64
+
+// fileCache holds the content of files read tasks returned, keyed by path.
+// An entry is served only while the file's modification time and size are
+// unchanged; writes and patches through the agent drop it outright.
+type fileCache struct {
+	mu        sync.Mutex
+	entries   map[string]*fileCacheEntry
+	bytes     int64
+	clock     uint64 // Advances on each use, ordering entries for eviction
+	hits      int64
+	misses    int64
+	evictions int64
+}
+
+type fileCacheEntry struct {
+	modTime  time.Time
+	size     int64
+	content  string
+	lastUsed uint64
+}
+
+// FileCacheStats reports how the read cache is doing
+type FileCacheStats struct {
+	Hits      int64 `json:"hits"`
+	Misses    int64 `json:"misses"`
+	Evictions int64 `json:"evictions"`
+	Entries   int   `json:"entries"`
+	Bytes     int64 `json:"bytes"`
+}
+
+// readCacheBytes returns the read cache's size cap
+func (a *Agent) readCacheBytes() int64 {
+	if a.config.ReadCacheMB > 0 {
+		return int64(a.config.ReadCacheMB) << 20
+	}
+	return DefaultReadCacheMB << 20
+}
+
+// get returns the cached content of path if info shows the file unchanged
+// since it was cached
+func (c *fileCache) get(path string, info os.FileInfo) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[path]
+	if !ok || !entry.modTime.Equal(info.ModTime()) || entry.size != info.Size() {
+		c.misses++
+		return "", false
+	}
+	c.hits++
+	c.clock++
+	entry.lastUsed = c.clock
+	return entry.content, true
+}
+
+// put caches the content of path as of info, evicting the least recently
+// used entries to stay within limit bytes. Content larger than limit is not
+// cached.
+func (c *fileCache) put(path string, info os.FileInfo, content string, limit int64) {
+	size := int64(len(content))
+	if size > limit {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[string]*fileCacheEntry)
+	}
+	c.remove(path)
+	for c.bytes+size > limit && len(c.entries) > 0 {
+		c.evictOldest()
+	}
+	c.clock++
+	c.entries[path] = &fileCacheEntry{modTime: info.ModTime(), size: info.Size(), content: content, lastUsed: c.clock}
+	c.bytes += size
+}
+
+// invalidate drops the cached content of path
+func (c *fileCache) invalidate(path string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.remove(path)
+}
+
+// stats returns the cache's hit and miss counts and current size
+func (c *fileCache) stats() FileCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return FileCacheStats{
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+		Entries:   len(c.entries),
+		Bytes:     c.bytes,
+	}
+}
+
+// remove drops an entry. The caller holds mu.
+func (c *fileCache) remove(path string) {
+	if entry, ok := c.entries[path]; ok {
+		c.bytes -= int64(len(entry.content))
+		delete(c.entries, path)
+	}
+}
+
+// evictOldest drops the least recently used entry. The caller holds mu.
+func (c *fileCache) evictOldest() {
+	var oldest string
+	var oldestUse uint64
+	first := true
+	for path, entry := range c.entries {
+		if first || entry.lastUsed < oldestUse {
+			oldest, oldestUse, first = path, entry.lastUsed, false
+		}
+	}
+	if !first {
+		c.remove(oldest)
+		c.evictions++
+	}
+}
@@ -0,0 +1,134 @@
+package projectagent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestExecuteRead_Cache(t *testing.T) {
+	agent := newTestAgent(t)
+	ctx := context.Background()
+	path := filepath.Join(agent.config.WorkDir, "f.txt")
+	writeWorkspaceFile(t, agent, "f.txt", "first")
+
+	read := func() string {
+		t.Helper()
+		out, err := agent.executeRead(ctx, map[string]interface{}{"path": "f.txt"})
+		if err != nil {
+			t.Fatalf("executeRead: %v", err)
+		}
+		return out
+	}
+
+	if got := read(); got != "first" {
+		t.Fatalf("read = %q", got)
+	}
+	if got := read(); got != "first" {
+		t.Fatalf("cached read = %q", got)
+	}
+	if stats := agent.files.stats(); stats.Hits != 1 || stats.Misses != 1 || stats.Entries != 1 {
+		t.Errorf("stats = %+v, want 1 hit and 1 miss", stats)
+	}
+
+	// A change made behind the agent's back shows in the modification time
+	if err := os.WriteFile(path, []byte("changed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Second)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if got := read(); got != "changed" {
+		t.Errorf("read after external change = %q", got)
+	}
+
+	// Writes and patches drop the entry even when size and time match
+	if _, err := agent.executeWrite(ctx, map[string]interface{}{"path": "f.txt", "content": "CHANGED"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if got := read(); got != "CHANGED" {
+		t.Errorf("read after write = %q", got)
+	}
+	patch := "@@ -1 +1 @@\n-CHANGED\n\\ No newline at end of file\n+PATCHED\n\\ No newline at end of file\n"
+	if out, err := agent.executeApplyPatch(ctx, map[string]interface{}{"path": "f.txt", "patch": patch}); err != nil {
+		t.Fatalf("executeApplyPatch: %v (%s)", err, out)
+	}
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if got := read(); got != "PATCHED" {
+		t.Errorf("read after patch = %q", got)
+	}
+}
+
+func TestFileCache_Evicts(t *testing.T) {
+	dir := t.TempDir()
+	info := func(name string) os.FileInfo {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("0123456789"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		fi, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return fi
+	}
+	a, b, c := info("a"), info("b"), info("c")
+
+	var cache fileCache
+	cache.put("a", a, "0123456789", 20)
+	cache.put("b", b, "0123456789", 20)
+	cache.get("a", a) // a is now more recently used than b
+	cache.put("c", c, "0123456789", 20)
+
+	if _, ok := cache.get("b", b); ok {
+		t.Error("expected the least recently used entry to be evicted")
+	}
+	if _, ok := cache.get("a", a); !ok {
+		t.Error("expected a recently used entry to stay cached")
+	}
+	if stats := cache.stats(); stats.Bytes != 20 || stats.Evictions != 1 {
+		t.Errorf("stats = %+v, want 20 bytes and 1 eviction", stats)
+	}
+
+	// Content over the cap is never cached
+	cache.put("big", a, "0123456789012345678901", 20)
+	if _, ok := cache.get("big", a); ok {
+		t.Error("expected content over the cap not to be cached")
+	}
+}
+
+func TestHandleStatus_FileCache(t *testing.T) {
+	agent := newTestAgent(t)
+	writeWorkspaceFile(t, agent, "f.txt", "hello")
+	for i := 0; i < 3; i++ {
+		if _, err := agent.executeRead(context.Background(), map[string]interface{}{"path": "f.txt"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mux := http.NewServeMux()
+	agent.RegisterHandlers(mux)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/status", nil))
+
+	var status struct {
+		FileCache FileCacheStats `json:"file_cache"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&status); err != nil {
+		t.Fatal(err)
+	}
+	if status.FileCache.Hits != 2 || status.FileCache.Misses != 1 || status.FileCache.Bytes != 5 {
+		t.Errorf("file_cache = %+v, want 2 hits, 1 miss and 5 bytes", status.FileCache)
+	}
+}
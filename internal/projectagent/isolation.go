@@ -0,0 +1,90 @@
+package projectagent
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+)
+
+// Isolation modes for the shell commands tasks run
+const (
+	IsolationNone      = "none"      // run on the host in WorkDir
+	IsolationChroot    = "chroot"    // run chrooted into WorkDir, which must hold /bin/bash
+	IsolationContainer = "container" // run in a throwaway container that mounts only WorkDir
+)
+
+// DefaultIsolationImage is the image container isolation uses when
+// IsolationImage is unset
+const DefaultIsolationImage = "debian:stable-slim"
+
+// containerWorkDir is where WorkDir is mounted inside isolation containers
+const containerWorkDir = "/workspace"
+
+// lookPath finds host commands; tests replace it
+var lookPath = exec.LookPath
+
+// checkIsolation reports whether the configured isolation mode can be used on
+// this host. For container isolation it returns the container runtime to use.
+func checkIsolation(config Config) (string, error) {
+	switch config.IsolationMode {
+	case "", IsolationNone:
+		return "", nil
+	case IsolationChroot:
+		if os.Geteuid() != 0 {
+			return "", fmt.Errorf("isolation mode chroot is not available: the agent must run as root")
+		}
+		if _, err := lookPath("chroot"); err != nil {
+			return "", fmt.Errorf("isolation mode chroot is not available: chroot command not found")
+		}
+		if _, err := os.Stat(filepath.Join(config.WorkDir, "bin", "bash")); err != nil {
+			return "", fmt.Errorf("isolation mode chroot is not available: %s has no /bin/bash to run commands with", config.WorkDir)
+		}
+		return "", nil
+	case IsolationContainer:
+		for _, runtime := range []string{"docker", "podman"} {
+			if path, err := lookPath(runtime); err == nil {
+				return path, nil
+			}
+		}
+		return "", fmt.Errorf("isolation mode container is not available: neither docker nor podman was found")
+	default:
+		return "", fmt.Errorf("unknown isolation mode %q (want none, chroot or container)", config.IsolationMode)
+	}
+}
+
+// shellCommand builds a command that runs script with bash in the workspace
+// under the agent's isolation mode
+func (a *Agent) shellCommand(ctx context.Context, script string) *exec.Cmd {
+	switch a.config.IsolationMode {
+	case IsolationChroot:
+		// chroot starts the command in the new root, which is WorkDir
+		return newCommand(ctx, "chroot", a.config.WorkDir, "/bin/bash", "-c", script)
+	case IsolationContainer:
+		name := fmt.Sprintf("loom-task-%d", time.Now().UnixNano())
+		args := []string{"run", "--rm", "-i", "--name", name,
+			"-v", a.config.WorkDir + ":" + containerWorkDir, "-w", containerWorkDir}
+		// Files the command creates stay owned by the agent's user, so git
+		// on the host can commit them
+		if uid := os.Getuid(); uid >= 0 {
+			args = append(args, "--user", fmt.Sprintf("%d:%d", uid, os.Getgid()))
+		}
+		args = append(args, a.containerLimitArgs()...)
+		args = append(args, a.config.IsolationImage, "bash", "-c", script)
+
+		cmd := newCommand(ctx, a.containerRuntime, args...)
+		kill := cmd.Cancel
+		cmd.Cancel = func() error {
+			// Killing the runtime's client does not stop the container
+			_ = exec.Command(a.containerRuntime, "rm", "-f", name).Run()
+			return kill()
+		}
+		return cmd
+	default:
+		cmd := newCommand(ctx, "bash", "-c", script)
+		cmd.Dir = a.config.WorkDir
+		return cmd
+	}
+}
@@ -0,0 +1,73 @@
+package projectagent
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNew_IsolationModes(t *testing.T) {
+	defer func(f func(string) (string, error)) { lookPath = f }(lookPath)
+	lookPath = func(string) (string, error) { return "", exec.ErrNotFound }
+
+	base := Config{ProjectID: "p", ControlPlaneURL: "http://localhost", WorkDir: t.TempDir()}
+
+	agent, err := New(base)
+	if err != nil {
+		t.Fatalf("New() with no isolation: %v", err)
+	}
+	if agent.config.IsolationMode != IsolationNone {
+		t.Errorf("IsolationMode = %q, want none by default", agent.config.IsolationMode)
+	}
+
+	tests := []struct {
+		mode string
+		want string
+	}{
+		{"sandbox", "unknown isolation mode"},
+		{IsolationContainer, "neither docker nor podman"},
+		{IsolationChroot, "isolation mode chroot is not available"},
+	}
+	for _, tt := range tests {
+		config := base
+		config.IsolationMode = tt.mode
+		if _, err := New(config); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("New() with isolation %q: error = %v, want %q", tt.mode, err, tt.want)
+		}
+	}
+}
+
+func TestExecuteBash_ContainerIsolation(t *testing.T) {
+	// A fake runtime that prints the arguments it was run with
+	runtime := filepath.Join(t.TempDir(), "docker")
+	if err := os.WriteFile(runtime, []byte("#!/bin/sh\necho \"$@\"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	defer func(f func(string) (string, error)) { lookPath = f }(lookPath)
+	lookPath = func(name string) (string, error) {
+		if name == "docker" {
+			return runtime, nil
+		}
+		return "", errors.New("not found")
+	}
+
+	workDir := t.TempDir()
+	agent, err := New(Config{ProjectID: "p", ControlPlaneURL: "http://localhost", WorkDir: workDir, IsolationMode: IsolationContainer})
+	if err != nil {
+		t.Fatalf("New() with container isolation: %v", err)
+	}
+
+	output, err := agent.executeBash(context.Background(), map[string]interface{}{"command": "make test"})
+	if err != nil {
+		t.Fatalf("executeBash: %v (%s)", err, output)
+	}
+	for _, want := range []string{"run --rm", "-v " + workDir + ":/workspace -w /workspace", DefaultIsolationImage + " bash -c make test"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("runtime args %q do not contain %q", output, want)
+		}
+	}
+}
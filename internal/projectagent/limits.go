@@ -0,0 +1,135 @@
+package projectagent
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os/exec"
+)
+
+// Resource limits that can end a task
+const (
+	LimitTime   = "time"
+	LimitMemory = "memory"
+	LimitCPU    = "cpu"
+)
+
+// LimitExceededError reports a task killed for exceeding a resource limit
+type LimitExceededError struct {
+	Limit string // LimitTime, LimitMemory or LimitCPU
+	Value string // The limit that was exceeded, e.g. "512MB"
+}
+
+func (e *LimitExceededError) Error() string {
+	if e.Limit == LimitTime {
+		return fmt.Sprintf("task timed out after %s", e.Value)
+	}
+	return fmt.Sprintf("%s limit of %s exceeded", e.Limit, e.Value)
+}
+
+// limitExceeded returns the limit err reports exceeding, or "" when it does
+// not
+func limitExceeded(err error) string {
+	var limitErr *LimitExceededError
+	if errors.As(err, &limitErr) {
+		return limitErr.Limit
+	}
+	return ""
+}
+
+// hasResourceLimits reports whether the config limits memory or CPU
+func (c Config) hasResourceLimits() bool {
+	return c.MaxMemoryMB > 0 || c.MaxCPUSeconds > 0
+}
+
+// checkResourceLimits validates the configured limits and, unless container
+// isolation enforces them through the runtime, prepares the cgroup commands
+// are limited in. It returns that cgroup, or "" when there is none. Where
+// cgroups are unavailable it logs that only the task timeout applies.
+func checkResourceLimits(config Config) (string, error) {
+	if config.MaxMemoryMB < 0 {
+		return "", fmt.Errorf("max memory must not be negative")
+	}
+	if config.MaxCPUSeconds < 0 {
+		return "", fmt.Errorf("max CPU seconds must not be negative")
+	}
+	if !config.hasResourceLimits() || config.IsolationMode == IsolationContainer {
+		return "", nil
+	}
+	parent, err := setupLimitCgroup(config.MaxMemoryMB > 0)
+	if err != nil {
+		log.Printf("Warning: memory and CPU limits are not enforced: %v", err)
+		log.Printf("Commands are limited by the task timeout only")
+		return "", nil
+	}
+	return parent, nil
+}
+
+// containerLimitArgs returns the container runtime flags that apply the
+// agent's memory and CPU limits
+func (a *Agent) containerLimitArgs() []string {
+	var args []string
+	if mb := a.config.MaxMemoryMB; mb > 0 {
+		// Equal memory and swap limits leave the container no swap
+		args = append(args, "--memory", fmt.Sprintf("%dm", mb), "--memory-swap", fmt.Sprintf("%dm", mb))
+	}
+	if secs := a.config.MaxCPUSeconds; secs > 0 {
+		// A soft limit below the hard one makes the kernel send SIGXCPU,
+		// which tells a CPU breach apart from other kills
+		args = append(args, "--ulimit", fmt.Sprintf("cpu=%d:%d", secs, secs+1))
+	}
+	return args
+}
+
+// runLimited runs cmd under the agent's memory and CPU limits and returns its
+// combined output. A command killed for exceeding a limit returns a
+// *LimitExceededError.
+func (a *Agent) runLimited(cmd *exec.Cmd) (string, error) {
+	var output []byte
+	var limit string
+	var err error
+
+	switch {
+	case a.limitCgroup != "":
+		output, limit, err = runInCgroup(a.limitCgroup, cmd, a.config.MaxMemoryMB, a.config.MaxCPUSeconds)
+	case a.config.IsolationMode == IsolationContainer && a.config.hasResourceLimits():
+		output, err = cmd.CombinedOutput()
+		limit = containerLimitExceeded(err)
+	default:
+		output, err = cmd.CombinedOutput()
+	}
+
+	switch limit {
+	case LimitMemory:
+		err = &LimitExceededError{Limit: LimitMemory, Value: fmt.Sprintf("%dMB", a.config.MaxMemoryMB)}
+	case LimitCPU:
+		err = &LimitExceededError{Limit: LimitCPU, Value: fmt.Sprintf("%ds", a.config.MaxCPUSeconds)}
+	}
+	return string(output), err
+}
+
+// containerLimitExceeded maps the exit status of a container run to the limit
+// it broke: the runtime reports an out-of-memory kill as 137 (128+SIGKILL) and
+// the shell a CPU limit kill as 152 (128+SIGXCPU).
+func containerLimitExceeded(err error) string {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return ""
+	}
+	switch exitErr.ExitCode() {
+	case 137:
+		return LimitMemory
+	case 152:
+		return LimitCPU
+	}
+	return ""
+}
+
+// limitContext is the result metadata naming the limit err reports
+// exceeding, or nil when it does not
+func limitContext(err error) map[string]interface{} {
+	if limit := limitExceeded(err); limit != "" {
+		return map[string]interface{}{"limit_exceeded": limit}
+	}
+	return nil
+}
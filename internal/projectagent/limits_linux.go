@@ -0,0 +1,259 @@
+//go:build linux
+
+package projectagent
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// cgroupPollInterval is how often a command's CPU use is checked against its
+// limit
+var cgroupPollInterval = 100 * time.Millisecond
+
+// setupLimitCgroup finds the agent's cgroup v2 directory, under which each
+// limited command gets a cgroup of its own. With memory set it also makes the
+// memory controller available to those cgroups.
+func setupLimitCgroup(memory bool) (string, error) {
+	mountinfo, err := os.ReadFile("/proc/self/mountinfo")
+	if err != nil {
+		return "", fmt.Errorf("failed to read mounts: %w", err)
+	}
+	mount, root, ok := parseCgroup2Mount(string(mountinfo))
+	if !ok {
+		return "", fmt.Errorf("cgroup v2 is not mounted")
+	}
+	self, err := os.ReadFile("/proc/self/cgroup")
+	if err != nil {
+		return "", fmt.Errorf("failed to read the agent's cgroup: %w", err)
+	}
+	own, ok := parseOwnCgroup(string(self))
+	if !ok {
+		return "", fmt.Errorf("the agent is not in a cgroup v2 hierarchy")
+	}
+	dir := filepath.Join(mount, strings.TrimPrefix(own, root))
+
+	if memory {
+		if err := enableMemoryController(dir); err != nil {
+			return "", err
+		}
+	}
+
+	// Make sure command cgroups can be created here
+	probe, err := os.MkdirTemp(dir, "loom-probe-")
+	if err != nil {
+		return "", fmt.Errorf("cannot create cgroups under %s: %w", dir, err)
+	}
+	_ = os.Remove(probe)
+	return dir, nil
+}
+
+// parseCgroup2Mount returns the mount point and root of the cgroup v2
+// hierarchy listed in mountinfo
+func parseCgroup2Mount(mountinfo string) (mount, root string, ok bool) {
+	scanner := bufio.NewScanner(strings.NewReader(mountinfo))
+	for scanner.Scan() {
+		// ID parent major:minor root mount-point options [optional...] - type source super-options
+		fields := strings.Fields(scanner.Text())
+		for i, field := range fields {
+			if field == "-" && i+1 < len(fields) && len(fields) >= 5 {
+				if fields[i+1] == "cgroup2" {
+					return fields[4], fields[3], true
+				}
+				break
+			}
+		}
+	}
+	return "", "", false
+}
+
+// parseOwnCgroup returns the cgroup v2 path in /proc/self/cgroup
+func parseOwnCgroup(data string) (string, bool) {
+	for _, line := range strings.Split(data, "\n") {
+		if path, ok := strings.CutPrefix(line, "0::"); ok {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// enableMemoryController makes the memory controller available to the
+// children of dir
+func enableMemoryController(dir string) error {
+	if hasController(filepath.Join(dir, "cgroup.subtree_control"), "memory") {
+		return nil
+	}
+	if !hasController(filepath.Join(dir, "cgroup.controllers"), "memory") {
+		return fmt.Errorf("the memory controller is not available in %s", dir)
+	}
+
+	// A cgroup that holds processes cannot hand controllers to its
+	// children, so the agent first moves into a leaf cgroup of its own
+	leaf := filepath.Join(dir, "loom-agent")
+	if err := os.Mkdir(leaf, 0755); err != nil && !os.IsExist(err) {
+		return fmt.Errorf("failed to create the agent's cgroup: %w", err)
+	}
+	if err := writeCgroup(leaf, "cgroup.procs", strconv.Itoa(os.Getpid())); err != nil {
+		return err
+	}
+	if err := writeCgroup(dir, "cgroup.subtree_control", "+memory"); err != nil {
+		return fmt.Errorf("failed to enable the memory controller: %w", err)
+	}
+	return nil
+}
+
+// hasController reports whether the controller list in file names controller
+func hasController(file, controller string) bool {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return false
+	}
+	for _, name := range strings.Fields(string(data)) {
+		if name == controller {
+			return true
+		}
+	}
+	return false
+}
+
+// runInCgroup runs cmd in a new cgroup under parent that limits its memory
+// and CPU time, and returns its combined output. When the command is killed
+// for exceeding a limit, it also returns that limit.
+func runInCgroup(parent string, cmd *exec.Cmd, memoryMB, cpuSeconds int) ([]byte, string, error) {
+	dir, err := os.MkdirTemp(parent, "loom-task-")
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create command cgroup: %w", err)
+	}
+	defer removeCgroup(dir)
+
+	if memoryMB > 0 {
+		if err := writeCgroup(dir, "memory.max", strconv.Itoa(memoryMB*1024*1024)); err != nil {
+			return nil, "", err
+		}
+		// Without swap the limit is firm, and an out-of-memory kill ends
+		// every process of the command rather than one of them
+		_ = writeCgroup(dir, "memory.swap.max", "0")
+		_ = writeCgroup(dir, "memory.oom.group", "1")
+	}
+
+	fd, err := syscall.Open(dir, syscall.O_DIRECTORY|syscall.O_RDONLY|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to open command cgroup: %w", err)
+	}
+	defer syscall.Close(fd)
+	// Start the command inside the cgroup, so nothing it forks escapes
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.UseCgroupFD = true
+	cmd.SysProcAttr.CgroupFD = fd
+
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	if err := cmd.Start(); err != nil {
+		return nil, "", err
+	}
+
+	var cpuExceeded atomic.Bool
+	done := make(chan struct{})
+	if cpuSeconds > 0 {
+		interval := cgroupPollInterval
+		go func() {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			limit := int64(cpuSeconds) * int64(time.Second/time.Microsecond)
+			for {
+				select {
+				case <-done:
+					return
+				case <-ticker.C:
+					if usage, err := cgroupCPUUsage(dir); err == nil && usage >= limit {
+						cpuExceeded.Store(true)
+						killCgroup(dir, cmd)
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	err = cmd.Wait()
+	close(done)
+
+	switch {
+	case cpuExceeded.Load():
+		return output.Bytes(), LimitCPU, err
+	case cgroupOOMKilled(dir):
+		return output.Bytes(), LimitMemory, err
+	}
+	return output.Bytes(), "", err
+}
+
+// cgroupCPUUsage returns the CPU time the cgroup's processes have used, in
+// microseconds
+func cgroupCPUUsage(dir string) (int64, error) {
+	return cgroupStat(dir, "cpu.stat", "usage_usec")
+}
+
+// cgroupOOMKilled reports whether the kernel killed a process of the cgroup
+// for exceeding its memory limit
+func cgroupOOMKilled(dir string) bool {
+	kills, err := cgroupStat(dir, "memory.events", "oom_kill")
+	return err == nil && kills > 0
+}
+
+// cgroupStat reads one "key value" line of a cgroup stat file
+func cgroupStat(dir, file, key string) (int64, error) {
+	data, err := os.ReadFile(filepath.Join(dir, file))
+	if err != nil {
+		return 0, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if value, ok := strings.CutPrefix(line, key+" "); ok {
+			return strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		}
+	}
+	return 0, fmt.Errorf("%s has no %s", file, key)
+}
+
+// killCgroup kills every process in the cgroup, falling back to the command's
+// process group on kernels without cgroup.kill
+func killCgroup(dir string, cmd *exec.Cmd) {
+	if writeCgroup(dir, "cgroup.kill", "1") == nil {
+		return
+	}
+	if cmd.Process != nil {
+		_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
+
+// removeCgroup kills anything the command left running in the cgroup and
+// removes it
+func removeCgroup(dir string) {
+	_ = writeCgroup(dir, "cgroup.kill", "1")
+	// The cgroup can only be removed once its killed processes are gone
+	for i := 0; i < 50; i++ {
+		if err := os.Remove(dir); err == nil || os.IsNotExist(err) {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// writeCgroup writes value to a cgroup interface file
+func writeCgroup(dir, file, value string) error {
+	if err := os.WriteFile(filepath.Join(dir, file), []byte(value), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", file, err)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package projectagent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseCgroup2Mount(t *testing.T) {
+	mountinfo := `22 1 0:21 / /proc rw,nosuid - proc proc rw
+30 25 0:26 / /sys/fs/cgroup ro,nosuid shared:4 - tmpfs tmpfs ro,mode=755
+35 30 0:31 / /sys/fs/cgroup/unified rw,nosuid shared:10 - cgroup2 cgroup2 rw,nsdelegate
+`
+	mount, root, ok := parseCgroup2Mount(mountinfo)
+	if !ok || mount != "/sys/fs/cgroup/unified" || root != "/" {
+		t.Errorf("parseCgroup2Mount() = %q, %q, %v", mount, root, ok)
+	}
+	if _, _, ok := parseCgroup2Mount("22 1 0:21 / /proc rw - proc proc rw\n"); ok {
+		t.Error("expected no cgroup2 mount")
+	}
+}
+
+func TestParseOwnCgroup(t *testing.T) {
+	path, ok := parseOwnCgroup("12:memory:/docker/abc\n0::/system.slice/loom.service\n")
+	if !ok || path != "/system.slice/loom.service" {
+		t.Errorf("parseOwnCgroup() = %q, %v", path, ok)
+	}
+	if _, ok := parseOwnCgroup("12:memory:/docker/abc\n"); ok {
+		t.Error("expected no cgroup v2 path")
+	}
+}
+
+func TestExecuteBash_CPULimit(t *testing.T) {
+	parent, err := setupLimitCgroup(false)
+	if err != nil {
+		t.Skipf("cgroups unavailable: %v", err)
+	}
+	defer func(d time.Duration) { cgroupPollInterval = d }(cgroupPollInterval)
+	cgroupPollInterval = 10 * time.Millisecond
+
+	agent := newTestAgent(t)
+	agent.config.MaxCPUSeconds = 1
+	agent.limitCgroup = parent
+
+	start := time.Now()
+	output, err := agent.executeBash(context.Background(), map[string]interface{}{"command": "while :; do :; done"})
+	var limitErr *LimitExceededError
+	if !errors.As(err, &limitErr) || limitErr.Limit != LimitCPU {
+		t.Fatalf("executeBash error = %v (%s), want cpu limit exceeded", err, output)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("command ran %v past a 1s CPU limit", elapsed)
+	}
+
+	// A command within its limits is unaffected
+	output, err = agent.executeBash(context.Background(), map[string]interface{}{"command": "echo ok"})
+	if err != nil || output != "ok\n" {
+		t.Errorf("executeBash = %q, %v", output, err)
+	}
+}
@@ -0,0 +1,19 @@
+//go:build !linux
+
+package projectagent
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// setupLimitCgroup always fails where cgroups do not exist
+func setupLimitCgroup(memory bool) (string, error) {
+	return "", fmt.Errorf("cgroups are not supported on this platform")
+}
+
+// runInCgroup is never reached without a limit cgroup
+func runInCgroup(parent string, cmd *exec.Cmd, memoryMB, cpuSeconds int) ([]byte, string, error) {
+	output, err := cmd.CombinedOutput()
+	return output, "", err
+}
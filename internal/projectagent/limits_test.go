@@ -0,0 +1,103 @@
+package projectagent
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNew_ResourceLimits(t *testing.T) {
+	base := Config{ProjectID: "p", ControlPlaneURL: "http://localhost", WorkDir: t.TempDir()}
+
+	for _, config := range []Config{
+		{MaxMemoryMB: -1},
+		{MaxCPUSeconds: -1},
+	} {
+		config.ProjectID, config.ControlPlaneURL, config.WorkDir = base.ProjectID, base.ControlPlaneURL, base.WorkDir
+		if _, err := New(config); err == nil || !strings.Contains(err.Error(), "must not be negative") {
+			t.Errorf("New(%+v) error = %v, want negative limit error", config, err)
+		}
+	}
+
+	// Limits the host cannot enforce leave the agent running on the task
+	// timeout alone
+	config := base
+	config.MaxMemoryMB, config.MaxCPUSeconds = 256, 10
+	if _, err := New(config); err != nil {
+		t.Fatalf("New() with resource limits: %v", err)
+	}
+}
+
+func TestLimitExceededError(t *testing.T) {
+	tests := []struct {
+		err  *LimitExceededError
+		want string
+	}{
+		{&LimitExceededError{Limit: LimitTime, Value: "1s"}, "task timed out after 1s"},
+		{&LimitExceededError{Limit: LimitMemory, Value: "256MB"}, "memory limit of 256MB exceeded"},
+		{&LimitExceededError{Limit: LimitCPU, Value: "10s"}, "cpu limit of 10s exceeded"},
+	}
+	for _, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("Error() = %q, want %q", got, tt.want)
+		}
+		if got := limitContext(tt.err)["limit_exceeded"]; got != tt.err.Limit {
+			t.Errorf("limitContext() = %v, want %s", got, tt.err.Limit)
+		}
+	}
+	if limitContext(errors.New("exit status 1")) != nil {
+		t.Error("expected no limit context for an ordinary failure")
+	}
+}
+
+func TestExecuteTask_TimeoutReportsLimit(t *testing.T) {
+	agent := newTestAgent(t)
+
+	go agent.executeTask(&TaskRequest{
+		TaskID:         "task-1",
+		Action:         "bash",
+		Params:         map[string]interface{}{"command": "sleep 30"},
+		TimeoutSeconds: 1,
+	})
+
+	result := waitForResult(t, agent)
+	if result.Metadata["limit_exceeded"] != LimitTime {
+		t.Errorf("Metadata = %v, want limit_exceeded time", result.Metadata)
+	}
+}
+
+func TestExecuteBash_ContainerLimits(t *testing.T) {
+	// A fake runtime that prints its arguments and exits as the kernel's
+	// out-of-memory killer would leave it
+	runtime := filepath.Join(t.TempDir(), "docker")
+	if err := os.WriteFile(runtime, []byte("#!/bin/sh\necho \"$@\"\nexit 137\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	defer func(f func(string) (string, error)) { lookPath = f }(lookPath)
+	lookPath = func(name string) (string, error) {
+		if name == "docker" {
+			return runtime, nil
+		}
+		return "", errors.New("not found")
+	}
+
+	agent, err := New(Config{ProjectID: "p", ControlPlaneURL: "http://localhost", WorkDir: t.TempDir(),
+		IsolationMode: IsolationContainer, MaxMemoryMB: 256, MaxCPUSeconds: 10})
+	if err != nil {
+		t.Fatalf("New() with container limits: %v", err)
+	}
+
+	output, err := agent.executeBash(context.Background(), map[string]interface{}{"command": "make test"})
+	for _, want := range []string{"--memory 256m --memory-swap 256m", "--ulimit cpu=10:11"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("runtime args %q do not contain %q", output, want)
+		}
+	}
+	var limitErr *LimitExceededError
+	if !errors.As(err, &limitErr) || limitErr.Limit != LimitMemory {
+		t.Errorf("executeBash error = %v, want memory limit exceeded", err)
+	}
+}
@@ -0,0 +1,100 @@
+package projectagent
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// executeApplyPatch applies a unified diff to one file in the workspace with
+// git apply. The patch may carry its own ---/+++ headers, which must name
+// path, or be bare hunks. A patch that does not apply cleanly changes
+// nothing and fails with git's hunk error.
+func (a *Agent) executeApplyPatch(ctx context.Context, params map[string]interface{}) (string, error) {
+	path, ok := params["path"].(string)
+	if !ok || path == "" {
+		return "", fmt.Errorf("path parameter required")
+	}
+	patch, ok := params["patch"].(string)
+	if !ok || strings.TrimSpace(patch) == "" {
+		return "", fmt.Errorf("patch parameter required")
+	}
+
+	fullPath, err := a.resolveSafePath(path)
+	if err != nil {
+		return "", err
+	}
+	defer a.files.invalidate(fullPath)
+	path = filepath.ToSlash(filepath.Clean(path))
+
+	// Headers from git carry a/ and b/ prefixes; plain diff headers do not
+	strip := "-p0"
+	files := patchFiles(patch)
+	if len(files) == 0 {
+		patch = fmt.Sprintf("--- a/%s\n+++ b/%s\n%s", path, path, patch)
+		strip = "-p1"
+	}
+	for _, file := range files {
+		switch {
+		case file == path:
+		case (strings.HasPrefix(file, "a/") || strings.HasPrefix(file, "b/")) && file[2:] == path:
+			strip = "-p1"
+		default:
+			return "", fmt.Errorf("patch touches %s, not %s", file, path)
+		}
+	}
+	if !strings.HasSuffix(patch, "\n") {
+		patch += "\n"
+	}
+
+	// git apply refuses paths that leave the working directory or pass
+	// through a symlink, and applies all hunks or none
+	cmd := newCommand(ctx, "git", "apply", strip, "--verbose", "--whitespace=nowarn", "-")
+	cmd.Dir = a.config.WorkDir
+	cmd.Stdin = strings.NewReader(patch)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return string(output), fmt.Errorf("patch does not apply cleanly: %s", strings.TrimSpace(string(output)))
+	}
+	return string(output), nil
+}
+
+// patchFiles returns the file names a unified diff's headers give, as written
+func patchFiles(patch string) []string {
+	var files []string
+	seen := make(map[string]bool)
+	add := func(name string) {
+		if name == "/dev/null" {
+			return
+		}
+		name = filepath.ToSlash(name)
+		if !seen[name] {
+			seen[name] = true
+			files = append(files, name)
+		}
+	}
+
+	lines := strings.Split(patch, "\n")
+	for i, line := range lines {
+		// A removed line starting with "-- " also begins with "--- ", so
+		// only a ---/+++ pair is a file header
+		if strings.HasPrefix(line, "--- ") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ ") {
+			add(headerPath(line[4:]))
+			add(headerPath(lines[i+1][4:]))
+			continue
+		}
+		for _, prefix := range []string{"rename from ", "rename to ", "copy from ", "copy to "} {
+			if name, ok := strings.CutPrefix(line, prefix); ok {
+				add(name)
+			}
+		}
+	}
+	return files
+}
+
+// headerPath strips any timestamp from the name in a ---/+++ header
+func headerPath(name string) string {
+	name, _, _ = strings.Cut(name, "\t")
+	return strings.TrimSpace(name)
+}
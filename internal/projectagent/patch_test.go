@@ -0,0 +1,141 @@
+package projectagent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const patchOriginal = "one\ntwo\nthree\n"
+
+func writeWorkspaceFile(t *testing.T, agent *Agent, name, content string) {
+	t.Helper()
+	path := filepath.Join(agent.config.WorkDir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readWorkspaceFile(t *testing.T, agent *Agent, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(agent.config.WorkDir, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestExecuteApplyPatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch string
+	}{
+		{"git headers", "diff --git a/src/f.txt b/src/f.txt\n--- a/src/f.txt\n+++ b/src/f.txt\n@@ -1,3 +1,3 @@\n one\n-two\n+TWO\n three\n"},
+		{"plain headers", "--- src/f.txt\t2024-01-01 00:00:00\n+++ src/f.txt\t2024-01-02 00:00:00\n@@ -1,3 +1,3 @@\n one\n-two\n+TWO\n three\n"},
+		{"bare hunk", "@@ -1,3 +1,3 @@\n one\n-two\n+TWO\n three"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := newTestAgent(t)
+			writeWorkspaceFile(t, agent, "src/f.txt", patchOriginal)
+
+			output, err := agent.executeApplyPatch(context.Background(), map[string]interface{}{"path": "src/f.txt", "patch": tt.patch})
+			if err != nil {
+				t.Fatalf("executeApplyPatch: %v", err)
+			}
+			if !strings.Contains(output, "Applied patch") {
+				t.Errorf("output = %q, want git's applied report", output)
+			}
+			if got := readWorkspaceFile(t, agent, "src/f.txt"); got != "one\nTWO\nthree\n" {
+				t.Errorf("file = %q after patch", got)
+			}
+		})
+	}
+}
+
+func TestExecuteApplyPatch_Rejects(t *testing.T) {
+	tests := []struct {
+		name   string
+		params map[string]interface{}
+		want   string
+	}{
+		{"missing patch", map[string]interface{}{"path": "f.txt"}, "patch parameter required"},
+		{"missing path", map[string]interface{}{"patch": "@@ -1 +1 @@\n-a\n+b\n"}, "path parameter required"},
+		{"escaping path", map[string]interface{}{"path": "../f.txt", "patch": "@@ -1 +1 @@\n-a\n+b\n"}, "escapes the workspace"},
+		{"other file", map[string]interface{}{"path": "f.txt", "patch": "--- a/../../etc/passwd\n+++ b/../../etc/passwd\n@@ -1 +1 @@\n-a\n+b\n"}, "patch touches"},
+		{"stale hunk", map[string]interface{}{"path": "f.txt", "patch": "@@ -1,3 +1,3 @@\n one\n-deux\n+TWO\n three\n"}, "patch does not apply cleanly"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := newTestAgent(t)
+			writeWorkspaceFile(t, agent, "f.txt", patchOriginal)
+
+			_, err := agent.executeApplyPatch(context.Background(), tt.params)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("executeApplyPatch error = %v, want %q", err, tt.want)
+			}
+			if got := readWorkspaceFile(t, agent, "f.txt"); got != patchOriginal {
+				t.Errorf("file changed to %q by a rejected patch", got)
+			}
+		})
+	}
+}
+
+func TestPatchFiles(t *testing.T) {
+	patch := "diff --git a/old.go b/new.go\nrename from old.go\nrename to new.go\n--- a/old.go\n+++ b/new.go\n@@ -1,2 +1,2 @@\n--- not a header\n+keep\n"
+	got := patchFiles(patch)
+	want := []string{"old.go", "new.go", "a/old.go", "b/new.go"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("patchFiles() = %v, want %v", got, want)
+	}
+}
+
+func TestHandleTask_ApplyPatch(t *testing.T) {
+	agent := newTestAgent(t)
+	writeWorkspaceFile(t, agent, "f.txt", patchOriginal)
+	mux := http.NewServeMux()
+	agent.RegisterHandlers(mux)
+
+	post := func(req TaskRequest) int {
+		body, _ := json.Marshal(req)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/task", strings.NewReader(string(body))))
+		return w.Code
+	}
+
+	escape := TaskRequest{TaskID: "task-0", ProjectID: "test-project", Action: "apply_patch",
+		Params: map[string]interface{}{"path": "../f.txt", "patch": "@@ -1 +1 @@\n-a\n+b\n"}}
+	if code := post(escape); code != http.StatusBadRequest {
+		t.Errorf("expected 400 for a path outside the workspace, got %d", code)
+	}
+
+	apply := TaskRequest{TaskID: "task-1", ProjectID: "test-project", Action: "apply_patch",
+		Params: map[string]interface{}{"path": "f.txt", "patch": "--- a/f.txt\n+++ b/f.txt\n@@ -1,3 +1,3 @@\n one\n-two\n+TWO\n three\n"}}
+	if code := post(apply); code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d", code)
+	}
+	if result := waitForResult(t, agent); !result.Success {
+		t.Fatalf("apply_patch failed: %s", result.Error)
+	}
+	if got := readWorkspaceFile(t, agent, "f.txt"); got != "one\nTWO\nthree\n" {
+		t.Errorf("file = %q after patch", got)
+	}
+
+	// The same patch no longer applies, and the hunk error is reported
+	apply.TaskID = "task-2"
+	if code := post(apply); code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d", code)
+	}
+	result := waitForResult(t, agent)
+	if result.Success || !strings.Contains(result.Error, "patch failed: f.txt:1") {
+		t.Errorf("result = %+v, want the hunk error", result)
+	}
+}
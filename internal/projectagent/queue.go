@@ -0,0 +1,44 @@
+package projectagent
+
+// maxConcurrent returns how many tasks may run at once
+func (a *Agent) maxConcurrent() int {
+	if a.config.MaxConcurrent > 0 {
+		return a.config.MaxConcurrent
+	}
+	return 1
+}
+
+// startRunners creates the task queue and the goroutines that drain it, one
+// per concurrent task slot
+func (a *Agent) startRunners() {
+	size := a.config.QueueSize
+	if size <= 0 {
+		size = DefaultQueueSize
+	}
+	a.queue = make(chan func(), size)
+	for i := 0; i < a.maxConcurrent(); i++ {
+		go func() {
+			for run := range a.queue {
+				run()
+			}
+		}()
+	}
+}
+
+// enqueue queues a task to run once a slot is free, reporting false when the
+// queue is full
+func (a *Agent) enqueue(run func()) bool {
+	a.queueOnce.Do(a.startRunners)
+	select {
+	case a.queue <- run:
+		return true
+	default:
+		return false
+	}
+}
+
+// queueDepth returns how many accepted tasks are waiting to run
+func (a *Agent) queueDepth() int {
+	a.queueOnce.Do(a.startRunners)
+	return len(a.queue)
+}
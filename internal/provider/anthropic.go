@@ -0,0 +1,254 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultAnthropicEndpoint is used when an anthropic provider has no endpoint configured.
+	DefaultAnthropicEndpoint = "https://api.anthropic.com/v1"
+	// AnthropicAPIVersion is sent in the anthropic-version header on every request.
+	AnthropicAPIVersion = "2023-06-01"
+	// DefaultAnthropicMaxTokens is used when neither the request nor the provider
+	// config sets max_tokens. The Messages API rejects requests without it.
+	DefaultAnthropicMaxTokens = 4096
+)
+
+// AnthropicProvider implements Protocol for the Anthropic Messages API.
+// See: https://docs.anthropic.com/en/api/messages
+type AnthropicProvider struct {
+	endpoint  string
+	apiKey    string
+	maxTokens int
+	client    *http.Client
+}
+
+// NewAnthropicProvider creates a new Anthropic Messages API provider.
+// maxTokens is the default max_tokens for requests that do not set one.
+func NewAnthropicProvider(endpoint, apiKey string, maxTokens int) *AnthropicProvider {
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	if endpoint == "" {
+		endpoint = DefaultAnthropicEndpoint
+	}
+	if maxTokens <= 0 {
+		maxTokens = DefaultAnthropicMaxTokens
+	}
+	return &AnthropicProvider{
+		endpoint:  endpoint,
+		apiKey:    apiKey,
+		maxTokens: maxTokens,
+		client: &http.Client{
+			Timeout: 15 * time.Minute, // Increased for action loops with 25 iterations
+		},
+	}
+}
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicRequest struct {
+	Model       string             `json:"model"`
+	System      string             `json:"system,omitempty"`
+	Messages    []anthropicMessage `json:"messages"`
+	MaxTokens   int                `json:"max_tokens"`
+	Temperature float64            `json:"temperature,omitempty"`
+}
+
+type anthropicResponse struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Role    string `json:"role"`
+	Model   string `json:"model"`
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text,omitempty"`
+	} `json:"content"`
+	StopReason string `json:"stop_reason"`
+	Usage      struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+// CreateChatCompletion translates an OpenAI-style request into a Messages API
+// call and maps the content-block response back into a ChatCompletionResponse.
+func (p *AnthropicProvider) CreateChatCompletion(ctx context.Context, req *ChatCompletionRequest) (*ChatCompletionResponse, error) {
+	url := fmt.Sprintf("%s/messages", p.endpoint)
+
+	body, err := json.Marshal(p.buildRequest(req))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	p.setHeaders(httpReq)
+	setTraceHeader(httpReq)
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, sendError(err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError(resp.StatusCode, string(respBody))
+	}
+
+	var msgResp anthropicResponse
+	if err := unmarshalJSON(respBody, &msgResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return msgResp.toChatCompletion(), nil
+}
+
+// GetModels lists available models via the Anthropic models endpoint.
+func (p *AnthropicProvider) GetModels(ctx context.Context) ([]Model, error) {
+	url := fmt.Sprintf("%s/models", p.endpoint)
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	p.setHeaders(httpReq)
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, sendError(err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError(resp.StatusCode, string(respBody))
+	}
+
+	var modelsResp struct {
+		Data []struct {
+			ID        string `json:"id"`
+			Type      string `json:"type"`
+			CreatedAt string `json:"created_at"`
+		} `json:"data"`
+	}
+	if err := unmarshalJSON(respBody, &modelsResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	models := make([]Model, 0, len(modelsResp.Data))
+	for _, m := range modelsResp.Data {
+		if strings.TrimSpace(m.ID) == "" {
+			continue
+		}
+		model := Model{ID: m.ID, Object: "model", OwnedBy: "anthropic"}
+		if t, err := time.Parse(time.RFC3339, m.CreatedAt); err == nil {
+			model.Created = t.Unix()
+		}
+		models = append(models, model)
+	}
+	return models, nil
+}
+
+func (p *AnthropicProvider) setHeaders(httpReq *http.Request) {
+	httpReq.Header.Set("anthropic-version", AnthropicAPIVersion)
+	if p.apiKey != "" {
+		httpReq.Header.Set("x-api-key", p.apiKey)
+	}
+}
+
+// buildRequest hoists system messages into the top-level system field, since
+// the Messages API only accepts user and assistant roles in the message list.
+func (p *AnthropicProvider) buildRequest(req *ChatCompletionRequest) *anthropicRequest {
+	return buildAnthropicRequest(req, p.maxTokens)
+}
+
+// buildAnthropicRequest converts a chat request into the Messages API shape,
+// using defaultMaxTokens when the request does not set max_tokens.
+func buildAnthropicRequest(req *ChatCompletionRequest, defaultMaxTokens int) *anthropicRequest {
+	out := &anthropicRequest{
+		Model:       req.Model,
+		MaxTokens:   req.MaxTokens,
+		Temperature: req.Temperature,
+	}
+	if out.MaxTokens <= 0 {
+		out.MaxTokens = defaultMaxTokens
+	}
+
+	var system []string
+	for _, msg := range req.Messages {
+		if msg.Role == "system" {
+			system = append(system, msg.Content)
+			continue
+		}
+		out.Messages = append(out.Messages, anthropicMessage{Role: msg.Role, Content: msg.Content})
+	}
+	out.System = strings.Join(system, "\n\n")
+	return out
+}
+
+func (r *anthropicResponse) toChatCompletion() *ChatCompletionResponse {
+	var text strings.Builder
+	for _, block := range r.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+
+	role := r.Role
+	if role == "" {
+		role = "assistant"
+	}
+
+	completion := &ChatCompletionResponse{
+		ID:      r.ID,
+		Object:  "chat.completion",
+		Created: time.Now().Unix(),
+		Model:   r.Model,
+	}
+	completion.Choices = append(completion.Choices, struct {
+		Index   int         `json:"index"`
+		Message ChatMessage `json:"message"`
+		Finish  string      `json:"finish_reason"`
+	}{
+		Index:   0,
+		Message: ChatMessage{Role: role, Content: text.String()},
+		Finish:  anthropicFinishReason(r.StopReason),
+	})
+	completion.Usage.PromptTokens = r.Usage.InputTokens
+	completion.Usage.CompletionTokens = r.Usage.OutputTokens
+	completion.Usage.TotalTokens = r.Usage.InputTokens + r.Usage.OutputTokens
+	return completion
+}
+
+// anthropicFinishReason maps Messages API stop reasons to OpenAI finish reasons.
+func anthropicFinishReason(stopReason string) string {
+	switch stopReason {
+	case "end_turn", "stop_sequence":
+		return "stop"
+	case "max_tokens":
+		return "length"
+	case "tool_use":
+		return "tool_calls"
+	default:
+		return stopReason
+	}
+}
@@ -0,0 +1,171 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAnthropicProvider_CreateChatCompletion(t *testing.T) {
+	var got anthropicRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/messages" {
+			t.Errorf("expected /v1/messages, got %s", r.URL.Path)
+		}
+		if r.Header.Get("x-api-key") != "sk-ant-test" {
+			t.Errorf("x-api-key = %q, want sk-ant-test", r.Header.Get("x-api-key"))
+		}
+		if r.Header.Get("anthropic-version") != AnthropicAPIVersion {
+			t.Errorf("anthropic-version = %q, want %q", r.Header.Get("anthropic-version"), AnthropicAPIVersion)
+		}
+		if r.Header.Get("Authorization") != "" {
+			t.Errorf("unexpected Authorization header %q", r.Header.Get("Authorization"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id": "msg_01",
+			"type": "message",
+			"role": "assistant",
+			"model": "claude-sonnet-4-5",
+			"content": [
+				{"type": "text", "text": "Hello, "},
+				{"type": "tool_use", "id": "tu_1", "name": "noop", "input": {}},
+				{"type": "text", "text": "world"}
+			],
+			"stop_reason": "end_turn",
+			"usage": {"input_tokens": 12, "output_tokens": 30}
+		}`))
+	}))
+	defer server.Close()
+
+	p := NewAnthropicProvider(server.URL+"/v1", "sk-ant-test", 2048)
+	resp, err := p.CreateChatCompletion(context.Background(), &ChatCompletionRequest{
+		Model: "claude-sonnet-4-5",
+		Messages: []ChatMessage{
+			{Role: "system", Content: "You are terse."},
+			{Role: "user", Content: "Say hello"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+
+	if got.System != "You are terse." {
+		t.Errorf("system = %q, want hoisted system prompt", got.System)
+	}
+	if len(got.Messages) != 1 || got.Messages[0].Role != "user" {
+		t.Errorf("messages = %+v, want single user message", got.Messages)
+	}
+	if got.MaxTokens != 2048 {
+		t.Errorf("max_tokens = %d, want provider default 2048", got.MaxTokens)
+	}
+
+	if len(resp.Choices) != 1 {
+		t.Fatalf("expected 1 choice, got %d", len(resp.Choices))
+	}
+	if resp.Choices[0].Message.Content != "Hello, world" {
+		t.Errorf("content = %q, want %q", resp.Choices[0].Message.Content, "Hello, world")
+	}
+	if resp.Choices[0].Finish != "stop" {
+		t.Errorf("finish = %q, want stop", resp.Choices[0].Finish)
+	}
+	if resp.Usage.TotalTokens != 42 {
+		t.Errorf("TotalTokens = %d, want 42", resp.Usage.TotalTokens)
+	}
+}
+
+func TestAnthropicProvider_RequestMaxTokensOverridesDefault(t *testing.T) {
+	p := NewAnthropicProvider("", "", 0)
+	if p.endpoint != DefaultAnthropicEndpoint {
+		t.Errorf("endpoint = %q, want %q", p.endpoint, DefaultAnthropicEndpoint)
+	}
+
+	req := p.buildRequest(&ChatCompletionRequest{Model: "m"})
+	if req.MaxTokens != DefaultAnthropicMaxTokens {
+		t.Errorf("max_tokens = %d, want %d", req.MaxTokens, DefaultAnthropicMaxTokens)
+	}
+
+	req = p.buildRequest(&ChatCompletionRequest{Model: "m", MaxTokens: 100})
+	if req.MaxTokens != 100 {
+		t.Errorf("max_tokens = %d, want 100", req.MaxTokens)
+	}
+}
+
+func TestAnthropicProvider_ContextLengthError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"type":"error","error":{"type":"invalid_request_error","message":"prompt is too long"}}`))
+	}))
+	defer server.Close()
+
+	p := NewAnthropicProvider(server.URL, "key", 0)
+	_, err := p.CreateChatCompletion(context.Background(), &ChatCompletionRequest{
+		Model:    "claude",
+		Messages: []ChatMessage{{Role: "user", Content: "hi"}},
+	})
+	if _, ok := err.(*ContextLengthError); !ok {
+		t.Fatalf("expected *ContextLengthError, got %T: %v", err, err)
+	}
+}
+
+func TestAnthropicProvider_GetModels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/models" {
+			t.Errorf("expected /models, got %s", r.URL.Path)
+		}
+		if r.Header.Get("x-api-key") != "key" {
+			t.Errorf("missing x-api-key header")
+		}
+		_, _ = w.Write([]byte(`{"data":[{"id":"claude-sonnet-4-5","type":"model","created_at":"2025-09-29T00:00:00Z"},{"id":""}]}`))
+	}))
+	defer server.Close()
+
+	p := NewAnthropicProvider(server.URL, "key", 0)
+	models, err := p.GetModels(context.Background())
+	if err != nil {
+		t.Fatalf("GetModels: %v", err)
+	}
+	if len(models) != 1 || models[0].ID != "claude-sonnet-4-5" {
+		t.Fatalf("models = %+v, want single claude-sonnet-4-5", models)
+	}
+	if models[0].Created == 0 {
+		t.Errorf("expected created_at to be parsed")
+	}
+}
+
+func TestRegistry_AnthropicProviderIsActive(t *testing.T) {
+	r := NewRegistry()
+	if err := r.Register(&ProviderConfig{
+		ID:        "claude",
+		Type:      "anthropic",
+		APIKey:    "key",
+		Model:     "claude-sonnet-4-5",
+		MaxTokens: 8192,
+		Status:    "healthy",
+	}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	rp, err := r.Get("claude")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	ap, ok := rp.Protocol.(*AnthropicProvider)
+	if !ok {
+		t.Fatalf("protocol = %T, want *AnthropicProvider", rp.Protocol)
+	}
+	if ap.maxTokens != 8192 {
+		t.Errorf("maxTokens = %d, want 8192", ap.maxTokens)
+	}
+	if !r.IsActive("claude") {
+		t.Error("expected anthropic provider to be active")
+	}
+	if active := r.ListActive(); len(active) != 1 || active[0].Config.ID != "claude" {
+		t.Errorf("ListActive = %v, want [claude]", active)
+	}
+}
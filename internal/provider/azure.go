@@ -0,0 +1,75 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// DefaultAzureOpenAIAPIVersion is used when an azure-openai provider has no
+// api-version configured.
+const DefaultAzureOpenAIAPIVersion = "2024-10-21"
+
+// NewAzureOpenAIProvider creates a provider for an Azure OpenAI deployment.
+// Azure serves models under deployment names, authenticates with an api-key
+// header and versions the API with an api-version query parameter; requests
+// and responses otherwise match OpenAI.
+func NewAzureOpenAIProvider(endpoint, apiKey, deployment, apiVersion string) *OpenAIProvider {
+	p := NewOpenAIProvider(endpoint, apiKey)
+	p.deployment = deployment
+	p.apiVersion = apiVersion
+	if p.apiVersion == "" {
+		p.apiVersion = DefaultAzureOpenAIAPIVersion
+	}
+	return p
+}
+
+func (p *OpenAIProvider) chatCompletionsURL() string {
+	if p.deployment == "" {
+		return fmt.Sprintf("%s/chat/completions", p.endpoint)
+	}
+	return fmt.Sprintf("%s/openai/deployments/%s/chat/completions?api-version=%s",
+		p.endpoint, url.PathEscape(p.deployment), url.QueryEscape(p.apiVersion))
+}
+
+func (p *OpenAIProvider) setAuth(httpReq *http.Request) {
+	if p.apiKey == "" {
+		return
+	}
+	if p.deployment != "" {
+		httpReq.Header.Set("api-key", p.apiKey)
+		return
+	}
+	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", p.apiKey))
+}
+
+// getAzureModels checks that the resource is reachable with the configured
+// key and reports the deployment as the provider's only model, since Azure
+// routes requests by deployment rather than model ID.
+func (p *OpenAIProvider) getAzureModels(ctx context.Context) ([]Model, error) {
+	modelsURL := fmt.Sprintf("%s/openai/models?api-version=%s", p.endpoint, url.QueryEscape(p.apiVersion))
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, modelsURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	p.setAuth(httpReq)
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, sendError(err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError(resp.StatusCode, string(respBody))
+	}
+
+	return []Model{{ID: p.deployment, Object: "model", OwnedBy: "azure-openai"}}, nil
+}
@@ -0,0 +1,105 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAzureOpenAIProvider_CreateChatCompletion(t *testing.T) {
+	var got ChatCompletionRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/openai/deployments/gpt-4o-prod/chat/completions" {
+			t.Errorf("path = %s, want deployment chat completions", r.URL.Path)
+		}
+		if v := r.URL.Query().Get("api-version"); v != "2024-06-01" {
+			t.Errorf("api-version = %q, want 2024-06-01", v)
+		}
+		if r.Header.Get("api-key") != "azure-key" {
+			t.Errorf("api-key = %q, want azure-key", r.Header.Get("api-key"))
+		}
+		if r.Header.Get("Authorization") != "" {
+			t.Errorf("unexpected Authorization header %q", r.Header.Get("Authorization"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id": "chatcmpl-1",
+			"object": "chat.completion",
+			"model": "gpt-4o",
+			"choices": [{"index": 0, "message": {"role": "assistant", "content": "hi"}, "finish_reason": "stop"}],
+			"usage": {"prompt_tokens": 5, "completion_tokens": 2, "total_tokens": 7}
+		}`))
+	}))
+	defer server.Close()
+
+	p := NewAzureOpenAIProvider(server.URL+"/", "azure-key", "gpt-4o-prod", "2024-06-01")
+	resp, err := p.CreateChatCompletion(context.Background(), &ChatCompletionRequest{
+		Model:    "gpt-4o",
+		Messages: []ChatMessage{{Role: "user", Content: "hello"}},
+	})
+	if err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+	if len(got.Messages) != 1 || got.Messages[0].Content != "hello" {
+		t.Errorf("request messages = %+v", got.Messages)
+	}
+	if resp.Choices[0].Message.Content != "hi" {
+		t.Errorf("content = %q, want hi", resp.Choices[0].Message.Content)
+	}
+	if resp.Usage.TotalTokens != 7 {
+		t.Errorf("total tokens = %d, want 7", resp.Usage.TotalTokens)
+	}
+}
+
+func TestAzureOpenAIProvider_GetModels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/openai/models" {
+			t.Errorf("path = %s, want /openai/models", r.URL.Path)
+		}
+		if v := r.URL.Query().Get("api-version"); v != DefaultAzureOpenAIAPIVersion {
+			t.Errorf("api-version = %q, want default %q", v, DefaultAzureOpenAIAPIVersion)
+		}
+		_, _ = w.Write([]byte(`{"data": [{"id": "gpt-4o-2024-08-06"}]}`))
+	}))
+	defer server.Close()
+
+	p := NewAzureOpenAIProvider(server.URL, "azure-key", "gpt-4o-prod", "")
+	models, err := p.GetModels(context.Background())
+	if err != nil {
+		t.Fatalf("GetModels: %v", err)
+	}
+	if len(models) != 1 || models[0].ID != "gpt-4o-prod" {
+		t.Errorf("models = %+v, want the deployment", models)
+	}
+}
+
+func TestRegistry_AzureOpenAIRequiresDeployment(t *testing.T) {
+	r := NewRegistry()
+	err := r.Register(&ProviderConfig{ID: "azure", Type: "azure-openai", Endpoint: "https://example.openai.azure.com"})
+	if err == nil {
+		t.Fatal("expected registration without a deployment name to fail")
+	}
+
+	if err := r.Register(&ProviderConfig{
+		ID:             "azure",
+		Type:           "azure-openai",
+		Endpoint:       "https://example.openai.azure.com",
+		APIKey:         "key",
+		DeploymentName: "gpt-4o-prod",
+	}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	rp, _ := r.Get("azure")
+	op, ok := rp.Protocol.(*OpenAIProvider)
+	if !ok {
+		t.Fatalf("protocol = %T, want *OpenAIProvider", rp.Protocol)
+	}
+	if op.apiVersion != DefaultAzureOpenAIAPIVersion {
+		t.Errorf("apiVersion = %q, want default", op.apiVersion)
+	}
+}
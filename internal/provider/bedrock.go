@@ -0,0 +1,335 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// BedrockAnthropicVersion is sent in the body of Anthropic model invocations.
+	BedrockAnthropicVersion = "bedrock-2023-05-31"
+
+	bedrockService = "bedrock"
+)
+
+// BedrockError is returned when Bedrock rejects a request. Type carries the
+// x-amzn-ErrorType header, e.g. ThrottlingException or AccessDeniedException.
+type BedrockError struct {
+	StatusCode int
+	Type       string
+	Message    string
+}
+
+func (e *BedrockError) Error() string {
+	return fmt.Sprintf("bedrock %s (HTTP %d): %s", e.Type, e.StatusCode, e.Message)
+}
+
+// Unwrap returns the error category; throttling is reported as rate limiting
+// whatever its status code.
+func (e *BedrockError) Unwrap() error {
+	if strings.HasPrefix(e.Type, "ThrottlingException") {
+		return ErrRateLimited
+	}
+	return statusCategory(e.StatusCode)
+}
+
+// Retryable reports whether the error is a throttle or a server-side failure.
+func (e *BedrockError) Retryable() bool {
+	return e.StatusCode >= 500 || e.StatusCode == http.StatusTooManyRequests ||
+		strings.HasPrefix(e.Type, "ThrottlingException")
+}
+
+// RequiresSignedCredentials reports whether a provider type authenticates by
+// signing requests with cloud credentials rather than sending an API key.
+// Such providers need credentials even when no key is configured, since they
+// can fall back to the environment.
+func RequiresSignedCredentials(providerType string) bool {
+	return providerType == "bedrock"
+}
+
+// BedrockProvider implements Protocol for AWS Bedrock's InvokeModel API,
+// signing requests with SigV4. Request and response bodies depend on the
+// model family, so only Anthropic Claude and Amazon Titan text models are
+// supported.
+// See: https://docs.aws.amazon.com/bedrock/latest/APIReference/API_runtime_InvokeModel.html
+type BedrockProvider struct {
+	endpoint  string
+	region    string
+	creds     awsCredentials
+	maxTokens int
+	client    *http.Client
+	now       func() time.Time
+}
+
+// NewBedrockProvider creates a provider for the Bedrock runtime in region.
+// An empty endpoint uses the regional bedrock-runtime host.
+func NewBedrockProvider(endpoint, region string, creds awsCredentials, maxTokens int) *BedrockProvider {
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://bedrock-runtime.%s.amazonaws.com", region)
+	}
+	if maxTokens <= 0 {
+		maxTokens = DefaultAnthropicMaxTokens
+	}
+	return &BedrockProvider{
+		endpoint:  endpoint,
+		region:    region,
+		creds:     creds,
+		maxTokens: maxTokens,
+		client: &http.Client{
+			Timeout: 15 * time.Minute,
+		},
+		now: time.Now,
+	}
+}
+
+// newBedrockFromConfig resolves the region and credentials for a bedrock
+// provider. The region may be given directly or taken from a regional
+// endpoint; credentials come from the API key or the AWS_* environment.
+func newBedrockFromConfig(config *ProviderConfig) (*BedrockProvider, error) {
+	region := strings.TrimSpace(config.Region)
+	if region == "" {
+		region = bedrockRegionFromEndpoint(config.Endpoint)
+	}
+	if region == "" {
+		return nil, fmt.Errorf("bedrock provider %s requires a region", config.ID)
+	}
+	creds, err := resolveAWSCredentials(config.APIKey)
+	if err != nil {
+		return nil, fmt.Errorf("bedrock provider %s: %w", config.ID, err)
+	}
+	return NewBedrockProvider(config.Endpoint, region, creds, config.MaxTokens), nil
+}
+
+// bedrockRegionFromEndpoint extracts the region from hosts of the form
+// bedrock-runtime.<region>.amazonaws.com.
+func bedrockRegionFromEndpoint(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return ""
+	}
+	parts := strings.Split(u.Hostname(), ".")
+	if len(parts) >= 4 && strings.HasPrefix(parts[0], "bedrock") && parts[len(parts)-2] == "amazonaws" {
+		return parts[1]
+	}
+	return ""
+}
+
+type bedrockAnthropicRequest struct {
+	AnthropicVersion string             `json:"anthropic_version"`
+	System           string             `json:"system,omitempty"`
+	Messages         []anthropicMessage `json:"messages"`
+	MaxTokens        int                `json:"max_tokens"`
+	Temperature      float64            `json:"temperature,omitempty"`
+}
+
+type titanRequest struct {
+	InputText            string `json:"inputText"`
+	TextGenerationConfig struct {
+		MaxTokenCount int     `json:"maxTokenCount"`
+		Temperature   float64 `json:"temperature"`
+	} `json:"textGenerationConfig"`
+}
+
+type titanResponse struct {
+	InputTextTokenCount int `json:"inputTextTokenCount"`
+	Results             []struct {
+		TokenCount       int    `json:"tokenCount"`
+		OutputText       string `json:"outputText"`
+		CompletionReason string `json:"completionReason"`
+	} `json:"results"`
+}
+
+// CreateChatCompletion invokes the requested model and maps its
+// family-specific response back into a ChatCompletionResponse.
+func (p *BedrockProvider) CreateChatCompletion(ctx context.Context, req *ChatCompletionRequest) (*ChatCompletionResponse, error) {
+	var payload interface{}
+	switch {
+	case strings.Contains(req.Model, "anthropic."):
+		msg := buildAnthropicRequest(req, p.maxTokens)
+		payload = &bedrockAnthropicRequest{
+			AnthropicVersion: BedrockAnthropicVersion,
+			System:           msg.System,
+			Messages:         msg.Messages,
+			MaxTokens:        msg.MaxTokens,
+			Temperature:      msg.Temperature,
+		}
+	case strings.Contains(req.Model, "amazon.titan-text"):
+		payload = p.buildTitanRequest(req)
+	default:
+		return nil, fmt.Errorf("bedrock model %q is not supported: only anthropic and amazon.titan-text models can be invoked", req.Model)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	invokeURL := fmt.Sprintf("%s/model/%s/invoke", p.endpoint, awsURIEscape(req.Model))
+	respBody, err := p.do(ctx, http.MethodPost, invokeURL, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.Contains(req.Model, "anthropic.") {
+		var msgResp anthropicResponse
+		if err := unmarshalJSON(respBody, &msgResp); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+		if msgResp.Model == "" {
+			msgResp.Model = req.Model
+		}
+		return msgResp.toChatCompletion(), nil
+	}
+
+	var titanResp titanResponse
+	if err := unmarshalJSON(respBody, &titanResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return titanResp.toChatCompletion(req.Model), nil
+}
+
+// GetModels lists foundation models via the Bedrock control-plane API, which
+// is served from the bedrock host rather than bedrock-runtime.
+func (p *BedrockProvider) GetModels(ctx context.Context) ([]Model, error) {
+	modelsURL := strings.Replace(p.endpoint, "://bedrock-runtime.", "://bedrock.", 1) + "/foundation-models"
+
+	respBody, err := p.do(ctx, http.MethodGet, modelsURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var modelsResp struct {
+		ModelSummaries []struct {
+			ModelID      string `json:"modelId"`
+			ProviderName string `json:"providerName"`
+		} `json:"modelSummaries"`
+	}
+	if err := unmarshalJSON(respBody, &modelsResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	models := make([]Model, 0, len(modelsResp.ModelSummaries))
+	for _, m := range modelsResp.ModelSummaries {
+		if strings.TrimSpace(m.ModelID) == "" {
+			continue
+		}
+		models = append(models, Model{ID: m.ModelID, Object: "model", OwnedBy: m.ProviderName})
+	}
+	return models, nil
+}
+
+// do sends a signed request and returns the body of a 200 response. Error
+// responses become a BedrockError, or a ContextLengthError when the input is
+// too long for the model.
+func (p *BedrockProvider) do(ctx context.Context, method, rawURL string, body []byte) ([]byte, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, method, rawURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	setTraceHeader(httpReq)
+	signV4(httpReq, body, p.creds, p.region, bedrockService, p.now())
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, sendError(err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode == http.StatusOK {
+		return respBody, nil
+	}
+
+	message := string(respBody)
+	var awsErr struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(respBody, &awsErr) == nil && awsErr.Message != "" {
+		message = awsErr.Message
+	}
+	if resp.StatusCode == http.StatusBadRequest && isContextLengthError(message) {
+		return nil, &ContextLengthError{StatusCode: resp.StatusCode, Body: message}
+	}
+	errType, _, _ := strings.Cut(resp.Header.Get("X-Amzn-ErrorType"), ":")
+	if errType == "" {
+		errType = "UnknownError"
+	}
+	return nil, &BedrockError{StatusCode: resp.StatusCode, Type: errType, Message: message}
+}
+
+// buildTitanRequest flattens the conversation into Titan's single-prompt
+// format.
+func (p *BedrockProvider) buildTitanRequest(req *ChatCompletionRequest) *titanRequest {
+	var prompt strings.Builder
+	for _, msg := range req.Messages {
+		switch msg.Role {
+		case "system":
+			prompt.WriteString(msg.Content + "\n\n")
+		case "assistant":
+			prompt.WriteString("Bot: " + msg.Content + "\n")
+		default:
+			prompt.WriteString("User: " + msg.Content + "\n")
+		}
+	}
+	prompt.WriteString("Bot:")
+
+	out := &titanRequest{InputText: prompt.String()}
+	out.TextGenerationConfig.MaxTokenCount = req.MaxTokens
+	if out.TextGenerationConfig.MaxTokenCount <= 0 {
+		out.TextGenerationConfig.MaxTokenCount = p.maxTokens
+	}
+	out.TextGenerationConfig.Temperature = req.Temperature
+	return out
+}
+
+func (r *titanResponse) toChatCompletion(model string) *ChatCompletionResponse {
+	completion := &ChatCompletionResponse{
+		Object:  "chat.completion",
+		Created: time.Now().Unix(),
+		Model:   model,
+	}
+	completion.Usage.PromptTokens = r.InputTextTokenCount
+	for i, result := range r.Results {
+		completion.Choices = append(completion.Choices, struct {
+			Index   int         `json:"index"`
+			Message ChatMessage `json:"message"`
+			Finish  string      `json:"finish_reason"`
+		}{
+			Index:   i,
+			Message: ChatMessage{Role: "assistant", Content: strings.TrimSpace(result.OutputText)},
+			Finish:  titanFinishReason(result.CompletionReason),
+		})
+		completion.Usage.CompletionTokens += result.TokenCount
+	}
+	completion.Usage.TotalTokens = completion.Usage.PromptTokens + completion.Usage.CompletionTokens
+	return completion
+}
+
+// titanFinishReason maps Titan completion reasons to OpenAI finish reasons.
+func titanFinishReason(reason string) string {
+	switch reason {
+	case "FINISH":
+		return "stop"
+	case "LENGTH":
+		return "length"
+	case "CONTENT_FILTERED":
+		return "content_filter"
+	default:
+		return strings.ToLower(reason)
+	}
+}
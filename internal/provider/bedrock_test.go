@@ -0,0 +1,257 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testAWSCreds = awsCredentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+
+func TestSignV4_VanillaGet(t *testing.T) {
+	// AWS SigV4 test suite: get-vanilla
+	req, _ := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	signV4(req, nil, testAWSCreds, "us-east-1", "service", time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+
+	want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+		"SignedHeaders=host;x-amz-date, " +
+		"Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Errorf("Authorization =\n%s\nwant\n%s", got, want)
+	}
+	if got := req.Header.Get("X-Amz-Date"); got != "20150830T123600Z" {
+		t.Errorf("X-Amz-Date = %q", got)
+	}
+}
+
+func TestSignV4_SessionToken(t *testing.T) {
+	creds := testAWSCreds
+	creds.SessionToken = "session"
+	req, _ := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	signV4(req, nil, creds, "us-east-1", "service", time.Now())
+
+	if req.Header.Get("X-Amz-Security-Token") != "session" {
+		t.Errorf("X-Amz-Security-Token = %q, want session", req.Header.Get("X-Amz-Security-Token"))
+	}
+	if !strings.Contains(req.Header.Get("Authorization"), "SignedHeaders=host;x-amz-date;x-amz-security-token") {
+		t.Errorf("session token not signed: %s", req.Header.Get("Authorization"))
+	}
+}
+
+func TestResolveAWSCredentials(t *testing.T) {
+	creds, err := resolveAWSCredentials("AKID:secret:token")
+	if err != nil || creds.AccessKeyID != "AKID" || creds.SecretAccessKey != "secret" || creds.SessionToken != "token" {
+		t.Errorf("configured creds = %+v, %v", creds, err)
+	}
+	if _, err := resolveAWSCredentials("just-a-key"); err == nil {
+		t.Error("expected error for key without secret")
+	}
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "ENVKEY")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "envsecret")
+	t.Setenv("AWS_SESSION_TOKEN", "")
+	creds, err = resolveAWSCredentials("")
+	if err != nil || creds.AccessKeyID != "ENVKEY" || creds.SecretAccessKey != "envsecret" {
+		t.Errorf("env creds = %+v, %v", creds, err)
+	}
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	if _, err := resolveAWSCredentials(""); err == nil {
+		t.Error("expected error without credentials")
+	}
+}
+
+func TestBedrockProvider_Anthropic(t *testing.T) {
+	var got bedrockAnthropicRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/model/anthropic.claude-3-5-sonnet-20240620-v1%3A0/invoke" {
+			t.Errorf("path = %s", r.URL.EscapedPath())
+		}
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/") ||
+			!strings.Contains(r.Header.Get("Authorization"), "/us-west-2/bedrock/aws4_request") {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		_, _ = w.Write([]byte(`{
+			"id": "msg_1",
+			"type": "message",
+			"role": "assistant",
+			"content": [{"type": "text", "text": "hi"}],
+			"stop_reason": "end_turn",
+			"usage": {"input_tokens": 12, "output_tokens": 3}
+		}`))
+	}))
+	defer server.Close()
+
+	p := NewBedrockProvider(server.URL, "us-west-2", testAWSCreds, 512)
+	resp, err := p.CreateChatCompletion(context.Background(), &ChatCompletionRequest{
+		Model: "anthropic.claude-3-5-sonnet-20240620-v1:0",
+		Messages: []ChatMessage{
+			{Role: "system", Content: "be brief"},
+			{Role: "user", Content: "hello"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+	if got.AnthropicVersion != BedrockAnthropicVersion || got.System != "be brief" || got.MaxTokens != 512 {
+		t.Errorf("request = %+v", got)
+	}
+	if len(got.Messages) != 1 || got.Messages[0].Role != "user" {
+		t.Errorf("request messages = %+v", got.Messages)
+	}
+	if resp.Choices[0].Message.Content != "hi" || resp.Choices[0].Finish != "stop" {
+		t.Errorf("choice = %+v", resp.Choices[0])
+	}
+	if resp.Usage.TotalTokens != 15 {
+		t.Errorf("total tokens = %d, want 15", resp.Usage.TotalTokens)
+	}
+}
+
+func TestBedrockProvider_Titan(t *testing.T) {
+	var got titanRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		_, _ = w.Write([]byte(`{
+			"inputTextTokenCount": 8,
+			"results": [{"tokenCount": 4, "outputText": " hi there", "completionReason": "FINISH"}]
+		}`))
+	}))
+	defer server.Close()
+
+	p := NewBedrockProvider(server.URL, "us-east-1", testAWSCreds, 0)
+	resp, err := p.CreateChatCompletion(context.Background(), &ChatCompletionRequest{
+		Model:    "amazon.titan-text-express-v1",
+		Messages: []ChatMessage{{Role: "user", Content: "hello"}},
+	})
+	if err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+	if got.InputText != "User: hello\nBot:" {
+		t.Errorf("inputText = %q", got.InputText)
+	}
+	if got.TextGenerationConfig.MaxTokenCount != DefaultAnthropicMaxTokens {
+		t.Errorf("maxTokenCount = %d", got.TextGenerationConfig.MaxTokenCount)
+	}
+	if resp.Choices[0].Message.Content != "hi there" {
+		t.Errorf("content = %q", resp.Choices[0].Message.Content)
+	}
+	if resp.Usage.PromptTokens != 8 || resp.Usage.CompletionTokens != 4 {
+		t.Errorf("usage = %+v", resp.Usage)
+	}
+}
+
+func TestBedrockProvider_UnsupportedModel(t *testing.T) {
+	p := NewBedrockProvider("http://127.0.0.1:1", "us-east-1", testAWSCreds, 0)
+	_, err := p.CreateChatCompletion(context.Background(), &ChatCompletionRequest{Model: "meta.llama3-8b-instruct-v1:0"})
+	if err == nil || !strings.Contains(err.Error(), "not supported") {
+		t.Errorf("err = %v, want unsupported model error", err)
+	}
+}
+
+func TestBedrockProvider_Errors(t *testing.T) {
+	status := http.StatusTooManyRequests
+	errType := "ThrottlingException:http://internal.amazon.com/coral/com.amazon.bedrock/"
+	message := "Too many requests"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Amzn-ErrorType", errType)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"message": "` + message + `"}`))
+	}))
+	defer server.Close()
+
+	p := NewBedrockProvider(server.URL, "us-east-1", testAWSCreds, 0)
+	req := &ChatCompletionRequest{Model: "anthropic.claude-v2", Messages: []ChatMessage{{Role: "user", Content: "x"}}}
+
+	_, err := p.CreateChatCompletion(context.Background(), req)
+	var bedrockErr *BedrockError
+	if !errors.As(err, &bedrockErr) {
+		t.Fatalf("err = %v, want BedrockError", err)
+	}
+	if bedrockErr.Type != "ThrottlingException" || bedrockErr.Message != message {
+		t.Errorf("BedrockError = %+v", bedrockErr)
+	}
+	if !IsFailoverError(err) {
+		t.Error("throttling should fail over")
+	}
+
+	status, errType, message = http.StatusForbidden, "AccessDeniedException", "not authorized"
+	_, err = p.CreateChatCompletion(context.Background(), req)
+	if IsFailoverError(err) {
+		t.Errorf("access denied should not fail over: %v", err)
+	}
+
+	status, errType, message = http.StatusBadRequest, "ValidationException", "Input is too long for requested model."
+	_, err = p.CreateChatCompletion(context.Background(), req)
+	var ctxErr *ContextLengthError
+	if !errors.As(err, &ctxErr) {
+		t.Errorf("err = %v, want ContextLengthError", err)
+	}
+}
+
+func TestBedrockProvider_GetModels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/foundation-models" {
+			t.Errorf("path = %s, want /foundation-models", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"modelSummaries": [
+			{"modelId": "anthropic.claude-v2", "providerName": "Anthropic"},
+			{"modelId": "amazon.titan-text-express-v1", "providerName": "Amazon"}
+		]}`))
+	}))
+	defer server.Close()
+
+	p := NewBedrockProvider(server.URL, "us-east-1", testAWSCreds, 0)
+	models, err := p.GetModels(context.Background())
+	if err != nil {
+		t.Fatalf("GetModels: %v", err)
+	}
+	if len(models) != 2 || models[0].ID != "anthropic.claude-v2" || models[1].OwnedBy != "Amazon" {
+		t.Errorf("models = %+v", models)
+	}
+}
+
+func TestRegistry_RegisterBedrock(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+
+	r := NewRegistry()
+	err := r.Register(&ProviderConfig{ID: "br", Type: "bedrock", APIKey: "AKID:secret"})
+	if err == nil || !strings.Contains(err.Error(), "region") {
+		t.Errorf("err = %v, want missing region error", err)
+	}
+	err = r.Register(&ProviderConfig{ID: "br", Type: "bedrock", Region: "us-east-1"})
+	if err == nil || !strings.Contains(err.Error(), "credentials") {
+		t.Errorf("err = %v, want missing credentials error", err)
+	}
+
+	err = r.Register(&ProviderConfig{
+		ID:       "br",
+		Type:     "bedrock",
+		Endpoint: "https://bedrock-runtime.eu-central-1.amazonaws.com",
+		APIKey:   "AKID:secret",
+	})
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	registered, _ := r.Get("br")
+	bp, ok := registered.Protocol.(*BedrockProvider)
+	if !ok {
+		t.Fatalf("protocol = %T, want *BedrockProvider", registered.Protocol)
+	}
+	if bp.region != "eu-central-1" {
+		t.Errorf("region = %q, want eu-central-1 from endpoint", bp.region)
+	}
+	if !RequiresSignedCredentials("bedrock") || RequiresSignedCredentials("openai") {
+		t.Error("RequiresSignedCredentials mismatch")
+	}
+}
@@ -0,0 +1,208 @@
+package provider
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of a provider's circuit breaker.
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"    // Requests flow normally
+	CircuitOpen     CircuitState = "open"      // Provider is out of rotation
+	CircuitHalfOpen CircuitState = "half-open" // Cooldown elapsed; one trial request decides
+)
+
+const (
+	// DefaultCircuitFailureThreshold is how many consecutive failures open a
+	// provider's circuit.
+	DefaultCircuitFailureThreshold = 5
+	// DefaultCircuitCooldown is how long a circuit stays open before a trial
+	// request is allowed.
+	DefaultCircuitCooldown = time.Minute
+)
+
+// circuitBreaker tracks consecutive failures for one provider.
+type circuitBreaker struct {
+	mu             sync.Mutex
+	failures       int
+	open           bool
+	openedAt       time.Time
+	probing        bool
+	probeStartedAt time.Time
+}
+
+// state reports the breaker state; an open circuit becomes half-open once the
+// cooldown has elapsed. While a trial request is in flight the circuit stays
+// open to everyone else; a trial that has not finished within a cooldown is
+// given up on.
+func (b *circuitBreaker) state(now time.Time, cooldown time.Duration) CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.stateLocked(now, cooldown)
+}
+
+// stateLocked is state for callers holding b.mu.
+func (b *circuitBreaker) stateLocked(now time.Time, cooldown time.Duration) CircuitState {
+	if !b.open {
+		return CircuitClosed
+	}
+	if now.Sub(b.openedAt) < cooldown {
+		return CircuitOpen
+	}
+	if b.probing && now.Sub(b.probeStartedAt) < cooldown {
+		return CircuitOpen
+	}
+	return CircuitHalfOpen
+}
+
+// allow reports whether a request may be sent. A half-open circuit admits a
+// single trial request and claims it until its outcome is recorded.
+func (b *circuitBreaker) allow(now time.Time, cooldown time.Duration) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.stateLocked(now, cooldown) {
+	case CircuitClosed:
+		return true
+	case CircuitHalfOpen:
+		b.probing = true
+		b.probeStartedAt = now
+		return true
+	}
+	return false
+}
+
+// release ends a trial request without deciding the circuit, so the next
+// request may try again.
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// record applies an outcome and reports whether it opened the circuit. A
+// failure in the half-open state reopens it immediately.
+func (b *circuitBreaker) record(failed bool, now time.Time, threshold int) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	if !failed {
+		b.failures = 0
+		b.open = false
+		return false
+	}
+	b.failures++
+	if b.open || b.failures >= threshold {
+		b.open = true
+		b.openedAt = now
+		return true
+	}
+	return false
+}
+
+// SetCircuitBreaker configures how many consecutive failures open a
+// provider's circuit and how long it stays open. Values <= 0 use the
+// defaults.
+func (r *Registry) SetCircuitBreaker(threshold int, cooldown time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.circuitThreshold = threshold
+	r.circuitCooldown = cooldown
+}
+
+// RecordOutcome feeds a request outcome to the provider's circuit breaker.
+// Only ErrProviderUnavailable counts as a failure. A rate-limited request
+// leaves the breaker as it is, apart from ending a trial request; any other
+// outcome shows the provider is up and closes the circuit.
+func (r *Registry) RecordOutcome(providerID string, err error) {
+	err = ClassifyError(err)
+
+	r.mu.Lock()
+	if _, exists := r.providers[providerID]; !exists {
+		r.mu.Unlock()
+		return
+	}
+	breaker := r.breakerLocked(providerID)
+	threshold := r.circuitThreshold
+	r.mu.Unlock()
+
+	if errors.Is(err, ErrRateLimited) {
+		breaker.release()
+		return
+	}
+	if threshold <= 0 {
+		threshold = DefaultCircuitFailureThreshold
+	}
+	if breaker.record(errors.Is(err, ErrProviderUnavailable), time.Now(), threshold) {
+		log.Printf("[Registry] Circuit opened for provider %s: %v", providerID, err)
+	}
+}
+
+// AllowRequest reports whether a request may be sent to a provider now. It is
+// true while the circuit is closed; once it is half-open, only the first
+// caller is let through as a trial and the circuit stays open to others until
+// that request's outcome is passed to RecordOutcome. Callers that get true must
+// record the outcome. Unknown providers have no circuit and are allowed.
+func (r *Registry) AllowRequest(providerID string) bool {
+	r.mu.Lock()
+	if _, exists := r.providers[providerID]; !exists {
+		r.mu.Unlock()
+		return true
+	}
+	breaker := r.breakerLocked(providerID)
+	cooldown := r.circuitCooldownLocked()
+	r.mu.Unlock()
+	return breaker.allow(time.Now(), cooldown)
+}
+
+// breakerLocked returns the provider's circuit breaker, creating it if
+// needed. The caller must hold r.mu for writing.
+func (r *Registry) breakerLocked(providerID string) *circuitBreaker {
+	if r.breakers == nil {
+		r.breakers = make(map[string]*circuitBreaker)
+	}
+	breaker, ok := r.breakers[providerID]
+	if !ok {
+		breaker = &circuitBreaker{}
+		r.breakers[providerID] = breaker
+	}
+	return breaker
+}
+
+// circuitCooldownLocked returns the configured cooldown or the default. The
+// caller must hold r.mu.
+func (r *Registry) circuitCooldownLocked() time.Duration {
+	if r.circuitCooldown <= 0 {
+		return DefaultCircuitCooldown
+	}
+	return r.circuitCooldown
+}
+
+// CircuitState returns the state of a provider's circuit breaker. Providers
+// with no recorded outcomes are closed.
+func (r *Registry) CircuitState(providerID string) CircuitState {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.circuitStateLocked(providerID)
+}
+
+// circuitStateLocked is CircuitState for callers holding r.mu.
+func (r *Registry) circuitStateLocked(providerID string) CircuitState {
+	breaker, ok := r.breakers[providerID]
+	if !ok {
+		return CircuitClosed
+	}
+	return breaker.state(time.Now(), r.circuitCooldownLocked())
+}
+
+// isAvailable reports whether a provider is healthy and its circuit is not
+// open. The caller must hold r.mu.
+func (r *Registry) isAvailable(p *RegisteredProvider) bool {
+	if p == nil || p.Config == nil || !isProviderHealthy(p.Config.Status) {
+		return false
+	}
+	return r.circuitStateLocked(p.Config.ID) != CircuitOpen
+}
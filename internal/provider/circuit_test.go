@@ -0,0 +1,184 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker_Transitions(t *testing.T) {
+	b := &circuitBreaker{}
+	start := time.Now()
+
+	if b.record(true, start, 2) {
+		t.Fatal("opened below threshold")
+	}
+	if got := b.state(start, time.Minute); got != CircuitClosed {
+		t.Fatalf("state = %s, want closed", got)
+	}
+	if !b.record(true, start, 2) {
+		t.Fatal("did not open at threshold")
+	}
+	if got := b.state(start.Add(30*time.Second), time.Minute); got != CircuitOpen {
+		t.Fatalf("state = %s, want open during cooldown", got)
+	}
+
+	halfOpen := start.Add(time.Minute)
+	if got := b.state(halfOpen, time.Minute); got != CircuitHalfOpen {
+		t.Fatalf("state = %s, want half-open after cooldown", got)
+	}
+	if !b.record(true, halfOpen, 2) {
+		t.Fatal("failed trial did not reopen the circuit")
+	}
+	if got := b.state(halfOpen.Add(time.Second), time.Minute); got != CircuitOpen {
+		t.Fatalf("state = %s, want open after failed trial", got)
+	}
+
+	b.record(false, halfOpen.Add(2*time.Minute), 2)
+	if got := b.state(halfOpen.Add(2*time.Minute), time.Minute); got != CircuitClosed {
+		t.Fatalf("state = %s, want closed after success", got)
+	}
+}
+
+func TestRegistry_CircuitBreaker(t *testing.T) {
+	r := NewRegistry()
+	r.SetCircuitBreaker(2, time.Hour)
+	_ = r.Register(&ProviderConfig{ID: "p1", Type: "mock", Status: "active"})
+	_ = r.Register(&ProviderConfig{ID: "p2", Type: "mock", Status: "active"})
+	_ = r.RegisterGroup("pool", []WeightedProvider{{ProviderID: "p1", Weight: 1}})
+
+	serverErr := fmt.Errorf("unexpected status code 503: overloaded")
+	r.RecordOutcome("p1", serverErr)
+	if got := r.CircuitState("p1"); got != CircuitClosed {
+		t.Fatalf("state = %s, want closed after one failure", got)
+	}
+
+	// Client errors show the provider is up and reset the count.
+	r.RecordOutcome("p1", errors.New("unexpected status code 400: bad request"))
+	r.RecordOutcome("p1", serverErr)
+	if got := r.CircuitState("p1"); got != CircuitClosed {
+		t.Fatalf("state = %s, want closed after a client error reset", got)
+	}
+
+	r.RecordOutcome("p1", serverErr)
+	if got := r.CircuitState("p1"); got != CircuitOpen {
+		t.Fatalf("state = %s, want open", got)
+	}
+	if r.IsActive("p1") {
+		t.Error("IsActive reports an open provider as active")
+	}
+	if r.IsActive("pool") {
+		t.Error("group with only an open member reported active")
+	}
+	for _, p := range r.ListActive() {
+		if p.Config.ID == "p1" {
+			t.Error("ListActive includes an open provider")
+		}
+	}
+
+	r.RecordOutcome("p1", nil)
+	if got := r.CircuitState("p1"); got != CircuitClosed || !r.IsActive("p1") {
+		t.Errorf("state = %s, want closed and active after success", got)
+	}
+
+	if got := r.CircuitState("missing"); got != CircuitClosed {
+		t.Errorf("unknown provider state = %s, want closed", got)
+	}
+}
+
+func TestRegistry_CircuitHalfOpenAfterCooldown(t *testing.T) {
+	r := NewRegistry()
+	r.SetCircuitBreaker(1, time.Millisecond)
+	_ = r.Register(&ProviderConfig{ID: "p1", Type: "mock", Status: "active"})
+
+	r.RecordOutcome("p1", errors.New("failed to send request: connection refused"))
+	time.Sleep(5 * time.Millisecond)
+
+	if got := r.CircuitState("p1"); got != CircuitHalfOpen {
+		t.Fatalf("state = %s, want half-open", got)
+	}
+	if !r.IsActive("p1") {
+		t.Error("half-open provider should be active for a trial request")
+	}
+}
+
+func TestCircuitBreaker_SingleProbe(t *testing.T) {
+	b := &circuitBreaker{}
+	start := time.Now()
+	b.record(true, start, 1)
+
+	halfOpen := start.Add(time.Minute)
+	if !b.allow(halfOpen, time.Minute) {
+		t.Fatal("half-open circuit refused the trial request")
+	}
+	if b.allow(halfOpen, time.Minute) {
+		t.Fatal("half-open circuit admitted a second request while the trial is in flight")
+	}
+	if got := b.state(halfOpen, time.Minute); got != CircuitOpen {
+		t.Fatalf("state = %s, want open while the trial is in flight", got)
+	}
+
+	b.release()
+	if !b.allow(halfOpen, time.Minute) {
+		t.Fatal("released trial was not offered to the next request")
+	}
+	if !b.allow(halfOpen.Add(time.Minute), time.Minute) {
+		t.Fatal("stale trial blocked the circuit past the cooldown")
+	}
+
+	b.record(false, halfOpen.Add(time.Minute), 1)
+	if !b.allow(halfOpen.Add(time.Minute), time.Minute) || !b.allow(halfOpen.Add(time.Minute), time.Minute) {
+		t.Fatal("closed circuit refused requests after a successful trial")
+	}
+}
+
+func TestRegistry_AllowRequestHalfOpen(t *testing.T) {
+	r := NewRegistry()
+	r.SetCircuitBreaker(1, 20*time.Millisecond)
+	_ = r.Register(&ProviderConfig{ID: "p1", Type: "mock", Status: "active"})
+
+	unavailable := errors.New("failed to send request: connection refused")
+	r.RecordOutcome("p1", unavailable)
+	if r.AllowRequest("p1") {
+		t.Fatal("open circuit allowed a request")
+	}
+	time.Sleep(30 * time.Millisecond)
+
+	if !r.AllowRequest("p1") {
+		t.Fatal("half-open circuit refused the trial request")
+	}
+	if r.AllowRequest("p1") {
+		t.Fatal("second request admitted while the trial is in flight")
+	}
+	if r.IsActive("p1") {
+		t.Error("provider reported active while its trial is in flight")
+	}
+
+	// A rate-limited trial ends without deciding the circuit.
+	r.RecordOutcome("p1", errors.New("unexpected status code 429: slow down"))
+	if got := r.CircuitState("p1"); got != CircuitHalfOpen {
+		t.Fatalf("state = %s, want half-open after a rate-limited trial", got)
+	}
+
+	if !r.AllowRequest("p1") {
+		t.Fatal("half-open circuit refused the next trial")
+	}
+	r.RecordOutcome("p1", unavailable)
+	if got := r.CircuitState("p1"); got != CircuitOpen {
+		t.Fatalf("state = %s, want open after a failed trial", got)
+	}
+	time.Sleep(30 * time.Millisecond)
+
+	if !r.AllowRequest("p1") {
+		t.Fatal("half-open circuit refused the trial request")
+	}
+	r.RecordOutcome("p1", nil)
+	if !r.AllowRequest("p1") || !r.AllowRequest("p1") {
+		t.Error("closed circuit refused requests after a successful trial")
+	}
+
+	if !r.AllowRequest("missing") {
+		t.Error("unknown provider refused")
+	}
+}
@@ -0,0 +1,106 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Provider error categories. Errors returned by providers wrap the category
+// that applies, so callers can check them with errors.Is instead of matching
+// on messages.
+var (
+	ErrRateLimited         = errors.New("rate limited")
+	ErrUnauthorized        = errors.New("unauthorized")
+	ErrProviderUnavailable = errors.New("provider unavailable")
+	ErrContextLength       = errors.New("context length exceeded")
+)
+
+var statusCodePattern = regexp.MustCompile(`status code (\d{3})`)
+
+// StatusError is returned when a provider answers with an unexpected HTTP
+// status. It unwraps to the category for the status, if any.
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code %d: %s", e.StatusCode, e.Body)
+}
+
+func (e *StatusError) Unwrap() error {
+	return statusCategory(e.StatusCode)
+}
+
+// statusCategory returns the error category for an HTTP status, or nil.
+func statusCategory(code int) error {
+	switch {
+	case code == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case code == http.StatusUnauthorized || code == http.StatusForbidden:
+		return ErrUnauthorized
+	case code >= 500:
+		return ErrProviderUnavailable
+	}
+	return nil
+}
+
+// statusError builds the error for a non-OK response.
+func statusError(code int, body string) error {
+	if code == http.StatusBadRequest && isContextLengthError(body) {
+		return &ContextLengthError{StatusCode: code, Body: body}
+	}
+	return &StatusError{StatusCode: code, Body: body}
+}
+
+// sendError wraps a failure to reach a provider. A cancelled request is not
+// the provider's fault and is left uncategorised.
+func sendError(err error) error {
+	if errors.Is(err, context.Canceled) {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	return fmt.Errorf("failed to send request: %w", &categorizedError{category: ErrProviderUnavailable, err: err})
+}
+
+// categorizedError attaches a category to an error without changing its
+// message.
+type categorizedError struct {
+	category error
+	err      error
+}
+
+func (e *categorizedError) Error() string   { return e.err.Error() }
+func (e *categorizedError) Unwrap() []error { return []error{e.category, e.err} }
+
+// ClassifyError returns err wrapped in its category when it does not carry
+// one yet, e.g. an "unexpected status code 429" string from a provider that
+// predates the typed errors. Errors with no recognisable category are
+// returned unchanged.
+func ClassifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+	for _, category := range []error{ErrRateLimited, ErrUnauthorized, ErrProviderUnavailable, ErrContextLength} {
+		if errors.Is(err, category) {
+			return err
+		}
+	}
+
+	msg := err.Error()
+	var category error
+	if m := statusCodePattern.FindStringSubmatch(msg); m != nil {
+		code, _ := strconv.Atoi(m[1])
+		category = statusCategory(code)
+	} else if strings.Contains(msg, "failed to send request") && !errors.Is(err, context.Canceled) {
+		category = ErrProviderUnavailable
+	}
+	if category == nil {
+		return err
+	}
+	return &categorizedError{category: category, err: err}
+}
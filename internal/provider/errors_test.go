@@ -0,0 +1,124 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestStatusError_Categories(t *testing.T) {
+	tests := []struct {
+		code int
+		body string
+		want error
+	}{
+		{http.StatusTooManyRequests, "slow down", ErrRateLimited},
+		{http.StatusUnauthorized, "bad key", ErrUnauthorized},
+		{http.StatusForbidden, "forbidden", ErrUnauthorized},
+		{http.StatusServiceUnavailable, "overloaded", ErrProviderUnavailable},
+		{http.StatusBadRequest, "maximum context length is 8192 tokens", ErrContextLength},
+		{http.StatusBadRequest, "bad request", nil},
+	}
+	categories := []error{ErrRateLimited, ErrUnauthorized, ErrProviderUnavailable, ErrContextLength}
+	for _, tt := range tests {
+		err := statusError(tt.code, tt.body)
+		for _, category := range categories {
+			if got := errors.Is(err, category); got != (category == tt.want) {
+				t.Errorf("errors.Is(status %d, %v) = %v", tt.code, category, got)
+			}
+		}
+	}
+
+	if got := statusError(http.StatusBadGateway, "down").Error(); got != "unexpected status code 502: down" {
+		t.Errorf("Error() = %q, want the legacy message", got)
+	}
+}
+
+func TestProviderErrors_FromHTTP(t *testing.T) {
+	status := http.StatusUnauthorized
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"error": "nope"}`))
+	}))
+	defer server.Close()
+
+	p := NewOpenAIProvider(server.URL, "key")
+	req := &ChatCompletionRequest{Model: "m", Messages: []ChatMessage{{Role: "user", Content: "x"}}}
+
+	_, err := p.CreateChatCompletion(context.Background(), req)
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusUnauthorized || !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("err = %v, want unauthorized StatusError", err)
+	}
+
+	status = http.StatusTooManyRequests
+	if _, err := p.CreateChatCompletion(context.Background(), req); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("err = %v, want ErrRateLimited", err)
+	}
+
+	server.Close()
+	if _, err := p.CreateChatCompletion(context.Background(), req); !errors.Is(err, ErrProviderUnavailable) {
+		t.Errorf("err = %v, want ErrProviderUnavailable for a closed server", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := p.CreateChatCompletion(ctx, req); err == nil || errors.Is(err, ErrProviderUnavailable) {
+		t.Errorf("err = %v, want a cancelled request to stay uncategorised", err)
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want error
+	}{
+		{fmt.Errorf("unexpected status code 429: slow down"), ErrRateLimited},
+		{fmt.Errorf("plugin: unexpected status code 401: denied"), ErrUnauthorized},
+		{fmt.Errorf("unexpected status code 500: boom"), ErrProviderUnavailable},
+		{fmt.Errorf("failed to send request: %w", errors.New("connection refused")), ErrProviderUnavailable},
+		{&BedrockError{StatusCode: http.StatusBadRequest, Type: "ThrottlingException"}, ErrRateLimited},
+		{&ContextLengthError{StatusCode: http.StatusBadRequest}, ErrContextLength},
+	}
+	for _, tt := range tests {
+		got := ClassifyError(tt.err)
+		if !errors.Is(got, tt.want) {
+			t.Errorf("ClassifyError(%v) does not match %v", tt.err, tt.want)
+		}
+		if got.Error() != tt.err.Error() {
+			t.Errorf("ClassifyError changed message %q to %q", tt.err, got)
+		}
+	}
+
+	plain := errors.New("failed to unmarshal response")
+	if got := ClassifyError(plain); got != plain {
+		t.Errorf("ClassifyError(%v) = %v, want it unchanged", plain, got)
+	}
+	if ClassifyError(nil) != nil {
+		t.Error("ClassifyError(nil) should be nil")
+	}
+}
+
+func TestRegistry_CircuitIgnoresRateLimits(t *testing.T) {
+	r := NewRegistry()
+	r.SetCircuitBreaker(2, time.Hour)
+	_ = r.Register(&ProviderConfig{ID: "p1", Type: "mock", Status: "active"})
+
+	serverErr := statusError(http.StatusServiceUnavailable, "overloaded")
+	r.RecordOutcome("p1", serverErr)
+	// Throttling neither opens the circuit nor resets the failure count.
+	for i := 0; i < 3; i++ {
+		r.RecordOutcome("p1", statusError(http.StatusTooManyRequests, "slow down"))
+	}
+	if got := r.CircuitState("p1"); got != CircuitClosed {
+		t.Fatalf("state = %s, want closed after rate limiting", got)
+	}
+	r.RecordOutcome("p1", serverErr)
+	if got := r.CircuitState("p1"); got != CircuitOpen {
+		t.Errorf("state = %s, want open after a second server error", got)
+	}
+}
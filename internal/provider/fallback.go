@@ -0,0 +1,126 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// GetWithFallback returns the first active provider in the fallback chain
+// starting at providerID.
+func (r *Registry) GetWithFallback(providerID string) (*RegisteredProvider, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, exists := r.providers[providerID]; !exists {
+		return nil, fmt.Errorf("provider %s not found", providerID)
+	}
+	for _, id := range r.fallbackChainLocked(providerID) {
+		p := r.providers[id]
+		if r.isAvailable(p) {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("no active provider in fallback chain for %s", providerID)
+}
+
+// FallbackChain returns the registered provider IDs in providerID's fallback
+// chain, starting with providerID itself. Each provider's fallbacks are
+// expanded depth-first in the order they are listed.
+func (r *Registry) FallbackChain(providerID string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.fallbackChainLocked(providerID)
+}
+
+// ActiveFallbacks returns the active providers that follow providerID in its
+// fallback chain.
+func (r *Registry) ActiveFallbacks(providerID string) []*RegisteredProvider {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	chain := r.fallbackChainLocked(providerID)
+	fallbacks := make([]*RegisteredProvider, 0, len(chain))
+	for _, id := range chain {
+		if id == providerID {
+			continue
+		}
+		p := r.providers[id]
+		if r.isAvailable(p) {
+			fallbacks = append(fallbacks, p)
+		}
+	}
+	return fallbacks
+}
+
+// fallbackChainLocked expands the chain; the caller must hold r.mu.
+func (r *Registry) fallbackChainLocked(providerID string) []string {
+	var chain []string
+	visited := make(map[string]bool)
+	var walk func(id string)
+	walk = func(id string) {
+		p, exists := r.providers[id]
+		if visited[id] || !exists || p == nil || p.Config == nil {
+			return
+		}
+		visited[id] = true
+		chain = append(chain, id)
+		for _, next := range p.Config.FallbackIDs {
+			walk(next)
+		}
+	}
+	walk(providerID)
+	return chain
+}
+
+// validateFallbacks rejects a config whose fallback chain leads back to
+// itself. Fallback IDs that are not registered yet are allowed; a cycle they
+// would close is caught when the last provider in it registers. The caller
+// must hold r.mu.
+func (r *Registry) validateFallbacks(config *ProviderConfig) error {
+	fallbacksOf := func(id string) []string {
+		if id == config.ID {
+			return config.FallbackIDs
+		}
+		if p, ok := r.providers[id]; ok && p != nil && p.Config != nil {
+			return p.Config.FallbackIDs
+		}
+		return nil
+	}
+
+	visited := make(map[string]bool)
+	var path []string
+	var walk func(id string) bool
+	walk = func(id string) bool {
+		path = append(path, id)
+		for _, next := range fallbacksOf(id) {
+			if next == config.ID {
+				path = append(path, next)
+				return true
+			}
+			if visited[next] {
+				continue
+			}
+			visited[next] = true
+			if walk(next) {
+				return true
+			}
+		}
+		path = path[:len(path)-1]
+		return false
+	}
+
+	if walk(config.ID) {
+		return fmt.Errorf("fallback chain for provider %s contains a cycle: %s", config.ID, strings.Join(path, " -> "))
+	}
+	return nil
+}
+
+// IsFailoverError reports whether a provider error should be retried against
+// a fallback provider: the provider is unavailable (connection failure or
+// 5xx) or rate limited. Client errors such as bad requests, rejected
+// credentials or context length are not.
+func IsFailoverError(err error) bool {
+	err = ClassifyError(err)
+	return errors.Is(err, ErrProviderUnavailable) || errors.Is(err, ErrRateLimited)
+}
@@ -0,0 +1,114 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func registerMock(t *testing.T, r *Registry, id, status string, fallbacks ...string) {
+	t.Helper()
+	if err := r.Register(&ProviderConfig{ID: id, Type: "mock", Status: status, FallbackIDs: fallbacks}); err != nil {
+		t.Fatalf("Register(%s): %v", id, err)
+	}
+}
+
+func TestRegistry_GetWithFallback(t *testing.T) {
+	r := NewRegistry()
+	registerMock(t, r, "primary", "disabled", "secondary", "tertiary")
+	registerMock(t, r, "secondary", "disabled")
+	registerMock(t, r, "tertiary", "healthy")
+
+	p, err := r.GetWithFallback("primary")
+	if err != nil {
+		t.Fatalf("GetWithFallback: %v", err)
+	}
+	if p.Config.ID != "tertiary" {
+		t.Errorf("got %s, want tertiary", p.Config.ID)
+	}
+
+	if err := r.Upsert(&ProviderConfig{ID: "primary", Type: "mock", Status: "healthy", FallbackIDs: []string{"secondary"}}); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+	p, err = r.GetWithFallback("primary")
+	if err != nil || p.Config.ID != "primary" {
+		t.Errorf("expected active primary to be returned, got %v, %v", p, err)
+	}
+
+	if _, err := r.GetWithFallback("missing"); err == nil {
+		t.Error("expected error for unknown provider")
+	}
+	if _, err := r.GetWithFallback("secondary"); err == nil {
+		t.Error("expected error when no provider in the chain is active")
+	}
+}
+
+func TestRegistry_FallbackChainOrder(t *testing.T) {
+	r := NewRegistry()
+	registerMock(t, r, "a", "healthy", "b", "c")
+	registerMock(t, r, "b", "healthy", "d")
+	registerMock(t, r, "c", "disabled")
+	registerMock(t, r, "d", "healthy", "c")
+
+	want := []string{"a", "b", "d", "c"}
+	if got := r.FallbackChain("a"); !reflect.DeepEqual(got, want) {
+		t.Errorf("FallbackChain = %v, want %v", got, want)
+	}
+
+	var active []string
+	for _, p := range r.ActiveFallbacks("a") {
+		active = append(active, p.Config.ID)
+	}
+	if !reflect.DeepEqual(active, []string{"b", "d"}) {
+		t.Errorf("ActiveFallbacks = %v, want [b d]", active)
+	}
+}
+
+func TestRegistry_RejectsFallbackCycles(t *testing.T) {
+	r := NewRegistry()
+	if err := r.Register(&ProviderConfig{ID: "self", Type: "mock", FallbackIDs: []string{"self"}}); err == nil {
+		t.Error("expected self-referencing fallback to be rejected")
+	}
+
+	// b is not registered yet, so a's chain is fine until b closes the loop
+	registerMock(t, r, "a", "healthy", "b")
+	err := r.Register(&ProviderConfig{ID: "b", Type: "mock", FallbackIDs: []string{"c", "a"}})
+	if err == nil {
+		t.Fatal("expected cycle a -> b -> a to be rejected")
+	}
+	if !strings.Contains(err.Error(), "b -> a -> b") {
+		t.Errorf("error = %q, want cycle path", err)
+	}
+	if _, getErr := r.Get("b"); getErr == nil {
+		t.Error("rejected provider should not be registered")
+	}
+
+	registerMock(t, r, "b", "healthy")
+	if err := r.Upsert(&ProviderConfig{ID: "b", Type: "mock", FallbackIDs: []string{"a"}}); err == nil {
+		t.Error("expected Upsert to reject a cycle")
+	}
+}
+
+func TestIsFailoverError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{fmt.Errorf("unexpected status code 503: overloaded"), true},
+		{fmt.Errorf("unexpected status code 500: boom"), true},
+		{fmt.Errorf("unexpected status code 429: slow down"), true},
+		{&StatusError{StatusCode: 401}, false},
+		{fmt.Errorf("unexpected status code 400: bad request"), false},
+		{fmt.Errorf("failed to send request: %w", errors.New("connection refused")), true},
+		{&ContextLengthError{}, false},
+		{errors.New("failed to unmarshal response"), false},
+	}
+	for _, tt := range tests {
+		if got := IsFailoverError(tt.err); got != tt.want {
+			t.Errorf("IsFailoverError(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
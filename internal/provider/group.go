@@ -0,0 +1,121 @@
+package provider
+
+import (
+	"fmt"
+	"math/rand/v2"
+)
+
+// WeightedProvider is a member of a provider group
+type WeightedProvider struct {
+	ProviderID string `json:"provider_id"`
+	Weight     int    `json:"weight"` // Relative share of picks; must be positive
+}
+
+// RegisterGroup registers (or replaces) a group of providers that share load
+// in proportion to their weights. Agents may reference the group ID wherever
+// a provider ID is expected.
+func (r *Registry) RegisterGroup(groupID string, members []WeightedProvider) error {
+	if groupID == "" {
+		return fmt.Errorf("group ID is required")
+	}
+	if len(members) == 0 {
+		return fmt.Errorf("group %s has no members", groupID)
+	}
+	seen := make(map[string]bool, len(members))
+	for _, m := range members {
+		if m.Weight <= 0 {
+			return fmt.Errorf("group %s: member %s must have a positive weight", groupID, m.ProviderID)
+		}
+		if seen[m.ProviderID] {
+			return fmt.Errorf("group %s: duplicate member %s", groupID, m.ProviderID)
+		}
+		seen[m.ProviderID] = true
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.providers[groupID]; exists {
+		return fmt.Errorf("group ID %s conflicts with a registered provider", groupID)
+	}
+	if r.groups == nil {
+		r.groups = make(map[string][]WeightedProvider)
+	}
+	r.groups[groupID] = append([]WeightedProvider(nil), members...)
+	return nil
+}
+
+// UnregisterGroup removes a provider group
+func (r *Registry) UnregisterGroup(groupID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.groups[groupID]; !exists {
+		return fmt.Errorf("group %s not found", groupID)
+	}
+	delete(r.groups, groupID)
+	return nil
+}
+
+// IsGroup returns true if id names a registered provider group.
+func (r *Registry) IsGroup(id string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, exists := r.groups[id]
+	return exists
+}
+
+// PickFromGroup selects an active member of the group with probability
+// proportional to its weight. Inactive and unregistered members are skipped.
+func (r *Registry) PickFromGroup(groupID string) (*RegisteredProvider, error) {
+	// Write lock: the shared RNG is not safe for concurrent use.
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	members, exists := r.groups[groupID]
+	if !exists {
+		return nil, fmt.Errorf("group %s not found", groupID)
+	}
+
+	candidates := make([]*RegisteredProvider, 0, len(members))
+	weights := make([]int, 0, len(members))
+	total := 0
+	for _, m := range members {
+		p := r.providers[m.ProviderID]
+		if !r.isAvailable(p) {
+			continue
+		}
+		candidates = append(candidates, p)
+		weights = append(weights, m.Weight)
+		total += m.Weight
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no active providers in group %s", groupID)
+	}
+
+	if r.rng == nil {
+		r.rng = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+	}
+	n := r.rng.IntN(total)
+	for i, w := range weights {
+		if n < w {
+			return candidates[i], nil
+		}
+		n -= w
+	}
+	return candidates[len(candidates)-1], nil
+}
+
+// Resolve returns the provider registered under id, or a weighted pick when
+// id names a provider group.
+func (r *Registry) Resolve(id string) (*RegisteredProvider, error) {
+	if r.IsGroup(id) {
+		return r.PickFromGroup(id)
+	}
+	return r.Get(id)
+}
+
+// SeedGroupPicker makes group picks deterministic. Intended for tests.
+func (r *Registry) SeedGroupPicker(seed uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rng = rand.New(rand.NewPCG(seed, seed))
+}
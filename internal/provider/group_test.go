@@ -0,0 +1,141 @@
+package provider
+
+import (
+	"sync"
+	"testing"
+)
+
+func newGroupRegistry(t *testing.T) *Registry {
+	t.Helper()
+	r := NewRegistry()
+	registerMock(t, r, "key-a", "healthy")
+	registerMock(t, r, "key-b", "healthy")
+	registerMock(t, r, "key-c", "disabled")
+	if err := r.RegisterGroup("gpt-pool", []WeightedProvider{
+		{ProviderID: "key-a", Weight: 3},
+		{ProviderID: "key-b", Weight: 1},
+		{ProviderID: "key-c", Weight: 10},
+	}); err != nil {
+		t.Fatalf("RegisterGroup: %v", err)
+	}
+	return r
+}
+
+func TestRegistry_PickFromGroup_Weighted(t *testing.T) {
+	r := newGroupRegistry(t)
+	r.SeedGroupPicker(42)
+
+	counts := make(map[string]int)
+	const picks = 4000
+	for i := 0; i < picks; i++ {
+		p, err := r.PickFromGroup("gpt-pool")
+		if err != nil {
+			t.Fatalf("PickFromGroup: %v", err)
+		}
+		counts[p.Config.ID]++
+	}
+
+	if counts["key-c"] != 0 {
+		t.Errorf("inactive member picked %d times", counts["key-c"])
+	}
+	// key-a should get ~75% of picks
+	share := float64(counts["key-a"]) / picks
+	if share < 0.70 || share > 0.80 {
+		t.Errorf("key-a share = %.2f, want ~0.75 (counts %v)", share, counts)
+	}
+}
+
+func TestRegistry_PickFromGroup_DeterministicWithSeed(t *testing.T) {
+	r1 := newGroupRegistry(t)
+	r2 := newGroupRegistry(t)
+	r1.SeedGroupPicker(7)
+	r2.SeedGroupPicker(7)
+
+	for i := 0; i < 50; i++ {
+		p1, _ := r1.PickFromGroup("gpt-pool")
+		p2, _ := r2.PickFromGroup("gpt-pool")
+		if p1.Config.ID != p2.Config.ID {
+			t.Fatalf("pick %d differs: %s vs %s", i, p1.Config.ID, p2.Config.ID)
+		}
+	}
+}
+
+func TestRegistry_PickFromGroup_NoActiveMembers(t *testing.T) {
+	r := NewRegistry()
+	registerMock(t, r, "down", "disabled")
+	if err := r.RegisterGroup("g", []WeightedProvider{{ProviderID: "down", Weight: 1}, {ProviderID: "missing", Weight: 1}}); err != nil {
+		t.Fatalf("RegisterGroup: %v", err)
+	}
+	if _, err := r.PickFromGroup("g"); err == nil {
+		t.Error("expected error when no member is active")
+	}
+	if r.IsActive("g") {
+		t.Error("group with no active members should not be active")
+	}
+	if _, err := r.PickFromGroup("nope"); err == nil {
+		t.Error("expected error for unknown group")
+	}
+}
+
+func TestRegistry_RegisterGroup_Validation(t *testing.T) {
+	r := NewRegistry()
+	registerMock(t, r, "p1", "healthy")
+
+	cases := map[string]struct {
+		id      string
+		members []WeightedProvider
+	}{
+		"empty id":        {"", []WeightedProvider{{ProviderID: "p1", Weight: 1}}},
+		"no members":      {"g", nil},
+		"zero weight":     {"g", []WeightedProvider{{ProviderID: "p1", Weight: 0}}},
+		"duplicate":       {"g", []WeightedProvider{{ProviderID: "p1", Weight: 1}, {ProviderID: "p1", Weight: 2}}},
+		"clashes with id": {"p1", []WeightedProvider{{ProviderID: "p1", Weight: 1}}},
+	}
+	for name, tc := range cases {
+		if err := r.RegisterGroup(tc.id, tc.members); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+
+	if err := r.RegisterGroup("g", []WeightedProvider{{ProviderID: "p1", Weight: 1}}); err != nil {
+		t.Fatalf("RegisterGroup: %v", err)
+	}
+	if err := r.Register(&ProviderConfig{ID: "g", Type: "mock"}); err == nil {
+		t.Error("expected provider registration to reject a group ID")
+	}
+}
+
+func TestRegistry_ResolveGroup(t *testing.T) {
+	r := newGroupRegistry(t)
+	if !r.IsActive("gpt-pool") {
+		t.Error("expected group with active members to be active")
+	}
+	p, err := r.Resolve("gpt-pool")
+	if err != nil {
+		t.Fatalf("Resolve(group): %v", err)
+	}
+	if p.Config.ID != "key-a" && p.Config.ID != "key-b" {
+		t.Errorf("Resolve picked %s", p.Config.ID)
+	}
+	if p, err := r.Resolve("key-c"); err != nil || p.Config.ID != "key-c" {
+		t.Errorf("Resolve(provider) = %v, %v", p, err)
+	}
+}
+
+func TestRegistry_PickFromGroup_Concurrent(t *testing.T) {
+	r := newGroupRegistry(t)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				if _, err := r.PickFromGroup("gpt-pool"); err != nil {
+					t.Errorf("PickFromGroup: %v", err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
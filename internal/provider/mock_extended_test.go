@@ -4,6 +4,7 @@ import (
 	"context"
 	"strings"
 	"testing"
+	"time"
 )
 
 // ---------------------------------------------------------------------------
@@ -227,3 +228,58 @@ func TestMockProvider_Stream_EmptyContent(t *testing.T) {
 		t.Errorf("expected default, got %q", content.String())
 	}
 }
+
+func TestMockProvider_CannedResponse(t *testing.T) {
+	p := &MockProvider{Response: "LGTM"}
+
+	resp, err := p.CreateChatCompletion(context.Background(), &ChatCompletionRequest{
+		Messages: []ChatMessage{{Role: "user", Content: "review this diff please"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Choices[0].Message.Content != "LGTM" {
+		t.Errorf("content = %q, want the canned response", resp.Choices[0].Message.Content)
+	}
+	// 23 characters of prompt and 4 of reply, at four characters per token.
+	if resp.Usage.PromptTokens != 6 || resp.Usage.CompletionTokens != 1 {
+		t.Errorf("usage = %d/%d, want 6/1", resp.Usage.PromptTokens, resp.Usage.CompletionTokens)
+	}
+}
+
+func TestMockProvider_Latency(t *testing.T) {
+	p := &MockProvider{Latency: 20 * time.Millisecond}
+
+	start := time.Now()
+	if _, err := p.CreateChatCompletion(context.Background(), &ChatCompletionRequest{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("returned after %v, want at least the simulated latency", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	p.Latency = time.Hour
+	if _, err := p.CreateChatCompletion(ctx, &ChatCompletionRequest{}); err == nil {
+		t.Error("expected the cancelled context to end the simulated latency")
+	}
+}
+
+func TestRegistry_RegistersMockWithoutKey(t *testing.T) {
+	r := NewRegistry()
+	if err := r.Register(&ProviderConfig{ID: "demo", Type: "mock", Model: "mock-model", MockResponse: "done", MockLatencyMs: 1}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	rp, err := r.Get("demo")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	resp, err := rp.Protocol.CreateChatCompletion(context.Background(), &ChatCompletionRequest{Model: "mock-model"})
+	if err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+	if resp.Choices[0].Message.Content != "done" {
+		t.Errorf("content = %q, want the configured response", resp.Choices[0].Message.Content)
+	}
+}
@@ -7,21 +7,26 @@ import (
 
 // MockProvider is an in-memory provider that returns canned responses.
 // It is useful for local development and smoke-testing when no real model endpoint is available.
-type MockProvider struct{}
+// Responses are deterministic: Response when set, otherwise an echo of the
+// last message, with token counts estimated at four characters per token.
+type MockProvider struct {
+	Response string        // canned reply; empty echoes the last message
+	Latency  time.Duration // simulated delay before each response
+}
 
 func NewMockProvider() *MockProvider {
 	return &MockProvider{}
 }
 
-// CreateChatCompletion returns a static echo response.
+// CreateChatCompletion returns the canned response or an echo of the last
+// message after the simulated latency.
 func (p *MockProvider) CreateChatCompletion(ctx context.Context, req *ChatCompletionRequest) (*ChatCompletionResponse, error) {
-	// Build a short echo message from the last user content.
-	content := "mock response"
-	if len(req.Messages) > 0 {
-		content = req.Messages[len(req.Messages)-1].Content
-		if content == "" {
-			content = "mock response"
-		}
+	if err := p.wait(ctx); err != nil {
+		return nil, err
+	}
+	content := p.Response
+	if content == "" {
+		content = "[mock] " + lastMessage(req)
 	}
 
 	resp := &ChatCompletionResponse{
@@ -38,14 +43,16 @@ func (p *MockProvider) CreateChatCompletion(ctx context.Context, req *ChatComple
 				Index: 0,
 				Message: ChatMessage{
 					Role:    "assistant",
-					Content: "[mock] " + content,
+					Content: content,
 				},
 				Finish: "stop",
 			},
 		},
 	}
-	resp.Usage.PromptTokens = len(content)
-	resp.Usage.CompletionTokens = len(resp.Choices[0].Message.Content)
+	for _, m := range req.Messages {
+		resp.Usage.PromptTokens += mockTokens(m.Content)
+	}
+	resp.Usage.CompletionTokens = mockTokens(content)
 	resp.Usage.TotalTokens = resp.Usage.PromptTokens + resp.Usage.CompletionTokens
 	return resp, nil
 }
@@ -61,3 +68,33 @@ func (p *MockProvider) GetModels(ctx context.Context) ([]Model, error) {
 		},
 	}, nil
 }
+
+// wait sleeps for the simulated latency, returning early if ctx ends.
+func (p *MockProvider) wait(ctx context.Context) error {
+	if p.Latency <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(p.Latency)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// lastMessage returns the content of the request's last message, or
+// "mock response" when there is none.
+func lastMessage(req *ChatCompletionRequest) string {
+	if len(req.Messages) > 0 && req.Messages[len(req.Messages)-1].Content != "" {
+		return req.Messages[len(req.Messages)-1].Content
+	}
+	return "mock response"
+}
+
+// mockTokens estimates a token count at four characters per token, rounding
+// up so any text counts as at least one token.
+func mockTokens(s string) int {
+	return (len(s) + 3) / 4
+}
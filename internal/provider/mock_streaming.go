@@ -9,16 +9,13 @@ import (
 // CreateChatCompletionStream implements streaming for MockProvider
 // Simulates streaming by sending the response in chunks
 func (p *MockProvider) CreateChatCompletionStream(ctx context.Context, req *ChatCompletionRequest, handler StreamHandler) error {
-	// Build mock response content
-	content := "mock response"
-	if len(req.Messages) > 0 {
-		content = req.Messages[len(req.Messages)-1].Content
-		if content == "" {
-			content = "mock response"
-		}
+	if err := p.wait(ctx); err != nil {
+		return err
+	}
+	fullContent := p.Response
+	if fullContent == "" {
+		fullContent = "[mock streaming] " + lastMessage(req)
 	}
-
-	fullContent := "[mock streaming] " + content
 
 	// Simulate streaming by sending content word-by-word
 	words := []rune(fullContent)
@@ -0,0 +1,96 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultModelCacheTTL is how long a provider's model list is reused before
+// ListModels queries the provider again.
+const DefaultModelCacheTTL = 5 * time.Minute
+
+// modelCacheEntry is a provider's model list as of fetchedAt.
+type modelCacheEntry struct {
+	models    []Model
+	fetchedAt time.Time
+}
+
+// ModelNotAvailableError reports that a provider does not serve a model.
+type ModelNotAvailableError struct {
+	Model      string
+	ProviderID string
+}
+
+func (e *ModelNotAvailableError) Error() string {
+	return fmt.Sprintf("model %s not available on provider %s", e.Model, e.ProviderID)
+}
+
+// SetModelCacheTTL configures how long ListModels results are cached.
+// Values <= 0 use DefaultModelCacheTTL.
+func (r *Registry) SetModelCacheTTL(ttl time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.modelCacheTTL = ttl
+}
+
+// ListModels returns the models a provider serves, querying the provider
+// only when the cached list is missing or older than the cache TTL.
+func (r *Registry) ListModels(ctx context.Context, providerID string) ([]Model, error) {
+	r.mu.RLock()
+	entry, ok := r.modelCache[providerID]
+	ttl := r.modelCacheTTL
+	r.mu.RUnlock()
+	if ttl <= 0 {
+		ttl = DefaultModelCacheTTL
+	}
+	if ok && time.Since(entry.fetchedAt) < ttl {
+		return entry.models, nil
+	}
+	return r.RefreshModels(ctx, providerID)
+}
+
+// RefreshModels queries a provider for its models and replaces the cached
+// list. A failed query leaves the previous list in place.
+func (r *Registry) RefreshModels(ctx context.Context, providerID string) ([]Model, error) {
+	provider, err := r.Get(providerID)
+	if err != nil {
+		return nil, err
+	}
+	models, err := provider.Protocol.GetModels(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list models for provider %s: %w", providerID, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// Skip caching if the provider was replaced or removed meanwhile.
+	if r.providers[providerID] == provider {
+		if r.modelCache == nil {
+			r.modelCache = make(map[string]*modelCacheEntry)
+		}
+		r.modelCache[providerID] = &modelCacheEntry{models: models, fetchedAt: time.Now()}
+	}
+	return models, nil
+}
+
+// ValidateModel checks that a provider serves model, using the cached model
+// list. An empty model is always valid. Ollama's implicit ":latest" tag is
+// ignored when comparing.
+func (r *Registry) ValidateModel(ctx context.Context, providerID, model string) error {
+	if model == "" {
+		return nil
+	}
+	models, err := r.ListModels(ctx, providerID)
+	if err != nil {
+		return err
+	}
+	want := strings.TrimSuffix(model, ":latest")
+	for _, m := range models {
+		if m.ID == model || strings.TrimSuffix(m.ID, ":latest") == want {
+			return nil
+		}
+	}
+	return &ModelNotAvailableError{Model: model, ProviderID: providerID}
+}
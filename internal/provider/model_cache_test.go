@@ -0,0 +1,103 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newModelsServer(t *testing.T, hits *atomic.Int32) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/models" {
+			http.NotFound(w, r)
+			return
+		}
+		hits.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":[{"id":"gpt-4o"},{"id":"llama3:latest"}]}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestRegistry_ListModelsCaches(t *testing.T) {
+	var hits atomic.Int32
+	srv := newModelsServer(t, &hits)
+	r := NewRegistry()
+	if err := r.Register(&ProviderConfig{ID: "openai-gpt4", Type: "openai", Endpoint: srv.URL, Model: "gpt-4o"}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		models, err := r.ListModels(ctx, "openai-gpt4")
+		if err != nil || len(models) != 2 {
+			t.Fatalf("ListModels = %v, %v", models, err)
+		}
+	}
+	if n := hits.Load(); n != 1 {
+		t.Errorf("expected one upstream query, got %d", n)
+	}
+
+	if _, err := r.RefreshModels(ctx, "openai-gpt4"); err != nil {
+		t.Fatalf("RefreshModels: %v", err)
+	}
+	if n := hits.Load(); n != 2 {
+		t.Errorf("refresh should bypass the cache, got %d queries", n)
+	}
+
+	r.SetModelCacheTTL(time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	if _, err := r.ListModels(ctx, "openai-gpt4"); err != nil {
+		t.Fatalf("ListModels: %v", err)
+	}
+	if n := hits.Load(); n != 3 {
+		t.Errorf("expired entry should be refetched, got %d queries", n)
+	}
+
+	// Re-registering a provider drops its cached models.
+	r.SetModelCacheTTL(0)
+	if err := r.Upsert(&ProviderConfig{ID: "openai-gpt4", Type: "openai", Endpoint: srv.URL, Model: "gpt-4o"}); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+	if _, err := r.ListModels(ctx, "openai-gpt4"); err != nil {
+		t.Fatalf("ListModels: %v", err)
+	}
+	if n := hits.Load(); n != 4 {
+		t.Errorf("upsert should invalidate the cache, got %d queries", n)
+	}
+}
+
+func TestRegistry_ValidateModel(t *testing.T) {
+	var hits atomic.Int32
+	srv := newModelsServer(t, &hits)
+	r := NewRegistry()
+	if err := r.Register(&ProviderConfig{ID: "openai-gpt4", Type: "openai", Endpoint: srv.URL, Model: "gpt-4o"}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	ctx := context.Background()
+
+	for _, model := range []string{"", "gpt-4o", "llama3", "llama3:latest"} {
+		if err := r.ValidateModel(ctx, "openai-gpt4", model); err != nil {
+			t.Errorf("ValidateModel(%q): %v", model, err)
+		}
+	}
+
+	err := r.ValidateModel(ctx, "openai-gpt4", "gpt-5")
+	var notAvailable *ModelNotAvailableError
+	if !errors.As(err, &notAvailable) {
+		t.Fatalf("expected ModelNotAvailableError, got %v", err)
+	}
+	if err.Error() != "model gpt-5 not available on provider openai-gpt4" {
+		t.Errorf("unexpected message %q", err.Error())
+	}
+
+	if err := r.ValidateModel(ctx, "missing", "gpt-4o"); err == nil {
+		t.Error("expected error for unknown provider")
+	}
+}
@@ -36,7 +36,7 @@ func (p *OllamaProvider) GetModels(ctx context.Context) ([]Model, error) {
 
 	resp, err := p.client.Do(httpReq)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return nil, sendError(err)
 	}
 	defer resp.Body.Close()
 
@@ -45,7 +45,7 @@ func (p *OllamaProvider) GetModels(ctx context.Context) ([]Model, error) {
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+		return nil, statusError(resp.StatusCode, string(body))
 	}
 
 	var tagsResp struct {
@@ -111,10 +111,11 @@ func (p *OllamaProvider) CreateChatCompletion(ctx context.Context, req *ChatComp
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
+	setTraceHeader(httpReq)
 
 	resp, err := p.client.Do(httpReq)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return nil, sendError(err)
 	}
 	defer resp.Body.Close()
 
@@ -123,11 +124,7 @@ func (p *OllamaProvider) CreateChatCompletion(ctx context.Context, req *ChatComp
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 	if resp.StatusCode != http.StatusOK {
-		bodyStr := string(respBody)
-		if resp.StatusCode == http.StatusBadRequest && isContextLengthError(bodyStr) {
-			return nil, &ContextLengthError{StatusCode: resp.StatusCode, Body: bodyStr}
-		}
-		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, bodyStr)
+		return nil, statusError(resp.StatusCode, string(respBody))
 	}
 
 	var ollamaResp struct {
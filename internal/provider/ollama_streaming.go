@@ -26,7 +26,8 @@ func (p *OllamaProvider) CreateChatCompletionStream(ctx context.Context, req *Ch
 			Role    string `json:"role"`
 			Content string `json:"content"`
 		} `json:"messages"`
-		Stream  bool `json:"stream"`
+		Stream  bool   `json:"stream"`
+		Format  string `json:"format,omitempty"`
 		Options struct {
 			Temperature float64 `json:"temperature,omitempty"`
 		} `json:"options,omitempty"`
@@ -35,6 +36,9 @@ func (p *OllamaProvider) CreateChatCompletionStream(ctx context.Context, req *Ch
 		Stream: true, // Enable streaming
 	}
 	ollamaReq.Options.Temperature = req.Temperature
+	if req.ResponseFormat != nil && req.ResponseFormat.Type == "json_object" {
+		ollamaReq.Format = "json"
+	}
 
 	for _, msg := range req.Messages {
 		ollamaReq.Messages = append(ollamaReq.Messages, struct {
@@ -54,18 +58,19 @@ func (p *OllamaProvider) CreateChatCompletionStream(ctx context.Context, req *Ch
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
+	setTraceHeader(httpReq)
 
 	// Send request
 	resp, err := p.client.Do(httpReq)
 	if err != nil {
-		return fmt.Errorf("failed to send request: %w", err)
+		return sendError(err)
 	}
 	defer resp.Body.Close()
 
 	// Check status
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(respBody))
+		return statusError(resp.StatusCode, string(respBody))
 	}
 
 	// Read streaming response (Ollama uses newline-delimited JSON, not SSE)
@@ -96,7 +101,9 @@ func (p *OllamaProvider) readOllamaStream(ctx context.Context, reader io.Reader,
 				Role    string `json:"role"`
 				Content string `json:"content"`
 			} `json:"message"`
-			Done bool `json:"done"`
+			Done            bool `json:"done"`
+			PromptEvalCount int  `json:"prompt_eval_count"`
+			EvalCount       int  `json:"eval_count"`
 		}
 
 		if err := json.Unmarshal(line, &ollamaChunk); err != nil {
@@ -131,9 +138,14 @@ func (p *OllamaProvider) readOllamaStream(ctx context.Context, reader io.Reader,
 			},
 		}
 
-		// Set finish reason on last chunk
+		// Set finish reason and usage on last chunk
 		if ollamaChunk.Done {
 			chunk.Choices[0].FinishReason = "stop"
+			chunk.Usage = &StreamUsage{
+				PromptTokens:     ollamaChunk.PromptEvalCount,
+				CompletionTokens: ollamaChunk.EvalCount,
+				TotalTokens:      ollamaChunk.PromptEvalCount + ollamaChunk.EvalCount,
+			}
 		}
 
 		// Call handler
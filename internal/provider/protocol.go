@@ -9,6 +9,8 @@ import (
 	"net/http"
 	"strings"
 	"time"
+
+	"github.com/jordanhubbard/loom/internal/observability"
 )
 
 // ContextLengthError is returned when the provider rejects a request because
@@ -23,6 +25,8 @@ func (e *ContextLengthError) Error() string {
 	return fmt.Sprintf("context length exceeded (HTTP %d): %s", e.StatusCode, e.Body)
 }
 
+func (e *ContextLengthError) Unwrap() error { return ErrContextLength }
+
 // isContextLengthError checks whether a provider error body indicates the
 // prompt exceeded the model's context window.
 func isContextLengthError(body string) bool {
@@ -116,6 +120,8 @@ type Model struct {
 type OpenAIProvider struct {
 	endpoint        string
 	apiKey          string
+	deployment      string // Azure OpenAI deployment; empty for OpenAI-compatible APIs
+	apiVersion      string // Azure OpenAI api-version query parameter
 	client          *http.Client
 	streamingClient *http.Client // Separate client for streaming (no timeout)
 }
@@ -142,7 +148,7 @@ func NewOpenAIProvider(endpoint, apiKey string) *OpenAIProvider {
 
 // CreateChatCompletion sends a chat completion request
 func (p *OpenAIProvider) CreateChatCompletion(ctx context.Context, req *ChatCompletionRequest) (*ChatCompletionResponse, error) {
-	url := fmt.Sprintf("%s/chat/completions", p.endpoint)
+	url := p.chatCompletionsURL()
 
 	// Marshal request body
 	body, err := json.Marshal(req)
@@ -158,14 +164,13 @@ func (p *OpenAIProvider) CreateChatCompletion(ctx context.Context, req *ChatComp
 
 	// Set headers
 	httpReq.Header.Set("Content-Type", "application/json")
-	if p.apiKey != "" {
-		httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", p.apiKey))
-	}
+	p.setAuth(httpReq)
+	setTraceHeader(httpReq)
 
 	// Send request
 	resp, err := p.client.Do(httpReq)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return nil, sendError(err)
 	}
 	defer resp.Body.Close()
 
@@ -177,11 +182,7 @@ func (p *OpenAIProvider) CreateChatCompletion(ctx context.Context, req *ChatComp
 
 	// Check status code
 	if resp.StatusCode != http.StatusOK {
-		bodyStr := string(respBody)
-		if resp.StatusCode == http.StatusBadRequest && isContextLengthError(bodyStr) {
-			return nil, &ContextLengthError{StatusCode: resp.StatusCode, Body: bodyStr}
-		}
-		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, bodyStr)
+		return nil, statusError(resp.StatusCode, string(respBody))
 	}
 
 	// Extract and unmarshal JSON response (handling extraneous text)
@@ -195,6 +196,9 @@ func (p *OpenAIProvider) CreateChatCompletion(ctx context.Context, req *ChatComp
 
 // GetModels lists available models
 func (p *OpenAIProvider) GetModels(ctx context.Context) ([]Model, error) {
+	if p.deployment != "" {
+		return p.getAzureModels(ctx)
+	}
 	url := fmt.Sprintf("%s/models", p.endpoint)
 
 	// Create HTTP request
@@ -211,7 +215,7 @@ func (p *OpenAIProvider) GetModels(ctx context.Context) ([]Model, error) {
 	// Send request
 	resp, err := p.client.Do(httpReq)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return nil, sendError(err)
 	}
 	defer resp.Body.Close()
 
@@ -223,7 +227,7 @@ func (p *OpenAIProvider) GetModels(ctx context.Context) ([]Model, error) {
 
 	// Check status code
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(respBody))
+		return nil, statusError(resp.StatusCode, string(respBody))
 	}
 
 	// Extract and unmarshal JSON response (handling extraneous text)
@@ -358,3 +362,11 @@ func findClosingBracket(data []byte) int {
 
 	return -1
 }
+
+// setTraceHeader forwards the request context's trace ID, if any, so provider
+// or gateway logs can be matched to the dispatch that made the call.
+func setTraceHeader(httpReq *http.Request) {
+	if traceID := observability.TraceID(httpReq.Context()); traceID != "" {
+		httpReq.Header.Set(observability.TraceHeader, traceID)
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+
+	"github.com/jordanhubbard/loom/internal/observability"
 )
 
 // ---------------------------------------------------------------------------
@@ -224,6 +226,28 @@ func TestOpenAIProvider_CreateChatCompletion_Success(t *testing.T) {
 	}
 }
 
+func TestOpenAIProvider_CreateChatCompletion_ForwardsTraceID(t *testing.T) {
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get(observability.TraceHeader))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"r","choices":[{"message":{"role":"assistant","content":"ok"}}]}`))
+	}))
+	defer server.Close()
+
+	p := NewOpenAIProvider(server.URL, "")
+	req := &ChatCompletionRequest{Model: "gpt-4", Messages: []ChatMessage{{Role: "user", Content: "hi"}}}
+	if _, err := p.CreateChatCompletion(observability.WithTraceID(context.Background(), "trace-1"), req); err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+	if _, err := p.CreateChatCompletion(context.Background(), req); err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+	if len(got) != 2 || got[0] != "trace-1" || got[1] != "" {
+		t.Errorf("trace headers = %q, want [trace-1, \"\"]", got)
+	}
+}
+
 func TestOpenAIProvider_CreateChatCompletion_NoAPIKey(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Authorization header should not be present
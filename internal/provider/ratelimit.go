@@ -0,0 +1,179 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RateLimitStats is a snapshot of a provider's request rate limiter.
+type RateLimitStats struct {
+	ProviderID        string  `json:"provider_id"`
+	RequestsPerMinute int     `json:"requests_per_minute"` // 0 means unlimited
+	Available         float64 `json:"available"`           // Tokens currently in the bucket
+	Capacity          float64 `json:"capacity"`
+}
+
+// rateLimiter is a token bucket holding up to one minute's worth of
+// requests, refilled continuously at rpm/60 tokens per second.
+type rateLimiter struct {
+	mu     sync.Mutex
+	rpm    int
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+func newRateLimiter(rpm int) *rateLimiter {
+	l := &rateLimiter{now: time.Now}
+	l.setRate(rpm)
+	return l
+}
+
+// setRate changes the rate, starting from a full bucket when the limiter was
+// previously unlimited. rpm <= 0 removes the limit.
+func (l *rateLimiter) setRate(rpm int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if rpm < 0 {
+		rpm = 0
+	}
+	l.refill()
+	if l.rpm == 0 || l.tokens > float64(rpm) {
+		l.tokens = float64(rpm)
+	}
+	l.rpm = rpm
+	l.last = l.now()
+}
+
+// refill adds tokens earned since the last refill. The caller must hold l.mu.
+func (l *rateLimiter) refill() {
+	now := l.now()
+	if l.rpm > 0 {
+		l.tokens += now.Sub(l.last).Minutes() * float64(l.rpm)
+		if l.tokens > float64(l.rpm) {
+			l.tokens = float64(l.rpm)
+		}
+	}
+	l.last = now
+}
+
+// reserve takes a token if one is available, otherwise it reports how long
+// until one will be.
+func (l *rateLimiter) reserve() (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.rpm <= 0 {
+		return 0, true
+	}
+	l.refill()
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0, true
+	}
+	return time.Duration((1 - l.tokens) / float64(l.rpm) * float64(time.Minute)), false
+}
+
+// wait blocks until a token is available or ctx is done.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	for {
+		delay, ok := l.reserve()
+		if ok {
+			return nil
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (l *rateLimiter) stats() (int, float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill()
+	return l.rpm, l.tokens
+}
+
+// applyRateLimit sets up the limiter for a newly registered config. A config
+// without RequestsPerMinute keeps any limit already set for the provider, so
+// runtime tuning survives re-registration. The caller must hold r.mu.
+func (r *Registry) applyRateLimit(config *ProviderConfig) {
+	if r.limiters == nil {
+		r.limiters = make(map[string]*rateLimiter)
+	}
+	limiter, exists := r.limiters[config.ID]
+	switch {
+	case config.RequestsPerMinute > 0 && exists:
+		limiter.setRate(config.RequestsPerMinute)
+	case config.RequestsPerMinute > 0:
+		r.limiters[config.ID] = newRateLimiter(config.RequestsPerMinute)
+	case exists:
+		config.RequestsPerMinute, _ = limiter.stats()
+	}
+}
+
+// SetRateLimit changes how many requests per minute a provider accepts.
+// rpm <= 0 removes the limit.
+func (r *Registry) SetRateLimit(providerID string, rpm int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	provider, exists := r.providers[providerID]
+	if !exists {
+		return fmt.Errorf("provider %s not found", providerID)
+	}
+	if rpm < 0 {
+		rpm = 0
+	}
+	if r.limiters == nil {
+		r.limiters = make(map[string]*rateLimiter)
+	}
+	if limiter, ok := r.limiters[providerID]; ok {
+		limiter.setRate(rpm)
+	} else if rpm > 0 {
+		r.limiters[providerID] = newRateLimiter(rpm)
+	}
+	if provider.Config != nil {
+		provider.Config.RequestsPerMinute = rpm
+	}
+	return nil
+}
+
+// RateLimitStats returns the current fill of a provider's rate limiter.
+func (r *Registry) RateLimitStats(providerID string) (*RateLimitStats, error) {
+	r.mu.RLock()
+	_, exists := r.providers[providerID]
+	limiter := r.limiters[providerID]
+	r.mu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("provider %s not found", providerID)
+	}
+	stats := &RateLimitStats{ProviderID: providerID}
+	if limiter != nil {
+		stats.RequestsPerMinute, stats.Available = limiter.stats()
+		stats.Capacity = float64(stats.RequestsPerMinute)
+	}
+	return stats, nil
+}
+
+// WaitForRateLimit blocks until the provider's rate limit allows another
+// request or ctx is done. Unlimited and unknown providers return immediately.
+func (r *Registry) WaitForRateLimit(ctx context.Context, providerID string) error {
+	r.mu.RLock()
+	limiter := r.limiters[providerID]
+	r.mu.RUnlock()
+
+	if limiter == nil {
+		return nil
+	}
+	if err := limiter.wait(ctx); err != nil {
+		return fmt.Errorf("waiting for provider %s rate limit: %w", providerID, err)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_Refill(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := &rateLimiter{now: func() time.Time { return now }}
+	l.setRate(60)
+
+	for i := 0; i < 60; i++ {
+		if _, ok := l.reserve(); !ok {
+			t.Fatalf("reserve %d failed with a full bucket", i)
+		}
+	}
+	delay, ok := l.reserve()
+	if ok {
+		t.Fatal("reserve succeeded with an empty bucket")
+	}
+	if delay != time.Second {
+		t.Errorf("delay = %v, want 1s at 60 rpm", delay)
+	}
+
+	now = now.Add(2500 * time.Millisecond)
+	if _, available := l.stats(); available < 2.49 || available > 2.51 {
+		t.Errorf("available = %v, want 2.5 after 2.5s", available)
+	}
+
+	now = now.Add(time.Hour)
+	if _, available := l.stats(); available != 60 {
+		t.Errorf("available = %v, want bucket capped at 60", available)
+	}
+}
+
+func TestRegistry_RateLimit(t *testing.T) {
+	r := NewRegistry()
+	if err := r.Register(&ProviderConfig{ID: "p1", Type: "mock"}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	stats, err := r.RateLimitStats("p1")
+	if err != nil {
+		t.Fatalf("RateLimitStats: %v", err)
+	}
+	if stats.RequestsPerMinute != 0 {
+		t.Errorf("default rpm = %d, want unlimited", stats.RequestsPerMinute)
+	}
+	for i := 0; i < 5; i++ {
+		if err := r.WaitForRateLimit(context.Background(), "p1"); err != nil {
+			t.Fatalf("unlimited wait: %v", err)
+		}
+	}
+
+	if err := r.SetRateLimit("p1", 2); err != nil {
+		t.Fatalf("SetRateLimit: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := r.WaitForRateLimit(context.Background(), "p1"); err != nil {
+			t.Fatalf("wait %d: %v", i, err)
+		}
+	}
+	stats, _ = r.RateLimitStats("p1")
+	if stats.RequestsPerMinute != 2 || stats.Capacity != 2 || stats.Available >= 1 {
+		t.Errorf("stats = %+v, want drained bucket of 2", stats)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := r.WaitForRateLimit(ctx, "p1"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want deadline exceeded", err)
+	}
+
+	// Re-registering without a rate keeps the runtime limit.
+	if err := r.Upsert(&ProviderConfig{ID: "p1", Type: "mock"}); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+	registered, _ := r.Get("p1")
+	if registered.Config.RequestsPerMinute != 2 {
+		t.Errorf("rpm after upsert = %d, want 2", registered.Config.RequestsPerMinute)
+	}
+
+	if err := r.SetRateLimit("p1", 0); err != nil {
+		t.Fatalf("SetRateLimit(0): %v", err)
+	}
+	if err := r.WaitForRateLimit(ctx, "p1"); err != nil {
+		t.Errorf("wait after removing limit: %v", err)
+	}
+
+	if err := r.SetRateLimit("missing", 10); err == nil {
+		t.Error("expected error for unknown provider")
+	}
+	if _, err := r.RateLimitStats("missing"); err == nil {
+		t.Error("expected error for unknown provider")
+	}
+}
+
+func TestRegistry_RateLimitFromConfig(t *testing.T) {
+	r := NewRegistry()
+	if err := r.Register(&ProviderConfig{ID: "p1", Type: "mock", RequestsPerMinute: 30}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	stats, _ := r.RateLimitStats("p1")
+	if stats.RequestsPerMinute != 30 || stats.Available != 30 {
+		t.Errorf("stats = %+v, want full bucket of 30", stats)
+	}
+
+	_ = r.Unregister("p1")
+	if err := r.WaitForRateLimit(context.Background(), "p1"); err != nil {
+		t.Errorf("wait on unregistered provider: %v", err)
+	}
+}
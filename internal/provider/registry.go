@@ -2,8 +2,10 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"math/rand/v2"
 	"sort"
 	"strings"
 	"sync"
@@ -26,6 +28,16 @@ type ProviderConfig struct {
 	LastHeartbeatLatencyMs int64     `json:"last_heartbeat_latency_ms,omitempty"`
 	CapabilityScore        float64   `json:"capability_score,omitempty"` // Dynamic composite score from Scorer
 	ContextWindow          int       `json:"context_window,omitempty"`
+	MaxTokens              int       `json:"max_tokens,omitempty"`          // default max_tokens (required by anthropic)
+	FallbackIDs            []string  `json:"fallback_ids,omitempty"`        // providers to try, in order, when this one fails
+	DeploymentName         string    `json:"deployment_name,omitempty"`     // azure-openai: deployment that serves requests
+	APIVersion             string    `json:"api_version,omitempty"`         // azure-openai: api-version query parameter
+	Region                 string    `json:"region,omitempty"`              // bedrock: AWS region, e.g. us-east-1
+	RequestsPerMinute      int       `json:"requests_per_minute,omitempty"` // 0 means unlimited
+	TimeoutSeconds         int       `json:"timeout_seconds,omitempty"`     // per non-streaming request; 0 means DefaultTimeoutSeconds
+	MaxRetries             int       `json:"max_retries,omitempty"`         // retries of rate-limited/unavailable requests; 0 means DefaultMaxRetries, negative disables
+	MockResponse           string    `json:"mock_response,omitempty"`       // mock: canned reply; empty echoes the last message
+	MockLatencyMs          int       `json:"mock_latency_ms,omitempty"`     // mock: simulated delay before each response
 
 	// Model metadata for scoring
 	ModelParamsB    float64 `json:"model_params_b,omitempty"`   // Total model parameters in billions
@@ -45,6 +57,17 @@ type Registry struct {
 	metricsCallback MetricsCallback
 	rrCounter       uint64  // Round-robin counter for equal-priority providers
 	scorer          *Scorer // Dynamic provider scoring
+
+	groups   map[string][]WeightedProvider // Weighted load-balancing groups
+	rng      *rand.Rand                    // Group picker; guarded by mu
+	limiters map[string]*rateLimiter       // Per-provider request rate limits
+
+	breakers         map[string]*circuitBreaker // Per-provider circuit breakers
+	circuitThreshold int                        // Consecutive failures that open a circuit
+	circuitCooldown  time.Duration              // How long a circuit stays open
+
+	modelCache    map[string]*modelCacheEntry // Per-provider model lists from ListModels
+	modelCacheTTL time.Duration               // How long a cached model list is reused
 }
 
 // RegisteredProvider wraps a provider with its configuration and protocol
@@ -56,8 +79,12 @@ type RegisteredProvider struct {
 // NewRegistry creates a new provider registry
 func NewRegistry() *Registry {
 	return &Registry{
-		providers: make(map[string]*RegisteredProvider),
-		scorer:    NewScorer(),
+		providers:  make(map[string]*RegisteredProvider),
+		scorer:     NewScorer(),
+		groups:     make(map[string][]WeightedProvider),
+		limiters:   make(map[string]*rateLimiter),
+		breakers:   make(map[string]*circuitBreaker),
+		modelCache: make(map[string]*modelCacheEntry),
 	}
 }
 
@@ -66,36 +93,40 @@ func (r *Registry) Clear() {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	r.providers = make(map[string]*RegisteredProvider)
+	r.limiters = make(map[string]*rateLimiter)
+	r.modelCache = make(map[string]*modelCacheEntry)
+	r.breakers = make(map[string]*circuitBreaker)
 }
 
-// Register registers a new provider
+// Register validates a provider config, fills in its defaults and registers
+// it. Invalid configs are rejected with a *ConfigError.
 func (r *Registry) Register(config *ProviderConfig) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	if config.Status == "" {
-		config.Status = "pending"
-	}
 
 	// Check if provider already exists
 	if _, exists := r.providers[config.ID]; exists {
 		return fmt.Errorf("provider %s already registered", config.ID)
 	}
+	if _, exists := r.groups[config.ID]; exists {
+		return fmt.Errorf("provider ID %s conflicts with a provider group", config.ID)
+	}
+
+	if err := r.validateFallbacks(config); err != nil {
+		return err
+	}
 
 	// Create protocol based on provider type
-	var protocol Protocol
-	switch config.Type {
-	case "openai", "anthropic", "local", "custom", "vllm":
-		// All use OpenAI-compatible protocol
-		protocol = NewOpenAIProvider(config.Endpoint, config.APIKey)
-	case "ollama":
-		protocol = NewOllamaProvider(config.Endpoint)
-	case "mock":
-		protocol = NewMockProvider()
-	default:
-		return fmt.Errorf("unsupported provider type: %s", config.Type)
+	protocol, err := newProtocol(config)
+	if err != nil {
+		return err
 	}
 
 	// Register provider
+	r.applyRateLimit(config)
 	r.providers[config.ID] = &RegisteredProvider{
 		Config:   config,
 		Protocol: protocol,
@@ -105,27 +136,52 @@ func (r *Registry) Register(config *ProviderConfig) error {
 }
 
 // Upsert registers a provider if it doesn't exist, or replaces it if it does.
+// It validates the config like Register except that the model may be empty,
+// since stored providers may not have discovered their model yet.
 func (r *Registry) Upsert(config *ProviderConfig) error {
+	if err := config.validate(false); err != nil {
+		return err
+	}
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	if config.Status == "" {
-		config.Status = "pending"
+
+	if err := r.validateFallbacks(config); err != nil {
+		return err
+	}
+
+	protocol, err := newProtocol(config)
+	if err != nil {
+		return err
 	}
 
-	var protocol Protocol
+	r.applyRateLimit(config)
+	r.providers[config.ID] = &RegisteredProvider{Config: config, Protocol: protocol}
+	delete(r.modelCache, config.ID)
+	return nil
+}
+
+// newProtocol creates the wire protocol implementation for a provider type.
+func newProtocol(config *ProviderConfig) (Protocol, error) {
 	switch config.Type {
-	case "openai", "anthropic", "local", "custom", "vllm":
-		protocol = NewOpenAIProvider(config.Endpoint, config.APIKey)
+	case "openai", "local", "custom", "vllm":
+		// All use OpenAI-compatible protocol
+		return NewOpenAIProvider(config.Endpoint, config.APIKey), nil
+	case "anthropic":
+		return NewAnthropicProvider(config.Endpoint, config.APIKey, config.MaxTokens), nil
+	case "azure-openai":
+		if strings.TrimSpace(config.DeploymentName) == "" {
+			return nil, fmt.Errorf("azure-openai provider %s requires a deployment name", config.ID)
+		}
+		return NewAzureOpenAIProvider(config.Endpoint, config.APIKey, config.DeploymentName, config.APIVersion), nil
+	case "bedrock":
+		return newBedrockFromConfig(config)
 	case "ollama":
-		protocol = NewOllamaProvider(config.Endpoint)
+		return NewOllamaProvider(config.Endpoint), nil
 	case "mock":
-		protocol = NewMockProvider()
+		return &MockProvider{Response: config.MockResponse, Latency: time.Duration(config.MockLatencyMs) * time.Millisecond}, nil
 	default:
-		return fmt.Errorf("unsupported provider type: %s", config.Type)
+		return nil, fmt.Errorf("unsupported provider type: %s", config.Type)
 	}
-
-	r.providers[config.ID] = &RegisteredProvider{Config: config, Protocol: protocol}
-	return nil
 }
 
 // Unregister removes a provider from the registry
@@ -138,6 +194,9 @@ func (r *Registry) Unregister(providerID string) error {
 	}
 
 	delete(r.providers, providerID)
+	delete(r.limiters, providerID)
+	delete(r.breakers, providerID)
+	delete(r.modelCache, providerID)
 	return nil
 }
 
@@ -167,8 +226,8 @@ func (r *Registry) List() []*RegisteredProvider {
 	return providers
 }
 
-// ListActive returns registered providers with active status, sorted by
-// dynamic capability score (highest first). Scoring prioritizes:
+// ListActive returns registered providers with active status and a circuit
+// that is not open, sorted by dynamic capability score (highest first). Scoring prioritizes:
 // 1. Model size (larger models preferred)
 // 2. Round-trip time (lower heartbeat latency preferred)
 // 3. Request latency (lower average response time preferred)
@@ -182,7 +241,7 @@ func (r *Registry) ListActive() []*RegisteredProvider {
 	r.mu.RLock()
 	providers := make([]*RegisteredProvider, 0, len(r.providers))
 	for _, provider := range r.providers {
-		if provider != nil && provider.Config != nil && isProviderHealthy(provider.Config.Status) {
+		if r.isAvailable(provider) {
 			// Update dynamic score from scorer
 			if r.scorer != nil {
 				if score, ok := r.scorer.GetScore(provider.Config.ID); ok {
@@ -235,16 +294,22 @@ func (r *Registry) ListActive() []*RegisteredProvider {
 	return providers
 }
 
-// IsActive returns true if the provider is registered and active.
+// IsActive returns true if the provider is registered and active and its
+// circuit is not open. A provider group is active when any of its members is.
 func (r *Registry) IsActive(providerID string) bool {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	provider, exists := r.providers[providerID]
-	if !exists || provider == nil || provider.Config == nil {
+	if members, ok := r.groups[providerID]; ok {
+		for _, m := range members {
+			if r.isAvailable(r.providers[m.ProviderID]) {
+				return true
+			}
+		}
 		return false
 	}
-	return isProviderHealthy(provider.Config.Status)
+
+	return r.isAvailable(r.providers[providerID])
 }
 
 // SetMetricsCallback sets the callback function for recording metrics
@@ -293,14 +358,20 @@ func (r *Registry) SendChatCompletion(ctx context.Context, providerID string, re
 	if provider.Config != nil && !isProviderHealthy(provider.Config.Status) {
 		return nil, fmt.Errorf("provider %s is disabled", providerID)
 	}
+	if !r.AllowRequest(providerID) {
+		return nil, fmt.Errorf("provider %s circuit is open: %w", providerID, ErrProviderUnavailable)
+	}
 
 	// Use default model if not specified
 	if req.Model == "" {
 		req.Model = provider.Config.Model
 	}
 
+	ctx, cancel := context.WithTimeout(ctx, provider.Config.timeout())
+	defer cancel()
+
 	// Make the request
-	resp, err := provider.Protocol.CreateChatCompletion(ctx, req)
+	resp, err := r.createWithRetries(ctx, provider, req)
 
 	// If model not found (404), the vLLM server may have restarted with a
 	// different model. Rediscover available models and retry once.
@@ -317,7 +388,7 @@ func (r *Registry) SendChatCompletion(ctx context.Context, providerID string, re
 			}
 			r.mu.Unlock()
 			req.Model = newModel
-			resp, err = provider.Protocol.CreateChatCompletion(ctx, req)
+			resp, err = r.createWithRetries(ctx, provider, req)
 		}
 	}
 
@@ -331,6 +402,7 @@ func (r *Registry) SendChatCompletion(ctx context.Context, providerID string, re
 
 	// Update dynamic scoring metrics
 	r.RecordRequestMetrics(providerID, latencyMs, success)
+	r.RecordOutcome(providerID, err)
 
 	// Call metrics callback if registered
 	r.mu.RLock()
@@ -344,6 +416,35 @@ func (r *Registry) SendChatCompletion(ctx context.Context, providerID string, re
 	return resp, err
 }
 
+// retryBackoff is the wait before the first retry; it doubles after each.
+var retryBackoff = 500 * time.Millisecond
+
+// createWithRetries sends req, retrying rate-limited and unavailable
+// responses up to the provider's MaxRetries times.
+func (r *Registry) createWithRetries(ctx context.Context, provider *RegisteredProvider, req *ChatCompletionRequest) (*ChatCompletionResponse, error) {
+	providerID := provider.Config.ID
+	for attempt := 0; ; attempt++ {
+		if err := r.WaitForRateLimit(ctx, providerID); err != nil {
+			return nil, err
+		}
+		resp, err := provider.Protocol.CreateChatCompletion(ctx, req)
+		if err == nil || attempt >= provider.Config.retries() || ctx.Err() != nil {
+			return resp, err
+		}
+		classified := ClassifyError(err)
+		if !errors.Is(classified, ErrRateLimited) && !errors.Is(classified, ErrProviderUnavailable) {
+			return resp, err
+		}
+		wait := retryBackoff << attempt
+		log.Printf("[Registry] Provider %s request failed (%v); retry %d/%d in %s", providerID, err, attempt+1, provider.Config.retries(), wait)
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(wait):
+		}
+	}
+}
+
 // GetModels retrieves available models from a provider
 func (r *Registry) GetModels(ctx context.Context, providerID string) ([]Model, error) {
 	provider, err := r.Get(providerID)
@@ -480,7 +581,7 @@ func (r *Registry) ListActiveForComplexity(complexity ComplexityLevel) []*Regist
 	providerMap := make(map[string]*RegisteredProvider)
 
 	for _, provider := range r.providers {
-		if provider != nil && provider.Config != nil && isProviderHealthy(provider.Config.Status) {
+		if r.isAvailable(provider) {
 			providers = append(providers, provider)
 			providerIDs = append(providerIDs, provider.Config.ID)
 			providerMap[provider.Config.ID] = provider
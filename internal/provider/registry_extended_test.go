@@ -73,7 +73,8 @@ func TestRegistryUpsert_UnsupportedType(t *testing.T) {
 	if err == nil {
 		t.Fatal("expected error for unsupported type")
 	}
-	if !strings.Contains(err.Error(), "unsupported provider type") {
+	var cfgErr *ConfigError
+	if !errors.As(err, &cfgErr) || cfgErr.Field != "type" {
 		t.Errorf("unexpected error: %v", err)
 	}
 }
@@ -24,6 +24,14 @@ type StreamChunk struct {
 		} `json:"delta"`
 		FinishReason string `json:"finish_reason,omitempty"`
 	} `json:"choices"`
+	Usage *StreamUsage `json:"usage,omitempty"` // Set on the final chunk when the provider reports it
+}
+
+// StreamUsage is the token usage reported at the end of a stream
+type StreamUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
 }
 
 // StreamHandler handles streaming responses
@@ -34,7 +42,7 @@ func (p *OpenAIProvider) CreateChatCompletionStream(ctx context.Context, req *Ch
 	// Ensure stream is enabled
 	req.Stream = true
 
-	url := fmt.Sprintf("%s/chat/completions", p.endpoint)
+	url := p.chatCompletionsURL()
 
 	// Marshal request body
 	body, err := json.Marshal(req)
@@ -51,9 +59,8 @@ func (p *OpenAIProvider) CreateChatCompletionStream(ctx context.Context, req *Ch
 	// Set headers
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("Accept", "text/event-stream")
-	if p.apiKey != "" {
-		httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", p.apiKey))
-	}
+	p.setAuth(httpReq)
+	setTraceHeader(httpReq)
 
 	// Use streaming client (no timeout) for streaming requests.
 	// The context controls cancellation; this prevents mid-stream timeouts.
@@ -68,18 +75,14 @@ func (p *OpenAIProvider) CreateChatCompletionStream(ctx context.Context, req *Ch
 		if ctx.Err() != nil {
 			return fmt.Errorf("request cancelled: %w", ctx.Err())
 		}
-		return fmt.Errorf("failed to send request: %w", err)
+		return sendError(err)
 	}
 	defer resp.Body.Close()
 
 	// Check status code
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		bodyStr := string(respBody)
-		if resp.StatusCode == http.StatusBadRequest && isContextLengthError(bodyStr) {
-			return &ContextLengthError{StatusCode: resp.StatusCode, Body: bodyStr}
-		}
-		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, bodyStr)
+		return statusError(resp.StatusCode, string(respBody))
 	}
 
 	// Read streaming response
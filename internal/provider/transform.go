@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"context"
+	"sync"
+)
+
+// ProviderTransform adapts requests and responses for a provider type, such
+// as moving the system prompt or reformatting tool output, without changing
+// the protocol implementation. The request passed in is shared with fallback
+// attempts, so a transform that changes it should return a modified copy.
+type ProviderTransform interface {
+	// TransformRequest returns the request to send in place of req.
+	TransformRequest(ctx context.Context, req *ChatCompletionRequest) (*ChatCompletionRequest, error)
+
+	// TransformResponse returns the response to use in place of resp. req is
+	// the request as it was sent.
+	TransformResponse(ctx context.Context, req *ChatCompletionRequest, resp *ChatCompletionResponse) (*ChatCompletionResponse, error)
+}
+
+// NoopTransform passes requests and responses through unchanged.
+type NoopTransform struct{}
+
+func (NoopTransform) TransformRequest(ctx context.Context, req *ChatCompletionRequest) (*ChatCompletionRequest, error) {
+	return req, nil
+}
+
+func (NoopTransform) TransformResponse(ctx context.Context, req *ChatCompletionRequest, resp *ChatCompletionResponse) (*ChatCompletionResponse, error) {
+	return resp, nil
+}
+
+var transforms = struct {
+	mu     sync.RWMutex
+	byType map[string]ProviderTransform
+}{byType: make(map[string]ProviderTransform)}
+
+func init() {
+	for _, providerType := range []string{"openai", "local", "custom", "vllm", "anthropic", "azure-openai", "bedrock", "ollama", "mock"} {
+		RegisterTransform(providerType, NoopTransform{})
+	}
+}
+
+// RegisterTransform sets the transform used for providers of providerType,
+// replacing any registered before. A nil transform restores the no-op.
+func RegisterTransform(providerType string, t ProviderTransform) {
+	if t == nil {
+		t = NoopTransform{}
+	}
+	transforms.mu.Lock()
+	defer transforms.mu.Unlock()
+	transforms.byType[providerType] = t
+}
+
+// TransformFor returns the transform registered for providerType, or
+// NoopTransform when there is none.
+func TransformFor(providerType string) ProviderTransform {
+	transforms.mu.RLock()
+	defer transforms.mu.RUnlock()
+	if t, ok := transforms.byType[providerType]; ok {
+		return t
+	}
+	return NoopTransform{}
+}
@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"context"
+	"testing"
+)
+
+type prefixTransform struct{}
+
+func (prefixTransform) TransformRequest(ctx context.Context, req *ChatCompletionRequest) (*ChatCompletionRequest, error) {
+	out := *req
+	out.Model = "gateway/" + req.Model
+	return &out, nil
+}
+
+func (prefixTransform) TransformResponse(ctx context.Context, req *ChatCompletionRequest, resp *ChatCompletionResponse) (*ChatCompletionResponse, error) {
+	return resp, nil
+}
+
+func TestTransformFor_BuiltinsAreNoop(t *testing.T) {
+	for _, providerType := range []string{"openai", "anthropic", "ollama", "unknown-type"} {
+		if _, ok := TransformFor(providerType).(NoopTransform); !ok {
+			t.Errorf("TransformFor(%q) = %T, want NoopTransform", providerType, TransformFor(providerType))
+		}
+	}
+
+	req := &ChatCompletionRequest{Model: "m"}
+	out, err := NoopTransform{}.TransformRequest(context.Background(), req)
+	if err != nil || out != req {
+		t.Errorf("NoopTransform changed the request: %v, %v", out, err)
+	}
+}
+
+func TestRegisterTransform(t *testing.T) {
+	RegisterTransform("gateway-test", prefixTransform{})
+	defer RegisterTransform("gateway-test", nil)
+
+	out, err := TransformFor("gateway-test").TransformRequest(context.Background(), &ChatCompletionRequest{Model: "m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Model != "gateway/m" {
+		t.Errorf("Model = %q, want gateway/m", out.Model)
+	}
+
+	RegisterTransform("gateway-test", nil)
+	if _, ok := TransformFor("gateway-test").(NoopTransform); !ok {
+		t.Error("registering nil should restore the no-op transform")
+	}
+}
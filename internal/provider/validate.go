@@ -0,0 +1,125 @@
+package provider
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Defaults applied to provider configs that leave them unset.
+const (
+	DefaultTimeoutSeconds = 900 // Matches the provider HTTP clients' 15 minute timeout
+	DefaultMaxRetries     = 2
+)
+
+// ConfigError reports an invalid ProviderConfig field.
+type ConfigError struct {
+	ProviderID string
+	Field      string
+	Reason     string
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("invalid provider %s: %s %s", e.ProviderID, e.Field, e.Reason)
+}
+
+// knownTypes lists the provider types newProtocol can build.
+var knownTypes = map[string]bool{
+	"openai": true, "local": true, "custom": true, "vllm": true,
+	"anthropic": true, "azure-openai": true, "bedrock": true, "ollama": true, "mock": true,
+}
+
+// Validate checks that the config can be used to reach its provider and
+// fills in the defaults for unset fields. The error is a *ConfigError
+// naming the first bad field.
+func (c *ProviderConfig) Validate() error {
+	return c.validate(true)
+}
+
+// validate does the work of Validate. Without requireModel a provider type
+// that needs a model may leave it empty, as a stored provider that has not
+// discovered its models yet does.
+func (c *ProviderConfig) validate(requireModel bool) error {
+	invalid := func(field, reason string) error {
+		return &ConfigError{ProviderID: c.ID, Field: field, Reason: reason}
+	}
+
+	if strings.TrimSpace(c.ID) == "" {
+		return invalid("id", "is required")
+	}
+	if !knownTypes[c.Type] {
+		return invalid("type", fmt.Sprintf("%q is not a known provider type", c.Type))
+	}
+
+	switch {
+	case c.Endpoint != "":
+		if u, err := url.Parse(c.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return invalid("endpoint", fmt.Sprintf("%q is not an http(s) URL", c.Endpoint))
+		}
+	case c.Type != "mock" && c.Type != "bedrock" && c.Type != "anthropic":
+		// Anthropic defaults to its public API; bedrock derives an endpoint
+		// from the region.
+		return invalid("endpoint", "is required")
+	}
+
+	switch c.Type {
+	case "openai", "anthropic":
+		if requireModel && strings.TrimSpace(c.Model) == "" {
+			return invalid("model", "is required for "+c.Type+" providers")
+		}
+	case "azure-openai":
+		if strings.TrimSpace(c.DeploymentName) == "" {
+			return invalid("deployment_name", "is required for azure-openai providers")
+		}
+	case "bedrock":
+		if strings.TrimSpace(c.Region) == "" && bedrockRegionFromEndpoint(c.Endpoint) == "" {
+			return invalid("region", "is required for bedrock providers without a regional endpoint")
+		}
+	}
+
+	if c.TimeoutSeconds < 0 {
+		return invalid("timeout_seconds", "must not be negative")
+	}
+	if c.RequestsPerMinute < 0 {
+		return invalid("requests_per_minute", "must not be negative")
+	}
+	if c.MaxTokens < 0 {
+		return invalid("max_tokens", "must not be negative")
+	}
+	if c.MockLatencyMs < 0 {
+		return invalid("mock_latency_ms", "must not be negative")
+	}
+
+	c.applyDefaults()
+	return nil
+}
+
+// applyDefaults fills in the timeout and retry count when they are unset.
+func (c *ProviderConfig) applyDefaults() {
+	if c.Status == "" {
+		c.Status = "pending"
+	}
+	if c.TimeoutSeconds == 0 {
+		c.TimeoutSeconds = DefaultTimeoutSeconds
+	}
+	if c.MaxRetries == 0 {
+		c.MaxRetries = DefaultMaxRetries
+	}
+}
+
+// timeout returns how long a non-streaming request may take.
+func (c *ProviderConfig) timeout() time.Duration {
+	if c == nil || c.TimeoutSeconds <= 0 {
+		return DefaultTimeoutSeconds * time.Second
+	}
+	return time.Duration(c.TimeoutSeconds) * time.Second
+}
+
+// retries returns how many times a failed request is retried.
+func (c *ProviderConfig) retries() int {
+	if c == nil || c.MaxRetries < 0 {
+		return 0
+	}
+	return c.MaxRetries
+}
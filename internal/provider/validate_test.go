@@ -0,0 +1,151 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestProviderConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    ProviderConfig
+		wantField string
+	}{
+		{"openai", ProviderConfig{ID: "p", Type: "openai", Endpoint: "https://api.openai.com/v1", Model: "gpt-4o"}, ""},
+		{"vllm without model", ProviderConfig{ID: "p", Type: "vllm", Endpoint: "http://gpu:8000/v1"}, ""},
+		{"anthropic default endpoint", ProviderConfig{ID: "p", Type: "anthropic", Model: "claude"}, ""},
+		{"bedrock region", ProviderConfig{ID: "p", Type: "bedrock", Region: "us-east-1"}, ""},
+		{"bedrock regional endpoint", ProviderConfig{ID: "p", Type: "bedrock", Endpoint: "https://bedrock-runtime.us-west-2.amazonaws.com"}, ""},
+		{"mock", ProviderConfig{ID: "p", Type: "mock"}, ""},
+		{"missing id", ProviderConfig{Type: "mock"}, "id"},
+		{"unknown type", ProviderConfig{ID: "p", Type: "openia", Endpoint: "http://x"}, "type"},
+		{"missing endpoint", ProviderConfig{ID: "p", Type: "ollama"}, "endpoint"},
+		{"endpoint without scheme", ProviderConfig{ID: "p", Type: "ollama", Endpoint: "localhost:11434"}, "endpoint"},
+		{"endpoint with bad scheme", ProviderConfig{ID: "p", Type: "ollama", Endpoint: "ftp://host"}, "endpoint"},
+		{"openai without model", ProviderConfig{ID: "p", Type: "openai", Endpoint: "https://api.openai.com/v1"}, "model"},
+		{"azure without deployment", ProviderConfig{ID: "p", Type: "azure-openai", Endpoint: "https://x.openai.azure.com"}, "deployment_name"},
+		{"bedrock without region", ProviderConfig{ID: "p", Type: "bedrock"}, "region"},
+		{"negative timeout", ProviderConfig{ID: "p", Type: "mock", TimeoutSeconds: -1}, "timeout_seconds"},
+		{"negative rate limit", ProviderConfig{ID: "p", Type: "mock", RequestsPerMinute: -5}, "requests_per_minute"},
+		{"negative mock latency", ProviderConfig{ID: "p", Type: "mock", MockLatencyMs: -1}, "mock_latency_ms"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatalf("Validate() error = %v", err)
+				}
+				return
+			}
+			var cfgErr *ConfigError
+			if !errors.As(err, &cfgErr) {
+				t.Fatalf("Validate() error = %v, want a *ConfigError", err)
+			}
+			if cfgErr.Field != tt.wantField {
+				t.Errorf("Field = %q, want %q", cfgErr.Field, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestProviderConfig_ValidateDefaults(t *testing.T) {
+	c := ProviderConfig{ID: "p", Type: "mock"}
+	if err := c.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if c.TimeoutSeconds != DefaultTimeoutSeconds || c.MaxRetries != DefaultMaxRetries || c.Status != "pending" {
+		t.Errorf("defaults not applied: %+v", c)
+	}
+
+	c = ProviderConfig{ID: "p", Type: "mock", TimeoutSeconds: 30, MaxRetries: -1}
+	if err := c.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if c.TimeoutSeconds != 30 || c.retries() != 0 {
+		t.Errorf("explicit settings overridden: %+v", c)
+	}
+}
+
+func TestRegistry_RegisterRejectsInvalid(t *testing.T) {
+	r := NewRegistry()
+	err := r.Register(&ProviderConfig{ID: "p", Type: "openai", Endpoint: "not a url", Model: "m"})
+	var cfgErr *ConfigError
+	if !errors.As(err, &cfgErr) || cfgErr.Field != "endpoint" {
+		t.Fatalf("Register() error = %v, want an endpoint ConfigError", err)
+	}
+	if _, err := r.Get("p"); err == nil {
+		t.Error("invalid provider should not be registered")
+	}
+}
+
+func TestRegistry_UpsertValidatesAllowingEmptyModel(t *testing.T) {
+	r := NewRegistry()
+	// A stored provider may not have discovered its model yet
+	if err := r.Upsert(&ProviderConfig{ID: "p", Type: "openai", Endpoint: "https://api.openai.com/v1"}); err != nil {
+		t.Fatalf("Upsert() without model error = %v", err)
+	}
+	got, err := r.Get("p")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Config.TimeoutSeconds != DefaultTimeoutSeconds || got.Config.MaxRetries != DefaultMaxRetries {
+		t.Errorf("defaults not applied: %+v", got.Config)
+	}
+
+	err = r.Upsert(&ProviderConfig{ID: "q", Type: "ollama", Endpoint: "ftp://host"})
+	var cfgErr *ConfigError
+	if !errors.As(err, &cfgErr) || cfgErr.Field != "endpoint" {
+		t.Fatalf("Upsert() error = %v, want an endpoint ConfigError", err)
+	}
+	if _, err := r.Get("q"); err == nil {
+		t.Error("invalid provider should not be upserted")
+	}
+}
+
+func TestRegistry_SendChatCompletionRetries(t *testing.T) {
+	old := retryBackoff
+	retryBackoff = time.Millisecond
+	t.Cleanup(func() { retryBackoff = old })
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}]}`))
+	}))
+	defer server.Close()
+
+	r := NewRegistry()
+	if err := r.Register(&ProviderConfig{ID: "p", Type: "openai", Endpoint: server.URL, Model: "m", Status: "healthy"}); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := r.SendChatCompletion(context.Background(), "p", &ChatCompletionRequest{Messages: []ChatMessage{{Role: "user", Content: "hi"}}})
+	if err != nil || resp.Choices[0].Message.Content != "ok" {
+		t.Fatalf("SendChatCompletion() = %+v, %v, want success after retries", resp, err)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("calls = %d, want 3", got)
+	}
+
+	// Client errors are not retried.
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	})
+	calls.Store(0)
+	if _, err := r.SendChatCompletion(context.Background(), "p", &ChatCompletionRequest{Messages: []ChatMessage{{Role: "user", Content: "hi"}}}); err == nil {
+		t.Fatal("expected an error")
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("calls = %d, want 1 for a 400", got)
+	}
+}
@@ -2,9 +2,7 @@ package activities
 
 import (
 	"context"
-	"fmt"
 	"log"
-	"strings"
 	"time"
 
 	"github.com/jordanhubbard/loom/internal/agent"
@@ -47,7 +45,7 @@ func (a *LoomActivities) LoomHeartbeatActivity(ctx context.Context, beatCount in
 	}
 	log.Printf("[Ralph] Beat %d: phase1 done (agentsReset=%d, elapsed=%v)", beatCount, agentsReset, time.Since(start).Round(time.Millisecond))
 
-	// Phase 2: Auto-block beads stuck in dispatch loops
+	// Phase 2: Dead-letter beads stuck in dispatch loops
 	stuckResolved := a.resolveStuckBeads()
 	log.Printf("[Ralph] Beat %d: phase2 done (stuckResolved=%d, elapsed=%v)", beatCount, stuckResolved, time.Since(start).Round(time.Millisecond))
 
@@ -74,10 +72,11 @@ func (a *LoomActivities) LoomHeartbeatActivity(ctx context.Context, beatCount in
 	return nil
 }
 
-// resolveStuckBeads finds beads with loop_detected=true that haven't been
-// resolved by Ralph yet, and auto-blocks them.
+// resolveStuckBeads finds beads the loop detector flagged that are still
+// open or in progress and dead-letters them, which files a decision for
+// human review like a bead stuck past the dispatch hop limit.
 func (a *LoomActivities) resolveStuckBeads() int {
-	if a.beadsMgr == nil {
+	if a.beadsMgr == nil || a.dispatcher == nil {
 		return 0
 	}
 
@@ -100,8 +99,8 @@ func (a *LoomActivities) resolveStuckBeads() int {
 		if b.Context["loop_detected"] != "true" {
 			continue
 		}
-		// Skip if already resolved by Ralph or escalated to CEO
-		if b.Context["ralph_blocked_at"] != "" || b.Context["escalated_to_ceo_decision_id"] != "" {
+		// Skip if already dead-lettered, or escalated to CEO
+		if b.Context["dead_lettered_at"] != "" || b.Context["escalated_to_ceo_decision_id"] != "" {
 			continue
 		}
 
@@ -109,54 +108,12 @@ func (a *LoomActivities) resolveStuckBeads() int {
 		if reason == "" {
 			reason = "loop detected"
 		}
-
-		triageAgent := a.findDefaultTriageAgent(b.ProjectID)
-		updates := map[string]interface{}{
-			"status":      models.BeadStatusBlocked,
-			"assigned_to": triageAgent,
-			"context": map[string]string{
-				"ralph_blocked_at":     time.Now().UTC().Format(time.RFC3339),
-				"ralph_blocked_reason": fmt.Sprintf("auto-blocked by Ralph: %s", reason),
-				"redispatch_requested": "false",
-			},
-		}
-		if err := a.beadsMgr.UpdateBead(b.ID, updates); err != nil {
-			log.Printf("[Ralph] Failed to auto-block stuck bead %s: %v", b.ID, err)
+		if err := a.dispatcher.DeadLetterStuckBead(b, reason); err != nil {
+			log.Printf("[Ralph] Failed to dead-letter stuck bead %s: %v", b.ID, err)
 			continue
 		}
-		log.Printf("[Ralph] Auto-blocked stuck bead %s: %s (reassigned to %s)", b.ID, reason, triageAgent)
+		log.Printf("[Ralph] Dead-lettered stuck bead %s: %s", b.ID, reason)
 		resolved++
 	}
 	return resolved
 }
-
-func (a *LoomActivities) findDefaultTriageAgent(projectID string) string {
-	if a.agentMgr == nil {
-		return ""
-	}
-	agents := a.agentMgr.ListAgentsByProject(projectID)
-	if len(agents) == 0 {
-		agents = a.agentMgr.ListAgents()
-	}
-	var fallback string
-	for _, ag := range agents {
-		role := strings.TrimSpace(strings.ToLower(ag.Role))
-		role = strings.ReplaceAll(role, "_", "-")
-		role = strings.ReplaceAll(role, " ", "-")
-		if role == "cto" || role == "chief-technology-officer" {
-			return ag.ID
-		}
-		if role == "engineering-manager" && fallback == "" {
-			fallback = ag.ID
-		}
-	}
-	if fallback != "" {
-		return fallback
-	}
-	for _, ag := range agents {
-		if ag.ProjectID == projectID || ag.ProjectID == "" {
-			return ag.ID
-		}
-	}
-	return ""
-}
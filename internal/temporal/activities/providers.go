@@ -237,11 +237,19 @@ func (a *ProviderActivities) syncRegistry(record *internalmodels.Provider) {
 		Name:                   record.Name,
 		Type:                   record.Type,
 		Endpoint:               record.Endpoint,
+		DeploymentName:         record.DeploymentName,
+		APIVersion:             record.APIVersion,
+		Region:                 record.Region,
 		APIKey:                 apiKey,
 		Model:                  selected,
 		ConfiguredModel:        record.ConfiguredModel,
 		SelectedModel:          selected,
 		SelectedGPU:            record.SelectedGPU,
+		TimeoutSeconds:         record.TimeoutSeconds,
+		MaxRetries:             record.MaxRetries,
+		RequestsPerMinute:      record.RequestsPerMinute,
+		MockResponse:           record.MockResponse,
+		MockLatencyMs:          record.MockLatencyMs,
 		Status:                 record.Status,
 		LastHeartbeatAt:        record.LastHeartbeatAt,
 		LastHeartbeatLatencyMs: record.LastHeartbeatLatencyMs,
@@ -495,7 +503,12 @@ func probeModels(ctx context.Context, c providerCandidate) ([]provider.Model, er
 		if err != nil {
 			return nil, err
 		}
-		if c.APIKey != "" {
+		if c.ProviderType == "anthropic" {
+			req.Header.Set("anthropic-version", provider.AnthropicAPIVersion)
+			if c.APIKey != "" {
+				req.Header.Set("x-api-key", c.APIKey)
+			}
+		} else if c.APIKey != "" {
 			req.Header.Set("Authorization", "Bearer "+c.APIKey)
 		}
 		resp, err := client.Do(req)
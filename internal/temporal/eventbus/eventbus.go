@@ -16,26 +16,27 @@ import (
 type EventType string
 
 const (
-	EventTypeAgentSpawned       EventType = "agent.spawned"
-	EventTypeAgentStatusChange  EventType = "agent.status_change"
-	EventTypeAgentHeartbeat     EventType = "agent.heartbeat"
-	EventTypeAgentCompleted     EventType = "agent.completed"
-	EventTypeBeadCreated        EventType = "bead.created"
-	EventTypeBeadAssigned       EventType = "bead.assigned"
-	EventTypeBeadStatusChange   EventType = "bead.status_change"
-	EventTypeBeadCompleted      EventType = "bead.completed"
-	EventTypeDecisionCreated    EventType = "decision.created"
-	EventTypeDecisionResolved   EventType = "decision.resolved"
-	EventTypeProviderRegistered EventType = "provider.registered"
-	EventTypeProviderDeleted    EventType = "provider.deleted"
-	EventTypeProviderUpdated    EventType = "provider.updated"
-	EventTypeProjectCreated     EventType = "project.created"
-	EventTypeProjectUpdated     EventType = "project.updated"
-	EventTypeProjectDeleted     EventType = "project.deleted"
-	EventTypeConfigUpdated      EventType = "config.updated"
-	EventTypeLogMessage         EventType = "log.message"
-	EventTypeWorkflowStarted    EventType = "workflow.started"
-	EventTypeWorkflowCompleted  EventType = "workflow.completed"
+	EventTypeAgentSpawned        EventType = "agent.spawned"
+	EventTypeAgentStatusChange   EventType = "agent.status_change"
+	EventTypeAgentHeartbeat      EventType = "agent.heartbeat"
+	EventTypeAgentCompleted      EventType = "agent.completed"
+	EventTypeBeadCreated         EventType = "bead.created"
+	EventTypeBeadAssigned        EventType = "bead.assigned"
+	EventTypeBeadStatusChange    EventType = "bead.status_change"
+	EventTypeBeadCompleted       EventType = "bead.completed"
+	EventTypeDecisionCreated     EventType = "decision.created"
+	EventTypeDecisionResolved    EventType = "decision.resolved"
+	EventTypeProviderRegistered  EventType = "provider.registered"
+	EventTypeProviderDeleted     EventType = "provider.deleted"
+	EventTypeProviderUpdated     EventType = "provider.updated"
+	EventTypeProjectCreated      EventType = "project.created"
+	EventTypeProjectUpdated      EventType = "project.updated"
+	EventTypeProjectDeleted      EventType = "project.deleted"
+	EventTypeConfigUpdated       EventType = "config.updated"
+	EventTypeLogMessage          EventType = "log.message"
+	EventTypeWorkflowStarted     EventType = "workflow.started"
+	EventTypeWorkflowCompleted   EventType = "workflow.completed"
+	EventTypeWorkflowNodeTimeout EventType = "workflow.node_timeout"
 
 	// Motivation system events
 	EventTypeMotivationFired     EventType = "motivation.fired"
@@ -45,6 +46,12 @@ const (
 	EventTypeDeadlinePassed      EventType = "deadline.passed"
 	EventTypeSystemIdle          EventType = "system.idle"
 
+	// Decision auto-resolution events
+	EventTypeDecisionAutoResolved EventType = "decision.auto_resolved"
+
+	// Dispatcher events
+	EventTypeDispatchStatusChange EventType = "dispatch.status_change"
+
 	// OpenClaw messaging gateway events
 	EventTypeOpenClawMessageSent     EventType = "openclaw.message_sent"
 	EventTypeOpenClawMessageFailed   EventType = "openclaw.message_failed"
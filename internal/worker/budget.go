@@ -0,0 +1,99 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jordanhubbard/loom/internal/provider"
+)
+
+// ErrBudgetExceeded is reported when a task would run, or has run, past its
+// token budget.
+var ErrBudgetExceeded = errors.New("token budget exceeded")
+
+// tokenBudget returns the task's budget in tokens, or 0 when it has none.
+// MaxCostUSD is converted at the provider's cost per million tokens and is
+// ignored for providers without a cost; the stricter limit applies.
+func (w *Worker) tokenBudget(task *Task) int {
+	budget := task.MaxTokens
+	if budget < 0 {
+		budget = 0
+	}
+	if task.MaxCostUSD > 0 && w.provider.Config.CostPerMToken > 0 {
+		costBudget := int(task.MaxCostUSD / w.provider.Config.CostPerMToken * 1_000_000)
+		if costBudget < 1 {
+			costBudget = 1
+		}
+		if budget == 0 || costBudget < budget {
+			budget = costBudget
+		}
+	}
+	return budget
+}
+
+// estimateTokens gives a rough prompt size (1 token ~= 4 characters).
+func estimateTokens(messages []provider.ChatMessage) int {
+	tokens := 0
+	for _, msg := range messages {
+		tokens += len(msg.Content) / 4
+	}
+	return tokens
+}
+
+// outputCap returns the completion limit the provider applies on its own:
+// its configured max_tokens, or the Anthropic default for Anthropic-style
+// providers. 0 means the provider sets no limit.
+func (w *Worker) outputCap() int {
+	if w.provider.Config.MaxTokens > 0 {
+		return w.provider.Config.MaxTokens
+	}
+	switch w.provider.Config.Type {
+	case "anthropic", "bedrock":
+		return provider.DefaultAnthropicMaxTokens
+	}
+	return 0
+}
+
+// applyBudget caps req's completion at what remains of the budget once its
+// prompt is paid for, keeping a lower cap already on req. It reports whether
+// the budget is what caps the completion, and fails when the prompt alone
+// would use the budget up.
+func applyBudget(req *provider.ChatCompletionRequest, remaining int) (bool, error) {
+	prompt := estimateTokens(req.Messages)
+	if prompt >= remaining {
+		return false, fmt.Errorf("%w: prompt needs ~%d tokens, %d left", ErrBudgetExceeded, prompt, remaining)
+	}
+	allowed := remaining - prompt
+	if req.MaxTokens > 0 && req.MaxTokens <= allowed {
+		return false, nil
+	}
+	req.MaxTokens = allowed
+	return true, nil
+}
+
+// overBudget reports whether a response used up the budget: either its
+// tokens reached it or, when the budget capped the completion, it was cut
+// off at that cap. A cut-off at the provider's own limit is not a budget
+// failure.
+func overBudget(resp *provider.ChatCompletionResponse, used, budget int, budgetCapped bool) bool {
+	if used >= budget {
+		return true
+	}
+	return budgetCapped && len(resp.Choices) > 0 && resp.Choices[0].Finish == "length"
+}
+
+// errStreamBudget stops a stream that reached its completion cap.
+var errStreamBudget = errors.New("completion token cap reached")
+
+// budgetResult is the failed result of a task stopped by its budget.
+func (w *Worker) budgetResult(task *Task, err error) *TaskResult {
+	return &TaskResult{
+		TaskID:      task.ID,
+		WorkerID:    w.id,
+		AgentID:     w.agent.ID,
+		ProviderID:  w.provider.Config.ID,
+		CompletedAt: time.Now(),
+		Error:       err.Error(),
+	}
+}
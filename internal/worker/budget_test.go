@@ -0,0 +1,195 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jordanhubbard/loom/internal/actions"
+	"github.com/jordanhubbard/loom/internal/provider"
+	"github.com/jordanhubbard/loom/pkg/models"
+)
+
+func newBudgetTestWorker(prov provider.Protocol, costPerMToken float64) *Worker {
+	rp := &provider.RegisteredProvider{
+		Config:   &provider.ProviderConfig{ID: "p1", Name: "P", Type: "openai", Model: "m", CostPerMToken: costPerMToken},
+		Protocol: prov,
+	}
+	return NewWorker("w1", &models.Agent{ID: "a1", Name: "A"}, rp)
+}
+
+func TestWorker_TokenBudget(t *testing.T) {
+	w := newBudgetTestWorker(&MockConversationProvider{}, 2.0)
+	tests := []struct {
+		name string
+		task Task
+		want int
+	}{
+		{"none", Task{}, 0},
+		{"tokens only", Task{MaxTokens: 5000}, 5000},
+		{"cost only", Task{MaxCostUSD: 0.01}, 5000},
+		{"cost stricter", Task{MaxTokens: 8000, MaxCostUSD: 0.01}, 5000},
+		{"tokens stricter", Task{MaxTokens: 3000, MaxCostUSD: 0.01}, 3000},
+	}
+	for _, tt := range tests {
+		if got := w.tokenBudget(&tt.task); got != tt.want {
+			t.Errorf("%s: tokenBudget() = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+
+	free := newBudgetTestWorker(&MockConversationProvider{}, 0)
+	if got := free.tokenBudget(&Task{MaxCostUSD: 0.01}); got != 0 {
+		t.Errorf("cost budget without provider cost = %d, want 0", got)
+	}
+}
+
+func TestWorker_ExecuteTask_RejectsPromptOverBudget(t *testing.T) {
+	w := newBudgetTestWorker(&MockConversationProvider{responseContent: "ok", tokenCount: 5}, 0)
+	result, err := w.ExecuteTask(t.Context(), &Task{
+		ID:          "t1",
+		Description: strings.Repeat("x", 4000),
+		MaxTokens:   100,
+	})
+	if err != nil {
+		t.Fatalf("ExecuteTask() error = %v", err)
+	}
+	if result.Success || !strings.HasPrefix(result.Error, ErrBudgetExceeded.Error()) {
+		t.Errorf("result = %+v, want a budget failure", result)
+	}
+	if result.TokensUsed != 0 {
+		t.Errorf("TokensUsed = %d, want 0 for a rejected task", result.TokensUsed)
+	}
+}
+
+func TestWorker_ExecuteTask_ReportsBudgetOverrun(t *testing.T) {
+	w := newBudgetTestWorker(&MockConversationProvider{responseContent: "ok", tokenCount: 50000}, 0)
+	result, err := w.ExecuteTask(t.Context(), &Task{ID: "t1", Description: "do it", MaxTokens: 20000})
+	if err != nil {
+		t.Fatalf("ExecuteTask() error = %v", err)
+	}
+	if result.Success || !strings.HasPrefix(result.Error, ErrBudgetExceeded.Error()) {
+		t.Errorf("result = %+v, want a budget failure", result)
+	}
+
+	w = newBudgetTestWorker(&MockConversationProvider{responseContent: "ok", tokenCount: 5}, 0)
+	result, err = w.ExecuteTask(t.Context(), &Task{ID: "t2", Description: "do it", MaxTokens: 20000})
+	if err != nil || !result.Success {
+		t.Errorf("ExecuteTask() within budget = %+v, %v, want success", result, err)
+	}
+}
+
+func TestWorker_ExecuteTaskWithLoop_StopsAtBudget(t *testing.T) {
+	mock := &MockConversationProvider{responseContent: `{"action": "done", "reason": "finished"}`, tokenCount: 15000}
+	w := newBudgetTestWorker(mock, 0)
+	config := &LoopConfig{
+		MaxIterations: 5,
+		Router:        &actions.Router{},
+		ActionContext: actions.ActionContext{ProjectID: "p1", BeadID: "b1"},
+		TextMode:      true,
+	}
+	result, err := w.ExecuteTaskWithLoop(t.Context(), &Task{ID: "t1", Description: "do it", MaxTokens: 12000}, config)
+	if err != nil {
+		t.Fatalf("ExecuteTaskWithLoop() error = %v", err)
+	}
+	if result.TerminalReason != "budget_exceeded" || result.Iterations != 1 {
+		t.Errorf("TerminalReason = %q after %d iterations, want budget_exceeded after 1", result.TerminalReason, result.Iterations)
+	}
+	if result.Success || !strings.HasPrefix(result.Error, ErrBudgetExceeded.Error()) {
+		t.Errorf("result = %+v, want a budget failure", result.TaskResult)
+	}
+}
+
+func TestStreamChatCompletion_StopsAtMaxTokens(t *testing.T) {
+	req := &provider.ChatCompletionRequest{
+		Model:     "m",
+		Messages:  []provider.ChatMessage{{Role: "user", Content: strings.Repeat("y", 200)}},
+		MaxTokens: 5,
+	}
+	var streamed strings.Builder
+	resp, err := streamChatCompletion(t.Context(), provider.NewMockProvider(), req, func(c string) { streamed.WriteString(c) })
+	if err != nil {
+		t.Fatalf("streamChatCompletion() error = %v", err)
+	}
+	if got := streamed.Len(); got < 20 || got >= 40 {
+		t.Errorf("streamed %d characters, want the stream to stop near 20", got)
+	}
+	if resp.Choices[0].Finish != "length" {
+		t.Errorf("Finish = %q, want length", resp.Choices[0].Finish)
+	}
+	if !overBudget(resp, resp.Usage.TotalTokens, 1<<20, true) {
+		t.Error("a response cut off at the budget's cap should count as over budget")
+	}
+	if overBudget(resp, resp.Usage.TotalTokens, 1<<20, false) {
+		t.Error("a response cut off at the provider's cap should not count as over budget")
+	}
+}
+
+func TestApplyBudget(t *testing.T) {
+	req := &provider.ChatCompletionRequest{Messages: []provider.ChatMessage{{Role: "user", Content: strings.Repeat("z", 400)}}}
+	capped, err := applyBudget(req, 1000)
+	if err != nil {
+		t.Fatalf("applyBudget() error = %v", err)
+	}
+	if req.MaxTokens != 900 || !capped {
+		t.Errorf("MaxTokens = %d, capped = %v, want 900 capped by the budget", req.MaxTokens, capped)
+	}
+
+	// A lower cap already on the request is kept.
+	req.MaxTokens = 300
+	if capped, err = applyBudget(req, 1000); err != nil || capped || req.MaxTokens != 300 {
+		t.Errorf("applyBudget() = %v, %v with MaxTokens %d, want the existing cap of 300 kept", capped, err, req.MaxTokens)
+	}
+
+	if _, err := applyBudget(req, 50); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("applyBudget() error = %v, want ErrBudgetExceeded", err)
+	}
+}
+
+// maxTokensRecorder records each request's max_tokens and answers with a
+// completion cut off at it.
+type maxTokensRecorder struct {
+	MockConversationProvider
+	maxTokens []int
+}
+
+func (p *maxTokensRecorder) CreateChatCompletion(ctx context.Context, req *provider.ChatCompletionRequest) (*provider.ChatCompletionResponse, error) {
+	p.maxTokens = append(p.maxTokens, req.MaxTokens)
+	resp, err := p.MockConversationProvider.CreateChatCompletion(ctx, req)
+	if err == nil {
+		resp.Choices[0].Finish = "length"
+	}
+	return resp, err
+}
+
+func TestWorker_ExecuteTask_BudgetKeepsProviderCap(t *testing.T) {
+	prov := &maxTokensRecorder{MockConversationProvider: MockConversationProvider{responseContent: "partial", tokenCount: 100}}
+	w := newBudgetTestWorker(prov, 0)
+	w.provider.Config.Type = "anthropic"
+
+	result, err := w.ExecuteTask(t.Context(), &Task{ID: "t1", Description: "do it", MaxTokens: 50000})
+	if err != nil {
+		t.Fatalf("ExecuteTask() error = %v", err)
+	}
+	if len(prov.maxTokens) != 1 || prov.maxTokens[0] != provider.DefaultAnthropicMaxTokens {
+		t.Errorf("max_tokens = %v, want the provider default %d", prov.maxTokens, provider.DefaultAnthropicMaxTokens)
+	}
+	if !result.Success {
+		t.Errorf("result = %+v, a cut-off at the provider's cap is not a budget failure", result)
+	}
+
+	// With a tight budget the budget sets the cap, and hitting it fails the task.
+	result, err = w.ExecuteTask(t.Context(), &Task{ID: "t2", Description: "do it", MaxTokens: 2000})
+	if err != nil {
+		t.Fatalf("ExecuteTask() error = %v", err)
+	}
+	if len(prov.maxTokens) != 2 {
+		t.Fatalf("result = %+v, want the request sent", result)
+	}
+	if got := prov.maxTokens[1]; got >= 2000 {
+		t.Errorf("max_tokens = %d, want the budget's remainder", got)
+	}
+	if result.Success || !strings.HasPrefix(result.Error, ErrBudgetExceeded.Error()) {
+		t.Errorf("result = %+v, want a budget failure", result)
+	}
+}
@@ -0,0 +1,150 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// taskSlots limits how many tasks a worker runs at once. A worker allowed
+// more than one concurrent task also queues up to that many more; a worker
+// limited to one rejects tasks while busy. Fields are guarded by Worker.mu.
+type taskSlots struct {
+	max     int // Concurrent tasks allowed; 0 means 1
+	running int
+	queued  int
+	freed   chan struct{} // Closed and replaced whenever a slot may have freed up
+}
+
+func (s *taskSlots) limit() int {
+	if s.max > 1 {
+		return s.max
+	}
+	return 1
+}
+
+func (s *taskSlots) queueLimit() int {
+	if s.max > 1 {
+		return s.max
+	}
+	return 0
+}
+
+// wait returns a channel that is closed on the next wake.
+func (s *taskSlots) wait() <-chan struct{} {
+	if s.freed == nil {
+		s.freed = make(chan struct{})
+	}
+	return s.freed
+}
+
+// wake releases every queued task to recheck for a free slot.
+func (s *taskSlots) wake() {
+	if s.freed != nil {
+		close(s.freed)
+		s.freed = nil
+	}
+}
+
+// SetMaxConcurrent sets how many tasks the worker may run at once. Values
+// below 1 mean one task at a time.
+func (w *Worker) SetMaxConcurrent(n int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.slots.max = n
+	w.slots.wake()
+}
+
+// HasCapacity reports whether the worker can accept another task, either
+// to run now or to queue.
+func (w *Worker) HasCapacity() bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.slotFreeLocked() || (w.status == WorkerStatusWorking && w.slots.queued < w.slots.queueLimit())
+}
+
+// slotFreeLocked reports whether a task can start now. The caller must hold
+// w.mu.
+func (w *Worker) slotFreeLocked() bool {
+	switch w.status {
+	case WorkerStatusIdle:
+		return true
+	case WorkerStatusWorking:
+		return w.slots.running > 0 && w.slots.running < w.slots.limit()
+	}
+	return false
+}
+
+// acquireSlot marks a task as running, first waiting in the worker's queue
+// when every slot is busy. It fails when the worker is stopped, the queue is
+// full, or ctx ends while queued.
+func (w *Worker) acquireSlot(ctx context.Context, taskID string) error {
+	w.mu.Lock()
+	queued := false
+	for !w.slotFreeLocked() {
+		if w.status != WorkerStatusWorking || (!queued && w.slots.queued >= w.slots.queueLimit()) {
+			if queued {
+				w.slots.queued--
+			}
+			w.mu.Unlock()
+			return fmt.Errorf("worker %s is not idle", w.id)
+		}
+		justQueued := !queued
+		if !queued {
+			w.slots.queued++
+			queued = true
+		}
+		freed := w.slots.wait()
+		w.mu.Unlock()
+		if justQueued {
+			w.slotsChanged()
+		}
+
+		select {
+		case <-freed:
+		case <-ctx.Done():
+			w.mu.Lock()
+			w.slots.queued--
+			w.mu.Unlock()
+			w.slotsChanged()
+			return fmt.Errorf("worker %s: task %s cancelled while queued: %w", w.id, taskID, ctx.Err())
+		}
+		w.mu.Lock()
+	}
+	if queued {
+		w.slots.queued--
+	}
+	w.slots.running++
+	w.status = WorkerStatusWorking
+	w.currentTask = taskID
+	w.lastActive = time.Now()
+	w.mu.Unlock()
+	w.slotsChanged()
+	return nil
+}
+
+// releaseSlot marks a task as finished and wakes queued tasks.
+func (w *Worker) releaseSlot(taskID string) {
+	defer w.slotsChanged()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.slots.running > 0 {
+		w.slots.running--
+	}
+	if w.slots.running == 0 && w.status == WorkerStatusWorking {
+		w.status = WorkerStatusIdle
+	}
+	if w.currentTask == taskID || w.slots.running == 0 {
+		w.currentTask = ""
+	}
+	w.lastActive = time.Now()
+	w.slots.wake()
+}
+
+// slotsChanged notifies the pool that the worker's running or queued tasks
+// changed. The caller must not hold w.mu.
+func (w *Worker) slotsChanged() {
+	if w.onSlotsChange != nil {
+		w.onSlotsChange()
+	}
+}
@@ -0,0 +1,287 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jordanhubbard/loom/internal/actions"
+	"github.com/jordanhubbard/loom/internal/provider"
+	"github.com/jordanhubbard/loom/pkg/models"
+)
+
+// blockingProvider holds each completion until release is closed.
+type blockingProvider struct {
+	MockConversationProvider
+	started chan struct{}
+	release chan struct{}
+}
+
+func (p *blockingProvider) CreateChatCompletion(ctx context.Context, req *provider.ChatCompletionRequest) (*provider.ChatCompletionResponse, error) {
+	p.started <- struct{}{}
+	<-p.release
+	return p.MockConversationProvider.CreateChatCompletion(ctx, req)
+}
+
+func newSlotTestWorker(maxConcurrent int) *Worker {
+	rp := &provider.RegisteredProvider{
+		Config:   &provider.ProviderConfig{ID: "p1", Name: "P", Model: "m"},
+		Protocol: &MockConversationProvider{responseContent: "ok"},
+	}
+	w := NewWorker("w1", &models.Agent{ID: "a1", Name: "A"}, rp)
+	w.SetMaxConcurrent(maxConcurrent)
+	return w
+}
+
+func TestWorker_DefaultConcurrencyRejectsWhileBusy(t *testing.T) {
+	w := newSlotTestWorker(0)
+	if err := w.acquireSlot(t.Context(), "t1"); err != nil {
+		t.Fatalf("acquireSlot() error = %v", err)
+	}
+	if w.HasCapacity() {
+		t.Error("busy single-task worker should have no capacity")
+	}
+	err := w.acquireSlot(t.Context(), "t2")
+	if err == nil || !strings.Contains(err.Error(), "not idle") {
+		t.Fatalf("second acquireSlot() error = %v, want not idle", err)
+	}
+	w.releaseSlot("t1")
+	if info := w.GetInfo(); info.Status != WorkerStatusIdle || info.Running != 0 || info.CurrentTask != "" {
+		t.Errorf("after release info = %+v, want idle", info)
+	}
+}
+
+func TestWorker_ConcurrentTasksAndQueue(t *testing.T) {
+	w := newSlotTestWorker(2)
+	for _, id := range []string{"t1", "t2"} {
+		if err := w.acquireSlot(t.Context(), id); err != nil {
+			t.Fatalf("acquireSlot(%s) error = %v", id, err)
+		}
+	}
+
+	// Two more tasks queue; a fifth is rejected.
+	acquired := make(chan string, 2)
+	for _, id := range []string{"t3", "t4"} {
+		go func(id string) {
+			if err := w.acquireSlot(context.Background(), id); err == nil {
+				acquired <- id
+			}
+		}(id)
+	}
+	waitFor(t, func() bool { return w.GetInfo().Queued == 2 })
+	if w.HasCapacity() {
+		t.Error("worker with full slots and queue should have no capacity")
+	}
+	if err := w.acquireSlot(t.Context(), "t5"); err == nil {
+		t.Fatal("acquireSlot() with a full queue should fail")
+	}
+
+	info := w.GetInfo()
+	if info.Running != 2 || info.Queued != 2 || info.MaxConcurrent != 2 {
+		t.Errorf("info = %+v, want 2 running, 2 queued", info)
+	}
+
+	w.releaseSlot("t1")
+	select {
+	case <-acquired:
+	case <-time.After(2 * time.Second):
+		t.Fatal("queued task did not start after a slot freed")
+	}
+	if info := w.GetInfo(); info.Running != 2 || info.Queued != 1 {
+		t.Errorf("after release info = %+v, want 2 running, 1 queued", info)
+	}
+}
+
+func TestWorker_QueuedTaskCancelled(t *testing.T) {
+	w := newSlotTestWorker(2)
+	_ = w.acquireSlot(t.Context(), "t1")
+	_ = w.acquireSlot(t.Context(), "t2")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- w.acquireSlot(ctx, "t3") }()
+	waitFor(t, func() bool { return w.GetInfo().Queued == 1 })
+	cancel()
+	if err := <-errCh; err == nil {
+		t.Fatal("cancelled queued task should fail")
+	}
+	if q := w.GetInfo().Queued; q != 0 {
+		t.Errorf("Queued = %d after cancellation, want 0", q)
+	}
+}
+
+func TestWorker_ExecuteTaskConcurrently(t *testing.T) {
+	prov := &blockingProvider{
+		MockConversationProvider: MockConversationProvider{responseContent: "done", tokenCount: 1},
+		started:                  make(chan struct{}, 2),
+		release:                  make(chan struct{}),
+	}
+	rp := &provider.RegisteredProvider{
+		Config:   &provider.ProviderConfig{ID: "p1", Name: "P", Model: "m"},
+		Protocol: prov,
+	}
+	pool := NewPool(provider.NewRegistry(), 1)
+	w := NewWorker("w1", &models.Agent{ID: "a1", Name: "A"}, rp)
+	w.SetMaxConcurrent(2)
+	pool.workers["a1"] = w
+
+	errs := make(chan error, 2)
+	for _, id := range []string{"t1", "t2"} {
+		go func(id string) {
+			_, err := w.ExecuteTask(context.Background(), &Task{ID: id, Description: "work"})
+			errs <- err
+		}(id)
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case <-prov.started:
+		case <-time.After(2 * time.Second):
+			t.Fatal("tasks did not run in parallel")
+		}
+	}
+	if stats := pool.GetPoolStats(); stats.RunningTasks != 2 || stats.WorkingWorkers != 1 {
+		t.Errorf("stats = %+v, want 2 running tasks on 1 working worker", stats)
+	}
+
+	close(prov.release)
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != nil {
+			t.Errorf("ExecuteTask() error = %v", err)
+		}
+	}
+	if stats := pool.GetPoolStats(); stats.RunningTasks != 0 || stats.IdleWorkers != 1 {
+		t.Errorf("stats = %+v, want idle", stats)
+	}
+}
+
+// TestWorker_ConcurrentLoopsKeepTheirModeAndProvider runs a text-mode task
+// and a JSON-mode task side by side, the second failing over to a backup
+// provider. Each must get its own prompt format and report the provider that
+// served it. Run with -race.
+func TestWorker_ConcurrentLoopsKeepTheirModeAndProvider(t *testing.T) {
+	// Hold each served completion until both tasks are mid-call.
+	var arrivals sync.WaitGroup
+	arrivals.Add(2)
+	var arriveOnce sync.Map
+	arrive := func(task string) {
+		if _, dup := arriveOnce.LoadOrStore(task, true); !dup {
+			arrivals.Done()
+		}
+		done := make(chan struct{})
+		go func() { arrivals.Wait(); close(done) }()
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+		}
+	}
+
+	var promptsMu sync.Mutex
+	prompts := make(map[string]string) // Task marker -> system prompt
+	serve := func(fail func(task string) bool) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			var req provider.ChatCompletionRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			var system, task string
+			for _, m := range req.Messages {
+				switch {
+				case m.Role == "system":
+					system = m.Content
+				case strings.Contains(m.Content, "text-task"):
+					task = "text-task"
+				case strings.Contains(m.Content, "json-task"):
+					task = "json-task"
+				}
+			}
+			if fail(task) {
+				http.Error(w, "overloaded", http.StatusServiceUnavailable)
+				return
+			}
+			promptsMu.Lock()
+			prompts[task] = system
+			promptsMu.Unlock()
+			arrive(task)
+
+			content := `{"action": "done", "reason": "ok"}`
+			if task == "json-task" {
+				content = `{"actions": [{"type": "done", "reason": "ok"}]}`
+			}
+			body, _ := json.Marshal(map[string]interface{}{
+				"id":      "c1",
+				"choices": []map[string]interface{}{{"index": 0, "message": map[string]string{"role": "assistant", "content": content}, "finish_reason": "stop"}},
+				"usage":   map[string]int{"total_tokens": 5},
+			})
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write(body)
+		}
+	}
+	primary := httptest.NewServer(serve(func(task string) bool { return task == "json-task" }))
+	defer primary.Close()
+	backup := httptest.NewServer(serve(func(string) bool { return false }))
+	defer backup.Close()
+
+	registry := provider.NewRegistry()
+	for _, cfg := range []*provider.ProviderConfig{
+		{ID: "backup", Type: "openai", Endpoint: backup.URL, Model: "backup-model", Status: "healthy"},
+		{ID: "primary", Type: "openai", Endpoint: primary.URL, Model: "primary-model", Status: "healthy", FallbackIDs: []string{"backup"}},
+	} {
+		if err := registry.Register(cfg); err != nil {
+			t.Fatalf("Register(%s): %v", cfg.ID, err)
+		}
+	}
+	rp, _ := registry.Get("primary")
+	w := NewWorker("w1", &models.Agent{ID: "a1", Name: "A"}, rp)
+	w.SetRegistry(registry)
+	w.SetMaxConcurrent(2)
+	_ = w.Start()
+
+	var wg sync.WaitGroup
+	results := make(map[string]*LoopResult)
+	var resultsMu sync.Mutex
+	for task, textMode := range map[string]bool{"text-task": true, "json-task": false} {
+		wg.Add(1)
+		go func(task string, textMode bool) {
+			defer wg.Done()
+			result, err := w.ExecuteTaskWithLoop(context.Background(), &Task{ID: task, Description: "run " + task}, &LoopConfig{
+				MaxIterations: 2,
+				Router:        &actions.Router{},
+				TextMode:      textMode,
+			})
+			if err != nil {
+				t.Errorf("%s: ExecuteTaskWithLoop() error = %v", task, err)
+			}
+			resultsMu.Lock()
+			results[task] = result
+			resultsMu.Unlock()
+		}(task, textMode)
+	}
+	wg.Wait()
+
+	if r := results["text-task"]; r == nil || r.ProviderID != "primary" {
+		t.Errorf("text task result = %+v, want served by primary", r)
+	}
+	if r := results["json-task"]; r == nil || r.ProviderID != "backup" {
+		t.Errorf("json task result = %+v, want served by backup", r)
+	}
+	if !strings.Contains(prompts["text-task"], "Every response is ONE action") {
+		t.Error("text task did not get the text-mode prompt")
+	}
+	if !strings.Contains(prompts["json-task"], "The response must be a single JSON object") {
+		t.Error("json task did not get the JSON-mode prompt")
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -19,6 +19,7 @@ type Pool struct {
 	db         *database.Database
 	mu         sync.RWMutex
 	maxWorkers int
+	observer   func(PoolStats)
 }
 
 // NewPool creates a new worker pool
@@ -37,8 +38,37 @@ func (p *Pool) SetDatabase(db *database.Database) {
 	p.db = db
 }
 
+// SetMaxWorkers changes how many workers the pool may hold. Existing workers
+// beyond the new limit keep running.
+func (p *Pool) SetMaxWorkers(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.maxWorkers = n
+}
+
+// SetStatsObserver registers fn to receive the pool's stats whenever workers
+// are spawned or stopped and whenever their tasks start, queue or finish.
+func (p *Pool) SetStatsObserver(fn func(PoolStats)) {
+	p.mu.Lock()
+	p.observer = fn
+	p.mu.Unlock()
+	p.reportStats()
+}
+
+// reportStats passes the current stats to the observer, if any. The caller
+// must not hold p.mu.
+func (p *Pool) reportStats() {
+	p.mu.RLock()
+	observer := p.observer
+	p.mu.RUnlock()
+	if observer != nil {
+		observer(p.GetPoolStats())
+	}
+}
+
 // SpawnWorker creates and starts a new worker for an agent
 func (p *Pool) SpawnWorker(agent *models.Agent, providerID string) (*Worker, error) {
+	defer p.reportStats()
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
@@ -51,7 +81,7 @@ func (p *Pool) SpawnWorker(agent *models.Agent, providerID string) (*Worker, err
 	if existingWorker, exists := p.workers[agent.ID]; exists {
 		// Worker exists - verify it's using the correct provider
 		workerInfo := existingWorker.GetInfo()
-		if workerInfo.ProviderID == providerID {
+		if workerInfo.ProviderID == providerID || workerInfo.ProviderGroup == providerID {
 			// Worker exists with correct provider - return it (idempotent)
 			log.Printf("Worker already exists for agent %s with provider %s (idempotent)", agent.ID, providerID)
 			return existingWorker, nil
@@ -63,8 +93,9 @@ func (p *Pool) SpawnWorker(agent *models.Agent, providerID string) (*Worker, err
 		delete(p.workers, agent.ID)
 	}
 
-	// Get provider from registry
-	registeredProvider, err := p.registry.Get(providerID)
+	// Get provider from registry; a group ID resolves to a weighted pick that
+	// serves as the worker's default, and the worker picks again per request
+	registeredProvider, err := p.registry.Resolve(providerID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get provider: %w", err)
 	}
@@ -72,6 +103,12 @@ func (p *Pool) SpawnWorker(agent *models.Agent, providerID string) (*Worker, err
 	// Create worker
 	workerID := fmt.Sprintf("worker-%s-%d", agent.ID, time.Now().Unix())
 	worker := NewWorker(workerID, agent, registeredProvider)
+	worker.SetRegistry(p.registry)
+	if p.registry.IsGroup(providerID) {
+		worker.SetProviderGroup(providerID)
+	}
+	worker.SetMaxConcurrent(agent.MaxConcurrent)
+	worker.onSlotsChange = p.reportStats
 
 	// Set database if available for conversation context support
 	if p.db != nil {
@@ -93,6 +130,7 @@ func (p *Pool) SpawnWorker(agent *models.Agent, providerID string) (*Worker, err
 
 // StopWorker stops and removes a worker
 func (p *Pool) StopWorker(agentID string) error {
+	defer p.reportStats()
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
@@ -176,7 +214,10 @@ func (p *Pool) GetPoolStats() PoolStats {
 	}
 
 	for _, worker := range p.workers {
-		switch worker.GetStatus() {
+		info := worker.GetInfo()
+		stats.RunningTasks += info.Running
+		stats.QueuedTasks += info.Queued
+		switch info.Status {
 		case WorkerStatusIdle:
 			stats.IdleWorkers++
 		case WorkerStatusWorking:
@@ -193,6 +234,7 @@ func (p *Pool) GetPoolStats() PoolStats {
 
 // StopAll stops all workers in the pool
 func (p *Pool) StopAll() {
+	defer p.reportStats()
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
@@ -212,4 +254,6 @@ type PoolStats struct {
 	ErrorWorkers   int
 	StoppedWorkers int
 	MaxWorkers     int
+	RunningTasks   int // Tasks executing across all workers
+	QueuedTasks    int // Tasks waiting for a free worker slot
 }
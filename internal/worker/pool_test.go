@@ -3,6 +3,8 @@ package worker
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/jordanhubbard/loom/internal/provider"
@@ -298,3 +300,84 @@ func TestPool_GetIdleWorkers(t *testing.T) {
 		t.Errorf("len(GetIdleWorkers()) = %d, want 1", len(idle))
 	}
 }
+
+func TestPool_StatsObserver(t *testing.T) {
+	registry := provider.NewRegistry()
+	registerMockProvider(t, registry, "mock-1")
+	pool := NewPool(registry, 5)
+
+	var reports []PoolStats
+	pool.SetStatsObserver(func(s PoolStats) { reports = append(reports, s) })
+	if len(reports) != 1 || reports[0].TotalWorkers != 0 || reports[0].MaxWorkers != 5 {
+		t.Fatalf("initial reports = %+v, want one empty pool", reports)
+	}
+
+	worker, err := pool.SpawnWorker(&models.Agent{ID: "agent-1", Name: "A"}, "mock-1")
+	if err != nil {
+		t.Fatalf("SpawnWorker() error = %v", err)
+	}
+	if last := reports[len(reports)-1]; last.TotalWorkers != 1 || last.IdleWorkers != 1 {
+		t.Errorf("after spawn = %+v, want one idle worker", last)
+	}
+
+	if err := worker.acquireSlot(context.Background(), "t1"); err != nil {
+		t.Fatalf("acquireSlot() error = %v", err)
+	}
+	if last := reports[len(reports)-1]; last.WorkingWorkers != 1 || last.RunningTasks != 1 {
+		t.Errorf("after task start = %+v, want one running task", last)
+	}
+	worker.releaseSlot("t1")
+	if last := reports[len(reports)-1]; last.IdleWorkers != 1 || last.RunningTasks != 0 {
+		t.Errorf("after task finish = %+v, want an idle worker", last)
+	}
+
+	if err := pool.StopWorker("agent-1"); err != nil {
+		t.Fatalf("StopWorker() error = %v", err)
+	}
+	if last := reports[len(reports)-1]; last.TotalWorkers != 0 {
+		t.Errorf("after stop = %+v, want no workers", last)
+	}
+}
+
+func TestPool_SpawnWorker_GroupPicksPerRequest(t *testing.T) {
+	calls := map[string]int{}
+	registry := provider.NewRegistry()
+	for _, id := range []string{"p1", "p2"} {
+		id := id
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls[id]++
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"id":"c1","choices":[{"index":0,"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}]}`))
+		}))
+		defer server.Close()
+		if err := registry.Register(&provider.ProviderConfig{ID: id, Type: "openai", Endpoint: server.URL, Model: "m-" + id, Status: "healthy"}); err != nil {
+			t.Fatalf("Register(%s): %v", id, err)
+		}
+	}
+	if err := registry.RegisterGroup("pool", []provider.WeightedProvider{{ProviderID: "p1", Weight: 1}, {ProviderID: "p2", Weight: 1}}); err != nil {
+		t.Fatalf("RegisterGroup: %v", err)
+	}
+	registry.SeedGroupPicker(1)
+
+	pool := NewPool(registry, 5)
+	agent := &models.Agent{ID: "agent-1", Name: "A"}
+	worker, err := pool.SpawnWorker(agent, "pool")
+	if err != nil {
+		t.Fatalf("SpawnWorker() error = %v", err)
+	}
+	if again, err := pool.SpawnWorker(agent, "pool"); err != nil || again != worker {
+		t.Errorf("second SpawnWorker() = %v, %v, want the existing worker", again, err)
+	}
+
+	served := map[string]bool{}
+	for i := 0; i < 20; i++ {
+		result, err := worker.ExecuteTask(context.Background(), &Task{ID: fmt.Sprintf("t%d", i), Description: "test"})
+		if err != nil {
+			t.Fatalf("ExecuteTask: %v", err)
+		}
+		served[result.ProviderID] = true
+	}
+	if calls["p1"] == 0 || calls["p2"] == 0 || !served["p1"] || !served["p2"] {
+		t.Errorf("calls = %v, served by %v, want requests spread across both members", calls, served)
+	}
+}
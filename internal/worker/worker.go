@@ -21,18 +21,21 @@ import (
 
 // Worker represents an agent worker that processes tasks
 type Worker struct {
-	id          string
-	agent       *models.Agent
-	provider    *provider.RegisteredProvider
-	db          *database.Database
-	textMode    bool // Use simple text-based actions instead of JSON
-	status      WorkerStatus
-	currentTask string
-	startedAt   time.Time
-	lastActive  time.Time
-	ctx         context.Context
-	cancel      context.CancelFunc
-	mu          sync.RWMutex
+	id            string
+	agent         *models.Agent
+	provider      *provider.RegisteredProvider
+	registry      *provider.Registry // Resolves fallback providers; nil disables fallback
+	group         string             // Provider group picked from on each request; empty for a single provider
+	db            *database.Database
+	status        WorkerStatus
+	currentTask   string
+	slots         taskSlots // Concurrent task limit and queue; see concurrency.go
+	onSlotsChange func()    // Called outside mu when tasks start, queue or finish; set by Pool
+	startedAt     time.Time
+	lastActive    time.Time
+	ctx           context.Context
+	cancel        context.CancelFunc
+	mu            sync.RWMutex
 }
 
 // WorkerStatus represents the status of a worker
@@ -88,6 +91,7 @@ func (w *Worker) Stop() {
 
 	w.cancel()
 	w.status = WorkerStatusStopped
+	w.slots.wake()
 
 	log.Printf("Worker %s stopped", w.id)
 }
@@ -99,26 +103,30 @@ func (w *Worker) SetDatabase(db *database.Database) {
 	w.db = db
 }
 
+// SetRegistry sets the registry used to enforce provider rate limits and to
+// find fallback providers when the worker's provider fails
+func (w *Worker) SetRegistry(registry *provider.Registry) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.registry = registry
+}
+
+// SetProviderGroup makes the worker pick a member of groupID for each
+// request instead of always using the provider it was created with. The
+// pick needs the registry set with SetRegistry.
+func (w *Worker) SetProviderGroup(groupID string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.group = groupID
+}
+
 // ExecuteTask executes a task using the agent's persona and provider
 // Supports multi-turn conversations when ConversationSession is provided or database is available
 func (w *Worker) ExecuteTask(ctx context.Context, task *Task) (*TaskResult, error) {
-	w.mu.Lock()
-	if w.status != WorkerStatusIdle {
-		w.mu.Unlock()
-		return nil, fmt.Errorf("worker %s is not idle", w.id)
+	if err := w.acquireSlot(ctx, task.ID); err != nil {
+		return nil, err
 	}
-	w.status = WorkerStatusWorking
-	w.currentTask = task.ID
-	w.lastActive = time.Now()
-	w.mu.Unlock()
-
-	defer func() {
-		w.mu.Lock()
-		w.status = WorkerStatusIdle
-		w.currentTask = ""
-		w.lastActive = time.Now()
-		w.mu.Unlock()
-	}()
+	defer w.releaseSlot(task.ID)
 
 	// Try to load or create conversation context
 	var messages []provider.ChatMessage
@@ -189,8 +197,17 @@ func (w *Worker) ExecuteTask(ctx context.Context, task *Task) (*TaskResult, erro
 		ResponseFormat: w.responseFormat(),
 	}
 
+	budget := w.tokenBudget(task)
+	budgetCapped := false
+	if budget > 0 {
+		req.MaxTokens = w.outputCap()
+		if budgetCapped, err = applyBudget(req, budget); err != nil {
+			return w.budgetResult(task, err), nil
+		}
+	}
+
 	// Send request to provider (with automatic context-length retry)
-	resp, usedMessages, err := w.callWithContextRetry(ctx, req)
+	resp, servedBy, usedMessages, err := w.callWithContextRetry(ctx, req, task.OnChunk)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get completion: %w", err)
 	}
@@ -230,9 +247,14 @@ func (w *Worker) ExecuteTask(ctx context.Context, task *Task) (*TaskResult, erro
 		AgentID:     w.agent.ID,
 		Response:    resp.Choices[0].Message.Content,
 		TokensUsed:  resp.Usage.TotalTokens,
+		ProviderID:  servedBy,
 		CompletedAt: time.Now(),
 		Success:     true,
 	}
+	if budget > 0 && overBudget(resp, result.TokensUsed, budget, budgetCapped) {
+		result.Success = false
+		result.Error = fmt.Errorf("%w: used %d of %d tokens", ErrBudgetExceeded, result.TokensUsed, budget).Error()
+	}
 
 	return result, nil
 }
@@ -243,7 +265,7 @@ func (w *Worker) buildConversationMessages(conversationCtx *models.ConversationC
 
 	// If no messages in history, add system prompt
 	if len(conversationCtx.Messages) == 0 {
-		systemPrompt := w.buildSystemPrompt()
+		systemPrompt := w.buildSystemPrompt(false)
 		conversationCtx.AddMessage("system", systemPrompt, len(systemPrompt)/4)
 	}
 
@@ -271,7 +293,7 @@ func (w *Worker) buildConversationMessages(conversationCtx *models.ConversationC
 
 // buildSingleShotMessages builds messages for single-shot execution (no conversation history)
 func (w *Worker) buildSingleShotMessages(task *Task) []provider.ChatMessage {
-	systemPrompt := w.buildSystemPrompt()
+	systemPrompt := w.buildSystemPrompt(false)
 	userPrompt := task.Description
 	if task.Context != "" {
 		userPrompt = fmt.Sprintf("%s\n\nContext:\n%s", userPrompt, task.Context)
@@ -390,18 +412,18 @@ func truncateMessages(messages []provider.ChatMessage, fraction float64) []provi
 }
 
 // callWithContextRetry calls CreateChatCompletion and retries with
-// progressively smaller message windows on ContextLengthError.
-// Returns the response and the final messages used (which may be truncated).
-func (w *Worker) callWithContextRetry(ctx context.Context, req *provider.ChatCompletionRequest) (*provider.ChatCompletionResponse, []provider.ChatMessage, error) {
+// progressively smaller message windows on provider.ErrContextLength.
+// Returns the response, the provider that served it, and the final messages
+// used (which may be truncated).
+func (w *Worker) callWithContextRetry(ctx context.Context, req *provider.ChatCompletionRequest, onChunk func(string)) (*provider.ChatCompletionResponse, string, []provider.ChatMessage, error) {
 	// Attempt 1: use messages as-is
-	resp, err := w.provider.Protocol.CreateChatCompletion(ctx, req)
+	resp, servedBy, err := w.createChatCompletion(ctx, req, onChunk)
 	if err == nil {
-		return resp, req.Messages, nil
+		return resp, servedBy, req.Messages, nil
 	}
 
-	var ctxErr *provider.ContextLengthError
-	if !errors.As(err, &ctxErr) {
-		return nil, req.Messages, err
+	if !errors.Is(err, provider.ErrContextLength) {
+		return nil, "", req.Messages, err
 	}
 
 	// Retry with progressively smaller context windows.
@@ -417,12 +439,12 @@ func (w *Worker) callWithContextRetry(ctx context.Context, req *provider.ChatCom
 		retryReq := *req
 		retryReq.Messages = truncated
 
-		resp, err = w.provider.Protocol.CreateChatCompletion(ctx, &retryReq)
+		resp, servedBy, err = w.createChatCompletion(ctx, &retryReq, onChunk)
 		if err == nil {
-			return resp, truncated, nil
+			return resp, servedBy, truncated, nil
 		}
-		if !errors.As(err, &ctxErr) {
-			return nil, truncated, err
+		if !errors.Is(err, provider.ErrContextLength) {
+			return nil, "", truncated, err
 		}
 	}
 
@@ -438,14 +460,193 @@ func (w *Worker) callWithContextRetry(ctx context.Context, req *provider.ChatCom
 
 			retryReq := *req
 			retryReq.Messages = minimal
-			resp, err = w.provider.Protocol.CreateChatCompletion(ctx, &retryReq)
+			resp, servedBy, err = w.createChatCompletion(ctx, &retryReq, onChunk)
+			if err == nil {
+				return resp, servedBy, minimal, nil
+			}
+		}
+	}
+
+	return nil, "", minimal, fmt.Errorf("context length exceeded after all retry attempts: %w", err)
+}
+
+// createChatCompletion sends req to the worker's provider, or to a member
+// picked for this call when the worker serves a provider group. Errors are
+// classified into the provider error categories; when the provider is
+// unavailable or rate limited it retries against the provider's active
+// fallbacks, in chain order, and returns which provider answered. A provider
+// already known to be down is skipped in favour of its fallbacks for this
+// call only, so the worker returns to it once it recovers. Each
+// request first waits for the target provider's rate limit, and its outcome
+// feeds that provider's circuit breaker. Each attempt goes through the
+// transform registered for the target's provider type. With onChunk set,
+// local providers stream the primary attempt; fallbacks are always blocking.
+func (w *Worker) createChatCompletion(ctx context.Context, req *provider.ChatCompletionRequest, onChunk func(string)) (*provider.ChatCompletionResponse, string, error) {
+	w.mu.RLock()
+	registry := w.registry
+	group := w.group
+	w.mu.RUnlock()
+
+	primary := w.provider
+	if group != "" && registry != nil {
+		if picked, pickErr := registry.PickFromGroup(group); pickErr == nil && picked.Config.ID != primary.Config.ID {
+			primary = picked
+			pickedReq := *req
+			pickedReq.Model = picked.Config.Model
+			req = &pickedReq
+		}
+	}
+
+	var resp *provider.ChatCompletionResponse
+	var err error
+	if registry != nil && !registry.IsActive(primary.Config.ID) && len(registry.ActiveFallbacks(primary.Config.ID)) > 0 {
+		err = fmt.Errorf("provider %s is inactive: %w", primary.Config.ID, provider.ErrProviderUnavailable)
+	} else {
+		if registry != nil {
+			if err := registry.WaitForRateLimit(ctx, primary.Config.ID); err != nil {
+				return nil, "", err
+			}
+		}
+		if registry != nil && !registry.AllowRequest(primary.Config.ID) {
+			// Another request is already probing the half-open circuit.
+			err = fmt.Errorf("provider %s circuit is open: %w", primary.Config.ID, provider.ErrProviderUnavailable)
+		} else {
+			resp, err = sendTransformed(ctx, primary, req, func(req *provider.ChatCompletionRequest) (*provider.ChatCompletionResponse, error) {
+				if sp, ok := localStreamer(primary); ok && onChunk != nil {
+					return streamChatCompletion(ctx, sp, req, onChunk)
+				}
+				return primary.Protocol.CreateChatCompletion(ctx, req)
+			})
+			err = provider.ClassifyError(err)
+			if registry != nil {
+				registry.RecordOutcome(primary.Config.ID, err)
+			}
 			if err == nil {
-				return resp, minimal, nil
+				return resp, primary.Config.ID, nil
+			}
+		}
+	}
+
+	if registry == nil || !provider.IsFailoverError(err) {
+		return nil, "", err
+	}
+
+	for _, fb := range registry.ActiveFallbacks(primary.Config.ID) {
+		log.Printf("[Worker] Provider %s failed (%v), falling back to %s", primary.Config.ID, err, fb.Config.ID)
+		if waitErr := registry.WaitForRateLimit(ctx, fb.Config.ID); waitErr != nil {
+			return nil, "", waitErr
+		}
+		if !registry.AllowRequest(fb.Config.ID) {
+			continue
+		}
+		fbReq := *req
+		fbReq.Model = fb.Config.Model
+		resp, err = sendTransformed(ctx, fb, &fbReq, func(req *provider.ChatCompletionRequest) (*provider.ChatCompletionResponse, error) {
+			return fb.Protocol.CreateChatCompletion(ctx, req)
+		})
+		err = provider.ClassifyError(err)
+		registry.RecordOutcome(fb.Config.ID, err)
+		if err == nil {
+			return resp, fb.Config.ID, nil
+		}
+		if !provider.IsFailoverError(err) {
+			return nil, "", err
+		}
+	}
+	return nil, "", err
+}
+
+// sendTransformed sends req with send, running it and the response through
+// the transform registered for p's provider type. Streamed chunks reach
+// onChunk untransformed; only the assembled response is transformed.
+func sendTransformed(ctx context.Context, p *provider.RegisteredProvider, req *provider.ChatCompletionRequest, send func(*provider.ChatCompletionRequest) (*provider.ChatCompletionResponse, error)) (*provider.ChatCompletionResponse, error) {
+	transform := provider.TransformFor(p.Config.Type)
+	out, err := transform.TransformRequest(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to transform request for provider %s: %w", p.Config.ID, err)
+	}
+	resp, err := send(out)
+	if err != nil {
+		return nil, err
+	}
+	resp, err = transform.TransformResponse(ctx, out, resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to transform response from provider %s: %w", p.Config.ID, err)
+	}
+	return resp, nil
+}
+
+// localStreamer returns p as a streaming protocol when it is a local model
+// server (type local or ollama).
+func localStreamer(p *provider.RegisteredProvider) (provider.StreamingProtocol, bool) {
+	if p.Config.Type != "local" && p.Config.Type != "ollama" {
+		return nil, false
+	}
+	sp, ok := p.Protocol.(provider.StreamingProtocol)
+	return sp, ok
+}
+
+// streamChatCompletion streams req, passing each piece of content to onChunk,
+// and assembles the pieces into a regular completion response. Usage comes
+// from the final chunk when the provider reports it, otherwise it is
+// estimated at four characters per token. With req.MaxTokens set, streaming
+// stops once the content reaches it and the response finishes with "length".
+func streamChatCompletion(ctx context.Context, sp provider.StreamingProtocol, req *provider.ChatCompletionRequest, onChunk func(string)) (*provider.ChatCompletionResponse, error) {
+	streamReq := *req // CreateChatCompletionStream may set Stream on the request
+	var content strings.Builder
+	var model, finish string
+	var usage *provider.StreamUsage
+
+	err := sp.CreateChatCompletionStream(ctx, &streamReq, func(chunk *provider.StreamChunk) error {
+		if chunk.Model != "" {
+			model = chunk.Model
+		}
+		if chunk.Usage != nil {
+			usage = chunk.Usage
+		}
+		for _, choice := range chunk.Choices {
+			if choice.FinishReason != "" {
+				finish = choice.FinishReason
+			}
+			if choice.Delta.Content == "" {
+				continue
+			}
+			content.WriteString(choice.Delta.Content)
+			onChunk(choice.Delta.Content)
+			if req.MaxTokens > 0 && content.Len()/4 >= req.MaxTokens {
+				finish = "length"
+				return errStreamBudget
 			}
 		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errStreamBudget) {
+		return nil, err
 	}
 
-	return nil, minimal, fmt.Errorf("context length exceeded after all retry attempts: %w", err)
+	resp := &provider.ChatCompletionResponse{
+		Object:  "chat.completion",
+		Created: time.Now().Unix(),
+		Model:   model,
+	}
+	resp.Choices = append(resp.Choices, struct {
+		Index   int                  `json:"index"`
+		Message provider.ChatMessage `json:"message"`
+		Finish  string               `json:"finish_reason"`
+	}{Message: provider.ChatMessage{Role: "assistant", Content: content.String()}, Finish: finish})
+
+	if usage != nil && usage.TotalTokens > 0 {
+		resp.Usage.PromptTokens = usage.PromptTokens
+		resp.Usage.CompletionTokens = usage.CompletionTokens
+		resp.Usage.TotalTokens = usage.TotalTokens
+	} else {
+		for _, msg := range req.Messages {
+			resp.Usage.PromptTokens += len(msg.Content) / 4
+		}
+		resp.Usage.CompletionTokens = content.Len() / 4
+		resp.Usage.TotalTokens = resp.Usage.PromptTokens + resp.Usage.CompletionTokens
+	}
+	return resp, nil
 }
 
 // messageExists checks if a message with the same content already exists in history
@@ -459,11 +660,12 @@ func (w *Worker) messageExists(messages []models.ChatMessage, content string) bo
 }
 
 // buildSystemPrompt builds the system prompt: ReAct operating model first,
-// brief persona role second.
-func (w *Worker) buildSystemPrompt() string {
+// brief persona role second. textMode selects the simple text-based action
+// format instead of JSON.
+func (w *Worker) buildSystemPrompt(textMode bool) string {
 	// 1. Action format with ReAct pattern FIRST
 	var prompt string
-	if w.textMode {
+	if textMode {
 		prompt = actions.SimpleJSONPrompt + "\n\n"
 	} else {
 		prompt = actions.ActionPrompt + "\n\n"
@@ -519,14 +721,18 @@ func (w *Worker) GetInfo() WorkerInfo {
 	defer w.mu.RUnlock()
 
 	return WorkerInfo{
-		ID:          w.id,
-		AgentName:   w.agent.Name,
-		PersonaName: w.agent.PersonaName,
-		ProviderID:  w.provider.Config.ID,
-		Status:      w.status,
-		CurrentTask: w.currentTask,
-		StartedAt:   w.startedAt,
-		LastActive:  w.lastActive,
+		ID:            w.id,
+		AgentName:     w.agent.Name,
+		PersonaName:   w.agent.PersonaName,
+		ProviderID:    w.provider.Config.ID,
+		ProviderGroup: w.group,
+		Status:        w.status,
+		CurrentTask:   w.currentTask,
+		Running:       w.slots.running,
+		Queued:        w.slots.queued,
+		MaxConcurrent: w.slots.limit(),
+		StartedAt:     w.startedAt,
+		LastActive:    w.lastActive,
 	}
 }
 
@@ -538,6 +744,18 @@ type Task struct {
 	BeadID              string
 	ProjectID           string
 	ConversationSession *models.ConversationContext // Optional: enables multi-turn conversation
+
+	// OnChunk, if set, receives response text as it is generated. Only local
+	// providers stream; others deliver the whole response at once and never
+	// call it. TaskResult.Response always holds the full text.
+	OnChunk func(chunk string)
+
+	// MaxTokens caps prompt plus completion tokens over the whole task, and
+	// MaxCostUSD caps its cost at the provider's price; the stricter applies.
+	// Zero means no limit. A task that would exceed its budget fails with
+	// ErrBudgetExceeded.
+	MaxTokens  int
+	MaxCostUSD float64
 }
 
 // TaskResult represents the result of task execution
@@ -548,6 +766,7 @@ type TaskResult struct {
 	Response           string
 	Actions            []actions.Result
 	TokensUsed         int
+	ProviderID         string // Provider that served the request; a fallback if the primary failed
 	CompletedAt        time.Time
 	Success            bool
 	Error              string
@@ -557,14 +776,18 @@ type TaskResult struct {
 
 // WorkerInfo contains information about a worker
 type WorkerInfo struct {
-	ID          string
-	AgentName   string
-	PersonaName string
-	ProviderID  string
-	Status      WorkerStatus
-	CurrentTask string
-	StartedAt   time.Time
-	LastActive  time.Time
+	ID            string
+	AgentName     string
+	PersonaName   string
+	ProviderID    string
+	ProviderGroup string // Group the worker picks providers from, if any
+	Status        WorkerStatus
+	CurrentTask   string // Most recently started task
+	Running       int    // Tasks executing now
+	Queued        int    // Tasks waiting for a free slot
+	MaxConcurrent int
+	StartedAt     time.Time
+	LastActive    time.Time
 }
 
 // --- Multi-turn action loop ---
@@ -590,11 +813,26 @@ type LoopConfig struct {
 type LoopResult struct {
 	*TaskResult
 	Iterations     int                    `json:"iterations"`
-	TerminalReason string                 `json:"terminal_reason"` // "completed", "max_iterations", "escalated", "error", "no_actions", "parse_failures", "progress_stagnant"
+	TerminalReason string                 `json:"terminal_reason"` // "completed", "max_iterations", "escalated", "error", "no_actions", "parse_failures", "progress_stagnant", "budget_exceeded"
 	ActionLog      []ActionLogEntry       `json:"action_log"`
 	Metadata       map[string]interface{} `json:"metadata,omitempty"` // For progress metrics and remediation analysis
 }
 
+// LoopCallError is returned when a provider call in the action loop fails.
+// ActionsRun counts the actions the loop had already executed, which a
+// caller must not repeat by simply running the task again.
+type LoopCallError struct {
+	Iteration  int
+	ActionsRun int
+	Err        error
+}
+
+func (e *LoopCallError) Error() string {
+	return fmt.Sprintf("LLM call failed on iteration %d: %v", e.Iteration, e.Err)
+}
+
+func (e *LoopCallError) Unwrap() error { return e.Err }
+
 // ActionLogEntry records a single iteration of the action loop.
 type ActionLogEntry struct {
 	Iteration int              `json:"iteration"`
@@ -634,24 +872,10 @@ func isConversationalResponse(response string) bool {
 // ExecuteTaskWithLoop runs the task in a multi-turn action loop:
 // call LLM → parse actions → execute → format results → feed back → repeat.
 func (w *Worker) ExecuteTaskWithLoop(ctx context.Context, task *Task, config *LoopConfig) (*LoopResult, error) {
-	w.textMode = config.TextMode
-	w.mu.Lock()
-	if w.status != WorkerStatusIdle {
-		w.mu.Unlock()
-		return nil, fmt.Errorf("worker %s is not idle", w.id)
+	if err := w.acquireSlot(ctx, task.ID); err != nil {
+		return nil, err
 	}
-	w.status = WorkerStatusWorking
-	w.currentTask = task.ID
-	w.lastActive = time.Now()
-	w.mu.Unlock()
-
-	defer func() {
-		w.mu.Lock()
-		w.status = WorkerStatusIdle
-		w.currentTask = ""
-		w.lastActive = time.Now()
-		w.mu.Unlock()
-	}()
+	defer w.releaseSlot(task.ID)
 
 	maxIter := config.MaxIterations
 	if maxIter <= 0 {
@@ -692,7 +916,7 @@ func (w *Worker) ExecuteTaskWithLoop(ctx context.Context, task *Task, config *Lo
 	}
 
 	// Build system prompt with lessons
-	systemPrompt := w.buildEnhancedSystemPrompt(config.LessonsProvider, task.ProjectID, task.Context)
+	systemPrompt := w.buildEnhancedSystemPrompt(config.LessonsProvider, task.ProjectID, task.Context, config.TextMode)
 
 	if conversationCtx != nil {
 		if len(conversationCtx.Messages) == 0 {
@@ -727,6 +951,7 @@ func (w *Worker) ExecuteTaskWithLoop(ctx context.Context, task *Task, config *Lo
 	}
 
 	tracker := NewProgressTracker(maxIter)
+	budget := w.tokenBudget(task)
 
 	var allActions []actions.Result
 	consecutiveParseFailures := 0
@@ -755,10 +980,24 @@ func (w *Worker) ExecuteTaskWithLoop(ctx context.Context, task *Task, config *Lo
 			Temperature:    0.1,
 			ResponseFormat: w.responseFormat(),
 		}
+		budgetCapped := false
+		if budget > 0 {
+			req.MaxTokens = w.outputCap()
+			var err error
+			if budgetCapped, err = applyBudget(req, budget-loopResult.TokensUsed); err != nil {
+				loopResult.TerminalReason = "budget_exceeded"
+				loopResult.Iterations = iteration
+				loopResult.Actions = allActions
+				loopResult.Success = false
+				loopResult.Error = err.Error()
+				loopResult.CompletedAt = time.Now()
+				return loopResult, nil
+			}
+		}
 
 		log.Printf("[ActionLoop] Iteration %d/%d for task %s (messages: %d, textMode: %v)", iteration+1, maxIter, task.ID, len(trimmedMessages), config.TextMode)
 
-		resp, usedMsgs, err := w.callWithContextRetry(ctx, req)
+		resp, servedBy, usedMsgs, err := w.callWithContextRetry(ctx, req, task.OnChunk)
 		if err != nil {
 			loopResult.TerminalReason = "error"
 			loopResult.Iterations = iteration + 1
@@ -766,7 +1005,7 @@ func (w *Worker) ExecuteTaskWithLoop(ctx context.Context, task *Task, config *Lo
 			loopResult.Success = false
 			loopResult.Error = err.Error()
 			loopResult.CompletedAt = time.Now()
-			return loopResult, fmt.Errorf("LLM call failed on iteration %d: %w", iteration+1, err)
+			return loopResult, &LoopCallError{Iteration: iteration + 1, ActionsRun: len(allActions), Err: err}
 		}
 		// If messages were truncated by retry, update the working set
 		if len(usedMsgs) < len(trimmedMessages) {
@@ -785,7 +1024,17 @@ func (w *Worker) ExecuteTaskWithLoop(ctx context.Context, task *Task, config *Lo
 
 		llmResponse := resp.Choices[0].Message.Content
 		loopResult.Response = llmResponse
+		loopResult.ProviderID = servedBy
 		loopResult.TokensUsed += resp.Usage.TotalTokens
+		if budget > 0 && overBudget(resp, loopResult.TokensUsed, budget, budgetCapped) {
+			loopResult.TerminalReason = "budget_exceeded"
+			loopResult.Iterations = iteration + 1
+			loopResult.Actions = allActions
+			loopResult.Success = false
+			loopResult.Error = fmt.Errorf("%w: used %d of %d tokens", ErrBudgetExceeded, loopResult.TokensUsed, budget).Error()
+			loopResult.CompletedAt = time.Now()
+			return loopResult, nil
+		}
 
 		// Add assistant message to conversation
 		messages = append(messages, provider.ChatMessage{Role: "assistant", Content: llmResponse})
@@ -1046,7 +1295,7 @@ func (w *Worker) ExecuteTaskWithLoop(ctx context.Context, task *Task, config *Lo
 
 // buildEnhancedSystemPrompt builds the system prompt with ReAct operating model first,
 // brief persona role second, and action format last.
-func (w *Worker) buildEnhancedSystemPrompt(lp LessonsProvider, projectID, progressCtx string) string {
+func (w *Worker) buildEnhancedSystemPrompt(lp LessonsProvider, projectID, progressCtx string, textMode bool) string {
 	// Get lessons — try file-based LESSONS.md first, then semantic search, then recency
 	var lessons string
 	if projectID != "" {
@@ -1065,7 +1314,7 @@ func (w *Worker) buildEnhancedSystemPrompt(lp LessonsProvider, projectID, progre
 
 	// 1. Action format with ReAct pattern FIRST — this is the operating model
 	var prompt string
-	if w.textMode {
+	if textMode {
 		prompt = actions.BuildSimpleJSONPrompt(lessons, progressCtx) + "\n\n"
 	} else {
 		prompt = actions.BuildEnhancedPrompt(lessons, progressCtx) + "\n\n"
@@ -2,6 +2,10 @@ package worker
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
@@ -84,7 +88,7 @@ func TestWorker_GetInfo(t *testing.T) {
 
 func TestWorker_buildSystemPrompt_NilPersona(t *testing.T) {
 	w := makeTestWorker(nil)
-	prompt := w.buildSystemPrompt()
+	prompt := w.buildSystemPrompt(false)
 
 	if !strings.Contains(prompt, "Test Agent") {
 		t.Error("prompt should contain agent name when no persona")
@@ -99,7 +103,7 @@ func TestWorker_buildSystemPrompt_WithPersona(t *testing.T) {
 		Character: "A skilled Go developer",
 		Mission:   "Write clean code",
 	})
-	prompt := w.buildSystemPrompt()
+	prompt := w.buildSystemPrompt(false)
 
 	if !strings.Contains(prompt, "A skilled Go developer") {
 		t.Error("prompt should contain character")
@@ -113,7 +117,7 @@ func TestWorker_buildSystemPrompt_PersonaNoCharacter(t *testing.T) {
 	w := makeTestWorker(&models.Persona{
 		Mission: "Help with tasks",
 	})
-	prompt := w.buildSystemPrompt()
+	prompt := w.buildSystemPrompt(false)
 
 	if !strings.Contains(prompt, "Test Agent") {
 		t.Error("should fall back to agent name when no character")
@@ -456,6 +460,90 @@ func min(a, b int) int {
 
 // --- Tests that need a mock provider (for ExecuteTask paths) ---
 
+func newOllamaStreamServer(t *testing.T, gotFormat *string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Stream bool   `json:"stream"`
+			Format string `json:"format"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if !req.Stream {
+			t.Error("expected a streaming request")
+		}
+		*gotFormat = req.Format
+		for _, part := range []string{"Hel", "lo, ", "world"} {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"model":   "llama3",
+				"message": map[string]string{"role": "assistant", "content": part},
+				"done":    false,
+			})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"model":             "llama3",
+			"message":           map[string]string{"role": "assistant", "content": ""},
+			"done":              true,
+			"prompt_eval_count": 12,
+			"eval_count":        3,
+		})
+	}))
+}
+
+func TestWorker_ExecuteTask_StreamsLocalProvider(t *testing.T) {
+	var format string
+	srv := newOllamaStreamServer(t, &format)
+	defer srv.Close()
+
+	rp := &provider.RegisteredProvider{
+		Config:   &provider.ProviderConfig{ID: "ollama", Name: "Ollama", Type: "ollama", Endpoint: "http://localhost", Model: "llama3"},
+		Protocol: provider.NewOllamaProvider(srv.URL),
+	}
+	w := NewWorker("w1", &models.Agent{ID: "a1", Name: "A"}, rp)
+
+	var chunks []string
+	result, err := w.ExecuteTask(t.Context(), &Task{
+		ID:          "t1",
+		Description: "greet",
+		OnChunk:     func(c string) { chunks = append(chunks, c) },
+	})
+	if err != nil {
+		t.Fatalf("ExecuteTask() error = %v", err)
+	}
+	if strings.Join(chunks, "|") != "Hel|lo, |world" {
+		t.Errorf("chunks = %q, want three incremental pieces", chunks)
+	}
+	if result.Response != "Hello, world" {
+		t.Errorf("Response = %q, want aggregated text", result.Response)
+	}
+	if result.TokensUsed != 15 {
+		t.Errorf("TokensUsed = %d, want 15 from the final chunk", result.TokensUsed)
+	}
+	if format != "json" {
+		t.Errorf("format = %q, want json for a localhost endpoint", format)
+	}
+}
+
+func TestWorker_ExecuteTask_OnChunkIgnoredByRemoteProvider(t *testing.T) {
+	mockProv := &MockConversationProvider{responseContent: "whole answer", tokenCount: 5}
+	rp := &provider.RegisteredProvider{
+		Config:   &provider.ProviderConfig{ID: "p1", Name: "P", Type: "openai", Model: "m"},
+		Protocol: mockProv,
+	}
+	w := NewWorker("w1", &models.Agent{ID: "a1", Name: "A"}, rp)
+
+	called := false
+	result, err := w.ExecuteTask(t.Context(), &Task{ID: "t1", Description: "test", OnChunk: func(string) { called = true }})
+	if err != nil {
+		t.Fatalf("ExecuteTask() error = %v", err)
+	}
+	if called {
+		t.Error("OnChunk should not be called for a non-streaming provider")
+	}
+	if result.Response != "whole answer" {
+		t.Errorf("Response = %q, want whole answer", result.Response)
+	}
+}
+
 func TestWorker_ExecuteTask_NotIdle(t *testing.T) {
 	mockProv := &MockConversationProvider{responseContent: "ok", tokenCount: 5}
 	rp := &provider.RegisteredProvider{
@@ -523,7 +611,7 @@ func TestWorker_ExecuteTask_WithConversationSession(t *testing.T) {
 func TestWorker_buildEnhancedSystemPrompt(t *testing.T) {
 	t.Run("nil persona", func(t *testing.T) {
 		w := makeTestWorker(nil)
-		prompt := w.buildEnhancedSystemPrompt(nil, "proj-1", "")
+		prompt := w.buildEnhancedSystemPrompt(nil, "proj-1", "", false)
 		if !strings.Contains(prompt, "Test Agent") {
 			t.Error("should contain agent name")
 		}
@@ -534,7 +622,7 @@ func TestWorker_buildEnhancedSystemPrompt(t *testing.T) {
 			Character: "Expert coder",
 			Mission:   "Ship fast",
 		})
-		prompt := w.buildEnhancedSystemPrompt(nil, "proj-1", "")
+		prompt := w.buildEnhancedSystemPrompt(nil, "proj-1", "", false)
 		if !strings.Contains(prompt, "Expert coder") {
 			t.Error("should contain character")
 		}
@@ -545,8 +633,7 @@ func TestWorker_buildEnhancedSystemPrompt(t *testing.T) {
 
 	t.Run("text mode", func(t *testing.T) {
 		w := makeTestWorker(nil)
-		w.textMode = true
-		prompt := w.buildEnhancedSystemPrompt(nil, "proj-1", "some progress")
+		prompt := w.buildEnhancedSystemPrompt(nil, "proj-1", "some progress", true)
 		if prompt == "" {
 			t.Error("prompt should not be empty")
 		}
@@ -555,7 +642,7 @@ func TestWorker_buildEnhancedSystemPrompt(t *testing.T) {
 	t.Run("with lessons provider", func(t *testing.T) {
 		w := makeTestWorker(nil)
 		lp := &mockLessonsProvider{lessonsText: "Lesson: always run tests"}
-		prompt := w.buildEnhancedSystemPrompt(lp, "proj-1", "building feature")
+		prompt := w.buildEnhancedSystemPrompt(lp, "proj-1", "building feature", false)
 		_ = prompt // Just verify it doesn't panic
 	})
 }
@@ -819,6 +906,47 @@ func TestWorker_ExecuteTaskWithLoop_ConversationalSlip(t *testing.T) {
 	}
 }
 
+// failAfterProvider serves its responses in turn, then fails every call.
+type failAfterProvider struct {
+	sequenceMockProvider
+	err error
+}
+
+func (m *failAfterProvider) CreateChatCompletion(ctx context.Context, req *provider.ChatCompletionRequest) (*provider.ChatCompletionResponse, error) {
+	if m.callCount >= len(m.responses) {
+		return nil, m.err
+	}
+	return m.sequenceMockProvider.CreateChatCompletion(ctx, req)
+}
+
+func TestWorker_ExecuteTaskWithLoop_CallErrorCountsActionsRun(t *testing.T) {
+	mock := &failAfterProvider{
+		sequenceMockProvider: sequenceMockProvider{responses: []string{`{"actions": [{"type": "read_code", "path": "main.go"}]}`}},
+		err:                  &provider.StatusError{StatusCode: 503, Body: "overloaded"},
+	}
+	rp := &provider.RegisteredProvider{
+		Config:   &provider.ProviderConfig{ID: "p1", Name: "P", Model: "m"},
+		Protocol: mock,
+	}
+	w := NewWorker("w1", &models.Agent{ID: "a1", Name: "Agent"}, rp)
+	_ = w.Start()
+
+	_, err := w.ExecuteTaskWithLoop(context.Background(), &Task{ID: "t1", Description: "do something"}, &LoopConfig{
+		MaxIterations: 5,
+		Router:        &actions.Router{},
+	})
+	var callErr *LoopCallError
+	if !errors.As(err, &callErr) {
+		t.Fatalf("error = %v, want a LoopCallError", err)
+	}
+	if callErr.Iteration != 2 || callErr.ActionsRun != 1 {
+		t.Errorf("LoopCallError = %+v, want iteration 2 after 1 action", callErr)
+	}
+	if !errors.Is(err, provider.ErrProviderUnavailable) {
+		t.Errorf("error = %v, want it to wrap the provider failure", err)
+	}
+}
+
 func TestWorker_ExecuteTaskWithLoop_WithConversationSession(t *testing.T) {
 	mock := &sequenceMockProvider{
 		responses: []string{`{"action": "done", "reason": "done"}`},
@@ -880,3 +1008,247 @@ func TestWorker_ExecuteTaskWithLoop_DefaultMaxIterations(t *testing.T) {
 		t.Errorf("TerminalReason = %q, want completed", result.TerminalReason)
 	}
 }
+
+func TestWorker_ExecuteTask_FallsBackOnServerError(t *testing.T) {
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "overloaded", http.StatusServiceUnavailable)
+	}))
+	defer primary.Close()
+
+	var fallbackModel string
+	backup := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req provider.ChatCompletionRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		fallbackModel = req.Model
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"c1","choices":[{"index":0,"message":{"role":"assistant","content":"done"},"finish_reason":"stop"}],"usage":{"total_tokens":7}}`))
+	}))
+	defer backup.Close()
+
+	registry := provider.NewRegistry()
+	for _, cfg := range []*provider.ProviderConfig{
+		{ID: "backup", Type: "openai", Endpoint: backup.URL, Model: "backup-model", Status: "healthy"},
+		{ID: "primary", Type: "openai", Endpoint: primary.URL, Model: "primary-model", Status: "healthy", FallbackIDs: []string{"backup"}},
+	} {
+		if err := registry.Register(cfg); err != nil {
+			t.Fatalf("Register(%s): %v", cfg.ID, err)
+		}
+	}
+	rp, _ := registry.Get("primary")
+
+	w := NewWorker("w1", &models.Agent{ID: "a1", Name: "A"}, rp)
+	w.SetRegistry(registry)
+	_ = w.Start()
+
+	result, err := w.ExecuteTask(context.Background(), &Task{ID: "t1", Description: "test"})
+	if err != nil {
+		t.Fatalf("ExecuteTask: %v", err)
+	}
+	if result.ProviderID != "backup" {
+		t.Errorf("ProviderID = %q, want backup", result.ProviderID)
+	}
+	if fallbackModel != "backup-model" {
+		t.Errorf("fallback request model = %q, want backup-model", fallbackModel)
+	}
+	if result.Response != "done" {
+		t.Errorf("Response = %q, want done", result.Response)
+	}
+}
+
+func TestWorker_ExecuteTask_SkipsInactiveProvider(t *testing.T) {
+	primaryCalls := 0
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		primaryCalls++
+		http.Error(w, "down", http.StatusServiceUnavailable)
+	}))
+	defer primary.Close()
+	backup := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"c1","choices":[{"index":0,"message":{"role":"assistant","content":"done"},"finish_reason":"stop"}]}`))
+	}))
+	defer backup.Close()
+
+	registry := provider.NewRegistry()
+	for _, cfg := range []*provider.ProviderConfig{
+		{ID: "backup", Type: "openai", Endpoint: backup.URL, Model: "backup-model", Status: "healthy"},
+		{ID: "primary", Type: "openai", Endpoint: primary.URL, Model: "primary-model", Status: "unhealthy", FallbackIDs: []string{"backup"}},
+	} {
+		if err := registry.Register(cfg); err != nil {
+			t.Fatalf("Register(%s): %v", cfg.ID, err)
+		}
+	}
+	rp, _ := registry.Get("primary")
+
+	w := NewWorker("w1", &models.Agent{ID: "a1", Name: "A", ProviderID: "primary"}, rp)
+	w.SetRegistry(registry)
+	_ = w.Start()
+
+	result, err := w.ExecuteTask(context.Background(), &Task{ID: "t1", Description: "test"})
+	if err != nil {
+		t.Fatalf("ExecuteTask: %v", err)
+	}
+	if primaryCalls != 0 {
+		t.Errorf("inactive primary called %d times, want 0", primaryCalls)
+	}
+	if result.ProviderID != "backup" {
+		t.Errorf("ProviderID = %q, want backup", result.ProviderID)
+	}
+	if info := w.GetInfo(); info.ProviderID != "primary" {
+		t.Errorf("worker provider = %q, want it to stay on primary", info.ProviderID)
+	}
+}
+
+// systemAsUserTransform folds the system prompt into the first user message
+// and tags responses, as a gateway without system-role support would need.
+type systemAsUserTransform struct{}
+
+func (systemAsUserTransform) TransformRequest(ctx context.Context, req *provider.ChatCompletionRequest) (*provider.ChatCompletionRequest, error) {
+	out := *req
+	out.Messages = nil
+	var system string
+	for _, m := range req.Messages {
+		if m.Role == "system" {
+			system = m.Content
+			continue
+		}
+		if m.Role == "user" && system != "" {
+			m.Content = system + "\n\n" + m.Content
+			system = ""
+		}
+		out.Messages = append(out.Messages, m)
+	}
+	return &out, nil
+}
+
+func (systemAsUserTransform) TransformResponse(ctx context.Context, req *provider.ChatCompletionRequest, resp *provider.ChatCompletionResponse) (*provider.ChatCompletionResponse, error) {
+	for i := range resp.Choices {
+		resp.Choices[i].Message.Content = "[gateway] " + resp.Choices[i].Message.Content
+	}
+	return resp, nil
+}
+
+func TestWorker_ExecuteTask_AppliesProviderTransform(t *testing.T) {
+	provider.RegisterTransform("custom", systemAsUserTransform{})
+	defer provider.RegisterTransform("custom", nil)
+
+	var roles []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req provider.ChatCompletionRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		for _, m := range req.Messages {
+			roles = append(roles, m.Role)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"ok"}}]}`))
+	}))
+	defer server.Close()
+
+	registry := provider.NewRegistry()
+	_ = registry.Register(&provider.ProviderConfig{ID: "gw", Type: "custom", Endpoint: server.URL, Model: "m", Status: "healthy"})
+	rp, _ := registry.Get("gw")
+
+	w := NewWorker("w1", &models.Agent{ID: "a1", Name: "A"}, rp)
+	w.SetRegistry(registry)
+	_ = w.Start()
+
+	result, err := w.ExecuteTask(context.Background(), &Task{ID: "t1", Description: "test"})
+	if err != nil {
+		t.Fatalf("ExecuteTask: %v", err)
+	}
+	for _, role := range roles {
+		if role == "system" {
+			t.Errorf("request roles = %v, want the system prompt folded into a user message", roles)
+		}
+	}
+	if result.Response != "[gateway] ok" {
+		t.Errorf("Response = %q, want the transformed response", result.Response)
+	}
+}
+
+func TestWorker_ExecuteTask_NoFallbackOnClientError(t *testing.T) {
+	var backupCalls int
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad request", http.StatusBadRequest)
+	}))
+	defer primary.Close()
+	backup := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		backupCalls++
+	}))
+	defer backup.Close()
+
+	registry := provider.NewRegistry()
+	_ = registry.Register(&provider.ProviderConfig{ID: "backup", Type: "openai", Endpoint: backup.URL, Model: "m", Status: "healthy"})
+	_ = registry.Register(&provider.ProviderConfig{ID: "primary", Type: "openai", Endpoint: primary.URL, Model: "m", Status: "healthy", FallbackIDs: []string{"backup"}})
+	rp, _ := registry.Get("primary")
+
+	w := NewWorker("w1", &models.Agent{ID: "a1", Name: "A"}, rp)
+	w.SetRegistry(registry)
+	_ = w.Start()
+
+	if _, err := w.ExecuteTask(context.Background(), &Task{ID: "t1", Description: "test"}); err == nil {
+		t.Fatal("expected error from primary")
+	}
+	if backupCalls != 0 {
+		t.Errorf("expected no fallback on 4xx, backup called %d times", backupCalls)
+	}
+}
+
+func TestWorker_ExecuteTask_WaitsForRateLimit(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"ok"}}]}`))
+	}))
+	defer server.Close()
+
+	registry := provider.NewRegistry()
+	_ = registry.Register(&provider.ProviderConfig{ID: "p1", Type: "openai", Endpoint: server.URL, Model: "m", Status: "healthy", RequestsPerMinute: 1})
+	rp, _ := registry.Get("p1")
+
+	w := NewWorker("w1", &models.Agent{ID: "a1", Name: "A"}, rp)
+	w.SetRegistry(registry)
+	_ = w.Start()
+
+	if _, err := w.ExecuteTask(context.Background(), &Task{ID: "t1", Description: "first"}); err != nil {
+		t.Fatalf("first task: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := w.ExecuteTask(ctx, &Task{ID: "t2", Description: "second"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("second task err = %v, want deadline exceeded while waiting for rate limit", err)
+	}
+	if calls != 1 {
+		t.Errorf("provider called %d times, want 1", calls)
+	}
+}
+
+func TestWorker_ExecuteTask_OpensCircuitOnServerErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "overloaded", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	registry := provider.NewRegistry()
+	registry.SetCircuitBreaker(2, time.Hour)
+	_ = registry.Register(&provider.ProviderConfig{ID: "p1", Type: "openai", Endpoint: server.URL, Model: "m", Status: "healthy"})
+	rp, _ := registry.Get("p1")
+
+	w := NewWorker("w1", &models.Agent{ID: "a1", Name: "A"}, rp)
+	w.SetRegistry(registry)
+	_ = w.Start()
+
+	for i := 0; i < 2; i++ {
+		if _, err := w.ExecuteTask(context.Background(), &Task{ID: "t1", Description: "test"}); err == nil {
+			t.Fatal("expected error from provider")
+		}
+	}
+	if got := registry.CircuitState("p1"); got != provider.CircuitOpen {
+		t.Errorf("circuit = %s, want open", got)
+	}
+	if registry.IsActive("p1") {
+		t.Error("provider with open circuit reported active")
+	}
+}
@@ -4,12 +4,18 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jordanhubbard/loom/internal/telemetry"
+	"github.com/jordanhubbard/loom/pkg/models"
 )
 
+// DefaultMaxParallelFanOut is the most branches a parallel node may spawn
+// unless overridden with SetMaxParallelFanOut.
+const DefaultMaxParallelFanOut = 8
+
 // Database interface for workflow operations
 type Database interface {
 	GetWorkflow(id string) (*Workflow, error)
@@ -25,6 +31,14 @@ type Database interface {
 	DeleteWorkflowExecutionByBeadID(beadID string) error
 }
 
+// DefinitionStore is implemented by databases that can save and delete a
+// workflow definition as a whole, as the workflow API does.
+type DefinitionStore interface {
+	SaveWorkflowDefinition(wf *Workflow) error
+	DeleteWorkflow(id string) error
+	CountActiveWorkflowExecutions(workflowID string) (int, error)
+}
+
 // BeadManager interface for bead operations
 type BeadManager interface {
 	UpdateBead(id string, updates map[string]interface{}) error
@@ -35,10 +49,20 @@ type BeadCreator interface {
 	GetBead(id string) (interface{}, error)
 }
 
+// ChildBeadCreator is implemented by bead managers that can create the child
+// beads a parallel node fans out to.
+type ChildBeadCreator interface {
+	CreateBead(title, description string, priority models.BeadPriority, beadType, projectID string) (*models.Bead, error)
+}
+
 // Engine manages workflow execution
 type Engine struct {
-	db    Database
-	beads BeadManager
+	db        Database
+	beads     BeadManager
+	maxFanOut int
+	joinMu    sync.Mutex // Serializes child results recorded on a parent
+	timeoutMu sync.Mutex // Serializes timeout checks so each timeout fires once
+	onTimeout func(NodeTimeout)
 }
 
 // NewEngine creates a new workflow engine
@@ -49,6 +73,12 @@ func NewEngine(db Database, beads BeadManager) *Engine {
 	}
 }
 
+// SetMaxParallelFanOut caps how many branches a parallel node may spawn.
+// Values <= 0 use DefaultMaxParallelFanOut.
+func (e *Engine) SetMaxParallelFanOut(n int) {
+	e.maxFanOut = n
+}
+
 // GetDatabase returns the underlying database interface
 func (e *Engine) GetDatabase() Database {
 	return e.db
@@ -211,6 +241,9 @@ func (e *Engine) AdvanceWorkflow(executionID string, condition EdgeCondition, ag
 	if exec.Status == ExecutionStatusCompleted || exec.Status == ExecutionStatusEscalated {
 		return fmt.Errorf("workflow execution already %s", exec.Status)
 	}
+	if exec.Status == ExecutionStatusBlocked && pendingChildren(exec) > 0 {
+		return fmt.Errorf("workflow execution is waiting on %d parallel branches", pendingChildren(exec))
+	}
 
 	// Record history
 	resultJSON := ""
@@ -245,6 +278,8 @@ func (e *Engine) AdvanceWorkflow(executionID string, condition EdgeCondition, ag
 		now := time.Now()
 		exec.CompletedAt = &now
 		exec.LastNodeAt = now
+		exec.NodeDispatchedAt = nil
+		exec.ParallelChildren = nil
 
 		if err := e.db.UpsertWorkflowExecution(exec); err != nil {
 			return fmt.Errorf("failed to complete workflow: %w", err)
@@ -262,6 +297,10 @@ func (e *Engine) AdvanceWorkflow(executionID string, condition EdgeCondition, ag
 		}
 
 		log.Printf("[Workflow] Completed workflow execution %s for bead %s", executionID, exec.BeadID)
+
+		if exec.ParentExecutionID != "" {
+			return e.recordChildResult(exec, EdgeConditionSuccess)
+		}
 		return nil
 	}
 
@@ -282,10 +321,16 @@ func (e *Engine) AdvanceWorkflow(executionID string, condition EdgeCondition, ag
 		return e.escalateWorkflow(exec, fmt.Sprintf("Exceeded max cycles (3): workflow has cycled %d times", exec.CycleCount))
 	}
 
+	if nextNode.NodeType == NodeTypeParallel {
+		return e.fanOut(exec, nextNode)
+	}
+
 	// Move to next node
 	exec.CurrentNodeKey = nextNode.NodeKey
 	exec.NodeAttemptCount = 0 // Reset attempt count for new node
 	exec.LastNodeAt = time.Now()
+	exec.NodeDispatchedAt = nil
+	exec.ParallelChildren = nil
 
 	if err := e.db.UpsertWorkflowExecution(exec); err != nil {
 		return fmt.Errorf("failed to update workflow execution: %w", err)
@@ -422,6 +467,202 @@ func (e *Engine) escalateWorkflow(exec *WorkflowExecution, reason string) error
 
 	log.Printf("[Workflow] Workflow escalated for bead %s - CEO escalation bead should be created", exec.BeadID)
 
+	if exec.ParentExecutionID != "" {
+		if err := e.recordChildResult(exec, EdgeConditionFailure); err != nil {
+			log.Printf("[Workflow] Warning: failed to record escalated branch on parent: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// fanOut moves the execution onto a parallel node, spawns a child bead and
+// execution for each of the node's branch edges, and blocks the execution
+// until every child finishes.
+func (e *Engine) fanOut(exec *WorkflowExecution, node *WorkflowNode) error {
+	wf, err := e.db.GetWorkflow(exec.WorkflowID)
+	if err != nil {
+		return fmt.Errorf("failed to get workflow: %w", err)
+	}
+
+	var branches []WorkflowNode
+	for _, edge := range wf.Edges {
+		if edge.FromNodeKey != node.NodeKey || edge.Condition != EdgeConditionBranch {
+			continue
+		}
+		target := findNode(wf, edge.ToNodeKey)
+		if target == nil {
+			return fmt.Errorf("branch target node not found: %s", edge.ToNodeKey)
+		}
+		branches = append(branches, *target)
+	}
+	if len(branches) == 0 {
+		return fmt.Errorf("parallel node %s has no branch edges", node.NodeKey)
+	}
+
+	exec.CurrentNodeKey = node.NodeKey
+	exec.NodeAttemptCount = 0
+	exec.LastNodeAt = time.Now()
+	exec.NodeDispatchedAt = nil
+
+	maxFanOut := e.maxFanOut
+	if maxFanOut <= 0 {
+		maxFanOut = DefaultMaxParallelFanOut
+	}
+	if len(branches) > maxFanOut {
+		return e.escalateWorkflow(exec, fmt.Sprintf("Parallel node %s fans out to %d branches (max %d)", node.NodeKey, len(branches), maxFanOut))
+	}
+
+	creator, ok := e.beads.(ChildBeadCreator)
+	if !ok {
+		return fmt.Errorf("parallel node %s requires a bead manager that can create beads", node.NodeKey)
+	}
+
+	children := make([]*WorkflowExecution, 0, len(branches))
+	for _, branch := range branches {
+		title := fmt.Sprintf("[%s] %s for %s", node.NodeKey, branch.NodeKey, exec.BeadID)
+		bead, err := creator.CreateBead(title, branch.Instructions, models.BeadPriorityP2, "task", exec.ProjectID)
+		if err != nil {
+			return fmt.Errorf("failed to create bead for branch %s: %w", branch.NodeKey, err)
+		}
+
+		child := &WorkflowExecution{
+			ID:                fmt.Sprintf("wfex-%s", uuid.New().String()[:8]),
+			WorkflowID:        exec.WorkflowID,
+			BeadID:            bead.ID,
+			ProjectID:         exec.ProjectID,
+			CurrentNodeKey:    branch.NodeKey,
+			Status:            ExecutionStatusActive,
+			CycleCount:        exec.CycleCount, // Branches share the parent's cycle budget
+			StartedAt:         time.Now(),
+			LastNodeAt:        time.Now(),
+			ParentExecutionID: exec.ID,
+		}
+		if err := e.db.UpsertWorkflowExecution(child); err != nil {
+			return fmt.Errorf("failed to create execution for branch %s: %w", branch.NodeKey, err)
+		}
+		children = append(children, child)
+
+		childContext := map[string]string{
+			"workflow_id":             exec.WorkflowID,
+			"workflow_exec_id":        child.ID,
+			"workflow_node":           branch.NodeKey,
+			"workflow_status":         string(ExecutionStatusActive),
+			"parent_workflow_exec_id": exec.ID,
+		}
+		if branch.RoleRequired != "" {
+			childContext["required_role"] = branch.RoleRequired
+		}
+		updates := map[string]interface{}{
+			"parent":  exec.BeadID,
+			"context": childContext,
+		}
+		if err := e.beads.UpdateBead(bead.ID, updates); err != nil {
+			log.Printf("[Workflow] Warning: failed to update branch bead context: %v", err)
+		}
+	}
+
+	exec.Status = ExecutionStatusBlocked
+	exec.ParallelChildren = make(map[string]EdgeCondition, len(children))
+	for _, child := range children {
+		exec.ParallelChildren[child.BeadID] = ""
+	}
+	if err := e.db.UpsertWorkflowExecution(exec); err != nil {
+		return fmt.Errorf("failed to update workflow execution: %w", err)
+	}
+
+	updates := map[string]interface{}{
+		"context": map[string]string{
+			"workflow_node":        node.NodeKey,
+			"workflow_status":      string(ExecutionStatusBlocked),
+			"cycle_count":          fmt.Sprintf("%d", exec.CycleCount),
+			"redispatch_requested": "false",
+		},
+	}
+	if err := e.beads.UpdateBead(exec.BeadID, updates); err != nil {
+		log.Printf("[Workflow] Warning: failed to update bead context: %v", err)
+	}
+
+	log.Printf("[Workflow] Bead %s fanned out to %d branches at node %s (cycle %d)",
+		exec.BeadID, len(children), node.NodeKey, exec.CycleCount)
+
+	return nil
+}
+
+// recordChildResult stores a finished branch's result on its parent. Once
+// every branch has finished the parent advances past the parallel node with
+// success if all branches succeeded and failure otherwise.
+func (e *Engine) recordChildResult(child *WorkflowExecution, condition EdgeCondition) error {
+	e.joinMu.Lock()
+	parent, err := e.db.GetWorkflowExecution(child.ParentExecutionID)
+	if err != nil {
+		e.joinMu.Unlock()
+		return fmt.Errorf("failed to get parent execution: %w", err)
+	}
+	if _, ok := parent.ParallelChildren[child.BeadID]; !ok || parent.Status != ExecutionStatusBlocked {
+		e.joinMu.Unlock()
+		return nil // Branch of an earlier fan-out that has already joined
+	}
+
+	parent.ParallelChildren[child.BeadID] = condition
+	history := &WorkflowExecutionHistory{
+		ID:            fmt.Sprintf("wfhist-%s", uuid.New().String()[:8]),
+		ExecutionID:   parent.ID,
+		NodeKey:       parent.CurrentNodeKey,
+		AgentID:       child.BeadID,
+		Condition:     condition,
+		ResultData:    branchResultPrefix + child.ID,
+		AttemptNumber: parent.NodeAttemptCount,
+		CreatedAt:     time.Now(),
+	}
+	if err := e.db.InsertWorkflowHistory(history); err != nil {
+		log.Printf("[Workflow] Warning: failed to insert history: %v", err)
+	}
+
+	pending := pendingChildren(parent)
+	if pending == 0 {
+		parent.Status = ExecutionStatusActive
+	}
+	err = e.db.UpsertWorkflowExecution(parent)
+	e.joinMu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to update parent execution: %w", err)
+	}
+	if pending > 0 {
+		log.Printf("[Workflow] Branch %s of bead %s finished (%s), %d still running",
+			child.BeadID, parent.BeadID, condition, pending)
+		return nil
+	}
+
+	joined := EdgeConditionSuccess
+	results := make(map[string]string, len(parent.ParallelChildren))
+	for beadID, result := range parent.ParallelChildren {
+		results[beadID] = string(result)
+		if result != EdgeConditionSuccess {
+			joined = EdgeConditionFailure
+		}
+	}
+	log.Printf("[Workflow] All branches of bead %s finished, joining with %s", parent.BeadID, joined)
+	return e.AdvanceWorkflow(parent.ID, joined, "system", results)
+}
+
+// pendingChildren counts the parallel branches that have not finished.
+func pendingChildren(exec *WorkflowExecution) int {
+	pending := 0
+	for _, result := range exec.ParallelChildren {
+		if result == "" {
+			pending++
+		}
+	}
+	return pending
+}
+
+func findNode(wf *Workflow, nodeKey string) *WorkflowNode {
+	for i := range wf.Nodes {
+		if wf.Nodes[i].NodeKey == nodeKey {
+			return &wf.Nodes[i]
+		}
+	}
 	return nil
 }
 
@@ -535,45 +776,21 @@ func (e *Engine) IsNodeReady(execution *WorkflowExecution) bool {
 	return true
 }
 
-// CheckNodeTimeout checks if the current node has exceeded its timeout
+// CheckNodeTimeout checks if the current node has exceeded its timeout. A
+// timed-out node is left along its timeout edge, or the execution is
+// escalated if it has none, and an error reports the timeout.
 func (e *Engine) CheckNodeTimeout(execution *WorkflowExecution) error {
 	if execution.CurrentNodeKey == "" {
 		return nil // At workflow start, no timeout
 	}
 
-	// Get current node
-	node, err := e.GetCurrentNode(execution.ID)
-	if err != nil || node == nil {
-		return nil // No node, no timeout
+	timedOut, err := e.handleNodeTimeout(execution.ID)
+	if err != nil {
+		return err
 	}
-
-	// Check if node has timeout configured
-	if node.TimeoutMinutes <= 0 {
-		return nil // No timeout configured
+	if timedOut {
+		return fmt.Errorf("node %s timed out", execution.CurrentNodeKey)
 	}
-
-	// Calculate time since node started
-	timeSinceNode := time.Since(execution.LastNodeAt)
-	timeoutDuration := time.Duration(node.TimeoutMinutes) * time.Minute
-
-	if timeSinceNode > timeoutDuration {
-		// Node has timed out - advance workflow with timeout condition
-		log.Printf("[Workflow] Node %s timed out for bead %s (elapsed: %v, timeout: %v)",
-			node.NodeKey, execution.BeadID, timeSinceNode, timeoutDuration)
-
-		resultData := map[string]string{
-			"timeout_reason": fmt.Sprintf("Node exceeded timeout of %d minutes", node.TimeoutMinutes),
-			"elapsed_time":   timeSinceNode.String(),
-		}
-
-		// Advance with timeout condition
-		if err := e.AdvanceWorkflow(execution.ID, EdgeConditionTimeout, "system", resultData); err != nil {
-			return fmt.Errorf("node timed out but failed to advance workflow: %w", err)
-		}
-
-		return fmt.Errorf("node %s timed out after %v", node.NodeKey, timeSinceNode)
-	}
-
 	return nil
 }
 
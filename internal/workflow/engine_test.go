@@ -1,8 +1,11 @@
 package workflow
 
 import (
+	"fmt"
 	"testing"
 	"time"
+
+	"github.com/jordanhubbard/loom/pkg/models"
 )
 
 // TestShouldRedispatch tests the shouldRedispatch helper function
@@ -324,6 +327,16 @@ func (m *mockDatabase) GetWorkflowExecution(id string) (*WorkflowExecution, erro
 	return exec, nil
 }
 
+func (m *mockDatabase) ListActiveWorkflowExecutions() ([]*WorkflowExecution, error) {
+	var result []*WorkflowExecution
+	for _, exec := range m.executions {
+		if exec.Status == ExecutionStatusActive {
+			result = append(result, exec)
+		}
+	}
+	return result, nil
+}
+
 func (m *mockDatabase) GetWorkflowExecutionByBeadID(beadID string) (*WorkflowExecution, error) {
 	exec, ok := m.beadExecutions[beadID]
 	if !ok {
@@ -714,3 +727,193 @@ func TestAdvanceWorkflow_ApprovalNode(t *testing.T) {
 		t.Errorf("Expected redispatch_requested = %q for approval node, got %q", "false", ctx["redispatch_requested"])
 	}
 }
+
+// fanOutBeadManager is a mock bead manager that can create branch beads
+type fanOutBeadManager struct {
+	*mockBeadManager
+	created []string
+}
+
+func (m *fanOutBeadManager) CreateBead(title, description string, priority models.BeadPriority, beadType, projectID string) (*models.Bead, error) {
+	id := fmt.Sprintf("bead-child-%d", len(m.created)+1)
+	m.created = append(m.created, id)
+	return &models.Bead{ID: id, Title: title, Description: description, Priority: priority, Type: beadType, ProjectID: projectID}, nil
+}
+
+// newParallelWorkflow builds: start -> split -(branch)-> {a, b} -> end,
+// joining to "done" on success and "fix" on failure; "fix" loops back to split.
+func newParallelWorkflow() *Workflow {
+	edge := func(from, to string, cond EdgeCondition) WorkflowEdge {
+		return WorkflowEdge{WorkflowID: "wf-par", FromNodeKey: from, ToNodeKey: to, Condition: cond, Priority: 100}
+	}
+	return &Workflow{
+		ID:           "wf-par",
+		Name:         "Parallel Workflow",
+		WorkflowType: "test",
+		Nodes: []WorkflowNode{
+			{WorkflowID: "wf-par", NodeKey: "split", NodeType: NodeTypeParallel},
+			{WorkflowID: "wf-par", NodeKey: "a", NodeType: NodeTypeTask, MaxAttempts: 1, RoleRequired: "QA Engineer"},
+			{WorkflowID: "wf-par", NodeKey: "b", NodeType: NodeTypeTask, MaxAttempts: 1},
+			{WorkflowID: "wf-par", NodeKey: "done", NodeType: NodeTypeTask, MaxAttempts: 3},
+			{WorkflowID: "wf-par", NodeKey: "fix", NodeType: NodeTypeTask, MaxAttempts: 3},
+		},
+		Edges: []WorkflowEdge{
+			edge("", "split", EdgeConditionSuccess),
+			edge("split", "a", EdgeConditionBranch),
+			edge("split", "b", EdgeConditionBranch),
+			edge("a", "", EdgeConditionSuccess),
+			edge("b", "", EdgeConditionSuccess),
+			edge("split", "done", EdgeConditionSuccess),
+			edge("split", "fix", EdgeConditionFailure),
+			edge("fix", "split", EdgeConditionSuccess),
+			edge("done", "", EdgeConditionSuccess),
+		},
+	}
+}
+
+func newParallelEngine(t *testing.T) (*Engine, *mockDatabase, *fanOutBeadManager) {
+	t.Helper()
+	db := newMockDatabase()
+	beads := &fanOutBeadManager{mockBeadManager: newMockBeadManager()}
+	db.workflows["wf-par"] = newParallelWorkflow()
+	engine := NewEngine(db, beads)
+	if _, err := engine.StartWorkflow("bead-1", "wf-par", "proj-1"); err != nil {
+		t.Fatalf("StartWorkflow() error = %v", err)
+	}
+	return engine, db, beads
+}
+
+func TestAdvanceWorkflow_ParallelFanOutAndJoin(t *testing.T) {
+	engine, db, beads := newParallelEngine(t)
+	parent := db.beadExecutions["bead-1"]
+
+	if err := engine.AdvanceWorkflow(parent.ID, EdgeConditionSuccess, "agent-1", nil); err != nil {
+		t.Fatalf("AdvanceWorkflow() error = %v", err)
+	}
+	if parent.CurrentNodeKey != "split" || parent.Status != ExecutionStatusBlocked {
+		t.Fatalf("parent at %q (%s), want split (blocked)", parent.CurrentNodeKey, parent.Status)
+	}
+	if len(beads.created) != 2 || len(parent.ParallelChildren) != 2 {
+		t.Fatalf("created %d beads, tracking %d children; want 2", len(beads.created), len(parent.ParallelChildren))
+	}
+
+	childA := db.beadExecutions["bead-child-1"]
+	childB := db.beadExecutions["bead-child-2"]
+	if childA == nil || childB == nil {
+		t.Fatal("expected an execution for each branch bead")
+	}
+	if childA.CurrentNodeKey != "a" || childB.CurrentNodeKey != "b" {
+		t.Errorf("branches start at %q and %q, want a and b", childA.CurrentNodeKey, childB.CurrentNodeKey)
+	}
+	if childA.ParentExecutionID != parent.ID {
+		t.Errorf("ParentExecutionID = %q, want %q", childA.ParentExecutionID, parent.ID)
+	}
+	childUpdates := beads.beads["bead-child-1"]
+	if childUpdates["parent"] != "bead-1" {
+		t.Errorf("branch bead parent = %v, want bead-1", childUpdates["parent"])
+	}
+	if ctx := childUpdates["context"].(map[string]string); ctx["required_role"] != "QA Engineer" {
+		t.Errorf("branch bead required_role = %q, want QA Engineer", ctx["required_role"])
+	}
+	if ctx := beads.beads["bead-1"]["context"].(map[string]string); ctx["redispatch_requested"] != "false" {
+		t.Errorf("parent redispatch_requested = %q, want false", ctx["redispatch_requested"])
+	}
+
+	if err := engine.AdvanceWorkflow(parent.ID, EdgeConditionSuccess, "agent-1", nil); err == nil {
+		t.Error("expected advancing a parent with running branches to fail")
+	}
+
+	if err := engine.AdvanceWorkflow(childA.ID, EdgeConditionSuccess, "agent-2", nil); err != nil {
+		t.Fatalf("AdvanceWorkflow(childA) error = %v", err)
+	}
+	if parent.Status != ExecutionStatusBlocked {
+		t.Fatalf("parent status = %s after one branch, want blocked", parent.Status)
+	}
+
+	if err := engine.AdvanceWorkflow(childB.ID, EdgeConditionSuccess, "agent-3", nil); err != nil {
+		t.Fatalf("AdvanceWorkflow(childB) error = %v", err)
+	}
+	if parent.CurrentNodeKey != "done" || parent.Status != ExecutionStatusActive {
+		t.Errorf("parent at %q (%s), want done (active)", parent.CurrentNodeKey, parent.Status)
+	}
+	if parent.ParallelChildren != nil {
+		t.Errorf("ParallelChildren = %v, want cleared after join", parent.ParallelChildren)
+	}
+}
+
+func TestAdvanceWorkflow_ParallelBranchFailure(t *testing.T) {
+	engine, db, beads := newParallelEngine(t)
+	parent := db.beadExecutions["bead-1"]
+
+	if err := engine.AdvanceWorkflow(parent.ID, EdgeConditionSuccess, "agent-1", nil); err != nil {
+		t.Fatalf("AdvanceWorkflow() error = %v", err)
+	}
+	childA := db.beadExecutions["bead-child-1"]
+	childB := db.beadExecutions["bead-child-2"]
+
+	if err := engine.AdvanceWorkflow(childA.ID, EdgeConditionSuccess, "agent-2", nil); err != nil {
+		t.Fatalf("AdvanceWorkflow(childA) error = %v", err)
+	}
+	// Branch b has MaxAttempts 1, so a failure escalates it
+	if err := engine.FailNode(childB.ID, "agent-3", "tests failed"); err != nil {
+		t.Fatalf("FailNode(childB) error = %v", err)
+	}
+	if childB.Status != ExecutionStatusEscalated {
+		t.Fatalf("childB status = %s, want escalated", childB.Status)
+	}
+	if parent.CurrentNodeKey != "fix" {
+		t.Fatalf("parent at %q, want fix", parent.CurrentNodeKey)
+	}
+
+	// Looping back into the parallel node counts as a cycle and fans out again
+	if err := engine.AdvanceWorkflow(parent.ID, EdgeConditionSuccess, "agent-1", nil); err != nil {
+		t.Fatalf("AdvanceWorkflow() error = %v", err)
+	}
+	if parent.CycleCount != 1 {
+		t.Errorf("CycleCount = %d, want 1", parent.CycleCount)
+	}
+	if len(beads.created) != 4 {
+		t.Errorf("created %d beads, want 4", len(beads.created))
+	}
+	if child := db.beadExecutions["bead-child-3"]; child == nil || child.CycleCount != 1 {
+		t.Errorf("second fan-out branch should inherit CycleCount 1, got %+v", child)
+	}
+
+	// A late result from the first fan-out is ignored
+	if err := engine.recordChildResult(childA, EdgeConditionSuccess); err != nil {
+		t.Fatalf("recordChildResult() error = %v", err)
+	}
+	if pendingChildren(parent) != 2 {
+		t.Errorf("pending branches = %d, want 2", pendingChildren(parent))
+	}
+}
+
+func TestAdvanceWorkflow_ParallelMaxFanOut(t *testing.T) {
+	engine, db, beads := newParallelEngine(t)
+	engine.SetMaxParallelFanOut(1)
+	parent := db.beadExecutions["bead-1"]
+
+	if err := engine.AdvanceWorkflow(parent.ID, EdgeConditionSuccess, "agent-1", nil); err != nil {
+		t.Fatalf("AdvanceWorkflow() error = %v", err)
+	}
+	if parent.Status != ExecutionStatusEscalated {
+		t.Errorf("status = %s, want escalated", parent.Status)
+	}
+	if len(beads.created) != 0 {
+		t.Errorf("created %d beads, want none", len(beads.created))
+	}
+}
+
+func TestAdvanceWorkflow_ParallelRequiresBeadCreator(t *testing.T) {
+	db := newMockDatabase()
+	db.workflows["wf-par"] = newParallelWorkflow()
+	engine := NewEngine(db, newMockBeadManager())
+	exec, err := engine.StartWorkflow("bead-1", "wf-par", "proj-1")
+	if err != nil {
+		t.Fatalf("StartWorkflow() error = %v", err)
+	}
+
+	if err := engine.AdvanceWorkflow(exec.ID, EdgeConditionSuccess, "agent-1", nil); err == nil {
+		t.Error("expected an error without a bead creator")
+	}
+}
@@ -30,6 +30,7 @@ type WorkflowNodeDefinition struct {
 	PersonaHint    string            `yaml:"persona_hint"`
 	MaxAttempts    int               `yaml:"max_attempts"`
 	TimeoutMinutes int               `yaml:"timeout_minutes"`
+	TimeoutSeconds int               `yaml:"timeout_seconds"`
 	Instructions   string            `yaml:"instructions"`
 	Metadata       map[string]string `yaml:"metadata,omitempty"`
 }
@@ -109,6 +110,7 @@ func convertDefinitionToWorkflow(def *WorkflowDefinition) *Workflow {
 			PersonaHint:    nodeDef.PersonaHint,
 			MaxAttempts:    nodeDef.MaxAttempts,
 			TimeoutMinutes: nodeDef.TimeoutMinutes,
+			TimeoutSeconds: nodeDef.TimeoutSeconds,
 			Instructions:   nodeDef.Instructions,
 			Metadata:       nodeDef.Metadata,
 			CreatedAt:      now,
@@ -143,7 +145,19 @@ func InstallDefaultWorkflows(db Database, workflowsDir string) error {
 		return fmt.Errorf("failed to load default workflows: %w", err)
 	}
 
+	store, replaces := db.(DefinitionStore)
 	for _, wf := range workflows {
+		if replaces {
+			// Replace the nodes and edges as a whole; edges get new IDs on
+			// every load, so upserting them would add a copy each startup
+			if err := store.SaveWorkflowDefinition(wf); err != nil {
+				log.Printf("[Workflow] Warning: failed to save workflow %s: %v", wf.ID, err)
+				continue
+			}
+			log.Printf("[Workflow] Installed default workflow: %s", wf.Name)
+			continue
+		}
+
 		// Insert workflow
 		if err := db.UpsertWorkflow(wf); err != nil {
 			log.Printf("[Workflow] Warning: failed to upsert workflow %s: %v", wf.ID, err)
@@ -12,6 +12,7 @@ const (
 	NodeTypeApproval NodeType = "approval" // Requires approval to proceed
 	NodeTypeCommit   NodeType = "commit"   // Git commit/push operation
 	NodeTypeVerify   NodeType = "verify"   // Verification/testing node
+	NodeTypeParallel NodeType = "parallel" // Fans out to child beads and joins on their results
 )
 
 // EdgeCondition represents conditions for workflow transitions
@@ -24,6 +25,7 @@ const (
 	EdgeConditionRejected  EdgeCondition = "rejected"  // Approval rejected
 	EdgeConditionTimeout   EdgeCondition = "timeout"   // Node timed out
 	EdgeConditionEscalated EdgeCondition = "escalated" // Escalated to higher authority
+	EdgeConditionBranch    EdgeCondition = "branch"    // Fan-out branch of a parallel node
 )
 
 // ExecutionStatus represents the status of a workflow execution
@@ -61,6 +63,7 @@ type WorkflowNode struct {
 	PersonaHint    string            `json:"persona_hint"`    // Persona path hint for dispatcher
 	MaxAttempts    int               `json:"max_attempts"`    // Max attempts before escalation (0 = unlimited)
 	TimeoutMinutes int               `json:"timeout_minutes"` // Timeout in minutes (0 = no timeout)
+	TimeoutSeconds int               `json:"timeout_seconds"` // Timeout in seconds; overrides TimeoutMinutes when set
 	Instructions   string            `json:"instructions"`    // Instructions for the agent
 	Metadata       map[string]string `json:"metadata"`        // Additional node-specific metadata
 	CreatedAt      time.Time         `json:"created_at"`
@@ -91,6 +94,16 @@ type WorkflowExecution struct {
 	CompletedAt      *time.Time      `json:"completed_at,omitempty"`
 	EscalatedAt      *time.Time      `json:"escalated_at,omitempty"`
 	LastNodeAt       time.Time       `json:"last_node_at"` // Last time node was updated
+
+	// NodeDispatchedAt is when work on the current node was first handed to
+	// an agent; node timeouts count from here, not from time in the queue.
+	NodeDispatchedAt *time.Time `json:"node_dispatched_at,omitempty"`
+
+	// Parallel fan-out: a child execution points at the parent that spawned
+	// it, and a parent waiting at a parallel node tracks each child bead's
+	// result (empty until the child finishes).
+	ParentExecutionID string                   `json:"parent_execution_id,omitempty"`
+	ParallelChildren  map[string]EdgeCondition `json:"parallel_children,omitempty"`
 }
 
 // WorkflowExecutionHistory represents an audit trail of workflow state changes
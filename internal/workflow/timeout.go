@@ -0,0 +1,207 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// DefaultTimeoutSweepInterval is how often StartTimeoutSweeper checks
+// running executions when given no interval
+const DefaultTimeoutSweepInterval = 30 * time.Second
+
+// ActiveExecutionLister is implemented by databases that can list running
+// executions, which the timeout sweeper needs.
+type ActiveExecutionLister interface {
+	ListActiveWorkflowExecutions() ([]*WorkflowExecution, error)
+}
+
+// NodeTimeout describes a node timeout the engine has acted on.
+type NodeTimeout struct {
+	Execution *WorkflowExecution // The execution after the timeout was handled
+	NodeKey   string
+	Timeout   time.Duration
+	Elapsed   time.Duration
+	Escalated bool // The execution was escalated rather than advanced
+}
+
+// Timeout returns how long an execution may stay on the node, or 0 for no
+// limit. TimeoutSeconds takes precedence over TimeoutMinutes.
+func (n *WorkflowNode) Timeout() time.Duration {
+	if n.TimeoutSeconds > 0 {
+		return time.Duration(n.TimeoutSeconds) * time.Second
+	}
+	if n.TimeoutMinutes > 0 {
+		return time.Duration(n.TimeoutMinutes) * time.Minute
+	}
+	return 0
+}
+
+// MarkNodeDispatched records that work on the execution's current node has
+// been handed to an agent, starting the node's timeout clock if it is not
+// already running. It returns the execution so the caller can remember which
+// node the work is for.
+func (e *Engine) MarkNodeDispatched(executionID string) (*WorkflowExecution, error) {
+	e.timeoutMu.Lock()
+	defer e.timeoutMu.Unlock()
+
+	exec, err := e.db.GetWorkflowExecution(executionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get execution: %w", err)
+	}
+	if exec == nil || exec.Status != ExecutionStatusActive || exec.CurrentNodeKey == "" || exec.NodeDispatchedAt != nil {
+		return exec, nil
+	}
+	now := time.Now()
+	exec.NodeDispatchedAt = &now
+	if err := e.db.UpsertWorkflowExecution(exec); err != nil {
+		return nil, fmt.Errorf("failed to record node dispatch: %w", err)
+	}
+	return exec, nil
+}
+
+// SetTimeoutHandler sets a function called after each node timeout is
+// handled, e.g. to publish an event or file an escalation bead.
+func (e *Engine) SetTimeoutHandler(fn func(NodeTimeout)) {
+	e.timeoutMu.Lock()
+	defer e.timeoutMu.Unlock()
+	e.onTimeout = fn
+}
+
+// StartTimeoutSweeper checks running executions for timed-out nodes every
+// interval until ctx is done. Values <= 0 use DefaultTimeoutSweepInterval.
+func (e *Engine) StartTimeoutSweeper(ctx context.Context, interval time.Duration) {
+	if _, ok := e.db.(ActiveExecutionLister); !ok {
+		log.Printf("[Workflow] Database cannot list active executions; node timeouts are only checked on dispatch")
+		return
+	}
+	if interval <= 0 {
+		interval = DefaultTimeoutSweepInterval
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := e.SweepTimeouts(); err != nil {
+					log.Printf("[Workflow] Timeout sweep failed: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// SweepTimeouts handles every running execution whose node is past its
+// timeout and returns how many timed out.
+func (e *Engine) SweepTimeouts() (int, error) {
+	lister, ok := e.db.(ActiveExecutionLister)
+	if !ok {
+		return 0, fmt.Errorf("workflow database cannot list active executions")
+	}
+	executions, err := lister.ListActiveWorkflowExecutions()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list active executions: %w", err)
+	}
+
+	timedOut := 0
+	for _, exec := range executions {
+		fired, err := e.handleNodeTimeout(exec.ID)
+		if err != nil {
+			log.Printf("[Workflow] Failed to handle timeout for bead %s: %v", exec.BeadID, err)
+		}
+		if fired {
+			timedOut++
+		}
+	}
+	return timedOut, nil
+}
+
+// handleNodeTimeout advances an execution whose node is past its timeout
+// along the node's timeout edge. Without one, a node that opted in with
+// timeout_seconds escalates the execution, while a legacy timeout_minutes
+// limit is left alone. The clock starts when the node is first dispatched. It
+// reports whether a timeout was handled; one that fails is retried by the
+// next check.
+func (e *Engine) handleNodeTimeout(executionID string) (bool, error) {
+	e.timeoutMu.Lock()
+	event, err := e.fireNodeTimeout(executionID)
+	onTimeout := e.onTimeout
+	e.timeoutMu.Unlock()
+
+	if event == nil {
+		return false, err
+	}
+	if onTimeout != nil {
+		onTimeout(*event)
+	}
+	return true, nil
+}
+
+// fireNodeTimeout does the work of handleNodeTimeout. The caller holds
+// timeoutMu, and the execution is re-read under it so a timeout seen by both
+// the sweeper and the dispatcher fires once.
+func (e *Engine) fireNodeTimeout(executionID string) (*NodeTimeout, error) {
+	exec, err := e.db.GetWorkflowExecution(executionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get execution: %w", err)
+	}
+	if exec == nil || exec.Status != ExecutionStatusActive || exec.CurrentNodeKey == "" {
+		return nil, nil
+	}
+	wf, err := e.db.GetWorkflow(exec.WorkflowID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workflow: %w", err)
+	}
+	node := findNode(wf, exec.CurrentNodeKey)
+	if node == nil || node.Timeout() == 0 || exec.NodeDispatchedAt == nil {
+		return nil, nil
+	}
+	timeoutEdge := hasEdge(wf, node.NodeKey, EdgeConditionTimeout)
+	if !timeoutEdge && node.TimeoutSeconds <= 0 {
+		return nil, nil
+	}
+	elapsed := time.Since(*exec.NodeDispatchedAt)
+	if elapsed <= node.Timeout() {
+		return nil, nil
+	}
+
+	log.Printf("[Workflow] Node %s timed out for bead %s (elapsed: %v, timeout: %v)",
+		node.NodeKey, exec.BeadID, elapsed.Round(time.Second), node.Timeout())
+	event := &NodeTimeout{Execution: exec, NodeKey: node.NodeKey, Timeout: node.Timeout(), Elapsed: elapsed}
+
+	if timeoutEdge {
+		resultData := map[string]string{
+			"timeout_reason": fmt.Sprintf("Node exceeded timeout of %v", node.Timeout()),
+			"elapsed_time":   elapsed.String(),
+		}
+		if err := e.AdvanceWorkflow(exec.ID, EdgeConditionTimeout, "system", resultData); err != nil {
+			return nil, fmt.Errorf("node timed out but failed to advance workflow: %w", err)
+		}
+	} else {
+		reason := fmt.Sprintf("Node %s exceeded its timeout of %v", node.NodeKey, node.Timeout())
+		if err := e.escalateWorkflow(exec, reason); err != nil {
+			return nil, err
+		}
+	}
+
+	if updated, getErr := e.db.GetWorkflowExecution(exec.ID); getErr == nil && updated != nil {
+		event.Execution = updated
+	}
+	// Advancing may escalate too, e.g. when the timeout edge closes a cycle
+	event.Escalated = event.Execution.Status == ExecutionStatusEscalated
+	return event, nil
+}
+
+// hasEdge reports whether the workflow has an edge leaving nodeKey on condition
+func hasEdge(wf *Workflow, nodeKey string, condition EdgeCondition) bool {
+	for _, edge := range wf.Edges {
+		if edge.FromNodeKey == nodeKey && edge.Condition == condition {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,187 @@
+package workflow
+
+import (
+	"testing"
+	"time"
+)
+
+// newTimeoutEngine returns an engine with one active execution whose "work"
+// node was dispatched an hour ago. withTimeoutEdge adds a timeout edge from
+// "work" to "retry".
+func newTimeoutEngine(t *testing.T, timeoutSeconds int, withTimeoutEdge bool) (*Engine, *mockDatabase) {
+	t.Helper()
+	db := newMockDatabase()
+	wf := &Workflow{
+		ID: "wf-1",
+		Nodes: []WorkflowNode{
+			{NodeKey: "work", NodeType: NodeTypeTask, TimeoutSeconds: timeoutSeconds},
+			{NodeKey: "retry", NodeType: NodeTypeTask},
+		},
+		Edges: []WorkflowEdge{
+			{FromNodeKey: "", ToNodeKey: "work", Condition: EdgeConditionSuccess},
+			{FromNodeKey: "work", ToNodeKey: "", Condition: EdgeConditionSuccess},
+			{FromNodeKey: "retry", ToNodeKey: "", Condition: EdgeConditionSuccess},
+		},
+	}
+	if withTimeoutEdge {
+		wf.Edges = append(wf.Edges, WorkflowEdge{FromNodeKey: "work", ToNodeKey: "retry", Condition: EdgeConditionTimeout})
+	}
+	db.workflows[wf.ID] = wf
+	exec := &WorkflowExecution{
+		ID:             "exec-1",
+		WorkflowID:     wf.ID,
+		BeadID:         "bead-1",
+		CurrentNodeKey: "work",
+		Status:         ExecutionStatusActive,
+		LastNodeAt:     time.Now().Add(-2 * time.Hour),
+	}
+	dispatchedAt := time.Now().Add(-time.Hour)
+	exec.NodeDispatchedAt = &dispatchedAt
+	db.executions[exec.ID] = exec
+	db.beadExecutions[exec.BeadID] = exec
+	return NewEngine(db, newMockBeadManager()), db
+}
+
+func TestSweepTimeouts_AdvancesAlongTimeoutEdge(t *testing.T) {
+	engine, db := newTimeoutEngine(t, 60, true)
+	var fired []NodeTimeout
+	engine.SetTimeoutHandler(func(nt NodeTimeout) { fired = append(fired, nt) })
+
+	n, err := engine.SweepTimeouts()
+	if err != nil {
+		t.Fatalf("SweepTimeouts() error = %v", err)
+	}
+	if n != 1 || len(fired) != 1 {
+		t.Fatalf("SweepTimeouts() = %d with %d events, want 1 and 1", n, len(fired))
+	}
+	exec := db.executions["exec-1"]
+	if exec.CurrentNodeKey != "retry" || exec.Status != ExecutionStatusActive {
+		t.Errorf("execution at %s (%s), want retry (active)", exec.CurrentNodeKey, exec.Status)
+	}
+	if fired[0].NodeKey != "work" || fired[0].Escalated || fired[0].Timeout != time.Minute {
+		t.Errorf("event = %+v, want work timed out after 1m without escalation", fired[0])
+	}
+
+	// The execution is now on a node without a timeout
+	if n, _ := engine.SweepTimeouts(); n != 0 || len(fired) != 1 {
+		t.Errorf("second SweepTimeouts() = %d with %d events, want 0 and 1", n, len(fired))
+	}
+}
+
+func TestSweepTimeouts_EscalatesWithoutTimeoutEdge(t *testing.T) {
+	engine, db := newTimeoutEngine(t, 60, false)
+	var fired []NodeTimeout
+	engine.SetTimeoutHandler(func(nt NodeTimeout) { fired = append(fired, nt) })
+
+	if n, err := engine.SweepTimeouts(); err != nil || n != 1 {
+		t.Fatalf("SweepTimeouts() = %d, %v, want 1", n, err)
+	}
+	if exec := db.executions["exec-1"]; exec.Status != ExecutionStatusEscalated || exec.EscalatedAt == nil {
+		t.Errorf("execution status = %s, want escalated", exec.Status)
+	}
+	if len(fired) != 1 || !fired[0].Escalated {
+		t.Fatalf("events = %+v, want one escalation", fired)
+	}
+
+	// Escalated executions are not running, so the timeout fires once
+	if n, _ := engine.SweepTimeouts(); n != 0 || len(fired) != 1 {
+		t.Errorf("second SweepTimeouts() = %d with %d events, want 0 and 1", n, len(fired))
+	}
+}
+
+func TestSweepTimeouts_NotTimedOut(t *testing.T) {
+	for _, timeout := range []int{0, 2 * 60 * 60} {
+		engine, db := newTimeoutEngine(t, timeout, true)
+		engine.SetTimeoutHandler(func(nt NodeTimeout) { t.Errorf("unexpected timeout %+v", nt) })
+
+		if n, err := engine.SweepTimeouts(); err != nil || n != 0 {
+			t.Errorf("SweepTimeouts() with timeout %ds = %d, %v, want 0", timeout, n, err)
+		}
+		if exec := db.executions["exec-1"]; exec.CurrentNodeKey != "work" || exec.Status != ExecutionStatusActive {
+			t.Errorf("execution moved to %s (%s)", exec.CurrentNodeKey, exec.Status)
+		}
+	}
+}
+
+func TestSweepTimeouts_ClockStartsAtDispatch(t *testing.T) {
+	engine, db := newTimeoutEngine(t, 60, false)
+	engine.SetTimeoutHandler(func(nt NodeTimeout) { t.Errorf("unexpected timeout %+v", nt) })
+
+	// Time spent queued before any agent picked the node up does not count
+	db.executions["exec-1"].NodeDispatchedAt = nil
+	if n, err := engine.SweepTimeouts(); err != nil || n != 0 {
+		t.Fatalf("SweepTimeouts() before dispatch = %d, %v, want 0", n, err)
+	}
+
+	exec, err := engine.MarkNodeDispatched("exec-1")
+	if err != nil || exec == nil || exec.NodeDispatchedAt == nil {
+		t.Fatalf("MarkNodeDispatched() = %+v, %v, want the dispatch time set", exec, err)
+	}
+	first := *exec.NodeDispatchedAt
+	if n, _ := engine.SweepTimeouts(); n != 0 {
+		t.Errorf("SweepTimeouts() just after dispatch = %d, want 0", n)
+	}
+
+	// A redispatch of the same node keeps the original start
+	if exec, _ := engine.MarkNodeDispatched("exec-1"); !exec.NodeDispatchedAt.Equal(first) {
+		t.Errorf("NodeDispatchedAt moved from %v to %v on redispatch", first, *exec.NodeDispatchedAt)
+	}
+
+	// Moving to another node stops the clock until that node is dispatched
+	if err := engine.AdvanceWorkflow("exec-1", EdgeConditionSuccess, "agent-1", nil); err != nil {
+		t.Fatalf("AdvanceWorkflow() error = %v", err)
+	}
+	if exec := db.executions["exec-1"]; exec.NodeDispatchedAt != nil {
+		t.Errorf("NodeDispatchedAt = %v after leaving the node, want nil", *exec.NodeDispatchedAt)
+	}
+}
+
+func TestSweepTimeouts_LegacyTimeoutMinutes(t *testing.T) {
+	// timeout_minutes without a timeout edge is not escalated by the sweeper
+	engine, db := newTimeoutEngine(t, 0, false)
+	db.workflows["wf-1"].Nodes[0].TimeoutMinutes = 1
+	engine.SetTimeoutHandler(func(nt NodeTimeout) { t.Errorf("unexpected timeout %+v", nt) })
+	if n, err := engine.SweepTimeouts(); err != nil || n != 0 {
+		t.Errorf("SweepTimeouts() = %d, %v, want 0", n, err)
+	}
+	if exec := db.executions["exec-1"]; exec.Status != ExecutionStatusActive {
+		t.Errorf("execution status = %s, want active", exec.Status)
+	}
+
+	// but still follows a timeout edge the workflow defines
+	engine, db = newTimeoutEngine(t, 0, true)
+	db.workflows["wf-1"].Nodes[0].TimeoutMinutes = 1
+	if n, err := engine.SweepTimeouts(); err != nil || n != 1 {
+		t.Fatalf("SweepTimeouts() with timeout edge = %d, %v, want 1", n, err)
+	}
+	if exec := db.executions["exec-1"]; exec.CurrentNodeKey != "retry" {
+		t.Errorf("execution at %s, want retry", exec.CurrentNodeKey)
+	}
+}
+
+func TestCheckNodeTimeout(t *testing.T) {
+	engine, db := newTimeoutEngine(t, 60, false)
+	if err := engine.CheckNodeTimeout(db.executions["exec-1"]); err == nil {
+		t.Error("CheckNodeTimeout() = nil, want a timeout error")
+	}
+	if db.executions["exec-1"].Status != ExecutionStatusEscalated {
+		t.Errorf("execution status = %s, want escalated", db.executions["exec-1"].Status)
+	}
+}
+
+func TestWorkflowNodeTimeout(t *testing.T) {
+	tests := []struct {
+		node WorkflowNode
+		want time.Duration
+	}{
+		{WorkflowNode{}, 0},
+		{WorkflowNode{TimeoutMinutes: 5}, 5 * time.Minute},
+		{WorkflowNode{TimeoutSeconds: 90}, 90 * time.Second},
+		{WorkflowNode{TimeoutSeconds: 90, TimeoutMinutes: 5}, 90 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := tt.node.Timeout(); got != tt.want {
+			t.Errorf("%+v.Timeout() = %v, want %v", tt.node, got, tt.want)
+		}
+	}
+}
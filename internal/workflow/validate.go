@@ -0,0 +1,116 @@
+package workflow
+
+import (
+	"fmt"
+)
+
+// knownNodeTypes and knownConditions are the node types and edge conditions
+// the engine acts on
+var (
+	knownNodeTypes = map[NodeType]bool{
+		NodeTypeTask: true, NodeTypeApproval: true, NodeTypeCommit: true, NodeTypeVerify: true, NodeTypeParallel: true,
+	}
+	knownConditions = map[EdgeCondition]bool{
+		EdgeConditionSuccess: true, EdgeConditionFailure: true, EdgeConditionApproved: true, EdgeConditionRejected: true,
+		EdgeConditionTimeout: true, EdgeConditionEscalated: true, EdgeConditionBranch: true,
+	}
+)
+
+// ValidateWorkflow checks that a workflow definition is a graph the engine
+// can run: it has a start edge, every edge names nodes that exist, and every
+// node reachable from the start can still reach the workflow end.
+func ValidateWorkflow(wf *Workflow) error {
+	if wf == nil {
+		return fmt.Errorf("workflow cannot be nil")
+	}
+	if wf.Name == "" {
+		return fmt.Errorf("workflow name is required")
+	}
+	if wf.WorkflowType == "" {
+		return fmt.Errorf("workflow_type is required")
+	}
+	if len(wf.Nodes) == 0 {
+		return fmt.Errorf("workflow has no nodes")
+	}
+
+	nodes := make(map[string]bool, len(wf.Nodes))
+	for _, node := range wf.Nodes {
+		if node.NodeKey == "" {
+			return fmt.Errorf("node_key is required on every node")
+		}
+		if nodes[node.NodeKey] {
+			return fmt.Errorf("duplicate node_key %q", node.NodeKey)
+		}
+		if !knownNodeTypes[node.NodeType] {
+			return fmt.Errorf("node %q has unknown node_type %q", node.NodeKey, node.NodeType)
+		}
+		if node.TimeoutSeconds < 0 || node.TimeoutMinutes < 0 {
+			return fmt.Errorf("node %q has a negative timeout", node.NodeKey)
+		}
+		nodes[node.NodeKey] = true
+	}
+
+	// Adjacency by node key; "" is both the start and the end
+	next := make(map[string][]string)
+	hasStart := false
+	for i, edge := range wf.Edges {
+		if !knownConditions[edge.Condition] {
+			return fmt.Errorf("edge %d has unknown condition %q", i, edge.Condition)
+		}
+		if edge.FromNodeKey != "" && !nodes[edge.FromNodeKey] {
+			return fmt.Errorf("edge %d starts at unknown node %q", i, edge.FromNodeKey)
+		}
+		if edge.ToNodeKey != "" && !nodes[edge.ToNodeKey] {
+			return fmt.Errorf("edge %d points to unknown node %q", i, edge.ToNodeKey)
+		}
+		if edge.FromNodeKey == "" {
+			if edge.ToNodeKey == "" {
+				return fmt.Errorf("edge %d goes from the start straight to the end", i)
+			}
+			hasStart = true
+		}
+		next[edge.FromNodeKey] = append(next[edge.FromNodeKey], edge.ToNodeKey)
+	}
+	if !hasStart {
+		return fmt.Errorf("workflow has no start edge (an edge with an empty from_node_key)")
+	}
+
+	// Walk forward from the start, then backward from the end; a node seen
+	// on the way out but not on the way back can never finish
+	reached := walk("", next)
+	prev := make(map[string][]string)
+	for from, tos := range next {
+		for _, to := range tos {
+			prev[to] = append(prev[to], from)
+		}
+	}
+	finishes := walk("", prev)
+	if !finishes[""] {
+		return fmt.Errorf("no terminal node (one with an edge to the workflow end) is reachable from the start")
+	}
+	for _, node := range wf.Nodes {
+		if reached[node.NodeKey] && !finishes[node.NodeKey] {
+			return fmt.Errorf("node %q cannot reach the workflow end", node.NodeKey)
+		}
+	}
+	return nil
+}
+
+// walk returns the node keys reachable from key by following edges, not
+// counting key itself unless a cycle leads back to it
+func walk(key string, edges map[string][]string) map[string]bool {
+	seen := make(map[string]bool)
+	queue := append([]string(nil), edges[key]...)
+	for len(queue) > 0 {
+		k := queue[0]
+		queue = queue[1:]
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		if k != "" {
+			queue = append(queue, edges[k]...)
+		}
+	}
+	return seen
+}
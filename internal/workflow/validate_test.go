@@ -0,0 +1,93 @@
+package workflow
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateWorkflow_Defaults(t *testing.T) {
+	workflows, err := LoadDefaultWorkflows("../../workflows/defaults")
+	if err != nil {
+		t.Fatalf("LoadDefaultWorkflows() error = %v", err)
+	}
+	if len(workflows) == 0 {
+		t.Fatal("no default workflows loaded")
+	}
+	for _, wf := range workflows {
+		if err := ValidateWorkflow(wf); err != nil {
+			t.Errorf("ValidateWorkflow(%s) error = %v", wf.ID, err)
+		}
+	}
+	if err := ValidateWorkflow(newParallelWorkflow()); err != nil {
+		t.Errorf("ValidateWorkflow(parallel) error = %v", err)
+	}
+}
+
+func TestValidateWorkflow_Rejects(t *testing.T) {
+	node := func(key string) WorkflowNode {
+		return WorkflowNode{NodeKey: key, NodeType: NodeTypeTask}
+	}
+	edge := func(from, to string) WorkflowEdge {
+		return WorkflowEdge{FromNodeKey: from, ToNodeKey: to, Condition: EdgeConditionSuccess}
+	}
+	tests := []struct {
+		name    string
+		change  func(wf *Workflow)
+		wantErr string
+	}{
+		{"missing name", func(wf *Workflow) { wf.Name = "" }, "name is required"},
+		{"missing type", func(wf *Workflow) { wf.WorkflowType = "" }, "workflow_type is required"},
+		{"no nodes", func(wf *Workflow) { wf.Nodes = nil }, "no nodes"},
+		{"duplicate key", func(wf *Workflow) { wf.Nodes = append(wf.Nodes, node("work")) }, `duplicate node_key "work"`},
+		{"unknown node type", func(wf *Workflow) { wf.Nodes[0].NodeType = "deploy" }, `unknown node_type "deploy"`},
+		{"negative timeout", func(wf *Workflow) { wf.Nodes[0].TimeoutSeconds = -1 }, `node "work" has a negative timeout`},
+		{"unknown condition", func(wf *Workflow) { wf.Edges[1].Condition = "maybe" }, `unknown condition "maybe"`},
+		{"no start edge", func(wf *Workflow) { wf.Edges = wf.Edges[1:] }, "no start edge"},
+		{"dangling target", func(wf *Workflow) { wf.Edges = append(wf.Edges, edge("work", "deploy")) }, `points to unknown node "deploy"`},
+		{"dangling source", func(wf *Workflow) { wf.Edges = append(wf.Edges, edge("deploy", "work")) }, `starts at unknown node "deploy"`},
+		{"no terminal", func(wf *Workflow) { wf.Edges[2] = edge("review", "work") }, "no terminal node"},
+		{"dead end", func(wf *Workflow) {
+			wf.Nodes = append(wf.Nodes, node("stuck"))
+			wf.Edges = append(wf.Edges, WorkflowEdge{FromNodeKey: "work", ToNodeKey: "stuck", Condition: EdgeConditionFailure})
+		}, `node "stuck" cannot reach the workflow end`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wf := &Workflow{
+				Name:         "Review",
+				WorkflowType: "custom",
+				Nodes:        []WorkflowNode{node("work"), node("review")},
+				Edges:        []WorkflowEdge{edge("", "work"), edge("work", "review"), edge("review", "")},
+			}
+			if err := ValidateWorkflow(wf); err != nil {
+				t.Fatalf("base workflow is invalid: %v", err)
+			}
+			tt.change(wf)
+			err := ValidateWorkflow(wf)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateWorkflow() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateWorkflow_AllowsUnreachableAndCycles(t *testing.T) {
+	wf := &Workflow{
+		Name:         "Loop",
+		WorkflowType: "custom",
+		Nodes: []WorkflowNode{
+			{NodeKey: "work", NodeType: NodeTypeTask},
+			{NodeKey: "approve", NodeType: NodeTypeApproval},
+			{NodeKey: "spare", NodeType: NodeTypeTask}, // Unreachable, so it cannot trap an execution
+		},
+		Edges: []WorkflowEdge{
+			{FromNodeKey: "", ToNodeKey: "work", Condition: EdgeConditionSuccess},
+			{FromNodeKey: "work", ToNodeKey: "approve", Condition: EdgeConditionSuccess},
+			{FromNodeKey: "approve", ToNodeKey: "work", Condition: EdgeConditionRejected},
+			{FromNodeKey: "approve", ToNodeKey: "", Condition: EdgeConditionApproved},
+		},
+	}
+	if err := ValidateWorkflow(wf); err != nil {
+		t.Errorf("ValidateWorkflow() error = %v", err)
+	}
+}
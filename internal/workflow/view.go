@@ -0,0 +1,122 @@
+package workflow
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// NodeStatus is where a node stands in one workflow execution
+type NodeStatus string
+
+const (
+	NodeStatusPending NodeStatus = "pending" // Not reached yet
+	NodeStatusActive  NodeStatus = "active"  // The execution is on this node
+	NodeStatusDone    NodeStatus = "done"    // Last left with success or approval
+	NodeStatusFailed  NodeStatus = "failed"  // Last left with a failure, or the execution stopped here
+)
+
+// branchResultPrefix marks the history entries recordChildResult writes for
+// each finished parallel branch; the execution stays on the parallel node
+const branchResultPrefix = "child_execution="
+
+// ExecutionView is a workflow execution merged with its workflow's graph,
+// enough to draw the graph with the execution's position on it.
+type ExecutionView struct {
+	Execution   *WorkflowExecution    `json:"execution"`
+	Workflow    *Workflow             `json:"workflow"`
+	CurrentNode *WorkflowNode         `json:"current_node,omitempty"`
+	Path        []ExecutionStep       `json:"path"`
+	CycleCount  int                   `json:"cycle_count"`
+	NodeStatus  map[string]NodeStatus `json:"node_status"`
+}
+
+// ExecutionStep is one visit to a node on an execution's path
+type ExecutionStep struct {
+	NodeKey       string        `json:"node_key"`
+	Condition     EdgeCondition `json:"condition,omitempty"` // How the node was left; empty while the execution is on it
+	AgentID       string        `json:"agent_id,omitempty"`
+	AttemptNumber int           `json:"attempt_number"`
+	At            time.Time     `json:"at"`
+}
+
+// GetExecutionView returns an execution with its workflow graph, the path it
+// has taken so far and the status of every node.
+func (e *Engine) GetExecutionView(executionID string) (*ExecutionView, error) {
+	exec, err := e.db.GetWorkflowExecution(executionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get execution: %w", err)
+	}
+	if exec == nil {
+		return nil, fmt.Errorf("workflow execution not found: %s", executionID)
+	}
+	wf, err := e.db.GetWorkflow(exec.WorkflowID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workflow: %w", err)
+	}
+	history, err := e.db.ListWorkflowHistory(executionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get execution history: %w", err)
+	}
+
+	view := &ExecutionView{
+		Execution:   exec,
+		Workflow:    wf,
+		CurrentNode: findNode(wf, exec.CurrentNodeKey),
+		Path:        executionPath(exec, history),
+		CycleCount:  exec.CycleCount,
+		NodeStatus:  make(map[string]NodeStatus, len(wf.Nodes)),
+	}
+	for _, node := range wf.Nodes {
+		view.NodeStatus[node.NodeKey] = NodeStatusPending
+	}
+	for _, step := range view.Path {
+		if _, ok := view.NodeStatus[step.NodeKey]; !ok {
+			continue // Node removed from the workflow since it ran
+		}
+		switch step.Condition {
+		case "":
+			if exec.Status == ExecutionStatusActive || exec.Status == ExecutionStatusBlocked {
+				view.NodeStatus[step.NodeKey] = NodeStatusActive
+			} else {
+				view.NodeStatus[step.NodeKey] = NodeStatusFailed
+			}
+		case EdgeConditionSuccess, EdgeConditionApproved:
+			view.NodeStatus[step.NodeKey] = NodeStatusDone
+		default:
+			view.NodeStatus[step.NodeKey] = NodeStatusFailed
+		}
+	}
+	return view, nil
+}
+
+// executionPath turns an execution's history into the nodes it visited in
+// order. History records each node as the execution leaves it, so the node
+// it is still on, or stopped on when escalated, is added at the end.
+func executionPath(exec *WorkflowExecution, history []*WorkflowExecutionHistory) []ExecutionStep {
+	path := []ExecutionStep{}
+	for _, h := range history {
+		if h.NodeKey == "" || strings.HasPrefix(h.ResultData, branchResultPrefix) {
+			continue // Workflow start, or a branch finishing while the node waits
+		}
+		path = append(path, ExecutionStep{
+			NodeKey:       h.NodeKey,
+			Condition:     h.Condition,
+			AgentID:       h.AgentID,
+			AttemptNumber: h.AttemptNumber,
+			At:            h.CreatedAt,
+		})
+	}
+	if exec.CurrentNodeKey == "" || exec.Status == ExecutionStatusCompleted {
+		return path
+	}
+	running := exec.Status == ExecutionStatusActive || exec.Status == ExecutionStatusBlocked
+	if running || len(path) == 0 || path[len(path)-1].NodeKey != exec.CurrentNodeKey {
+		path = append(path, ExecutionStep{
+			NodeKey:       exec.CurrentNodeKey,
+			AttemptNumber: exec.NodeAttemptCount,
+			At:            exec.LastNodeAt,
+		})
+	}
+	return path
+}
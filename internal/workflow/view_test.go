@@ -0,0 +1,140 @@
+package workflow
+
+import (
+	"reflect"
+	"testing"
+)
+
+// pathKeys returns the node keys and conditions of a path as "key:condition"
+func pathKeys(path []ExecutionStep) []string {
+	keys := make([]string, len(path))
+	for i, step := range path {
+		keys[i] = step.NodeKey + ":" + string(step.Condition)
+	}
+	return keys
+}
+
+func TestGetExecutionView_Parallel(t *testing.T) {
+	engine, db, _ := newParallelEngine(t)
+	parent := db.beadExecutions["bead-1"]
+
+	if err := engine.AdvanceWorkflow(parent.ID, EdgeConditionSuccess, "agent-1", nil); err != nil {
+		t.Fatalf("AdvanceWorkflow() error = %v", err)
+	}
+	view, err := engine.GetExecutionView(parent.ID)
+	if err != nil {
+		t.Fatalf("GetExecutionView() error = %v", err)
+	}
+	if view.CurrentNode == nil || view.CurrentNode.NodeKey != "split" {
+		t.Fatalf("CurrentNode = %+v, want split", view.CurrentNode)
+	}
+	if got, want := pathKeys(view.Path), []string{"split:"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Path = %v, want %v", got, want)
+	}
+	want := map[string]NodeStatus{
+		"split": NodeStatusActive, "a": NodeStatusPending, "b": NodeStatusPending,
+		"done": NodeStatusPending, "fix": NodeStatusPending,
+	}
+	if !reflect.DeepEqual(view.NodeStatus, want) {
+		t.Errorf("NodeStatus = %v, want %v", view.NodeStatus, want)
+	}
+
+	// Branch results are recorded on the parallel node but are not steps
+	if err := engine.AdvanceWorkflow(db.beadExecutions["bead-child-1"].ID, EdgeConditionSuccess, "agent-2", nil); err != nil {
+		t.Fatalf("AdvanceWorkflow(childA) error = %v", err)
+	}
+	if err := engine.FailNode(db.beadExecutions["bead-child-2"].ID, "agent-3", "tests failed"); err != nil {
+		t.Fatalf("FailNode(childB) error = %v", err)
+	}
+	if err := engine.AdvanceWorkflow(parent.ID, EdgeConditionSuccess, "agent-1", nil); err != nil {
+		t.Fatalf("AdvanceWorkflow(fix) error = %v", err)
+	}
+
+	view, err = engine.GetExecutionView(parent.ID)
+	if err != nil {
+		t.Fatalf("GetExecutionView() error = %v", err)
+	}
+	if got, want := pathKeys(view.Path), []string{"split:failure", "fix:success", "split:"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Path = %v, want %v", got, want)
+	}
+	if view.CycleCount != 1 {
+		t.Errorf("CycleCount = %d, want 1", view.CycleCount)
+	}
+	if view.NodeStatus["split"] != NodeStatusActive || view.NodeStatus["fix"] != NodeStatusDone {
+		t.Errorf("NodeStatus = %v, want split active and fix done", view.NodeStatus)
+	}
+	if view.Path[1].AgentID != "agent-1" {
+		t.Errorf("fix step AgentID = %q, want agent-1", view.Path[1].AgentID)
+	}
+}
+
+func TestGetExecutionView_Finished(t *testing.T) {
+	history := func(node string, cond EdgeCondition) *WorkflowExecutionHistory {
+		return &WorkflowExecutionHistory{ExecutionID: "exec-1", NodeKey: node, Condition: cond}
+	}
+	tests := []struct {
+		name       string
+		status     ExecutionStatus
+		current    string
+		history    []*WorkflowExecutionHistory
+		wantPath   []string
+		wantStatus map[string]NodeStatus
+	}{
+		{
+			name:       "completed",
+			status:     ExecutionStatusCompleted,
+			current:    "review",
+			history:    []*WorkflowExecutionHistory{history("", EdgeConditionSuccess), history("work", EdgeConditionSuccess), history("review", EdgeConditionApproved)},
+			wantPath:   []string{"work:success", "review:approved"},
+			wantStatus: map[string]NodeStatus{"work": NodeStatusDone, "review": NodeStatusDone},
+		},
+		{
+			name:       "escalated",
+			status:     ExecutionStatusEscalated,
+			current:    "work",
+			history:    []*WorkflowExecutionHistory{history("", EdgeConditionSuccess)},
+			wantPath:   []string{"work:"},
+			wantStatus: map[string]NodeStatus{"work": NodeStatusFailed, "review": NodeStatusPending},
+		},
+		{
+			name:       "rejected and retried",
+			status:     ExecutionStatusActive,
+			current:    "review",
+			history:    []*WorkflowExecutionHistory{history("work", EdgeConditionSuccess), history("review", EdgeConditionRejected), history("work", EdgeConditionSuccess)},
+			wantPath:   []string{"work:success", "review:rejected", "work:success", "review:"},
+			wantStatus: map[string]NodeStatus{"work": NodeStatusDone, "review": NodeStatusActive},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newMockDatabase()
+			db.workflows["wf-1"] = &Workflow{
+				ID: "wf-1",
+				Nodes: []WorkflowNode{
+					{NodeKey: "work", NodeType: NodeTypeTask},
+					{NodeKey: "review", NodeType: NodeTypeApproval},
+				},
+			}
+			db.executions["exec-1"] = &WorkflowExecution{ID: "exec-1", WorkflowID: "wf-1", CurrentNodeKey: tt.current, Status: tt.status}
+			db.history["exec-1"] = tt.history
+
+			view, err := NewEngine(db, newMockBeadManager()).GetExecutionView("exec-1")
+			if err != nil {
+				t.Fatalf("GetExecutionView() error = %v", err)
+			}
+			if got := pathKeys(view.Path); !reflect.DeepEqual(got, tt.wantPath) {
+				t.Errorf("Path = %v, want %v", got, tt.wantPath)
+			}
+			if !reflect.DeepEqual(view.NodeStatus, tt.wantStatus) {
+				t.Errorf("NodeStatus = %v, want %v", view.NodeStatus, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestGetExecutionView_NotFound(t *testing.T) {
+	engine := NewEngine(newMockDatabase(), newMockBeadManager())
+	if _, err := engine.GetExecutionView("missing"); err == nil {
+		t.Error("expected an error for a missing execution")
+	}
+}
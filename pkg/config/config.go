@@ -15,13 +15,21 @@ const configFileName = ".loom.json"
 
 // Provider represents an AI service provider configuration (file/JSON config).
 type Provider struct {
-	ID       string `yaml:"id" json:"id"`
-	Name     string `yaml:"name" json:"name"`
-	Type     string `yaml:"type" json:"type"`
-	Endpoint string `yaml:"endpoint" json:"endpoint"`
-	APIKey   string `yaml:"api_key" json:"api_key"`
-	Model    string `yaml:"model" json:"model"`
-	Enabled  bool   `yaml:"enabled" json:"enabled"`
+	ID                string `yaml:"id" json:"id"`
+	Name              string `yaml:"name" json:"name"`
+	Type              string `yaml:"type" json:"type"`
+	Endpoint          string `yaml:"endpoint" json:"endpoint"`
+	APIKey            string `yaml:"api_key" json:"api_key"`
+	Model             string `yaml:"model" json:"model"`
+	DeploymentName    string `yaml:"deployment_name" json:"deployment_name,omitempty"`         // azure-openai
+	APIVersion        string `yaml:"api_version" json:"api_version,omitempty"`                 // azure-openai
+	Region            string `yaml:"region" json:"region,omitempty"`                           // bedrock
+	TimeoutSeconds    int    `yaml:"timeout_seconds" json:"timeout_seconds,omitempty"`         // 0 uses the default
+	MaxRetries        int    `yaml:"max_retries" json:"max_retries,omitempty"`                 // 0 uses the default
+	RequestsPerMinute int    `yaml:"requests_per_minute" json:"requests_per_minute,omitempty"` // 0 is unlimited
+	MockResponse      string `yaml:"mock_response" json:"mock_response,omitempty"`             // mock: canned reply
+	MockLatencyMs     int    `yaml:"mock_latency_ms" json:"mock_latency_ms,omitempty"`         // mock: simulated delay
+	Enabled           bool   `yaml:"enabled" json:"enabled"`
 }
 
 // Config represents the main configuration for the loom system.
@@ -44,6 +52,7 @@ type Config struct {
 	Temporal  TemporalConfig  `yaml:"temporal" json:"temporal,omitempty"`
 	HotReload HotReloadConfig `yaml:"hot_reload" json:"hot_reload,omitempty"`
 	OpenClaw  OpenClawConfig  `yaml:"openclaw" json:"openclaw,omitempty"`
+	Logging   LoggingConfig   `yaml:"logging" json:"logging,omitempty"`
 
 	// JSON/User-specific configuration fields
 	Providers   []Provider     `yaml:"providers,omitempty" json:"providers"`
@@ -62,6 +71,20 @@ type ServerConfig struct {
 	ReadTimeout  time.Duration `yaml:"read_timeout"`
 	WriteTimeout time.Duration `yaml:"write_timeout"`
 	IdleTimeout  time.Duration `yaml:"idle_timeout"`
+
+	MaxRequestBodyBytes int64 `yaml:"max_request_body_bytes"` // API request body cap (0 = 4MB default, negative = no limit)
+
+	CORS CORSConfig `yaml:"cors"`
+}
+
+// CORSConfig controls cross-origin access to the API. With no allowed
+// origins only same-origin requests are served.
+type CORSConfig struct {
+	AllowedOrigins   []string `yaml:"allowed_origins"` // "*" allows any origin
+	AllowedMethods   []string `yaml:"allowed_methods"` // Default: GET, POST, PUT, PATCH, DELETE, OPTIONS
+	AllowedHeaders   []string `yaml:"allowed_headers"` // Default: Content-Type, X-API-Key, Authorization
+	AllowCredentials bool     `yaml:"allow_credentials"`
+	MaxAge           int      `yaml:"max_age"` // Seconds browsers may cache a preflight
 }
 
 // DatabaseConfig configures the local storage
@@ -89,7 +112,7 @@ type BeadsFederationConfig struct {
 	AutoSync     bool             `yaml:"auto_sync"`     // Sync with peers on startup
 	SyncInterval time.Duration    `yaml:"sync_interval"` // Periodic sync interval (0 = disabled)
 	SyncStrategy string           `yaml:"sync_strategy"` // "ours", "theirs", or "" (manual)
-	SyncMode     string           `yaml:"sync_mode"`     // "git-native" (replaces "dolt-native")
+	SyncMode     string           `yaml:"sync_mode"`     // "git-native" (replaces "dolt-native") or "http"
 	Peers        []FederationPeer `yaml:"peers"`
 }
 
@@ -99,6 +122,7 @@ type FederationPeer struct {
 	RemoteURL   string `yaml:"remote_url"`
 	Enabled     bool   `yaml:"enabled"`
 	Description string `yaml:"description,omitempty"`
+	Token       string `yaml:"token,omitempty"` // Bearer token for the peer's API (http sync mode)
 }
 
 // AgentsConfig configures agent behavior
@@ -109,16 +133,41 @@ type AgentsConfig struct {
 	FileLockTimeout    time.Duration `yaml:"file_lock_timeout"`
 	CorpProfile        string        `yaml:"corp_profile" json:"corp_profile,omitempty"`
 	AllowedRoles       []string      `yaml:"allowed_roles" json:"allowed_roles,omitempty"`
+
+	// Worker pool auto-scaling; disabled when AutoScaleMax is 0
+	AutoScaleMin      int           `yaml:"autoscale_min" json:"autoscale_min,omitempty"`
+	AutoScaleMax      int           `yaml:"autoscale_max" json:"autoscale_max,omitempty"`
+	AutoScaleInterval time.Duration `yaml:"autoscale_interval" json:"autoscale_interval,omitempty"`
+
+	// Agents whose project agent sends no heartbeat for this long are marked
+	// unreachable; 0 means three heartbeat intervals
+	HeartbeatTimeout time.Duration `yaml:"heartbeat_timeout" json:"heartbeat_timeout,omitempty"`
 }
 
 // ReadinessConfig controls readiness gating behavior
 type ReadinessConfig struct {
 	Mode string `yaml:"mode" json:"mode,omitempty"`
+	// CleanTree also holds dispatch while a project's work dir has
+	// uncommitted changes.
+	CleanTree bool `yaml:"clean_tree" json:"clean_tree,omitempty"`
 }
 
 // DispatchConfig controls dispatcher guardrails
 type DispatchConfig struct {
-	MaxHops int `yaml:"max_hops" json:"max_hops,omitempty"`
+	MaxHops               int `yaml:"max_hops" json:"max_hops,omitempty"`
+	LoopWindow            int `yaml:"loop_window" json:"loop_window,omitempty"`                           // Alternating two-agent failures that count as a loop
+	MaxDispatchCount      int `yaml:"max_dispatch_count" json:"max_dispatch_count,omitempty"`             // Failed dispatches before a loop is declared (0 = no limit)
+	SameAgentFailureLimit int `yaml:"same_agent_failure_limit" json:"same_agent_failure_limit,omitempty"` // Consecutive failures by one agent that count as a loop
+
+	ProviderFailureThreshold int           `yaml:"provider_failure_threshold" json:"provider_failure_threshold,omitempty"` // Consecutive provider failures that open its circuit
+	ProviderCooldown         time.Duration `yaml:"provider_cooldown" json:"provider_cooldown,omitempty"`                   // How long an open circuit keeps a provider out of rotation
+	ModelCacheTTL            time.Duration `yaml:"model_cache_ttl" json:"model_cache_ttl,omitempty"`                       // How long a provider's model list is cached
+	PriorityAgingInterval    time.Duration `yaml:"priority_aging_interval" json:"priority_aging_interval,omitempty"`       // Ready time that raises a bead one priority level (0 = no aging)
+	ExecRetries              int           `yaml:"exec_retries" json:"exec_retries,omitempty"`                             // In-tick retries of a task that failed transiently (0 = none)
+	ExecRetryBackoff         time.Duration `yaml:"exec_retry_backoff" json:"exec_retry_backoff,omitempty"`                 // Wait before the first retry; doubles per retry
+	MaxConcurrentPerProject  int           `yaml:"max_concurrent_per_project" json:"max_concurrent_per_project,omitempty"` // In-progress beads allowed per project (0 = unlimited)
+
+	PersonaStrategies []string `yaml:"persona_strategies" json:"persona_strategies,omitempty"` // Fallback persona matchers tried after exact matching ("fuzzy", "capability")
 }
 
 // GitConfig controls git-related settings
@@ -147,10 +196,25 @@ type SecurityConfig struct {
 	PKIEnabled     bool     `yaml:"pki_enabled"`
 	CAFile         string   `yaml:"ca_file"`
 	RequireHTTPS   bool     `yaml:"require_https"`
-	AllowedOrigins []string `yaml:"allowed_origins"` // CORS
+	AllowedOrigins []string `yaml:"allowed_origins"` // Deprecated: use server.cors.allowed_origins
 	APIKeys        []string `yaml:"api_keys,omitempty"`
 	JWTSecret      string   `yaml:"jwt_secret" json:"jwt_secret,omitempty"`
 	WebhookSecret  string   `yaml:"webhook_secret" json:"webhook_secret,omitempty"` // GitHub webhook secret
+	// GitLabWebhookSecret is compared against the X-Gitlab-Token header
+	GitLabWebhookSecret string `yaml:"gitlab_webhook_secret" json:"gitlab_webhook_secret,omitempty"`
+	// Tokens are pre-shared bearer tokens, each bound to a subject and scopes
+	Tokens []TokenConfig `yaml:"tokens,omitempty" json:"-"`
+	// RateLimitRPS caps requests per second per client (auth subject, else
+	// remote IP); 0 disables the limiter. RateLimitBurst defaults to the RPS.
+	RateLimitRPS   float64 `yaml:"rate_limit_rps" json:"rate_limit_rps,omitempty"`
+	RateLimitBurst int     `yaml:"rate_limit_burst" json:"rate_limit_burst,omitempty"`
+}
+
+// TokenConfig is a static bearer token and the scopes it grants
+type TokenConfig struct {
+	Subject string   `yaml:"subject"`
+	Token   string   `yaml:"token"`
+	Scopes  []string `yaml:"scopes"`
 }
 
 // TemporalConfig configures Temporal workflow engine
@@ -191,6 +255,14 @@ type ProjectConfig struct {
 	IsPerpetual     bool              `yaml:"is_perpetual" json:"is_perpetual,omitempty"`
 	IsSticky        bool              `yaml:"is_sticky" json:"is_sticky,omitempty"`
 	Context         map[string]string `yaml:"context"`
+	// StatusWebhookURL receives a signed POST on every bead status change
+	StatusWebhookURL string `yaml:"status_webhook_url" json:"status_webhook_url,omitempty"`
+	// StatusWebhookSecret signs those POSTs (defaults to security.webhook_secret)
+	StatusWebhookSecret string `yaml:"status_webhook_secret" json:"status_webhook_secret,omitempty"`
+	// MaxTaskTokens caps the tokens each dispatched task may use (0 = no limit)
+	MaxTaskTokens int `yaml:"max_task_tokens" json:"max_task_tokens,omitempty"`
+	// MaxTaskCostUSD caps the provider cost of each dispatched task (0 = no limit)
+	MaxTaskCostUSD float64 `yaml:"max_task_cost_usd" json:"max_task_cost_usd,omitempty"`
 }
 
 // WebUIConfig configures the web interface
@@ -207,6 +279,11 @@ type HotReloadConfig struct {
 	Patterns  []string `yaml:"patterns"`   // File patterns to watch (e.g. "*.js", "*.css")
 }
 
+// LoggingConfig configures structured logging
+type LoggingConfig struct {
+	Level string `yaml:"level" json:"level,omitempty"` // debug, info, warn or error (default: info)
+}
+
 // OpenClawConfig configures the OpenClaw messaging gateway integration.
 // OpenClaw acts as a bidirectional bridge between loom and human messaging
 // platforms (WhatsApp, Signal, Slack, Telegram, etc.) for P0 decision escalations.
@@ -315,11 +392,10 @@ func DefaultConfig() *Config {
 			ProjectKeyDir: "/app/data/projects",
 		},
 		Security: SecurityConfig{
-			EnableAuth:     true,
-			PKIEnabled:     false,
-			RequireHTTPS:   false,
-			AllowedOrigins: []string{"*"},
-			JWTSecret:      "",
+			EnableAuth:   true,
+			PKIEnabled:   false,
+			RequireHTTPS: false,
+			JWTSecret:    "",
 		},
 		Temporal: TemporalConfig{
 			Host:                     "localhost:7233",
@@ -0,0 +1,196 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadDebounce lets an editor finish writing before the file is read.
+const reloadDebounce = 200 * time.Millisecond
+
+var reloadHooks struct {
+	mu  sync.Mutex
+	fns []func(*Config)
+}
+
+// OnReload registers fn to receive the config after each reload that changed
+// a live setting. Hooks run in registration order on the watcher goroutine.
+func OnReload(fn func(*Config)) {
+	reloadHooks.mu.Lock()
+	defer reloadHooks.mu.Unlock()
+	reloadHooks.fns = append(reloadHooks.fns, fn)
+}
+
+func runReloadHooks(cfg *Config) {
+	reloadHooks.mu.Lock()
+	fns := append([]func(*Config){}, reloadHooks.fns...)
+	reloadHooks.mu.Unlock()
+	for _, fn := range fns {
+		fn(cfg)
+	}
+}
+
+// ApplyEnvOverrides applies settings that environment variables override:
+// TEMPORAL_HOST, TEMPORAL_NAMESPACE and LOOM_LOG_LEVEL.
+func ApplyEnvOverrides(cfg *Config) {
+	if temporalHost := os.Getenv("TEMPORAL_HOST"); temporalHost != "" {
+		cfg.Temporal.Host = temporalHost
+		log.Printf("Using Temporal host from environment: %s", temporalHost)
+	}
+	if temporalNamespace := os.Getenv("TEMPORAL_NAMESPACE"); temporalNamespace != "" {
+		cfg.Temporal.Namespace = temporalNamespace
+		log.Printf("Using Temporal namespace from environment: %s", temporalNamespace)
+	}
+	if logLevel := os.Getenv("LOOM_LOG_LEVEL"); logLevel != "" {
+		cfg.Logging.Level = logLevel
+	}
+}
+
+// Validate checks the settings that can be reloaded live.
+func (c *Config) Validate() error {
+	switch strings.ToLower(strings.TrimSpace(c.Logging.Level)) {
+	case "", "debug", "info", "warn", "warning", "error":
+	default:
+		return fmt.Errorf("unknown logging.level %q", c.Logging.Level)
+	}
+	switch c.Readiness.Mode {
+	case "", "block", "warn":
+	default:
+		return fmt.Errorf("unknown readiness.mode %q", c.Readiness.Mode)
+	}
+	if c.Security.RateLimitRPS < 0 || c.Security.RateLimitBurst < 0 {
+		return fmt.Errorf("security rate limits must not be negative")
+	}
+	return nil
+}
+
+// Watcher reloads a config file when it changes. Only logging, readiness
+// and the security rate limits change live; changes to anything else, such
+// as the database path, are logged and ignored until a restart.
+type Watcher struct {
+	path    string
+	fs      *fsnotify.Watcher
+	mu      sync.Mutex
+	current *Config
+}
+
+// WatchFile starts watching path, whose contents are currently applied as
+// current. The file's directory is watched so editors that replace the file
+// are noticed.
+func WatchFile(path string, current *Config) (*Watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config watcher: %w", err)
+	}
+	path = filepath.Clean(path)
+	if err := fsw.Add(filepath.Dir(path)); err != nil {
+		_ = fsw.Close()
+		return nil, fmt.Errorf("failed to watch %s: %w", path, err)
+	}
+
+	w := &Watcher{path: path, fs: fsw, current: current}
+	go w.loop()
+	return w, nil
+}
+
+// Close stops watching.
+func (w *Watcher) Close() error {
+	return w.fs.Close()
+}
+
+// Current returns the config as last applied.
+func (w *Watcher) Current() *Config {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.current
+}
+
+func (w *Watcher) loop() {
+	var debounce <-chan time.Time
+	for {
+		select {
+		case event, ok := <-w.fs.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(event.Name) == w.path && event.Op&(fsnotify.Write|fsnotify.Create) != 0 {
+				debounce = time.After(reloadDebounce)
+			}
+		case err, ok := <-w.fs.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("[Config] Watcher error: %v", err)
+		case <-debounce:
+			debounce = nil
+			if err := w.Reload(); err != nil {
+				log.Printf("[Config] Keeping current config, reload of %s failed: %v", w.path, err)
+			}
+		}
+	}
+}
+
+// Reload reads and validates the file and applies its live settings. An
+// invalid file changes nothing.
+func (w *Watcher) Reload() error {
+	loaded, err := LoadConfigFromFile(w.path)
+	if err != nil {
+		return err
+	}
+	ApplyEnvOverrides(loaded)
+	if err := loaded.Validate(); err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	old := w.current
+	next := *old
+	next.Logging = loaded.Logging
+	next.Readiness = loaded.Readiness
+	next.Security.RateLimitRPS = loaded.Security.RateLimitRPS
+	next.Security.RateLimitBurst = loaded.Security.RateLimitBurst
+	for _, section := range changedSections(&next, loaded) {
+		log.Printf("[Config] WARNING: ignoring change to %s in %s; restart to apply it", section, w.path)
+	}
+	changed := !reflect.DeepEqual(old.Logging, next.Logging) ||
+		!reflect.DeepEqual(old.Readiness, next.Readiness) ||
+		old.Security.RateLimitRPS != next.Security.RateLimitRPS ||
+		old.Security.RateLimitBurst != next.Security.RateLimitBurst
+	w.current = &next
+	w.mu.Unlock()
+
+	if changed {
+		log.Printf("[Config] Reloaded %s", w.path)
+		runReloadHooks(&next)
+	}
+	return nil
+}
+
+// changedSections names the top-level sections that differ between a and b,
+// by their YAML keys.
+func changedSections(a, b *Config) []string {
+	va, vb := reflect.ValueOf(*a), reflect.ValueOf(*b)
+	var sections []string
+	for i := 0; i < va.NumField(); i++ {
+		field := va.Type().Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if !reflect.DeepEqual(va.Field(i).Interface(), vb.Field(i).Interface()) {
+			sections = append(sections, name)
+		}
+	}
+	return sections
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeConfig(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatcher_Reload(t *testing.T) {
+	t.Setenv("LOOM_LOG_LEVEL", "")
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig(t, path, "logging:\n  level: info\ndatabase:\n  path: /data/loom.db\n")
+	current, err := LoadConfigFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := &Watcher{path: path, current: current}
+
+	var applied []*Config
+	OnReload(func(cfg *Config) { applied = append(applied, cfg) })
+	t.Cleanup(func() { reloadHooks.fns = nil })
+
+	writeConfig(t, path, "logging:\n  level: debug\ndatabase:\n  path: /tmp/other.db\nsecurity:\n  rate_limit_rps: 5\n")
+	if err := w.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	got := w.Current()
+	if got.Logging.Level != "debug" || got.Security.RateLimitRPS != 5 {
+		t.Errorf("live settings not applied: %+v %+v", got.Logging, got.Security)
+	}
+	if got.Database.Path != "/data/loom.db" {
+		t.Errorf("Database.Path = %q, want the unsafe change ignored", got.Database.Path)
+	}
+	if len(applied) != 1 || applied[0] != got {
+		t.Fatalf("hooks ran %d times, want once with the new config", len(applied))
+	}
+
+	// Only an unsafe change: nothing to apply, so the hooks do not run.
+	writeConfig(t, path, "logging:\n  level: debug\ndatabase:\n  path: /tmp/third.db\nsecurity:\n  rate_limit_rps: 5\n")
+	if err := w.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if len(applied) != 1 {
+		t.Errorf("hooks ran %d times, want 1", len(applied))
+	}
+}
+
+func TestWatcher_ReloadRejectsInvalid(t *testing.T) {
+	t.Setenv("LOOM_LOG_LEVEL", "")
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	current := &Config{Logging: LoggingConfig{Level: "info"}}
+	w := &Watcher{path: path, current: current}
+
+	for _, data := range []string{
+		"logging: [unclosed\n",
+		"logging:\n  level: loud\n",
+		"readiness:\n  mode: sometimes\n",
+		"security:\n  rate_limit_burst: -1\n",
+	} {
+		writeConfig(t, path, data)
+		if err := w.Reload(); err == nil {
+			t.Errorf("Reload(%q) succeeded, want an error", data)
+		}
+		if w.Current() != current {
+			t.Errorf("Reload(%q) replaced the config", data)
+		}
+	}
+}
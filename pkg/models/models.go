@@ -16,6 +16,7 @@ type Persona struct {
 	License       string                 `json:"license,omitempty" yaml:"license,omitempty"`             // License name or reference
 	Compatibility string                 `json:"compatibility,omitempty" yaml:"compatibility,omitempty"` // Environment requirements
 	Metadata      map[string]interface{} `json:"metadata,omitempty" yaml:"metadata,omitempty"`           // Flexible metadata
+	OutputFormat  string                 `json:"output_format,omitempty" yaml:"output_format,omitempty"` // Template replacing the default task instructions
 
 	// Deprecated fields (kept for backward compatibility during transition)
 	// TODO: Remove these after full migration
@@ -60,12 +61,17 @@ type Agent struct {
 	PersonaName string    `json:"persona_name"`
 	Persona     *Persona  `json:"persona,omitempty"`
 	ProviderID  string    `json:"provider_id,omitempty"`
-	Status      string    `json:"status"` // "paused", "idle", "working", "deciding", "blocked"
+	Status      string    `json:"status"` // "paused", "idle", "working", "deciding", "blocked", "unreachable"
 	CurrentBead string    `json:"current_bead,omitempty"`
 	ProjectID   string    `json:"project_id"`
 	PositionID  string    `json:"position_id,omitempty"` // Link to org chart position
 	StartedAt   time.Time `json:"started_at"`
 	LastActive  time.Time `json:"last_active"`
+
+	MaxConcurrent int `json:"max_concurrent,omitempty"` // Tasks the agent may run at once; 0 means 1
+
+	// Last heartbeat from the project's agent container; nil if none was received
+	LastHeartbeat *time.Time `json:"last_heartbeat,omitempty"`
 }
 
 // VersionedEntity interface implementation for Agent
@@ -192,6 +198,7 @@ const (
 	BeadStatusInProgress BeadStatus = "in_progress"
 	BeadStatusBlocked    BeadStatus = "blocked"
 	BeadStatusClosed     BeadStatus = "closed"
+	BeadStatusDeadLetter BeadStatus = "dead-letter" // Failed dispatch too often; waits on a human decision
 )
 
 // BeadPriority represents the priority of a bead
@@ -224,6 +231,9 @@ type Bead struct {
 	Tags        []string          `json:"tags,omitempty"`
 	Context     map[string]string `json:"context,omitempty"`
 
+	// RequiredCapability restricts dispatch to agents whose persona lists it
+	RequiredCapability string `json:"required_capability,omitempty"`
+
 	// Deadline tracking (motivation system)
 	DueDate       *time.Time `json:"due_date,omitempty"`       // When this bead should be completed
 	MilestoneID   string     `json:"milestone_id,omitempty"`   // Associated milestone
@@ -252,6 +262,11 @@ type DecisionBead struct {
 	Decision       string     `json:"decision,omitempty"`
 	Rationale      string     `json:"rationale,omitempty"`
 	DecidedAt      *time.Time `json:"decided_at,omitempty"`
+
+	// Optional timeout: DefaultDecision is applied once AutoResolveAt passes
+	DefaultDecision string     `json:"default_decision,omitempty"`
+	AutoResolveAt   *time.Time `json:"auto_resolve_at,omitempty"`
+	AutoResolved    bool       `json:"auto_resolved,omitempty"`
 }
 
 // FileLock represents a lock on a file to prevent merge conflicts
@@ -271,6 +286,19 @@ type WorkGraph struct {
 	UpdatedAt time.Time        `json:"updated_at"`
 }
 
+// WorkGraphDelta holds the work graph changes since a point in time: beads
+// updated after it, edges touching them, and beads removed since. When
+// Full is set the deletion history does not reach back that far, and Beads
+// holds the whole graph to replace the client's copy.
+type WorkGraphDelta struct {
+	Beads          map[string]*Bead `json:"beads"`
+	Edges          []Edge           `json:"edges"`
+	DeletedBeadIDs []string         `json:"deleted_bead_ids"`
+	Since          time.Time        `json:"since"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+	Full           bool             `json:"full"`
+}
+
 // Edge represents a directed edge in the work graph
 type Edge struct {
 	From         string `json:"from"`
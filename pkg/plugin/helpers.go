@@ -2,7 +2,9 @@ package plugin
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
 	"time"
 )
 
@@ -151,6 +153,45 @@ func ValidateConfig(config map[string]interface{}, schema []ConfigField) error {
 	return nil
 }
 
+// ConfigError describes one invalid configuration field.
+type ConfigError struct {
+	// Path is the field's path within the config, e.g. "api_key"
+	Path string `json:"path"`
+
+	// Message explains what is wrong with the field
+	Message string `json:"message"`
+}
+
+// Error implements the error interface.
+func (e ConfigError) Error() string {
+	return e.Path + ": " + e.Message
+}
+
+// CheckConfig validates config against the schema without modifying it and
+// returns every problem found rather than stopping at the first.
+func CheckConfig(config map[string]interface{}, schema []ConfigField) []ConfigError {
+	var errs []ConfigError
+	for _, field := range schema {
+		value, exists := config[field.Name]
+		if !exists || value == nil {
+			if field.Required {
+				errs = append(errs, ConfigError{Path: field.Name, Message: "required field is missing"})
+			}
+			continue
+		}
+		if err := validateType(value, field.Type); err != nil {
+			errs = append(errs, ConfigError{Path: field.Name, Message: err.Error()})
+			continue
+		}
+		if field.Validation != nil {
+			if err := validateRules(value, field); err != nil {
+				errs = append(errs, ConfigError{Path: field.Name, Message: err.Error()})
+			}
+		}
+	}
+	return errs
+}
+
 func validateType(value interface{}, expectedType string) error {
 	switch expectedType {
 	case "string":
@@ -158,9 +199,13 @@ func validateType(value interface{}, expectedType string) error {
 			return fmt.Errorf("expected string, got %T", value)
 		}
 	case "int":
-		switch value.(type) {
-		case int, int64, float64:
-			// Accept numeric types
+		switch v := value.(type) {
+		case int, int64:
+		case float64:
+			// JSON decodes every number as float64
+			if v != math.Trunc(v) {
+				return fmt.Errorf("expected int, got %v", v)
+			}
 		default:
 			return fmt.Errorf("expected int, got %T", value)
 		}
@@ -281,8 +326,10 @@ func CalculateCost(usage *UsageInfo, costPerMToken float64) float64 {
 }
 
 // IsTransientError determines if an error is transient (retry-able).
+// Wrapped plugin errors are unwrapped with errors.As.
 func IsTransientError(err error) bool {
-	if pluginErr, ok := err.(*PluginError); ok {
+	var pluginErr *PluginError
+	if errors.As(err, &pluginErr) {
 		return pluginErr.Transient
 	}
 	return false
@@ -290,7 +337,8 @@ func IsTransientError(err error) bool {
 
 // GetErrorCode extracts the error code from a plugin error.
 func GetErrorCode(err error) string {
-	if pluginErr, ok := err.(*PluginError); ok {
+	var pluginErr *PluginError
+	if errors.As(err, &pluginErr) {
 		return pluginErr.Code
 	}
 	return ""
@@ -53,100 +53,100 @@ type StreamingPlugin interface {
 // Metadata describes a plugin for registration and discovery.
 type Metadata struct {
 	// Name is the human-readable plugin name (e.g., "OpenAI Plugin")
-	Name string `json:"name"`
+	Name string `json:"name" yaml:"name"`
 
 	// Version is the plugin version (semantic versioning recommended)
-	Version string `json:"version"`
+	Version string `json:"version" yaml:"version"`
 
 	// PluginAPIVersion is the version of the plugin API this plugin implements
-	PluginAPIVersion string `json:"plugin_api_version"`
+	PluginAPIVersion string `json:"plugin_api_version" yaml:"plugin_api_version"`
 
 	// ProviderType is the provider type identifier (e.g., "openai", "anthropic", "custom-llm")
-	ProviderType string `json:"provider_type"`
+	ProviderType string `json:"provider_type" yaml:"provider_type"`
 
 	// Description provides a brief description of the plugin
-	Description string `json:"description"`
+	Description string `json:"description" yaml:"description"`
 
 	// Author is the plugin author or organization
-	Author string `json:"author"`
+	Author string `json:"author" yaml:"author"`
 
 	// Homepage is the URL to the plugin's homepage or documentation
-	Homepage string `json:"homepage,omitempty"`
+	Homepage string `json:"homepage,omitempty" yaml:"homepage,omitempty"`
 
 	// License is the plugin's license (e.g., "MIT", "Apache-2.0")
-	License string `json:"license,omitempty"`
+	License string `json:"license,omitempty" yaml:"license,omitempty"`
 
 	// Capabilities describes what the plugin supports
-	Capabilities Capabilities `json:"capabilities"`
+	Capabilities Capabilities `json:"capabilities" yaml:"capabilities"`
 
 	// ConfigSchema describes the configuration fields this plugin accepts
-	ConfigSchema []ConfigField `json:"config_schema,omitempty"`
+	ConfigSchema []ConfigField `json:"config_schema,omitempty" yaml:"config_schema,omitempty"`
 }
 
 // Capabilities describes plugin capabilities.
 type Capabilities struct {
 	// Streaming indicates if the plugin supports streaming responses
-	Streaming bool `json:"streaming"`
+	Streaming bool `json:"streaming" yaml:"streaming"`
 
 	// FunctionCalling indicates if the plugin supports function/tool calling
-	FunctionCalling bool `json:"function_calling"`
+	FunctionCalling bool `json:"function_calling" yaml:"function_calling"`
 
 	// Vision indicates if the plugin supports multimodal/vision inputs
-	Vision bool `json:"vision"`
+	Vision bool `json:"vision" yaml:"vision"`
 
 	// Embeddings indicates if the plugin supports generating embeddings
-	Embeddings bool `json:"embeddings"`
+	Embeddings bool `json:"embeddings" yaml:"embeddings"`
 
 	// FineTuning indicates if the plugin supports fine-tuning
-	FineTuning bool `json:"fine_tuning"`
+	FineTuning bool `json:"fine_tuning" yaml:"fine_tuning"`
 
 	// CustomCapabilities allows plugins to declare custom capabilities
-	CustomCapabilities map[string]bool `json:"custom_capabilities,omitempty"`
+	CustomCapabilities map[string]bool `json:"custom_capabilities,omitempty" yaml:"custom_capabilities,omitempty"`
 }
 
 // ConfigField describes a configuration field for the plugin.
 type ConfigField struct {
 	// Name is the field name (e.g., "api_key", "endpoint")
-	Name string `json:"name"`
+	Name string `json:"name" yaml:"name"`
 
 	// Type is the field type ("string", "int", "bool", "float")
-	Type string `json:"type"`
+	Type string `json:"type" yaml:"type"`
 
 	// Required indicates if this field is required
-	Required bool `json:"required"`
+	Required bool `json:"required" yaml:"required"`
 
 	// Description explains what this field is for
-	Description string `json:"description"`
+	Description string `json:"description" yaml:"description"`
 
 	// Default is the default value if not provided (optional)
-	Default interface{} `json:"default,omitempty"`
+	Default interface{} `json:"default,omitempty" yaml:"default,omitempty"`
 
 	// Sensitive indicates if this field contains sensitive data (e.g., API keys)
-	Sensitive bool `json:"sensitive"`
+	Sensitive bool `json:"sensitive" yaml:"sensitive"`
 
 	// Validation contains validation rules (optional)
-	Validation *ValidationRule `json:"validation,omitempty"`
+	Validation *ValidationRule `json:"validation,omitempty" yaml:"validation,omitempty"`
 }
 
 // ValidationRule defines validation constraints for a config field.
 type ValidationRule struct {
 	// MinLength for string fields
-	MinLength int `json:"min_length,omitempty"`
+	MinLength int `json:"min_length,omitempty" yaml:"min_length,omitempty"`
 
 	// MaxLength for string fields
-	MaxLength int `json:"max_length,omitempty"`
+	MaxLength int `json:"max_length,omitempty" yaml:"max_length,omitempty"`
 
 	// Pattern is a regex pattern for string validation
-	Pattern string `json:"pattern,omitempty"`
+	Pattern string `json:"pattern,omitempty" yaml:"pattern,omitempty"`
 
 	// Min for numeric fields
-	Min *float64 `json:"min,omitempty"`
+	Min *float64 `json:"min,omitempty" yaml:"min,omitempty"`
 
 	// Max for numeric fields
-	Max *float64 `json:"max,omitempty"`
+	Max *float64 `json:"max,omitempty" yaml:"max,omitempty"`
 
 	// Enum lists allowed values
-	Enum []interface{} `json:"enum,omitempty"`
+	Enum []interface{} `json:"enum,omitempty" yaml:"enum,omitempty"`
 }
 
 // HealthStatus represents the health status of a plugin/provider.
@@ -299,6 +299,12 @@ type ModelInfo struct {
 	// CostPerMToken is the cost per million tokens in USD
 	CostPerMToken *float64 `json:"cost_per_mtoken,omitempty"`
 
+	// PromptCostPerToken is the cost of one prompt token in USD
+	PromptCostPerToken *float64 `json:"prompt_cost_per_token,omitempty"`
+
+	// CompletionCostPerToken is the cost of one completion token in USD
+	CompletionCostPerToken *float64 `json:"completion_cost_per_token,omitempty"`
+
 	// Capabilities describes model-specific capabilities
 	Capabilities Capabilities `json:"capabilities"`
 
@@ -101,6 +101,34 @@ func TestBasePlugin(t *testing.T) {
 	}
 }
 
+func TestCheckConfig(t *testing.T) {
+	schema := []ConfigField{
+		{Name: "api_key", Type: "string", Required: true},
+		{Name: "timeout", Type: "int", Validation: &ValidationRule{Min: floatPtr(1)}},
+		{Name: "enabled", Type: "bool"},
+	}
+
+	if errs := CheckConfig(map[string]interface{}{"api_key": "k", "timeout": float64(30)}, schema); len(errs) != 0 {
+		t.Errorf("expected valid config, got %v", errs)
+	}
+
+	config := map[string]interface{}{"timeout": 0, "enabled": "yes"}
+	errs := CheckConfig(config, schema)
+	if len(errs) != 3 {
+		t.Fatalf("expected every bad field to be reported, got %v", errs)
+	}
+	if errs[0].Path != "api_key" || errs[1].Path != "timeout" || errs[2].Path != "enabled" {
+		t.Errorf("unexpected paths %v", errs)
+	}
+	if len(config) != 2 {
+		t.Error("CheckConfig should not modify the config")
+	}
+
+	if errs := CheckConfig(map[string]interface{}{"api_key": "k", "timeout": 2.5}, schema); len(errs) != 1 {
+		t.Errorf("fractional value should not pass as int, got %v", errs)
+	}
+}
+
 // TestValidateConfig tests configuration validation
 func TestValidateConfig(t *testing.T) {
 	schema := []ConfigField{
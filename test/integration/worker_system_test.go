@@ -31,12 +31,11 @@ func TestWorkerSystemIntegration(t *testing.T) {
 
 	// Register a mock provider (in real usage, this would be OpenAI, etc.)
 	mockProviderConfig := &provider.ProviderConfig{
-		ID:       "mock-provider",
-		Name:     "Mock Provider",
-		Type:     "custom",
-		Endpoint: "http://localhost:8888/v1", // Mock endpoint
-		APIKey:   "mock-key",
-		Model:    "mock-model",
+		ID:           "mock-provider",
+		Name:         "Mock Provider",
+		Type:         "mock",
+		Model:        "mock-model",
+		MockResponse: "task complete",
 	}
 
 	err := registry.Register(mockProviderConfig)
@@ -58,7 +57,6 @@ func TestWorkerSystemIntegration(t *testing.T) {
 	}
 
 	// Step 4: Spawn an agent with worker
-	// Note: This will fail to connect to the mock provider, but tests the workflow
 	spawnedAgent, err := workerManager.SpawnAgentWorker(
 		ctx,
 		"test-agent-1",
@@ -113,7 +111,23 @@ func TestWorkerSystemIntegration(t *testing.T) {
 		t.Errorf("Expected 1 agent, got %d", len(agents))
 	}
 
-	// Step 8: Clean up - stop the agent
+	// Step 8: Execute a task against the mock provider
+	result, err := workerManager.ExecuteTask(ctx, spawnedAgent.ID, &worker.Task{
+		ID:          "task-1",
+		Description: "Verify the worker system end to end",
+		ProjectID:   "test-project",
+	})
+	if err != nil {
+		t.Fatalf("Failed to execute task: %v", err)
+	}
+	if result.Response != "task complete" {
+		t.Errorf("Expected the mock provider's canned response, got '%s'", result.Response)
+	}
+	if result.TokensUsed == 0 {
+		t.Error("Expected the mock provider to report token usage")
+	}
+
+	// Step 9: Clean up - stop the agent
 	err = workerManager.StopAgent(spawnedAgent.ID)
 	if err != nil {
 		t.Errorf("Failed to stop agent: %v", err)